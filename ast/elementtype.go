@@ -43,6 +43,7 @@ const (
 	ElementTypePragmaDeclaration
 	ElementTypeImportDeclaration
 	ElementTypeTransactionDeclaration
+	ElementTypeInvalidDeclaration
 
 	// Statements
 
@@ -59,6 +60,7 @@ const (
 	ElementTypeSwapStatement
 	ElementTypeExpressionStatement
 	ElementTypeRemoveStatement
+	ElementTypeInvalidStatement
 
 	// Expressions
 
@@ -86,4 +88,5 @@ const (
 	ElementTypePathExpression
 	ElementTypeAttachExpression
 	ElementTypeStringTemplateExpression
+	ElementTypeInvalidExpression
 )
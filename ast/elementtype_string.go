@@ -24,48 +24,51 @@ func _() {
 	_ = x[ElementTypePragmaDeclaration-13]
 	_ = x[ElementTypeImportDeclaration-14]
 	_ = x[ElementTypeTransactionDeclaration-15]
-	_ = x[ElementTypeReturnStatement-16]
-	_ = x[ElementTypeBreakStatement-17]
-	_ = x[ElementTypeContinueStatement-18]
-	_ = x[ElementTypeIfStatement-19]
-	_ = x[ElementTypeSwitchStatement-20]
-	_ = x[ElementTypeWhileStatement-21]
-	_ = x[ElementTypeForStatement-22]
-	_ = x[ElementTypeEmitStatement-23]
-	_ = x[ElementTypeVariableDeclaration-24]
-	_ = x[ElementTypeAssignmentStatement-25]
-	_ = x[ElementTypeSwapStatement-26]
-	_ = x[ElementTypeExpressionStatement-27]
-	_ = x[ElementTypeRemoveStatement-28]
-	_ = x[ElementTypeVoidExpression-29]
-	_ = x[ElementTypeBoolExpression-30]
-	_ = x[ElementTypeNilExpression-31]
-	_ = x[ElementTypeIntegerExpression-32]
-	_ = x[ElementTypeFixedPointExpression-33]
-	_ = x[ElementTypeArrayExpression-34]
-	_ = x[ElementTypeDictionaryExpression-35]
-	_ = x[ElementTypeIdentifierExpression-36]
-	_ = x[ElementTypeInvocationExpression-37]
-	_ = x[ElementTypeMemberExpression-38]
-	_ = x[ElementTypeIndexExpression-39]
-	_ = x[ElementTypeConditionalExpression-40]
-	_ = x[ElementTypeUnaryExpression-41]
-	_ = x[ElementTypeBinaryExpression-42]
-	_ = x[ElementTypeFunctionExpression-43]
-	_ = x[ElementTypeStringExpression-44]
-	_ = x[ElementTypeCastingExpression-45]
-	_ = x[ElementTypeCreateExpression-46]
-	_ = x[ElementTypeDestroyExpression-47]
-	_ = x[ElementTypeReferenceExpression-48]
-	_ = x[ElementTypeForceExpression-49]
-	_ = x[ElementTypePathExpression-50]
-	_ = x[ElementTypeAttachExpression-51]
-	_ = x[ElementTypeStringTemplateExpression-52]
+	_ = x[ElementTypeInvalidDeclaration-16]
+	_ = x[ElementTypeReturnStatement-17]
+	_ = x[ElementTypeBreakStatement-18]
+	_ = x[ElementTypeContinueStatement-19]
+	_ = x[ElementTypeIfStatement-20]
+	_ = x[ElementTypeSwitchStatement-21]
+	_ = x[ElementTypeWhileStatement-22]
+	_ = x[ElementTypeForStatement-23]
+	_ = x[ElementTypeEmitStatement-24]
+	_ = x[ElementTypeVariableDeclaration-25]
+	_ = x[ElementTypeAssignmentStatement-26]
+	_ = x[ElementTypeSwapStatement-27]
+	_ = x[ElementTypeExpressionStatement-28]
+	_ = x[ElementTypeRemoveStatement-29]
+	_ = x[ElementTypeInvalidStatement-30]
+	_ = x[ElementTypeVoidExpression-31]
+	_ = x[ElementTypeBoolExpression-32]
+	_ = x[ElementTypeNilExpression-33]
+	_ = x[ElementTypeIntegerExpression-34]
+	_ = x[ElementTypeFixedPointExpression-35]
+	_ = x[ElementTypeArrayExpression-36]
+	_ = x[ElementTypeDictionaryExpression-37]
+	_ = x[ElementTypeIdentifierExpression-38]
+	_ = x[ElementTypeInvocationExpression-39]
+	_ = x[ElementTypeMemberExpression-40]
+	_ = x[ElementTypeIndexExpression-41]
+	_ = x[ElementTypeConditionalExpression-42]
+	_ = x[ElementTypeUnaryExpression-43]
+	_ = x[ElementTypeBinaryExpression-44]
+	_ = x[ElementTypeFunctionExpression-45]
+	_ = x[ElementTypeStringExpression-46]
+	_ = x[ElementTypeCastingExpression-47]
+	_ = x[ElementTypeCreateExpression-48]
+	_ = x[ElementTypeDestroyExpression-49]
+	_ = x[ElementTypeReferenceExpression-50]
+	_ = x[ElementTypeForceExpression-51]
+	_ = x[ElementTypePathExpression-52]
+	_ = x[ElementTypeAttachExpression-53]
+	_ = x[ElementTypeStringTemplateExpression-54]
+	_ = x[ElementTypeInvalidExpression-55]
 }
 
-const _ElementType_name = "ElementTypeUnknownElementTypeProgramElementTypeBlockElementTypeFunctionBlockElementTypeFunctionDeclarationElementTypeSpecialFunctionDeclarationElementTypeCompositeDeclarationElementTypeInterfaceDeclarationElementTypeEntitlementDeclarationElementTypeEntitlementMappingDeclarationElementTypeAttachmentDeclarationElementTypeFieldDeclarationElementTypeEnumCaseDeclarationElementTypePragmaDeclarationElementTypeImportDeclarationElementTypeTransactionDeclarationElementTypeReturnStatementElementTypeBreakStatementElementTypeContinueStatementElementTypeIfStatementElementTypeSwitchStatementElementTypeWhileStatementElementTypeForStatementElementTypeEmitStatementElementTypeVariableDeclarationElementTypeAssignmentStatementElementTypeSwapStatementElementTypeExpressionStatementElementTypeRemoveStatementElementTypeVoidExpressionElementTypeBoolExpressionElementTypeNilExpressionElementTypeIntegerExpressionElementTypeFixedPointExpressionElementTypeArrayExpressionElementTypeDictionaryExpressionElementTypeIdentifierExpressionElementTypeInvocationExpressionElementTypeMemberExpressionElementTypeIndexExpressionElementTypeConditionalExpressionElementTypeUnaryExpressionElementTypeBinaryExpressionElementTypeFunctionExpressionElementTypeStringExpressionElementTypeCastingExpressionElementTypeCreateExpressionElementTypeDestroyExpressionElementTypeReferenceExpressionElementTypeForceExpressionElementTypePathExpressionElementTypeAttachExpressionElementTypeStringTemplateExpression"
+const _ElementType_name = "ElementTypeUnknownElementTypeProgramElementTypeBlockElementTypeFunctionBlockElementTypeFunctionDeclarationElementTypeSpecialFunctionDeclarationElementTypeCompositeDeclarationElementTypeInterfaceDeclarationElementTypeEntitlementDeclarationElementTypeEntitlementMappingDeclarationElementTypeAttachmentDeclarationElementTypeFieldDeclarationElementTypeEnumCaseDeclarationElementTypePragmaDeclarationElementTypeImportDeclarationElementTypeTransactionDeclarationElementTypeInvalidDeclarationElementTypeReturnStatementElementTypeBreakStatementElementTypeContinueStatementElementTypeIfStatementElementTypeSwitchStatementElementTypeWhileStatementElementTypeForStatementElementTypeEmitStatementElementTypeVariableDeclarationElementTypeAssignmentStatementElementTypeSwapStatementElementTypeExpressionStatementElementTypeRemoveStatementElementTypeInvalidStatementElementTypeVoidExpressionElementTypeBoolExpressionElementTypeNilExpressionElementTypeIntegerExpressionElementTypeFixedPointExpressionElementTypeArrayExpressionElementTypeDictionaryExpressionElementTypeIdentifierExpressionElementTypeInvocationExpressionElementTypeMemberExpressionElementTypeIndexExpressionElementTypeConditionalExpressionElementTypeUnaryExpressionElementTypeBinaryExpressionElementTypeFunctionExpressionElementTypeStringExpressionElementTypeCastingExpressionElementTypeCreateExpressionElementTypeDestroyExpressionElementTypeReferenceExpressionElementTypeForceExpressionElementTypePathExpressionElementTypeAttachExpressionElementTypeStringTemplateExpressionElementTypeInvalidExpression"
 
-var _ElementType_index = [...]uint16{0, 18, 36, 52, 76, 106, 143, 174, 205, 238, 278, 310, 337, 367, 395, 423, 456, 482, 507, 535, 557, 583, 608, 631, 655, 685, 715, 739, 769, 795, 820, 845, 869, 897, 928, 954, 985, 1016, 1047, 1074, 1100, 1132, 1158, 1185, 1214, 1241, 1269, 1296, 1324, 1354, 1380, 1405, 1432, 1467}
+var _ElementType_index = [...]uint16{0, 18, 36, 52, 76, 106, 143, 174, 205, 238, 278, 310, 337, 367, 395, 423, 456, 485, 511, 536, 564, 586, 612, 637, 660, 684, 714, 744, 768, 798, 824, 851, 876, 901, 925, 953, 984, 1010, 1041, 1072, 1103, 1130, 1156, 1188, 1214, 1241, 1270, 1297, 1325, 1352, 1380, 1410, 1436, 1461, 1488, 1523, 1551}
 
 func (i ElementType) String() string {
 	if i >= ElementType(len(_ElementType_index)-1) {
@@ -852,3 +852,7 @@ func (extractor *ExpressionExtractor) ExtractAttach(expression *AttachExpression
 		ExtractedExpressions: extractedExpressions,
 	}
 }
+
+func (extractor *ExpressionExtractor) VisitInvalidExpression(expression *InvalidExpression) ExpressionExtraction {
+	return rewriteExpressionAsIs(expression)
+}
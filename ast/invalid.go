@@ -0,0 +1,197 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"encoding/json"
+
+	"github.com/turbolent/prettier"
+
+	"github.com/onflow/cadence/common"
+)
+
+// Invalid nodes are placeholders produced by the parser when it recovers from a syntax error.
+// They cover the source range which could not be parsed.
+// A program which contains invalid nodes always also has parsing errors
+
+var invalidDoc prettier.Doc = prettier.Text("/* invalid */")
+
+// InvalidDeclaration
+
+type InvalidDeclaration struct {
+	Range
+}
+
+var _ Element = &InvalidDeclaration{}
+var _ Declaration = &InvalidDeclaration{}
+
+func NewInvalidDeclaration(gauge common.MemoryGauge, declRange Range) *InvalidDeclaration {
+	common.UseMemory(gauge, common.InvalidDeclarationMemoryUsage)
+
+	return &InvalidDeclaration{
+		Range: declRange,
+	}
+}
+
+func (*InvalidDeclaration) ElementType() ElementType {
+	return ElementTypeInvalidDeclaration
+}
+
+func (*InvalidDeclaration) isDeclaration() {}
+
+func (*InvalidDeclaration) Walk(_ func(Element)) {
+	// NO-OP
+}
+
+func (*InvalidDeclaration) DeclarationIdentifier() *Identifier {
+	return nil
+}
+
+func (*InvalidDeclaration) DeclarationKind() common.DeclarationKind {
+	return common.DeclarationKindUnknown
+}
+
+func (*InvalidDeclaration) DeclarationAccess() Access {
+	return AccessNotSpecified
+}
+
+func (*InvalidDeclaration) DeclarationMembers() *Members {
+	return nil
+}
+
+func (*InvalidDeclaration) DeclarationDocString() string {
+	return ""
+}
+
+func (d *InvalidDeclaration) MarshalJSON() ([]byte, error) {
+	type Alias InvalidDeclaration
+	return json.Marshal(&struct {
+		*Alias
+		Type string
+	}{
+		Type:  "InvalidDeclaration",
+		Alias: (*Alias)(d),
+	})
+}
+
+func (*InvalidDeclaration) Doc() prettier.Doc {
+	return invalidDoc
+}
+
+func (d *InvalidDeclaration) String() string {
+	return Prettier(d)
+}
+
+// InvalidStatement
+
+type InvalidStatement struct {
+	Range
+}
+
+var _ Element = &InvalidStatement{}
+var _ Statement = &InvalidStatement{}
+
+func NewInvalidStatement(gauge common.MemoryGauge, statementRange Range) *InvalidStatement {
+	common.UseMemory(gauge, common.InvalidStatementMemoryUsage)
+
+	return &InvalidStatement{
+		Range: statementRange,
+	}
+}
+
+func (*InvalidStatement) ElementType() ElementType {
+	return ElementTypeInvalidStatement
+}
+
+func (*InvalidStatement) isStatement() {}
+
+func (*InvalidStatement) Walk(_ func(Element)) {
+	// NO-OP
+}
+
+func (s *InvalidStatement) MarshalJSON() ([]byte, error) {
+	type Alias InvalidStatement
+	return json.Marshal(&struct {
+		*Alias
+		Type string
+	}{
+		Type:  "InvalidStatement",
+		Alias: (*Alias)(s),
+	})
+}
+
+func (*InvalidStatement) Doc() prettier.Doc {
+	return invalidDoc
+}
+
+func (s *InvalidStatement) String() string {
+	return Prettier(s)
+}
+
+// InvalidExpression
+
+type InvalidExpression struct {
+	Range
+}
+
+var _ Element = &InvalidExpression{}
+var _ Expression = &InvalidExpression{}
+
+func NewInvalidExpression(gauge common.MemoryGauge, expressionRange Range) *InvalidExpression {
+	common.UseMemory(gauge, common.InvalidExpressionMemoryUsage)
+
+	return &InvalidExpression{
+		Range: expressionRange,
+	}
+}
+
+func (*InvalidExpression) ElementType() ElementType {
+	return ElementTypeInvalidExpression
+}
+
+func (*InvalidExpression) isExpression() {}
+
+func (*InvalidExpression) isIfStatementTest() {}
+
+func (*InvalidExpression) Walk(_ func(Element)) {
+	// NO-OP
+}
+
+func (e *InvalidExpression) MarshalJSON() ([]byte, error) {
+	type Alias InvalidExpression
+	return json.Marshal(&struct {
+		*Alias
+		Type string
+	}{
+		Type:  "InvalidExpression",
+		Alias: (*Alias)(e),
+	})
+}
+
+func (*InvalidExpression) Doc() prettier.Doc {
+	return invalidDoc
+}
+
+func (e *InvalidExpression) String() string {
+	return Prettier(e)
+}
+
+func (*InvalidExpression) precedence() precedence {
+	return precedenceLiteral
+}
@@ -46,6 +46,7 @@ type DeclarationVisitor[T any] interface {
 	VisitEnumCaseDeclaration(*EnumCaseDeclaration) T
 	VisitPragmaDeclaration(*PragmaDeclaration) T
 	VisitImportDeclaration(*ImportDeclaration) T
+	VisitInvalidDeclaration(*InvalidDeclaration) T
 }
 
 func AcceptDeclaration[T any](declaration Declaration, visitor DeclarationVisitor[T]) (_ T) {
@@ -90,6 +91,9 @@ func AcceptDeclaration[T any](declaration Declaration, visitor DeclarationVisito
 
 	case ElementTypeEntitlementMappingDeclaration:
 		return visitor.VisitEntitlementMappingDeclaration(declaration.(*EntitlementMappingDeclaration))
+
+	case ElementTypeInvalidDeclaration:
+		return visitor.VisitInvalidDeclaration(declaration.(*InvalidDeclaration))
 	}
 
 	panic(errors.NewUnreachableError())
@@ -109,6 +113,7 @@ type StatementVisitor[T any] interface {
 	VisitEmitStatement(*EmitStatement) T
 	VisitExpressionStatement(*ExpressionStatement) T
 	VisitRemoveStatement(*RemoveStatement) T
+	VisitInvalidStatement(*InvalidStatement) T
 }
 
 func AcceptStatement[T any](statement Statement, visitor StatementVisitor[T]) (_ T) {
@@ -173,6 +178,9 @@ func AcceptStatement[T any](statement Statement, visitor StatementVisitor[T]) (_
 
 	case ElementTypeRemoveStatement:
 		return visitor.VisitRemoveStatement(statement.(*RemoveStatement))
+
+	case ElementTypeInvalidStatement:
+		return visitor.VisitInvalidStatement(statement.(*InvalidStatement))
 	}
 
 	panic(errors.NewUnreachableError())
@@ -203,6 +211,7 @@ type ExpressionVisitor[T any] interface {
 	VisitBinaryExpression(*BinaryExpression) T
 	VisitConditionalExpression(*ConditionalExpression) T
 	VisitAttachExpression(*AttachExpression) T
+	VisitInvalidExpression(*InvalidExpression) T
 }
 
 func AcceptExpression[T any](expression Expression, visitor ExpressionVisitor[T]) (_ T) {
@@ -279,6 +288,9 @@ func AcceptExpression[T any](expression Expression, visitor ExpressionVisitor[T]
 
 	case ElementTypeAttachExpression:
 		return visitor.VisitAttachExpression(expression.(*AttachExpression))
+
+	case ElementTypeInvalidExpression:
+		return visitor.VisitInvalidExpression(expression.(*InvalidExpression))
 	}
 
 	panic(errors.NewUnreachableError())
@@ -0,0 +1,1141 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/bbq"
+	"github.com/onflow/cadence/bbq/opcode"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/fixedpoint"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/sema"
+)
+
+// Compiler compiles the global functions of a checked program to bytecode.
+//
+// The compiler only supports a subset of the language.
+// Functions which use unsupported features are not compiled,
+// and are executed by the interpreter instead.
+type Compiler struct {
+	program         *interpreter.Program
+	elaboration     *sema.Elaboration
+	globalFunctions map[string]struct{}
+
+	output        *bbq.Program
+	nameIndices   map[string]uint16
+	typeIndices   map[sema.TypeID]uint16
+	stringIndices map[string]uint16
+
+	function *function
+}
+
+type function struct {
+	declaration  *ast.FunctionDeclaration
+	functionType *sema.FunctionType
+	code         []byte
+	positions    []bbq.Position
+	scopes       []*scope
+	localCount   int
+	loops        []*loop
+	// postConditions are the rewritten post-conditions of the function, if any.
+	// Return statements store the result in resultLocal,
+	// and jump to the evaluation of the post-conditions
+	postConditions []ast.Condition
+	resultLocal    uint16
+	returnJumps    []int
+}
+
+type scope struct {
+	locals          map[string]uint16
+	hasDeclarations bool
+}
+
+type loop struct {
+	start      int
+	breakJumps []int
+}
+
+// Compile compiles the global functions of the given program.
+//
+// It returns the compiled program,
+// and the errors for the functions which could not be compiled.
+func Compile(program *interpreter.Program) (*bbq.Program, []*UnsupportedError) {
+	return NewCompiler(program).Compile()
+}
+
+func NewCompiler(program *interpreter.Program) *Compiler {
+	return &Compiler{
+		program:         program,
+		elaboration:     program.Elaboration,
+		globalFunctions: map[string]struct{}{},
+		output:          &bbq.Program{},
+		nameIndices:     map[string]uint16{},
+		typeIndices:     map[sema.TypeID]uint16{},
+		stringIndices:   map[string]uint16{},
+	}
+}
+
+func (c *Compiler) Compile() (*bbq.Program, []*UnsupportedError) {
+
+	declarations := c.program.Program.FunctionDeclarations()
+
+	for _, declaration := range declarations {
+		c.globalFunctions[declaration.Identifier.Identifier] = struct{}{}
+	}
+
+	var unsupportedErrors []*UnsupportedError
+
+	for _, declaration := range declarations {
+		compiledFunction, err := c.compileFunction(declaration)
+		if err != nil {
+			unsupportedErrors = append(unsupportedErrors, err)
+			continue
+		}
+		c.output.Functions = append(c.output.Functions, compiledFunction)
+	}
+
+	return c.output, unsupportedErrors
+}
+
+// unsupportedFeature is used to abort the compilation of a function.
+type unsupportedFeature struct {
+	feature string
+	element ast.HasPosition
+}
+
+func (c *Compiler) unsupported(feature string, element ast.HasPosition) {
+	panic(unsupportedFeature{
+		feature: feature,
+		element: element,
+	})
+}
+
+// elementDescription returns a description of the kind of the given element,
+// e.g. "for statement" for a *ast.ForStatement
+func elementDescription(element ast.Element) string {
+	name := strings.TrimPrefix(element.ElementType().String(), "ElementType")
+
+	var builder strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteByte(' ')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func (c *Compiler) compileFunction(declaration *ast.FunctionDeclaration) (result *bbq.Function, err *UnsupportedError) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			unsupported, ok := r.(unsupportedFeature)
+			if !ok {
+				panic(r)
+			}
+			err = &UnsupportedError{
+				FunctionName: declaration.Identifier.Identifier,
+				Feature:      unsupported.feature,
+				Range:        ast.NewUnmeteredRangeFromPositioned(unsupported.element),
+			}
+		}
+	}()
+
+	functionBlock := declaration.FunctionBlock
+	if functionBlock == nil {
+		c.unsupported("native function", declaration)
+	}
+
+	if declaration.TypeParameterList != nil &&
+		len(declaration.TypeParameterList.TypeParameters) > 0 {
+
+		c.unsupported("generic function", declaration.TypeParameterList)
+	}
+
+	functionType := c.elaboration.FunctionDeclarationFunctionType(declaration)
+
+	for _, parameter := range functionType.Parameters {
+		c.checkResourceType(parameter.TypeAnnotation.Type, declaration.ParameterList)
+	}
+	c.checkResourceType(functionType.ReturnTypeAnnotation.Type, declaration)
+
+	c.function = &function{
+		declaration:  declaration,
+		functionType: functionType,
+	}
+	defer func() {
+		c.function = nil
+	}()
+
+	// The parameters are declared in the function's scope,
+	// the statements are executed in the function body's scope
+
+	parameterScope := c.pushScope()
+	var parameterCount int
+	if declaration.ParameterList != nil {
+		for _, parameter := range declaration.ParameterList.Parameters {
+			c.declareLocal(parameter.Identifier.Identifier)
+		}
+		parameterCount = len(declaration.ParameterList.Parameters)
+	}
+	parameterScope.hasDeclarations = parameterCount > 0
+
+	c.pushScope()
+
+	if !functionBlock.PostConditions.IsEmpty() {
+		postConditionsRewrite := c.elaboration.PostConditionsRewrite(functionBlock.PostConditions)
+
+		// Like the interpreter, evaluate the expressions of `before` invocations
+		// before the pre-conditions
+		c.compileStatements(postConditionsRewrite.BeforeStatements)
+
+		c.function.postConditions = postConditionsRewrite.RewrittenPostConditions
+		c.function.resultLocal = c.newLocal()
+	}
+
+	if !functionBlock.PreConditions.IsEmpty() {
+		c.compileConditions(functionBlock.PreConditions.Conditions)
+	}
+
+	c.compileStatements(functionBlock.Block.Statements)
+
+	if c.function.postConditions != nil {
+		c.compilePostConditions()
+	} else {
+		c.emit(opcode.Return, nil)
+	}
+
+	c.popScope()
+
+	c.popScope()
+
+	if len(c.function.code) > math.MaxUint16 {
+		c.unsupported("function larger than 64 KiB", declaration)
+	}
+
+	return &bbq.Function{
+		Name:           declaration.Identifier.Identifier,
+		Type:           functionType,
+		Code:           c.function.code,
+		Positions:      c.function.positions,
+		ParameterCount: parameterCount,
+		LocalCount:     c.function.localCount,
+	}, nil
+}
+
+func (c *Compiler) checkResourceType(ty sema.Type, element ast.HasPosition) {
+	if ty.IsResourceType() {
+		c.unsupported("resource", element)
+	}
+}
+
+// Scopes
+
+func (c *Compiler) pushScope() *scope {
+	scope := &scope{
+		locals: map[string]uint16{},
+	}
+	c.function.scopes = append(c.function.scopes, scope)
+	return scope
+}
+
+func (c *Compiler) popScope() {
+	scopes := c.function.scopes
+	c.function.scopes = scopes[:len(scopes)-1]
+}
+
+func (c *Compiler) currentScope() *scope {
+	scopes := c.function.scopes
+	return scopes[len(scopes)-1]
+}
+
+func (c *Compiler) declareLocal(name string) uint16 {
+	index := c.newLocal()
+	c.currentScope().locals[name] = index
+	return index
+}
+
+// newLocal allocates a new local, without declaring it in the current scope
+func (c *Compiler) newLocal() uint16 {
+	index := c.function.localCount
+	if index > math.MaxUint16 {
+		c.unsupported("function with more than 65536 locals", c.function.declaration)
+	}
+	c.function.localCount++
+
+	return uint16(index)
+}
+
+func (c *Compiler) findLocal(name string) (uint16, bool) {
+	scopes := c.function.scopes
+	for i := len(scopes) - 1; i >= 0; i-- {
+		index, ok := scopes[i].locals[name]
+		if ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// Emission
+
+func (c *Compiler) emit(op opcode.Opcode, position ast.HasPosition, operands ...uint16) int {
+	function := c.function
+	offset := len(function.code)
+
+	if position != nil {
+		function.positions = append(
+			function.positions,
+			bbq.Position{
+				Offset: offset,
+				Range:  ast.NewUnmeteredRangeFromPositioned(position),
+			},
+		)
+	}
+
+	function.code = append(function.code, byte(op))
+
+	sizes := op.OperandSizes()
+	if len(sizes) != len(operands) {
+		panic(errors.NewUnreachableError())
+	}
+
+	for i, operand := range operands {
+		switch sizes[i] {
+		case 1:
+			function.code = append(function.code, byte(operand))
+		case 2:
+			function.code = append(function.code, byte(operand>>8), byte(operand))
+		default:
+			panic(errors.NewUnreachableError())
+		}
+	}
+
+	return offset
+}
+
+// emitJump emits a jump instruction with a placeholder target,
+// which must be patched using patchJump.
+func (c *Compiler) emitJump(op opcode.Opcode) int {
+	return c.emit(op, nil, math.MaxUint16)
+}
+
+func (c *Compiler) patchJump(offset int) {
+	c.patchJumpTo(offset, len(c.function.code))
+}
+
+func (c *Compiler) patchJumpTo(offset int, target int) {
+	if target > math.MaxUint16 {
+		c.unsupported("function larger than 64 KiB", c.function.declaration)
+	}
+	code := c.function.code
+	code[offset+1] = byte(target >> 8)
+	code[offset+2] = byte(target)
+}
+
+func (c *Compiler) declarationFlag(first bool) uint16 {
+	if first {
+		return 1
+	}
+	return 0
+}
+
+func (c *Compiler) nameIndex(name string) uint16 {
+	index, ok := c.nameIndices[name]
+	if ok {
+		return index
+	}
+	index = c.newIndex(len(c.output.Names))
+	c.output.Names = append(c.output.Names, name)
+	c.nameIndices[name] = index
+	return index
+}
+
+func (c *Compiler) typeIndex(ty sema.Type) uint16 {
+	typeID := ty.ID()
+	index, ok := c.typeIndices[typeID]
+	if ok {
+		return index
+	}
+	index = c.newIndex(len(c.output.Types))
+	c.output.Types = append(c.output.Types, ty)
+	c.typeIndices[typeID] = index
+	return index
+}
+
+func (c *Compiler) constantIndex(constant bbq.Constant) uint16 {
+	index := c.newIndex(len(c.output.Constants))
+	c.output.Constants = append(c.output.Constants, constant)
+	return index
+}
+
+func (c *Compiler) stringConstantIndex(kind bbq.ConstantKind, value string) uint16 {
+	key := string(rune(kind)) + value
+	index, ok := c.stringIndices[key]
+	if ok {
+		return index
+	}
+	index = c.constantIndex(bbq.Constant{
+		Kind:   kind,
+		String: value,
+	})
+	c.stringIndices[key] = index
+	return index
+}
+
+func (c *Compiler) invocationIndex(types sema.InvocationExpressionTypes) uint16 {
+	index := c.newIndex(len(c.output.Invocations))
+	c.output.Invocations = append(
+		c.output.Invocations,
+		bbq.InvocationTypes{
+			TypeArguments:  types.TypeArguments,
+			ArgumentTypes:  types.ArgumentTypes,
+			ParameterTypes: types.ParameterTypes,
+			ReturnType:     types.ReturnType,
+		},
+	)
+	return index
+}
+
+func (c *Compiler) newIndex(index int) uint16 {
+	if index > math.MaxUint16 {
+		c.unsupported("program with more than 65536 constants, names, or types", c.function.declaration)
+	}
+	return uint16(index)
+}
+
+// Statements
+
+func (c *Compiler) compileStatements(statements []ast.Statement) {
+	for _, statement := range statements {
+		c.compileStatement(statement)
+	}
+}
+
+func (c *Compiler) compileBlock(block *ast.Block) {
+	c.emit(opcode.EnterBlock, nil)
+	c.pushScope()
+	c.compileStatements(block.Statements)
+	c.popScope()
+}
+
+func (c *Compiler) compileStatement(statement ast.Statement) {
+
+	c.emit(opcode.Statement, statement)
+
+	switch statement := statement.(type) {
+	case *ast.ReturnStatement:
+		c.compileReturnStatement(statement)
+
+	case *ast.ExpressionStatement:
+		c.compileExpression(statement.Expression)
+		c.emit(opcode.Pop, nil)
+
+	case *ast.IfStatement:
+		c.compileIfStatement(statement)
+
+	case *ast.WhileStatement:
+		c.compileWhileStatement(statement)
+
+	case *ast.ForStatement:
+		c.compileForStatement(statement)
+
+	case *ast.BreakStatement:
+		loop := c.currentLoop()
+		offset := c.emitJump(opcode.Jump)
+		loop.breakJumps = append(loop.breakJumps, offset)
+
+	case *ast.ContinueStatement:
+		loop := c.currentLoop()
+		c.emit(opcode.Jump, nil, uint16(loop.start))
+
+	case *ast.VariableDeclaration:
+		c.compileVariableDeclaration(statement)
+
+	case *ast.AssignmentStatement:
+		c.compileAssignmentStatement(statement)
+
+	default:
+		c.unsupported(elementDescription(statement), statement)
+	}
+}
+
+func (c *Compiler) currentLoop() *loop {
+	loops := c.function.loops
+	return loops[len(loops)-1]
+}
+
+func (c *Compiler) compileReturnStatement(statement *ast.ReturnStatement) {
+	hasValue := statement.Expression != nil
+
+	if hasValue {
+		c.compileExpression(statement.Expression)
+
+		returnStatementTypes := c.elaboration.ReturnStatementTypes(statement)
+		c.emitTransfer(
+			returnStatementTypes.ValueType,
+			returnStatementTypes.ReturnType,
+			statement.Expression,
+		)
+	}
+
+	if c.function.postConditions != nil {
+		c.compileResultDeclaration(hasValue)
+		offset := c.emitJump(opcode.Jump)
+		c.function.returnJumps = append(c.function.returnJumps, offset)
+		return
+	}
+
+	c.compileResultDeclaration(false)
+
+	if hasValue {
+		c.emit(opcode.ReturnValue, nil)
+	} else {
+		c.emit(opcode.Return, nil)
+	}
+}
+
+// compileResultDeclaration emits the declaration of the `result` constant,
+// which the interpreter declares for functions with a return type.
+// If the result is stored, the returned value is popped into the result local
+func (c *Compiler) compileResultDeclaration(store bool) {
+	if c.function.functionType.ReturnTypeAnnotation.Type == sema.VoidType {
+		if store {
+			c.emit(opcode.Pop, nil)
+		}
+		return
+	}
+
+	// The result is declared in the function body's scope
+	bodyScope := c.function.scopes[1]
+	first := c.declarationFlag(!bodyScope.hasDeclarations)
+
+	if store {
+		c.emit(opcode.DeclareLocal, nil, c.function.resultLocal, first)
+	} else {
+		c.emit(opcode.DeclareResult, nil, first)
+	}
+}
+
+// compilePostConditions emits the evaluation of the post-conditions,
+// which all return statements jump to, and the return of the result
+func (c *Compiler) compilePostConditions() {
+	for _, offset := range c.function.returnJumps {
+		c.patchJump(offset)
+	}
+
+	hasResult := c.function.functionType.ReturnTypeAnnotation.Type != sema.VoidType
+	if hasResult {
+		// The result is only accessible in the post-conditions
+		c.currentScope().locals[sema.ResultIdentifier] = c.function.resultLocal
+	}
+
+	c.compileConditions(c.function.postConditions)
+
+	if hasResult {
+		c.emit(opcode.GetLocal, nil, c.function.resultLocal)
+		c.emit(opcode.ReturnValue, nil)
+	} else {
+		c.emit(opcode.Return, nil)
+	}
+}
+
+// compileConditions compiles the given function conditions.
+// Like an invocation of the `panic` function, a failed test condition aborts the execution
+// with the condition's message, if any
+func (c *Compiler) compileConditions(conditions []ast.Condition) {
+	for _, condition := range conditions {
+		testCondition, ok := condition.(*ast.TestCondition)
+		if !ok {
+			c.unsupported("emit condition", condition)
+		}
+
+		c.emit(opcode.Condition, testCondition.Test)
+		c.compileExpression(testCondition.Test)
+		endJump := c.emitJump(opcode.JumpIfTrue)
+
+		if testCondition.Message != nil {
+			c.compileExpression(testCondition.Message)
+		} else {
+			c.emit(
+				opcode.GetConstant,
+				nil,
+				c.stringConstantIndex(bbq.ConstantKindString, conditionFailedMessage),
+			)
+		}
+		c.emit(opcode.Panic, testCondition.Test)
+
+		c.patchJump(endJump)
+	}
+}
+
+const conditionFailedMessage = "pre/post condition failed"
+
+func (c *Compiler) compileIfStatement(statement *ast.IfStatement) {
+	test, ok := statement.Test.(ast.Expression)
+	if !ok {
+		c.unsupported("optional binding", statement)
+	}
+
+	c.compileExpression(test)
+	elseJump := c.emitJump(opcode.JumpIfFalse)
+
+	c.compileBlock(statement.Then)
+
+	if statement.Else == nil {
+		c.patchJump(elseJump)
+		return
+	}
+
+	endJump := c.emitJump(opcode.Jump)
+	c.patchJump(elseJump)
+	c.compileBlock(statement.Else)
+	c.patchJump(endJump)
+}
+
+func (c *Compiler) compileWhileStatement(statement *ast.WhileStatement) {
+	start := len(c.function.code)
+
+	c.compileExpression(statement.Test)
+	endJump := c.emitJump(opcode.JumpIfFalse)
+
+	c.emit(opcode.Loop, statement)
+
+	loop := &loop{
+		start: start,
+	}
+	c.function.loops = append(c.function.loops, loop)
+
+	c.compileBlock(statement.Block)
+
+	c.function.loops = c.function.loops[:len(c.function.loops)-1]
+
+	c.emit(opcode.Jump, nil, uint16(start))
+
+	c.patchJump(endJump)
+	for _, breakJump := range loop.breakJumps {
+		c.patchJump(breakJump)
+	}
+}
+
+// compileForStatement compiles a for-in statement over an inclusive range or a string.
+// Iterations over other values are not supported yet
+func (c *Compiler) compileForStatement(statement *ast.ForStatement) {
+	if statement.Index != nil {
+		c.unsupported("for statement with index", statement)
+	}
+
+	forStatementTypes := c.elaboration.ForStatementType(statement)
+	switch forStatementTypes.IterableType.(type) {
+	case *sema.InclusiveRangeType:
+	default:
+		if forStatementTypes.IterableType != sema.StringType {
+			c.unsupported("for statement", statement)
+		}
+	}
+
+	// Like the interpreter, the iterable value is evaluated in the for statement's scope,
+	// and the element is declared in a new scope for each iteration
+
+	c.emit(opcode.EnterBlock, nil)
+	c.pushScope()
+
+	c.compileExpression(statement.Value)
+	c.emit(opcode.Iterator, statement)
+
+	start := len(c.function.code)
+	endJump := c.emit(opcode.IteratorNext, statement, math.MaxUint16)
+
+	c.emit(opcode.Loop, statement)
+
+	c.emit(opcode.EnterBlock, nil)
+	iterationScope := c.pushScope()
+	iterationScope.hasDeclarations = true
+	index := c.declareLocal(statement.Identifier.Identifier)
+	c.emit(opcode.DeclareLocal, nil, index, c.declarationFlag(true))
+
+	loop := &loop{
+		start: start,
+	}
+	c.function.loops = append(c.function.loops, loop)
+
+	c.compileBlock(statement.Block)
+
+	c.function.loops = c.function.loops[:len(c.function.loops)-1]
+
+	c.popScope()
+
+	c.emit(opcode.Jump, nil, uint16(start))
+
+	c.patchJump(endJump)
+	for _, breakJump := range loop.breakJumps {
+		c.patchJump(breakJump)
+	}
+	c.emit(opcode.IteratorEnd, nil)
+
+	c.popScope()
+}
+
+func (c *Compiler) compileVariableDeclaration(declaration *ast.VariableDeclaration) {
+	if declaration.SecondValue != nil {
+		c.unsupported("variable declaration with a second value", declaration)
+	}
+
+	variableDeclarationTypes := c.elaboration.VariableDeclarationTypes(declaration)
+	c.checkResourceType(variableDeclarationTypes.TargetType, declaration)
+
+	// Like the interpreter, access the value like an assignment target,
+	// e.g. indexing values are transferred
+	c.compileAssignmentTargetValue(declaration.Value)
+
+	c.emitTransfer(
+		variableDeclarationTypes.ValueType,
+		variableDeclarationTypes.TargetType,
+		declaration.Value,
+	)
+
+	// NOTE: declare the local after compiling the value,
+	// the value may refer to a shadowed variable
+
+	scope := c.currentScope()
+	first := !scope.hasDeclarations
+	scope.hasDeclarations = true
+
+	index := c.declareLocal(declaration.Identifier.Identifier)
+	c.emit(opcode.DeclareLocal, nil, index, c.declarationFlag(first))
+}
+
+func (c *Compiler) compileAssignmentStatement(assignment *ast.AssignmentStatement) {
+	assignmentStatementTypes := c.elaboration.AssignmentStatementTypes(assignment)
+
+	switch target := assignment.Target.(type) {
+	case *ast.IdentifierExpression:
+		c.compileExpression(assignment.Value)
+		c.emitTransfer(
+			assignmentStatementTypes.ValueType,
+			assignmentStatementTypes.TargetType,
+			target,
+		)
+
+		name := target.Identifier.Identifier
+		index, ok := c.findLocal(name)
+		if ok {
+			c.emit(opcode.SetLocal, target, index)
+		} else {
+			c.emit(opcode.SetGlobal, target, c.nameIndex(name))
+		}
+
+	case *ast.IndexExpression:
+		c.compileIndexTarget(target)
+
+		c.compileExpression(assignment.Value)
+		c.emitTransfer(
+			assignmentStatementTypes.ValueType,
+			assignmentStatementTypes.TargetType,
+			target,
+		)
+		// NOTE: the instruction covers the whole assignment
+		c.emit(opcode.SetIndex, assignment)
+
+	default:
+		c.unsupported("assignment to a member", assignment)
+	}
+}
+
+func (c *Compiler) emitTransfer(valueType, targetType sema.Type, position ast.HasPosition) {
+	c.emit(
+		opcode.Transfer,
+		position,
+		c.typeIndex(valueType),
+		c.typeIndex(targetType),
+	)
+}
+
+// Expressions
+
+func (c *Compiler) compileExpression(expression ast.Expression) {
+	switch expression := expression.(type) {
+	case *ast.BoolExpression:
+		if expression.Value {
+			c.emit(opcode.True, nil)
+		} else {
+			c.emit(opcode.False, nil)
+		}
+
+	case *ast.NilExpression:
+		c.emit(opcode.Nil, nil)
+
+	case *ast.VoidExpression:
+		c.emit(opcode.Void, nil)
+
+	case *ast.IntegerExpression:
+		c.compileIntegerExpression(expression)
+
+	case *ast.FixedPointExpression:
+		c.compileFixedPointExpression(expression)
+
+	case *ast.StringExpression:
+		kind := bbq.ConstantKindString
+		if c.elaboration.StringExpressionType(expression) == sema.CharacterType {
+			kind = bbq.ConstantKindCharacter
+		}
+		c.emit(opcode.GetConstant, nil, c.stringConstantIndex(kind, expression.Value))
+
+	case *ast.StringTemplateExpression:
+		c.compileStringTemplateExpression(expression)
+
+	case *ast.ArrayExpression:
+		c.compileArrayExpression(expression)
+
+	case *ast.IdentifierExpression:
+		c.compileIdentifierExpression(expression)
+
+	case *ast.BinaryExpression:
+		c.compileBinaryExpression(expression)
+
+	case *ast.UnaryExpression:
+		c.compileUnaryExpression(expression)
+
+	case *ast.ConditionalExpression:
+		c.compileExpression(expression.Test)
+		elseJump := c.emitJump(opcode.JumpIfFalse)
+		c.compileExpression(expression.Then)
+		endJump := c.emitJump(opcode.Jump)
+		c.patchJump(elseJump)
+		c.compileExpression(expression.Else)
+		c.patchJump(endJump)
+
+	case *ast.InvocationExpression:
+		c.compileInvocationExpression(expression)
+
+	case *ast.MemberExpression:
+		c.compileMemberExpression(expression)
+
+	case *ast.IndexExpression:
+		c.compileIndexExpression(expression, false)
+
+	default:
+		c.unsupported(elementDescription(expression), expression)
+	}
+}
+
+func (c *Compiler) compileIntegerExpression(expression *ast.IntegerExpression) {
+	integerType := c.elaboration.IntegerExpressionType(expression)
+
+	kind := bbq.ConstantKindInteger
+	if _, ok := integerType.(*sema.AddressType); ok {
+		kind = bbq.ConstantKindAddress
+	}
+
+	index := c.constantIndex(bbq.Constant{
+		Kind:    kind,
+		Type:    integerType,
+		Integer: expression.Value,
+	})
+	c.emit(opcode.GetConstant, nil, index)
+}
+
+func (c *Compiler) compileFixedPointExpression(expression *ast.FixedPointExpression) {
+	fixedPointType := c.elaboration.FixedPointExpression(expression)
+
+	value := fixedpoint.ConvertToFixedPointBigInt(
+		expression.Negative,
+		expression.UnsignedInteger,
+		expression.Fractional,
+		expression.Scale,
+		sema.Fix64Scale,
+	)
+
+	var kind bbq.ConstantKind
+	switch fixedPointType {
+	case sema.Fix64Type, sema.SignedFixedPointType:
+		kind = bbq.ConstantKindFix64
+	case sema.UFix64Type:
+		kind = bbq.ConstantKindUFix64
+	case sema.FixedPointType:
+		if expression.Negative {
+			kind = bbq.ConstantKindFix64
+		} else {
+			kind = bbq.ConstantKindUFix64
+		}
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	index := c.constantIndex(bbq.Constant{
+		Kind:    kind,
+		Type:    fixedPointType,
+		Integer: new(big.Int).Set(value),
+	})
+	c.emit(opcode.GetConstant, nil, index)
+}
+
+func (c *Compiler) compileStringTemplateExpression(expression *ast.StringTemplateExpression) {
+	// The string parts and the values of the expressions are interleaved,
+	// so the expressions are evaluated in order, like in the interpreter
+
+	for i, value := range expression.Values {
+		c.emit(opcode.GetConstant, nil, c.stringConstantIndex(bbq.ConstantKindString, value))
+		if i < len(expression.Expressions) {
+			c.compileExpression(expression.Expressions[i])
+		}
+	}
+
+	count := len(expression.Values) + len(expression.Expressions)
+	if count > math.MaxUint16 {
+		c.unsupported("string template with more than 65535 parts", expression)
+	}
+
+	c.emit(opcode.Template, nil, uint16(count))
+}
+
+func (c *Compiler) compileArrayExpression(expression *ast.ArrayExpression) {
+	arrayExpressionTypes := c.elaboration.ArrayExpressionTypes(expression)
+	elementType := arrayExpressionTypes.ArrayType.ElementType(false)
+
+	for i, value := range expression.Values {
+		c.compileExpression(value)
+		c.emitTransfer(
+			arrayExpressionTypes.ArgumentTypes[i],
+			elementType,
+			value,
+		)
+	}
+
+	count := len(expression.Values)
+	if count > math.MaxUint16 {
+		c.unsupported("array literal with more than 65535 elements", expression)
+	}
+
+	c.emit(
+		opcode.NewArray,
+		expression,
+		c.typeIndex(arrayExpressionTypes.ArrayType),
+		uint16(count),
+	)
+}
+
+func (c *Compiler) compileIdentifierExpression(expression *ast.IdentifierExpression) {
+	name := expression.Identifier.Identifier
+
+	index, ok := c.findLocal(name)
+	if ok {
+		c.emit(opcode.GetLocal, expression, index)
+		return
+	}
+
+	c.emit(opcode.GetGlobal, expression, c.nameIndex(name))
+}
+
+var binaryOpcodes = map[ast.Operation]opcode.Opcode{
+	ast.OperationPlus:              opcode.Add,
+	ast.OperationMinus:             opcode.Subtract,
+	ast.OperationMul:               opcode.Multiply,
+	ast.OperationDiv:               opcode.Divide,
+	ast.OperationMod:               opcode.Mod,
+	ast.OperationBitwiseOr:         opcode.BitwiseOr,
+	ast.OperationBitwiseXor:        opcode.BitwiseXor,
+	ast.OperationBitwiseAnd:        opcode.BitwiseAnd,
+	ast.OperationBitwiseLeftShift:  opcode.BitwiseLeftShift,
+	ast.OperationBitwiseRightShift: opcode.BitwiseRightShift,
+	ast.OperationLess:              opcode.Less,
+	ast.OperationLessEqual:         opcode.LessOrEqual,
+	ast.OperationGreater:           opcode.Greater,
+	ast.OperationGreaterEqual:      opcode.GreaterOrEqual,
+	ast.OperationEqual:             opcode.Equal,
+	ast.OperationNotEqual:          opcode.NotEqual,
+}
+
+func (c *Compiler) compileBinaryExpression(expression *ast.BinaryExpression) {
+	switch expression.Operation {
+	case ast.OperationOr, ast.OperationAnd:
+		// Short-circuit: Only evaluate the right-hand side
+		// if the left-hand side does not determine the result
+
+		c.compileExpression(expression.Left)
+		c.emit(opcode.Dup, nil)
+
+		var endJump int
+		if expression.Operation == ast.OperationOr {
+			endJump = c.emitJump(opcode.JumpIfTrue)
+		} else {
+			endJump = c.emitJump(opcode.JumpIfFalse)
+		}
+
+		c.emit(opcode.Pop, nil)
+		c.compileExpression(expression.Right)
+		c.patchJump(endJump)
+
+		return
+	}
+
+	op, ok := binaryOpcodes[expression.Operation]
+	if !ok {
+		c.unsupported(expression.Operation.Category()+" operation", expression)
+	}
+
+	c.compileExpression(expression.Left)
+	c.compileExpression(expression.Right)
+	c.emit(op, expression)
+}
+
+func (c *Compiler) compileUnaryExpression(expression *ast.UnaryExpression) {
+	var op opcode.Opcode
+	switch expression.Operation {
+	case ast.OperationNegate:
+		op = opcode.Not
+	case ast.OperationMinus:
+		op = opcode.Negate
+	default:
+		c.unsupported(expression.Operation.Category()+" operation", expression)
+	}
+
+	c.compileExpression(expression.Expression)
+	c.emit(op, expression)
+}
+
+func (c *Compiler) compileInvocationExpression(expression *ast.InvocationExpression) {
+	invocationExpressionTypes := c.elaboration.InvocationExpressionTypes(expression)
+
+	// Global functions of the program are invoked directly
+
+	if identifierExpression, ok := expression.InvokedExpression.(*ast.IdentifierExpression); ok {
+		name := identifierExpression.Identifier.Identifier
+		_, isLocal := c.findLocal(name)
+		_, isGlobalFunction := c.globalFunctions[name]
+		if !isLocal && isGlobalFunction {
+			c.compileArguments(expression.Arguments)
+			c.emit(
+				opcode.InvokeGlobal,
+				expression,
+				c.nameIndex(name),
+				c.invocationIndex(invocationExpressionTypes),
+			)
+			return
+		}
+	}
+
+	c.compileExpression(expression.InvokedExpression)
+	c.compileArguments(expression.Arguments)
+	c.emit(
+		opcode.Invoke,
+		expression,
+		c.invocationIndex(invocationExpressionTypes),
+	)
+}
+
+func (c *Compiler) compileArguments(arguments ast.Arguments) {
+	for _, argument := range arguments {
+		c.compileExpression(argument.Expression)
+	}
+}
+
+func (c *Compiler) compileMemberExpression(expression *ast.MemberExpression) {
+	if expression.Optional {
+		c.unsupported("optional chaining", expression)
+	}
+
+	memberAccessInfo, ok := c.elaboration.MemberExpressionMemberAccessInfo(expression)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	if memberAccessInfo.ReturnReference {
+		c.unsupported("member access through a reference", expression)
+	}
+
+	if c.elaboration.IsNestedResourceMoveExpression(expression) {
+		c.unsupported("resource", expression)
+	}
+
+	c.compileExpression(expression.Expression)
+	c.emit(
+		opcode.GetField,
+		expression,
+		c.nameIndex(expression.Identifier.Identifier),
+		c.typeIndex(memberAccessInfo.AccessedType),
+	)
+}
+
+// compileAssignmentTargetValue compiles the given expression
+// like the interpreter evaluates the value of an assignment target
+func (c *Compiler) compileAssignmentTargetValue(expression ast.Expression) {
+	if indexExpression, ok := expression.(*ast.IndexExpression); ok {
+		c.compileIndexExpression(indexExpression, true)
+		return
+	}
+
+	c.compileExpression(expression)
+}
+
+func (c *Compiler) compileIndexExpression(expression *ast.IndexExpression, isTarget bool) {
+	if isTarget {
+		c.compileIndexTarget(expression)
+	} else {
+		c.checkIndexExpression(expression)
+		c.compileExpression(expression.TargetExpression)
+		c.compileExpression(expression.IndexingExpression)
+	}
+
+	c.emit(opcode.GetIndex, expression)
+}
+
+// compileIndexTarget compiles the indexed value and the indexing value of an index expression
+// which is used as an assignment target. In that case, the indexing value is transferred
+func (c *Compiler) compileIndexTarget(expression *ast.IndexExpression) {
+	indexExpressionTypes := c.checkIndexExpression(expression)
+
+	c.compileAssignmentTargetValue(expression.TargetExpression)
+	c.compileExpression(expression.IndexingExpression)
+	c.emitTransfer(
+		indexExpressionTypes.IndexingType,
+		indexExpressionTypes.IndexedType.IndexingType(),
+		expression.IndexingExpression,
+	)
+}
+
+func (c *Compiler) checkIndexExpression(expression *ast.IndexExpression) sema.IndexExpressionTypes {
+	if _, ok := c.elaboration.AttachmentAccessTypes(expression); ok {
+		c.unsupported("attachment", expression)
+	}
+
+	if c.elaboration.IsNestedResourceMoveExpression(expression) {
+		c.unsupported("resource", expression)
+	}
+
+	indexExpressionTypes, ok := c.elaboration.IndexExpressionTypes(expression)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	if indexExpressionTypes.ReturnReference {
+		c.unsupported("index access through a reference", expression)
+	}
+
+	return indexExpressionTypes
+}
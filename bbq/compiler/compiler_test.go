@@ -0,0 +1,133 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/bbq/compiler"
+	. "github.com/onflow/cadence/test_utils"
+)
+
+func TestCompileFunction(t *testing.T) {
+
+	t.Parallel()
+
+	inter := ParseCheckAndInterpret(t, `
+      fun test(_ n: Int): Int {
+          var i = 0
+          while i < n {
+              i = i + 1
+          }
+          return i
+      }
+    `)
+
+	program, unsupportedErrors := compiler.Compile(inter.Program)
+	require.Empty(t, unsupportedErrors)
+
+	function := program.Function("test")
+	require.NotNil(t, function)
+
+	assert.Equal(t, 1, function.ParameterCount)
+	assert.Equal(t, 2, function.LocalCount)
+	assert.Equal(t,
+		`fun test:
+    0  Statement
+    1  GetConstant 0
+    4  Transfer 0 0
+    9  DeclareLocal 1 1
+   13  Statement
+   14  GetLocal 1
+   17  GetLocal 0
+   20  Less
+   21  JumpIfFalse 45
+   24  Loop
+   25  EnterBlock
+   26  Statement
+   27  GetLocal 1
+   30  GetConstant 1
+   33  Add
+   34  Transfer 0 0
+   39  SetLocal 1
+   42  Jump 14
+   45  Statement
+   46  GetLocal 1
+   49  Transfer 0 0
+   54  DeclareResult 0
+   56  ReturnValue
+   57  Return
+`,
+		function.String(),
+	)
+}
+
+func TestCompileUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, code string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			inter := ParseCheckAndInterpret(t, code)
+
+			program, unsupportedErrors := compiler.Compile(inter.Program)
+			require.Len(t, unsupportedErrors, 1)
+			assert.Equal(t, expected, unsupportedErrors[0].Error())
+
+			assert.Nil(t, program.Function("test"))
+		})
+	}
+
+	test(
+		"for statement",
+		`
+          fun test() {
+              for x in [1] {}
+          }
+        `,
+		"cannot compile function `test`: for statement is not supported yet",
+	)
+
+	test(
+		"for statement with index",
+		`
+          fun test() {
+              for i, c in "abc" {}
+          }
+        `,
+		"cannot compile function `test`: for statement with index is not supported yet",
+	)
+
+	test(
+		"resource parameter",
+		`
+          resource R {}
+
+          fun test(_ r: @R) {
+              destroy r
+          }
+        `,
+		"cannot compile function `test`: resource is not supported yet",
+	)
+}
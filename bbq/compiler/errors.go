@@ -0,0 +1,42 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+)
+
+// UnsupportedError is reported for a function
+// which uses a feature that is not supported by the compiler yet.
+// Such functions are not compiled, and are executed by the interpreter instead.
+type UnsupportedError struct {
+	FunctionName string
+	Feature      string
+	ast.Range
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf(
+		"cannot compile function `%s`: %s is not supported yet",
+		e.FunctionName,
+		e.Feature,
+	)
+}
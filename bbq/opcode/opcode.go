@@ -0,0 +1,199 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opcode
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Opcode
+
+// Opcode is the operation code of an instruction.
+//
+// Instructions consist of an opcode, followed by its operands, if any.
+// Unless noted otherwise, operands are 16-bit unsigned integers, encoded in big-endian order.
+type Opcode byte
+
+const (
+	Unknown Opcode = iota
+
+	// Control flow
+
+	// Return returns Void from the current function.
+	Return
+	// ReturnValue returns the value on top of the stack from the current function.
+	ReturnValue
+	// Jump jumps to the given offset.
+	Jump
+	// JumpIfFalse pops a boolean, and jumps to the given offset if it is false.
+	JumpIfFalse
+	// JumpIfTrue pops a boolean, and jumps to the given offset if it is true.
+	JumpIfTrue
+	// Panic pops a string, and aborts the execution with it as the message.
+	Panic
+
+	// Iteration
+
+	// Iterator pops an iterable value, and starts an iteration over it.
+	Iterator
+	// IteratorNext pushes the next element of the innermost iteration,
+	// or jumps to the given offset if there are no more elements.
+	IteratorNext
+	// IteratorEnd ends the innermost iteration.
+	IteratorEnd
+
+	// Metering
+
+	// Statement reports the execution of a statement.
+	Statement
+	// Loop reports a loop iteration.
+	Loop
+	// EnterBlock reports the entry into a new block scope.
+	EnterBlock
+	// DeclareResult reports the declaration of the implicit `result` constant.
+	// The operand is a single byte, which is 1 if it is the first declaration in its scope.
+	DeclareResult
+	// Condition reports the evaluation of a test condition of a function.
+	Condition
+
+	// Values
+
+	// True pushes the boolean `true`.
+	True
+	// False pushes the boolean `false`.
+	False
+	// Nil pushes `nil`.
+	Nil
+	// Void pushes the void value.
+	Void
+	// GetConstant pushes the value of the constant with the given index.
+	GetConstant
+	// NewArray pops the given number of elements, and pushes an array of the type with the given index.
+	// The operands are the type index and the element count.
+	NewArray
+	// Template pops the given number of values, and pushes the string which concatenates them,
+	// like a string template.
+	Template
+
+	// Variables
+
+	// GetLocal pushes the value of the local with the given index.
+	GetLocal
+	// SetLocal pops a value and assigns it to the local with the given index.
+	SetLocal
+	// DeclareLocal pops a value and declares the local with the given index.
+	// The second operand is a single byte, which is 1 if it is the first declaration in its scope.
+	DeclareLocal
+	// GetGlobal pushes the value of the global with the given name index.
+	GetGlobal
+	// SetGlobal pops a value and assigns it to the global with the given name index.
+	SetGlobal
+
+	// Members and indexing
+
+	// GetField pops a value and pushes the value of its member.
+	// The operands are the name index of the member, and the type index of the accessed type.
+	GetField
+	// GetIndex pops an indexing value and an indexed value, and pushes the element.
+	GetIndex
+	// SetIndex pops a value, an indexing value, and an indexed value, and sets the element.
+	SetIndex
+
+	// Invocations
+
+	// InvokeGlobal pops the arguments, invokes the global function with the given name index,
+	// and pushes the result.
+	// The second operand is the index of the invocation types.
+	InvokeGlobal
+	// Invoke pops the arguments and the function, invokes the function, and pushes the result.
+	// The operand is the index of the invocation types.
+	Invoke
+
+	// Transfers
+
+	// Transfer pops a value, transfers and converts it, and pushes the result.
+	// The operands are the type index of the value's type, and the type index of the target type.
+	Transfer
+
+	// Stack
+
+	// Pop pops a value.
+	Pop
+	// Dup pushes the value on top of the stack again.
+	Dup
+
+	// Operators
+
+	Add
+	Subtract
+	Multiply
+	Divide
+	Mod
+	Negate
+	Not
+	BitwiseOr
+	BitwiseXor
+	BitwiseAnd
+	BitwiseLeftShift
+	BitwiseRightShift
+	Less
+	LessOrEqual
+	Greater
+	GreaterOrEqual
+	Equal
+	NotEqual
+)
+
+// OperandSizes returns the sizes of the operands of the given opcode, in bytes.
+func (o Opcode) OperandSizes() []int {
+	switch o {
+	case Jump,
+		JumpIfFalse,
+		JumpIfTrue,
+		IteratorNext,
+		GetConstant,
+		Template,
+		GetLocal,
+		SetLocal,
+		GetGlobal,
+		SetGlobal,
+		Invoke:
+		return []int{2}
+
+	case DeclareResult:
+		return []int{1}
+
+	case DeclareLocal:
+		return []int{2, 1}
+
+	case NewArray,
+		GetField,
+		InvokeGlobal,
+		Transfer:
+		return []int{2, 2}
+
+	default:
+		return nil
+	}
+}
+
+// Size returns the size of an instruction with the given opcode, in bytes.
+func (o Opcode) Size() int {
+	size := 1
+	for _, operandSize := range o.OperandSizes() {
+		size += operandSize
+	}
+	return size
+}
@@ -0,0 +1,143 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bbq
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/bbq/opcode"
+	"github.com/onflow/cadence/sema"
+)
+
+// Program is a compiled program.
+//
+// Instructions refer to constants, types, names, and invocation types by their index in the program.
+type Program struct {
+	Functions   []*Function
+	Constants   []Constant
+	Types       []sema.Type
+	Names       []string
+	Invocations []InvocationTypes
+}
+
+// Function returns the compiled function with the given name, if any.
+func (p *Program) Function(name string) *Function {
+	for _, function := range p.Functions {
+		if function.Name == name {
+			return function
+		}
+	}
+	return nil
+}
+
+// Function is a compiled global function.
+type Function struct {
+	Name string
+	Type *sema.FunctionType
+	Code []byte
+	// Positions maps instruction offsets to source ranges, ordered by offset
+	Positions      []Position
+	ParameterCount int
+	LocalCount     int
+}
+
+// Position is the source range of the instructions starting at Offset.
+type Position struct {
+	Offset int
+	Range  ast.Range
+}
+
+// Position returns the source range of the instruction at the given offset.
+func (f *Function) Position(offset int) ast.HasPosition {
+	index := sort.Search(len(f.Positions), func(i int) bool {
+		return f.Positions[i].Offset > offset
+	})
+	if index == 0 {
+		return ast.EmptyRange
+	}
+	return &f.Positions[index-1].Range
+}
+
+// String returns a human-readable listing of the function's instructions.
+func (f *Function) String() string {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "fun %s:\n", f.Name)
+
+	code := f.Code
+	offset := 0
+	for offset < len(code) {
+		op := opcode.Opcode(code[offset])
+		_, _ = fmt.Fprintf(&builder, "%5d  %s", offset, op)
+
+		operandOffset := offset + 1
+		for _, size := range op.OperandSizes() {
+			var operand int
+			switch size {
+			case 1:
+				operand = int(code[operandOffset])
+			case 2:
+				operand = int(code[operandOffset])<<8 | int(code[operandOffset+1])
+			}
+			_, _ = fmt.Fprintf(&builder, " %d", operand)
+			operandOffset += size
+		}
+
+		builder.WriteByte('\n')
+		offset += op.Size()
+	}
+
+	return builder.String()
+}
+
+// ConstantKind is the kind of constant.
+type ConstantKind uint8
+
+const (
+	ConstantKindUnknown ConstantKind = iota
+	ConstantKindInteger
+	ConstantKindAddress
+	ConstantKindFix64
+	ConstantKindUFix64
+	ConstantKindString
+	ConstantKindCharacter
+)
+
+// Constant is a literal value.
+//
+// Constants are stored in their decoded form, and a new value is created each time
+// a constant is loaded, so that the creation is metered like the evaluation of the literal.
+type Constant struct {
+	Kind ConstantKind
+	// Type is the integer type of an integer constant
+	Type    sema.Type
+	Integer *big.Int
+	String  string
+}
+
+// InvocationTypes are the static types of an invocation.
+type InvocationTypes struct {
+	TypeArguments  *sema.TypeParameterTypeOrderedMap
+	ArgumentTypes  []sema.Type
+	ParameterTypes []sema.Type
+	ReturnType     sema.Type
+}
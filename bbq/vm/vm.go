@@ -0,0 +1,771 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"strings"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/bbq"
+	"github.com/onflow/cadence/bbq/opcode"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
+)
+
+// VM executes compiled programs.
+//
+// The VM operates on the values of the interpreter,
+// and uses the interpreter of the program as its context,
+// e.g. for storage, metering, and the configured handlers.
+// Globals which are not compiled, like composite declarations and imports,
+// are declared by the interpreter, and functions which are not compiled are invoked
+// through the interpreter.
+//
+// The VM meters computation and memory like the interpreter.
+// It does not support debuggers, coverage reports, and tracing.
+//
+// Unlike in the interpreter, a failed function condition aborts the execution
+// like an invocation of the `panic` function.
+type VM struct {
+	interpreter *interpreter.Interpreter
+	program     *bbq.Program
+	// functions are the compiled functions, by name index
+	functions []*bbq.Function
+	// globals are the resolved global variables, by name index
+	globals []interpreter.Variable
+	stack   []interpreter.Value
+}
+
+var variableMemoryUsage = common.NewConstantMemoryUsage(common.MemoryKindVariable)
+
+// NewVM returns a new VM for the given program.
+// The given interpreter must have interpreted the program the compiled program was compiled from.
+func NewVM(program *bbq.Program, inter *interpreter.Interpreter) *VM {
+	functions := make([]*bbq.Function, len(program.Names))
+	for index, name := range program.Names {
+		functions[index] = program.Function(name)
+	}
+
+	return &VM{
+		interpreter: inter,
+		program:     program,
+		functions:   functions,
+		globals:     make([]interpreter.Variable, len(program.Names)),
+	}
+}
+
+// Invoke invokes the global function with the given name.
+// If the function was not compiled, it is invoked using the interpreter.
+func (vm *VM) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+	inter := vm.interpreter
+
+	function := vm.program.Function(name)
+	if function == nil {
+		return inter.Invoke(name, arguments...)
+	}
+
+	// recover internal panics and return them as an error
+	defer inter.RecoverErrors(func(internalErr error) {
+		vm.stack = vm.stack[:0]
+		err = internalErr
+	})
+
+	preparedArguments, err := interpreter.PrepareExternalInvocationArguments(
+		inter,
+		function.Type,
+		arguments,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.call(function, preparedArguments), nil
+}
+
+// call calls the given compiled function.
+// The arguments must already be transferred.
+func (vm *VM) call(function *bbq.Function, arguments []interpreter.Value) interpreter.Value {
+	inter := vm.interpreter
+
+	// Meter like the interpreter:
+	// The invocation, the function's activation, which declares the parameters,
+	// and the function body's activation
+
+	common.UseMemory(inter, common.InvocationMemoryUsage)
+	common.UseMemory(inter, common.ActivationMemoryUsage)
+
+	locals := make([]interpreter.Value, function.LocalCount)
+
+	if len(arguments) > 0 {
+		common.UseMemory(inter, common.ActivationEntriesMemoryUsage)
+		for i, argument := range arguments {
+			common.UseMemory(inter, variableMemoryUsage)
+			locals[i] = argument
+		}
+	}
+
+	common.UseMemory(inter, common.ActivationMemoryUsage)
+
+	return vm.run(function, locals)
+}
+
+func (vm *VM) push(value interpreter.Value) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VM) pop() interpreter.Value {
+	lastIndex := len(vm.stack) - 1
+	value := vm.stack[lastIndex]
+	vm.stack[lastIndex] = nil
+	vm.stack = vm.stack[:lastIndex]
+	return value
+}
+
+func (vm *VM) peek() interpreter.Value {
+	return vm.stack[len(vm.stack)-1]
+}
+
+func (vm *VM) popN(count int) []interpreter.Value {
+	if count == 0 {
+		return nil
+	}
+
+	start := len(vm.stack) - count
+	values := make([]interpreter.Value, count)
+	copy(values, vm.stack[start:])
+	clear(vm.stack[start:])
+	vm.stack = vm.stack[:start]
+	return values
+}
+
+func (vm *VM) locationRange(function *bbq.Function, offset int) interpreter.LocationRange {
+	return interpreter.LocationRange{
+		Location:    vm.interpreter.Location,
+		HasPosition: function.Position(offset),
+	}
+}
+
+func read16(code []byte, offset int) int {
+	return int(code[offset])<<8 | int(code[offset+1])
+}
+
+func (vm *VM) run(function *bbq.Function, locals []interpreter.Value) interpreter.Value {
+	inter := vm.interpreter
+	program := vm.program
+	code := function.Code
+
+	// iterators are the iterators of the active for-in loops, innermost last
+	var iterators []interpreter.ValueIterator
+
+	ip := 0
+
+	for {
+		offset := ip
+		op := opcode.Opcode(code[ip])
+		ip += op.Size()
+
+		switch op {
+
+		// Control flow
+
+		case opcode.Return:
+			return interpreter.Void
+
+		case opcode.ReturnValue:
+			return vm.pop()
+
+		case opcode.Jump:
+			ip = read16(code, offset+1)
+
+		case opcode.JumpIfFalse:
+			if !vm.popBool() {
+				ip = read16(code, offset+1)
+			}
+
+		case opcode.JumpIfTrue:
+			if vm.popBool() {
+				ip = read16(code, offset+1)
+			}
+
+		case opcode.Panic:
+			message, ok := vm.pop().(*interpreter.StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			panic(stdlib.PanicError{
+				Message:       message.Str,
+				LocationRange: vm.locationRange(function, offset),
+			})
+
+		// Iteration
+
+		case opcode.Iterator:
+			iterable, ok := vm.pop().(interpreter.IterableValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			iterator := iterable.Iterator(inter, vm.locationRange(function, offset))
+			iterators = append(iterators, iterator)
+
+		case opcode.IteratorNext:
+			iterator := iterators[len(iterators)-1]
+			locationRange := vm.locationRange(function, offset)
+			element := iterator.Next(inter, locationRange)
+			if element == nil {
+				ip = read16(code, offset+1)
+				break
+			}
+			// Like the interpreter, transfer each element
+			vm.push(
+				element.Transfer(
+					inter,
+					locationRange,
+					atree.Address{},
+					false,
+					nil,
+					nil,
+					false, // value has a parent container because it is from iterator.
+				),
+			)
+
+		case opcode.IteratorEnd:
+			lastIndex := len(iterators) - 1
+			iterators[lastIndex] = nil
+			iterators = iterators[:lastIndex]
+
+		// Metering
+
+		case opcode.Statement:
+			common.UseComputation(inter, common.StatementComputationUsage)
+
+		case opcode.Loop:
+			inter.ReportLoopIteration(function.Position(offset))
+
+		case opcode.EnterBlock:
+			common.UseMemory(inter, common.ActivationMemoryUsage)
+
+		case opcode.DeclareResult:
+			vm.meterDeclaration(code[offset+1] == 1)
+
+		case opcode.Condition:
+			// The interpreter evaluates each test condition as a new expression statement
+			common.UseMemory(inter, common.ExpressionStatementMemoryUsage)
+			common.UseComputation(inter, common.StatementComputationUsage)
+
+		// Values
+
+		case opcode.True:
+			vm.push(interpreter.TrueValue)
+
+		case opcode.False:
+			vm.push(interpreter.FalseValue)
+
+		case opcode.Nil:
+			vm.push(interpreter.Nil)
+
+		case opcode.Void:
+			vm.push(interpreter.Void)
+
+		case opcode.GetConstant:
+			constant := program.Constants[read16(code, offset+1)]
+			vm.push(vm.constantValue(constant))
+
+		case opcode.NewArray:
+			arrayType, ok := program.Types[read16(code, offset+1)].(sema.ArrayType)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			elements := vm.popN(read16(code, offset+3))
+
+			arrayStaticType := interpreter.ConvertSemaArrayTypeToStaticArrayType(inter, arrayType)
+
+			vm.push(
+				interpreter.NewArrayValue(
+					inter,
+					vm.locationRange(function, offset),
+					arrayStaticType,
+					common.ZeroAddress,
+					elements...,
+				),
+			)
+
+		case opcode.Template:
+			values := vm.popN(read16(code, offset+1))
+			vm.push(templateString(values))
+
+		// Variables
+
+		case opcode.GetLocal:
+			value := locals[read16(code, offset+1)]
+			vm.checkInvalidated(value, function, offset)
+			vm.push(value)
+
+		case opcode.SetLocal:
+			locals[read16(code, offset+1)] = vm.pop()
+
+		case opcode.DeclareLocal:
+			vm.meterDeclaration(code[offset+3] == 1)
+			locals[read16(code, offset+1)] = vm.pop()
+
+		case opcode.GetGlobal:
+			value := vm.global(read16(code, offset+1)).GetValue(inter)
+			vm.checkInvalidated(value, function, offset)
+			vm.push(value)
+
+		case opcode.SetGlobal:
+			vm.global(read16(code, offset+1)).SetValue(
+				inter,
+				vm.locationRange(function, offset),
+				vm.pop(),
+			)
+
+		// Members and indexing
+
+		case opcode.GetField:
+			vm.getField(function, offset)
+
+		case opcode.GetIndex:
+			indexingValue := vm.pop()
+			target, ok := vm.pop().(interpreter.ValueIndexableValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			locationRange := vm.locationRange(function, offset)
+			interpreter.CheckInvalidatedResourceOrResourceReference(target, locationRange, inter)
+			value := target.GetKey(inter, locationRange, indexingValue)
+			vm.checkInvalidated(value, function, offset)
+			vm.push(value)
+
+		case opcode.SetIndex:
+			value := vm.pop()
+			indexingValue := vm.pop()
+			target, ok := vm.pop().(interpreter.ValueIndexableValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			locationRange := vm.locationRange(function, offset)
+			interpreter.CheckInvalidatedResourceOrResourceReference(target, locationRange, inter)
+			target.SetKey(inter, locationRange, indexingValue, value)
+
+		// Invocations
+
+		case opcode.InvokeGlobal:
+			nameIndex := read16(code, offset+1)
+			invocationTypes := program.Invocations[read16(code, offset+3)]
+			arguments := vm.popN(len(invocationTypes.ArgumentTypes))
+			locationRange := vm.locationRange(function, offset)
+
+			var result interpreter.Value
+			if compiledFunction := vm.functions[nameIndex]; compiledFunction != nil {
+				result = vm.invokeCompiledFunction(compiledFunction, arguments, invocationTypes, locationRange)
+			} else {
+				functionValue, ok := vm.global(nameIndex).GetValue(inter).(interpreter.FunctionValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+				result = vm.invokeFunctionValue(functionValue, arguments, invocationTypes, locationRange)
+			}
+			vm.checkInvalidated(result, function, offset)
+			vm.push(result)
+
+		case opcode.Invoke:
+			invocationTypes := program.Invocations[read16(code, offset+1)]
+			arguments := vm.popN(len(invocationTypes.ArgumentTypes))
+			functionValue, ok := vm.pop().(interpreter.FunctionValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			locationRange := vm.locationRange(function, offset)
+
+			result := vm.invokeFunctionValue(functionValue, arguments, invocationTypes, locationRange)
+			vm.checkInvalidated(result, function, offset)
+			vm.push(result)
+
+		// Transfers
+
+		case opcode.Transfer:
+			valueType := program.Types[read16(code, offset+1)]
+			targetType := program.Types[read16(code, offset+3)]
+			value := vm.pop()
+			vm.push(
+				interpreter.TransferAndConvert(
+					inter,
+					value,
+					valueType,
+					targetType,
+					vm.locationRange(function, offset),
+				),
+			)
+
+		// Stack
+
+		case opcode.Pop:
+			_ = vm.pop()
+
+		case opcode.Dup:
+			vm.push(vm.peek())
+
+		// Operators
+
+		case opcode.Not:
+			value, ok := vm.pop().(interpreter.BoolValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			vm.push(value.Negate(inter))
+
+		case opcode.Negate:
+			value, ok := vm.pop().(interpreter.NumberValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+			vm.push(value.Negate(inter, vm.locationRange(function, offset)))
+
+		case opcode.Equal,
+			opcode.NotEqual:
+
+			right := vm.pop()
+			left := vm.pop()
+			result := interpreter.TestValueEqual(
+				inter,
+				vm.locationRange(function, offset),
+				left,
+				right,
+			)
+			if op == opcode.NotEqual {
+				result = !result
+			}
+			vm.push(result)
+
+		default:
+			vm.binaryOperation(op, function, offset)
+		}
+	}
+}
+
+// templateString returns the string which concatenates the given values, like a string template
+func templateString(values []interpreter.Value) *interpreter.StringValue {
+	var builder strings.Builder
+	for _, value := range values {
+		switch value := value.(type) {
+		case *interpreter.StringValue:
+			builder.WriteString(value.Str)
+		case interpreter.CharacterValue:
+			builder.WriteString(value.Str)
+		default:
+			builder.WriteString(value.String())
+		}
+	}
+
+	return interpreter.NewUnmeteredStringValue(builder.String())
+}
+
+func (vm *VM) popBool() bool {
+	value, ok := vm.pop().(interpreter.BoolValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return bool(value)
+}
+
+// meterDeclaration meters the declaration of a variable, like the interpreter.
+// The first declaration in a scope also initializes the scope's entries.
+func (vm *VM) meterDeclaration(first bool) {
+	if first {
+		common.UseMemory(vm.interpreter, common.ActivationEntriesMemoryUsage)
+	}
+	common.UseMemory(vm.interpreter, variableMemoryUsage)
+}
+
+func (vm *VM) checkInvalidated(value interpreter.Value, function *bbq.Function, offset int) {
+	switch value.(type) {
+	case interpreter.ResourceKindedValue,
+		*interpreter.EphemeralReferenceValue,
+		*interpreter.SomeValue:
+
+		interpreter.CheckInvalidatedResourceOrResourceReference(
+			value,
+			vm.locationRange(function, offset),
+			vm.interpreter,
+		)
+	}
+}
+
+func (vm *VM) global(nameIndex int) interpreter.Variable {
+	variable := vm.globals[nameIndex]
+	if variable == nil {
+		variable = vm.interpreter.FindVariable(vm.program.Names[nameIndex])
+		if variable == nil {
+			panic(errors.NewUnreachableError())
+		}
+		vm.globals[nameIndex] = variable
+	}
+	return variable
+}
+
+func (vm *VM) constantValue(constant bbq.Constant) interpreter.Value {
+	inter := vm.interpreter
+
+	switch constant.Kind {
+	case bbq.ConstantKindInteger:
+		// The ranges are checked at the checker level.
+		// Hence, it is safe to create the value without validation.
+		return inter.NewIntegerValueFromBigInt(constant.Integer, constant.Type)
+
+	case bbq.ConstantKindAddress:
+		return interpreter.NewAddressValueFromBytes(inter, constant.Integer.Bytes)
+
+	case bbq.ConstantKindFix64:
+		return interpreter.NewFix64Value(inter, constant.Integer.Int64)
+
+	case bbq.ConstantKindUFix64:
+		return interpreter.NewUFix64Value(inter, constant.Integer.Uint64)
+
+	case bbq.ConstantKindString:
+		// Optimization: If the string is empty, return the empty string singleton
+		// to avoid allocating a new string value.
+		if len(constant.String) == 0 {
+			return interpreter.EmptyString
+		}
+
+		// NOTE: already metered in lexer/parser
+		return interpreter.NewUnmeteredStringValue(constant.String)
+
+	case bbq.ConstantKindCharacter:
+		return interpreter.NewUnmeteredCharacterValue(constant.String)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+func (vm *VM) getField(function *bbq.Function, offset int) {
+	inter := vm.interpreter
+	code := function.Code
+
+	name := vm.program.Names[read16(code, offset+1)]
+	accessedType := vm.program.Types[read16(code, offset+3)]
+
+	target := vm.pop()
+
+	locationRange := vm.locationRange(function, offset)
+
+	interpreter.CheckInvalidatedResourceOrResourceReference(target, locationRange, inter)
+	inter.CheckMemberAccessTarget(target, accessedType, locationRange)
+
+	value := interpreter.GetMember(inter, target, locationRange, name)
+	if value == nil {
+		panic(&interpreter.UseBeforeInitializationError{
+			Name:          name,
+			LocationRange: locationRange,
+		})
+	}
+
+	vm.checkInvalidated(value, function, offset)
+	vm.push(value)
+}
+
+func (vm *VM) invokeCompiledFunction(
+	function *bbq.Function,
+	arguments []interpreter.Value,
+	invocationTypes bbq.InvocationTypes,
+	locationRange interpreter.LocationRange,
+) interpreter.Value {
+	inter := vm.interpreter
+
+	inter.ReportFunctionInvocation()
+
+	for i, argument := range arguments {
+		arguments[i] = interpreter.TransferAndConvert(
+			inter,
+			argument,
+			invocationTypes.ArgumentTypes[i],
+			invocationTypes.ParameterTypes[i],
+			locationRange,
+		)
+	}
+
+	result := vm.call(function, arguments)
+
+	result = interpreter.ConvertAndBox(
+		inter,
+		locationRange,
+		result,
+		function.Type.ReturnTypeAnnotation.Type,
+		invocationTypes.ReturnType,
+	)
+
+	inter.ReportInvokedFunctionReturn()
+
+	return result
+}
+
+func (vm *VM) invokeFunctionValue(
+	function interpreter.FunctionValue,
+	arguments []interpreter.Value,
+	invocationTypes bbq.InvocationTypes,
+	locationRange interpreter.LocationRange,
+) interpreter.Value {
+	inter := vm.interpreter
+
+	inter.ReportFunctionInvocation()
+
+	result, err := interpreter.InvokeFunctionValueWithTypeArguments(
+		inter,
+		function,
+		arguments,
+		invocationTypes.ArgumentTypes,
+		invocationTypes.ParameterTypes,
+		invocationTypes.ReturnType,
+		invocationTypes.TypeArguments,
+		locationRange.HasPosition,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	inter.ReportInvokedFunctionReturn()
+
+	return result
+}
+
+var binaryOperations = map[opcode.Opcode]ast.Operation{
+	opcode.Add:               ast.OperationPlus,
+	opcode.Subtract:          ast.OperationMinus,
+	opcode.Multiply:          ast.OperationMul,
+	opcode.Divide:            ast.OperationDiv,
+	opcode.Mod:               ast.OperationMod,
+	opcode.BitwiseOr:         ast.OperationBitwiseOr,
+	opcode.BitwiseXor:        ast.OperationBitwiseXor,
+	opcode.BitwiseAnd:        ast.OperationBitwiseAnd,
+	opcode.BitwiseLeftShift:  ast.OperationBitwiseLeftShift,
+	opcode.BitwiseRightShift: ast.OperationBitwiseRightShift,
+	opcode.Less:              ast.OperationLess,
+	opcode.LessOrEqual:       ast.OperationLessEqual,
+	opcode.Greater:           ast.OperationGreater,
+	opcode.GreaterOrEqual:    ast.OperationGreaterEqual,
+}
+
+func (vm *VM) binaryOperation(op opcode.Opcode, function *bbq.Function, offset int) {
+	inter := vm.interpreter
+
+	right := vm.pop()
+	left := vm.pop()
+
+	locationRange := vm.locationRange(function, offset)
+
+	invalidOperands := func() {
+		operation, ok := binaryOperations[op]
+		if !ok {
+			panic(errors.NewUnexpectedError("invalid opcode: %s", op))
+		}
+		panic(&interpreter.InvalidOperandsError{
+			Operation:     operation,
+			LeftType:      left.StaticType(inter),
+			RightType:     right.StaticType(inter),
+			LocationRange: locationRange,
+		})
+	}
+
+	var result interpreter.Value
+
+	switch op {
+	case opcode.Add,
+		opcode.Subtract,
+		opcode.Multiply,
+		opcode.Divide,
+		opcode.Mod:
+
+		leftNumber, leftOk := left.(interpreter.NumberValue)
+		rightNumber, rightOk := right.(interpreter.NumberValue)
+		if !leftOk || !rightOk {
+			invalidOperands()
+		}
+
+		switch op {
+		case opcode.Add:
+			result = leftNumber.Plus(inter, rightNumber, locationRange)
+		case opcode.Subtract:
+			result = leftNumber.Minus(inter, rightNumber, locationRange)
+		case opcode.Multiply:
+			result = leftNumber.Mul(inter, rightNumber, locationRange)
+		case opcode.Divide:
+			result = leftNumber.Div(inter, rightNumber, locationRange)
+		case opcode.Mod:
+			result = leftNumber.Mod(inter, rightNumber, locationRange)
+		}
+
+	case opcode.BitwiseOr,
+		opcode.BitwiseXor,
+		opcode.BitwiseAnd,
+		opcode.BitwiseLeftShift,
+		opcode.BitwiseRightShift:
+
+		leftInteger, leftOk := left.(interpreter.IntegerValue)
+		rightInteger, rightOk := right.(interpreter.IntegerValue)
+		if !leftOk || !rightOk {
+			invalidOperands()
+		}
+
+		switch op {
+		case opcode.BitwiseOr:
+			result = leftInteger.BitwiseOr(inter, rightInteger, locationRange)
+		case opcode.BitwiseXor:
+			result = leftInteger.BitwiseXor(inter, rightInteger, locationRange)
+		case opcode.BitwiseAnd:
+			result = leftInteger.BitwiseAnd(inter, rightInteger, locationRange)
+		case opcode.BitwiseLeftShift:
+			result = leftInteger.BitwiseLeftShift(inter, rightInteger, locationRange)
+		case opcode.BitwiseRightShift:
+			result = leftInteger.BitwiseRightShift(inter, rightInteger, locationRange)
+		}
+
+	case opcode.Less,
+		opcode.LessOrEqual,
+		opcode.Greater,
+		opcode.GreaterOrEqual:
+
+		leftComparable, leftOk := left.(interpreter.ComparableValue)
+		rightComparable, rightOk := right.(interpreter.ComparableValue)
+		if !leftOk || !rightOk {
+			invalidOperands()
+		}
+
+		switch op {
+		case opcode.Less:
+			result = leftComparable.Less(inter, rightComparable, locationRange)
+		case opcode.LessOrEqual:
+			result = leftComparable.LessEqual(inter, rightComparable, locationRange)
+		case opcode.Greater:
+			result = leftComparable.Greater(inter, rightComparable, locationRange)
+		case opcode.GreaterOrEqual:
+			result = leftComparable.GreaterEqual(inter, rightComparable, locationRange)
+		}
+
+	default:
+		panic(errors.NewUnexpectedError("invalid opcode: %s", op))
+	}
+
+	vm.push(result)
+}
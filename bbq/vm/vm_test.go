@@ -0,0 +1,540 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/activations"
+	"github.com/onflow/cadence/bbq"
+	"github.com/onflow/cadence/bbq/compiler"
+	"github.com/onflow/cadence/bbq/vm"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
+	. "github.com/onflow/cadence/test_utils"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+)
+
+type testGauge struct {
+	memory      map[common.MemoryKind]uint64
+	computation map[common.ComputationKind]uint64
+}
+
+var _ common.MemoryGauge = &testGauge{}
+var _ common.ComputationGauge = &testGauge{}
+
+func newTestGauge() *testGauge {
+	return &testGauge{
+		memory:      map[common.MemoryKind]uint64{},
+		computation: map[common.ComputationKind]uint64{},
+	}
+}
+
+func (g *testGauge) MeterMemory(usage common.MemoryUsage) error {
+	g.memory[usage.Kind] += usage.Amount
+	return nil
+}
+
+func (g *testGauge) MeterComputation(usage common.ComputationUsage) error {
+	g.computation[usage.Kind] += usage.Intensity
+	return nil
+}
+
+type testLogger struct {
+	logs []string
+}
+
+var _ stdlib.Logger = &testLogger{}
+
+func (l *testLogger) ProgramLog(message string, _ interpreter.LocationRange) error {
+	l.logs = append(l.logs, message)
+	return nil
+}
+
+type execution struct {
+	result interpreter.Value
+	err    error
+	gauge  *testGauge
+	logs   []string
+	inter  *interpreter.Interpreter
+	// program is the compiled program, if the VM was used
+	program           *bbq.Program
+	unsupportedErrors []*compiler.UnsupportedError
+}
+
+// execute parses, checks, and interprets the given program,
+// and then invokes the given function, either using the interpreter, or using the VM
+func execute(
+	t *testing.T,
+	code string,
+	functionName string,
+	useVM bool,
+	arguments ...interpreter.Value,
+) execution {
+
+	gauge := newTestGauge()
+	logger := &testLogger{}
+
+	valueDeclarations := []stdlib.StandardLibraryValue{
+		stdlib.AssertFunction,
+		stdlib.PanicFunction,
+		stdlib.NewLogFunction(logger),
+		stdlib.InclusiveRangeConstructorFunction,
+	}
+
+	baseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
+	baseActivation := activations.NewActivation(nil, interpreter.BaseActivation)
+	for _, valueDeclaration := range valueDeclarations {
+		baseValueActivation.DeclareValue(valueDeclaration)
+		interpreter.Declare(baseActivation, valueDeclaration)
+	}
+
+	inter, err := ParseCheckAndInterpretWithOptionsAndMemoryMetering(
+		t,
+		code,
+		ParseCheckAndInterpretOptions{
+			Config: &interpreter.Config{
+				ComputationGauge: gauge,
+				BaseActivationHandler: func(_ common.Location) *interpreter.VariableActivation {
+					return baseActivation
+				},
+			},
+			CheckerConfig: &sema.Config{
+				BaseValueActivationHandler: func(_ common.Location) *sema.VariableActivation {
+					return baseValueActivation
+				},
+			},
+		},
+		gauge,
+	)
+	require.NoError(t, err)
+
+	var result interpreter.Value
+	var program *bbq.Program
+	var unsupportedErrors []*compiler.UnsupportedError
+	if useVM {
+		program, unsupportedErrors = compiler.Compile(inter.Program)
+		result, err = vm.NewVM(program, inter).Invoke(functionName, arguments...)
+	} else {
+		result, err = inter.Invoke(functionName, arguments...)
+	}
+
+	return execution{
+		result:            result,
+		err:               err,
+		gauge:             gauge,
+		logs:              logger.logs,
+		inter:             inter,
+		program:           program,
+		unsupportedErrors: unsupportedErrors,
+	}
+}
+
+// assertSameExecution executes the given function using both the interpreter and the VM,
+// and asserts that the results, the logs, and the metered computation and memory are the same
+func assertSameExecution(
+	t *testing.T,
+	code string,
+	functionName string,
+	arguments ...interpreter.Value,
+) execution {
+
+	interpreterExecution := execute(t, code, functionName, false, arguments...)
+	vmExecution := execute(t, code, functionName, true, arguments...)
+
+	if interpreterExecution.err != nil {
+		require.Error(t, vmExecution.err)
+		assert.Equal(t,
+			interpreterExecution.err.Error(),
+			vmExecution.err.Error(),
+		)
+	} else {
+		require.NoError(t, vmExecution.err)
+		AssertValuesEqual(
+			t,
+			interpreterExecution.inter,
+			interpreterExecution.result,
+			vmExecution.result,
+		)
+	}
+
+	assert.Equal(t, interpreterExecution.logs, vmExecution.logs)
+	assert.Equal(t, interpreterExecution.gauge.computation, vmExecution.gauge.computation)
+	assert.Equal(t, interpreterExecution.gauge.memory, vmExecution.gauge.memory)
+
+	return vmExecution
+}
+
+// benchmarkInvocations overrides the invoked function of benchmarks
+// which take too long to run with metering when invoking their main function
+var benchmarkInvocations = map[string]struct {
+	functionName string
+	arguments    []interpreter.Value
+}{
+	"binarytrees.cdc": {
+		functionName: "run",
+		arguments: []interpreter.Value{
+			interpreter.NewUnmeteredIntValueFromInt64(6),
+		},
+	},
+}
+
+func TestVMBenchmarks(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("..", "..", "benchmarks", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			functionName := "main"
+			var arguments []interpreter.Value
+			if invocation, ok := benchmarkInvocations[name]; ok {
+				functionName = invocation.functionName
+				arguments = invocation.arguments
+			}
+
+			execution := assertSameExecution(t, string(code), functionName, arguments...)
+
+			// The benchmarked functions must actually be executed by the VM,
+			// not by the interpreter
+
+			require.Empty(t, execution.unsupportedErrors)
+			require.NotNil(t, execution.program.Function(functionName))
+		})
+	}
+}
+
+func TestVMExecution(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, code string, arguments ...interpreter.Value) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			execution := assertSameExecution(t, code, "test", arguments...)
+			require.Empty(t, execution.unsupportedErrors)
+		})
+	}
+
+	test(
+		"recursion",
+		`
+          fun fib(_ n: Int): Int {
+              if n < 2 {
+                  return n
+              }
+              return fib(n - 1) + fib(n - 2)
+          }
+
+          fun test(): Int {
+              return fib(14)
+          }
+        `,
+	)
+
+	test(
+		"loop with break and continue",
+		`
+          fun test(): [Int] {
+              let evens: [Int] = []
+              var i = 0
+              while true {
+                  i = i + 1
+                  if i > 10 {
+                      break
+                  } else if i % 2 == 1 {
+                      continue
+                  }
+                  evens.append(i)
+              }
+              return evens
+          }
+        `,
+	)
+
+	test(
+		"shadowing",
+		`
+          fun test(): Int {
+              let x = 1
+              if true {
+                  let x = x + 1
+                  return x
+              }
+              return x
+          }
+        `,
+	)
+
+	test(
+		"short-circuiting",
+		`
+          fun fail(): Bool {
+              panic("evaluated")
+          }
+
+          fun test(): Bool {
+              return (false && fail()) || (true || fail())
+          }
+        `,
+	)
+
+	test(
+		"conversion",
+		`
+          fun optional(_ x: Int?): Int? {
+              return x
+          }
+
+          fun test(): [Int?] {
+              let x: Int? = 1
+              return [optional(x), optional(2), nil]
+          }
+        `,
+	)
+
+	test(
+		"literals and operators",
+		`
+          fun test(): [AnyStruct] {
+              let c: Character = "x"
+              return [
+                  -Int8(3) == -3 ? "a" : "b",
+                  !true,
+                  1.5 + 2.25,
+                  0x1,
+                  0b1010 & 0b0110 | 1 << 4,
+                  "abc".length,
+                  c
+              ]
+          }
+        `,
+	)
+
+	test(
+		"index assignment",
+		`
+          fun test(): [Int] {
+              let xs = [1, 2]
+              xs[1] = 3
+              xs[0] = xs[0] + xs[1]
+              return xs
+          }
+        `,
+	)
+
+	test(
+		"overflow",
+		`
+          fun test(): UInt8 {
+              var x: UInt8 = 250
+              while true {
+                  x = x + 1
+              }
+              return x
+          }
+        `,
+	)
+
+	test(
+		"for-in loops",
+		`
+          fun first(_ s: String, _ c: Character): Int {
+              var i = 0
+              for d in s {
+                  if d == c {
+                      return i
+                  }
+                  i = i + 1
+              }
+              return -1
+          }
+
+          fun test(): [Int] {
+              var sum = 0
+              for x in InclusiveRange(1, 20, step: 3) {
+                  if x == 4 {
+                      continue
+                  }
+                  for y in InclusiveRange(x, 0, step: -1) {
+                      if y < x - 2 {
+                          break
+                      }
+                      sum = sum + y
+                  }
+              }
+              return [sum, first("hello", "l"), first("hello", "x")]
+          }
+        `,
+	)
+
+	test(
+		"string template",
+		`
+          fun test(): String {
+              let c: Character = "c"
+              let s = "s"
+              return "\(1), \(c), \(s), \(true), \(0x1), \(1.5)"
+          }
+        `,
+	)
+
+	test(
+		"global variable",
+		`
+          var count = 0
+
+          fun increment() {
+              count = count + 1
+          }
+
+          fun test(): Int {
+              increment()
+              increment()
+              return count
+          }
+        `,
+	)
+
+	test(
+		"conditions",
+		`
+          fun abs(_ x: Int): Int {
+              pre {
+                  x != 0: "zero"
+              }
+              post {
+                  result > 0
+                  result == before(x) || result == -before(x)
+              }
+              if x < 0 {
+                  return -x
+              }
+              return x
+          }
+
+          fun test(): Int {
+              return abs(-2) + abs(3)
+          }
+        `,
+	)
+
+	test(
+		"arguments",
+		`
+          fun test(_ a: Int, _ b: String): String {
+              return b.concat(a.toString())
+          }
+        `,
+		interpreter.NewUnmeteredIntValueFromInt64(42),
+		interpreter.NewUnmeteredStringValue("answer: "),
+	)
+}
+
+func TestVMConditions(t *testing.T) {
+
+	t.Parallel()
+
+	code := `
+      fun check(_ x: Int) {
+          pre {
+              x > 0: "x must be positive"
+          }
+          post {
+              x < 10
+          }
+      }
+    `
+
+	test := func(argument int64, expected string) {
+		execution := execute(
+			t,
+			code,
+			"check",
+			true,
+			interpreter.NewUnmeteredIntValueFromInt64(argument),
+		)
+
+		var panicErr stdlib.PanicError
+		require.ErrorAs(t, execution.err, &panicErr)
+		assert.Equal(t, expected, panicErr.Message)
+	}
+
+	test(0, "x must be positive")
+	test(10, "pre/post condition failed")
+}
+
+func TestVMFallback(t *testing.T) {
+
+	t.Parallel()
+
+	code := `
+      fun double(_ xs: [Int]): [Int] {
+          let result: [Int] = []
+          for x in xs {
+              result.append(x * 2)
+          }
+          return result
+      }
+
+      fun test(): Int {
+          let xs = double([1, 2, 3])
+          return xs[0] + xs[1] + xs[2]
+      }
+    `
+
+	inter := ParseCheckAndInterpret(t, code)
+	program, unsupportedErrors := compiler.Compile(inter.Program)
+
+	require.Len(t, unsupportedErrors, 1)
+	assert.Equal(t,
+		"cannot compile function `double`: for statement is not supported yet",
+		unsupportedErrors[0].Error(),
+	)
+
+	assert.Nil(t, program.Function("double"))
+	assert.NotNil(t, program.Function("test"))
+
+	execution := assertSameExecution(t, code, "test")
+	AssertValuesEqual(
+		t,
+		execution.inter,
+		interpreter.NewUnmeteredIntValueFromInt64(12),
+		execution.result,
+	)
+}
@@ -0,0 +1,563 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+)
+
+type ProblemKind string
+
+const (
+	// ProblemKindUndecodableSlab is reported for slab registers which cannot be decoded
+	ProblemKindUndecodableSlab ProblemKind = "undecodable-slab"
+	// ProblemKindDanglingSlabReference is reported for references to slabs which do not exist
+	ProblemKindDanglingSlabReference ProblemKind = "dangling-slab-reference"
+	// ProblemKindMultiplyReferencedSlab is reported for slabs which are referenced more than once
+	ProblemKindMultiplyReferencedSlab ProblemKind = "multiply-referenced-slab"
+	// ProblemKindUnreferencedRootSlab is reported for root slabs which are not referenced by account storage
+	ProblemKindUnreferencedRootSlab ProblemKind = "unreferenced-root-slab"
+	// ProblemKindInvalidStorable is reported for stored values which cannot be decoded or converted
+	ProblemKindInvalidStorable ProblemKind = "invalid-storable"
+	// ProblemKindInvalidValue is reported for stored values which cannot be traversed, e.g. due to missing slabs
+	ProblemKindInvalidValue ProblemKind = "invalid-value"
+	// ProblemKindNonConformingValue is reported for stored values which do not conform to their static type
+	ProblemKindNonConformingValue ProblemKind = "non-conforming-value"
+)
+
+type Problem struct {
+	Kind    ProblemKind `json:"kind"`
+	Address string      `json:"address"`
+	SlabID  string      `json:"slabID,omitempty"`
+	Domain  string      `json:"domain,omitempty"`
+	Key     string      `json:"key,omitempty"`
+	Message string      `json:"message"`
+}
+
+type RepairKind string
+
+const (
+	// RepairKindRemoveSlabs removes the registers of an unreferenced slab tree
+	RepairKindRemoveSlabs RepairKind = "remove-slabs"
+	// RepairKindRemoveValue removes a broken value from a storage map
+	RepairKindRemoveValue RepairKind = "remove-value"
+)
+
+type Repair struct {
+	Kind    RepairKind `json:"kind"`
+	Address string     `json:"address"`
+	SlabIDs []string   `json:"slabIDs,omitempty"`
+	Domain  string     `json:"domain,omitempty"`
+	Key     string     `json:"key,omitempty"`
+	// Automatic is true if the repair can be safely applied by the tool,
+	// i.e. only removes registers which are unreachable from account storage
+	Automatic bool `json:"automatic"`
+
+	slabIDs []atree.SlabID
+}
+
+type Report struct {
+	Accounts int `json:"accounts"`
+	Slabs    int `json:"slabs"`
+	Values   int `json:"values"`
+	// SkippedValues is the number of values which could not be checked for conformance,
+	// because their types are defined in contracts which are not available
+	SkippedValues int       `json:"skippedValues"`
+	Problems      []Problem `json:"problems"`
+	Repairs       []Repair  `json:"repairs"`
+}
+
+type Config struct {
+	// CheckValues enables the loading of all stored values
+	// and the check that they conform to their static types
+	CheckValues bool
+}
+
+type Checker struct {
+	ledger *payloads.Ledger
+	config Config
+	report *Report
+
+	slabs map[atree.SlabID]atree.Slab
+	// undecodableSlabs contains the slabs which exist, but could not be decoded
+	undecodableSlabs map[atree.SlabID]struct{}
+	parents          map[atree.SlabID]atree.SlabID
+	children         map[atree.SlabID][]atree.SlabID
+	// rootSlabIDs contains the IDs of the slabs which are referenced by account registers
+	rootSlabIDs map[atree.SlabID]struct{}
+}
+
+func NewChecker(ledger *payloads.Ledger, config Config) *Checker {
+	return &Checker{
+		ledger: ledger,
+		config: config,
+		report: &Report{
+			Problems: []Problem{},
+			Repairs:  []Repair{},
+		},
+		slabs:            map[atree.SlabID]atree.Slab{},
+		undecodableSlabs: map[atree.SlabID]struct{}{},
+		parents:          map[atree.SlabID]atree.SlabID{},
+		children:         map[atree.SlabID][]atree.SlabID{},
+		rootSlabIDs:      map[atree.SlabID]struct{}{},
+	}
+}
+
+// Check checks the slabs and, if enabled, the values of all accounts.
+func (c *Checker) Check() *Report {
+	addresses := c.ledger.Addresses()
+	c.report.Accounts = len(addresses)
+
+	slabIDs := c.decodeSlabs()
+	c.report.Slabs = len(slabIDs)
+
+	c.checkSlabReferences(slabIDs)
+	c.checkRootSlabs(addresses)
+	c.checkUnreferencedSlabs(slabIDs)
+
+	if c.config.CheckValues {
+		c.checkValues(addresses)
+	}
+
+	return c.report
+}
+
+func (c *Checker) reportProblem(problem Problem) {
+	c.report.Problems = append(c.report.Problems, problem)
+}
+
+func (c *Checker) decodeSlabs() []atree.SlabID {
+	var slabIDs []atree.SlabID
+
+	_ = c.ledger.ForEachRegister(func(registerID payloads.RegisterID, data []byte) error {
+		slabID := registerID.SlabID()
+		if slabID == atree.SlabIDUndefined {
+			return nil
+		}
+
+		slabIDs = append(slabIDs, slabID)
+
+		slab, err := payloads.DecodeSlab(slabID, data)
+		if err != nil {
+			c.undecodableSlabs[slabID] = struct{}{}
+			c.reportProblem(Problem{
+				Kind:    ProblemKindUndecodableSlab,
+				Address: slabAddress(slabID).HexWithPrefix(),
+				SlabID:  slabID.String(),
+				Message: err.Error(),
+			})
+			return nil
+		}
+
+		c.slabs[slabID] = slab
+		return nil
+	})
+
+	return slabIDs
+}
+
+func (c *Checker) slabExists(slabID atree.SlabID) bool {
+	if _, ok := c.slabs[slabID]; ok {
+		return true
+	}
+	_, ok := c.undecodableSlabs[slabID]
+	return ok
+}
+
+// checkSlabReferences records the parent and children of each slab,
+// and reports references to missing slabs and slabs with multiple parents.
+func (c *Checker) checkSlabReferences(slabIDs []atree.SlabID) {
+	for _, slabID := range slabIDs {
+		slab, ok := c.slabs[slabID]
+		if !ok {
+			continue
+		}
+
+		address := slabAddress(slabID).HexWithPrefix()
+
+		// Traverse inlined storables, like atree.CheckStorageHealth
+		childStorables := slab.ChildStorables()
+		for len(childStorables) > 0 {
+			var next []atree.Storable
+
+			for _, storable := range childStorables {
+				if slabIDStorable, ok := storable.(atree.SlabIDStorable); ok {
+					childID := atree.SlabID(slabIDStorable)
+
+					if parentID, ok := c.parents[childID]; ok {
+						c.reportProblem(Problem{
+							Kind:    ProblemKindMultiplyReferencedSlab,
+							Address: address,
+							SlabID:  childID.String(),
+							Message: fmt.Sprintf("slab is referenced by %s and %s", parentID, slabID),
+						})
+					} else {
+						c.parents[childID] = slabID
+						c.children[slabID] = append(c.children[slabID], childID)
+					}
+
+					if !c.slabExists(childID) {
+						c.reportProblem(Problem{
+							Kind:    ProblemKindDanglingSlabReference,
+							Address: address,
+							SlabID:  slabID.String(),
+							Message: fmt.Sprintf("slab references missing slab %s", childID),
+						})
+					}
+				}
+
+				next = append(next, storable.ChildStorables()...)
+			}
+
+			childStorables = next
+		}
+	}
+}
+
+// checkRootSlabs records the slabs which are referenced by account registers,
+// i.e. account storage maps and unmigrated domain storage maps,
+// and reports references to missing slabs.
+func (c *Checker) checkRootSlabs(addresses []common.Address) {
+	for _, address := range addresses {
+
+		keys := []string{runtime.AccountStorageKey}
+		for _, domain := range common.AllStorageDomains {
+			keys = append(keys, domain.Identifier())
+		}
+
+		for _, key := range keys {
+			data, err := c.ledger.GetValue(address[:], []byte(key))
+			if err != nil || len(data) != len(atree.SlabIndex{}) {
+				continue
+			}
+
+			slabID := atree.NewSlabID(atree.Address(address), atree.SlabIndex(data))
+			c.rootSlabIDs[slabID] = struct{}{}
+
+			if !c.slabExists(slabID) {
+				c.reportProblem(Problem{
+					Kind:    ProblemKindDanglingSlabReference,
+					Address: address.HexWithPrefix(),
+					Domain:  key,
+					Message: fmt.Sprintf("account register references missing slab %s", slabID),
+				})
+			}
+		}
+	}
+}
+
+// checkUnreferencedSlabs reports root slabs which are not referenced by account registers,
+// and plans their removal, including all of their descendants.
+func (c *Checker) checkUnreferencedSlabs(slabIDs []atree.SlabID) {
+
+	// If an account has slabs which cannot be decoded,
+	// their children appear to be unreferenced,
+	// so their removal must not be automatic
+
+	accountsWithUndecodableSlabs := map[atree.Address]struct{}{}
+	for slabID := range c.undecodableSlabs { //nolint:maprange
+		accountsWithUndecodableSlabs[slabID.Address()] = struct{}{}
+	}
+
+	for _, slabID := range slabIDs {
+		if _, ok := c.parents[slabID]; ok {
+			continue
+		}
+		if _, ok := c.rootSlabIDs[slabID]; ok {
+			continue
+		}
+		if _, ok := c.undecodableSlabs[slabID]; ok {
+			continue
+		}
+
+		address := slabAddress(slabID).HexWithPrefix()
+
+		c.reportProblem(Problem{
+			Kind:    ProblemKindUnreferencedRootSlab,
+			Address: address,
+			SlabID:  slabID.String(),
+			Message: "root slab is not referenced by account storage",
+		})
+
+		descendants := c.descendants(slabID)
+
+		slabIDStrings := make([]string, 0, len(descendants))
+		for _, descendant := range descendants {
+			slabIDStrings = append(slabIDStrings, descendant.String())
+		}
+
+		_, hasUndecodableSlabs := accountsWithUndecodableSlabs[slabID.Address()]
+
+		c.report.Repairs = append(c.report.Repairs, Repair{
+			Kind:      RepairKindRemoveSlabs,
+			Address:   address,
+			SlabIDs:   slabIDStrings,
+			Automatic: !hasUndecodableSlabs,
+			slabIDs:   descendants,
+		})
+	}
+}
+
+// descendants returns the given slab and all existing descendant slabs.
+func (c *Checker) descendants(slabID atree.SlabID) []atree.SlabID {
+	var result []atree.SlabID
+
+	stack := []atree.SlabID{slabID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !c.slabExists(id) {
+			continue
+		}
+
+		result = append(result, id)
+		stack = append(stack, c.children[id]...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Compare(result[j]) < 0
+	})
+
+	return result
+}
+
+// typeUnavailableError is reported when a value's type is defined in a contract,
+// as contract programs are not available
+type typeUnavailableError struct {
+	Location common.Location
+}
+
+func (e typeUnavailableError) Error() string {
+	return fmt.Sprintf("type defined in %s is not available", e.Location)
+}
+
+func (c *Checker) checkValues(addresses []common.Address) {
+	storage := runtime.NewStorage(c.ledger, nil, runtime.StorageConfig{})
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		nil,
+		&interpreter.Config{
+			Storage: storage,
+			ImportLocationHandler: func(_ *interpreter.Interpreter, location common.Location) interpreter.Import {
+				panic(typeUnavailableError{
+					Location: location,
+				})
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, address := range addresses {
+		for _, domain := range common.AllStorageDomains {
+			c.checkDomainValues(storage, inter, address, domain)
+		}
+	}
+}
+
+func (c *Checker) checkDomainValues(
+	storage *runtime.Storage,
+	inter *interpreter.Interpreter,
+	address common.Address,
+	domain common.StorageDomain,
+) {
+	var storageMap *interpreter.DomainStorageMap
+	var keys []interpreter.StorageMapKey
+
+	err := catchPanic(func() {
+		storageMap = storage.GetDomainStorageMap(inter, address, domain, false)
+		if storageMap == nil {
+			return
+		}
+
+		// Only iterate over the keys, so that a broken value
+		// does not prevent checking the remaining values
+
+		iterator := storageMap.Iterator(nil)
+		for {
+			key := iterator.NextKey()
+			if key == nil {
+				break
+			}
+
+			switch key := key.(type) {
+			case interpreter.StringAtreeValue:
+				keys = append(keys, interpreter.StringStorageMapKey(key))
+			case interpreter.Uint64AtreeValue:
+				keys = append(keys, interpreter.Uint64StorageMapKey(key))
+			default:
+				panic(fmt.Errorf("invalid storage map key: %T", key))
+			}
+		}
+	})
+	if err != nil {
+		c.reportProblem(Problem{
+			Kind:    ProblemKindInvalidStorable,
+			Address: address.HexWithPrefix(),
+			Domain:  domain.Identifier(),
+			Message: fmt.Sprintf("failed to load storage map: %s", err),
+		})
+	}
+
+	for _, key := range keys {
+		c.checkValue(inter, storageMap, address, domain, key)
+	}
+}
+
+func (c *Checker) checkValue(
+	inter *interpreter.Interpreter,
+	storageMap *interpreter.DomainStorageMap,
+	address common.Address,
+	domain common.StorageDomain,
+	key interpreter.StorageMapKey,
+) {
+	c.report.Values++
+
+	keyString := storageMapKeyString(key)
+
+	reportValueProblem := func(kind ProblemKind, message string) {
+		c.reportProblem(Problem{
+			Kind:    kind,
+			Address: address.HexWithPrefix(),
+			Domain:  domain.Identifier(),
+			Key:     keyString,
+			Message: message,
+		})
+
+		c.report.Repairs = append(c.report.Repairs, Repair{
+			Kind:    RepairKindRemoveValue,
+			Address: address.HexWithPrefix(),
+			Domain:  domain.Identifier(),
+			Key:     keyString,
+		})
+	}
+
+	var value interpreter.Value
+	err := catchPanic(func() {
+		value = storageMap.ReadValue(nil, key)
+	})
+	if err != nil {
+		reportValueProblem(ProblemKindInvalidStorable, err.Error())
+		return
+	}
+
+	var conforms bool
+	err = catchPanic(func() {
+		conforms = value.ConformsToStaticType(
+			inter,
+			interpreter.EmptyLocationRange,
+			interpreter.TypeConformanceResults{},
+		)
+	})
+	if err != nil {
+		var typeUnavailableErr typeUnavailableError
+		var typeLoadingErr interpreter.TypeLoadingError
+		if goerrors.As(err, &typeUnavailableErr) || goerrors.As(err, &typeLoadingErr) {
+			c.report.SkippedValues++
+			return
+		}
+
+		reportValueProblem(ProblemKindInvalidValue, err.Error())
+		return
+	}
+
+	if !conforms {
+		reportValueProblem(
+			ProblemKindNonConformingValue,
+			fmt.Sprintf("value does not conform to its static type %s", value.StaticType(inter).ID()),
+		)
+	}
+}
+
+// ApplyRepairs applies all automatic repairs to the ledger,
+// and returns the number of removed registers.
+func ApplyRepairs(ledger *payloads.Ledger, report *Report) (int, error) {
+	var removed int
+
+	for _, repair := range report.Repairs {
+		if !repair.Automatic {
+			continue
+		}
+
+		switch repair.Kind {
+		case RepairKindRemoveSlabs:
+			for _, slabID := range repair.slabIDs {
+				registerID := payloads.SlabRegisterID(slabID)
+				err := ledger.SetValue([]byte(registerID.Owner), []byte(registerID.Key), nil)
+				if err != nil {
+					return removed, err
+				}
+				removed++
+			}
+
+		default:
+			return removed, fmt.Errorf("unsupported automatic repair: %s", repair.Kind)
+		}
+	}
+
+	return removed, nil
+}
+
+func catchPanic(f func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		switch r := r.(type) {
+		case error:
+			err = r
+		default:
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	f()
+
+	return nil
+}
+
+func slabAddress(slabID atree.SlabID) common.Address {
+	return common.Address(slabID.Address())
+}
+
+func storageMapKeyString(key interpreter.StorageMapKey) string {
+	switch key := key.(type) {
+	case interpreter.StringStorageMapKey:
+		return string(key)
+	case interpreter.Uint64StorageMapKey:
+		return strconv.FormatUint(uint64(key), 10)
+	default:
+		return fmt.Sprintf("%v", key)
+	}
+}
@@ -0,0 +1,290 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+)
+
+var testAddress = common.MustBytesToAddress([]byte{0x1})
+
+func newTestArray(
+	inter *interpreter.Interpreter,
+	elementType interpreter.StaticType,
+	values ...interpreter.Value,
+) *interpreter.ArrayValue {
+	return interpreter.NewArrayValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		interpreter.NewVariableSizedStaticType(nil, elementType),
+		testAddress,
+		values...,
+	)
+}
+
+// newTestStrings returns enough strings for an array to not be inlined
+func newTestStrings() []interpreter.Value {
+	values := make([]interpreter.Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(
+			values,
+			interpreter.NewUnmeteredStringValue(fmt.Sprintf("value %d", i)),
+		)
+	}
+	return values
+}
+
+func newTestLedger(
+	t *testing.T,
+	prepare func(inter *interpreter.Interpreter, storageMap *interpreter.DomainStorageMap),
+) *payloads.Ledger {
+	ledger := payloads.NewLedger()
+
+	storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+	storageMap := storage.GetDomainStorageMap(
+		inter,
+		testAddress,
+		common.PathDomainStorage.StorageDomain(),
+		true,
+	)
+
+	storageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("strings"),
+		newTestArray(inter, interpreter.PrimitiveStaticTypeString, newTestStrings()...),
+	)
+
+	if prepare != nil {
+		prepare(inter, storageMap)
+	}
+
+	const commitContractUpdates = false
+	err := storage.Commit(inter, commitContractUpdates)
+	require.NoError(t, err)
+
+	return ledger
+}
+
+// arraySlabID returns the ID of the root slab of the stored array
+func arraySlabID(t *testing.T, ledger *payloads.Ledger) atree.SlabID {
+	storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	storageMap := storage.GetDomainStorageMap(
+		inter,
+		testAddress,
+		common.PathDomainStorage.StorageDomain(),
+		false,
+	)
+	require.NotNil(t, storageMap)
+
+	value := storageMap.ReadValue(nil, interpreter.StringStorageMapKey("strings"))
+	require.IsType(t, &interpreter.ArrayValue{}, value)
+
+	slabID := value.(*interpreter.ArrayValue).SlabID()
+	require.False(t, value.(*interpreter.ArrayValue).Inlined())
+
+	return slabID
+}
+
+func problemKinds(report *Report) []ProblemKind {
+	kinds := make([]ProblemKind, 0, len(report.Problems))
+	for _, problem := range report.Problems {
+		kinds = append(kinds, problem.Kind)
+	}
+	return kinds
+}
+
+func TestCheckStorage(t *testing.T) {
+
+	t.Parallel()
+
+	config := Config{
+		CheckValues: true,
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, nil)
+
+		report := NewChecker(ledger, config).Check()
+
+		assert.Equal(t, 1, report.Accounts)
+		assert.Greater(t, report.Slabs, 1)
+		assert.Equal(t, 1, report.Values)
+		assert.Empty(t, report.Problems)
+		assert.Empty(t, report.Repairs)
+	})
+
+	t.Run("unreferenced root slab", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, func(inter *interpreter.Interpreter, _ *interpreter.DomainStorageMap) {
+			// Create an array in the account, but do not store it
+			newTestArray(inter, interpreter.PrimitiveStaticTypeString, newTestStrings()...)
+		})
+
+		registerCount := ledger.Count()
+
+		report := NewChecker(ledger, config).Check()
+
+		assert.Equal(t,
+			[]ProblemKind{ProblemKindUnreferencedRootSlab},
+			problemKinds(report),
+		)
+		require.Len(t, report.Repairs, 1)
+
+		repair := report.Repairs[0]
+		assert.Equal(t, RepairKindRemoveSlabs, repair.Kind)
+		assert.True(t, repair.Automatic)
+		assert.NotEmpty(t, repair.SlabIDs)
+
+		removed, err := ApplyRepairs(ledger, report)
+		require.NoError(t, err)
+		assert.Equal(t, len(repair.SlabIDs), removed)
+		assert.Equal(t, registerCount-removed, ledger.Count())
+
+		report = NewChecker(ledger, config).Check()
+		assert.Empty(t, report.Problems)
+	})
+
+	t.Run("dangling slab reference", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, nil)
+
+		// Remove a child slab of the array
+
+		arraySlabID := arraySlabID(t, ledger)
+
+		var childSlabID atree.SlabID
+		for _, registerID := range ledger.RegisterIDs() {
+			slabID := registerID.SlabID()
+			if slabID == atree.SlabIDUndefined || slabID == arraySlabID {
+				continue
+			}
+			data, err := ledger.GetValue([]byte(registerID.Owner), []byte(registerID.Key))
+			require.NoError(t, err)
+
+			slab, err := payloads.DecodeSlab(slabID, data)
+			require.NoError(t, err)
+
+			if _, ok := slab.(*atree.ArrayDataSlab); ok {
+				childSlabID = slabID
+				break
+			}
+		}
+		require.NotEqual(t, atree.SlabIDUndefined, childSlabID)
+
+		registerID := payloads.SlabRegisterID(childSlabID)
+		err := ledger.SetValue([]byte(registerID.Owner), []byte(registerID.Key), nil)
+		require.NoError(t, err)
+
+		report := NewChecker(ledger, config).Check()
+
+		assert.Equal(t,
+			[]ProblemKind{
+				ProblemKindDanglingSlabReference,
+				ProblemKindInvalidValue,
+			},
+			problemKinds(report),
+		)
+
+		assert.Equal(t, "strings", report.Problems[1].Key)
+		assert.Equal(t, "storage", report.Problems[1].Domain)
+
+		require.Len(t, report.Repairs, 1)
+		repair := report.Repairs[0]
+		assert.Equal(t, RepairKindRemoveValue, repair.Kind)
+		assert.False(t, repair.Automatic)
+	})
+
+	t.Run("undecodable slab", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, nil)
+
+		registerID := payloads.SlabRegisterID(arraySlabID(t, ledger))
+		err := ledger.SetValue([]byte(registerID.Owner), []byte(registerID.Key), []byte{0xff})
+		require.NoError(t, err)
+
+		report := NewChecker(ledger, config).Check()
+
+		kinds := problemKinds(report)
+		require.NotEmpty(t, kinds)
+		assert.Equal(t, ProblemKindUndecodableSlab, kinds[0])
+		assert.Contains(t, kinds, ProblemKindInvalidStorable)
+
+		// The children of the undecodable slab appear unreferenced,
+		// so their removal must not be automatic
+		for _, repair := range report.Repairs {
+			assert.False(t, repair.Automatic)
+		}
+	})
+
+	t.Run("non-conforming value", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, func(inter *interpreter.Interpreter, storageMap *interpreter.DomainStorageMap) {
+			storageMap.WriteValue(
+				inter,
+				interpreter.StringStorageMapKey("ints"),
+				newTestArray(
+					inter,
+					interpreter.PrimitiveStaticTypeInt,
+					interpreter.NewUnmeteredStringValue("not an int"),
+				),
+			)
+		})
+
+		report := NewChecker(ledger, config).Check()
+
+		assert.Equal(t, 2, report.Values)
+		assert.Equal(t,
+			[]ProblemKind{ProblemKindNonConformingValue},
+			problemKinds(report),
+		)
+		assert.Equal(t, "ints", report.Problems[0].Key)
+	})
+
+	t.Run("values not checked", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newTestLedger(t, nil)
+
+		report := NewChecker(ledger, Config{}).Check()
+
+		assert.Equal(t, 0, report.Values)
+		assert.Empty(t, report.Problems)
+	})
+}
@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// A utility program that checks the health of the storage in a state dump,
+// and reports problems and a repair plan as JSON.
+//
+// The state dump is in the JSON Lines format also used by cmd/decode-state-values.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+)
+
+var addressesFlag = flag.String("addresses", "", "only check the accounts with the given comma-separated addresses")
+var checkValuesFlag = flag.Bool("check-values", true, "load all values and check that they conform to their static types")
+var repairFlag = flag.String("repair", "", "apply the automatic repairs and write the repaired payloads to the given file")
+
+func main() {
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		log.Fatal("missing path argument")
+	}
+
+	var addresses []common.Address
+	if *addressesFlag != "" {
+		for _, hexAddress := range strings.Split(*addressesFlag, ",") {
+			address, err := common.HexToAddress(strings.TrimSpace(hexAddress))
+			if err != nil {
+				log.Fatalf("Invalid address: %s", hexAddress)
+			}
+			addresses = append(addresses, address)
+		}
+	}
+
+	log.Println("Reading payloads ...")
+
+	ledger, err := payloads.ReadFile(args[0], addresses)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Checking %d payloads ...", ledger.Count())
+
+	report := NewChecker(
+		ledger,
+		Config{
+			CheckValues: *checkValuesFlag,
+		},
+	).Check()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Found %d problems", len(report.Problems))
+
+	if *repairFlag != "" {
+		removed, err := ApplyRepairs(ledger, report)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = writePayloads(ledger, *repairFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Removed %d registers, wrote repaired payloads to %s", removed, *repairFlag)
+	}
+
+	if len(report.Problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+func writePayloads(ledger *payloads.Ledger, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ledger.Write(file)
+}
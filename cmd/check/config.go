@@ -0,0 +1,61 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/cmd"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// newAnalysisConfig returns the configuration for loading files and their imports,
+// which loads at most the given number of programs in parallel.
+//
+// Programs with errors are kept, so the errors can be reported
+func newAnalysisConfig(parallel int) *analysis.Config {
+	return &analysis.Config{
+		Mode:                  analysis.NeedTypes,
+		Concurrency:           parallel,
+		TypeParametersEnabled: cmd.ParserConfig.TypeParametersEnabled,
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			stringLocation, ok := location.(common.StringLocation)
+			if !ok {
+				return nil, fmt.Errorf("cannot import `%s`. only files are supported", location)
+			}
+			return os.ReadFile(string(stringLocation))
+		},
+		HandleParserError: func(err analysis.ParsingCheckingError, program *ast.Program) error {
+			if program == nil {
+				return err
+			}
+			return nil
+		},
+		HandleCheckerError: func(_ analysis.ParsingCheckingError, _ *sema.Checker) error {
+			return nil
+		},
+	}
+}
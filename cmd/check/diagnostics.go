@@ -0,0 +1,124 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/diagnostics"
+)
+
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+// checkDiagnostics checks the files at the given paths,
+// and prints the parser and checker errors in the given machine-readable format
+func checkDiagnostics(paths []string, format string, parallel int) {
+	if len(paths) == 0 {
+		panic(fmt.Errorf("cannot check standard input in format %s", format))
+	}
+
+	locations := make([]common.Location, 0, len(paths))
+	for _, path := range paths {
+		locations = append(locations, common.NewStringLocation(nil, path))
+	}
+
+	config := newAnalysisConfig(parallel)
+
+	programs := &analysis.Programs{
+		Programs: map[common.Location]*analysis.Program{},
+	}
+
+	// Errors are reported for each location
+	_, _ = programs.Reload(config, locations...)
+
+	codes := make(map[common.Location][]byte, len(programs.Programs))
+	for location, program := range programs.Programs { //nolint:maprange
+		codes[location] = program.Code
+	}
+
+	var results []diagnostics.Diagnostic
+
+	for _, location := range locations {
+		var err error
+		program := programs.Get(location)
+		if program != nil {
+			err = program.LoadError
+		} else {
+			// The program failed to load, load it again to get the error
+			err = programs.Load(config, location)
+		}
+
+		if err != nil {
+			results = append(results, diagnostics.FromError(err, location, codes)...)
+		}
+	}
+
+	var err error
+	switch format {
+	case formatJSON:
+		err = diagnostics.WriteJSON(os.Stdout, results)
+	case formatSARIF:
+		err = diagnostics.WriteSARIF(
+			os.Stdout,
+			diagnostics.Tool{
+				Name:           "cadence-check",
+				InformationURI: "https://github.com/onflow/cadence",
+				Rules:          errorCodeRules(),
+			},
+			results,
+		)
+	default:
+		panic(fmt.Errorf("unsupported format: %s", format))
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	if len(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+// errorCodeRules returns the SARIF rules for the error codes of the parser and checker errors
+func errorCodeRules() []diagnostics.Rule {
+	catalogs := [][]errors.ErrorCodeInfo{
+		parser.ErrorCodes,
+		sema.ErrorCodes,
+	}
+
+	var rules []diagnostics.Rule
+	for _, catalog := range catalogs {
+		for _, info := range catalog {
+			rules = append(rules, diagnostics.Rule{
+				ID:          string(info.Code),
+				Description: info.Explanation,
+			})
+		}
+	}
+	return rules
+}
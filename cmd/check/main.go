@@ -27,6 +27,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"testing"
 	"text/tabwriter"
 	"time"
@@ -34,6 +35,7 @@ import (
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/cmd"
 	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/pretty"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
@@ -52,13 +54,26 @@ func (f *memberAccountAccessFlags) Set(value string) error {
 
 var benchFlag = flag.Bool("bench", false, "benchmark the checker")
 var jsonFlag = flag.Bool("json", false, "print the result formatted as JSON")
+var parallelFlag = flag.Int("parallel", 1, "number of files checked in parallel (ignored when benchmarking)")
+var watchFlag = flag.Bool("watch", false, "check the files again when they or their imports change")
+var formatFlag = flag.String("format", formatText, "format of the errors: text, json, or sarif")
+var typeParametersFlag = flag.Bool("typeParameters", false, "allow type parameters for non-native functions")
 
 var memberAccountAccessFlag memberAccountAccessFlags
 
+// parserConfig is the configuration used to parse the checked programs.
+// Error recovery is enabled, so all syntax errors of a program are reported
+var parserConfig parser.Config
+
 func main() {
 	flag.Var(&memberAccountAccessFlag, "memberAccountAccess", "allow account access from:to")
 	flag.Parse()
 
+	cmd.ParserConfig.TypeParametersEnabled = *typeParametersFlag
+
+	parserConfig = cmd.ParserConfig
+	parserConfig.ErrorRecoveryEnabled = true
+
 	memberAccountAccess := map[common.Location]map[common.Location]struct{}{}
 
 	for _, value := range memberAccountAccessFlag {
@@ -88,7 +103,24 @@ func main() {
 	}
 
 	args := flag.Args()
-	run(args, *benchFlag, *jsonFlag, memberAccountAccess)
+
+	if *formatFlag != formatText {
+		if *benchFlag || *watchFlag || *jsonFlag || len(memberAccountAccessFlag) > 0 {
+			panic(fmt.Errorf("format %s is not supported when benchmarking, watching, printing JSON results, or allowing member account access", *formatFlag))
+		}
+		checkDiagnostics(args, *formatFlag, *parallelFlag)
+		return
+	}
+
+	if *watchFlag {
+		if *benchFlag || len(memberAccountAccessFlag) > 0 {
+			panic(fmt.Errorf("watching is not supported when benchmarking or allowing member account access"))
+		}
+		watch(args, *jsonFlag, *parallelFlag)
+		return
+	}
+
+	run(args, *benchFlag, *jsonFlag, *parallelFlag, memberAccountAccess)
 }
 
 type benchResult struct {
@@ -181,6 +213,7 @@ func run(
 	paths []string,
 	bench bool,
 	json bool,
+	parallel int,
 	memberAccountAccess map[common.Location]map[common.Location]struct{},
 ) {
 	if len(paths) == 0 {
@@ -198,9 +231,38 @@ func run(
 
 	useColor := !json
 
-	for _, path := range paths {
-		res, runSucceeded := runPath(path, bench, useColor, memberAccountAccess)
-		if !runSucceeded {
+	// Benchmarks are run sequentially, so they do not affect each other
+
+	if bench || parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]result, len(paths))
+	succeeded := make([]bool, len(paths))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, parallel)
+
+	for i, path := range paths {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			results[i], succeeded[i] = runPath(path, bench, useColor, memberAccountAccess)
+		}()
+	}
+
+	wg.Wait()
+
+	// Report the results in the order of the given paths
+
+	for i, res := range results {
+		if !succeeded[i] {
 			allSucceeded = false
 		}
 
@@ -247,7 +309,7 @@ func runPath(
 			}
 		}()
 
-		program, must = cmd.PrepareProgram(code, location, codes)
+		program, must = cmd.PrepareProgramWithConfig(code, location, codes, parserConfig)
 
 		checker, _ = cmd.PrepareChecker(
 			program,
@@ -262,6 +324,7 @@ func runPath(
 		if err != nil {
 			var builder strings.Builder
 			printErr := pretty.NewErrorPrettyPrinter(&builder, useColor).
+				WithErrorCodes().
 				PrettyPrintError(err, location, codes)
 			if printErr != nil {
 				panic(printErr)
@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/pretty"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const watchInterval = 500 * time.Millisecond
+
+// watch checks the files at the given paths, and checks them again whenever they or their imports change.
+//
+// Only the changed files and the files which import them are checked again
+func watch(paths []string, json bool, parallel int) {
+	if len(paths) == 0 {
+		panic(fmt.Errorf("cannot watch standard input"))
+	}
+
+	locations := make([]common.Location, 0, len(paths))
+	for _, path := range paths {
+		locations = append(locations, common.NewStringLocation(nil, path))
+	}
+
+	config := newAnalysisConfig(parallel)
+
+	programs := &analysis.Programs{
+		Programs: map[common.Location]*analysis.Program{},
+	}
+
+	modTimes := map[common.Location]time.Time{}
+
+	// Errors are reported for each location
+	_, _ = programs.Reload(config, locations...)
+	updateModTimes(programs, locations, modTimes)
+	report(programs, config, locations, json)
+
+	for {
+		time.Sleep(watchInterval)
+
+		changed := changedLocations(modTimes)
+		if len(changed) == 0 {
+			continue
+		}
+
+		invalidated, _ := programs.Reload(config, changed...)
+		updateModTimes(programs, locations, modTimes)
+
+		// Report the results for the invalidated files which were given,
+		// in the order of the given paths
+
+		invalidatedLocations := make(map[common.Location]struct{}, len(invalidated))
+		for _, location := range invalidated {
+			invalidatedLocations[location] = struct{}{}
+		}
+
+		var reported []common.Location
+		for _, location := range locations {
+			if _, ok := invalidatedLocations[location]; ok {
+				reported = append(reported, location)
+			}
+		}
+
+		if !json {
+			fmt.Printf("\n%s: changed: %s\n", time.Now().Format(time.TimeOnly), joinLocations(changed))
+		}
+
+		report(programs, config, reported, json)
+	}
+}
+
+func report(
+	programs *analysis.Programs,
+	config *analysis.Config,
+	locations []common.Location,
+	json bool,
+) {
+	var out output
+	if json {
+		out = newJSONOutput(len(locations))
+	} else {
+		out = newStdoutOutput()
+	}
+
+	codes := make(map[common.Location][]byte, len(programs.Programs))
+	for location, program := range programs.Programs { //nolint:maprange
+		codes[location] = program.Code
+	}
+
+	useColor := !json
+
+	for _, location := range locations {
+		res := result{
+			Path: string(location.(common.StringLocation)),
+		}
+
+		var err error
+		program := programs.Get(location)
+		if program != nil {
+			err = program.LoadError
+		} else {
+			// The program failed to load, load it again to get the error
+			err = programs.Load(config, location)
+		}
+
+		if err != nil {
+			var builder strings.Builder
+			printErr := pretty.NewErrorPrettyPrinter(&builder, useColor).
+				WithErrorCodes().
+				PrettyPrintError(err, location, codes)
+			if printErr != nil {
+				panic(printErr)
+			}
+			res.Error = builder.String()
+		}
+
+		out.Append(res)
+	}
+
+	out.End()
+}
+
+// updateModTimes records the modification times of the given files and of all loaded files,
+// if they are not recorded yet
+func updateModTimes(
+	programs *analysis.Programs,
+	locations []common.Location,
+	modTimes map[common.Location]time.Time,
+) {
+	record := func(location common.Location) {
+		if _, ok := modTimes[location]; ok {
+			return
+		}
+		stringLocation, ok := location.(common.StringLocation)
+		if !ok {
+			return
+		}
+		modTimes[location] = modTime(stringLocation)
+	}
+
+	for _, location := range locations {
+		record(location)
+	}
+
+	for location := range programs.Programs { //nolint:maprange
+		record(location)
+	}
+}
+
+// changedLocations returns the locations of the watched files which changed since they were recorded,
+// sorted by location ID, and records their new modification times
+func changedLocations(modTimes map[common.Location]time.Time) []common.Location {
+	var changed []common.Location
+
+	for location, recordedModTime := range modTimes { //nolint:maprange
+		currentModTime := modTime(location.(common.StringLocation))
+		if currentModTime.Equal(recordedModTime) {
+			continue
+		}
+		modTimes[location] = currentModTime
+		changed = append(changed, location)
+	}
+
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].ID() < changed[j].ID()
+	})
+
+	return changed
+}
+
+// modTime returns the modification time of the given file,
+// or the zero time if the file does not exist
+func modTime(location common.StringLocation) time.Time {
+	info, err := os.Stat(string(location))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func joinLocations(locations []common.Location) string {
+	descriptions := make([]string, 0, len(locations))
+	for _, location := range locations {
+		descriptions = append(descriptions, location.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
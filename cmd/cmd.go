@@ -24,6 +24,7 @@ import (
 	"math/rand"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/onflow/cadence/activations"
@@ -41,6 +42,7 @@ func must(err error, location common.Location, codes map[common.Location][]byte)
 		return
 	}
 	printErr := pretty.NewErrorPrettyPrinter(os.Stderr, true).
+		WithErrorCodes().
 		PrettyPrintError(err, location, codes)
 	if printErr != nil {
 		panic(printErr)
@@ -64,17 +66,35 @@ func PrepareProgramFromFile(location common.StringLocation, codes map[common.Loc
 }
 
 func PrepareProgram(code []byte, location common.Location, codes map[common.Location][]byte) (*ast.Program, func(error)) {
+	return PrepareProgramWithConfig(code, location, codes, ParserConfig)
+}
+
+func PrepareProgramWithConfig(
+	code []byte,
+	location common.Location,
+	codes map[common.Location][]byte,
+	config parser.Config,
+) (*ast.Program, func(error)) {
 	must := mustClosure(location, codes)
 
-	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	program, err := parser.ParseProgram(nil, code, config)
 	codes[location] = code
 	must(err)
 
 	return program, must
 }
 
+// ParserConfig is the configuration used to parse programs.
+// Like in the runtime, type parameters of non-native functions are disabled by default.
+// If they are enabled, they are also enabled in the checker configuration
+var ParserConfig = parser.Config{}
+
 var checkers = map[common.Location]*sema.Checker{}
 
+// checkersLock guards the checkers of imported programs,
+// as programs may be checked concurrently
+var checkersLock sync.Mutex
+
 func DefaultCheckerConfig(
 	checkers map[common.Location]*sema.Checker,
 	codes map[common.Location][]byte,
@@ -91,7 +111,8 @@ func DefaultCheckerConfig(
 		BaseValueActivationHandler: func(_ common.Location) *sema.VariableActivation {
 			return baseValueActivation
 		},
-		AccessCheckMode: sema.AccessCheckModeStrict,
+		AccessCheckMode:       sema.AccessCheckModeStrict,
+		TypeParametersEnabled: ParserConfig.TypeParametersEnabled,
 		ImportHandler: func(
 			checker *sema.Checker,
 			importedLocation common.Location,
@@ -108,6 +129,9 @@ func DefaultCheckerConfig(
 				}
 			}
 
+			checkersLock.Lock()
+			defer checkersLock.Unlock()
+
 			importedChecker, ok := checkers[importedLocation]
 			if !ok {
 				importedProgram, _ := PrepareProgramFromFile(stringLocation, codes)
@@ -391,6 +415,10 @@ func (*StandardLibraryHandler) RecordContractRemoval(_ common.AddressLocation) {
 	// NO-OP
 }
 
+func (*StandardLibraryHandler) ParserConfig() parser.Config {
+	return ParserConfig
+}
+
 func (*StandardLibraryHandler) CreateAccount(_ common.Address) (address common.Address, err error) {
 	return common.ZeroAddress, goerrors.New("accounts are not available in this environment")
 }
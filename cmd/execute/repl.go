@@ -51,7 +51,7 @@ type ConsoleREPL struct {
 func NewConsoleREPL() (*ConsoleREPL, error) {
 	consoleREPL := &ConsoleREPL{
 		lineNumber:         1,
-		errorPrettyPrinter: pretty.NewErrorPrettyPrinter(os.Stderr, true),
+		errorPrettyPrinter: pretty.NewErrorPrettyPrinter(os.Stderr, true).WithErrorCodes(),
 	}
 
 	repl, err := runtime.NewREPL()
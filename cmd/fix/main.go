@@ -0,0 +1,168 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// fix applies the fixes suggested by codemods to Cadence files,
+// e.g. to upgrade programs to the current version of the language.
+//
+// Usage:
+//
+//	fix [-analyzers name,...] [-dry-run] [-list] path...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/codemods"
+)
+
+var analyzersFlag = flag.String("analyzers", "", "comma-separated names of the codemods to apply (default: all)")
+var dryRunFlag = flag.Bool("dry-run", false, "print the changes as a unified diff instead of writing the files")
+var listFlag = flag.Bool("list", false, "list the available codemods")
+
+func main() {
+	flag.Parse()
+
+	if *listFlag {
+		for _, name := range codemods.AnalyzerNames() {
+			fmt.Printf("%s\t%s\n", name, codemods.Analyzers[name].Description)
+		}
+		return
+	}
+
+	analyzers, err := selectAnalyzers(*analyzersFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "no files given")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range paths {
+		err := fix(path, analyzers, *dryRunFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// selectAnalyzers returns the codemods with the given comma-separated names,
+// or all codemods if no names are given
+func selectAnalyzers(names string) ([]*analysis.Analyzer, error) {
+	if names == "" {
+		names = strings.Join(codemods.AnalyzerNames(), ",")
+	}
+
+	var analyzers []*analysis.Analyzer
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		analyzer, ok := codemods.Analyzers[name]
+		if !ok {
+			return nil, fmt.Errorf(
+				"unknown codemod: %s. available codemods: %s",
+				name,
+				strings.Join(codemods.AnalyzerNames(), ", "),
+			)
+		}
+		analyzers = append(analyzers, analyzer)
+	}
+
+	return analyzers, nil
+}
+
+// fix applies the fixes suggested by the given analyzers to the file at the given path.
+// Imports are resolved relative to the working directory.
+//
+// In dry-run mode, the changes are printed as a unified diff instead
+func fix(path string, analyzers []*analysis.Analyzer, dryRun bool) error {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	config := &analysis.Config{
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			stringLocation, ok := location.(common.StringLocation)
+			if !ok {
+				return nil, fmt.Errorf("cannot import `%s`. only files are supported", location)
+			}
+			return os.ReadFile(string(stringLocation))
+		},
+	}
+
+	location := common.NewStringLocation(nil, path)
+
+	fixedCode, fixes, err := codemods.Fix(config, location, code, analyzers)
+	if err != nil {
+		return err
+	}
+
+	if len(fixes) == 0 {
+		return nil
+	}
+
+	if dryRun {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(code)),
+			B:        difflib.SplitLines(string(fixedCode)),
+			FromFile: path,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Print(diff)
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, fixedCode, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: applied %d fixes\n", path, len(fixes))
+
+	return nil
+}
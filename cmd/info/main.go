@@ -27,6 +27,7 @@ import (
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
@@ -75,6 +76,10 @@ var commands = map[string]command{
 		help:    "Dumps all hard keywords",
 		handler: dumpHardKeywords,
 	},
+	"dump-error-codes": {
+		help:    "Dumps the catalog of all error codes",
+		handler: dumpErrorCodes,
+	},
 }
 
 func dumpBuiltinTypes() {
@@ -301,6 +306,33 @@ func dumpHardKeywords() {
 	}
 }
 
+func dumpErrorCodes() {
+	catalogs := []struct {
+		name  string
+		infos []errors.ErrorCodeInfo
+	}{
+		{"Parser errors", parser.ErrorCodes},
+		{"Checker errors", sema.ErrorCodes},
+		{"Interpreter errors", interpreter.ErrorCodes},
+	}
+
+	fmt.Println("# Error codes")
+
+	for _, catalog := range catalogs {
+		fmt.Printf("\n## %s\n", catalog.name)
+
+		for _, info := range catalog.infos {
+			fmt.Printf("\n### %s: %s\n\n", info.Code, info.Name)
+			fmt.Println(info.Explanation)
+
+			example := strings.TrimSpace(info.Example)
+			if example != "" {
+				fmt.Printf("\n```cadence\n%s\n```\n", example)
+			}
+		}
+	}
+}
+
 func printAvailableCommands() {
 	type commandHelp struct {
 		name string
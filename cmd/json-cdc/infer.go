@@ -0,0 +1,144 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	_ "unsafe"
+
+	"github.com/onflow/cadence"
+)
+
+//go:linkname getCompositeFieldValues github.com/onflow/cadence.getCompositeFieldValues
+func getCompositeFieldValues(cadence.Composite) []cadence.Value
+
+//go:linkname getCompositeTypeFields github.com/onflow/cadence.getCompositeTypeFields
+func getCompositeTypeFields(cadence.CompositeType) []cadence.Field
+
+// inferTypes returns the value with static types for arrays and dictionaries,
+// and the fields of composite types, which JSON-CDC does not encode,
+// but CCF requires.
+//
+// The inferred type of a container is the type of its elements, if they all have the same type,
+// otherwise AnyStruct or AnyResource.
+//
+// Composite values decoded from JSON-CDC have their own types, which are updated in place.
+func inferTypes(value cadence.Value) cadence.Value {
+	switch v := value.(type) {
+	case cadence.Optional:
+		if v.Value == nil {
+			return v
+		}
+		return cadence.NewOptional(inferTypes(v.Value))
+
+	case cadence.Array:
+		if v.ArrayType != nil {
+			return v
+		}
+
+		values := make([]cadence.Value, len(v.Values))
+		elementTypes := make([]cadence.Type, len(v.Values))
+		for i, element := range v.Values {
+			values[i] = inferTypes(element)
+			elementTypes[i] = values[i].Type()
+		}
+
+		return cadence.NewArray(values).
+			WithType(cadence.NewVariableSizedArrayType(commonType(elementTypes)))
+
+	case cadence.Dictionary:
+		if v.DictionaryType != nil {
+			return v
+		}
+
+		pairs := make([]cadence.KeyValuePair, len(v.Pairs))
+		keyTypes := make([]cadence.Type, len(v.Pairs))
+		valueTypes := make([]cadence.Type, len(v.Pairs))
+		for i, pair := range v.Pairs {
+			pairs[i] = cadence.KeyValuePair{
+				Key:   inferTypes(pair.Key),
+				Value: inferTypes(pair.Value),
+			}
+			keyTypes[i] = pairs[i].Key.Type()
+			valueTypes[i] = pairs[i].Value.Type()
+		}
+
+		return cadence.NewDictionary(pairs).
+			WithType(cadence.NewDictionaryType(
+				commonType(keyTypes),
+				commonType(valueTypes),
+			))
+
+	case cadence.Composite:
+		fieldValues := getCompositeFieldValues(v)
+		for i, fieldValue := range fieldValues {
+			fieldValues[i] = inferTypes(fieldValue)
+		}
+
+		compositeType, ok := v.Type().(cadence.CompositeType)
+		if ok {
+			fields := getCompositeTypeFields(compositeType)
+			if len(fields) == len(fieldValues) {
+				for i, field := range fields {
+					if field.Type == nil {
+						fields[i].Type = fieldValues[i].Type()
+					}
+				}
+			}
+		}
+
+		return v
+
+	default:
+		return value
+	}
+}
+
+func commonType(types []cadence.Type) cadence.Type {
+	var result cadence.Type
+
+	isResource := false
+	allEqual := true
+
+	for _, ty := range types {
+		if _, ok := ty.(*cadence.ResourceType); ok {
+			isResource = true
+		}
+
+		if ty == nil {
+			allEqual = false
+			continue
+		}
+
+		if result == nil {
+			result = ty
+		} else if !result.Equal(ty) {
+			allEqual = false
+		}
+	}
+
+	if result != nil && allEqual {
+		return result
+	}
+
+	if isResource {
+		return cadence.AnyResourceType
+	}
+
+	return cadence.AnyStructType
+}
@@ -0,0 +1,93 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/cmd"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
+)
+
+// checkProgram parses and checks the given program.
+// Errors are reported and exit the process.
+func checkProgram(code []byte, location common.Location) *sema.Checker {
+
+	codes := map[common.Location][]byte{}
+
+	program, must := cmd.PrepareProgram(code, location, codes)
+
+	standardLibraryValues := stdlib.DefaultScriptStandardLibraryValues(
+		&cmd.StandardLibraryHandler{},
+	)
+
+	checker, must := cmd.PrepareChecker(
+		program,
+		location,
+		codes,
+		nil,
+		standardLibraryValues,
+		must,
+	)
+
+	must(checker.Check())
+
+	return checker
+}
+
+// parseLiteral parses the given Cadence literal, which should have the given type.
+//
+// The type may refer to the composite types declared in the given code.
+func parseLiteral(literal string, typeString string, code []byte) (cadence.Value, error) {
+
+	checker := checkProgram(code, common.StringLocation("json-cdc"))
+
+	typeCode := []byte(typeString)
+	astType, errs := parser.ParseType(nil, typeCode, parser.Config{})
+	if len(errs) > 0 {
+		return nil, parser.Error{
+			Code:   typeCode,
+			Errors: errs,
+		}
+	}
+
+	// Resolve the type in the scope of the checked program,
+	// so it may refer to the declared composite types
+	ty := checker.ConvertType(astType)
+	if err := checker.CheckerError(); err != nil {
+		return nil, err
+	}
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		&interpreter.Config{
+			Storage: interpreter.NewInMemoryStorage(nil),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.ParseLiteral(literal, ty, inter)
+}
@@ -16,45 +16,396 @@
  * limitations under the License.
  */
 
+// json-cdc inspects and converts Cadence values in JSON-CDC and CCF.
+//
+// Usage:
+//
+//	json-cdc decode < value.json
+//	json-cdc encode -type '[Int]' [-code types.cdc] [-compact] '[1, 2, 3]'
+//	json-cdc convert -from json -to ccf [-encoding hex|base64|binary] < value.json
+//	json-cdc validate [-from json|ccf] < value.json
+//	json-cdc query [-from json|ccf] '.amount' < event.json
+//	json-cdc typescript [-address 0x1] Token.cdc > token.ts
+//
+// Values are read from standard input.
+// The typescript command generates TypeScript types and JSON-CDC codecs
+// for the declarations of the given contract.
+// CCF input and output is hex-encoded by default.
 package main
 
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/k0kubun/pp/v3"
 
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/ccf"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 )
 
+const (
+	formatJSON = "json"
+	formatCCF  = "ccf"
+)
+
+const (
+	encodingHex    = "hex"
+	encodingBase64 = "base64"
+	encodingBinary = "binary"
+)
+
+type command struct {
+	help    string
+	handler func(args []string) error
+}
+
+var commands = map[string]command{
+	"decode": {
+		help:    "print the Go representation of a JSON-CDC value",
+		handler: decode,
+	},
+	"encode": {
+		help:    "encode a value given in Cadence literal syntax",
+		handler: encode,
+	},
+	"convert": {
+		help:    "convert a value between JSON-CDC and CCF",
+		handler: convert,
+	},
+	"validate": {
+		help:    "check that a value is well-formed",
+		handler: validate,
+	},
+	"query": {
+		help:    "query a value with a jq expression",
+		handler: query,
+	},
+	"typescript": {
+		help:    "generate TypeScript types and JSON-CDC codecs for the declarations of a contract",
+		handler: typescript,
+	},
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		_, _ = fmt.Fprintf(os.Stderr, "expected command\n")
+		printAvailableCommands()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
-	switch command {
-	case "decode":
-		var data bytes.Buffer
-		reader := bufio.NewReader(os.Stdin)
-		_, err := io.Copy(&data, reader)
+	commandName := os.Args[1]
+	command, ok := commands[commandName]
+	if !ok {
+		_, _ = fmt.Fprintf(os.Stderr, "unsupported command: %s\n", commandName)
+		printAvailableCommands()
+		os.Exit(1)
+	}
+
+	err := command.handler(os.Args[2:])
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func printAvailableCommands() {
+	names := make([]string, 0, len(commands))
+	for name := range commands { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintf(os.Stderr, "available commands:\n")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(os.Stderr, "  %s\t%s\n", name, commands[name].help)
+	}
+}
+
+func decode(args []string) error {
+	flags := flag.NewFlagSet("decode", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	value, err := readValue(formatJSON, encodingHex)
+	if err != nil {
+		return err
+	}
+
+	_, _ = pp.Print(value)
+	return nil
+}
+
+func encode(args []string) error {
+	flags := flag.NewFlagSet("encode", flag.ExitOnError)
+	typeFlag := flags.String("type", "", "the type of the value, e.g. `[Int]`")
+	codeFlag := flags.String("code", "", "a Cadence file declaring the composite types used in the value")
+	toFlag := flags.String("to", formatJSON, "the output format: json or ccf")
+	encodingFlag := flags.String("encoding", encodingHex, "the encoding of CCF output: hex, base64, or binary")
+	compactFlag := flags.Bool("compact", false, "print JSON without insignificant whitespace")
+	_ = flags.Parse(args)
+
+	if *typeFlag == "" {
+		return fmt.Errorf("missing type")
+	}
+
+	var literal string
+	switch flags.NArg() {
+	case 0:
+		input, err := readInput()
+		if err != nil {
+			return err
+		}
+		literal = string(input)
+	case 1:
+		literal = flags.Arg(0)
+	default:
+		return fmt.Errorf("expected at most one literal, got %d", flags.NArg())
+	}
+
+	var code []byte
+	if *codeFlag != "" {
+		var err error
+		code, err = os.ReadFile(*codeFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	value, err := parseLiteral(literal, *typeFlag, code)
+	if err != nil {
+		return err
+	}
+
+	return writeValue(value, *toFlag, *encodingFlag, *compactFlag)
+}
+
+func convert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	fromFlag := flags.String("from", formatJSON, "the input format: json or ccf")
+	toFlag := flags.String("to", formatJSON, "the output format: json or ccf")
+	encodingFlag := flags.String("encoding", encodingHex, "the encoding of CCF input and output: hex, base64, or binary")
+	compactFlag := flags.Bool("compact", false, "print JSON without insignificant whitespace")
+	_ = flags.Parse(args)
+
+	value, err := readValue(*fromFlag, *encodingFlag)
+	if err != nil {
+		return err
+	}
+
+	return writeValue(value, *toFlag, *encodingFlag, *compactFlag)
+}
+
+func validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	fromFlag := flags.String("from", formatJSON, "the input format: json or ccf")
+	encodingFlag := flags.String("encoding", encodingHex, "the encoding of CCF input: hex, base64, or binary")
+	_ = flags.Parse(args)
+
+	value, err := readValue(*fromFlag, *encodingFlag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("valid %s value of type %s\n", *fromFlag, typeID(value))
+	return nil
+}
+
+func query(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	fromFlag := flags.String("from", formatJSON, "the input format: json or ccf")
+	encodingFlag := flags.String("encoding", encodingHex, "the encoding of CCF input: hex, base64, or binary")
+	compactFlag := flags.Bool("compact", false, "print results without insignificant whitespace")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected a query")
+	}
+
+	value, err := readValue(*fromFlag, *encodingFlag)
+	if err != nil {
+		return err
+	}
+
+	results, err := queryValue(value, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		var output []byte
+		if *compactFlag {
+			output, err = json.Marshal(result)
+		} else {
+			output, err = json.MarshalIndent(result, "", "  ")
+		}
 		if err != nil {
-			panic(err)
+			return err
 		}
+		fmt.Println(string(output))
+	}
+
+	return nil
+}
+
+func typescript(args []string) error {
+	flags := flag.NewFlagSet("typescript", flag.ExitOnError)
+	addressFlag := flags.String("address", "", "the address of the account the contract is deployed to, which determines the type IDs")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected a Cadence file")
+	}
+
+	path := flags.Arg(0)
 
-		value, err := jsoncdc.Decode(nil, data.Bytes())
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var location common.Location = common.StringLocation(path)
+	if *addressFlag != "" {
+		address, err := common.HexToAddress(*addressFlag)
 		if err != nil {
-			panic(err)
+			return err
 		}
 
-		_, _ = pp.Print(value)
+		location = common.AddressLocation{
+			Address: address,
+			Name:    strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		}
+	}
+
+	output, err := generateTypeScript(checkProgram(code, location))
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+func typeID(value cadence.Value) string {
+	ty := value.Type()
+	if ty == nil {
+		return "<unknown>"
+	}
+	return ty.ID()
+}
+
+func readInput() ([]byte, error) {
+	var data bytes.Buffer
+	reader := bufio.NewReader(os.Stdin)
+	_, err := io.Copy(&data, reader)
+	if err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+// readValue reads a value in the given format from standard input
+func readValue(format string, encoding string) (cadence.Value, error) {
+	input, err := readInput()
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case formatJSON:
+		return jsoncdc.Decode(nil, input)
+
+	case formatCCF:
+		data, err := decodeBinary(input, encoding)
+		if err != nil {
+			return nil, err
+		}
+		return ccf.Decode(nil, data)
 
 	default:
-		_, _ = fmt.Fprintf(os.Stderr, "unsupported command: %s", command)
-		os.Exit(1)
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeValue writes the value in the given format to standard output
+func writeValue(value cadence.Value, format string, encoding string, compact bool) error {
+	switch format {
+	case formatJSON:
+		data, err := jsoncdc.Encode(value)
+		if err != nil {
+			return err
+		}
+
+		// jsoncdc.Encode appends a newline
+		data = bytes.TrimSpace(data)
+
+		if !compact {
+			var indented bytes.Buffer
+			err = json.Indent(&indented, data, "", "  ")
+			if err != nil {
+				return err
+			}
+			data = indented.Bytes()
+		}
+
+		fmt.Println(string(data))
+		return nil
+
+	case formatCCF:
+		data, err := ccf.Encode(inferTypes(value))
+		if err != nil {
+			return err
+		}
+
+		return writeBinary(data, encoding)
+
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func decodeBinary(input []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case encodingHex:
+		trimmed := strings.TrimPrefix(strings.TrimSpace(string(input)), "0x")
+		return hex.DecodeString(trimmed)
+
+	case encodingBase64:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(input)))
+
+	case encodingBinary:
+		return input, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+}
+
+func writeBinary(data []byte, encoding string) error {
+	switch encoding {
+	case encodingHex:
+		fmt.Println(hex.EncodeToString(data))
+		return nil
+
+	case encodingBase64:
+		fmt.Println(base64.StdEncoding.EncodeToString(data))
+		return nil
+
+	case encodingBinary:
+		_, err := os.Stdout.Write(data)
+		return err
+
+	default:
+		return fmt.Errorf("unsupported encoding: %s", encoding)
 	}
 }
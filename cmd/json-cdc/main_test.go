@@ -0,0 +1,362 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/ccf"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+)
+
+func TestParseLiteral(t *testing.T) {
+
+	t.Run("built-in type", func(t *testing.T) {
+		value, err := parseLiteral(`[1, 2]`, `[UInt8]`, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.NewUInt8(1),
+				cadence.NewUInt8(2),
+			}).WithType(cadence.NewVariableSizedArrayType(cadence.UInt8Type)),
+			value,
+		)
+	})
+
+	t.Run("declared type", func(t *testing.T) {
+		const code = `
+          access(all) struct S {
+              access(all) let x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+        `
+
+		value, err := parseLiteral(`S(x: 42)`, `S`, []byte(code))
+		require.NoError(t, err)
+
+		composite, ok := value.(cadence.Struct)
+		require.True(t, ok)
+
+		assert.Equal(t, "S.json-cdc.S", composite.Type().ID())
+		assert.Equal(t, cadence.NewInt(42), cadence.SearchFieldByName(composite, "x"))
+	})
+
+	t.Run("invalid literal", func(t *testing.T) {
+		_, err := parseLiteral(`"foo"`, `Int`, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := parseLiteral(`1`, `[Int`, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestQueryValue(t *testing.T) {
+
+	t.Parallel()
+
+	eventType := cadence.NewEventType(
+		nil,
+		"A.0000000000000001.Token.Deposited",
+		[]cadence.Field{
+			{
+				Identifier: "amount",
+				Type:       cadence.UFix64Type,
+			},
+			{
+				Identifier: "to",
+				Type:       cadence.NewOptionalType(cadence.AddressType),
+			},
+			{
+				Identifier: "ids",
+				Type:       cadence.NewVariableSizedArrayType(cadence.UInt64Type),
+			},
+		},
+		nil,
+	)
+
+	amount, err := cadence.NewUFix64("1.5")
+	require.NoError(t, err)
+
+	event := cadence.NewEvent([]cadence.Value{
+		amount,
+		cadence.NewOptional(cadence.BytesToAddress([]byte{0x1})),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewUInt64(1),
+			cadence.NewUInt64(18446744073709551615),
+		}).WithType(cadence.NewVariableSizedArrayType(cadence.UInt64Type)),
+	}).WithType(eventType)
+
+	test := func(query string, expected ...any) {
+		t.Run(query, func(t *testing.T) {
+			t.Parallel()
+
+			results, err := queryValue(event, query)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, results)
+		})
+	}
+
+	test(".amount", "1.50000000")
+	test(".to", "0x0000000000000001")
+	test(".ids[0]", 1)
+	test(".ids | length", 2)
+	test(".ids[1] > .ids[0]", true)
+
+	t.Run("invalid query", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := queryValue(event, ".[")
+		require.Error(t, err)
+	})
+}
+
+func TestInferTypes(t *testing.T) {
+
+	t.Parallel()
+
+	// language=json
+	const encoded = `
+      {
+        "type": "Struct",
+        "value": {
+          "id": "S.test.S",
+          "fields": [
+            {
+              "name": "a",
+              "value": {
+                "type": "Array",
+                "value": [
+                  {"type": "Int", "value": "1"},
+                  {"type": "Int", "value": "2"}
+                ]
+              }
+            },
+            {
+              "name": "b",
+              "value": {
+                "type": "Dictionary",
+                "value": [
+                  {
+                    "key": {"type": "String", "value": "x"},
+                    "value": {"type": "Bool", "value": true}
+                  },
+                  {
+                    "key": {"type": "String", "value": "y"},
+                    "value": {"type": "String", "value": "z"}
+                  }
+                ]
+              }
+            }
+          ]
+        }
+      }
+    `
+
+	value, err := jsoncdc.Decode(nil, []byte(encoded))
+	require.NoError(t, err)
+
+	inferred := inferTypes(value)
+
+	fieldTypes := inferred.Type().(*cadence.StructType).FieldsMappedByName()
+	assert.Equal(t,
+		cadence.NewVariableSizedArrayType(cadence.IntType),
+		fieldTypes["a"],
+	)
+	assert.Equal(t,
+		cadence.NewDictionaryType(cadence.StringType, cadence.AnyStructType),
+		fieldTypes["b"],
+	)
+
+	// The inferred value can be converted to CCF
+	data, err := ccf.Encode(inferred)
+	require.NoError(t, err)
+
+	decoded, err := ccf.Decode(nil, data)
+	require.NoError(t, err)
+
+	reencoded, err := jsoncdc.Encode(decoded)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, encoded, string(reencoded))
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      access(all) contract Token {
+
+          access(all) enum Kind: UInt8 {
+              access(all) case fungible
+              access(all) case nonFungible
+          }
+
+          access(all) struct Info {
+              access(all) let id: UInt64
+              access(all) let balance: UFix64
+              access(all) let decimals: UInt8
+              access(all) let owner: Address?
+              access(all) let balances: {String: Int}
+              access(all) let kind: Kind
+              access(all) let next: Info?
+              access(all) let extra: AnyStruct
+
+              init() {
+                  self.id = 0
+                  self.balance = 0.0
+                  self.decimals = 8
+                  self.owner = nil
+                  self.balances = {}
+                  self.kind = Kind.fungible
+                  self.next = nil
+                  self.extra = 1
+              }
+          }
+
+          access(all) event Deposited(id: UInt64, to: Address?)
+
+          access(all) fun getInfo(id: UInt64, new: [Int8]): Info {
+              return Info()
+          }
+
+          access(self) fun secret() {}
+      }
+    `
+
+	location := common.AddressLocation{
+		Address: common.MustBytesToAddress([]byte{0x1}),
+		Name:    "Token",
+	}
+
+	output, err := generateTypeScript(checkProgram([]byte(code), location))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(output, typeScriptPrelude))
+
+	assert.Contains(t,
+		output,
+		`/** A.0000000000000001.Token.Kind */
+export const Token_Kind = {
+  fungible: 0,
+  nonFungible: 1,
+} as const;
+
+export type Token_Kind = (typeof Token_Kind)[keyof typeof Token_Kind];
+
+export const Token_KindCodec = enumCodec("A.0000000000000001.Token.Kind", numberCodec("UInt8")) as Codec<Token_Kind>;
+`,
+	)
+
+	assert.Contains(t,
+		output,
+		`/** A.0000000000000001.Token.Info */
+export interface Token_Info {
+  id: bigint;
+  balance: string;
+  decimals: number;
+  owner: string | null;
+  balances: Map<string, bigint>;
+  kind: Token_Kind;
+  next: Token_Info | null;
+  extra: JsonCdcValue;
+}
+
+export const Token_InfoCodec: Codec<Token_Info> = compositeCodec("Struct", "A.0000000000000001.Token.Info", {
+  id: bigintCodec("UInt64"),
+  balance: stringCodec("UFix64"),
+  decimals: numberCodec("UInt8"),
+  owner: optionalCodec(stringCodec("Address")),
+  balances: dictionaryCodec(stringCodec("String"), bigintCodec("Int")),
+  kind: lazyCodec(() => Token_KindCodec),
+  next: optionalCodec(lazyCodec(() => Token_InfoCodec)),
+  extra: anyCodec,
+});
+`,
+	)
+
+	assert.Contains(t,
+		output,
+		`export const Token_DepositedCodec: Codec<Token_Deposited> = compositeCodec("Event", "A.0000000000000001.Token.Deposited", {
+  id: bigintCodec("UInt64"),
+  to: optionalCodec(stringCodec("Address")),
+});
+`,
+	)
+
+	assert.Contains(t,
+		output,
+		`/** Token.getInfo(id: UInt64, new: [Int8]): A.0000000000000001.Token.Info */
+export const Token_getInfo = {
+  encodeArguments: (id: bigint, new_: Array<number>): JsonCdcValue[] => [bigintCodec("UInt64").encode(id), arrayCodec(numberCodec("Int8")).encode(new_)],
+  decodeResult: (json: JsonCdcValue): Token_Info => lazyCodec(() => Token_InfoCodec).decode(json),
+};
+`,
+	)
+
+	// Non-public functions are not generated
+	assert.NotContains(t, output, "secret")
+
+	// The static types are the JSON-CDC encoding of the types
+	staticType := jsoncdc.MustEncode(cadence.NewTypeValue(
+		cadence.NewEventType(
+			location,
+			"Token.Deposited",
+			[]cadence.Field{
+				{
+					Identifier: "id",
+					Type:       cadence.UInt64Type,
+				},
+				{
+					Identifier: "to",
+					Type:       cadence.NewOptionalType(cadence.AddressType),
+				},
+			},
+			// Event initializers are not exported
+			[]cadence.Parameter{},
+		),
+	))
+
+	var typeValue struct {
+		Value struct {
+			StaticType json.RawMessage `json:"staticType"`
+		} `json:"value"`
+	}
+	err = json.Unmarshal(staticType, &typeValue)
+	require.NoError(t, err)
+
+	assert.Contains(t,
+		output,
+		"export const Token_DepositedType: StaticType = "+string(typeValue.Value.StaticType)+";\n",
+	)
+}
@@ -0,0 +1,210 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/onflow/cadence"
+)
+
+// queryValue runs the given jq query against the simplified projection of the value
+func queryValue(value cadence.Value, queryString string) ([]any, error) {
+	query, err := gojq.Parse(queryString)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []any
+
+	iter := code.Run(project(value))
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+
+		results = append(results, v)
+	}
+
+	return results, nil
+}
+
+// project returns a simplified JSON representation of the given value,
+// which is easier to query than JSON-CDC:
+//
+//   - Optionals are unwrapped, nil and Void are null
+//   - Integers are numbers, fixed-point numbers are strings, as in JSON-CDC
+//   - Composites are objects of their fields
+//   - Dictionaries are objects, keyed by the string representation of the keys
+//   - Addresses, paths, and types are strings
+func project(value cadence.Value) any {
+	switch v := value.(type) {
+	case nil, cadence.Void:
+		return nil
+
+	case cadence.Optional:
+		return project(v.Value)
+
+	case cadence.Bool:
+		return bool(v)
+
+	case cadence.String:
+		return string(v)
+
+	case cadence.Character:
+		return string(v)
+
+	case cadence.Address:
+		return v.String()
+
+	case cadence.Int:
+		return projectInteger(v.Big())
+	case cadence.Int8:
+		return int(v)
+	case cadence.Int16:
+		return int(v)
+	case cadence.Int32:
+		return int(v)
+	case cadence.Int64:
+		return projectInteger(big.NewInt(int64(v)))
+	case cadence.Int128:
+		return projectInteger(v.Big())
+	case cadence.Int256:
+		return projectInteger(v.Big())
+	case cadence.UInt:
+		return projectInteger(v.Big())
+	case cadence.UInt8:
+		return int(v)
+	case cadence.UInt16:
+		return int(v)
+	case cadence.UInt32:
+		return projectInteger(new(big.Int).SetUint64(uint64(v)))
+	case cadence.UInt64:
+		return projectInteger(new(big.Int).SetUint64(uint64(v)))
+	case cadence.UInt128:
+		return projectInteger(v.Big())
+	case cadence.UInt256:
+		return projectInteger(v.Big())
+	case cadence.Word8:
+		return int(v)
+	case cadence.Word16:
+		return int(v)
+	case cadence.Word32:
+		return projectInteger(new(big.Int).SetUint64(uint64(v)))
+	case cadence.Word64:
+		return projectInteger(new(big.Int).SetUint64(uint64(v)))
+	case cadence.Word128:
+		return projectInteger(v.Big())
+	case cadence.Word256:
+		return projectInteger(v.Big())
+
+	case cadence.Fix64:
+		return v.String()
+	case cadence.UFix64:
+		return v.String()
+
+	case cadence.Array:
+		result := make([]any, len(v.Values))
+		for i, element := range v.Values {
+			result[i] = project(element)
+		}
+		return result
+
+	case cadence.Dictionary:
+		result := make(map[string]any, len(v.Pairs))
+		for _, pair := range v.Pairs {
+			result[projectKey(pair.Key)] = project(pair.Value)
+		}
+		return result
+
+	case cadence.Composite:
+		fields := cadence.FieldsMappedByName(v)
+		result := make(map[string]any, len(fields))
+		for name, field := range fields { //nolint:maprange
+			result[name] = project(field)
+		}
+		return result
+
+	case *cadence.InclusiveRange:
+		return map[string]any{
+			"start": project(v.Start),
+			"end":   project(v.End),
+			"step":  project(v.Step),
+		}
+
+	case cadence.Path:
+		return v.String()
+
+	case cadence.TypeValue:
+		if v.StaticType == nil {
+			return nil
+		}
+		return v.StaticType.ID()
+
+	case cadence.Capability:
+		result := map[string]any{
+			"id":      projectInteger(new(big.Int).SetUint64(uint64(v.ID))),
+			"address": v.Address.String(),
+		}
+		if v.BorrowType != nil {
+			result["borrowType"] = v.BorrowType.ID()
+		}
+		return result
+
+	case cadence.Function:
+		return v.FunctionType.ID()
+
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// projectInteger returns the integer as an int, if it fits, as gojq handles ints more efficiently
+func projectInteger(value *big.Int) any {
+	if value.IsInt64() {
+		i := value.Int64()
+		if int64(int(i)) == i {
+			return int(i)
+		}
+	}
+	return value
+}
+
+func projectKey(key cadence.Value) string {
+	switch key := key.(type) {
+	case cadence.String:
+		return string(key)
+	case cadence.Character:
+		return string(key)
+	default:
+		return fmt.Sprint(project(key))
+	}
+}
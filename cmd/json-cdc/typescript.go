@@ -0,0 +1,573 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/sema"
+)
+
+// generateTypeScript generates TypeScript type definitions and JSON-CDC codecs
+// for the composites, enums, events, and public functions declared in the checked program.
+//
+// Values are represented in TypeScript following the JSON-CDC encoding:
+//   - Integers of at most 32 bits are represented as numbers
+//   - Larger integers are represented as bigints, as they might not be safely representable as numbers
+//   - Fixed-point numbers are represented as decimal strings, as they are in JSON-CDC
+//   - Addresses are represented as hex strings with 0x prefix
+//   - Optionals are represented as the value or null
+//   - Dictionaries are represented as maps
+//   - Values of abstract types, e.g. AnyStruct, are left JSON-CDC encoded
+func generateTypeScript(checker *sema.Checker) (string, error) {
+	g := &typeScriptGenerator{
+		compositeNames: map[string]string{},
+		enumCases:      map[string][]string{},
+		exportResults:  map[sema.TypeID]cadence.Type{},
+	}
+
+	err := g.collectDeclarations(checker)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate the functions first, as their signatures may refer to further composite types
+
+	var functions strings.Builder
+	for _, function := range g.functions {
+		g.writeFunction(&functions, function)
+	}
+
+	var composites strings.Builder
+	// NOTE: composite types may be added while writing, e.g. built-in types of fields
+	for i := 0; i < len(g.composites); i++ {
+		err := g.writeComposite(&composites, g.composites[i])
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(typeScriptPrelude)
+	result.WriteString(composites.String())
+	result.WriteString(functions.String())
+	return result.String(), nil
+}
+
+type typeScriptFunction struct {
+	name          string
+	qualifiedName string
+	functionType  *cadence.FunctionType
+}
+
+type typeScriptGenerator struct {
+	composites []cadence.CompositeType
+	// compositeNames are the TypeScript names of the composite types, by type ID
+	compositeNames map[string]string
+	// enumCases are the names of the cases of the declared enums, by type ID
+	enumCases     map[string][]string
+	functions     []typeScriptFunction
+	exportResults map[sema.TypeID]cadence.Type
+}
+
+func (g *typeScriptGenerator) collectDeclarations(checker *sema.Checker) error {
+	program := checker.Program
+	elaboration := checker.Elaboration
+
+	for _, declaration := range program.FunctionDeclarations() {
+		g.collectFunction(elaboration, declaration, "")
+	}
+
+	var collectComposite func(declaration ast.CompositeLikeDeclaration) error
+	collectComposite = func(declaration ast.CompositeLikeDeclaration) error {
+		semaType := elaboration.CompositeDeclarationType(declaration)
+		compositeType, ok := runtime.ExportType(semaType, g.exportResults).(cadence.CompositeType)
+		if !ok {
+			return fmt.Errorf("unsupported composite type: %s", semaType.QualifiedString())
+		}
+
+		g.compositeName(compositeType)
+
+		members := declaration.DeclarationMembers()
+
+		if semaType.Kind == common.CompositeKindEnum {
+			enumCases := members.EnumCases()
+			caseNames := make([]string, len(enumCases))
+			for i, enumCase := range enumCases {
+				caseNames[i] = enumCase.Identifier.Identifier
+			}
+			g.enumCases[compositeType.ID()] = caseNames
+		}
+
+		for _, function := range members.Functions() {
+			g.collectFunction(elaboration, function, semaType.QualifiedIdentifier())
+		}
+
+		for _, nestedDeclaration := range members.Composites() {
+			err := collectComposite(nestedDeclaration)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, nestedDeclaration := range members.Attachments() {
+			err := collectComposite(nestedDeclaration)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, declaration := range program.CompositeDeclarations() {
+		err := collectComposite(declaration)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, declaration := range program.AttachmentDeclarations() {
+		err := collectComposite(declaration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectFunction collects the given function declaration, if it is public and not generic,
+// as the arguments of generic functions can not be described statically
+func (g *typeScriptGenerator) collectFunction(
+	elaboration *sema.Elaboration,
+	declaration *ast.FunctionDeclaration,
+	containerQualifiedIdentifier string,
+) {
+	if containerQualifiedIdentifier != "" && declaration.Access != ast.AccessAll {
+		return
+	}
+
+	semaFunctionType := elaboration.FunctionDeclarationFunctionType(declaration)
+	if semaFunctionType == nil || len(semaFunctionType.TypeParameters) > 0 {
+		return
+	}
+
+	functionType, ok := runtime.ExportType(semaFunctionType, g.exportResults).(*cadence.FunctionType)
+	if !ok {
+		return
+	}
+
+	qualifiedName := declaration.Identifier.Identifier
+	if containerQualifiedIdentifier != "" {
+		qualifiedName = containerQualifiedIdentifier + "." + qualifiedName
+	}
+
+	g.functions = append(
+		g.functions,
+		typeScriptFunction{
+			name:          typeScriptName(qualifiedName),
+			qualifiedName: qualifiedName,
+			functionType:  functionType,
+		},
+	)
+}
+
+// compositeName returns the TypeScript name of the given composite type,
+// and registers the type to be generated, if needed
+func (g *typeScriptGenerator) compositeName(t cadence.CompositeType) string {
+	typeID := t.ID()
+
+	name, ok := g.compositeNames[typeID]
+	if !ok {
+		name = typeScriptName(t.CompositeTypeQualifiedIdentifier())
+		g.compositeNames[typeID] = name
+		g.composites = append(g.composites, t)
+	}
+
+	return name
+}
+
+func (g *typeScriptGenerator) writeComposite(w *strings.Builder, t cadence.CompositeType) error {
+	name := g.compositeName(t)
+	typeID := t.ID()
+
+	staticType, err := json.Marshal(jsoncdc.PrepareType(t, jsoncdc.TypePreparationResults{}))
+	if err != nil {
+		return err
+	}
+
+	if enumType, ok := t.(*cadence.EnumType); ok {
+		g.writeEnum(w, name, enumType)
+	} else {
+		g.writeCompositeInterface(w, name, t)
+	}
+
+	_, _ = fmt.Fprintf(w, "/** The static type of %s, e.g. for `Type` values */\n", typeID)
+	_, _ = fmt.Fprintf(w, "export const %sType: StaticType = %s;\n\n", name, staticType)
+
+	return nil
+}
+
+func (g *typeScriptGenerator) writeEnum(w *strings.Builder, name string, t *cadence.EnumType) {
+	typeID := t.ID()
+	rawType := t.RawType
+
+	_, _ = fmt.Fprintf(w, "/** %s */\n", typeID)
+
+	caseNames, ok := g.enumCases[typeID]
+	if ok {
+		_, _ = fmt.Fprintf(w, "export const %s = {\n", name)
+		for i, caseName := range caseNames {
+			_, _ = fmt.Fprintf(w, "  %s: %s,\n", caseName, typeScriptIntegerLiteral(rawType, i))
+		}
+		w.WriteString("} as const;\n\n")
+
+		_, _ = fmt.Fprintf(w, "export type %[1]s = (typeof %[1]s)[keyof typeof %[1]s];\n\n", name)
+	} else {
+		// The cases of enums which are not declared in the program, e.g. built-in enums, are unknown
+		_, _ = fmt.Fprintf(w, "export type %s = %s;\n\n", name, g.typeScriptType(rawType))
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"export const %[1]sCodec = enumCodec(%[2]q, %[3]s) as Codec<%[1]s>;\n\n",
+		name,
+		typeID,
+		g.codec(rawType),
+	)
+}
+
+func (g *typeScriptGenerator) writeCompositeInterface(w *strings.Builder, name string, t cadence.CompositeType) {
+	typeID := t.ID()
+	fields := getCompositeTypeFields(t)
+
+	_, _ = fmt.Fprintf(w, "/** %s */\n", typeID)
+	_, _ = fmt.Fprintf(w, "export interface %s {\n", name)
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(w, "  %s: %s;\n", field.Identifier, g.typeScriptType(field.Type))
+	}
+	w.WriteString("}\n\n")
+
+	_, _ = fmt.Fprintf(
+		w,
+		"export const %[1]sCodec: Codec<%[1]s> = compositeCodec(%[2]q, %[3]q, {\n",
+		name,
+		compositeTypeString(t),
+		typeID,
+	)
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(w, "  %s: %s,\n", field.Identifier, g.codec(field.Type))
+	}
+	w.WriteString("});\n\n")
+}
+
+func (g *typeScriptGenerator) writeFunction(w *strings.Builder, function typeScriptFunction) {
+	functionType := function.functionType
+
+	parameters := make([]string, len(functionType.Parameters))
+	signatureParameters := make([]string, len(functionType.Parameters))
+	arguments := make([]string, len(functionType.Parameters))
+
+	for i, parameter := range functionType.Parameters {
+		parameterName := typeScriptParameterName(parameter.Identifier)
+
+		signatureParameters[i] = fmt.Sprintf("%s: %s", parameter.Identifier, parameter.Type.ID())
+		parameters[i] = fmt.Sprintf("%s: %s", parameterName, g.typeScriptType(parameter.Type))
+		arguments[i] = fmt.Sprintf("%s.encode(%s)", g.codec(parameter.Type), parameterName)
+	}
+
+	returnType := functionType.ReturnType
+	if returnType == nil {
+		returnType = cadence.VoidType
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"/** %s(%s): %s */\n",
+		function.qualifiedName,
+		strings.Join(signatureParameters, ", "),
+		returnType.ID(),
+	)
+	_, _ = fmt.Fprintf(w, "export const %s = {\n", function.name)
+	_, _ = fmt.Fprintf(
+		w,
+		"  encodeArguments: (%s): JsonCdcValue[] => [%s],\n",
+		strings.Join(parameters, ", "),
+		strings.Join(arguments, ", "),
+	)
+	_, _ = fmt.Fprintf(
+		w,
+		"  decodeResult: (json: JsonCdcValue): %s => %s.decode(json),\n",
+		g.typeScriptType(returnType),
+		g.codec(returnType),
+	)
+	w.WriteString("};\n\n")
+}
+
+// typeScriptType returns the TypeScript type of the values of the given Cadence type
+func (g *typeScriptGenerator) typeScriptType(t cadence.Type) string {
+	switch t {
+	case cadence.VoidType:
+		return "null"
+
+	case cadence.BoolType:
+		return "boolean"
+
+	case cadence.StringType,
+		cadence.CharacterType,
+		cadence.AddressType,
+		cadence.Fix64Type,
+		cadence.UFix64Type:
+
+		return "string"
+
+	case cadence.Int8Type,
+		cadence.Int16Type,
+		cadence.Int32Type,
+		cadence.UInt8Type,
+		cadence.UInt16Type,
+		cadence.UInt32Type,
+		cadence.Word8Type,
+		cadence.Word16Type,
+		cadence.Word32Type:
+
+		return "number"
+
+	case cadence.IntType,
+		cadence.Int64Type,
+		cadence.Int128Type,
+		cadence.Int256Type,
+		cadence.UIntType,
+		cadence.UInt64Type,
+		cadence.UInt128Type,
+		cadence.UInt256Type,
+		cadence.Word64Type,
+		cadence.Word128Type,
+		cadence.Word256Type:
+
+		return "bigint"
+
+	case cadence.PathType,
+		cadence.CapabilityPathType,
+		cadence.StoragePathType,
+		cadence.PublicPathType,
+		cadence.PrivatePathType:
+
+		return "Path"
+
+	case cadence.MetaType:
+		return "StaticType"
+	}
+
+	switch t := t.(type) {
+	case *cadence.OptionalType:
+		return g.typeScriptType(t.Type) + " | null"
+
+	case cadence.ArrayType:
+		return fmt.Sprintf("Array<%s>", g.typeScriptType(t.Element()))
+
+	case *cadence.DictionaryType:
+		return fmt.Sprintf(
+			"Map<%s, %s>",
+			g.typeScriptType(t.KeyType),
+			g.typeScriptType(t.ElementType),
+		)
+
+	case *cadence.CapabilityType:
+		return "Capability"
+
+	case cadence.CompositeType:
+		return g.compositeName(t)
+
+	default:
+		return "JsonCdcValue"
+	}
+}
+
+// codec returns the TypeScript expression for the codec of values of the given Cadence type
+func (g *typeScriptGenerator) codec(t cadence.Type) string {
+	switch t {
+	case cadence.VoidType:
+		return "voidCodec"
+
+	case cadence.BoolType:
+		return "boolCodec"
+
+	case cadence.PathType,
+		cadence.CapabilityPathType,
+		cadence.StoragePathType,
+		cadence.PublicPathType,
+		cadence.PrivatePathType:
+
+		return "pathCodec"
+
+	case cadence.MetaType:
+		return "typeCodec"
+	}
+
+	switch g.typeScriptType(t) {
+	case "string":
+		return fmt.Sprintf("stringCodec(%q)", t.ID())
+	case "number":
+		return fmt.Sprintf("numberCodec(%q)", t.ID())
+	case "bigint":
+		return fmt.Sprintf("bigintCodec(%q)", t.ID())
+	}
+
+	switch t := t.(type) {
+	case *cadence.OptionalType:
+		return fmt.Sprintf("optionalCodec(%s)", g.codec(t.Type))
+
+	case cadence.ArrayType:
+		return fmt.Sprintf("arrayCodec(%s)", g.codec(t.Element()))
+
+	case *cadence.DictionaryType:
+		return fmt.Sprintf(
+			"dictionaryCodec(%s, %s)",
+			g.codec(t.KeyType),
+			g.codec(t.ElementType),
+		)
+
+	case *cadence.CapabilityType:
+		return "capabilityCodec"
+
+	case cadence.CompositeType:
+		// Composite codecs are referenced lazily,
+		// as they may be declared later, or be recursive
+		return fmt.Sprintf("lazyCodec(() => %sCodec)", g.compositeName(t))
+
+	default:
+		return "anyCodec"
+	}
+}
+
+// compositeTypeString returns the JSON-CDC value type of the values of the given composite type
+func compositeTypeString(t cadence.CompositeType) string {
+	switch t.(type) {
+	case *cadence.StructType:
+		return "Struct"
+	case *cadence.ResourceType:
+		return "Resource"
+	case *cadence.EventType:
+		return "Event"
+	case *cadence.ContractType:
+		return "Contract"
+	case *cadence.EnumType:
+		return "Enum"
+	case *cadence.AttachmentType:
+		return "Attachment"
+	default:
+		panic(fmt.Errorf("unsupported composite type: %T", t))
+	}
+}
+
+// typeScriptIntegerLiteral returns the TypeScript literal for the given integer,
+// as a value of the TypeScript representation of the given Cadence integer type
+func typeScriptIntegerLiteral(t cadence.Type, value int) string {
+	switch t {
+	case cadence.Int8Type,
+		cadence.Int16Type,
+		cadence.Int32Type,
+		cadence.UInt8Type,
+		cadence.UInt16Type,
+		cadence.UInt32Type,
+		cadence.Word8Type,
+		cadence.Word16Type,
+		cadence.Word32Type:
+
+		return fmt.Sprint(value)
+
+	default:
+		return fmt.Sprintf("%dn", value)
+	}
+}
+
+// typeScriptName returns the TypeScript name for the given qualified identifier,
+// e.g. `Token_Vault` for `Token.Vault`
+func typeScriptName(qualifiedIdentifier string) string {
+	return strings.ReplaceAll(qualifiedIdentifier, ".", "_")
+}
+
+// typeScriptReservedWords are the reserved words of TypeScript in strict mode,
+// which are not valid TypeScript parameter names
+var typeScriptReservedWords = map[string]struct{}{
+	"arguments":  {},
+	"await":      {},
+	"break":      {},
+	"case":       {},
+	"catch":      {},
+	"class":      {},
+	"const":      {},
+	"continue":   {},
+	"debugger":   {},
+	"default":    {},
+	"delete":     {},
+	"do":         {},
+	"else":       {},
+	"enum":       {},
+	"eval":       {},
+	"export":     {},
+	"extends":    {},
+	"false":      {},
+	"finally":    {},
+	"for":        {},
+	"function":   {},
+	"if":         {},
+	"implements": {},
+	"import":     {},
+	"in":         {},
+	"instanceof": {},
+	"interface":  {},
+	"let":        {},
+	"new":        {},
+	"null":       {},
+	"package":    {},
+	"private":    {},
+	"protected":  {},
+	"public":     {},
+	"return":     {},
+	"static":     {},
+	"super":      {},
+	"switch":     {},
+	"this":       {},
+	"throw":      {},
+	"true":       {},
+	"try":        {},
+	"typeof":     {},
+	"var":        {},
+	"void":       {},
+	"while":      {},
+	"with":       {},
+	"yield":      {},
+}
+
+func typeScriptParameterName(identifier string) string {
+	if _, ok := typeScriptReservedWords[identifier]; ok {
+		return identifier + "_"
+	}
+	return identifier
+}
@@ -0,0 +1,256 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+// typeScriptPrelude declares the types and codecs used by the generated TypeScript code
+const typeScriptPrelude = `// Code generated by json-cdc typescript. DO NOT EDIT.
+
+/** A JSON-CDC encoded value */
+export type JsonCdcValue = {
+  type: string;
+  value?: any;
+};
+
+/** A JSON-CDC encoded static type, e.g. the value of a Type value */
+export type StaticType = {
+  kind: string;
+  [key: string]: unknown;
+};
+
+export type Path = {
+  domain: "storage" | "public" | "private";
+  identifier: string;
+};
+
+export type Capability = {
+  id: bigint;
+  address: string;
+  borrowType: StaticType;
+};
+
+/** Encodes values of type T to JSON-CDC, and decodes them from JSON-CDC */
+export interface Codec<T> {
+  encode(value: T): JsonCdcValue;
+  decode(json: JsonCdcValue): T;
+}
+
+function expectType(json: JsonCdcValue, type: string): any {
+  if (json.type !== type) {
+    throw new Error("expected JSON-CDC value of type " + type + ", got " + json.type);
+  }
+  return json.value;
+}
+
+function expectTypeID(value: { id: string }, id: string) {
+  if (value.id !== id) {
+    throw new Error("expected JSON-CDC value of type " + id + ", got " + value.id);
+  }
+}
+
+export const voidCodec: Codec<null> = {
+  encode: () => ({ type: "Void" }),
+  decode: (json) => {
+    expectType(json, "Void");
+    return null;
+  },
+};
+
+export const boolCodec: Codec<boolean> = {
+  encode: (value) => ({ type: "Bool", value }),
+  decode: (json) => expectType(json, "Bool"),
+};
+
+/** Codec for strings, characters, addresses, and fixed-point numbers */
+export function stringCodec(type: string): Codec<string> {
+  return {
+    encode: (value) => ({ type, value }),
+    decode: (json) => expectType(json, type),
+  };
+}
+
+/** Codec for integers of at most 32 bits */
+export function numberCodec(type: string): Codec<number> {
+  return {
+    encode: (value) => ({ type, value: value.toString() }),
+    decode: (json) => Number(expectType(json, type)),
+  };
+}
+
+/** Codec for integers of more than 32 bits */
+export function bigintCodec(type: string): Codec<bigint> {
+  return {
+    encode: (value) => ({ type, value: value.toString() }),
+    decode: (json) => BigInt(expectType(json, type)),
+  };
+}
+
+export const pathCodec: Codec<Path> = {
+  encode: (value) => ({
+    type: "Path",
+    value: { domain: value.domain, identifier: value.identifier },
+  }),
+  decode: (json) => {
+    const value = expectType(json, "Path");
+    return { domain: value.domain, identifier: value.identifier };
+  },
+};
+
+export const typeCodec: Codec<StaticType> = {
+  encode: (staticType) => ({ type: "Type", value: { staticType } }),
+  decode: (json) => expectType(json, "Type").staticType,
+};
+
+export const capabilityCodec: Codec<Capability> = {
+  encode: (value) => ({
+    type: "Capability",
+    value: {
+      id: value.id.toString(),
+      address: value.address,
+      borrowType: value.borrowType,
+    },
+  }),
+  decode: (json) => {
+    const value = expectType(json, "Capability");
+    return {
+      id: BigInt(value.id),
+      address: value.address,
+      borrowType: value.borrowType,
+    };
+  },
+};
+
+/** Codec for values of abstract types, e.g. AnyStruct, which are left JSON-CDC encoded */
+export const anyCodec: Codec<JsonCdcValue> = {
+  encode: (value) => value,
+  decode: (json) => json,
+};
+
+export function optionalCodec<T>(codec: Codec<T>): Codec<T | null> {
+  return {
+    encode: (value) => ({
+      type: "Optional",
+      value: value === null ? null : codec.encode(value),
+    }),
+    decode: (json) => {
+      const value = expectType(json, "Optional");
+      return value === null ? null : codec.decode(value);
+    },
+  };
+}
+
+export function arrayCodec<T>(codec: Codec<T>): Codec<Array<T>> {
+  return {
+    encode: (values) => ({
+      type: "Array",
+      value: values.map((value) => codec.encode(value)),
+    }),
+    decode: (json) =>
+      expectType(json, "Array").map((value: JsonCdcValue) => codec.decode(value)),
+  };
+}
+
+export function dictionaryCodec<K, V>(
+  keyCodec: Codec<K>,
+  valueCodec: Codec<V>,
+): Codec<Map<K, V>> {
+  return {
+    encode: (entries) => ({
+      type: "Dictionary",
+      value: Array.from(entries, ([key, value]) => ({
+        key: keyCodec.encode(key),
+        value: valueCodec.encode(value),
+      })),
+    }),
+    decode: (json) =>
+      new Map(
+        expectType(json, "Dictionary").map(
+          (entry: { key: JsonCdcValue; value: JsonCdcValue }): [K, V] => [
+            keyCodec.decode(entry.key),
+            valueCodec.decode(entry.value),
+          ],
+        ),
+      ),
+  };
+}
+
+/** Codec which defers to the given codec, for types which are declared later, or are recursive */
+export function lazyCodec<T>(get: () => Codec<T>): Codec<T> {
+  return {
+    encode: (value) => get().encode(value),
+    decode: (json) => get().decode(json),
+  };
+}
+
+export function compositeCodec<T extends object>(
+  type: string,
+  id: string,
+  fields: { [K in keyof T]: Codec<T[K]> },
+): Codec<T> {
+  const names = Object.keys(fields) as Array<keyof T & string>;
+  return {
+    encode: (value) => ({
+      type,
+      value: {
+        id,
+        fields: names.map((name) => ({
+          name,
+          value: fields[name].encode(value[name]),
+        })),
+      },
+    }),
+    decode: (json) => {
+      const value = expectType(json, type);
+      expectTypeID(value, id);
+      const result: Partial<T> = {};
+      for (const field of value.fields) {
+        const name = field.name as keyof T;
+        const codec = fields[name];
+        if (codec !== undefined) {
+          result[name] = codec.decode(field.value);
+        }
+      }
+      return result as T;
+    },
+  };
+}
+
+export function enumCodec<T>(id: string, rawValueCodec: Codec<T>): Codec<T> {
+  return {
+    encode: (rawValue) => ({
+      type: "Enum",
+      value: {
+        id,
+        fields: [{ name: "rawValue", value: rawValueCodec.encode(rawValue) }],
+      },
+    }),
+    decode: (json) => {
+      const value = expectType(json, "Enum");
+      expectTypeID(value, id);
+      const field = value.fields.find(
+        (field: { name: string }) => field.name === "rawValue",
+      );
+      if (field === undefined) {
+        throw new Error("missing raw value of enum " + id);
+      }
+      return rawValueCodec.decode(field.value);
+    },
+  };
+}
+
+`
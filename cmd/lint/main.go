@@ -0,0 +1,236 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// lint reports security anti-patterns in Cadence files.
+//
+// Usage:
+//
+//	lint [-analyzers name,...] [-format text|json|sarif] [-list] path...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/pretty"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/diagnostics"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+var analyzersFlag = flag.String("analyzers", "", "comma-separated names of the analyzers to run (default: all)")
+var formatFlag = flag.String("format", formatText, "output format: text, json, or sarif")
+var listFlag = flag.Bool("list", false, "list the available analyzers")
+
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+func main() {
+	flag.Parse()
+
+	if *listFlag {
+		for _, name := range lint.AnalyzerNames() {
+			fmt.Printf("%s\t%s\n", name, lint.Analyzers[name].Description)
+		}
+		return
+	}
+
+	names, err := selectAnalyzers(*analyzersFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var out output
+	switch *formatFlag {
+	case formatText:
+		out = newTextOutput()
+	case formatJSON:
+		out = newJSONOutput()
+	case formatSARIF:
+		out = newSARIFOutput(names)
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported format: %s\n", *formatFlag)
+		os.Exit(2)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "no files given")
+		os.Exit(2)
+	}
+
+	// Load errors are reported as diagnostics, unless the output is for humans
+	printErrors := *formatFlag == formatText
+
+	results, failed := run(paths, names, printErrors)
+
+	for _, diagnostic := range results {
+		out.Append(diagnostic)
+	}
+	out.End()
+
+	if failed || len(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+// selectAnalyzers returns the names of the analyzers with the given comma-separated names,
+// or the names of all analyzers if no names are given
+func selectAnalyzers(names string) ([]string, error) {
+	if names == "" {
+		return lint.AnalyzerNames(), nil
+	}
+
+	var result []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := lint.Analyzers[name]; !ok {
+			return nil, fmt.Errorf(
+				"unknown analyzer: %s. available analyzers: %s",
+				name,
+				strings.Join(lint.AnalyzerNames(), ", "),
+			)
+		}
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+// run runs the analyzers with the given names on the files at the given paths.
+//
+// Files which fail to parse or check cannot be analyzed.
+// Their errors are printed to standard error if printErrors is true,
+// otherwise they are returned as diagnostics.
+//
+// It returns the diagnostics, sorted by path and position,
+// and whether any file failed to load
+func run(
+	paths []string,
+	names []string,
+	printErrors bool,
+) (
+	results []diagnostics.Diagnostic,
+	failed bool,
+) {
+
+	analyzers := make([]*analysis.Analyzer, 0, len(names))
+	for _, name := range names {
+		analyzers = append(analyzers, lint.Analyzers[name])
+	}
+
+	config := &analysis.Config{
+		Mode: analysis.NeedTypes,
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			stringLocation, ok := location.(common.StringLocation)
+			if !ok {
+				return nil, fmt.Errorf("cannot import `%s`. only files are supported", location)
+			}
+			return os.ReadFile(string(stringLocation))
+		},
+	}
+
+	programs := &analysis.Programs{
+		Programs: map[common.Location]*analysis.Program{},
+	}
+
+	var mutex sync.Mutex
+
+	// Analyzers may be run concurrently
+	report := func(diagnostic analysis.Diagnostic) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		results = append(results, diagnostics.FromAnalysis(diagnostic))
+	}
+
+	for _, path := range paths {
+		location := common.NewStringLocation(nil, path)
+
+		err := programs.Load(config, location)
+		if err != nil {
+			codes := programCodes(location, programs)
+			if printErrors {
+				printError(err, location, codes)
+			} else {
+				results = append(results, diagnostics.FromError(err, location, codes)...)
+			}
+			failed = true
+			continue
+		}
+
+		programs.Get(location).Run(analyzers, report)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a := results[i]
+		b := results[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return startOffset(a) < startOffset(b)
+	})
+
+	return results, failed
+}
+
+func startOffset(diagnostic diagnostics.Diagnostic) int {
+	if diagnostic.Range == nil {
+		return 0
+	}
+	return diagnostic.Range.Start.Offset
+}
+
+// programCodes returns the codes of the loaded programs and of the given location, by location
+func programCodes(location common.Location, programs *analysis.Programs) map[common.Location][]byte {
+	codes := make(map[common.Location][]byte, len(programs.Programs))
+	for location, program := range programs.Programs { //nolint:maprange
+		codes[location] = program.Code
+	}
+
+	// The program failed to load, so its code is not available from the programs
+	if _, ok := codes[location]; !ok {
+		if code, readErr := os.ReadFile(location.String()); readErr == nil {
+			codes[location] = code
+		}
+	}
+
+	return codes
+}
+
+func printError(err error, location common.Location, codes map[common.Location][]byte) {
+	printErr := pretty.NewErrorPrettyPrinter(os.Stderr, true).
+		WithErrorCodes().
+		PrettyPrintError(err, location, codes)
+	if printErr != nil {
+		panic(printErr)
+	}
+}
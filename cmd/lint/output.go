@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/onflow/cadence/tools/diagnostics"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+type output interface {
+	Append(diagnostics.Diagnostic)
+	End()
+}
+
+// textOutput prints each diagnostic on a line, prefixed with its position
+type textOutput struct{}
+
+func newTextOutput() textOutput {
+	return textOutput{}
+}
+
+func (textOutput) Append(diagnostic diagnostics.Diagnostic) {
+	position := diagnostic.Location
+	if diagnostic.Range != nil {
+		position = fmt.Sprintf(
+			"%s:%d:%d",
+			position,
+			diagnostic.Range.Start.Line,
+			diagnostic.Range.Start.Column+1,
+		)
+	}
+
+	fmt.Printf("%s: %s (%s)\n", position, diagnostic.Message, diagnostic.Code)
+	if diagnostic.SecondaryMessage != "" {
+		fmt.Printf("\t%s\n", diagnostic.SecondaryMessage)
+	}
+}
+
+func (textOutput) End() {
+	// no-op
+}
+
+type jsonOutput struct {
+	diagnostics []diagnostics.Diagnostic
+}
+
+func newJSONOutput() *jsonOutput {
+	return &jsonOutput{}
+}
+
+func (j *jsonOutput) Append(diagnostic diagnostics.Diagnostic) {
+	j.diagnostics = append(j.diagnostics, diagnostic)
+}
+
+func (j *jsonOutput) End() {
+	err := diagnostics.WriteJSON(os.Stdout, j.diagnostics)
+	if err != nil {
+		panic(err)
+	}
+}
+
+type sarifOutput struct {
+	tool        diagnostics.Tool
+	diagnostics []diagnostics.Diagnostic
+}
+
+func newSARIFOutput(analyzerNames []string) *sarifOutput {
+	rules := make([]diagnostics.Rule, 0, len(analyzerNames))
+	for _, name := range analyzerNames {
+		rules = append(rules, diagnostics.Rule{
+			ID:          name,
+			Description: lint.Analyzers[name].Description,
+		})
+	}
+
+	return &sarifOutput{
+		tool: diagnostics.Tool{
+			Name:           "cadence-lint",
+			InformationURI: "https://github.com/onflow/cadence",
+			Rules:          rules,
+		},
+	}
+}
+
+func (s *sarifOutput) Append(diagnostic diagnostics.Diagnostic) {
+	s.diagnostics = append(s.diagnostics, diagnostic)
+}
+
+func (s *sarifOutput) End() {
+	err := diagnostics.WriteSARIF(os.Stdout, s.tool, s.diagnostics)
+	if err != nil {
+		panic(err)
+	}
+}
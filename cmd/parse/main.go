@@ -115,6 +115,7 @@ func (s fileOutput) Append(r result) {
 	if r.Error != nil {
 		location := common.NewStringLocation(nil, r.Path)
 		printErr := pretty.NewErrorPrettyPrinter(s.file, true).
+			WithErrorCodes().
 			PrettyPrintError(r.Error, location, map[common.Location][]byte{location: r.Code})
 		if printErr != nil {
 			panic(printErr)
@@ -0,0 +1,368 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package payloads reads and writes state dumps ("payloads")
+// in the JSON Lines format also used by cmd/decode-state-values,
+// and provides an in-memory atree.Ledger over them,
+// so offline tools can load account storage without any external services.
+package payloads
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+)
+
+// RegisterID identifies a register (payload) by its owner and key.
+type RegisterID struct {
+	Owner string
+	Key   string
+}
+
+// Ledger is an in-memory atree.Ledger, backed by registers read from a payloads file.
+type Ledger struct {
+	registers map[RegisterID][]byte
+	// slabIndices contains the last allocated slab index for each owner
+	slabIndices map[string]uint64
+}
+
+var _ atree.Ledger = &Ledger{}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		registers:   map[RegisterID][]byte{},
+		slabIndices: map[string]uint64{},
+	}
+}
+
+func (l *Ledger) GetValue(owner, key []byte) (value []byte, err error) {
+	return l.registers[RegisterID{Owner: string(owner), Key: string(key)}], nil
+}
+
+func (l *Ledger) SetValue(owner, key, value []byte) (err error) {
+	registerID := RegisterID{Owner: string(owner), Key: string(key)}
+	if len(value) == 0 {
+		delete(l.registers, registerID)
+	} else {
+		l.registers[registerID] = value
+	}
+	return nil
+}
+
+func (l *Ledger) ValueExists(owner, key []byte) (exists bool, err error) {
+	return len(l.registers[RegisterID{Owner: string(owner), Key: string(key)}]) > 0, nil
+}
+
+// AllocateSlabIndex allocates a slab index which is greater than
+// all slab indices of the owner's slab registers.
+func (l *Ledger) AllocateSlabIndex(owner []byte) (result atree.SlabIndex, err error) {
+	index, ok := l.slabIndices[string(owner)]
+	if !ok {
+		index = l.maxSlabIndex(string(owner))
+	}
+	index++
+	l.slabIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+func (l *Ledger) maxSlabIndex(owner string) uint64 {
+	var result uint64
+
+	// NOTE: iteration over map is safe,
+	// as the maximum does not depend on the order
+
+	for registerID := range l.registers { //nolint:maprange
+		if registerID.Owner != owner || !IsSlabKey(registerID.Key) {
+			continue
+		}
+		index := binary.BigEndian.Uint64([]byte(registerID.Key[1:]))
+		if index > result {
+			result = index
+		}
+	}
+
+	return result
+}
+
+// Count returns the number of registers.
+func (l *Ledger) Count() int {
+	return len(l.registers)
+}
+
+// Addresses returns the sorted addresses of all accounts which own registers.
+// Registers without an owner (global registers) are ignored.
+func (l *Ledger) Addresses() []common.Address {
+	owners := map[string]struct{}{}
+
+	for registerID := range l.registers { //nolint:maprange
+		if len(registerID.Owner) != common.AddressLength {
+			continue
+		}
+		owners[registerID.Owner] = struct{}{}
+	}
+
+	addresses := make([]common.Address, 0, len(owners))
+	for owner := range owners { //nolint:maprange
+		addresses = append(addresses, common.Address([]byte(owner)))
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Compare(addresses[j]) < 0
+	})
+
+	return addresses
+}
+
+// RegisterIDs returns the IDs of all registers, sorted by owner and key.
+func (l *Ledger) RegisterIDs() []RegisterID {
+	registerIDs := make([]RegisterID, 0, len(l.registers))
+	for registerID := range l.registers { //nolint:maprange
+		registerIDs = append(registerIDs, registerID)
+	}
+
+	sort.Slice(registerIDs, func(i, j int) bool {
+		a := registerIDs[i]
+		b := registerIDs[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Key < b.Key
+	})
+
+	return registerIDs
+}
+
+// ForEachRegister calls the given function for each register, sorted by owner and key.
+func (l *Ledger) ForEachRegister(f func(registerID RegisterID, value []byte) error) error {
+	for _, registerID := range l.RegisterIDs() {
+		err := f(registerID, l.registers[registerID])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// '$' + 8 byte index
+const slabKeyLength = 9
+
+// IsSlabKey returns true if the given register key is the key of a slab register.
+func IsSlabKey(key string) bool {
+	return len(key) == slabKeyLength && key[0] == '$'
+}
+
+// SlabID returns the slab ID for the given register ID,
+// or atree.SlabIDUndefined if the register is not a slab register.
+func (id RegisterID) SlabID() atree.SlabID {
+	if !IsSlabKey(id.Key) || len(id.Owner) != common.AddressLength {
+		return atree.SlabIDUndefined
+	}
+
+	var address atree.Address
+	copy(address[:], id.Owner)
+
+	var index atree.SlabIndex
+	copy(index[:], id.Key[1:])
+
+	return atree.NewSlabID(address, index)
+}
+
+// SlabRegisterID returns the ID of the register which stores the slab with the given ID.
+func SlabRegisterID(id atree.SlabID) RegisterID {
+	address := id.Address()
+	index := id.Index()
+
+	return RegisterID{
+		Owner: string(address[:]),
+		Key:   "$" + string(index[:]),
+	}
+}
+
+func decodeStorable(
+	decoder *cbor.StreamDecoder,
+	storableSlabStorageID atree.SlabID,
+	inlinedExtraData []atree.ExtraData,
+) (atree.Storable, error) {
+	return interpreter.DecodeStorable(decoder, storableSlabStorageID, inlinedExtraData, nil)
+}
+
+func decodeTypeInfo(decoder *cbor.StreamDecoder) (atree.TypeInfo, error) {
+	return interpreter.DecodeTypeInfo(decoder, nil)
+}
+
+// DecodeSlab decodes the data of the slab register with the given slab ID.
+func DecodeSlab(id atree.SlabID, data []byte) (atree.Slab, error) {
+	return atree.DecodeSlab(
+		id,
+		data,
+		interpreter.CBORDecMode,
+		decodeStorable,
+		decodeTypeInfo,
+	)
+}
+
+// Encoding
+
+type encodedKeyPart struct {
+	Value string
+}
+
+type encodedKey struct {
+	KeyParts []encodedKeyPart
+}
+
+type encodedEntry struct {
+	Value string
+	Key   encodedKey
+}
+
+// Key parts are either owner and key,
+// or owner, controller (unused), and key
+const (
+	minKeyPartCount = 2
+	maxKeyPartCount = 3
+)
+
+// gzip magic number
+var gzipHeader = []byte{0x1f, 0x8b}
+
+// Read reads payloads from the given reader, which may be gzip-compressed.
+// If addresses are given, only registers owned by these accounts are kept.
+func Read(reader io.Reader, addresses []common.Address) (*Ledger, error) {
+
+	bufferedReader := bufio.NewReader(reader)
+
+	header, err := bufferedReader.Peek(len(gzipHeader))
+	if err == nil && string(header) == string(gzipHeader) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		bufferedReader = bufio.NewReader(gzipReader)
+	}
+
+	filter := map[string]struct{}{}
+	for _, address := range addresses {
+		filter[string(address[:])] = struct{}{}
+	}
+
+	ledger := NewLedger()
+
+	decoder := json.NewDecoder(bufferedReader)
+
+	for line := 1; ; line++ {
+		var entry encodedEntry
+
+		err := decoder.Decode(&entry)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode payload %d: %w", line, err)
+		}
+
+		keyParts := entry.Key.KeyParts
+		switch len(keyParts) {
+		case 0:
+			// Ignore empty entries
+			continue
+		case minKeyPartCount, maxKeyPartCount:
+			break
+		default:
+			return nil, fmt.Errorf("invalid key parts for payload %d: %d", line, len(keyParts))
+		}
+
+		owner, err := hex.DecodeString(keyParts[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode owner of payload %d: %w", line, err)
+		}
+
+		key, err := hex.DecodeString(keyParts[len(keyParts)-1].Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key of payload %d: %w", line, err)
+		}
+
+		if len(filter) > 0 {
+			if _, ok := filter[string(owner)]; !ok {
+				continue
+			}
+		}
+
+		value, err := hex.DecodeString(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode value of payload %d: %w", line, err)
+		}
+
+		// Ignore empty registers
+		if len(value) == 0 {
+			continue
+		}
+
+		ledger.registers[RegisterID{
+			Owner: string(owner),
+			Key:   string(key),
+		}] = value
+	}
+
+	return ledger, nil
+}
+
+// ReadFile reads the payloads file at the given path. See Read.
+func ReadFile(path string, addresses []common.Address) (*Ledger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Read(file, addresses)
+}
+
+// Write writes all registers to the given writer, one JSON object per line,
+// sorted by owner and key.
+func (l *Ledger) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+
+	return l.ForEachRegister(func(registerID RegisterID, value []byte) error {
+		return encoder.Encode(encodedEntry{
+			Value: hex.EncodeToString(value),
+			Key: encodedKey{
+				KeyParts: []encodedKeyPart{
+					{Value: hex.EncodeToString([]byte(registerID.Owner))},
+					// controller
+					{Value: ""},
+					{Value: hex.EncodeToString([]byte(registerID.Key))},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,240 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package payloads
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+)
+
+func newTestLedger(t *testing.T, address common.Address) *Ledger {
+	ledger := NewLedger()
+
+	storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	storageMap := storage.GetDomainStorageMap(
+		inter,
+		address,
+		common.PathDomainStorage.StorageDomain(),
+		true,
+	)
+
+	// Store an array large enough to not be inlined
+
+	values := make([]interpreter.Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(
+			values,
+			interpreter.NewUnmeteredStringValue(fmt.Sprintf("value %d", i)),
+		)
+	}
+
+	array := interpreter.NewArrayValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		interpreter.NewVariableSizedStaticType(nil, interpreter.PrimitiveStaticTypeString),
+		address,
+		values...,
+	)
+
+	storageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("array"),
+		array,
+	)
+
+	const commitContractUpdates = false
+	err := storage.Commit(inter, commitContractUpdates)
+	require.NoError(t, err)
+
+	return ledger
+}
+
+func TestLedgerRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	ledger := newTestLedger(t, address)
+	require.Greater(t, ledger.Count(), 1)
+
+	var buffer bytes.Buffer
+	err := ledger.Write(&buffer)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		ledger.Count(),
+		strings.Count(buffer.String(), "\n"),
+	)
+
+	test := func(t *testing.T, decoded *Ledger) {
+		assert.Equal(t, ledger.registers, decoded.registers)
+		assert.Equal(t, []common.Address{address}, decoded.Addresses())
+
+		storage := runtime.NewStorage(decoded, nil, runtime.StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		storageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			false,
+		)
+		require.NotNil(t, storageMap)
+
+		value := storageMap.ReadValue(nil, interpreter.StringStorageMapKey("array"))
+		require.IsType(t, &interpreter.ArrayValue{}, value)
+		assert.Equal(t, 100, value.(*interpreter.ArrayValue).Count())
+
+		err = storage.CheckHealth()
+		require.NoError(t, err)
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		t.Parallel()
+
+		decoded, err := Read(bytes.NewReader(buffer.Bytes()), nil)
+		require.NoError(t, err)
+
+		test(t, decoded)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		t.Parallel()
+
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		_, err := writer.Write(buffer.Bytes())
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		decoded, err := Read(&compressed, nil)
+		require.NoError(t, err)
+
+		test(t, decoded)
+	})
+}
+
+func TestRead(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("two key parts", func(t *testing.T) {
+		t.Parallel()
+
+		const data = `{"Key":{"KeyParts":[{"Value":"0000000000000001"},{"Value":"666f6f"}]},"Value":"01"}`
+
+		ledger, err := Read(strings.NewReader(data), nil)
+		require.NoError(t, err)
+
+		value, err := ledger.GetValue([]byte{0, 0, 0, 0, 0, 0, 0, 1}, []byte("foo"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x1}, value)
+	})
+
+	t.Run("empty entries and values", func(t *testing.T) {
+		t.Parallel()
+
+		const data = `
+{"Key":{"KeyParts":[]},"Value":""}
+{"Key":{"KeyParts":[{"Value":"0000000000000001"},{"Value":""},{"Value":"666f6f"}]},"Value":""}
+`
+
+		ledger, err := Read(strings.NewReader(data), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, ledger.Count())
+	})
+
+	t.Run("address filter", func(t *testing.T) {
+		t.Parallel()
+
+		const data = `
+{"Key":{"KeyParts":[{"Value":"0000000000000001"},{"Value":""},{"Value":"666f6f"}]},"Value":"01"}
+{"Key":{"KeyParts":[{"Value":"0000000000000002"},{"Value":""},{"Value":"666f6f"}]},"Value":"02"}
+`
+
+		address := common.MustBytesToAddress([]byte{0x2})
+
+		ledger, err := Read(strings.NewReader(data), []common.Address{address})
+		require.NoError(t, err)
+		assert.Equal(t, []common.Address{address}, ledger.Addresses())
+	})
+
+	t.Run("invalid key parts", func(t *testing.T) {
+		t.Parallel()
+
+		const data = `{"Key":{"KeyParts":[{"Value":"0000000000000001"}]},"Value":"01"}`
+
+		_, err := Read(strings.NewReader(data), nil)
+		require.ErrorContains(t, err, "invalid key parts for payload 1")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+
+		const data = `{"Key":{"KeyParts":[{"Value":"0000000000000001"},{"Value":"666f6f"}]},"Value":"x"}`
+
+		_, err := Read(strings.NewReader(data), nil)
+		require.ErrorContains(t, err, "failed to decode value of payload 1")
+	})
+}
+
+func TestLedgerAllocateSlabIndex(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	ledger := newTestLedger(t, address)
+
+	var maxSlabIndex atree.SlabIndex
+	for _, registerID := range ledger.RegisterIDs() {
+		slabID := registerID.SlabID()
+		if slabID == atree.SlabIDUndefined {
+			continue
+		}
+		index := slabID.Index()
+		if bytes.Compare(index[:], maxSlabIndex[:]) > 0 {
+			maxSlabIndex = index
+		}
+	}
+	require.NotEqual(t, atree.SlabIndexUndefined, maxSlabIndex)
+
+	index, err := ledger.AllocateSlabIndex(address[:])
+	require.NoError(t, err)
+	assert.Equal(t, maxSlabIndex.Next(), index)
+
+	index, err = ledger.AllocateSlabIndex(address[:])
+	require.NoError(t, err)
+	assert.Equal(t, maxSlabIndex.Next().Next(), index)
+}
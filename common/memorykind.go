@@ -183,6 +183,7 @@ const (
 	MemoryKindVariableDeclaration
 	MemoryKindSpecialFunctionDeclaration
 	MemoryKindPragmaDeclaration
+	MemoryKindInvalidDeclaration
 
 	MemoryKindAssignmentStatement
 	MemoryKindBreakStatement
@@ -196,6 +197,7 @@ const (
 	MemoryKindSwitchStatement
 	MemoryKindWhileStatement
 	MemoryKindRemoveStatement
+	MemoryKindInvalidStatement
 
 	MemoryKindBooleanExpression
 	MemoryKindVoidExpression
@@ -221,6 +223,7 @@ const (
 	MemoryKindForceExpression
 	MemoryKindPathExpression
 	MemoryKindAttachExpression
+	MemoryKindInvalidExpression
 
 	MemoryKindConstantSizedType
 	MemoryKindDictionaryType
@@ -146,76 +146,79 @@ func _() {
 	_ = x[MemoryKindVariableDeclaration-135]
 	_ = x[MemoryKindSpecialFunctionDeclaration-136]
 	_ = x[MemoryKindPragmaDeclaration-137]
-	_ = x[MemoryKindAssignmentStatement-138]
-	_ = x[MemoryKindBreakStatement-139]
-	_ = x[MemoryKindContinueStatement-140]
-	_ = x[MemoryKindEmitStatement-141]
-	_ = x[MemoryKindExpressionStatement-142]
-	_ = x[MemoryKindForStatement-143]
-	_ = x[MemoryKindIfStatement-144]
-	_ = x[MemoryKindReturnStatement-145]
-	_ = x[MemoryKindSwapStatement-146]
-	_ = x[MemoryKindSwitchStatement-147]
-	_ = x[MemoryKindWhileStatement-148]
-	_ = x[MemoryKindRemoveStatement-149]
-	_ = x[MemoryKindBooleanExpression-150]
-	_ = x[MemoryKindVoidExpression-151]
-	_ = x[MemoryKindNilExpression-152]
-	_ = x[MemoryKindStringExpression-153]
-	_ = x[MemoryKindIntegerExpression-154]
-	_ = x[MemoryKindFixedPointExpression-155]
-	_ = x[MemoryKindArrayExpression-156]
-	_ = x[MemoryKindStringTemplateExpression-157]
-	_ = x[MemoryKindDictionaryExpression-158]
-	_ = x[MemoryKindIdentifierExpression-159]
-	_ = x[MemoryKindInvocationExpression-160]
-	_ = x[MemoryKindMemberExpression-161]
-	_ = x[MemoryKindIndexExpression-162]
-	_ = x[MemoryKindConditionalExpression-163]
-	_ = x[MemoryKindUnaryExpression-164]
-	_ = x[MemoryKindBinaryExpression-165]
-	_ = x[MemoryKindFunctionExpression-166]
-	_ = x[MemoryKindCastingExpression-167]
-	_ = x[MemoryKindCreateExpression-168]
-	_ = x[MemoryKindDestroyExpression-169]
-	_ = x[MemoryKindReferenceExpression-170]
-	_ = x[MemoryKindForceExpression-171]
-	_ = x[MemoryKindPathExpression-172]
-	_ = x[MemoryKindAttachExpression-173]
-	_ = x[MemoryKindConstantSizedType-174]
-	_ = x[MemoryKindDictionaryType-175]
-	_ = x[MemoryKindFunctionType-176]
-	_ = x[MemoryKindInstantiationType-177]
-	_ = x[MemoryKindNominalType-178]
-	_ = x[MemoryKindOptionalType-179]
-	_ = x[MemoryKindReferenceType-180]
-	_ = x[MemoryKindIntersectionType-181]
-	_ = x[MemoryKindVariableSizedType-182]
-	_ = x[MemoryKindPosition-183]
-	_ = x[MemoryKindRange-184]
-	_ = x[MemoryKindElaboration-185]
-	_ = x[MemoryKindActivation-186]
-	_ = x[MemoryKindActivationEntries-187]
-	_ = x[MemoryKindVariableSizedSemaType-188]
-	_ = x[MemoryKindConstantSizedSemaType-189]
-	_ = x[MemoryKindDictionarySemaType-190]
-	_ = x[MemoryKindOptionalSemaType-191]
-	_ = x[MemoryKindIntersectionSemaType-192]
-	_ = x[MemoryKindReferenceSemaType-193]
-	_ = x[MemoryKindEntitlementSemaType-194]
-	_ = x[MemoryKindEntitlementMapSemaType-195]
-	_ = x[MemoryKindEntitlementRelationSemaType-196]
-	_ = x[MemoryKindCapabilitySemaType-197]
-	_ = x[MemoryKindInclusiveRangeSemaType-198]
-	_ = x[MemoryKindOrderedMap-199]
-	_ = x[MemoryKindOrderedMapEntryList-200]
-	_ = x[MemoryKindOrderedMapEntry-201]
-	_ = x[MemoryKindLast-202]
+	_ = x[MemoryKindInvalidDeclaration-138]
+	_ = x[MemoryKindAssignmentStatement-139]
+	_ = x[MemoryKindBreakStatement-140]
+	_ = x[MemoryKindContinueStatement-141]
+	_ = x[MemoryKindEmitStatement-142]
+	_ = x[MemoryKindExpressionStatement-143]
+	_ = x[MemoryKindForStatement-144]
+	_ = x[MemoryKindIfStatement-145]
+	_ = x[MemoryKindReturnStatement-146]
+	_ = x[MemoryKindSwapStatement-147]
+	_ = x[MemoryKindSwitchStatement-148]
+	_ = x[MemoryKindWhileStatement-149]
+	_ = x[MemoryKindRemoveStatement-150]
+	_ = x[MemoryKindInvalidStatement-151]
+	_ = x[MemoryKindBooleanExpression-152]
+	_ = x[MemoryKindVoidExpression-153]
+	_ = x[MemoryKindNilExpression-154]
+	_ = x[MemoryKindStringExpression-155]
+	_ = x[MemoryKindIntegerExpression-156]
+	_ = x[MemoryKindFixedPointExpression-157]
+	_ = x[MemoryKindArrayExpression-158]
+	_ = x[MemoryKindStringTemplateExpression-159]
+	_ = x[MemoryKindDictionaryExpression-160]
+	_ = x[MemoryKindIdentifierExpression-161]
+	_ = x[MemoryKindInvocationExpression-162]
+	_ = x[MemoryKindMemberExpression-163]
+	_ = x[MemoryKindIndexExpression-164]
+	_ = x[MemoryKindConditionalExpression-165]
+	_ = x[MemoryKindUnaryExpression-166]
+	_ = x[MemoryKindBinaryExpression-167]
+	_ = x[MemoryKindFunctionExpression-168]
+	_ = x[MemoryKindCastingExpression-169]
+	_ = x[MemoryKindCreateExpression-170]
+	_ = x[MemoryKindDestroyExpression-171]
+	_ = x[MemoryKindReferenceExpression-172]
+	_ = x[MemoryKindForceExpression-173]
+	_ = x[MemoryKindPathExpression-174]
+	_ = x[MemoryKindAttachExpression-175]
+	_ = x[MemoryKindInvalidExpression-176]
+	_ = x[MemoryKindConstantSizedType-177]
+	_ = x[MemoryKindDictionaryType-178]
+	_ = x[MemoryKindFunctionType-179]
+	_ = x[MemoryKindInstantiationType-180]
+	_ = x[MemoryKindNominalType-181]
+	_ = x[MemoryKindOptionalType-182]
+	_ = x[MemoryKindReferenceType-183]
+	_ = x[MemoryKindIntersectionType-184]
+	_ = x[MemoryKindVariableSizedType-185]
+	_ = x[MemoryKindPosition-186]
+	_ = x[MemoryKindRange-187]
+	_ = x[MemoryKindElaboration-188]
+	_ = x[MemoryKindActivation-189]
+	_ = x[MemoryKindActivationEntries-190]
+	_ = x[MemoryKindVariableSizedSemaType-191]
+	_ = x[MemoryKindConstantSizedSemaType-192]
+	_ = x[MemoryKindDictionarySemaType-193]
+	_ = x[MemoryKindOptionalSemaType-194]
+	_ = x[MemoryKindIntersectionSemaType-195]
+	_ = x[MemoryKindReferenceSemaType-196]
+	_ = x[MemoryKindEntitlementSemaType-197]
+	_ = x[MemoryKindEntitlementMapSemaType-198]
+	_ = x[MemoryKindEntitlementRelationSemaType-199]
+	_ = x[MemoryKindCapabilitySemaType-200]
+	_ = x[MemoryKindInclusiveRangeSemaType-201]
+	_ = x[MemoryKindOrderedMap-202]
+	_ = x[MemoryKindOrderedMapEntryList-203]
+	_ = x[MemoryKindOrderedMapEntry-204]
+	_ = x[MemoryKindLast-205]
 }
 
-const _MemoryKind_name = "UnknownAddressValueStringValueCharacterValueNumberValueArrayValueBaseDictionaryValueBaseCompositeValueBaseSimpleCompositeValueBaseOptionalValueTypeValuePathValueCapabilityValueStorageReferenceValueEphemeralReferenceValueInterpretedFunctionValueHostFunctionValueBoundFunctionValueBigIntSimpleCompositeValuePublishedValueStorageCapabilityControllerValueAccountCapabilityControllerValueAtreeArrayDataSlabAtreeArrayMetaDataSlabAtreeArrayElementOverheadAtreeMapDataSlabAtreeMapMetaDataSlabAtreeMapElementOverheadAtreeMapPreAllocatedElementAtreeEncodedSlabPrimitiveStaticTypeCompositeStaticTypeInterfaceStaticTypeVariableSizedStaticTypeConstantSizedStaticTypeDictionaryStaticTypeInclusiveRangeStaticTypeOptionalStaticTypeIntersectionStaticTypeEntitlementSetStaticAccessEntitlementMapStaticAccessReferenceStaticTypeCapabilityStaticTypeFunctionStaticTypeCadenceVoidValueCadenceOptionalValueCadenceBoolValueCadenceStringValueCadenceCharacterValueCadenceAddressValueCadenceIntValueCadenceNumberValueCadenceArrayValueBaseCadenceArrayValueLengthCadenceDictionaryValueCadenceInclusiveRangeValueCadenceKeyValuePairCadenceStructValueBaseCadenceStructValueSizeCadenceResourceValueBaseCadenceAttachmentValueBaseCadenceResourceValueSizeCadenceAttachmentValueSizeCadenceEventValueBaseCadenceEventValueSizeCadenceContractValueBaseCadenceContractValueSizeCadenceEnumValueBaseCadenceEnumValueSizeCadencePathValueCadenceTypeValueCadenceCapabilityValueCadenceDeprecatedPathCapabilityTypeCadenceFunctionValueCadenceOptionalTypeCadenceDeprecatedRestrictedTypeCadenceVariableSizedArrayTypeCadenceConstantSizedArrayTypeCadenceDictionaryTypeCadenceInclusiveRangeTypeCadenceFieldCadenceParameterCadenceTypeParameterCadenceStructTypeCadenceResourceTypeCadenceAttachmentTypeCadenceEventTypeCadenceContractTypeCadenceStructInterfaceTypeCadenceResourceInterfaceTypeCadenceContractInterfaceTypeCadenceFunctionTypeCadenceEntitlementSetAccessCadenceEntitlementMapAccessCadenceReferenceTypeCadenceIntersectionTypeCadenceCapabilityTypeCadenceEnumTypeRawStringAddressLocationBytesVariableCompositeTypeInfoCompositeFieldInvocationStorageMapStorageKeyTypeTokenErrorTokenSpaceTokenProgramIdentifierArgumentBlockFunctionBlockParameterParameterListTypeParameterTypeParameterListTransferMembersTypeAnnotationDictionaryEntryFunctionDeclarationCompositeDeclarationAttachmentDeclarationInterfaceDeclarationEntitlementDeclarationEntitlementMappingElementEntitlementMappingDeclarationEnumCaseDeclarationFieldDeclarationTransactionDeclarationImportDeclarationVariableDeclarationSpecialFunctionDeclarationPragmaDeclarationAssignmentStatementBreakStatementContinueStatementEmitStatementExpressionStatementForStatementIfStatementReturnStatementSwapStatementSwitchStatementWhileStatementRemoveStatementBooleanExpressionVoidExpressionNilExpressionStringExpressionIntegerExpressionFixedPointExpressionArrayExpressionStringTemplateExpressionDictionaryExpressionIdentifierExpressionInvocationExpressionMemberExpressionIndexExpressionConditionalExpressionUnaryExpressionBinaryExpressionFunctionExpressionCastingExpressionCreateExpressionDestroyExpressionReferenceExpressionForceExpressionPathExpressionAttachExpressionConstantSizedTypeDictionaryTypeFunctionTypeInstantiationTypeNominalTypeOptionalTypeReferenceTypeIntersectionTypeVariableSizedTypePositionRangeElaborationActivationActivationEntriesVariableSizedSemaTypeConstantSizedSemaTypeDictionarySemaTypeOptionalSemaTypeIntersectionSemaTypeReferenceSemaTypeEntitlementSemaTypeEntitlementMapSemaTypeEntitlementRelationSemaTypeCapabilitySemaTypeInclusiveRangeSemaTypeOrderedMapOrderedMapEntryListOrderedMapEntryLast"
+const _MemoryKind_name = "UnknownAddressValueStringValueCharacterValueNumberValueArrayValueBaseDictionaryValueBaseCompositeValueBaseSimpleCompositeValueBaseOptionalValueTypeValuePathValueCapabilityValueStorageReferenceValueEphemeralReferenceValueInterpretedFunctionValueHostFunctionValueBoundFunctionValueBigIntSimpleCompositeValuePublishedValueStorageCapabilityControllerValueAccountCapabilityControllerValueAtreeArrayDataSlabAtreeArrayMetaDataSlabAtreeArrayElementOverheadAtreeMapDataSlabAtreeMapMetaDataSlabAtreeMapElementOverheadAtreeMapPreAllocatedElementAtreeEncodedSlabPrimitiveStaticTypeCompositeStaticTypeInterfaceStaticTypeVariableSizedStaticTypeConstantSizedStaticTypeDictionaryStaticTypeInclusiveRangeStaticTypeOptionalStaticTypeIntersectionStaticTypeEntitlementSetStaticAccessEntitlementMapStaticAccessReferenceStaticTypeCapabilityStaticTypeFunctionStaticTypeCadenceVoidValueCadenceOptionalValueCadenceBoolValueCadenceStringValueCadenceCharacterValueCadenceAddressValueCadenceIntValueCadenceNumberValueCadenceArrayValueBaseCadenceArrayValueLengthCadenceDictionaryValueCadenceInclusiveRangeValueCadenceKeyValuePairCadenceStructValueBaseCadenceStructValueSizeCadenceResourceValueBaseCadenceAttachmentValueBaseCadenceResourceValueSizeCadenceAttachmentValueSizeCadenceEventValueBaseCadenceEventValueSizeCadenceContractValueBaseCadenceContractValueSizeCadenceEnumValueBaseCadenceEnumValueSizeCadencePathValueCadenceTypeValueCadenceCapabilityValueCadenceDeprecatedPathCapabilityTypeCadenceFunctionValueCadenceOptionalTypeCadenceDeprecatedRestrictedTypeCadenceVariableSizedArrayTypeCadenceConstantSizedArrayTypeCadenceDictionaryTypeCadenceInclusiveRangeTypeCadenceFieldCadenceParameterCadenceTypeParameterCadenceStructTypeCadenceResourceTypeCadenceAttachmentTypeCadenceEventTypeCadenceContractTypeCadenceStructInterfaceTypeCadenceResourceInterfaceTypeCadenceContractInterfaceTypeCadenceFunctionTypeCadenceEntitlementSetAccessCadenceEntitlementMapAccessCadenceReferenceTypeCadenceIntersectionTypeCadenceCapabilityTypeCadenceEnumTypeRawStringAddressLocationBytesVariableCompositeTypeInfoCompositeFieldInvocationStorageMapStorageKeyTypeTokenErrorTokenSpaceTokenProgramIdentifierArgumentBlockFunctionBlockParameterParameterListTypeParameterTypeParameterListTransferMembersTypeAnnotationDictionaryEntryFunctionDeclarationCompositeDeclarationAttachmentDeclarationInterfaceDeclarationEntitlementDeclarationEntitlementMappingElementEntitlementMappingDeclarationEnumCaseDeclarationFieldDeclarationTransactionDeclarationImportDeclarationVariableDeclarationSpecialFunctionDeclarationPragmaDeclarationInvalidDeclarationAssignmentStatementBreakStatementContinueStatementEmitStatementExpressionStatementForStatementIfStatementReturnStatementSwapStatementSwitchStatementWhileStatementRemoveStatementInvalidStatementBooleanExpressionVoidExpressionNilExpressionStringExpressionIntegerExpressionFixedPointExpressionArrayExpressionStringTemplateExpressionDictionaryExpressionIdentifierExpressionInvocationExpressionMemberExpressionIndexExpressionConditionalExpressionUnaryExpressionBinaryExpressionFunctionExpressionCastingExpressionCreateExpressionDestroyExpressionReferenceExpressionForceExpressionPathExpressionAttachExpressionInvalidExpressionConstantSizedTypeDictionaryTypeFunctionTypeInstantiationTypeNominalTypeOptionalTypeReferenceTypeIntersectionTypeVariableSizedTypePositionRangeElaborationActivationActivationEntriesVariableSizedSemaTypeConstantSizedSemaTypeDictionarySemaTypeOptionalSemaTypeIntersectionSemaTypeReferenceSemaTypeEntitlementSemaTypeEntitlementMapSemaTypeEntitlementRelationSemaTypeCapabilitySemaTypeInclusiveRangeSemaTypeOrderedMapOrderedMapEntryListOrderedMapEntryLast"
 
-var _MemoryKind_index = [...]uint16{0, 7, 19, 30, 44, 55, 69, 88, 106, 130, 143, 152, 161, 176, 197, 220, 244, 261, 279, 285, 305, 319, 351, 383, 401, 423, 448, 464, 484, 507, 534, 550, 569, 588, 607, 630, 653, 673, 697, 715, 737, 763, 789, 808, 828, 846, 862, 882, 898, 916, 937, 956, 971, 989, 1010, 1033, 1055, 1081, 1100, 1122, 1144, 1168, 1194, 1218, 1244, 1265, 1286, 1310, 1334, 1354, 1374, 1390, 1406, 1428, 1463, 1483, 1502, 1533, 1562, 1591, 1612, 1637, 1649, 1665, 1685, 1702, 1721, 1742, 1758, 1777, 1803, 1831, 1859, 1878, 1905, 1932, 1952, 1975, 1996, 2011, 2020, 2035, 2040, 2048, 2065, 2079, 2089, 2099, 2109, 2118, 2128, 2138, 2145, 2155, 2163, 2168, 2181, 2190, 2203, 2216, 2233, 2241, 2248, 2262, 2277, 2296, 2316, 2337, 2357, 2379, 2404, 2433, 2452, 2468, 2490, 2507, 2526, 2552, 2569, 2588, 2602, 2619, 2632, 2651, 2663, 2674, 2689, 2702, 2717, 2731, 2746, 2763, 2777, 2790, 2806, 2823, 2843, 2858, 2882, 2902, 2922, 2942, 2958, 2973, 2994, 3009, 3025, 3043, 3060, 3076, 3093, 3112, 3127, 3141, 3157, 3174, 3188, 3200, 3217, 3228, 3240, 3253, 3269, 3286, 3294, 3299, 3310, 3320, 3337, 3358, 3379, 3397, 3413, 3433, 3450, 3469, 3491, 3518, 3536, 3558, 3568, 3587, 3602, 3606}
+var _MemoryKind_index = [...]uint16{0, 7, 19, 30, 44, 55, 69, 88, 106, 130, 143, 152, 161, 176, 197, 220, 244, 261, 279, 285, 305, 319, 351, 383, 401, 423, 448, 464, 484, 507, 534, 550, 569, 588, 607, 630, 653, 673, 697, 715, 737, 763, 789, 808, 828, 846, 862, 882, 898, 916, 937, 956, 971, 989, 1010, 1033, 1055, 1081, 1100, 1122, 1144, 1168, 1194, 1218, 1244, 1265, 1286, 1310, 1334, 1354, 1374, 1390, 1406, 1428, 1463, 1483, 1502, 1533, 1562, 1591, 1612, 1637, 1649, 1665, 1685, 1702, 1721, 1742, 1758, 1777, 1803, 1831, 1859, 1878, 1905, 1932, 1952, 1975, 1996, 2011, 2020, 2035, 2040, 2048, 2065, 2079, 2089, 2099, 2109, 2118, 2128, 2138, 2145, 2155, 2163, 2168, 2181, 2190, 2203, 2216, 2233, 2241, 2248, 2262, 2277, 2296, 2316, 2337, 2357, 2379, 2404, 2433, 2452, 2468, 2490, 2507, 2526, 2552, 2569, 2587, 2606, 2620, 2637, 2650, 2669, 2681, 2692, 2707, 2720, 2735, 2749, 2764, 2780, 2797, 2811, 2824, 2840, 2857, 2877, 2892, 2916, 2936, 2956, 2976, 2992, 3007, 3028, 3043, 3059, 3077, 3094, 3110, 3127, 3146, 3161, 3175, 3191, 3208, 3225, 3239, 3251, 3268, 3279, 3291, 3304, 3320, 3337, 3345, 3350, 3361, 3371, 3388, 3409, 3430, 3448, 3464, 3484, 3501, 3520, 3542, 3569, 3587, 3609, 3619, 3638, 3653, 3657}
 
 func (i MemoryKind) String() string {
 	if i >= MemoryKind(len(_MemoryKind_index)-1) {
@@ -138,6 +138,7 @@ var (
 	VariableDeclarationMemoryUsage           = NewConstantMemoryUsage(MemoryKindVariableDeclaration)
 	SpecialFunctionDeclarationMemoryUsage    = NewConstantMemoryUsage(MemoryKindSpecialFunctionDeclaration)
 	PragmaDeclarationMemoryUsage             = NewConstantMemoryUsage(MemoryKindPragmaDeclaration)
+	InvalidDeclarationMemoryUsage            = NewConstantMemoryUsage(MemoryKindInvalidDeclaration)
 
 	// AST Statements
 
@@ -153,6 +154,7 @@ var (
 	SwitchStatementMemoryUsage     = NewConstantMemoryUsage(MemoryKindSwitchStatement)
 	WhileStatementMemoryUsage      = NewConstantMemoryUsage(MemoryKindWhileStatement)
 	RemoveStatementMemoryUsage     = NewConstantMemoryUsage(MemoryKindRemoveStatement)
+	InvalidStatementMemoryUsage    = NewConstantMemoryUsage(MemoryKindInvalidStatement)
 
 	// AST Expressions
 
@@ -177,6 +179,7 @@ var (
 	ForceExpressionMemoryUsage       = NewConstantMemoryUsage(MemoryKindForceExpression)
 	PathExpressionMemoryUsage        = NewConstantMemoryUsage(MemoryKindPathExpression)
 	AttachExpressionMemoryUsage      = NewConstantMemoryUsage(MemoryKindAttachExpression)
+	InvalidExpressionMemoryUsage     = NewConstantMemoryUsage(MemoryKindInvalidExpression)
 
 	// AST Types
 
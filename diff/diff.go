@@ -0,0 +1,384 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diff computes structured differences between Cadence values,
+// for example between the expected and the actual result of a script,
+// between event payloads, or between stored values before and after a migration.
+//
+// The differences can be rendered as text or JSON, and applied as a patch.
+package diff
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	_ "unsafe"
+
+	"github.com/onflow/cadence"
+)
+
+//go:linkname getCompositeFieldValues github.com/onflow/cadence.getCompositeFieldValues
+func getCompositeFieldValues(cadence.Composite) []cadence.Value
+
+//go:linkname getCompositeTypeFields github.com/onflow/cadence.getCompositeTypeFields
+func getCompositeTypeFields(cadence.CompositeType) []cadence.Field
+
+// PathElement is an element of a Path
+type PathElement interface {
+	isPathElement()
+	String() string
+}
+
+// FieldPathElement selects a field of a composite value
+type FieldPathElement string
+
+var _ PathElement = FieldPathElement("")
+
+func (FieldPathElement) isPathElement() {}
+
+func (e FieldPathElement) String() string {
+	return "." + string(e)
+}
+
+// IndexPathElement selects an element of an array value,
+// or a field of a composite value of unknown type by its position
+type IndexPathElement int
+
+var _ PathElement = IndexPathElement(0)
+
+func (IndexPathElement) isPathElement() {}
+
+func (e IndexPathElement) String() string {
+	return "[" + strconv.Itoa(int(e)) + "]"
+}
+
+// KeyPathElement selects the value of a dictionary entry by its key
+type KeyPathElement struct {
+	Key cadence.Value
+}
+
+var _ PathElement = KeyPathElement{}
+
+func (KeyPathElement) isPathElement() {}
+
+func (e KeyPathElement) String() string {
+	return "[" + e.Key.String() + "]"
+}
+
+// Path is the location of a difference in a value.
+// The empty path refers to the value itself.
+//
+// Optionals are transparent, i.e. paths do not have elements for them.
+type Path []PathElement
+
+// String returns the path expression, e.g. `.balances["alice"][0]`.
+// The empty path is represented as `.`
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "."
+	}
+
+	var builder strings.Builder
+	if _, ok := p[0].(FieldPathElement); !ok {
+		builder.WriteByte('.')
+	}
+	for _, element := range p {
+		builder.WriteString(element.String())
+	}
+	return builder.String()
+}
+
+// append returns a new path with the given element appended.
+// The new path does not share its backing array with p,
+// so paths of sibling changes do not overwrite each other.
+func (p Path) append(element PathElement) Path {
+	result := make(Path, len(p), len(p)+1)
+	copy(result, p)
+	return append(result, element)
+}
+
+// Change is a difference between two values at a path.
+//
+// Old is nil for added values, and New is nil for removed values.
+type Change struct {
+	Path Path
+	Kind Kind
+	Old  cadence.Value
+	New  cadence.Value
+}
+
+// Diff returns the differences between the old and the new value,
+// or nil if the values are equal.
+//
+// Composite values are compared field by field, if they have the same type.
+// Array values are compared element by element:
+// Elements which only exist in the new array are reported as added, in ascending order,
+// and elements which only exist in the old array are reported as removed, in descending order,
+// so the changes can be applied in sequence.
+// Dictionary values are compared entry by entry, by key.
+// Other values are compared by their string representation.
+func Diff(oldValue, newValue cadence.Value) []Change {
+	var changes []Change
+	diff(nil, oldValue, newValue, &changes)
+	return changes
+}
+
+// Equal returns true if the given values have no differences
+func Equal(a, b cadence.Value) bool {
+	return len(Diff(a, b)) == 0
+}
+
+func diff(path Path, oldValue, newValue cadence.Value, changes *[]Change) {
+
+	addChange := func(kind Kind) {
+		*changes = append(
+			*changes,
+			Change{
+				Path: path,
+				Kind: kind,
+				Old:  oldValue,
+				New:  newValue,
+			},
+		)
+	}
+
+	if oldValue == nil || newValue == nil {
+		if oldValue != newValue {
+			addChange(KindChanged)
+		}
+		return
+	}
+
+	if reflect.TypeOf(oldValue) != reflect.TypeOf(newValue) {
+		addChange(KindTypeMismatch)
+		return
+	}
+
+	switch oldValue := oldValue.(type) {
+	case cadence.Optional:
+		newValue := newValue.(cadence.Optional)
+		if oldValue.Value == nil || newValue.Value == nil {
+			if oldValue.Value != nil || newValue.Value != nil {
+				addChange(KindChanged)
+			}
+			return
+		}
+		diff(path, oldValue.Value, newValue.Value, changes)
+
+	case cadence.Array:
+		newValue := newValue.(cadence.Array)
+		if !equalTypes(oldValue.ArrayType, newValue.ArrayType) {
+			addChange(KindTypeMismatch)
+			return
+		}
+		diffArrays(path, oldValue, newValue, changes)
+
+	case cadence.Dictionary:
+		newValue := newValue.(cadence.Dictionary)
+		if !equalTypes(oldValue.DictionaryType, newValue.DictionaryType) {
+			addChange(KindTypeMismatch)
+			return
+		}
+		diffDictionaries(path, oldValue, newValue, changes)
+
+	case cadence.Composite:
+		newValue := newValue.(cadence.Composite)
+		if typeID(oldValue) != typeID(newValue) {
+			addChange(KindTypeMismatch)
+			return
+		}
+		diffComposites(path, oldValue, newValue, changes)
+
+	default:
+		if oldValue.String() != newValue.String() {
+			addChange(KindChanged)
+		}
+	}
+}
+
+func diffArrays(path Path, oldValue, newValue cadence.Array, changes *[]Change) {
+	oldCount := len(oldValue.Values)
+	newCount := len(newValue.Values)
+
+	for i := 0; i < min(oldCount, newCount); i++ {
+		diff(path.append(IndexPathElement(i)), oldValue.Values[i], newValue.Values[i], changes)
+	}
+
+	for i := oldCount; i < newCount; i++ {
+		*changes = append(
+			*changes,
+			Change{
+				Path: path.append(IndexPathElement(i)),
+				Kind: KindAdded,
+				New:  newValue.Values[i],
+			},
+		)
+	}
+
+	for i := oldCount - 1; i >= newCount; i-- {
+		*changes = append(
+			*changes,
+			Change{
+				Path: path.append(IndexPathElement(i)),
+				Kind: KindRemoved,
+				Old:  oldValue.Values[i],
+			},
+		)
+	}
+}
+
+func diffDictionaries(path Path, oldValue, newValue cadence.Dictionary, changes *[]Change) {
+	newValues := make(map[string]cadence.Value, len(newValue.Pairs))
+	for _, pair := range newValue.Pairs {
+		newValues[pair.Key.String()] = pair.Value
+	}
+
+	oldKeys := make(map[string]struct{}, len(oldValue.Pairs))
+
+	for _, pair := range oldValue.Pairs {
+		key := pair.Key.String()
+		oldKeys[key] = struct{}{}
+
+		keyPath := path.append(KeyPathElement{Key: pair.Key})
+
+		value, ok := newValues[key]
+		if !ok {
+			*changes = append(
+				*changes,
+				Change{
+					Path: keyPath,
+					Kind: KindRemoved,
+					Old:  pair.Value,
+				},
+			)
+			continue
+		}
+
+		diff(keyPath, pair.Value, value, changes)
+	}
+
+	for _, pair := range newValue.Pairs {
+		if _, ok := oldKeys[pair.Key.String()]; ok {
+			continue
+		}
+
+		*changes = append(
+			*changes,
+			Change{
+				Path: path.append(KeyPathElement{Key: pair.Key}),
+				Kind: KindAdded,
+				New:  pair.Value,
+			},
+		)
+	}
+}
+
+func diffComposites(path Path, oldValue, newValue cadence.Composite, changes *[]Change) {
+	oldFieldValues := getCompositeFieldValues(oldValue)
+	newFieldValues := getCompositeFieldValues(newValue)
+
+	oldFields := compositeFields(oldValue)
+	newFields := compositeFields(newValue)
+
+	// If the fields are unknown, compare the field values by position
+	if oldFields == nil || newFields == nil {
+		diffArrays(
+			path,
+			cadence.NewArray(oldFieldValues),
+			cadence.NewArray(newFieldValues),
+			changes,
+		)
+		return
+	}
+
+	newFieldIndices := make(map[string]int, len(newFields))
+	for i, field := range newFields {
+		newFieldIndices[field.Identifier] = i
+	}
+
+	oldFieldNames := make(map[string]struct{}, len(oldFields))
+
+	for i, field := range oldFields {
+		oldFieldNames[field.Identifier] = struct{}{}
+
+		fieldPath := path.append(FieldPathElement(field.Identifier))
+
+		newIndex, ok := newFieldIndices[field.Identifier]
+		if !ok {
+			*changes = append(
+				*changes,
+				Change{
+					Path: fieldPath,
+					Kind: KindRemoved,
+					Old:  oldFieldValues[i],
+				},
+			)
+			continue
+		}
+
+		diff(fieldPath, oldFieldValues[i], newFieldValues[newIndex], changes)
+	}
+
+	for i, field := range newFields {
+		if _, ok := oldFieldNames[field.Identifier]; ok {
+			continue
+		}
+
+		*changes = append(
+			*changes,
+			Change{
+				Path: path.append(FieldPathElement(field.Identifier)),
+				Kind: KindAdded,
+				New:  newFieldValues[i],
+			},
+		)
+	}
+}
+
+// compositeFields returns the fields of the given composite value's type,
+// or nil if the type is unknown, or the fields do not match the field values
+func compositeFields(composite cadence.Composite) []cadence.Field {
+	compositeType, ok := composite.Type().(cadence.CompositeType)
+	if !ok {
+		return nil
+	}
+
+	fields := getCompositeTypeFields(compositeType)
+	if len(fields) != len(getCompositeFieldValues(composite)) {
+		return nil
+	}
+
+	return fields
+}
+
+func equalTypes(a, b cadence.Type) bool {
+	// Values without static types, e.g. decoded from JSON-CDC,
+	// are compared by their contents
+	if a == nil || b == nil {
+		return true
+	}
+	return a.Equal(b)
+}
+
+func typeID(value cadence.Value) string {
+	ty := value.Type()
+	if ty == nil {
+		return ""
+	}
+	return ty.ID()
+}
@@ -0,0 +1,556 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/diff"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+func newFooType() *cadence.StructType {
+	return cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		[]cadence.Field{
+			{
+				Identifier: "id",
+				Type:       cadence.UInt64Type,
+			},
+			{
+				Identifier: "tags",
+				Type:       cadence.NewVariableSizedArrayType(cadence.StringType),
+			},
+			{
+				Identifier: "balances",
+				Type:       cadence.NewDictionaryType(cadence.StringType, cadence.IntType),
+			},
+			{
+				Identifier: "owner",
+				Type:       cadence.NewOptionalType(cadence.AddressType),
+			},
+		},
+		nil,
+	)
+}
+
+func newFoo(
+	id uint64,
+	tags []string,
+	balances map[string]int,
+	owner cadence.Value,
+) cadence.Struct {
+	tagValues := make([]cadence.Value, len(tags))
+	for i, tag := range tags {
+		tagValues[i] = cadence.String(tag)
+	}
+
+	pairs := make([]cadence.KeyValuePair, 0, len(balances))
+	for _, key := range []string{"alice", "bob", "charlie"} {
+		balance, ok := balances[key]
+		if !ok {
+			continue
+		}
+		pairs = append(
+			pairs,
+			cadence.KeyValuePair{
+				Key:   cadence.String(key),
+				Value: cadence.NewInt(balance),
+			},
+		)
+	}
+
+	return cadence.NewStruct([]cadence.Value{
+		cadence.NewUInt64(id),
+		cadence.NewArray(tagValues).
+			WithType(cadence.NewVariableSizedArrayType(cadence.StringType)),
+		cadence.NewDictionary(pairs).
+			WithType(cadence.NewDictionaryType(cadence.StringType, cadence.IntType)),
+		cadence.NewOptional(owner),
+	}).WithType(newFooType())
+}
+
+func TestDiff(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("equal", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFoo(1, []string{"a", "b"}, map[string]int{"alice": 1}, nil)
+		b := newFoo(1, []string{"a", "b"}, map[string]int{"alice": 1}, nil)
+
+		assert.Nil(t, diff.Diff(a, b))
+		assert.True(t, diff.Equal(a, b))
+	})
+
+	t.Run("changed primitive", func(t *testing.T) {
+		t.Parallel()
+
+		changes := diff.Diff(cadence.NewInt(15), cadence.NewInt(21))
+
+		assert.Equal(t,
+			[]diff.Change{
+				{
+					Path: nil,
+					Kind: diff.KindChanged,
+					Old:  cadence.NewInt(15),
+					New:  cadence.NewInt(21),
+				},
+			},
+			changes,
+		)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		changes := diff.Diff(cadence.NewInt(1), cadence.String("1"))
+
+		require.Len(t, changes, 1)
+		assert.Equal(t, diff.KindTypeMismatch, changes[0].Kind)
+	})
+
+	t.Run("composite type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		a := cadence.NewStruct([]cadence.Value{
+			cadence.NewInt(1),
+		}).WithType(cadence.NewStructType(
+			TestLocation,
+			"Foo",
+			[]cadence.Field{
+				{
+					Identifier: "x",
+					Type:       cadence.IntType,
+				},
+			},
+			nil,
+		))
+
+		b := cadence.NewStruct([]cadence.Value{
+			cadence.NewInt(1),
+		}).WithType(cadence.NewStructType(
+			TestLocation,
+			"Bar",
+			[]cadence.Field{
+				{
+					Identifier: "x",
+					Type:       cadence.IntType,
+				},
+			},
+			nil,
+		))
+
+		changes := diff.Diff(
+			cadence.NewArray([]cadence.Value{a}),
+			cadence.NewArray([]cadence.Value{b}),
+		)
+
+		assert.Equal(t,
+			"! .[0]: S.test.Foo => S.test.Bar",
+			diff.Text(changes),
+		)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+
+		a := newFoo(
+			1,
+			[]string{"a", "b", "c"},
+			map[string]int{"alice": 1, "bob": 2},
+			nil,
+		)
+		b := newFoo(
+			2,
+			[]string{"a", "x"},
+			map[string]int{"bob": 3, "charlie": 4},
+			cadence.BytesToAddress([]byte{0x1}),
+		)
+
+		changes := diff.Diff(a, b)
+
+		assert.Equal(t,
+			[]diff.Change{
+				{
+					Path: diff.Path{diff.FieldPathElement("id")},
+					Kind: diff.KindChanged,
+					Old:  cadence.NewUInt64(1),
+					New:  cadence.NewUInt64(2),
+				},
+				{
+					Path: diff.Path{
+						diff.FieldPathElement("tags"),
+						diff.IndexPathElement(1),
+					},
+					Kind: diff.KindChanged,
+					Old:  cadence.String("b"),
+					New:  cadence.String("x"),
+				},
+				{
+					Path: diff.Path{
+						diff.FieldPathElement("tags"),
+						diff.IndexPathElement(2),
+					},
+					Kind: diff.KindRemoved,
+					Old:  cadence.String("c"),
+				},
+				{
+					Path: diff.Path{
+						diff.FieldPathElement("balances"),
+						diff.KeyPathElement{Key: cadence.String("alice")},
+					},
+					Kind: diff.KindRemoved,
+					Old:  cadence.NewInt(1),
+				},
+				{
+					Path: diff.Path{
+						diff.FieldPathElement("balances"),
+						diff.KeyPathElement{Key: cadence.String("bob")},
+					},
+					Kind: diff.KindChanged,
+					Old:  cadence.NewInt(2),
+					New:  cadence.NewInt(3),
+				},
+				{
+					Path: diff.Path{
+						diff.FieldPathElement("balances"),
+						diff.KeyPathElement{Key: cadence.String("charlie")},
+					},
+					Kind: diff.KindAdded,
+					New:  cadence.NewInt(4),
+				},
+				{
+					Path: diff.Path{diff.FieldPathElement("owner")},
+					Kind: diff.KindChanged,
+					Old:  cadence.NewOptional(nil),
+					New:  cadence.NewOptional(cadence.BytesToAddress([]byte{0x1})),
+				},
+			},
+			changes,
+		)
+
+		assert.Equal(t,
+			`~ .id: 1 => 2`+"\n"+
+				`~ .tags[1]: "b" => "x"`+"\n"+
+				`- .tags[2]: "c"`+"\n"+
+				`- .balances["alice"]: 1`+"\n"+
+				`~ .balances["bob"]: 2 => 3`+"\n"+
+				`+ .balances["charlie"]: 4`+"\n"+
+				`~ .owner: nil => 0x0000000000000001`,
+			diff.Text(changes),
+		)
+	})
+
+	t.Run("array elements", func(t *testing.T) {
+		t.Parallel()
+
+		a := cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+		})
+		b := cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewInt(2),
+			cadence.NewInt(3),
+		})
+
+		assert.Equal(t,
+			"+ .[1]: 2\n+ .[2]: 3",
+			diff.Text(diff.Diff(a, b)),
+		)
+		assert.Equal(t,
+			"- .[2]: 3\n- .[1]: 2",
+			diff.Text(diff.Diff(b, a)),
+		)
+	})
+}
+
+func TestDiffFields(t *testing.T) {
+
+	t.Parallel()
+
+	// The type of a composite value may change, e.g. when stored values are migrated
+
+	oldValue := cadence.NewStruct([]cadence.Value{
+		cadence.NewInt(1),
+		cadence.String("a"),
+	}).WithType(cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		[]cadence.Field{
+			{
+				Identifier: "x",
+				Type:       cadence.IntType,
+			},
+			{
+				Identifier: "y",
+				Type:       cadence.StringType,
+			},
+		},
+		nil,
+	))
+
+	newValue := cadence.NewStruct([]cadence.Value{
+		cadence.Bool(true),
+		cadence.NewInt(1),
+	}).WithType(cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		[]cadence.Field{
+			{
+				Identifier: "z",
+				Type:       cadence.BoolType,
+			},
+			{
+				Identifier: "x",
+				Type:       cadence.IntType,
+			},
+		},
+		nil,
+	))
+
+	assert.Equal(t,
+		"- .y: \"a\"\n+ .z: true",
+		diff.Text(diff.Diff(oldValue, newValue)),
+	)
+}
+
+func TestPathString(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, ".", diff.Path(nil).String())
+
+	assert.Equal(t,
+		".a[1][\"b\"].c",
+		diff.Path{
+			diff.FieldPathElement("a"),
+			diff.IndexPathElement(1),
+			diff.KeyPathElement{Key: cadence.String("b")},
+			diff.FieldPathElement("c"),
+		}.String(),
+	)
+
+	assert.Equal(t,
+		".[0].a",
+		diff.Path{
+			diff.IndexPathElement(0),
+			diff.FieldPathElement("a"),
+		}.String(),
+	)
+}
+
+func TestJSON(t *testing.T) {
+
+	t.Parallel()
+
+	changes := diff.Diff(
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.String("a"),
+		}),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(2),
+		}),
+	)
+
+	actual, err := diff.JSON(changes, jsoncdc.Encode)
+	require.NoError(t, err)
+
+	// language=json
+	const expected = `
+      [
+        {
+          "path": ".[0]",
+          "kind": "Changed",
+          "old": {"type": "Int", "value": "1"},
+          "new": {"type": "Int", "value": "2"}
+        },
+        {
+          "path": ".[1]",
+          "kind": "Removed",
+          "old": {"type": "String", "value": "a"}
+        }
+      ]
+    `
+
+	assert.JSONEq(t, expected, string(actual))
+}
+
+func TestApply(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, oldValue, newValue cadence.Value) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			changes := diff.Diff(oldValue, newValue)
+			require.NotEmpty(t, changes)
+
+			patched, err := diff.Apply(oldValue, changes)
+			require.NoError(t, err)
+
+			assert.Empty(t, diff.Diff(patched, newValue))
+			assert.Equal(t, newValue.Type(), patched.Type())
+		})
+	}
+
+	test(
+		"primitive",
+		cadence.NewInt(1),
+		cadence.NewInt(2),
+	)
+
+	test(
+		"type mismatch",
+		cadence.NewInt(1),
+		cadence.String("1"),
+	)
+
+	test(
+		"composite",
+		newFoo(
+			1,
+			[]string{"a", "b", "c"},
+			map[string]int{"alice": 1, "bob": 2},
+			nil,
+		),
+		newFoo(
+			2,
+			[]string{"a", "x"},
+			map[string]int{"bob": 3, "charlie": 4},
+			cadence.BytesToAddress([]byte{0x1}),
+		),
+	)
+
+	test(
+		"added array elements",
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+		}),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewInt(2),
+			cadence.NewInt(3),
+		}),
+	)
+
+	test(
+		"removed array elements",
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewInt(2),
+			cadence.NewInt(3),
+		}),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(1),
+		}),
+	)
+
+	test(
+		"optional",
+		cadence.NewOptional(
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+			}),
+		),
+		cadence.NewOptional(
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(2),
+			}),
+		),
+	)
+
+	t.Run("value is not modified", func(t *testing.T) {
+		t.Parallel()
+
+		oldValue := newFoo(1, []string{"a"}, map[string]int{"alice": 1}, nil)
+		newValue := newFoo(1, []string{"b"}, map[string]int{"alice": 2}, nil)
+
+		_, err := diff.Apply(oldValue, diff.Diff(oldValue, newValue))
+		require.NoError(t, err)
+
+		assert.True(t,
+			diff.Equal(
+				newFoo(1, []string{"a"}, map[string]int{"alice": 1}, nil),
+				oldValue,
+			),
+		)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+
+		changes := diff.Diff(
+			cadence.NewArray([]cadence.Value{cadence.NewInt(1)}),
+			cadence.NewArray([]cadence.Value{cadence.NewInt(2)}),
+		)
+
+		_, err := diff.Apply(
+			cadence.NewArray([]cadence.Value{cadence.NewInt(3)}),
+			changes,
+		)
+		require.Error(t, err)
+		assert.ErrorAs(t, err, &diff.PatchError{})
+		assert.EqualError(t, err, "cannot apply Changed change at .[0]: expected 1, got 3")
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := diff.Apply(
+			cadence.NewInt(1),
+			[]diff.Change{
+				{
+					Path: diff.Path{diff.FieldPathElement("x")},
+					Kind: diff.KindChanged,
+					Old:  cadence.NewInt(1),
+					New:  cadence.NewInt(2),
+				},
+			},
+		)
+		require.Error(t, err)
+		assert.ErrorAs(t, err, &diff.PatchError{})
+	})
+
+	t.Run("added field", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := diff.Apply(
+			newFoo(1, nil, nil, nil),
+			[]diff.Change{
+				{
+					Path: diff.Path{diff.FieldPathElement("x")},
+					Kind: diff.KindAdded,
+					New:  cadence.NewInt(2),
+				},
+			},
+		)
+		require.Error(t, err)
+		assert.ErrorAs(t, err, &diff.PatchError{})
+	})
+}
@@ -0,0 +1,123 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+)
+
+// String returns the textual representation of the change, prefixed with a symbol for its kind:
+//
+//   - .path: new
+//   - .path: old
+//     ~ .path: old => new
+//     ! .path: OldType => NewType
+func (c Change) String() string {
+	switch c.Kind {
+	case KindAdded:
+		return fmt.Sprintf("%s %s: %s", c.Kind.symbol(), c.Path, c.New)
+
+	case KindRemoved:
+		return fmt.Sprintf("%s %s: %s", c.Kind.symbol(), c.Path, c.Old)
+
+	case KindTypeMismatch:
+		return fmt.Sprintf(
+			"%s %s: %s => %s",
+			c.Kind.symbol(),
+			c.Path,
+			typeString(c.Old),
+			typeString(c.New),
+		)
+
+	default:
+		return fmt.Sprintf("%s %s: %s => %s", c.Kind.symbol(), c.Path, c.Old, c.New)
+	}
+}
+
+// Text returns the textual representation of the given changes, one change per line
+func Text(changes []Change) string {
+	var builder strings.Builder
+	for i, change := range changes {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(change.String())
+	}
+	return builder.String()
+}
+
+type jsonChange struct {
+	Path string          `json:"path"`
+	Kind string          `json:"kind"`
+	Old  json.RawMessage `json:"old,omitempty"`
+	New  json.RawMessage `json:"new,omitempty"`
+}
+
+// JSON returns the JSON representation of the given changes.
+//
+// The result is an array of objects, one per change,
+// with the path expression, the kind of change, and the old and new values,
+// encoded using the given function, which must return JSON, e.g. the JSON-CDC encoder `json.Encode`.
+//
+// The encoder is passed in, because the JSON-CDC codec depends on packages which use this package.
+func JSON(changes []Change, encodeValue func(cadence.Value) ([]byte, error)) ([]byte, error) {
+	jsonChanges := make([]jsonChange, len(changes))
+
+	for i, change := range changes {
+		jsonChanges[i] = jsonChange{
+			Path: change.Path.String(),
+			Kind: change.Kind.String(),
+		}
+
+		var err error
+
+		if change.Old != nil {
+			jsonChanges[i].Old, err = encodeValue(change.Old)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if change.New != nil {
+			jsonChanges[i].New, err = encodeValue(change.New)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(jsonChanges)
+}
+
+func typeString(value cadence.Value) string {
+	if value == nil {
+		return "<none>"
+	}
+
+	ty := value.Type()
+	if ty == nil {
+		return "<unknown>"
+	}
+
+	return ty.ID()
+}
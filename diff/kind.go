@@ -0,0 +1,52 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+//go:generate stringer -type=Kind -trimprefix=Kind
+
+// Kind is the kind of difference between two values
+type Kind uint8
+
+const (
+	KindUnknown Kind = iota
+	// KindAdded indicates that a field, array element, or dictionary entry only exists in the new value
+	KindAdded
+	// KindRemoved indicates that a field, array element, or dictionary entry only exists in the old value
+	KindRemoved
+	// KindChanged indicates that the old and the new value have the same type, but are not equal
+	KindChanged
+	// KindTypeMismatch indicates that the old and the new value have different types
+	KindTypeMismatch
+)
+
+// symbol returns the symbol used to prefix changes of this kind in the textual representation
+func (k Kind) symbol() string {
+	switch k {
+	case KindAdded:
+		return "+"
+	case KindRemoved:
+		return "-"
+	case KindChanged:
+		return "~"
+	case KindTypeMismatch:
+		return "!"
+	default:
+		return "?"
+	}
+}
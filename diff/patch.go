@@ -0,0 +1,345 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/errors"
+)
+
+// PatchError is returned by Apply if a change cannot be applied
+type PatchError struct {
+	Change Change
+	Reason string
+}
+
+var _ error = PatchError{}
+
+func (e PatchError) Error() string {
+	return fmt.Sprintf(
+		"cannot apply %s change at %s: %s",
+		e.Change.Kind,
+		e.Change.Path,
+		e.Reason,
+	)
+}
+
+// Apply applies the given changes to the value, in order, and returns the patched value.
+// The given value is not modified.
+//
+// Applying the changes returned by Diff(a, b) to a results in a value equal to b.
+//
+// The old value of each changed or removed value must be equal to the current value,
+// otherwise the patch conflicts with the value, and an error is returned.
+//
+// Fields of composite values cannot be added or removed,
+// as the fields are declared by the composite type.
+func Apply(value cadence.Value, changes []Change) (cadence.Value, error) {
+	for _, change := range changes {
+		var err error
+		value, err = apply(value, change.Path, change)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func apply(value cadence.Value, path Path, change Change) (cadence.Value, error) {
+
+	if len(path) == 0 {
+		switch change.Kind {
+		case KindChanged, KindTypeMismatch:
+			if !Equal(value, change.Old) {
+				return nil, PatchError{
+					Change: change,
+					Reason: fmt.Sprintf("expected %s, got %s", change.Old, value),
+				}
+			}
+			return change.New, nil
+
+		default:
+			return nil, PatchError{
+				Change: change,
+				Reason: "invalid path",
+			}
+		}
+	}
+
+	// Optionals are transparent
+	if optional, ok := value.(cadence.Optional); ok && optional.Value != nil {
+		patched, err := apply(optional.Value, path, change)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(patched), nil
+	}
+
+	switch element := path[0].(type) {
+	case IndexPathElement:
+		switch value := value.(type) {
+		case cadence.Array:
+			values, err := applyToElements(value.Values, int(element), path[1:], change)
+			if err != nil {
+				return nil, err
+			}
+			return cadence.NewArray(values).WithType(value.ArrayType), nil
+
+		case cadence.Composite:
+			fieldValues := getCompositeFieldValues(value)
+			if len(path) == 1 && change.Kind != KindChanged && change.Kind != KindTypeMismatch {
+				return nil, PatchError{
+					Change: change,
+					Reason: "fields of composite values cannot be added or removed",
+				}
+			}
+			fieldValues, err := applyToElements(fieldValues, int(element), path[1:], change)
+			if err != nil {
+				return nil, err
+			}
+			return withFieldValues(value, fieldValues), nil
+		}
+
+	case FieldPathElement:
+		if composite, ok := value.(cadence.Composite); ok {
+			return applyToField(composite, string(element), path[1:], change)
+		}
+
+	case KeyPathElement:
+		if dictionary, ok := value.(cadence.Dictionary); ok {
+			return applyToEntry(dictionary, element.Key, path[1:], change)
+		}
+	}
+
+	return nil, PatchError{
+		Change: change,
+		Reason: fmt.Sprintf("path element %s does not apply to %s", path[0], value),
+	}
+}
+
+func applyToElements(
+	values []cadence.Value,
+	index int,
+	rest Path,
+	change Change,
+) ([]cadence.Value, error) {
+
+	if len(rest) == 0 {
+		switch change.Kind {
+		case KindAdded:
+			if index < 0 || index > len(values) {
+				return nil, PatchError{
+					Change: change,
+					Reason: fmt.Sprintf("index out of bounds: %d, count: %d", index, len(values)),
+				}
+			}
+
+			result := make([]cadence.Value, 0, len(values)+1)
+			result = append(result, values[:index]...)
+			result = append(result, change.New)
+			return append(result, values[index:]...), nil
+
+		case KindRemoved:
+			if index < 0 || index >= len(values) {
+				return nil, PatchError{
+					Change: change,
+					Reason: fmt.Sprintf("index out of bounds: %d, count: %d", index, len(values)),
+				}
+			}
+
+			if !Equal(values[index], change.Old) {
+				return nil, PatchError{
+					Change: change,
+					Reason: fmt.Sprintf("expected %s, got %s", change.Old, values[index]),
+				}
+			}
+
+			result := make([]cadence.Value, 0, len(values)-1)
+			result = append(result, values[:index]...)
+			return append(result, values[index+1:]...), nil
+		}
+	}
+
+	if index < 0 || index >= len(values) {
+		return nil, PatchError{
+			Change: change,
+			Reason: fmt.Sprintf("index out of bounds: %d, count: %d", index, len(values)),
+		}
+	}
+
+	patched, err := apply(values[index], rest, change)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cadence.Value, len(values))
+	copy(result, values)
+	result[index] = patched
+	return result, nil
+}
+
+func applyToField(
+	composite cadence.Composite,
+	name string,
+	rest Path,
+	change Change,
+) (cadence.Value, error) {
+
+	fields := compositeFields(composite)
+
+	index := -1
+	for i, field := range fields {
+		if field.Identifier == name {
+			index = i
+			break
+		}
+	}
+
+	if len(rest) == 0 && change.Kind != KindChanged && change.Kind != KindTypeMismatch {
+		return nil, PatchError{
+			Change: change,
+			Reason: "fields of composite values cannot be added or removed",
+		}
+	}
+
+	if index < 0 {
+		return nil, PatchError{
+			Change: change,
+			Reason: fmt.Sprintf("unknown field %s", name),
+		}
+	}
+
+	fieldValues, err := applyToElements(
+		getCompositeFieldValues(composite),
+		index,
+		rest,
+		change,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return withFieldValues(composite, fieldValues), nil
+}
+
+func applyToEntry(
+	dictionary cadence.Dictionary,
+	key cadence.Value,
+	rest Path,
+	change Change,
+) (cadence.Value, error) {
+
+	index := -1
+	for i, pair := range dictionary.Pairs {
+		if Equal(pair.Key, key) {
+			index = i
+			break
+		}
+	}
+
+	var pairs []cadence.KeyValuePair
+
+	switch {
+	case len(rest) == 0 && change.Kind == KindAdded:
+		if index >= 0 {
+			return nil, PatchError{
+				Change: change,
+				Reason: fmt.Sprintf("key %s already exists", key),
+			}
+		}
+
+		pairs = make([]cadence.KeyValuePair, 0, len(dictionary.Pairs)+1)
+		pairs = append(pairs, dictionary.Pairs...)
+		pairs = append(
+			pairs,
+			cadence.KeyValuePair{
+				Key:   key,
+				Value: change.New,
+			},
+		)
+
+	case index < 0:
+		return nil, PatchError{
+			Change: change,
+			Reason: fmt.Sprintf("unknown key %s", key),
+		}
+
+	case len(rest) == 0 && change.Kind == KindRemoved:
+		existing := dictionary.Pairs[index].Value
+		if !Equal(existing, change.Old) {
+			return nil, PatchError{
+				Change: change,
+				Reason: fmt.Sprintf("expected %s, got %s", change.Old, existing),
+			}
+		}
+
+		pairs = make([]cadence.KeyValuePair, 0, len(dictionary.Pairs)-1)
+		pairs = append(pairs, dictionary.Pairs[:index]...)
+		pairs = append(pairs, dictionary.Pairs[index+1:]...)
+
+	default:
+		patched, err := apply(dictionary.Pairs[index].Value, rest, change)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = make([]cadence.KeyValuePair, len(dictionary.Pairs))
+		copy(pairs, dictionary.Pairs)
+		pairs[index].Value = patched
+	}
+
+	return cadence.NewDictionary(pairs).WithType(dictionary.DictionaryType), nil
+}
+
+// withFieldValues returns a copy of the given composite value,
+// with the given field values, and the same type and attachments
+func withFieldValues(composite cadence.Composite, fieldValues []cadence.Value) cadence.Value {
+	switch composite := composite.(type) {
+	case cadence.Struct:
+		return cadence.NewStruct(fieldValues).
+			WithType(composite.StructType).
+			WithAttachments(composite.Attachments())
+
+	case cadence.Resource:
+		return cadence.NewResource(fieldValues).
+			WithType(composite.ResourceType).
+			WithAttachments(composite.Attachments())
+
+	case cadence.Attachment:
+		return cadence.NewAttachment(fieldValues).
+			WithType(composite.AttachmentType)
+
+	case cadence.Event:
+		return cadence.NewEvent(fieldValues).
+			WithType(composite.EventType)
+
+	case cadence.Contract:
+		return cadence.NewContract(fieldValues).
+			WithType(composite.ContractType)
+
+	case cadence.Enum:
+		return cadence.NewEnum(fieldValues).
+			WithType(composite.EnumType)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
@@ -19,6 +19,8 @@
 package ccf_test
 
 import (
+	"bytes"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -209,3 +211,47 @@ func BenchmarkDecodeBatchEventsCCF(b *testing.B) {
 
 	require.NoError(b, err)
 }
+
+func benchmarkStreamDecodeBatchEventsCCF(b *testing.B, options ccf.StreamOptions) {
+	var buffer bytes.Buffer
+
+	for _, bm := range batchBenchmarks {
+		encoded := ccf.MustEncode(bm.value)
+		for i := 0; i < bm.count; i++ {
+			buffer.Write(encoded)
+		}
+	}
+
+	data := buffer.Bytes()
+
+	b.ResetTimer()
+
+	var err error
+	for i := 0; i < b.N; i++ {
+		decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(data), options)
+		for {
+			val, err = decoder.Next()
+			if err != nil {
+				break
+			}
+		}
+		if err != io.EOF {
+			break
+		}
+	}
+
+	require.Equal(b, io.EOF, err)
+}
+
+func BenchmarkStreamDecodeBatchEventsCCF(b *testing.B) {
+	benchmarkStreamDecodeBatchEventsCCF(b, ccf.StreamOptions{})
+}
+
+func BenchmarkStreamDecodeBatchEventsProjectedCCF(b *testing.B) {
+	benchmarkStreamDecodeBatchEventsCCF(
+		b,
+		ccf.StreamOptions{
+			Fields: []string{"amount"},
+		},
+	)
+}
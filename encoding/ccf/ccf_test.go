@@ -34,7 +34,6 @@ import (
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/encoding/ccf"
-	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/sema"
@@ -5707,13 +5706,355 @@ func TestEncodeStruct(t *testing.T) {
 		},
 	}
 
+	attachmentStruct := encodeTest{
+		name: "attachments",
+		val: func() cadence.Value {
+			structType := cadence.NewStructType(
+				TestLocation,
+				"FooStruct",
+				[]cadence.Field{
+					{
+						Identifier: "a",
+						Type:       cadence.IntType,
+					},
+				},
+				nil,
+			)
+			attachmentType := cadence.NewAttachmentType(
+				TestLocation,
+				"FooAttachment",
+				nil,
+				[]cadence.Field{
+					{
+						Identifier: "i",
+						Type:       cadence.UInt8Type,
+					},
+				},
+				nil,
+			)
+			return cadence.NewStruct(
+				[]cadence.Value{
+					cadence.NewInt(1),
+				},
+			).
+				WithType(structType).
+				WithAttachments([]cadence.Attachment{
+					cadence.NewAttachment(
+						[]cadence.Value{
+							cadence.NewUInt8(2),
+						},
+					).WithType(attachmentType),
+				})
+		}(),
+		expected: []byte{
+			// language=json, format=json-cdc
+			// {"type":"Struct","value":{"id":"S.test.FooStruct","fields":[{"name":"a","value":{"type":"Int","value":"1"}}],"attachments":[{"type":"Attachment","value":{"id":"S.test.FooAttachment","fields":[{"name":"i","value":{"type":"UInt8","value":"2"}}]}}]}}
+			//
+			// language=edn, format=ccf
+			// 129([[160([h'', "S.test.FooStruct", [["a", 137(4)]]]), 163([h'01', "S.test.FooAttachment", [["i", 137(12)]]])], [136(h''), [1, [[136(h'01'), [2]]]]]])
+			//
+			// language=cbor, format=ccf
+			// tag
+			0xd8, ccf.CBORTagTypeDefAndValue,
+			// array, 2 items follow
+			0x82,
+			// element 0: type definitions
+			// array, 2 items follow
+			0x82,
+			// struct type:
+			// id: []byte{}
+			// cadence-type-id: "S.test.FooStruct"
+			// 1 fields: [["a", type(int)]]
+			// tag
+			0xd8, ccf.CBORTagStructType,
+			// array, 3 items follow
+			0x83,
+			// id
+			// bytes, 0 bytes follow
+			0x40,
+			// cadence-type-id
+			// string, 16 bytes follow
+			0x70,
+			// S.test.FooStruct
+			0x53, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x6f, 0x6f, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+			// fields
+			// array, 1 items follow
+			0x81,
+			// field 0
+			// array, 2 items follow
+			0x82,
+			// text, 1 bytes follow
+			0x61,
+			// a
+			0x61,
+			// tag
+			0xd8, ccf.CBORTagSimpleType,
+			// Int type ID (4)
+			0x04,
+			// attachment type:
+			// id: []byte{1}
+			// cadence-type-id: "S.test.FooAttachment"
+			// 1 fields: [["i", type(uint8)]]
+			// tag
+			0xd8, ccf.CBORTagAttachmentType,
+			// array, 3 items follow
+			0x83,
+			// id
+			// bytes, 1 bytes follow
+			0x41,
+			// 1
+			0x01,
+			// cadence-type-id
+			// string, 20 bytes follow
+			0x74,
+			// S.test.FooAttachment
+			0x53, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x6f, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74,
+			// fields
+			// array, 1 items follow
+			0x81,
+			// field 0
+			// array, 2 items follow
+			0x82,
+			// text, 1 bytes follow
+			0x61,
+			// i
+			0x69,
+			// tag
+			0xd8, ccf.CBORTagSimpleType,
+			// UInt8 type ID (12)
+			0x0c,
+
+			// element 1: type and value
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, ccf.CBORTagTypeRef,
+			// bytes, 0 bytes follow
+			0x40,
+			// array, 2 items follow
+			0x82,
+			// tag (big number)
+			0xc2,
+			// bytes, 1 byte follow
+			0x41,
+			// 1
+			0x01,
+			// attachments
+			// array, 1 items follow
+			0x81,
+			// attachment 0
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, ccf.CBORTagTypeRef,
+			// bytes, 1 bytes follow
+			0x41,
+			// 1
+			0x01,
+			// array, 1 items follow
+			0x81,
+			// 2
+			0x02,
+		},
+	}
+
 	testAllEncodeAndDecode(t,
 		noFieldStruct,
 		simpleStruct,
 		resourceStruct,
+		attachmentStruct,
 	)
 }
 
+func TestEncodeSortedAttachments(t *testing.T) {
+
+	t.Parallel()
+
+	structType := cadence.NewStructType(
+		TestLocation,
+		"FooStruct",
+		[]cadence.Field{},
+		nil,
+	)
+
+	newAttachment := func(identifier string) cadence.Attachment {
+		return cadence.NewAttachment(
+			[]cadence.Value{},
+		).WithType(
+			cadence.NewAttachmentType(
+				TestLocation,
+				identifier,
+				nil,
+				[]cadence.Field{},
+				nil,
+			),
+		)
+	}
+
+	val := cadence.NewStruct(
+		[]cadence.Value{},
+	).
+		WithType(structType).
+		WithAttachments([]cadence.Attachment{
+			newAttachment("B"),
+			newAttachment("A"),
+		})
+
+	t.Run("sorted", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := deterministicEncMode.Encode(val)
+		require.NoError(t, err)
+
+		decoded, err := deterministicDecMode.Decode(nil, encoded)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			val.WithAttachments([]cadence.Attachment{
+				newAttachment("A"),
+				newAttachment("B"),
+			}),
+			decoded,
+		)
+	})
+
+	t.Run("unsorted", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(val)
+		require.NoError(t, err)
+
+		decoded, err := ccf.EventsDecMode.Decode(nil, encoded)
+		require.NoError(t, err)
+		assert.Equal(t, val, decoded)
+
+		_, err = deterministicDecMode.Decode(nil, encoded)
+		require.ErrorContains(t, err, "attachments are not sorted in composite-value")
+	})
+}
+
+func TestDecodeInvalidAttachments(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte{
+			// language=edn, format=ccf
+			// 129([[160([h'', "S.test.FooStruct", []])], [136(h''), [[]]]])
+			//
+			// language=cbor, format=ccf
+			// tag
+			0xd8, ccf.CBORTagTypeDefAndValue,
+			// array, 2 items follow
+			0x82,
+			// element 0: type definitions
+			// array, 1 items follow
+			0x81,
+			// struct type:
+			// id: []byte{}
+			// cadence-type-id: "S.test.FooStruct"
+			// 0 fields: []
+			// tag
+			0xd8, ccf.CBORTagStructType,
+			// array, 3 items follow
+			0x83,
+			// id
+			// bytes, 0 bytes follow
+			0x40,
+			// cadence-type-id
+			// string, 16 bytes follow
+			0x70,
+			// S.test.FooStruct
+			0x53, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x6f, 0x6f, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+			// array, 0 item follows
+			0x80,
+
+			// element 1: type and value
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, ccf.CBORTagTypeRef,
+			// bytes, 0 bytes follow
+			0x40,
+			// array, 1 items follow
+			0x81,
+			// attachments
+			// array, 0 items follow
+			0x80,
+		}
+
+		_, err := ccf.Decode(nil, data)
+		require.ErrorContains(t, err, "unexpected empty attachments in composite-value")
+	})
+
+	t.Run("non-attachment", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte{
+			// language=edn, format=ccf
+			// 129([[160([h'', "S.test.FooStruct", []])], [136(h''), [[[137(4), 1]]]]])
+			//
+			// language=cbor, format=ccf
+			// tag
+			0xd8, ccf.CBORTagTypeDefAndValue,
+			// array, 2 items follow
+			0x82,
+			// element 0: type definitions
+			// array, 1 items follow
+			0x81,
+			// struct type:
+			// id: []byte{}
+			// cadence-type-id: "S.test.FooStruct"
+			// 0 fields: []
+			// tag
+			0xd8, ccf.CBORTagStructType,
+			// array, 3 items follow
+			0x83,
+			// id
+			// bytes, 0 bytes follow
+			0x40,
+			// cadence-type-id
+			// string, 16 bytes follow
+			0x70,
+			// S.test.FooStruct
+			0x53, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x6f, 0x6f, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+			// array, 0 item follows
+			0x80,
+
+			// element 1: type and value
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, ccf.CBORTagTypeRef,
+			// bytes, 0 bytes follow
+			0x40,
+			// array, 1 items follow
+			0x81,
+			// attachments
+			// array, 1 items follow
+			0x81,
+			// array, 2 items follow
+			0x82,
+			// tag
+			0xd8, ccf.CBORTagSimpleType,
+			// Int type ID (4)
+			0x04,
+			// tag (big number)
+			0xc2,
+			// bytes, 1 byte follow
+			0x41,
+			// 1
+			0x01,
+		}
+
+		_, err := ccf.Decode(nil, data)
+		require.ErrorContains(t, err, "unexpected non-attachment value 1 in attachments of composite-value")
+	})
+}
+
 func TestEncodeInclusiveRange(t *testing.T) {
 
 	t.Parallel()
@@ -17138,11 +17479,21 @@ func TestEncodeEventWithAttachment(t *testing.T) {
 
 	v := exportEventFromScript(t, script)
 
-	_, err := ccf.Encode(v)
+	encoded, err := ccf.Encode(v)
+	require.NoError(t, err)
+
+	decoded, err := ccf.Decode(nil, encoded)
+	require.NoError(t, err)
+
+	// NOTE: the base type of attachment types is not encoded
+	require.Equal(t, v.String(), decoded.String())
+
+	s := decoded.(cadence.Event).SearchFieldByName("s").(cadence.Struct)
+	require.Len(t, s.Attachments(), 1)
 
-	var attachmentFieldError ccf.AttachmentFieldNotSupportedEncodingError
-	require.ErrorAs(t, err, &attachmentFieldError)
-	require.Implements(t, (*errors.UserError)(nil), attachmentFieldError)
+	attachment := s.Attachments()[0]
+	require.Equal(t, "s.0000000000000000000000000000000000000000000000000000000000000000.A", attachment.Type().ID())
+	require.Equal(t, cadence.NewInt(3), attachment.SearchFieldByName("y"))
 }
 
 func exportEventFromScript(t *testing.T, script string) cadence.Event {
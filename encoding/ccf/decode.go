@@ -21,6 +21,7 @@ package ccf
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	goRuntime "runtime"
@@ -117,6 +118,14 @@ type DecMode interface {
 	// NewDecoder initializes a Decoder that will decode CCF-encoded bytes from the
 	// given bytes.
 	NewDecoder(gauge common.MemoryGauge, b []byte) *Decoder
+
+	// NewStreamDecoder initializes a StreamDecoder that will decode
+	// a sequence of CCF messages from the given reader.
+	NewStreamDecoder(gauge common.MemoryGauge, r io.Reader, options StreamOptions) *StreamDecoder
+
+	// Validate checks that the given bytes are a valid CCF message
+	// which encodes a value of the expected type.
+	Validate(b []byte, expectedType cadence.Type) error
 }
 
 // EnforceSortMode specifies how the decoder should enforce sort order.
@@ -1183,14 +1192,123 @@ func (d *Decoder) decodeDictionary(typ *cadence.DictionaryType, types *cadenceTy
 // language=CDDL
 // composite-value = [* (field: value)]
 func (d *Decoder) decodeComposite(fieldTypes []cadence.Field, types *cadenceTypeByCCFTypeID) ([]cadence.Value, error) {
-	fieldCount := len(fieldTypes)
-
 	// Decode number of fields.
-	err := decodeCBORArrayWithKnownSize(d.dec, uint64(fieldCount))
+	err := decodeCBORArrayWithKnownSize(d.dec, uint64(len(fieldTypes)))
 	if err != nil {
 		return nil, err
 	}
 
+	return d.decodeCompositeFieldValues(fieldTypes, types)
+}
+
+// decodeCompositeWithAttachments decodes encoded composite-value
+// of a composite which supports attachments as
+// language=CDDL
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
+func (d *Decoder) decodeCompositeWithAttachments(
+	fieldTypes []cadence.Field,
+	types *cadenceTypeByCCFTypeID,
+) (
+	[]cadence.Value,
+	[]cadence.Attachment,
+	error,
+) {
+	fieldCount := uint64(len(fieldTypes))
+
+	// Decode number of fields (and attachments).
+	n, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n != fieldCount && n != fieldCount+1 {
+		return nil, nil, fmt.Errorf(
+			"CBOR array has %d elements (expected %d or %d elements)",
+			n,
+			fieldCount,
+			fieldCount+1,
+		)
+	}
+
+	fieldValues, err := d.decodeCompositeFieldValues(fieldTypes, types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n == fieldCount {
+		return fieldValues, nil, nil
+	}
+
+	attachments, err := d.decodeCompositeAttachments(types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fieldValues, attachments, nil
+}
+
+// decodeCompositeAttachments decodes encoded attachments as
+// language=CDDL
+// attachments = [+ inline-type-and-value]
+func (d *Decoder) decodeCompositeAttachments(types *cadenceTypeByCCFTypeID) ([]cadence.Attachment, error) {
+	// Decode number of attachments.
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	// "Valid CCF Encoding Requirements" in CCF specs:
+	//
+	//   "attachments MUST be omitted if composite-value has no attachments."
+	if count == 0 {
+		return nil, errors.New("unexpected empty attachments in composite-value")
+	}
+
+	attachments := make([]cadence.Attachment, count)
+	attachmentTypeIDs := make(map[string]struct{}, count)
+	var previousAttachmentTypeID string
+
+	for i := 0; i < int(count); i++ {
+		value, err := d.decodeTypeAndValue(types)
+		if err != nil {
+			return nil, err
+		}
+
+		attachment, ok := value.(cadence.Attachment)
+		if !ok {
+			return nil, fmt.Errorf("unexpected non-attachment value %s in attachments of composite-value", value)
+		}
+
+		attachmentTypeID := attachment.Type().ID()
+
+		// "Valid CCF Encoding Requirements" in CCF specs:
+		//
+		//   "attachment types MUST be unique in composite-value.attachments."
+		if _, ok := attachmentTypeIDs[attachmentTypeID]; ok {
+			return nil, fmt.Errorf("found duplicate attachment type %s in composite-value", attachmentTypeID)
+		}
+
+		if d.dm.enforceSortCompositeFields == EnforceSortBytewiseLexical {
+			// "Deterministic CCF Encoding Requirements" in CCF specs:
+			//
+			//   "composite-value.attachments MUST be sorted by attachment's cadence-type-id."
+			if !stringsAreSortedBytewise(previousAttachmentTypeID, attachmentTypeID) {
+				return nil, fmt.Errorf("attachments are not sorted in composite-value (%s, %s)", previousAttachmentTypeID, attachmentTypeID)
+			}
+		}
+
+		attachmentTypeIDs[attachmentTypeID] = struct{}{}
+		previousAttachmentTypeID = attachmentTypeID
+
+		attachments[i] = attachment
+	}
+
+	return attachments, nil
+}
+
+func (d *Decoder) decodeCompositeFieldValues(fieldTypes []cadence.Field, types *cadenceTypeByCCFTypeID) ([]cadence.Value, error) {
+	fieldCount := len(fieldTypes)
+
 	common.UseMemory(d.gauge, common.MemoryUsage{
 		Kind:   common.MemoryKindCadenceField,
 		Amount: uint64(fieldCount),
@@ -1218,9 +1336,9 @@ func getInterfaceTypeFields(cadence.InterfaceType) []cadence.Field
 
 // decodeStruct decodes encoded composite-value as
 // language=CDDL
-// composite-value = [* (field: value)]
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
 func (d *Decoder) decodeStruct(typ *cadence.StructType, types *cadenceTypeByCCFTypeID) (cadence.Value, error) {
-	fieldValues, err := d.decodeComposite(getCompositeTypeFields(typ), types)
+	fieldValues, attachments, err := d.decodeCompositeWithAttachments(getCompositeTypeFields(typ), types)
 	if err != nil {
 		return nil, err
 	}
@@ -1237,14 +1355,16 @@ func (d *Decoder) decodeStruct(typ *cadence.StructType, types *cadenceTypeByCCFT
 	}
 
 	// typ is already metered at creation.
-	return v.WithType(typ), nil
+	return v.
+		WithType(typ).
+		WithAttachments(attachments), nil
 }
 
 // decodeResource decodes encoded composite-value as
 // language=CDDL
-// composite-value = [* (field: value)]
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
 func (d *Decoder) decodeResource(typ *cadence.ResourceType, types *cadenceTypeByCCFTypeID) (cadence.Value, error) {
-	fieldValues, err := d.decodeComposite(getCompositeTypeFields(typ), types)
+	fieldValues, attachments, err := d.decodeCompositeWithAttachments(getCompositeTypeFields(typ), types)
 	if err != nil {
 		return nil, err
 	}
@@ -1261,7 +1381,9 @@ func (d *Decoder) decodeResource(typ *cadence.ResourceType, types *cadenceTypeBy
 	}
 
 	// typ is already metered at creation.
-	return resource.WithType(typ), nil
+	return resource.
+		WithType(typ).
+		WithAttachments(attachments), nil
 }
 
 // decodeEvent decodes encoded composite-value as
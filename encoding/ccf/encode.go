@@ -72,6 +72,10 @@ type EncMode interface {
 	// NewEncoder initializes an Encoder that will write CCF-encoded bytes to the
 	// given io.Writer.
 	NewEncoder(w io.Writer) *Encoder
+
+	// CDDL returns a CDDL schema of the CCF messages produced
+	// by this encoding mode for values of the given type.
+	CDDL(t cadence.Type) string
 }
 
 type SortMode int
@@ -178,24 +182,14 @@ var defaultEncMode = &encMode{}
 
 // AttachmentFieldNotSupportedEncodingError is a user error that is returned
 // when encoding composite value (such as Event) that has cadence.Attachment field.
+//
+// Deprecated: Attachments are supported and encoded, so this error is no longer returned.
 type AttachmentFieldNotSupportedEncodingError struct {
 	compositeType  string
 	fieldCount     int
 	fieldTypeCount int
 }
 
-func newAttachmentFieldNotSupportedError(
-	compositeType string,
-	fieldCount int,
-	fieldTypeCount int,
-) error {
-	return AttachmentFieldNotSupportedEncodingError{
-		compositeType:  compositeType,
-		fieldCount:     fieldCount,
-		fieldTypeCount: fieldTypeCount,
-	}
-}
-
 func (e AttachmentFieldNotSupportedEncodingError) Error() string {
 	return fmt.Sprintf(
 		"encoding attachment field in composite value isn't supported: %s field count %d doesn't match declared field type count %d",
@@ -1066,22 +1060,24 @@ func getCompositeFieldValues(cadence.Composite) []cadence.Value
 
 // encodeStruct encodes cadence.Struct as
 // language=CDDL
-// composite-value = [* (field: value)]
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
 func (e *Encoder) encodeStruct(v cadence.Struct, tids ccfTypeIDByCadenceType) error {
 	return e.encodeComposite(
 		v.StructType,
 		getCompositeFieldValues(v),
+		v.Attachments(),
 		tids,
 	)
 }
 
 // encodeResource encodes cadence.Resource as
 // language=CDDL
-// composite-value = [* (field: value)]
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
 func (e *Encoder) encodeResource(v cadence.Resource, tids ccfTypeIDByCadenceType) error {
 	return e.encodeComposite(
 		v.ResourceType,
 		getCompositeFieldValues(v),
+		v.Attachments(),
 		tids,
 	)
 }
@@ -1093,6 +1089,7 @@ func (e *Encoder) encodeEvent(v cadence.Event, tids ccfTypeIDByCadenceType) erro
 	return e.encodeComposite(
 		v.EventType,
 		getCompositeFieldValues(v),
+		nil,
 		tids,
 	)
 }
@@ -1104,6 +1101,7 @@ func (e *Encoder) encodeContract(v cadence.Contract, tids ccfTypeIDByCadenceType
 	return e.encodeComposite(
 		v.ContractType,
 		getCompositeFieldValues(v),
+		nil,
 		tids,
 	)
 }
@@ -1115,6 +1113,7 @@ func (e *Encoder) encodeEnum(v cadence.Enum, tids ccfTypeIDByCadenceType) error
 	return e.encodeComposite(
 		v.EnumType,
 		getCompositeFieldValues(v),
+		nil,
 		tids,
 	)
 }
@@ -1126,37 +1125,25 @@ func (e *Encoder) encodeAttachment(v cadence.Attachment, tids ccfTypeIDByCadence
 	return e.encodeComposite(
 		v.AttachmentType,
 		getCompositeFieldValues(v),
+		nil,
 		tids,
 	)
 }
 
 // encodeComposite encodes composite types as
 // language=CDDL
-// composite-value = [* (field: value)]
+// composite-value = [* (field: value), ? attachments: [+ inline-type-and-value]]
+//
+// The attachments element is only present if the composite value has attachments.
 func (e *Encoder) encodeComposite(
 	typ cadence.CompositeType,
 	fields []cadence.Value,
+	attachments []cadence.Attachment,
 	tids ccfTypeIDByCadenceType,
 ) error {
 	staticFieldTypes := getCompositeTypeFields(typ)
 
 	if len(staticFieldTypes) != len(fields) {
-
-		// The CCF encoder requires field values and types to match. However,
-		// composite values that have attachment field don't satisfy this requirement because:
-		// - composite field values INCLUDE attachment field values
-		// - composite field types EXCLUDE attachment field types
-		// Given this, CCF encoder will return a user error if composite value has attachment field.
-		for _, f := range fields {
-			if _, ok := f.(cadence.Attachment); ok {
-				panic(newAttachmentFieldNotSupportedError(
-					typ.ID(),
-					len(fields),
-					len(staticFieldTypes),
-				))
-			}
-		}
-
 		panic(cadenceErrors.NewUnexpectedError(
 			"%s field count %d doesn't match declared field type count %d",
 			typ.ID(),
@@ -1165,17 +1152,40 @@ func (e *Encoder) encodeComposite(
 		))
 	}
 
-	// Encode array head with number of fields.
-	err := e.enc.EncodeArrayHead(uint64(len(fields)))
+	elementCount := len(fields)
+	if len(attachments) > 0 {
+		elementCount++
+	}
+
+	// Encode array head with number of fields (and attachments).
+	err := e.enc.EncodeArrayHead(uint64(elementCount))
+	if err != nil {
+		return err
+	}
+
+	err = e.encodeCompositeFields(typ, staticFieldTypes, fields, tids)
 	if err != nil {
 		return err
 	}
 
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	return e.encodeCompositeAttachments(attachments, tids)
+}
+
+func (e *Encoder) encodeCompositeFields(
+	typ cadence.CompositeType,
+	staticFieldTypes []cadence.Field,
+	fields []cadence.Value,
+	tids ccfTypeIDByCadenceType,
+) error {
 	switch e.em.sortCompositeFields {
 	case SortNone:
 		// Encode fields without sorting.
 		for i, field := range fields {
-			err = e.encodeValue(field, staticFieldTypes[i].Type, tids)
+			err := e.encodeValue(field, staticFieldTypes[i].Type, tids)
 			if err != nil {
 				return err
 			}
@@ -1201,7 +1211,7 @@ func (e *Encoder) encodeComposite(
 
 			for _, index := range sortedIndexes {
 				// Encode sorted field as value.
-				err = e.encodeValue(fields[index], staticFieldTypes[index].Type, tids)
+				err := e.encodeValue(fields[index], staticFieldTypes[index].Type, tids)
 				if err != nil {
 					return err
 				}
@@ -1215,6 +1225,57 @@ func (e *Encoder) encodeComposite(
 	}
 }
 
+// encodeCompositeAttachments encodes attachments of a composite value as
+// language=CDDL
+// attachments = [+ inline-type-and-value]
+//
+// Attachments are sorted by type ID if composite fields are sorted,
+// so that the encoding is deterministic.
+func (e *Encoder) encodeCompositeAttachments(
+	attachments []cadence.Attachment,
+	tids ccfTypeIDByCadenceType,
+) error {
+	// Encode array head with number of attachments.
+	err := e.enc.EncodeArrayHead(uint64(len(attachments)))
+	if err != nil {
+		return err
+	}
+
+	switch e.em.sortCompositeFields {
+	case SortNone:
+		// Encode attachments without sorting.
+		for _, attachment := range attachments {
+			err = e.encodeInlineTypeAndValue(attachment, tids)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case SortBytewiseLexical:
+		attachmentTypes := make([]cadence.Type, len(attachments))
+		for i, attachment := range attachments {
+			attachmentTypes[i] = attachment.Type()
+		}
+
+		sorter := newBytewiseCadenceTypeSorter(attachmentTypes)
+
+		sort.Sort(sorter)
+
+		for _, index := range sorter.indexes {
+			// Encode sorted attachment.
+			err = e.encodeInlineTypeAndValue(attachments[index], tids)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		panic(cadenceErrors.NewUnexpectedError("unsupported sort option for composite fields: %d", e.em.sortCompositeFields))
+	}
+}
+
 // encodePath encodes cadence.Path as
 // language=CDDL
 // path-value = [
@@ -0,0 +1,705 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"errors"
+	"fmt"
+	goRuntime "runtime"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	cadenceErrors "github.com/onflow/cadence/errors"
+)
+
+type cddlRule struct {
+	name       string
+	definition string
+}
+
+// cddlSimpleValueRules are the CDDL rules of values of types
+// which are encoded without type information
+var cddlSimpleValueRules = map[cadence.Type]cddlRule{
+	cadence.VoidType:        {"void-value", "nil"},
+	cadence.BoolType:        {"bool-value", "bool"},
+	cadence.CharacterType:   {"character-value", "tstr"},
+	cadence.StringType:      {"string-value", "tstr"},
+	cadence.AddressType:     {"address-value", "bstr .size 8"},
+	cadence.IntType:         {"int-value", "bigint"},
+	cadence.Int8Type:        {"int8-value", "(int .ge -128) .le 127"},
+	cadence.Int16Type:       {"int16-value", "(int .ge -32768) .le 32767"},
+	cadence.Int32Type:       {"int32-value", "(int .ge -2147483648) .le 2147483647"},
+	cadence.Int64Type:       {"int64-value", "(int .ge -9223372036854775808) .le 9223372036854775807"},
+	cadence.Int128Type:      {"int128-value", "bigint"},
+	cadence.Int256Type:      {"int256-value", "bigint"},
+	cadence.UIntType:        {"uint-value", "biguint"},
+	cadence.UInt8Type:       {"uint8-value", "uint .le 255"},
+	cadence.UInt16Type:      {"uint16-value", "uint .le 65535"},
+	cadence.UInt32Type:      {"uint32-value", "uint .le 4294967295"},
+	cadence.UInt64Type:      {"uint64-value", "uint .le 18446744073709551615"},
+	cadence.UInt128Type:     {"uint128-value", "biguint"},
+	cadence.UInt256Type:     {"uint256-value", "biguint"},
+	cadence.Word8Type:       {"word8-value", "uint .le 255"},
+	cadence.Word16Type:      {"word16-value", "uint .le 65535"},
+	cadence.Word32Type:      {"word32-value", "uint .le 4294967295"},
+	cadence.Word64Type:      {"word64-value", "uint .le 18446744073709551615"},
+	cadence.Word128Type:     {"word128-value", "biguint"},
+	cadence.Word256Type:     {"word256-value", "biguint"},
+	cadence.Fix64Type:       {"fix64-value", "(int .ge -9223372036854775808) .le 9223372036854775807"},
+	cadence.UFix64Type:      {"ufix64-value", "uint .le 18446744073709551615"},
+	cadence.StoragePathType: {"path-value", "[domain: uint, identifier: tstr]"},
+	cadence.PublicPathType:  {"path-value", "[domain: uint, identifier: tstr]"},
+	cadence.PrivatePathType: {"path-value", "[domain: uint, identifier: tstr]"},
+	cadence.MetaType:        {"type-value", "any"},
+}
+
+// CDDL returns a CDDL (RFC 8610) schema of the CCF messages
+// produced by this encoding mode for values of the given type.
+//
+// Type definitions and inline types are described as `any`,
+// values are described by the given type.
+func (em *encMode) CDDL(t cadence.Type) string {
+	g := &cddlGenerator{
+		ruleIndex:           map[string]int{},
+		sortCompositeFields: em.sortCompositeFields,
+	}
+
+	g.addRule(
+		"message",
+		"ccf-typedef-and-value-message / ccf-type-and-value-message",
+	)
+	g.addRule(
+		"ccf-typedef-and-value-message",
+		"#6.129([typedef: composite-typedef, type-and-value: [type: inline-type, value: value]])",
+	)
+	g.addRule(
+		"ccf-type-and-value-message",
+		"#6.130([type: inline-type, value: value])",
+	)
+	g.addRule("composite-typedef", "[+ any]")
+	g.addRule("inline-type", "any")
+
+	index := g.reserveRule("value")
+	g.rules[index].definition = g.valueType(t)
+
+	var sb strings.Builder
+
+	sb.WriteString("; CCF messages encoding values of type ")
+	sb.WriteString(t.ID())
+	sb.WriteByte('\n')
+
+	for _, rule := range g.rules {
+		sb.WriteString(rule.name)
+		sb.WriteString(" = ")
+		sb.WriteString(rule.definition)
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// CDDL returns a CDDL (RFC 8610) schema of the CCF messages
+// produced by the default encoding mode for values of the given type.
+func CDDL(t cadence.Type) string {
+	return defaultEncMode.CDDL(t)
+}
+
+type cddlGenerator struct {
+	rules               []cddlRule
+	ruleIndex           map[string]int
+	sortCompositeFields SortMode
+}
+
+func (g *cddlGenerator) addRule(name, definition string) {
+	if _, ok := g.ruleIndex[name]; ok {
+		return
+	}
+	g.ruleIndex[name] = len(g.rules)
+	g.rules = append(g.rules, cddlRule{name: name, definition: definition})
+}
+
+// reserveRule adds a rule without a definition, so recursive types can refer to it
+func (g *cddlGenerator) reserveRule(name string) int {
+	g.addRule(name, "")
+	return g.ruleIndex[name]
+}
+
+// valueType returns the CDDL type of encoded values of the given static type
+func (g *cddlGenerator) valueType(t cadence.Type) string {
+	if rule, ok := cddlSimpleValueRules[t]; ok {
+		g.addRule(rule.name, rule.definition)
+		return rule.name
+	}
+
+	switch t := t.(type) {
+	case *cadence.OptionalType:
+		return "nil / " + g.valueType(t.Type)
+
+	case *cadence.VariableSizedArrayType:
+		return fmt.Sprintf("[* %s]", g.valueType(t.ElementType))
+
+	case *cadence.ConstantSizedArrayType:
+		return fmt.Sprintf("[%d*%d %s]", t.Size, t.Size, g.valueType(t.ElementType))
+
+	case *cadence.DictionaryType:
+		return fmt.Sprintf(
+			"[* (key: %s, value: %s)]",
+			g.valueType(t.KeyType),
+			g.valueType(t.ElementType),
+		)
+
+	case *cadence.InclusiveRangeType:
+		elementType := g.valueType(t.ElementType)
+		return fmt.Sprintf(
+			"[start: %s, end: %s, step: %s]",
+			elementType,
+			elementType,
+			elementType,
+		)
+
+	case *cadence.ReferenceType:
+		// Referenced values are encoded as their dereferenced value.
+		return g.valueType(t.Type)
+
+	case *cadence.CapabilityType:
+		if t.BorrowType != nil {
+			g.addRule(
+				"capability-value",
+				fmt.Sprintf(
+					"[address: %s, id: %s]",
+					g.valueType(cadence.AddressType),
+					g.valueType(cadence.UInt64Type),
+				),
+			)
+			return "capability-value"
+		}
+
+	case cadence.CompositeType:
+		return g.compositeValueType(t)
+	}
+
+	// Values of abstract types are encoded with their runtime type.
+	g.addRule("type-and-value", "#6.130(inline-type-and-value)")
+	g.addRule("inline-type-and-value", "[type: inline-type, value: any]")
+	return "type-and-value"
+}
+
+func (g *cddlGenerator) compositeValueType(t cadence.CompositeType) string {
+	name := cddlRuleName(t.ID())
+
+	if _, ok := g.ruleIndex[name]; ok {
+		return name
+	}
+
+	index := g.reserveRule(name)
+
+	fields := getCompositeTypeFields(t)
+	if g.sortCompositeFields == SortBytewiseLexical {
+		sorter := newBytewiseFieldSorter(fields)
+		sort.Sort(sorter)
+
+		sortedFields := make([]cadence.Field, len(fields))
+		for i, index := range sorter.indexes {
+			sortedFields[i] = fields[index]
+		}
+		fields = sortedFields
+	}
+
+	entries := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		entries = append(
+			entries,
+			fmt.Sprintf("%s: %s", field.Identifier, g.valueType(field.Type)),
+		)
+	}
+
+	switch t.(type) {
+	case *cadence.StructType, *cadence.ResourceType:
+		g.addRule("inline-type-and-value", "[type: inline-type, value: any]")
+		entries = append(entries, "? attachments: [+ inline-type-and-value]")
+	}
+
+	g.rules[index].definition = "[" + strings.Join(entries, ", ") + "]"
+
+	return name
+}
+
+// cddlRuleName returns a valid CDDL rule name for the values of the type with the given ID
+func cddlRuleName(typeID string) string {
+	var sb strings.Builder
+
+	for i, r := range typeID {
+		switch {
+		case r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r == '_', r == '@', r == '$':
+
+			sb.WriteRune(r)
+
+		case i > 0 && (r >= '0' && r <= '9' || r == '.' || r == '-'):
+			sb.WriteRune(r)
+
+		default:
+			sb.WriteByte('_')
+		}
+	}
+
+	sb.WriteString("-value")
+
+	return sb.String()
+}
+
+// Validate checks that the given bytes are a valid CCF message
+// which encodes a value of the expected type.
+//
+// Composite, array, and dictionary values are only checked,
+// and not decoded, so payloads can be rejected early and cheaply.
+//
+// Values of abstract expected types (e.g. AnyStruct) may have any type.
+func (dm *decMode) Validate(b []byte, expectedType cadence.Type) error {
+	dec := dm.NewDecoder(nil, b)
+
+	err := dec.validate(expectedType)
+	if err != nil {
+		return err
+	}
+
+	if dec.dec.NumBytesDecoded() != len(b) {
+		return cadenceErrors.NewDefaultUserError("ccf: invalid message: decoded %d bytes, received %d bytes", dec.dec.NumBytesDecoded(), len(b))
+	}
+
+	return nil
+}
+
+// Validate checks that the given bytes are a valid CCF message
+// which encodes a value of the expected type.
+func Validate(b []byte, expectedType cadence.Type) error {
+	return defaultDecMode.Validate(b, expectedType)
+}
+
+func (d *Decoder) validate(expectedType cadence.Type) (err error) {
+	// Capture panics that occur during decoding.
+	defer func() {
+		// Recover panic error if there is any.
+		if r := recover(); r != nil {
+			// Don't recover Go errors, internal errors, or non-errors.
+			switch r := r.(type) {
+			case goRuntime.Error, cadenceErrors.InternalError:
+				panic(r)
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+
+		// Add context to error if there is any.
+		if err != nil {
+			err = cadenceErrors.NewDefaultUserError("ccf: invalid message: %s", err)
+		}
+	}()
+
+	// Decode top level message.
+	tagNum, err := d.dec.DecodeTagNumber()
+	if err != nil {
+		return err
+	}
+
+	var types *cadenceTypeByCCFTypeID
+
+	switch tagNum {
+	case CBORTagTypeDefAndValue:
+		// Decode array head of length 2
+		err := decodeCBORArrayWithKnownSize(d.dec, 2)
+		if err != nil {
+			return err
+		}
+
+		// element 0: typedef
+		types, err = d.decodeTypeDefs()
+		if err != nil {
+			return err
+		}
+
+	case CBORTagTypeAndValue:
+		types = newCadenceTypeByCCFTypeID()
+
+	default:
+		return fmt.Errorf(
+			"unsupported top level CCF message with CBOR tag number %d",
+			tagNum,
+		)
+	}
+
+	// Decode array head of length 2.
+	err = decodeCBORArrayWithKnownSize(d.dec, 2)
+	if err != nil {
+		return err
+	}
+
+	// element 0: inline-type
+	t, err := d.decodeInlineType(types)
+	if err != nil {
+		return err
+	}
+
+	err = checkEncodedType(expectedType, t, map[cadence.Type]struct{}{})
+	if err != nil {
+		return err
+	}
+
+	// element 1: value
+	err = d.validateValue(t, types)
+	if err != nil {
+		return err
+	}
+
+	// Check if there is any unreferenced type definition.
+	if types.hasUnreferenced() {
+		return errors.New("found unreferenced type definition")
+	}
+
+	return nil
+}
+
+// validateValue checks the encoded value of the given type.
+// Values of simple types are decoded, all other values are only traversed.
+func (d *Decoder) validateValue(t cadence.Type, types *cadenceTypeByCCFTypeID) error {
+	if _, ok := cddlSimpleValueRules[t]; ok {
+		_, err := d.decodeValue(t, types)
+		return err
+	}
+
+	switch t := t.(type) {
+	case *cadence.OptionalType:
+		nt, err := d.dec.NextType()
+		if err != nil {
+			return err
+		}
+		if nt == cbor.NilType {
+			return d.dec.DecodeNil()
+		}
+		return d.validateValue(t.Type, types)
+
+	case *cadence.VariableSizedArrayType:
+		n, err := d.dec.DecodeArrayHead()
+		if err != nil {
+			return err
+		}
+		return d.validateValues(t.ElementType, n, types)
+
+	case *cadence.ConstantSizedArrayType:
+		err := decodeCBORArrayWithKnownSize(d.dec, uint64(t.Size))
+		if err != nil {
+			return err
+		}
+		return d.validateValues(t.ElementType, uint64(t.Size), types)
+
+	case *cadence.DictionaryType:
+		n, err := d.dec.DecodeArrayHead()
+		if err != nil {
+			return err
+		}
+
+		// Check if number of elements is even.
+		if n%2 != 0 {
+			return fmt.Errorf(
+				"encoded dict-value has %d elements (expected even number of elements)",
+				n,
+			)
+		}
+
+		for i := uint64(0); i < n/2; i++ {
+			err = d.validateValue(t.KeyType, types)
+			if err != nil {
+				return err
+			}
+			err = d.validateValue(t.ElementType, types)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *cadence.InclusiveRangeType:
+		err := decodeCBORArrayWithKnownSize(d.dec, 3)
+		if err != nil {
+			return err
+		}
+		return d.validateValues(t.ElementType, 3, types)
+
+	case *cadence.ReferenceType:
+		// When static type is a reference type, encoded value is its deferenced type.
+		return d.validateValue(t.Type, types)
+
+	case *cadence.CapabilityType:
+		_, err := d.decodeCapability(t, types)
+		return err
+
+	case cadence.CompositeType:
+		return d.validateComposite(t, types)
+	}
+
+	// Values of abstract types are encoded as ccf-type-and-value-message.
+
+	nt, err := d.dec.NextType()
+	if err != nil {
+		return err
+	}
+
+	if nt == cbor.NilType {
+		// Decode nil value (such as cyclic reference value).
+		return d.dec.DecodeNil()
+	}
+
+	err = decodeCBORTagWithKnownNumber(d.dec, CBORTagTypeAndValue)
+	if err != nil {
+		return fmt.Errorf("unexpected encoded value of Cadence type %s (%T): %s", t.ID(), t, err.Error())
+	}
+
+	runtimeType, err := d.validateInlineType(types)
+	if err != nil {
+		return err
+	}
+
+	return d.validateValue(runtimeType, types)
+}
+
+func (d *Decoder) validateValues(t cadence.Type, n uint64, types *cadenceTypeByCCFTypeID) error {
+	for i := uint64(0); i < n; i++ {
+		err := d.validateValue(t, types)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateInlineType decodes the array head and the inline type of an inline-type-and-value
+func (d *Decoder) validateInlineType(types *cadenceTypeByCCFTypeID) (cadence.Type, error) {
+	// Decode array head of length 2.
+	err := decodeCBORArrayWithKnownSize(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	// element 0: inline-type
+	return d.decodeInlineType(types)
+}
+
+func (d *Decoder) validateComposite(t cadence.CompositeType, types *cadenceTypeByCCFTypeID) error {
+	fieldTypes := getCompositeTypeFields(t)
+	fieldCount := uint64(len(fieldTypes))
+
+	n, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return err
+	}
+
+	var hasAttachments bool
+	switch t.(type) {
+	case *cadence.StructType, *cadence.ResourceType:
+		hasAttachments = n == fieldCount+1
+	}
+
+	if n != fieldCount && !hasAttachments {
+		return fmt.Errorf(
+			"encoded composite-value has %d elements (expected %d elements)",
+			n,
+			fieldCount,
+		)
+	}
+
+	for _, field := range fieldTypes {
+		err = d.validateValue(field.Type, types)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasAttachments {
+		return nil
+	}
+
+	n, err = d.dec.DecodeArrayHead()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return errors.New("unexpected empty attachments in composite-value")
+	}
+
+	for i := uint64(0); i < n; i++ {
+		attachmentType, err := d.validateInlineType(types)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := attachmentType.(*cadence.AttachmentType); !ok {
+			return fmt.Errorf(
+				"unexpected non-attachment type %s in attachments of composite-value",
+				attachmentType.ID(),
+			)
+		}
+
+		err = d.validateValue(attachmentType, types)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkEncodedType checks that the encoded type is the expected type.
+// Fields of composite types are compared by name and type.
+func checkEncodedType(
+	expectedType cadence.Type,
+	encodedType cadence.Type,
+	checked map[cadence.Type]struct{},
+) error {
+
+	switch expectedType := expectedType.(type) {
+	case *cadence.OptionalType:
+		if isOptionalNeverType(encodedType) {
+			return nil
+		}
+		if encodedType, ok := encodedType.(*cadence.OptionalType); ok {
+			return checkEncodedType(expectedType.Type, encodedType.Type, checked)
+		}
+
+	case *cadence.VariableSizedArrayType:
+		if encodedType, ok := encodedType.(*cadence.VariableSizedArrayType); ok {
+			return checkEncodedType(expectedType.ElementType, encodedType.ElementType, checked)
+		}
+
+	case *cadence.ConstantSizedArrayType:
+		if encodedType, ok := encodedType.(*cadence.ConstantSizedArrayType); ok &&
+			encodedType.Size == expectedType.Size {
+
+			return checkEncodedType(expectedType.ElementType, encodedType.ElementType, checked)
+		}
+
+	case *cadence.DictionaryType:
+		if encodedType, ok := encodedType.(*cadence.DictionaryType); ok {
+			err := checkEncodedType(expectedType.KeyType, encodedType.KeyType, checked)
+			if err != nil {
+				return err
+			}
+			return checkEncodedType(expectedType.ElementType, encodedType.ElementType, checked)
+		}
+
+	case *cadence.InclusiveRangeType:
+		if encodedType, ok := encodedType.(*cadence.InclusiveRangeType); ok {
+			return checkEncodedType(expectedType.ElementType, encodedType.ElementType, checked)
+		}
+
+	case *cadence.ReferenceType:
+		// Referenced values are encoded as their dereferenced value.
+		return checkEncodedType(expectedType.Type, encodedType, checked)
+
+	case *cadence.CapabilityType:
+		if encodedType, ok := encodedType.(*cadence.CapabilityType); ok &&
+			(expectedType.BorrowType == nil || expectedType.Equal(encodedType)) {
+
+			return nil
+		}
+
+	case cadence.CompositeType:
+		if expectedType.Equal(encodedType) {
+			return checkEncodedCompositeType(
+				expectedType,
+				encodedType.(cadence.CompositeType),
+				checked,
+			)
+		}
+
+	default:
+		if expectedType.Equal(encodedType) {
+			return nil
+		}
+
+		if _, ok := cddlSimpleValueRules[expectedType]; !ok {
+			// Values of abstract types may have any type.
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"encoded value has type %s (expected %s)",
+		encodedType.ID(),
+		expectedType.ID(),
+	)
+}
+
+func checkEncodedCompositeType(
+	expectedType cadence.CompositeType,
+	encodedType cadence.CompositeType,
+	checked map[cadence.Type]struct{},
+) error {
+
+	if _, ok := checked[expectedType]; ok {
+		return nil
+	}
+	checked[expectedType] = struct{}{}
+
+	expectedFields := getCompositeTypeFields(expectedType)
+	encodedFields := getCompositeTypeFields(encodedType)
+
+	if len(expectedFields) != len(encodedFields) {
+		return fmt.Errorf(
+			"encoded type %s has %d fields (expected %d fields)",
+			encodedType.ID(),
+			len(encodedFields),
+			len(expectedFields),
+		)
+	}
+
+	encodedFieldTypes := make(map[string]cadence.Type, len(encodedFields))
+	for _, field := range encodedFields {
+		encodedFieldTypes[field.Identifier] = field.Type
+	}
+
+	for _, field := range expectedFields {
+		encodedFieldType, ok := encodedFieldTypes[field.Identifier]
+		if !ok {
+			return fmt.Errorf(
+				"encoded type %s is missing field %s",
+				encodedType.ID(),
+				field.Identifier,
+			)
+		}
+
+		err := checkEncodedType(field.Type, encodedFieldType, checked)
+		if err != nil {
+			return fmt.Errorf(
+				"invalid field %s of encoded type %s: %w",
+				field.Identifier,
+				encodedType.ID(),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
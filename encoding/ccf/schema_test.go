@@ -0,0 +1,298 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/errors"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+func newSchemaTestStructType() *cadence.StructType {
+	fields := []cadence.Field{
+		{
+			Identifier: "b",
+			Type:       cadence.IntType,
+		},
+		{
+			Identifier: "a",
+			Type: &cadence.OptionalType{
+				Type: cadence.NewVariableSizedArrayType(cadence.UInt8Type),
+			},
+		},
+		{
+			Identifier: "next",
+		},
+		{
+			Identifier: "any",
+			Type:       cadence.AnyStructType,
+		},
+	}
+
+	structType := cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		fields,
+		nil,
+	)
+
+	fields[2].Type = &cadence.OptionalType{
+		Type: structType,
+	}
+
+	return structType
+}
+
+func TestCDDL(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t,
+			`; CCF messages encoding values of type {String:[UInt64;2]}
+message = ccf-typedef-and-value-message / ccf-type-and-value-message
+ccf-typedef-and-value-message = #6.129([typedef: composite-typedef, type-and-value: [type: inline-type, value: value]])
+ccf-type-and-value-message = #6.130([type: inline-type, value: value])
+composite-typedef = [+ any]
+inline-type = any
+value = [* (key: string-value, value: [2*2 uint64-value])]
+string-value = tstr
+uint64-value = uint .le 18446744073709551615
+`,
+			ccf.CDDL(
+				cadence.NewDictionaryType(
+					cadence.StringType,
+					cadence.NewConstantSizedArrayType(2, cadence.UInt64Type),
+				),
+			),
+		)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t,
+			`; CCF messages encoding values of type S.test.Foo
+message = ccf-typedef-and-value-message / ccf-type-and-value-message
+ccf-typedef-and-value-message = #6.129([typedef: composite-typedef, type-and-value: [type: inline-type, value: value]])
+ccf-type-and-value-message = #6.130([type: inline-type, value: value])
+composite-typedef = [+ any]
+inline-type = any
+value = S.test.Foo-value
+S.test.Foo-value = [b: int-value, a: nil / [* uint8-value], next: nil / S.test.Foo-value, any: type-and-value, ? attachments: [+ inline-type-and-value]]
+int-value = bigint
+uint8-value = uint .le 255
+type-and-value = #6.130(inline-type-and-value)
+inline-type-and-value = [type: inline-type, value: any]
+`,
+			ccf.CDDL(newSchemaTestStructType()),
+		)
+	})
+
+	t.Run("sorted fields", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Contains(t,
+			deterministicEncMode.CDDL(newSchemaTestStructType()),
+			"S.test.Foo-value = [a: nil / [* uint8-value], b: int-value, any: type-and-value, next: nil / S.test.Foo-value, ? attachments: [+ inline-type-and-value]]\n",
+		)
+	})
+}
+
+func TestValidate(t *testing.T) {
+
+	t.Parallel()
+
+	structType := newSchemaTestStructType()
+
+	newStruct := func(next cadence.Value) cadence.Struct {
+		return cadence.NewStruct([]cadence.Value{
+			cadence.NewInt(1),
+			cadence.NewOptional(
+				cadence.NewArray([]cadence.Value{
+					cadence.UInt8(2),
+				}).WithType(cadence.NewVariableSizedArrayType(cadence.UInt8Type)),
+			),
+			cadence.NewOptional(next),
+			cadence.String("any"),
+		}).WithType(structType)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []cadence.Value{
+			newStruct(nil),
+			newStruct(newStruct(nil)),
+			cadence.NewInt(42),
+			cadence.NewOptional(nil),
+			createFlowTokenTokensDepositedEvent(),
+			cadence.NewArray([]cadence.Value{
+				createFlowTokenTokensDepositedEvent(),
+				cadence.NewInt(1),
+			}).WithType(cadence.NewVariableSizedArrayType(cadence.AnyStructType)),
+		} {
+			encoded, err := ccf.Encode(value)
+			require.NoError(t, err)
+
+			err = ccf.Validate(encoded, value.Type())
+			require.NoError(t, err, value.String())
+
+			encoded, err = deterministicEncMode.Encode(value)
+			require.NoError(t, err)
+
+			err = deterministicDecMode.Validate(encoded, value.Type())
+			require.NoError(t, err, value.String())
+		}
+	})
+
+	t.Run("abstract", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newStruct(nil))
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded, cadence.AnyStructType)
+		require.NoError(t, err)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(cadence.NewOptional(nil))
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded, cadence.NewOptionalType(cadence.AddressType))
+		require.NoError(t, err)
+
+		encoded, err = ccf.Encode(cadence.NewOptional(cadence.NewInt(1)))
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded, cadence.NewOptionalType(cadence.AddressType))
+		require.ErrorContains(t, err, "encoded value has type Int (expected Address)")
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(cadence.NewInt(42))
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded, cadence.StringType)
+		require.ErrorContains(t, err, "ccf: invalid message: encoded value has type Int (expected String)")
+		require.True(t, errors.IsUserError(err))
+	})
+
+	t.Run("field mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newStruct(nil))
+		require.NoError(t, err)
+
+		otherStructType := cadence.NewStructType(
+			TestLocation,
+			"Foo",
+			[]cadence.Field{
+				{
+					Identifier: "b",
+					Type:       cadence.StringType,
+				},
+				{
+					Identifier: "a",
+					Type: &cadence.OptionalType{
+						Type: cadence.NewVariableSizedArrayType(cadence.UInt8Type),
+					},
+				},
+				{
+					Identifier: "next",
+					Type:       cadence.AnyStructType,
+				},
+				{
+					Identifier: "any",
+					Type:       cadence.AnyStructType,
+				},
+			},
+			nil,
+		)
+
+		err = ccf.Validate(encoded, otherStructType)
+		require.ErrorContains(t, err, "invalid field b of encoded type S.test.Foo: encoded value has type Int (expected String)")
+
+		missingFieldStructType := cadence.NewStructType(
+			TestLocation,
+			"Foo",
+			[]cadence.Field{
+				{
+					Identifier: "c",
+					Type:       cadence.IntType,
+				},
+			},
+			nil,
+		)
+
+		err = ccf.Validate(encoded, missingFieldStructType)
+		require.ErrorContains(t, err, "encoded type S.test.Foo has 4 fields (expected 1 fields)")
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		t.Parallel()
+
+		// Field b is declared as Int, but has a String value
+		value := cadence.NewStruct([]cadence.Value{
+			cadence.String("b"),
+			cadence.NewOptional(nil),
+			cadence.NewOptional(nil),
+			cadence.String("any"),
+		}).WithType(structType)
+
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded, structType)
+		require.ErrorContains(t, err, "ccf: invalid message: cbor: cannot decode CBOR tag type to big.Int")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(newStruct(nil))
+		require.NoError(t, err)
+
+		err = ccf.Validate(encoded[:len(encoded)-1], structType)
+		require.ErrorContains(t, err, "ccf: invalid message: unexpected EOF")
+	})
+
+	t.Run("extraneous data", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := ccf.Encode(cadence.NewInt(42))
+		require.NoError(t, err)
+
+		err = ccf.Validate(append(encoded, 0x00), cadence.IntType)
+		require.ErrorContains(t, err, "ccf: invalid message: decoded")
+	})
+}
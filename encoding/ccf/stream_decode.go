@@ -0,0 +1,633 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	goRuntime "runtime"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	cadenceErrors "github.com/onflow/cadence/errors"
+)
+
+// maxCachedTypeDefs is the maximum number of distinct type definitions
+// a StreamDecoder keeps for reuse across messages.
+const maxCachedTypeDefs = 1024
+
+// StreamOptions specifies options of a StreamDecoder.
+type StreamOptions struct {
+	// StreamArrays specifies if top-level array values are streamed,
+	// i.e. if their elements are returned one at a time, instead of the whole array.
+	// This allows decoding large CCF arrays of events lazily.
+	StreamArrays bool
+
+	// Fields specifies the names of the fields of top-level composite values (e.g. events)
+	// which are decoded. All other fields, and attachments, are skipped,
+	// and the type of the returned value only has the decoded fields.
+	// If Fields is empty, all fields are decoded.
+	//
+	// NOTE: Skipped fields are not validated.
+	Fields []string
+}
+
+// StreamDecoder decodes a sequence of CCF messages from an io.Reader.
+//
+// Type definitions are decoded once and reused for all messages
+// which have identical type definitions, e.g. events of the same type.
+type StreamDecoder struct {
+	dec *Decoder
+
+	streamArrays bool
+	fields       map[string]struct{}
+
+	// typeDefs are the decoded type definitions, by their encoding
+	typeDefs map[string]cachedTypeDefs
+
+	// projectedTypes are the composite types with only the projected fields,
+	// by the original composite type
+	projectedTypes map[cadence.CompositeType]cadence.CompositeType
+
+	// types are the types of the message which is currently decoded
+	types *cadenceTypeByCCFTypeID
+
+	// arrayElementType is the element type of the array which is currently streamed
+	arrayElementType cadence.Type
+	// arrayRemaining is the number of remaining elements of the array which is currently streamed
+	arrayRemaining uint64
+
+	err error
+}
+
+type cachedTypeDefs struct {
+	types           map[ccfTypeID]cadence.Type
+	referencedTypes map[ccfTypeID]struct{}
+	nextCCFTypeID   ccfTypeID
+}
+
+func (c cachedTypeDefs) newTypes() *cadenceTypeByCCFTypeID {
+	// NOTE: types are not modified after the type definitions are decoded,
+	// so they can be shared. References are tracked per message.
+	referencedTypes := make(map[ccfTypeID]struct{}, len(c.referencedTypes))
+	for id := range c.referencedTypes { //nolint:maprange
+		referencedTypes[id] = struct{}{}
+	}
+
+	return &cadenceTypeByCCFTypeID{
+		types:           c.types,
+		referencedTypes: referencedTypes,
+		nextCCFTypeID:   c.nextCCFTypeID,
+	}
+}
+
+// NewStreamDecoder initializes a StreamDecoder that will decode
+// a sequence of CCF messages from the given reader.
+func (dm *decMode) NewStreamDecoder(gauge common.MemoryGauge, r io.Reader, options StreamOptions) *StreamDecoder {
+	var fields map[string]struct{}
+	if len(options.Fields) > 0 {
+		fields = make(map[string]struct{}, len(options.Fields))
+		for _, field := range options.Fields {
+			fields[field] = struct{}{}
+		}
+	}
+
+	return &StreamDecoder{
+		dec: &Decoder{
+			dec:   dm.cborDecMode.NewStreamDecoder(r),
+			gauge: gauge,
+			dm:    dm,
+		},
+		streamArrays:   options.StreamArrays,
+		fields:         fields,
+		typeDefs:       map[string]cachedTypeDefs{},
+		projectedTypes: map[cadence.CompositeType]cadence.CompositeType{},
+	}
+}
+
+// NewStreamDecoder initializes a StreamDecoder that will decode
+// a sequence of CCF messages from the given reader.
+func NewStreamDecoder(gauge common.MemoryGauge, r io.Reader, options StreamOptions) *StreamDecoder {
+	return defaultDecMode.NewStreamDecoder(gauge, r, options)
+}
+
+// Next decodes the next value of the stream.
+//
+// If array streaming is enabled, the elements of top-level arrays are returned one at a time.
+// It returns io.EOF if there are no more values.
+//
+// Once an error is returned, all further calls return the same error,
+// as the stream cannot be resynchronized.
+func (s *StreamDecoder) Next() (value cadence.Value, err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	defer func() {
+		// Recover panic error if there is any.
+		if r := recover(); r != nil {
+			// Don't recover Go errors, internal errors, or non-errors.
+			switch r := r.(type) {
+			case goRuntime.Error, cadenceErrors.InternalError:
+				panic(r)
+			case error:
+				err = r
+			default:
+				panic(r)
+			}
+		}
+
+		if err == nil {
+			return
+		}
+
+		// Add context to error if there is any.
+		if err != io.EOF {
+			err = cadenceErrors.NewDefaultUserError("ccf: failed to decode: %s", err)
+		}
+
+		value = nil
+		s.err = err
+	}()
+
+	if s.arrayRemaining > 0 {
+		return s.nextArrayElement()
+	}
+
+	return s.nextMessage()
+}
+
+func (s *StreamDecoder) nextMessage() (cadence.Value, error) {
+	d := s.dec
+
+	// Check if there is another message.
+	_, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode top level message.
+	tagNum, err := d.dec.DecodeTagNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tagNum {
+	case CBORTagTypeDefAndValue:
+		// Decode ccf-typedef-and-value-message.
+
+		// Decode array head of length 2
+		err := decodeCBORArrayWithKnownSize(d.dec, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		// element 0: typedef
+		s.types, err = s.decodeTypeDefs()
+		if err != nil {
+			return nil, err
+		}
+
+	case CBORTagTypeAndValue:
+		// Decode ccf-type-and-value-message.
+		s.types = newCadenceTypeByCCFTypeID()
+
+	default:
+		return nil, fmt.Errorf(
+			"unsupported top level CCF message with CBOR tag number %d",
+			tagNum,
+		)
+	}
+
+	// Decode inline-type-and-value.
+
+	// Decode array head of length 2.
+	err = decodeCBORArrayWithKnownSize(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	// element 0: inline-type
+	t, err := d.decodeInlineType(s.types)
+	if err != nil {
+		return nil, err
+	}
+
+	// element 1: value
+
+	if s.streamArrays {
+		if arrayType, ok := t.(cadence.ArrayType); ok {
+			return s.startArray(arrayType)
+		}
+	}
+
+	value, err := s.decodeValue(t)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// decodeTypeDefs decodes the type definitions of the current message,
+// or reuses the type definitions of a previous message if they are identical.
+func (s *StreamDecoder) decodeTypeDefs() (*cadenceTypeByCCFTypeID, error) {
+	d := s.dec
+
+	encodedTypeDefs, err := d.dec.DecodeRawBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	cached, ok := s.typeDefs[string(encodedTypeDefs)]
+	if ok {
+		return cached.newTypes(), nil
+	}
+
+	typeDefDecoder := d.dm.NewDecoder(d.gauge, encodedTypeDefs)
+	types, err := typeDefDecoder.decodeTypeDefs()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.typeDefs) >= maxCachedTypeDefs {
+		clear(s.typeDefs)
+		clear(s.projectedTypes)
+	}
+
+	cached = cachedTypeDefs{
+		types:           types.types,
+		referencedTypes: types.referencedTypes,
+		nextCCFTypeID:   types.nextCCFTypeID,
+	}
+	s.typeDefs[string(encodedTypeDefs)] = cached
+
+	return cached.newTypes(), nil
+}
+
+// checkTypes checks that all type definitions of the current message were referenced.
+// The check is skipped if fields are projected, as skipped fields are not decoded.
+func (s *StreamDecoder) checkTypes() error {
+	if s.fields != nil {
+		return nil
+	}
+
+	if s.types.hasUnreferenced() {
+		return errors.New("found unreferenced type definition")
+	}
+
+	return nil
+}
+
+// startArray decodes the head of an array-value,
+// so its elements can be decoded one at a time
+func (s *StreamDecoder) startArray(arrayType cadence.ArrayType) (cadence.Value, error) {
+	d := s.dec
+
+	count, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if constantSizedArrayType, ok := arrayType.(*cadence.ConstantSizedArrayType); ok &&
+		uint64(constantSizedArrayType.Size) != count {
+
+		return nil, fmt.Errorf(
+			"encoded array-value has %d elements (expected %d elements)",
+			count,
+			constantSizedArrayType.Size,
+		)
+	}
+
+	if count == 0 {
+		err = s.checkTypes()
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip empty arrays
+		return s.nextMessage()
+	}
+
+	s.arrayElementType = arrayType.Element()
+	s.arrayRemaining = count
+
+	return s.nextArrayElement()
+}
+
+func (s *StreamDecoder) nextArrayElement() (cadence.Value, error) {
+	value, err := s.decodeValue(s.arrayElementType)
+	if err != nil {
+		return nil, err
+	}
+
+	s.arrayRemaining--
+
+	if s.arrayRemaining == 0 {
+		s.arrayElementType = nil
+
+		err = s.checkTypes()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// decodeValue decodes a top-level value (or array element) of the given type,
+// and projects composite values to the configured fields
+func (s *StreamDecoder) decodeValue(t cadence.Type) (cadence.Value, error) {
+	d := s.dec
+
+	if s.fields == nil {
+		return d.decodeValue(t, s.types)
+	}
+
+	switch t := t.(type) {
+	case cadence.CompositeType:
+		return s.decodeProjectedComposite(t)
+
+	case *cadence.ReferenceType:
+		// When static type is a reference type, encoded value is its deferenced type.
+		return s.decodeValue(t.Type)
+	}
+
+	if !isAbstractType(t) {
+		return d.decodeValue(t, s.types)
+	}
+
+	// Values of abstract types are encoded as ccf-type-and-value-message.
+
+	nt, err := d.dec.NextType()
+	if err != nil {
+		return nil, err
+	}
+
+	if nt == cbor.NilType {
+		return d.decodeValue(t, s.types)
+	}
+
+	err = decodeCBORTagWithKnownNumber(d.dec, CBORTagTypeAndValue)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected encoded value of Cadence type %s (%T): %s", t.ID(), t, err.Error())
+	}
+
+	// Decode array head of length 2.
+	err = decodeCBORArrayWithKnownSize(d.dec, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	// element 0: inline-type
+	runtimeType, err := d.decodeInlineType(s.types)
+	if err != nil {
+		return nil, err
+	}
+
+	// element 1: value
+	return s.decodeValue(runtimeType)
+}
+
+// decodeProjectedComposite decodes only the projected fields of an encoded composite-value.
+// All other fields and attachments are skipped.
+func (s *StreamDecoder) decodeProjectedComposite(typ cadence.CompositeType) (cadence.Value, error) {
+	d := s.dec
+
+	fieldTypes := getCompositeTypeFields(typ)
+	fieldCount := uint64(len(fieldTypes))
+
+	n, err := d.dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	var hasAttachments bool
+	switch typ.(type) {
+	case *cadence.StructType, *cadence.ResourceType:
+		hasAttachments = n == fieldCount+1
+	}
+
+	if n != fieldCount && !hasAttachments {
+		return nil, fmt.Errorf(
+			"CBOR array has %d elements (expected %d elements)",
+			n,
+			fieldCount,
+		)
+	}
+
+	var fieldValues []cadence.Value
+
+	for _, field := range fieldTypes {
+		if _, ok := s.fields[field.Identifier]; !ok {
+			err = d.dec.Skip()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		fieldValue, err := d.decodeValue(field.Type, s.types)
+		if err != nil {
+			return nil, err
+		}
+		fieldValues = append(fieldValues, fieldValue)
+	}
+
+	if hasAttachments {
+		err = d.dec.Skip()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	projectedType := s.projectedType(typ)
+
+	return newCompositeValue(d.gauge, projectedType, fieldValues)
+}
+
+// projectedType returns the given composite type with only the projected fields
+func (s *StreamDecoder) projectedType(typ cadence.CompositeType) cadence.CompositeType {
+	projectedType, ok := s.projectedTypes[typ]
+	if ok {
+		return projectedType
+	}
+
+	var fields []cadence.Field
+	for _, field := range getCompositeTypeFields(typ) {
+		if _, ok := s.fields[field.Identifier]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	gauge := s.dec.gauge
+
+	switch typ := typ.(type) {
+	case *cadence.StructType:
+		projectedType = cadence.NewMeteredStructType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			fields,
+			nil,
+		)
+
+	case *cadence.ResourceType:
+		projectedType = cadence.NewMeteredResourceType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			fields,
+			nil,
+		)
+
+	case *cadence.EventType:
+		projectedType = cadence.NewMeteredEventType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			fields,
+			nil,
+		)
+
+	case *cadence.ContractType:
+		projectedType = cadence.NewMeteredContractType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			fields,
+			nil,
+		)
+
+	case *cadence.EnumType:
+		projectedType = cadence.NewMeteredEnumType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			typ.RawType,
+			fields,
+			nil,
+		)
+
+	case *cadence.AttachmentType:
+		projectedType = cadence.NewMeteredAttachmentType(
+			gauge,
+			typ.Location,
+			typ.QualifiedIdentifier,
+			typ.BaseType,
+			fields,
+			nil,
+		)
+
+	default:
+		panic(cadenceErrors.NewUnexpectedError("unsupported composite type %T", typ))
+	}
+
+	s.projectedTypes[typ] = projectedType
+
+	return projectedType
+}
+
+// newCompositeValue returns a composite value of the given type with the given field values
+func newCompositeValue(
+	gauge common.MemoryGauge,
+	typ cadence.CompositeType,
+	fieldValues []cadence.Value,
+) (cadence.Value, error) {
+
+	constructor := func() ([]cadence.Value, error) {
+		return fieldValues, nil
+	}
+
+	switch typ := typ.(type) {
+	case *cadence.StructType:
+		v, err := cadence.NewMeteredStruct(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	case *cadence.ResourceType:
+		v, err := cadence.NewMeteredResource(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	case *cadence.EventType:
+		v, err := cadence.NewMeteredEvent(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	case *cadence.ContractType:
+		v, err := cadence.NewMeteredContract(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	case *cadence.EnumType:
+		v, err := cadence.NewMeteredEnum(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	case *cadence.AttachmentType:
+		v, err := cadence.NewMeteredAttachment(gauge, len(fieldValues), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return v.WithType(typ), nil
+
+	default:
+		panic(cadenceErrors.NewUnexpectedError("unsupported composite type %T", typ))
+	}
+}
+
+// isAbstractType returns true if values of the given static type
+// are encoded with their runtime type, as ccf-type-and-value-message
+func isAbstractType(t cadence.Type) bool {
+	switch t.(type) {
+	case cadence.InterfaceType, *cadence.IntersectionType:
+		return true
+	}
+
+	switch t {
+	case cadence.AnyType,
+		cadence.AnyStructType,
+		cadence.AnyResourceType,
+		cadence.HashableStructType,
+		cadence.AnyStructAttachmentType,
+		cadence.AnyResourceAttachmentType:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,310 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/errors"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+func encodeStream(t *testing.T, values ...cadence.Value) []byte {
+	var buffer bytes.Buffer
+	for _, value := range values {
+		encoded, err := ccf.Encode(value)
+		require.NoError(t, err)
+		buffer.Write(encoded)
+	}
+	return buffer.Bytes()
+}
+
+func decodeStream(t *testing.T, decoder *ccf.StreamDecoder) []cadence.Value {
+	var values []cadence.Value
+	for {
+		value, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+
+	// Further calls keep returning io.EOF
+	_, err := decoder.Next()
+	require.Equal(t, io.EOF, err)
+
+	return values
+}
+
+func TestStreamDecoder(t *testing.T) {
+
+	t.Parallel()
+
+	events := []cadence.Value{
+		createFlowTokenTokensDepositedEvent(),
+		createFlowTokenTokensWithdrawnEvent(),
+		createFlowTokenTokensDepositedEvent(),
+		cadence.NewInt(42),
+		createFlowFeesFeesDeductedEvent(),
+	}
+
+	t.Run("messages", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeStream(t, events...)
+
+		decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(data), ccf.StreamOptions{})
+
+		assert.Equal(t, events, decodeStream(t, decoder))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(nil), ccf.StreamOptions{})
+
+		assert.Empty(t, decodeStream(t, decoder))
+	})
+
+	t.Run("type definitions are reused", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeStream(
+			t,
+			createFlowTokenTokensDepositedEvent(),
+			createFlowTokenTokensDepositedEvent(),
+		)
+
+		decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(data), ccf.StreamOptions{})
+
+		values := decodeStream(t, decoder)
+		require.Len(t, values, 2)
+
+		assert.Same(t, values[0].Type(), values[1].Type())
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		t.Parallel()
+
+		array := cadence.NewArray(events).
+			WithType(cadence.NewVariableSizedArrayType(cadence.AnyStructType))
+
+		emptyArray := cadence.NewArray([]cadence.Value{}).
+			WithType(cadence.NewVariableSizedArrayType(cadence.AnyStructType))
+
+		data := encodeStream(
+			t,
+			array,
+			emptyArray,
+			cadence.String("end"),
+		)
+
+		t.Run("streamed", func(t *testing.T) {
+			t.Parallel()
+
+			decoder := ccf.NewStreamDecoder(
+				nil,
+				bytes.NewReader(data),
+				ccf.StreamOptions{
+					StreamArrays: true,
+				},
+			)
+
+			expected := append(events[:len(events):len(events)], cadence.String("end"))
+
+			assert.Equal(t, expected, decodeStream(t, decoder))
+		})
+
+		t.Run("not streamed", func(t *testing.T) {
+			t.Parallel()
+
+			decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(data), ccf.StreamOptions{})
+
+			values := decodeStream(t, decoder)
+			require.Len(t, values, 3)
+
+			assert.Equal(t, array, values[0])
+			assert.Equal(t, emptyArray, values[1])
+			assert.Equal(t, cadence.String("end"), values[2])
+		})
+	})
+
+	t.Run("projection", func(t *testing.T) {
+		t.Parallel()
+
+		array := cadence.NewArray(events).
+			WithType(cadence.NewVariableSizedArrayType(cadence.AnyStructType))
+
+		data := encodeStream(t, array, createFlowTokenTokensDepositedEvent())
+
+		decoder := ccf.NewStreamDecoder(
+			nil,
+			bytes.NewReader(data),
+			ccf.StreamOptions{
+				StreamArrays: true,
+				Fields:       []string{"amount"},
+			},
+		)
+
+		values := decodeStream(t, decoder)
+		require.Len(t, values, 6)
+
+		for i, value := range values {
+			var original cadence.Value
+			if i < len(events) {
+				original = events[i]
+			} else {
+				original = createFlowTokenTokensDepositedEvent()
+			}
+
+			event, ok := value.(cadence.Event)
+			if !ok {
+				// Non-composite values are not projected
+				assert.Equal(t, original, value)
+				continue
+			}
+
+			originalEvent := original.(cadence.Event)
+
+			assert.Equal(t, originalEvent.Type().ID(), event.Type().ID())
+
+			fields := cadence.FieldsMappedByName(event)
+			originalFields := cadence.FieldsMappedByName(originalEvent)
+
+			if originalAmount, ok := originalFields["amount"]; ok {
+				assert.Equal(t,
+					map[string]cadence.Value{
+						"amount": originalAmount,
+					},
+					fields,
+				)
+			} else {
+				assert.Empty(t, fields)
+			}
+		}
+	})
+
+	t.Run("projection with attachments", func(t *testing.T) {
+		t.Parallel()
+
+		structType := cadence.NewStructType(
+			TestLocation,
+			"S",
+			[]cadence.Field{
+				{
+					Identifier: "a",
+					Type:       cadence.IntType,
+				},
+				{
+					Identifier: "b",
+					Type:       cadence.AnyStructType,
+				},
+			},
+			nil,
+		)
+
+		attachmentType := cadence.NewAttachmentType(
+			TestLocation,
+			"A",
+			nil,
+			[]cadence.Field{},
+			nil,
+		)
+
+		value := cadence.NewStruct(
+			[]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("b"),
+			},
+		).
+			WithType(structType).
+			WithAttachments([]cadence.Attachment{
+				cadence.NewAttachment(nil).WithType(attachmentType),
+			})
+
+		data := encodeStream(t, value)
+
+		decoder := ccf.NewStreamDecoder(
+			nil,
+			bytes.NewReader(data),
+			ccf.StreamOptions{
+				Fields: []string{"a"},
+			},
+		)
+
+		values := decodeStream(t, decoder)
+		require.Len(t, values, 1)
+
+		decoded := values[0].(cadence.Struct)
+		assert.Equal(t,
+			map[string]cadence.Value{
+				"a": cadence.NewInt(1),
+			},
+			cadence.FieldsMappedByName(decoded),
+		)
+		assert.Empty(t, decoded.Attachments())
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeStream(t, events...)
+
+		decoder := ccf.NewStreamDecoder(nil, bytes.NewReader(data[:len(data)-1]), ccf.StreamOptions{})
+
+		for range events[:len(events)-1] {
+			_, err := decoder.Next()
+			require.NoError(t, err)
+		}
+
+		_, err := decoder.Next()
+		require.ErrorContains(t, err, "unexpected EOF")
+		require.True(t, errors.IsUserError(err))
+
+		// Errors are sticky
+		_, err2 := decoder.Next()
+		require.Equal(t, err, err2)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		t.Parallel()
+
+		decoder := ccf.NewStreamDecoder(
+			nil,
+			bytes.NewReader([]byte{
+				// tag
+				0xd8, 0x42,
+				// nil
+				0xf6,
+			}),
+			ccf.StreamOptions{},
+		)
+
+		_, err := decoder.Next()
+		require.ErrorContains(t, err, "unsupported top level CCF message with CBOR tag number 66")
+	})
+}
@@ -100,11 +100,17 @@ func (ct *compositeTypes) traverseValue(v cadence.Value) {
 		for _, field := range getCompositeFieldValues(v) {
 			ct.traverseValue(field)
 		}
+		for _, attachment := range v.Attachments() {
+			ct.traverseValue(attachment)
+		}
 
 	case cadence.Resource:
 		for _, field := range getCompositeFieldValues(v) {
 			ct.traverseValue(field)
 		}
+		for _, attachment := range v.Attachments() {
+			ct.traverseValue(attachment)
+		}
 
 	case cadence.Event:
 		for _, field := range getCompositeFieldValues(v) {
@@ -162,7 +168,14 @@ func (ct *compositeTypes) traverseType(typ cadence.Type) (checkRuntimeType bool)
 			return ct.abstractTypes[typ.ID()]
 		}
 
-		check := false
+		// Struct and resource values may have attachments,
+		// which are not part of the static type.
+		var check bool
+		switch typ.(type) {
+		case *cadence.StructType, *cadence.ResourceType:
+			check = true
+		}
+
 		fields := getCompositeTypeFields(typ)
 		for _, field := range fields {
 			checkField := ct.traverseType(field.Type)
@@ -0,0 +1,199 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/onflow/cadence"
+)
+
+// EncodeCanonical returns the canonical JSON-encoded representation of the given value.
+//
+// The canonical encoding is deterministic, so it can be hashed and signed:
+//   - Object keys are sorted
+//   - Dictionary entries are sorted by the canonical encoding of their keys
+//   - The types of intersection types and the entitlements of entitlement sets are sorted by type ID.
+//     The types of intersection types are sorted before they are encoded,
+//     so the definition of a nominal type always precedes the references to it
+//   - There is no insignificant whitespace, including no trailing newline
+//   - HTML characters are not escaped
+//
+// Numbers are already encoded as decimal strings without leading zeros, and fixed-point numbers
+// always have all fractional digits, and types are identified by their canonical type IDs.
+//
+// This function returns an error if the Cadence value cannot be represented as JSON.
+func EncodeCanonical(value cadence.Value) ([]byte, error) {
+	encoded, err := encode(value, preparer{canonical: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalize(encoded)
+}
+
+// MustEncodeCanonical returns the canonical JSON-encoded representation of the given value,
+// or panics if the value cannot be represented as JSON.
+func MustEncodeCanonical(value cadence.Value) []byte {
+	b, err := EncodeCanonical(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// canonicalize returns the canonical form of the given JSON-Cadence encoding
+func canonicalize(encoded []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber()
+
+	var object any
+	err := dec.Decode(&object)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err = canonicalizeJSON(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalCanonical(object)
+}
+
+// marshalCanonical returns the compact JSON encoding of the given object.
+// Maps are encoded with sorted keys.
+func marshalCanonical(object any) ([]byte, error) {
+	var w bytes.Buffer
+	enc := json.NewEncoder(&w)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(object)
+	if err != nil {
+		return nil, err
+	}
+
+	// json.Encoder always appends a newline
+	return bytes.TrimSuffix(w.Bytes(), []byte{'\n'}), nil
+}
+
+// canonicalizeJSON sorts the elements of JSON arrays which represent sets or maps.
+// The types of intersection types are already sorted by the encoder.
+func canonicalizeJSON(object any) (any, error) {
+	switch object := object.(type) {
+	case []any:
+		for i, element := range object {
+			canonicalElement, err := canonicalizeJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			object[i] = canonicalElement
+		}
+		return object, nil
+
+	case map[string]any:
+		for key, value := range object { //nolint:maprange
+			canonicalValue, err := canonicalizeJSON(value)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = canonicalValue
+		}
+
+		switch {
+		case object[typeKey] == dictionaryTypeStr:
+			return object, sortDictionaryEntries(object)
+
+		case object[kindKey] == "EntitlementConjunctionSet",
+			object[kindKey] == "EntitlementDisjunctionSet":
+
+			sortByTypeID(object, entitlementsKey)
+		}
+
+		return object, nil
+
+	default:
+		return object, nil
+	}
+}
+
+// sortDictionaryEntries sorts the entries of a dictionary value by the canonical encoding of their keys
+func sortDictionaryEntries(object map[string]any) error {
+	entries, ok := object[valueKey].([]any)
+	if !ok {
+		return nil
+	}
+
+	type sortableEntry struct {
+		key   []byte
+		entry any
+	}
+
+	sortableEntries := make([]sortableEntry, len(entries))
+	for i, entry := range entries {
+		var key any
+		if entryObject, ok := entry.(map[string]any); ok {
+			key = entryObject[keyKey]
+		}
+
+		encodedKey, err := marshalCanonical(key)
+		if err != nil {
+			return err
+		}
+
+		sortableEntries[i] = sortableEntry{
+			key:   encodedKey,
+			entry: entry,
+		}
+	}
+
+	slices.SortStableFunc(sortableEntries, func(a, b sortableEntry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	for i, sortableEntry := range sortableEntries {
+		entries[i] = sortableEntry.entry
+	}
+
+	return nil
+}
+
+// sortByTypeID sorts the nominal type objects in the given array field by their type IDs
+func sortByTypeID(object map[string]any, key string) {
+	types, ok := object[key].([]any)
+	if !ok {
+		return
+	}
+
+	typeID := func(typ any) string {
+		typeObject, ok := typ.(map[string]any)
+		if !ok {
+			return ""
+		}
+		id, _ := typeObject[typeIDKey].(string)
+		return id
+	}
+
+	slices.SortStableFunc(types, func(a, b any) int {
+		return strings.Compare(typeID(a), typeID(b))
+	})
+}
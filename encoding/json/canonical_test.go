@@ -0,0 +1,305 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+func TestEncodeCanonical(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, value cadence.Value, expected string) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := EncodeCanonical(value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, string(actual))
+
+			decoded, err := Decode(nil, actual, WithRequireCanonical())
+			require.NoError(t, err)
+
+			reencoded, err := EncodeCanonical(decoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, string(reencoded))
+		})
+	}
+
+	test(
+		"Int",
+		cadence.NewInt(-42),
+		`{"type":"Int","value":"-42"}`,
+	)
+
+	test(
+		"UFix64",
+		cadence.UFix64(150000000),
+		`{"type":"UFix64","value":"1.50000000"}`,
+	)
+
+	test(
+		"String, HTML is not escaped",
+		cadence.String("<a href=\"x\">&</a>"),
+		`{"type":"String","value":"<a href=\"x\">&</a>"}`,
+	)
+
+	test(
+		"Dictionary",
+		cadence.NewDictionary([]cadence.KeyValuePair{
+			{
+				Key:   cadence.String("b"),
+				Value: cadence.NewInt(2),
+			},
+			{
+				Key:   cadence.String("a"),
+				Value: cadence.NewInt(1),
+			},
+		}),
+		`{"type":"Dictionary","value":[`+
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Int","value":"1"}},`+
+			`{"key":{"type":"String","value":"b"},"value":{"type":"Int","value":"2"}}`+
+			`]}`,
+	)
+
+	test(
+		"Struct",
+		cadence.NewStruct([]cadence.Value{
+			cadence.NewBool(true),
+			cadence.NewOptional(nil),
+		}).WithType(cadence.NewStructType(
+			TestLocation,
+			"Foo",
+			[]cadence.Field{
+				{
+					Identifier: "b",
+					Type:       cadence.BoolType,
+				},
+				{
+					Identifier: "a",
+					Type:       cadence.NewOptionalType(cadence.IntType),
+				},
+			},
+			nil,
+		)),
+		// Fields keep their declaration order
+		`{"type":"Struct","value":{"fields":[`+
+			`{"name":"b","value":{"type":"Bool","value":true}},`+
+			`{"name":"a","value":{"type":"Optional","value":null}}`+
+			`],"id":"S.test.Foo"}}`,
+	)
+
+	t.Run("Dictionary, independent of order", func(t *testing.T) {
+		t.Parallel()
+
+		pairs := []cadence.KeyValuePair{
+			{
+				Key:   cadence.NewInt(3),
+				Value: cadence.String("c"),
+			},
+			{
+				Key:   cadence.NewInt(1),
+				Value: cadence.String("a"),
+			},
+			{
+				Key:   cadence.NewInt(2),
+				Value: cadence.String("b"),
+			},
+		}
+
+		reversedPairs := []cadence.KeyValuePair{pairs[2], pairs[1], pairs[0]}
+
+		actual := MustEncodeCanonical(cadence.NewDictionary(pairs))
+		expected := MustEncodeCanonical(cadence.NewDictionary(reversedPairs))
+
+		assert.Equal(t, string(expected), string(actual))
+	})
+
+	t.Run("intersection and entitlement set, independent of order", func(t *testing.T) {
+		t.Parallel()
+
+		newTypeValue := func(interfaceIDs []string, entitlements []common.TypeID) cadence.TypeValue {
+			interfaceTypes := make([]cadence.Type, len(interfaceIDs))
+			for i, id := range interfaceIDs {
+				interfaceTypes[i] = cadence.NewStructInterfaceType(TestLocation, id, nil, nil)
+			}
+
+			return cadence.NewTypeValue(
+				cadence.NewReferenceType(
+					cadence.NewEntitlementSetAuthorization(nil, entitlements, cadence.Conjunction),
+					cadence.NewIntersectionType(interfaceTypes),
+				),
+			)
+		}
+
+		actual := MustEncodeCanonical(
+			newTypeValue(
+				[]string{"I", "J"},
+				[]common.TypeID{"S.test.E", "S.test.F"},
+			),
+		)
+		expected := MustEncodeCanonical(
+			newTypeValue(
+				[]string{"J", "I"},
+				[]common.TypeID{"S.test.F", "S.test.E"},
+			),
+		)
+
+		assert.Equal(t, string(expected), string(actual))
+	})
+
+	t.Run("intersection of interrelated interfaces", func(t *testing.T) {
+		t.Parallel()
+
+		interfaceA := cadence.NewStructInterfaceType(TestLocation, "A", nil, nil)
+
+		interfaceB := cadence.NewStructInterfaceType(
+			TestLocation,
+			"B",
+			[]cadence.Field{
+				{
+					Identifier: "a",
+					Type:       interfaceA,
+				},
+			},
+			nil,
+		)
+
+		// B is first, but its field refers to A,
+		// so A must be defined before it is referred to by type ID
+
+		value := cadence.NewTypeValue(
+			cadence.NewIntersectionType([]cadence.Type{interfaceB, interfaceA}),
+		)
+
+		encoded, err := EncodeCanonical(value)
+		require.NoError(t, err)
+
+		decoded, err := Decode(nil, encoded, WithRequireCanonical())
+		require.NoError(t, err)
+
+		decodedType := decoded.(cadence.TypeValue).StaticType
+		require.IsType(t, &cadence.IntersectionType{}, decodedType)
+
+		decodedTypes := decodedType.(*cadence.IntersectionType).Types
+		require.Len(t, decodedTypes, 2)
+		assert.Equal(t, interfaceA.ID(), decodedTypes[0].ID())
+		assert.Equal(t, interfaceB.ID(), decodedTypes[1].ID())
+
+		reencoded, err := EncodeCanonical(decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, string(encoded), string(reencoded))
+
+		sorted := MustEncodeCanonical(
+			cadence.NewTypeValue(
+				cadence.NewIntersectionType([]cadence.Type{interfaceA, interfaceB}),
+			),
+		)
+
+		assert.Equal(t, string(sorted), string(encoded))
+	})
+}
+
+func TestDecodeRequireCanonical(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, encoded string) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Non-canonical input is accepted by default
+			_, err := Decode(nil, []byte(encoded))
+			require.NoError(t, err)
+
+			_, err = Decode(nil, []byte(encoded), WithRequireCanonical())
+			require.Error(t, err)
+			assert.ErrorAs(t, err, &NonCanonicalEncodingError{})
+		})
+	}
+
+	test(
+		"unsorted object keys",
+		`{"value":"42","type":"Int"}`,
+	)
+
+	test(
+		"whitespace",
+		`{"type": "Int", "value": "42"}`,
+	)
+
+	test(
+		"trailing newline",
+		"{\"type\":\"Int\",\"value\":\"42\"}\n",
+	)
+
+	test(
+		"unsorted dictionary entries",
+		`{"type":"Dictionary","value":[`+
+			`{"key":{"type":"String","value":"b"},"value":{"type":"Int","value":"2"}},`+
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Int","value":"1"}}`+
+			`]}`,
+	)
+
+	test(
+		"non-normalized fixed-point number",
+		`{"type":"UFix64","value":"1.5"}`,
+	)
+
+	test(
+		"escaped HTML",
+		`{"type":"String","value":"\u003c"}`,
+	)
+
+	t.Run("duplicate dictionary keys", func(t *testing.T) {
+		t.Parallel()
+
+		encoded := `{"type":"Dictionary","value":[` +
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Int","value":"1"}},` +
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Int","value":"2"}}` +
+			`]}`
+
+		// Duplicate keys are accepted by default
+		_, err := Decode(nil, []byte(encoded))
+		require.NoError(t, err)
+
+		_, err = Decode(nil, []byte(encoded), WithRequireCanonical())
+		require.ErrorContains(t, err, `invalid dictionary: duplicate key "a"`)
+	})
+
+	t.Run("Encode output", func(t *testing.T) {
+		t.Parallel()
+
+		encoded := MustEncode(cadence.NewInt(42))
+
+		_, err := Decode(nil, encoded, WithRequireCanonical())
+		require.Error(t, err)
+	})
+}
@@ -44,6 +44,8 @@ type Decoder struct {
 	allowUnstructuredStaticTypes bool
 	// backwardsCompatible controls if the decoder can decode old versions of the JSON encoding
 	backwardsCompatible bool
+	// requireCanonical controls if the decoder rejects input which is not canonically encoded
+	requireCanonical bool
 }
 
 type Option func(*Decoder)
@@ -66,6 +68,16 @@ func WithBackwardsCompatibility() Option {
 	}
 }
 
+// WithRequireCanonical returns a new Decoder Option
+// which enables strict mode, where input that is not
+// in the canonical encoding produced by EncodeCanonical is rejected.
+// Dictionaries with duplicate keys are rejected as well
+func WithRequireCanonical() Option {
+	return func(decoder *Decoder) {
+		decoder.requireCanonical = true
+	}
+}
+
 // Decode returns a Cadence value decoded from its JSON-encoded representation.
 //
 // This function returns an error if the bytes represent JSON that is malformed
@@ -83,6 +95,12 @@ func Decode(gauge common.MemoryGauge, b []byte, options ...Option) (cadence.Valu
 		return nil, err
 	}
 
+	// The decoder ignores whitespace around the value,
+	// but the canonical encoding has no surrounding whitespace
+	if dec.requireCanonical && len(bytes.TrimSpace(b)) != len(b) {
+		return nil, errors.NewDefaultUserError("failed to decode JSON-Cadence value: %w", NonCanonicalEncodingError{})
+	}
+
 	return v, nil
 }
 
@@ -103,7 +121,15 @@ func NewDecoder(gauge common.MemoryGauge, r io.Reader) *Decoder {
 func (d *Decoder) Decode() (value cadence.Value, err error) {
 	jsonMap := make(map[string]any)
 
-	err = d.dec.Decode(&jsonMap)
+	var raw json.RawMessage
+	if d.requireCanonical {
+		err = d.dec.Decode(&raw)
+		if err == nil {
+			err = json.Unmarshal(raw, &jsonMap)
+		}
+	} else {
+		err = d.dec.Decode(&jsonMap)
+	}
 	if err != nil {
 		return nil, errors.NewDefaultUserError("failed to decode JSON: %w", err)
 	}
@@ -121,9 +147,30 @@ func (d *Decoder) Decode() (value cadence.Value, err error) {
 	}()
 
 	value = d.DecodeJSON(jsonMap)
+
+	if d.requireCanonical {
+		canonical, err := EncodeCanonical(value)
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(raw, canonical) {
+			panic(NonCanonicalEncodingError{})
+		}
+	}
+
 	return value, nil
 }
 
+// NonCanonicalEncodingError is reported when decoding in strict mode,
+// and the input is not canonically encoded
+type NonCanonicalEncodingError struct{}
+
+var _ error = NonCanonicalEncodingError{}
+
+func (NonCanonicalEncodingError) Error() string {
+	return "non-canonical encoding"
+}
+
 const (
 	typeKey              = "type"
 	kindKey              = "kind"
@@ -131,6 +178,7 @@ const (
 	keyKey               = "key"
 	nameKey              = "name"
 	fieldsKey            = "fields"
+	attachmentsKey       = "attachments"
 	initializersKey      = "initializers"
 	idKey                = "id"
 	targetPathKey        = "targetPath"
@@ -254,6 +302,8 @@ func (d *Decoder) DecodeJSON(v any) cadence.Value {
 		return d.decodeCapability(valueJSON)
 	case enumTypeStr:
 		return d.decodeEnum(valueJSON)
+	case attachmentTypeStr:
+		return d.decodeAttachment(valueJSON)
 	case functionTypeStr:
 		return d.decodeFunction(valueJSON)
 	}
@@ -690,8 +740,28 @@ func (d *Decoder) decodeDictionary(valueJSON any) cadence.Dictionary {
 		func() ([]cadence.KeyValuePair, error) {
 			pairs := make([]cadence.KeyValuePair, len(v))
 
+			// Duplicate keys are encoded identically,
+			// so they must be rejected explicitly in strict mode
+			var encodedKeys map[string]struct{}
+			if d.requireCanonical {
+				encodedKeys = make(map[string]struct{}, len(v))
+			}
+
 			for i, val := range v {
-				pairs[i] = d.decodeKeyValuePair(val)
+				pair := d.decodeKeyValuePair(val)
+
+				if encodedKeys != nil {
+					encodedKey, err := EncodeCanonical(pair.Key)
+					if err != nil {
+						return nil, err
+					}
+					if _, ok := encodedKeys[string(encodedKey)]; ok {
+						return nil, errors.NewDefaultUserError("duplicate key %s", pair.Key)
+					}
+					encodedKeys[string(encodedKey)] = struct{}{}
+				}
+
+				pairs[i] = pair
 			}
 
 			return pairs, nil
@@ -793,13 +863,15 @@ func (d *Decoder) decodeStruct(valueJSON any) cadence.Struct {
 		panic(errors.NewDefaultUserError("invalid struct: %w", err))
 	}
 
-	return structure.WithType(cadence.NewMeteredStructType(
-		d.gauge,
-		comp.location,
-		comp.qualifiedIdentifier,
-		comp.fieldTypes,
-		nil,
-	))
+	return structure.
+		WithType(cadence.NewMeteredStructType(
+			d.gauge,
+			comp.location,
+			comp.qualifiedIdentifier,
+			comp.fieldTypes,
+			nil,
+		)).
+		WithAttachments(d.decodeAttachments(valueJSON))
 }
 
 func (d *Decoder) decodeResource(valueJSON any) cadence.Resource {
@@ -816,15 +888,71 @@ func (d *Decoder) decodeResource(valueJSON any) cadence.Resource {
 	if err != nil {
 		panic(errors.NewDefaultUserError("invalid resource: %w", err))
 	}
-	return resource.WithType(cadence.NewMeteredResourceType(
+	return resource.
+		WithType(cadence.NewMeteredResourceType(
+			d.gauge,
+			comp.location,
+			comp.qualifiedIdentifier,
+			comp.fieldTypes,
+			nil,
+		)).
+		WithAttachments(d.decodeAttachments(valueJSON))
+}
+
+func (d *Decoder) decodeAttachment(valueJSON any) cadence.Attachment {
+	comp := d.decodeComposite(valueJSON)
+
+	attachment, err := cadence.NewMeteredAttachment(
+		d.gauge,
+		len(comp.fieldValues),
+		func() ([]cadence.Value, error) {
+			return comp.fieldValues, nil
+		},
+	)
+
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid attachment: %w", err))
+	}
+
+	// NOTE: the base type of the attachment is not encoded,
+	// so it cannot be inferred from the value
+	return attachment.WithType(cadence.NewMeteredAttachmentType(
 		d.gauge,
 		comp.location,
 		comp.qualifiedIdentifier,
+		nil,
 		comp.fieldTypes,
 		nil,
 	))
 }
 
+// decodeAttachments decodes the optional attachments of a composite value
+func (d *Decoder) decodeAttachments(valueJSON any) []cadence.Attachment {
+	obj := toObject(valueJSON)
+
+	attachmentsJSON, ok := obj[attachmentsKey]
+	if !ok {
+		return nil
+	}
+
+	attachmentValues := toSlice(attachmentsJSON)
+	if len(attachmentValues) == 0 {
+		return nil
+	}
+
+	attachments := make([]cadence.Attachment, len(attachmentValues))
+
+	for i, attachmentJSON := range attachmentValues {
+		attachment, ok := d.DecodeJSON(attachmentJSON).(cadence.Attachment)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid attachment: expected attachment value"))
+		}
+		attachments[i] = attachment
+	}
+
+	return attachments
+}
+
 func (d *Decoder) decodeEvent(valueJSON any) cadence.Event {
 	comp := d.decodeComposite(valueJSON)
 
@@ -25,6 +25,7 @@ import (
 	"io"
 	"math/big"
 	goRuntime "runtime"
+	"slices"
 	"strconv"
 	"strings"
 	_ "unsafe"
@@ -43,10 +44,14 @@ type Encoder struct {
 //
 // This function returns an error if the Cadence value cannot be represented as JSON.
 func Encode(value cadence.Value) ([]byte, error) {
+	return encode(value, preparer{})
+}
+
+func encode(value cadence.Value, p preparer) ([]byte, error) {
 	var w bytes.Buffer
 	enc := NewEncoder(&w)
 
-	err := enc.Encode(value)
+	err := enc.encode(value, p)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +81,10 @@ func NewEncoder(w io.Writer) *Encoder {
 // This function returns an error if the given value's type is not supported
 // by this encoder.
 func (e *Encoder) Encode(value cadence.Value) (err error) {
+	return e.encode(value, preparer{})
+}
+
+func (e *Encoder) encode(value cadence.Value, p preparer) (err error) {
 	// capture panics that occur during struct preparation
 	defer func() {
 		if r := recover(); r != nil {
@@ -94,7 +103,7 @@ func (e *Encoder) Encode(value cadence.Value) (err error) {
 		}
 	}()
 
-	preparedValue := Prepare(value)
+	preparedValue := p.prepare(value)
 
 	return e.enc.Encode(&preparedValue)
 }
@@ -124,8 +133,9 @@ type jsonInclusiveRangeValue struct {
 }
 
 type jsonCompositeValue struct {
-	ID     string               `json:"id"`
-	Fields []jsonCompositeField `json:"fields"`
+	ID          string               `json:"id"`
+	Fields      []jsonCompositeField `json:"fields"`
+	Attachments []jsonValue          `json:"attachments,omitempty"`
 }
 
 type jsonCompositeField struct {
@@ -276,11 +286,23 @@ const (
 // Prepare traverses the object graph of the provided value and constructs
 // a struct representation that can be marshalled to JSON.
 func Prepare(v cadence.Value) jsonValue {
+	return preparer{}.prepare(v)
+}
+
+// preparer constructs the JSON representations of values and types
+type preparer struct {
+	// canonical determines if the types of intersection types are sorted by their type IDs.
+	// The types are sorted before they are prepared, so the definition of a nominal type
+	// always precedes the references to it by type ID
+	canonical bool
+}
+
+func (p preparer) prepare(v cadence.Value) jsonValue {
 	switch v := v.(type) {
 	case cadence.Void:
 		return prepareVoid()
 	case cadence.Optional:
-		return prepareOptional(v)
+		return p.prepareOptional(v)
 	case cadence.Bool:
 		return prepareBool(v)
 	case cadence.Character:
@@ -334,31 +356,31 @@ func Prepare(v cadence.Value) jsonValue {
 	case cadence.UFix64:
 		return prepareUFix64(v)
 	case cadence.Array:
-		return prepareArray(v)
+		return p.prepareArray(v)
 	case cadence.Dictionary:
-		return prepareDictionary(v)
+		return p.prepareDictionary(v)
 	case *cadence.InclusiveRange:
-		return prepareInclusiveRange(v)
+		return p.prepareInclusiveRange(v)
 	case cadence.Struct:
-		return prepareStruct(v)
+		return p.prepareStruct(v)
 	case cadence.Resource:
-		return prepareResource(v)
+		return p.prepareResource(v)
 	case cadence.Event:
-		return prepareEvent(v)
+		return p.prepareEvent(v)
 	case cadence.Contract:
-		return prepareContract(v)
+		return p.prepareContract(v)
 	case cadence.Path:
 		return preparePath(v)
 	case cadence.TypeValue:
-		return prepareTypeValue(v)
+		return p.prepareTypeValue(v)
 	case cadence.Capability:
-		return prepareCapability(v)
+		return p.prepareCapability(v)
 	case cadence.Enum:
-		return prepareEnum(v)
+		return p.prepareEnum(v)
 	case cadence.Attachment:
-		return prepareAttachment(v)
+		return p.prepareAttachment(v)
 	case cadence.Function:
-		return prepareFunction(v)
+		return p.prepareFunction(v)
 	case nil:
 		return nil
 	default:
@@ -370,11 +392,11 @@ func prepareVoid() jsonValue {
 	return jsonEmptyValueObject{Type: voidTypeStr}
 }
 
-func prepareOptional(v cadence.Optional) jsonValue {
+func (p preparer) prepareOptional(v cadence.Optional) jsonValue {
 	var value any
 
 	if v.Value != nil {
-		value = Prepare(v.Value)
+		value = p.prepare(v.Value)
 	}
 
 	return jsonValueObject{
@@ -565,11 +587,11 @@ func prepareUFix64(v cadence.UFix64) jsonValue {
 	}
 }
 
-func prepareArray(v cadence.Array) jsonValue {
+func (p preparer) prepareArray(v cadence.Array) jsonValue {
 	values := make([]jsonValue, len(v.Values))
 
 	for i, value := range v.Values {
-		values[i] = Prepare(value)
+		values[i] = p.prepare(value)
 	}
 
 	return jsonValueObject{
@@ -578,13 +600,13 @@ func prepareArray(v cadence.Array) jsonValue {
 	}
 }
 
-func prepareDictionary(v cadence.Dictionary) jsonValue {
+func (p preparer) prepareDictionary(v cadence.Dictionary) jsonValue {
 	items := make([]jsonDictionaryItem, len(v.Pairs))
 
 	for i, pair := range v.Pairs {
 		items[i] = jsonDictionaryItem{
-			Key:   Prepare(pair.Key),
-			Value: Prepare(pair.Value),
+			Key:   p.prepare(pair.Key),
+			Value: p.prepare(pair.Value),
 		}
 	}
 
@@ -594,13 +616,13 @@ func prepareDictionary(v cadence.Dictionary) jsonValue {
 	}
 }
 
-func prepareInclusiveRange(v *cadence.InclusiveRange) jsonValue {
+func (p preparer) prepareInclusiveRange(v *cadence.InclusiveRange) jsonValue {
 	return jsonValueObject{
 		Type: inclusiveRangeTypeStr,
 		Value: jsonInclusiveRangeValue{
-			Start: Prepare(v.Start),
-			End:   Prepare(v.End),
-			Step:  Prepare(v.Step),
+			Start: p.prepare(v.Start),
+			End:   p.prepare(v.End),
+			Step:  p.prepare(v.Step),
 		},
 	}
 }
@@ -614,64 +636,73 @@ func getCompositeTypeFields(cadence.CompositeType) []cadence.Field
 //go:linkname getInterfaceTypeFields github.com/onflow/cadence.getInterfaceTypeFields
 func getInterfaceTypeFields(cadence.InterfaceType) []cadence.Field
 
-func prepareStruct(v cadence.Struct) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareStruct(v cadence.Struct) jsonValue {
+	return p.prepareComposite(
 		structTypeStr,
 		v.StructType.ID(),
 		getCompositeTypeFields(v.StructType),
 		getCompositeFieldValues(v),
+		v.Attachments(),
 	)
 }
 
-func prepareResource(v cadence.Resource) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareResource(v cadence.Resource) jsonValue {
+	return p.prepareComposite(
 		resourceTypeStr,
 		v.ResourceType.ID(),
 		getCompositeTypeFields(v.ResourceType),
 		getCompositeFieldValues(v),
+		v.Attachments(),
 	)
 }
 
-func prepareEvent(v cadence.Event) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareEvent(v cadence.Event) jsonValue {
+	return p.prepareComposite(
 		eventTypeStr,
 		v.EventType.ID(),
 		getCompositeTypeFields(v.EventType),
 		getCompositeFieldValues(v),
+		nil,
 	)
 }
 
-func prepareContract(v cadence.Contract) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareContract(v cadence.Contract) jsonValue {
+	return p.prepareComposite(
 		contractTypeStr,
 		v.ContractType.ID(),
 		getCompositeTypeFields(v.ContractType),
 		getCompositeFieldValues(v),
+		nil,
 	)
 }
 
-func prepareEnum(v cadence.Enum) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareEnum(v cadence.Enum) jsonValue {
+	return p.prepareComposite(
 		enumTypeStr,
 		v.EnumType.ID(),
 		getCompositeTypeFields(v.EnumType),
 		getCompositeFieldValues(v),
+		nil,
 	)
 }
 
-func prepareAttachment(v cadence.Attachment) jsonValue {
-	return prepareComposite(
+func (p preparer) prepareAttachment(v cadence.Attachment) jsonValue {
+	return p.prepareComposite(
 		attachmentTypeStr,
 		v.AttachmentType.ID(),
 		getCompositeTypeFields(v.AttachmentType),
 		getCompositeFieldValues(v),
+		nil,
 	)
 }
 
-func prepareComposite(kind, id string, fieldTypes []cadence.Field, fields []cadence.Value) jsonValue {
-	// Ensure there are _at least_ as many field values as field types.
-	// There might be more field values in the case of attachments.
-	if len(fields) < len(fieldTypes) {
+func (p preparer) prepareComposite(
+	kind, id string,
+	fieldTypes []cadence.Field,
+	fields []cadence.Value,
+	attachments []cadence.Attachment,
+) jsonValue {
+	if len(fields) != len(fieldTypes) {
 		panic(fmt.Errorf(
 			"%s field count (%d) does not match declared type (%d)",
 			kind,
@@ -683,26 +714,29 @@ func prepareComposite(kind, id string, fieldTypes []cadence.Field, fields []cade
 	compositeFields := make([]jsonCompositeField, len(fields))
 
 	for i, value := range fields {
-		var name string
-		// Provide the field name, if the field type is available.
-		// In the case of attachments, they are provided as field values,
-		// but there is no corresponding field type.
-		if i < len(fieldTypes) {
-			fieldType := fieldTypes[i]
-			name = fieldType.Identifier
-		}
+		fieldType := fieldTypes[i]
 
 		compositeFields[i] = jsonCompositeField{
-			Name:  name,
-			Value: Prepare(value),
+			Name:  fieldType.Identifier,
+			Value: p.prepare(value),
+		}
+	}
+
+	var compositeAttachments []jsonValue
+	if len(attachments) > 0 {
+		compositeAttachments = make([]jsonValue, len(attachments))
+
+		for i, attachment := range attachments {
+			compositeAttachments[i] = p.prepareAttachment(attachment)
 		}
 	}
 
 	return jsonValueObject{
 		Type: kind,
 		Value: jsonCompositeValue{
-			ID:     id,
-			Fields: compositeFields,
+			ID:          id,
+			Fields:      compositeFields,
+			Attachments: compositeAttachments,
 		},
 	}
 }
@@ -751,11 +785,11 @@ func preparePath(x cadence.Path) jsonValue {
 	}
 }
 
-func prepareTypeParameter(typeParameter cadence.TypeParameter, results TypePreparationResults) jsonTypeParameter {
+func (p preparer) prepareTypeParameter(typeParameter cadence.TypeParameter, results TypePreparationResults) jsonTypeParameter {
 	typeBound := typeParameter.TypeBound
 	var preparedTypeBound jsonValue
 	if typeBound != nil {
-		preparedTypeBound = PrepareType(typeBound, results)
+		preparedTypeBound = p.prepareType(typeBound, results)
 	}
 	return jsonTypeParameter{
 		Name:      typeParameter.Name,
@@ -763,54 +797,60 @@ func prepareTypeParameter(typeParameter cadence.TypeParameter, results TypePrepa
 	}
 }
 
-func prepareParameter(parameterType cadence.Parameter, results TypePreparationResults) jsonParameterType {
+func (p preparer) prepareParameter(parameterType cadence.Parameter, results TypePreparationResults) jsonParameterType {
 	return jsonParameterType{
 		Label: parameterType.Label,
 		Id:    parameterType.Identifier,
-		Type:  PrepareType(parameterType.Type, results),
+		Type:  p.prepareType(parameterType.Type, results),
 	}
 }
 
-func prepareFieldType(fieldType cadence.Field, results TypePreparationResults) jsonFieldType {
+func (p preparer) prepareFieldType(fieldType cadence.Field, results TypePreparationResults) jsonFieldType {
 	return jsonFieldType{
 		Id:   fieldType.Identifier,
-		Type: PrepareType(fieldType.Type, results),
+		Type: p.prepareType(fieldType.Type, results),
 	}
 }
 
-func prepareFields(fieldTypes []cadence.Field, results TypePreparationResults) []jsonFieldType {
+func (p preparer) prepareFields(fieldTypes []cadence.Field, results TypePreparationResults) []jsonFieldType {
 	fields := make([]jsonFieldType, len(fieldTypes))
 	for i, fieldType := range fieldTypes {
-		fields[i] = prepareFieldType(fieldType, results)
+		fields[i] = p.prepareFieldType(fieldType, results)
 	}
 	return fields
 }
 
-func prepareTypeParameters(typeParameters []cadence.TypeParameter, results TypePreparationResults) []jsonTypeParameter {
+func (p preparer) prepareTypeParameters(typeParameters []cadence.TypeParameter, results TypePreparationResults) []jsonTypeParameter {
 	result := make([]jsonTypeParameter, len(typeParameters))
 	for i, typeParameter := range typeParameters {
-		result[i] = prepareTypeParameter(typeParameter, results)
+		result[i] = p.prepareTypeParameter(typeParameter, results)
 	}
 	return result
 }
 
-func prepareParameters(parameters []cadence.Parameter, results TypePreparationResults) []jsonParameterType {
+func (p preparer) prepareParameters(parameters []cadence.Parameter, results TypePreparationResults) []jsonParameterType {
 	result := make([]jsonParameterType, len(parameters))
 	for i, param := range parameters {
-		result[i] = prepareParameter(param, results)
+		result[i] = p.prepareParameter(param, results)
 	}
 	return result
 }
 
-func prepareInitializers(initializers [][]cadence.Parameter, results TypePreparationResults) [][]jsonParameterType {
+func (p preparer) prepareInitializers(initializers [][]cadence.Parameter, results TypePreparationResults) [][]jsonParameterType {
 	result := make([][]jsonParameterType, len(initializers))
 	for i, params := range initializers {
-		result[i] = prepareParameters(params, results)
+		result[i] = p.prepareParameters(params, results)
 	}
 	return result
 }
 
+// PrepareType returns the JSON representation of the given type.
+// Nominal types which are already in the given results are represented by their type ID
 func PrepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
+	return preparer{}.prepareType(typ, results)
+}
+
+func (p preparer) prepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
 
 	var supportedRecursiveType bool
 	switch typ.(type) {
@@ -838,93 +878,93 @@ func PrepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
 	case *cadence.OptionalType:
 		return jsonUnaryType{
 			Kind: "Optional",
-			Type: PrepareType(typ.Type, results),
+			Type: p.prepareType(typ.Type, results),
 		}
 	case *cadence.VariableSizedArrayType:
 		return jsonUnaryType{
 			Kind: "VariableSizedArray",
-			Type: PrepareType(typ.ElementType, results),
+			Type: p.prepareType(typ.ElementType, results),
 		}
 	case *cadence.ConstantSizedArrayType:
 		return jsonConstantSizedArrayType{
 			Kind: "ConstantSizedArray",
-			Type: PrepareType(typ.ElementType, results),
+			Type: p.prepareType(typ.ElementType, results),
 			Size: typ.Size,
 		}
 	case *cadence.DictionaryType:
 		return jsonDictionaryType{
 			Kind:      "Dictionary",
-			KeyType:   PrepareType(typ.KeyType, results),
-			ValueType: PrepareType(typ.ElementType, results),
+			KeyType:   p.prepareType(typ.KeyType, results),
+			ValueType: p.prepareType(typ.ElementType, results),
 		}
 	case *cadence.InclusiveRangeType:
 		return jsonInclusiveRangeType{
 			Kind:        "InclusiveRange",
-			ElementType: PrepareType(typ.ElementType, results),
+			ElementType: p.prepareType(typ.ElementType, results),
 		}
 	case *cadence.StructType:
 		return jsonNominalType{
 			Kind:         "Struct",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getCompositeTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getCompositeTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ResourceType:
 		return jsonNominalType{
 			Kind:         "Resource",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getCompositeTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getCompositeTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.EventType:
 		return jsonNominalType{
 			Kind:         "Event",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getCompositeTypeFields(typ), results),
-			Initializers: [][]jsonParameterType{prepareParameters(typ.Initializer, results)},
+			Fields:       p.prepareFields(getCompositeTypeFields(typ), results),
+			Initializers: [][]jsonParameterType{p.prepareParameters(typ.Initializer, results)},
 		}
 	case *cadence.ContractType:
 		return jsonNominalType{
 			Kind:         "Contract",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getCompositeTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getCompositeTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.StructInterfaceType:
 		return jsonNominalType{
 			Kind:         "StructInterface",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getInterfaceTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getInterfaceTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ResourceInterfaceType:
 		return jsonNominalType{
 			Kind:         "ResourceInterface",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getInterfaceTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getInterfaceTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.ContractInterfaceType:
 		return jsonNominalType{
 			Kind:         "ContractInterface",
 			Type:         "",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getInterfaceTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
+			Fields:       p.prepareFields(getInterfaceTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
 		}
 	case *cadence.FunctionType:
 		typeJson := jsonFunctionType{
 			Kind:           "Function",
 			TypeID:         typ.ID(),
-			TypeParameters: prepareTypeParameters(typ.TypeParameters, results),
-			Parameters:     prepareParameters(typ.Parameters, results),
-			Return:         PrepareType(typ.ReturnType, results),
+			TypeParameters: p.prepareTypeParameters(typ.TypeParameters, results),
+			Parameters:     p.prepareParameters(typ.Parameters, results),
+			Return:         p.prepareType(typ.ReturnType, results),
 		}
 		if typ.Purity == cadence.FunctionPurityView {
 			typeJson.Purity = "view"
@@ -934,12 +974,20 @@ func PrepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
 		return jsonReferenceType{
 			Kind:          "Reference",
 			Authorization: prepareAuthorization(typ.Authorization),
-			Type:          PrepareType(typ.Type, results),
+			Type:          p.prepareType(typ.Type, results),
 		}
 	case *cadence.IntersectionType:
-		types := make([]jsonValue, len(typ.Types))
-		for i, typ := range typ.Types {
-			types[i] = PrepareType(typ, results)
+		intersectionTypes := typ.Types
+		if p.canonical {
+			intersectionTypes = slices.Clone(intersectionTypes)
+			slices.SortStableFunc(intersectionTypes, func(a, b cadence.Type) int {
+				return strings.Compare(a.ID(), b.ID())
+			})
+		}
+
+		types := make([]jsonValue, len(intersectionTypes))
+		for i, typ := range intersectionTypes {
+			types[i] = p.prepareType(typ, results)
 		}
 		return jsonIntersectionType{
 			Kind:   "Intersection",
@@ -949,15 +997,15 @@ func PrepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
 	case *cadence.CapabilityType:
 		return jsonUnaryType{
 			Kind: "Capability",
-			Type: PrepareType(typ.BorrowType, results),
+			Type: p.prepareType(typ.BorrowType, results),
 		}
 	case *cadence.EnumType:
 		return jsonNominalType{
 			Kind:         "Enum",
 			TypeID:       string(common.NewTypeIDFromQualifiedName(nil, typ.Location, typ.QualifiedIdentifier)),
-			Fields:       prepareFields(getCompositeTypeFields(typ), results),
-			Initializers: prepareInitializers(typ.Initializers, results),
-			Type:         PrepareType(typ.RawType, results),
+			Fields:       p.prepareFields(getCompositeTypeFields(typ), results),
+			Initializers: p.prepareInitializers(typ.Initializers, results),
+			Type:         p.prepareType(typ.RawType, results),
 		}
 	case cadence.PrimitiveType:
 		return jsonSimpleType{
@@ -972,20 +1020,20 @@ func PrepareType(typ cadence.Type, results TypePreparationResults) jsonValue {
 
 type TypePreparationResults map[cadence.Type]struct{}
 
-func prepareTypeValue(typeValue cadence.TypeValue) jsonValue {
+func (p preparer) prepareTypeValue(typeValue cadence.TypeValue) jsonValue {
 	return jsonValueObject{
 		Type: typeTypeStr,
 		Value: jsonTypeValue{
-			StaticType: PrepareType(typeValue.StaticType, TypePreparationResults{}),
+			StaticType: p.prepareType(typeValue.StaticType, TypePreparationResults{}),
 		},
 	}
 }
 
-func prepareCapability(capability cadence.Capability) jsonValue {
+func (p preparer) prepareCapability(capability cadence.Capability) jsonValue {
 	capabilityJson := jsonCapabilityValue{
 		ID:         encodeUInt(uint64(capability.ID)),
 		Address:    encodeBytes(capability.Address.Bytes()),
-		BorrowType: PrepareType(capability.BorrowType, TypePreparationResults{}),
+		BorrowType: p.prepareType(capability.BorrowType, TypePreparationResults{}),
 	}
 
 	if capability.DeprecatedPath != nil {
@@ -998,11 +1046,11 @@ func prepareCapability(capability cadence.Capability) jsonValue {
 	}
 }
 
-func prepareFunction(function cadence.Function) jsonValue {
+func (p preparer) prepareFunction(function cadence.Function) jsonValue {
 	return jsonValueObject{
 		Type: functionTypeStr,
 		Value: jsonFunctionValue{
-			FunctionType: PrepareType(function.FunctionType, TypePreparationResults{}),
+			FunctionType: p.prepareType(function.FunctionType, TypePreparationResults{}),
 		},
 	}
 }
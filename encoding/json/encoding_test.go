@@ -1454,7 +1454,105 @@ func TestEncodeStruct(t *testing.T) {
         `,
 	}
 
-	testAllEncodeAndDecode(t, simpleStruct, resourceStruct)
+	attachmentType := cadence.NewAttachmentType(
+		TestLocation,
+		"FooAttachment",
+		nil,
+		[]cadence.Field{
+			{
+				Identifier: "c",
+				Type:       cadence.BoolType,
+			},
+		},
+		nil,
+	)
+
+	attachmentStruct := encodeTest{
+		"Attachments",
+		cadence.NewStruct(
+			[]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("foo"),
+			},
+		).
+			WithType(simpleStructType).
+			WithAttachments([]cadence.Attachment{
+				cadence.NewAttachment(
+					[]cadence.Value{
+						cadence.NewBool(true),
+					},
+				).WithType(attachmentType),
+			}),
+		// language=json
+		`
+          {
+            "type": "Struct",
+            "value": {
+              "id": "S.test.FooStruct",
+              "fields": [
+                {
+                  "name": "a",
+                  "value": {
+                    "type": "Int",
+                    "value": "1"
+                  }
+                },
+                {
+                  "name": "b",
+                  "value": {
+                    "type": "String",
+                    "value": "foo"
+                  }
+                }
+              ],
+              "attachments": [
+                {
+                  "type": "Attachment",
+                  "value": {
+                    "id": "S.test.FooAttachment",
+                    "fields": [
+                      {
+                        "name": "c",
+                        "value": {
+                          "type": "Bool",
+                          "value": true
+                        }
+                      }
+                    ]
+                  }
+                }
+              ]
+            }
+          }
+        `,
+	}
+
+	testAllEncodeAndDecode(t, simpleStruct, resourceStruct, attachmentStruct)
+}
+
+func TestDecodeInvalidAttachments(t *testing.T) {
+
+	t.Parallel()
+
+	// language=json
+	encodedValue := `
+      {
+        "type": "Struct",
+        "value": {
+          "id": "S.test.FooStruct",
+          "fields": [],
+          "attachments": [
+            {
+              "type": "Int",
+              "value": "1"
+            }
+          ]
+        }
+      }
+    `
+	_, err := Decode(nil, []byte(encodedValue))
+	require.Error(t, err)
+	assert.Equal(t, "failed to decode JSON-Cadence value: invalid attachment: expected attachment value", err.Error())
 }
 
 func TestEncodeInclusiveRange(t *testing.T) {
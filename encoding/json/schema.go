@@ -0,0 +1,626 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Const                any                    `json:"const,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	PrefixItems          []*jsonSchema          `json:"prefixItems,omitempty"`
+	Items                any                    `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+const (
+	signedIntegerPattern      = `^-?[0-9]+$`
+	unsignedIntegerPattern    = `^[0-9]+$`
+	signedFixedPointPattern   = `^-?[0-9]+\.[0-9]{1,8}$`
+	unsignedFixedPointPattern = `^[0-9]+\.[0-9]{1,8}$`
+	addressPattern            = `^0x[0-9a-fA-F]{16}$`
+)
+
+// jsonSimpleTypes are the JSON-CDC type names of values of types
+// which are not described by the expected type alone
+var jsonSimpleTypes = map[cadence.Type]string{
+	cadence.VoidType:        voidTypeStr,
+	cadence.BoolType:        boolTypeStr,
+	cadence.CharacterType:   characterTypeStr,
+	cadence.StringType:      stringTypeStr,
+	cadence.AddressType:     addressTypeStr,
+	cadence.IntType:         intTypeStr,
+	cadence.Int8Type:        int8TypeStr,
+	cadence.Int16Type:       int16TypeStr,
+	cadence.Int32Type:       int32TypeStr,
+	cadence.Int64Type:       int64TypeStr,
+	cadence.Int128Type:      int128TypeStr,
+	cadence.Int256Type:      int256TypeStr,
+	cadence.UIntType:        uintTypeStr,
+	cadence.UInt8Type:       uint8TypeStr,
+	cadence.UInt16Type:      uint16TypeStr,
+	cadence.UInt32Type:      uint32TypeStr,
+	cadence.UInt64Type:      uint64TypeStr,
+	cadence.UInt128Type:     uint128TypeStr,
+	cadence.UInt256Type:     uint256TypeStr,
+	cadence.Word8Type:       word8TypeStr,
+	cadence.Word16Type:      word16TypeStr,
+	cadence.Word32Type:      word32TypeStr,
+	cadence.Word64Type:      word64TypeStr,
+	cadence.Word128Type:     word128TypeStr,
+	cadence.Word256Type:     word256TypeStr,
+	cadence.Fix64Type:       fix64TypeStr,
+	cadence.UFix64Type:      ufix64TypeStr,
+	cadence.StoragePathType: pathTypeStr,
+	cadence.PublicPathType:  pathTypeStr,
+	cadence.PrivatePathType: pathTypeStr,
+	cadence.MetaType:        typeTypeStr,
+}
+
+// Schema returns a JSON Schema (draft 2020-12) of JSON-CDC encoded values of the given type.
+//
+// Composite types are described in the `$defs` of the schema, by type ID.
+// Values of abstract types (e.g. AnyStruct) are described as any JSON-CDC value.
+func Schema(t cadence.Type) ([]byte, error) {
+	g := &schemaGenerator{
+		defs: map[string]*jsonSchema{},
+	}
+
+	schema := g.valueSchema(t)
+
+	schema.Schema = jsonSchemaDialect
+
+	if len(g.defs) > 0 {
+		schema.Defs = g.defs
+	}
+
+	return json.Marshal(schema)
+}
+
+type schemaGenerator struct {
+	defs map[string]*jsonSchema
+}
+
+func (g *schemaGenerator) valueSchema(t cadence.Type) *jsonSchema {
+	if typeStr, ok := jsonSimpleTypes[t]; ok {
+		return simpleValueSchema(t, typeStr)
+	}
+
+	switch t := t.(type) {
+	case *cadence.OptionalType:
+		return valueObjectSchema(
+			optionalTypeStr,
+			&jsonSchema{
+				AnyOf: []*jsonSchema{
+					{Type: "null"},
+					g.valueSchema(t.Type),
+				},
+			},
+		)
+
+	case *cadence.VariableSizedArrayType:
+		return valueObjectSchema(
+			arrayTypeStr,
+			&jsonSchema{
+				Type:  "array",
+				Items: g.valueSchema(t.ElementType),
+			},
+		)
+
+	case *cadence.ConstantSizedArrayType:
+		size := int(t.Size)
+		return valueObjectSchema(
+			arrayTypeStr,
+			&jsonSchema{
+				Type:     "array",
+				Items:    g.valueSchema(t.ElementType),
+				MinItems: &size,
+				MaxItems: &size,
+			},
+		)
+
+	case *cadence.DictionaryType:
+		return valueObjectSchema(
+			dictionaryTypeStr,
+			&jsonSchema{
+				Type: "array",
+				Items: objectSchema(
+					map[string]*jsonSchema{
+						keyKey:   g.valueSchema(t.KeyType),
+						valueKey: g.valueSchema(t.ElementType),
+					},
+					keyKey,
+					valueKey,
+				),
+			},
+		)
+
+	case *cadence.InclusiveRangeType:
+		elementSchema := g.valueSchema(t.ElementType)
+		return valueObjectSchema(
+			inclusiveRangeTypeStr,
+			objectSchema(
+				map[string]*jsonSchema{
+					startKey: elementSchema,
+					endKey:   elementSchema,
+					stepKey:  elementSchema,
+				},
+				startKey,
+				endKey,
+				stepKey,
+			),
+		)
+
+	case *cadence.ReferenceType:
+		// Referenced values are encoded as their dereferenced value.
+		return g.valueSchema(t.Type)
+
+	case *cadence.CapabilityType:
+		return valueObjectSchema(
+			capabilityTypeStr,
+			objectSchema(
+				map[string]*jsonSchema{
+					idKey:         {Type: "string", Pattern: unsignedIntegerPattern},
+					addressKey:    {Type: "string", Pattern: addressPattern},
+					borrowTypeKey: {},
+				},
+				idKey,
+				addressKey,
+				borrowTypeKey,
+			),
+		)
+
+	case cadence.CompositeType:
+		return g.compositeSchema(t)
+	}
+
+	// Values of abstract types may be any value.
+	return anyValueSchema()
+}
+
+func (g *schemaGenerator) compositeSchema(t cadence.CompositeType) *jsonSchema {
+	id := t.ID()
+
+	ref := &jsonSchema{
+		Ref: "#/$defs/" + escapeJSONPointer(id),
+	}
+
+	if _, ok := g.defs[id]; ok {
+		return ref
+	}
+
+	// Add the definition before generating the field schemas,
+	// so recursive types refer to it
+	def := &jsonSchema{}
+	g.defs[id] = def
+
+	fieldTypes := getCompositeTypeFields(t)
+
+	fieldSchemas := make([]*jsonSchema, len(fieldTypes))
+	for i, field := range fieldTypes {
+		fieldSchemas[i] = objectSchema(
+			map[string]*jsonSchema{
+				nameKey:  {Const: field.Identifier},
+				valueKey: g.valueSchema(field.Type),
+			},
+			nameKey,
+			valueKey,
+		)
+	}
+
+	fieldCount := len(fieldTypes)
+
+	properties := map[string]*jsonSchema{
+		idKey: {Const: id},
+		fieldsKey: {
+			Type:        "array",
+			PrefixItems: fieldSchemas,
+			Items:       false,
+			MinItems:    &fieldCount,
+		},
+	}
+
+	var kind string
+	switch t.(type) {
+	case *cadence.StructType:
+		kind = structTypeStr
+	case *cadence.ResourceType:
+		kind = resourceTypeStr
+	case *cadence.EventType:
+		kind = eventTypeStr
+	case *cadence.ContractType:
+		kind = contractTypeStr
+	case *cadence.EnumType:
+		kind = enumTypeStr
+	case *cadence.AttachmentType:
+		kind = attachmentTypeStr
+	default:
+		panic(errors.NewUnexpectedError("unsupported composite type %T", t))
+	}
+
+	switch kind {
+	case structTypeStr, resourceTypeStr:
+		properties[attachmentsKey] = &jsonSchema{
+			Type:  "array",
+			Items: anyValueSchema(),
+		}
+	}
+
+	*def = *valueObjectSchema(
+		kind,
+		objectSchema(properties, idKey, fieldsKey),
+	)
+
+	return ref
+}
+
+func simpleValueSchema(t cadence.Type, typeStr string) *jsonSchema {
+	var valueSchema *jsonSchema
+
+	switch t {
+	case cadence.VoidType:
+		return objectSchema(
+			map[string]*jsonSchema{
+				typeKey: {Const: voidTypeStr},
+			},
+			typeKey,
+		)
+
+	case cadence.BoolType:
+		valueSchema = &jsonSchema{Type: "boolean"}
+
+	case cadence.CharacterType, cadence.StringType:
+		valueSchema = &jsonSchema{Type: "string"}
+
+	case cadence.AddressType:
+		valueSchema = &jsonSchema{Type: "string", Pattern: addressPattern}
+
+	case cadence.IntType,
+		cadence.Int8Type,
+		cadence.Int16Type,
+		cadence.Int32Type,
+		cadence.Int64Type,
+		cadence.Int128Type,
+		cadence.Int256Type:
+
+		valueSchema = &jsonSchema{Type: "string", Pattern: signedIntegerPattern}
+
+	case cadence.Fix64Type:
+		valueSchema = &jsonSchema{Type: "string", Pattern: signedFixedPointPattern}
+
+	case cadence.UFix64Type:
+		valueSchema = &jsonSchema{Type: "string", Pattern: unsignedFixedPointPattern}
+
+	case cadence.StoragePathType:
+		valueSchema = pathValueSchema(common.PathDomainStorage)
+
+	case cadence.PublicPathType:
+		valueSchema = pathValueSchema(common.PathDomainPublic)
+
+	case cadence.PrivatePathType:
+		valueSchema = pathValueSchema(common.PathDomainPrivate)
+
+	case cadence.MetaType:
+		valueSchema = objectSchema(
+			map[string]*jsonSchema{
+				staticTypeKey: {},
+			},
+			staticTypeKey,
+		)
+
+	default:
+		// Unsigned integers and words
+		valueSchema = &jsonSchema{Type: "string", Pattern: unsignedIntegerPattern}
+	}
+
+	return valueObjectSchema(typeStr, valueSchema)
+}
+
+func pathValueSchema(domain common.PathDomain) *jsonSchema {
+	return objectSchema(
+		map[string]*jsonSchema{
+			domainKey:     {Const: domain.Identifier()},
+			identifierKey: {Type: "string"},
+		},
+		domainKey,
+		identifierKey,
+	)
+}
+
+// valueObjectSchema returns the schema of a JSON-CDC value object
+// with the given type name and value schema
+func valueObjectSchema(typeStr string, valueSchema *jsonSchema) *jsonSchema {
+	return objectSchema(
+		map[string]*jsonSchema{
+			typeKey:  {Const: typeStr},
+			valueKey: valueSchema,
+		},
+		typeKey,
+		valueKey,
+	)
+}
+
+// anyValueSchema returns the schema of any JSON-CDC value
+func anyValueSchema() *jsonSchema {
+	return &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			typeKey: {Type: "string"},
+		},
+		Required: []string{typeKey},
+	}
+}
+
+func objectSchema(properties map[string]*jsonSchema, required ...string) *jsonSchema {
+	additionalProperties := false
+	return &jsonSchema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &additionalProperties,
+	}
+}
+
+// escapeJSONPointer escapes the given string for use as a JSON Pointer (RFC 6901) reference token
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// Validate checks that the given bytes are a JSON-CDC encoded value of the expected type.
+//
+// Optional, array, dictionary, and composite values are only checked against the expected type,
+// and not decoded, so payloads can be rejected early and cheaply.
+//
+// Values of abstract expected types (e.g. AnyStruct) may have any type.
+func Validate(b []byte, expectedType cadence.Type, options ...Option) (err error) {
+	dec := NewDecoder(nil, bytes.NewReader(b))
+
+	for _, option := range options {
+		option(dec)
+	}
+
+	var valueJSON any
+
+	err = dec.dec.Decode(&valueJSON)
+	if err != nil {
+		return errors.NewDefaultUserError("failed to decode JSON: %w", err)
+	}
+
+	// capture panics that occur during validation
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr, isError := r.(error)
+			if !isError {
+				panic(r)
+			}
+
+			err = errors.NewDefaultUserError("invalid JSON-Cadence value: %w", panicErr)
+		}
+	}()
+
+	dec.validate(valueJSON, expectedType)
+
+	return nil
+}
+
+func (d *Decoder) validate(valueJSON any, expectedType cadence.Type) {
+	if typeStr, ok := jsonSimpleTypes[expectedType]; ok {
+		obj := toObject(valueJSON)
+		checkValueType(obj, typeStr)
+
+		value := d.DecodeJSON(valueJSON)
+
+		// Paths of all domains have the same type name
+		if path, ok := value.(cadence.Path); ok && !path.Type().Equal(expectedType) {
+			panic(errors.NewDefaultUserError(
+				"expected %s, got %s",
+				expectedType.ID(),
+				path.Type().ID(),
+			))
+		}
+
+		return
+	}
+
+	switch t := expectedType.(type) {
+	case *cadence.OptionalType:
+		valueJSON := validateValueObject(valueJSON, optionalTypeStr)
+		if valueJSON != nil {
+			d.validate(valueJSON, t.Type)
+		}
+		return
+
+	case *cadence.VariableSizedArrayType:
+		valueJSON := validateValueObject(valueJSON, arrayTypeStr)
+		for _, elementJSON := range toSlice(valueJSON) {
+			d.validate(elementJSON, t.ElementType)
+		}
+		return
+
+	case *cadence.ConstantSizedArrayType:
+		valueJSON := validateValueObject(valueJSON, arrayTypeStr)
+		elementsJSON := toSlice(valueJSON)
+		if uint(len(elementsJSON)) != t.Size {
+			panic(errors.NewDefaultUserError(
+				"invalid array: expected %d elements, got %d",
+				t.Size,
+				len(elementsJSON),
+			))
+		}
+		for _, elementJSON := range elementsJSON {
+			d.validate(elementJSON, t.ElementType)
+		}
+		return
+
+	case *cadence.DictionaryType:
+		valueJSON := validateValueObject(valueJSON, dictionaryTypeStr)
+		for _, pairJSON := range toSlice(valueJSON) {
+			obj := toObject(pairJSON)
+			d.validate(obj.Get(keyKey), t.KeyType)
+			d.validate(obj.Get(valueKey), t.ElementType)
+		}
+		return
+
+	case *cadence.InclusiveRangeType:
+		valueJSON := validateValueObject(valueJSON, inclusiveRangeTypeStr)
+		obj := toObject(valueJSON)
+		d.validate(obj.Get(startKey), t.ElementType)
+		d.validate(obj.Get(endKey), t.ElementType)
+		d.validate(obj.Get(stepKey), t.ElementType)
+		return
+
+	case *cadence.ReferenceType:
+		// Referenced values are encoded as their dereferenced value.
+		d.validate(valueJSON, t.Type)
+		return
+
+	case *cadence.CapabilityType:
+		obj := toObject(valueJSON)
+		checkValueType(obj, capabilityTypeStr)
+		d.DecodeJSON(valueJSON)
+		return
+
+	case cadence.CompositeType:
+		d.validateComposite(valueJSON, t)
+		return
+	}
+
+	// Values of abstract types may have any type,
+	// but must be valid.
+	d.DecodeJSON(valueJSON)
+}
+
+func (d *Decoder) validateComposite(valueJSON any, t cadence.CompositeType) {
+	var kind string
+	var hasAttachments bool
+
+	switch t.(type) {
+	case *cadence.StructType:
+		kind = structTypeStr
+		hasAttachments = true
+	case *cadence.ResourceType:
+		kind = resourceTypeStr
+		hasAttachments = true
+	case *cadence.EventType:
+		kind = eventTypeStr
+	case *cadence.ContractType:
+		kind = contractTypeStr
+	case *cadence.EnumType:
+		kind = enumTypeStr
+	case *cadence.AttachmentType:
+		kind = attachmentTypeStr
+	default:
+		panic(errors.NewUnexpectedError("unsupported composite type %T", t))
+	}
+
+	obj := toObject(validateValueObject(valueJSON, kind))
+
+	id := obj.GetString(idKey)
+	if id != t.ID() {
+		panic(errors.NewDefaultUserError(
+			"invalid composite: expected ID `%s`, got `%s`",
+			t.ID(),
+			id,
+		))
+	}
+
+	fieldTypes := getCompositeTypeFields(t)
+	fieldsJSON := obj.GetSlice(fieldsKey)
+
+	if len(fieldsJSON) != len(fieldTypes) {
+		panic(errors.NewDefaultUserError(
+			"invalid composite `%s`: expected %d fields, got %d",
+			id,
+			len(fieldTypes),
+			len(fieldsJSON),
+		))
+	}
+
+	for i, fieldJSON := range fieldsJSON {
+		fieldType := fieldTypes[i]
+
+		fieldObj := toObject(fieldJSON)
+
+		name := fieldObj.GetString(nameKey)
+		if name != fieldType.Identifier {
+			panic(errors.NewDefaultUserError(
+				"invalid composite `%s`: expected field `%s`, got `%s`",
+				id,
+				fieldType.Identifier,
+				name,
+			))
+		}
+
+		d.validate(fieldObj.Get(valueKey), fieldType.Type)
+	}
+
+	if attachmentsJSON, ok := obj[attachmentsKey]; ok {
+		if !hasAttachments {
+			panic(errors.NewDefaultUserError(
+				"invalid composite `%s`: unexpected attachments",
+				id,
+			))
+		}
+		d.decodeAttachments(attachmentsJSON)
+	}
+}
+
+// validateValueObject checks that the given JSON is a value object of the given type,
+// and returns the value
+func validateValueObject(valueJSON any, typeStr string) any {
+	obj := toObject(valueJSON)
+
+	checkValueType(obj, typeStr)
+
+	// object should only contain two keys: "type", "value"
+	if len(obj) != 2 {
+		panic(errors.NewDefaultUserError("expected JSON object with keys `%s` and `%s`", typeKey, valueKey))
+	}
+
+	return obj.Get(valueKey)
+}
+
+func checkValueType(obj jsonObject, typeStr string) {
+	actualTypeStr := obj.GetString(typeKey)
+	if actualTypeStr != typeStr {
+		panic(errors.NewDefaultUserError(
+			"expected %s value, got %s value",
+			typeStr,
+			actualTypeStr,
+		))
+	}
+}
@@ -0,0 +1,411 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+func newSchemaTestStructType() *cadence.StructType {
+	fields := []cadence.Field{
+		{
+			Identifier: "a",
+			Type:       cadence.Int8Type,
+		},
+		{
+			Identifier: "next",
+		},
+	}
+
+	structType := cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		fields,
+		nil,
+	)
+
+	fields[1].Type = &cadence.OptionalType{
+		Type: structType,
+	}
+
+	return structType
+}
+
+func TestSchema(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+
+		schema, err := Schema(cadence.NewVariableSizedArrayType(cadence.UFix64Type))
+		require.NoError(t, err)
+
+		assert.JSONEq(t,
+			// language=json
+			`
+              {
+                "$schema": "https://json-schema.org/draft/2020-12/schema",
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Array"},
+                  "value": {
+                    "type": "array",
+                    "items": {
+                      "type": "object",
+                      "properties": {
+                        "type": {"const": "UFix64"},
+                        "value": {"type": "string", "pattern": "^[0-9]+\\.[0-9]{1,8}$"}
+                      },
+                      "required": ["type", "value"],
+                      "additionalProperties": false
+                    }
+                  }
+                },
+                "required": ["type", "value"],
+                "additionalProperties": false
+              }
+            `,
+			string(schema),
+		)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+
+		schema, err := Schema(cadence.PublicPathType)
+		require.NoError(t, err)
+
+		assert.JSONEq(t,
+			// language=json
+			`
+              {
+                "$schema": "https://json-schema.org/draft/2020-12/schema",
+                "type": "object",
+                "properties": {
+                  "type": {"const": "Path"},
+                  "value": {
+                    "type": "object",
+                    "properties": {
+                      "domain": {"const": "public"},
+                      "identifier": {"type": "string"}
+                    },
+                    "required": ["domain", "identifier"],
+                    "additionalProperties": false
+                  }
+                },
+                "required": ["type", "value"],
+                "additionalProperties": false
+              }
+            `,
+			string(schema),
+		)
+	})
+
+	t.Run("abstract", func(t *testing.T) {
+		t.Parallel()
+
+		schema, err := Schema(cadence.AnyStructType)
+		require.NoError(t, err)
+
+		assert.JSONEq(t,
+			// language=json
+			`
+              {
+                "$schema": "https://json-schema.org/draft/2020-12/schema",
+                "type": "object",
+                "properties": {
+                  "type": {"type": "string"}
+                },
+                "required": ["type"]
+              }
+            `,
+			string(schema),
+		)
+	})
+
+	t.Run("recursive composite", func(t *testing.T) {
+		t.Parallel()
+
+		schema, err := Schema(newSchemaTestStructType())
+		require.NoError(t, err)
+
+		assert.JSONEq(t,
+			// language=json
+			`
+              {
+                "$schema": "https://json-schema.org/draft/2020-12/schema",
+                "$ref": "#/$defs/S.test.Foo",
+                "$defs": {
+                  "S.test.Foo": {
+                    "type": "object",
+                    "properties": {
+                      "type": {"const": "Struct"},
+                      "value": {
+                        "type": "object",
+                        "properties": {
+                          "id": {"const": "S.test.Foo"},
+                          "fields": {
+                            "type": "array",
+                            "prefixItems": [
+                              {
+                                "type": "object",
+                                "properties": {
+                                  "name": {"const": "a"},
+                                  "value": {
+                                    "type": "object",
+                                    "properties": {
+                                      "type": {"const": "Int8"},
+                                      "value": {"type": "string", "pattern": "^-?[0-9]+$"}
+                                    },
+                                    "required": ["type", "value"],
+                                    "additionalProperties": false
+                                  }
+                                },
+                                "required": ["name", "value"],
+                                "additionalProperties": false
+                              },
+                              {
+                                "type": "object",
+                                "properties": {
+                                  "name": {"const": "next"},
+                                  "value": {
+                                    "type": "object",
+                                    "properties": {
+                                      "type": {"const": "Optional"},
+                                      "value": {
+                                        "anyOf": [
+                                          {"type": "null"},
+                                          {"$ref": "#/$defs/S.test.Foo"}
+                                        ]
+                                      }
+                                    },
+                                    "required": ["type", "value"],
+                                    "additionalProperties": false
+                                  }
+                                },
+                                "required": ["name", "value"],
+                                "additionalProperties": false
+                              }
+                            ],
+                            "items": false,
+                            "minItems": 2
+                          },
+                          "attachments": {
+                            "type": "array",
+                            "items": {
+                              "type": "object",
+                              "properties": {
+                                "type": {"type": "string"}
+                              },
+                              "required": ["type"]
+                            }
+                          }
+                        },
+                        "required": ["id", "fields"],
+                        "additionalProperties": false
+                      }
+                    },
+                    "required": ["type", "value"],
+                    "additionalProperties": false
+                  }
+                }
+              }
+            `,
+			string(schema),
+		)
+	})
+}
+
+func TestValidate(t *testing.T) {
+
+	t.Parallel()
+
+	structType := newSchemaTestStructType()
+
+	newStruct := func(next cadence.Value) cadence.Struct {
+		return cadence.NewStruct([]cadence.Value{
+			cadence.Int8(1),
+			cadence.NewOptional(next),
+		}).WithType(structType)
+	}
+
+	storagePath := cadence.MustNewPath(common.PathDomainStorage, "foo")
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []cadence.Value{
+			newStruct(nil),
+			newStruct(newStruct(nil)),
+			cadence.NewInt(42),
+			cadence.UFix64(1_00000000),
+			storagePath,
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.String("b"),
+			}).WithType(cadence.NewConstantSizedArrayType(2, cadence.StringType)),
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("a"),
+					Value: newStruct(nil),
+				},
+			}).WithType(cadence.NewDictionaryType(cadence.StringType, structType)),
+		} {
+			encoded, err := Encode(value)
+			require.NoError(t, err)
+
+			err = Validate(encoded, value.Type())
+			require.NoError(t, err, value.String())
+		}
+	})
+
+	t.Run("abstract", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := Encode(newStruct(nil))
+		require.NoError(t, err)
+
+		err = Validate(encoded, cadence.AnyStructType)
+		require.NoError(t, err)
+
+		err = Validate([]byte(`{"type":"Int","value":"x"}`), cadence.AnyStructType)
+		require.ErrorContains(t, err, "invalid Int: x")
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		t.Parallel()
+
+		optionalType := cadence.NewOptionalType(cadence.AddressType)
+
+		err := Validate([]byte(`{"type":"Optional","value":null}`), optionalType)
+		require.NoError(t, err)
+
+		err = Validate([]byte(`{"type":"Optional","value":{"type":"Int","value":"1"}}`), optionalType)
+		require.ErrorContains(t, err, "expected Address value, got Int value")
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		err := Validate([]byte(`{"type":"Int","value":"1"}`), cadence.StringType)
+		require.ErrorContains(t, err, "invalid JSON-Cadence value: expected String value, got Int value")
+		require.True(t, errors.IsUserError(err))
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		t.Parallel()
+
+		err := Validate([]byte(`{"type":"Int8","value":"128"}`), cadence.Int8Type)
+		require.ErrorContains(t, err, "invalid Int8")
+	})
+
+	t.Run("path domain", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := Encode(storagePath)
+		require.NoError(t, err)
+
+		err = Validate(encoded, cadence.PublicPathType)
+		require.ErrorContains(t, err, "expected PublicPath, got StoragePath")
+	})
+
+	t.Run("array size", func(t *testing.T) {
+		t.Parallel()
+
+		err := Validate(
+			[]byte(`{"type":"Array","value":[{"type":"String","value":"a"}]}`),
+			cadence.NewConstantSizedArrayType(2, cadence.StringType),
+		)
+		require.ErrorContains(t, err, "invalid array: expected 2 elements, got 1")
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		t.Parallel()
+
+		err := Validate(
+			[]byte(`{"type":"Struct","value":{"id":"S.test.Bar","fields":[]}}`),
+			structType,
+		)
+		require.ErrorContains(t, err, "invalid composite: expected ID `S.test.Foo`, got `S.test.Bar`")
+
+		err = Validate(
+			[]byte(`{"type":"Resource","value":{"id":"S.test.Foo","fields":[]}}`),
+			structType,
+		)
+		require.ErrorContains(t, err, "expected Struct value, got Resource value")
+
+		err = Validate(
+			[]byte(`{"type":"Struct","value":{"id":"S.test.Foo","fields":[]}}`),
+			structType,
+		)
+		require.ErrorContains(t, err, "invalid composite `S.test.Foo`: expected 2 fields, got 0")
+
+		err = Validate(
+			// language=json
+			[]byte(`
+              {
+                "type": "Struct",
+                "value": {
+                  "id": "S.test.Foo",
+                  "fields": [
+                    {"name": "b", "value": {"type": "Int8", "value": "1"}},
+                    {"name": "next", "value": {"type": "Optional", "value": null}}
+                  ]
+                }
+              }
+            `),
+			structType,
+		)
+		require.ErrorContains(t, err, "invalid composite `S.test.Foo`: expected field `a`, got `b`")
+
+		err = Validate(
+			// language=json
+			[]byte(`
+              {
+                "type": "Struct",
+                "value": {
+                  "id": "S.test.Foo",
+                  "fields": [
+                    {"name": "a", "value": {"type": "Int8", "value": "1"}},
+                    {"name": "next", "value": {"type": "Optional", "value": {"type": "Int8", "value": "1"}}}
+                  ]
+                }
+              }
+            `),
+			structType,
+		)
+		require.ErrorContains(t, err, "expected Struct value, got Int8 value")
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		err := Validate([]byte(`{"type":`), cadence.IntType)
+		require.ErrorContains(t, err, "failed to decode JSON")
+	})
+}
@@ -0,0 +1,1382 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/sema"
+)
+
+// decoder decodes Cadence values and types from Protocol Buffers messages.
+//
+// Decoding is metered using the memory gauge, if any.
+type decoder struct {
+	gauge       common.MemoryGauge
+	definitions []cadence.Type
+}
+
+// Decode returns a Cadence value decoded from its Protocol Buffers encoding, a ValueMessage.
+//
+// This function returns an error if the bytes are malformed,
+// or do not conform to the messages in cadence.proto.
+func Decode(gauge common.MemoryGauge, b []byte) (value cadence.Value, err error) {
+	defer recoverDecodingError(&err, "value")
+
+	d := &decoder{
+		gauge: gauge,
+	}
+
+	content := d.decodeMessage(b, messageFieldValue, "value")
+
+	return d.decodeValue(content), nil
+}
+
+// DecodeType returns a Cadence type decoded from its Protocol Buffers encoding, a TypeMessage.
+//
+// This function returns an error if the bytes are malformed,
+// or do not conform to the messages in cadence.proto.
+func DecodeType(gauge common.MemoryGauge, b []byte) (typ cadence.Type, err error) {
+	defer recoverDecodingError(&err, "type")
+
+	d := &decoder{
+		gauge: gauge,
+	}
+
+	content := d.decodeMessage(b, messageFieldType, "type")
+
+	return d.decodeType(content), nil
+}
+
+// recoverDecodingError captures panics that occur during decoding
+func recoverDecodingError(err *error, subject string) {
+	if r := recover(); r != nil {
+		panicErr, isError := r.(error)
+		if !isError {
+			panic(r)
+		}
+
+		*err = errors.NewDefaultUserError("failed to decode Cadence %s from protobuf: %w", subject, panicErr)
+	}
+}
+
+// decodeMessage decodes the type definitions of a top-level message,
+// and returns the content of the given field
+func (d *decoder) decodeMessage(b []byte, field uint64, name string) []byte {
+	var definitions [][]byte
+	var content []byte
+	var hasContent bool
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case messageFieldTypeDefinitions:
+			definitions = append(definitions, r.bytes())
+		case field:
+			content = r.bytes()
+			hasContent = true
+		default:
+			r.skip()
+		}
+	}
+
+	d.decodeTypeDefinitions(definitions)
+
+	if !hasContent {
+		panic(errors.NewDefaultUserError("missing %s", name))
+	}
+
+	return content
+}
+
+// Values
+
+func (d *decoder) decodeValue(b []byte) cadence.Value {
+	var result cadence.Value
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case valueFieldVoid:
+			r.bytes()
+			result = cadence.NewMeteredVoid(d.gauge)
+		case valueFieldOptional:
+			result = d.decodeOptional(r.bytes())
+		case valueFieldBool:
+			result = cadence.NewMeteredBool(d.gauge, r.bool())
+		case valueFieldString:
+			result = d.decodeString(r.string())
+		case valueFieldCharacter:
+			result = d.decodeCharacter(r.string())
+		case valueFieldAddress:
+			result = d.decodeAddress(r.bytes())
+		case valueFieldInt:
+			result = d.decodeInt(r.bytes())
+		case valueFieldInt8:
+			result = cadence.NewMeteredInt8(d.gauge, int8(decodeSignedInteger(r, "Int8", math.MinInt8, math.MaxInt8)))
+		case valueFieldInt16:
+			result = cadence.NewMeteredInt16(d.gauge, int16(decodeSignedInteger(r, "Int16", math.MinInt16, math.MaxInt16)))
+		case valueFieldInt32:
+			result = cadence.NewMeteredInt32(d.gauge, int32(decodeSignedInteger(r, "Int32", math.MinInt32, math.MaxInt32)))
+		case valueFieldInt64:
+			result = cadence.NewMeteredInt64(d.gauge, r.sint64())
+		case valueFieldInt128:
+			result = d.decodeInt128(r.bytes())
+		case valueFieldInt256:
+			result = d.decodeInt256(r.bytes())
+		case valueFieldUInt:
+			result = d.decodeUInt(r.bytes())
+		case valueFieldUInt8:
+			result = cadence.NewMeteredUInt8(d.gauge, uint8(decodeUnsignedInteger(r, "UInt8", math.MaxUint8)))
+		case valueFieldUInt16:
+			result = cadence.NewMeteredUInt16(d.gauge, uint16(decodeUnsignedInteger(r, "UInt16", math.MaxUint16)))
+		case valueFieldUInt32:
+			result = cadence.NewMeteredUInt32(d.gauge, uint32(decodeUnsignedInteger(r, "UInt32", math.MaxUint32)))
+		case valueFieldUInt64:
+			result = cadence.NewMeteredUInt64(d.gauge, r.uint64())
+		case valueFieldUInt128:
+			result = d.decodeUInt128(r.bytes())
+		case valueFieldUInt256:
+			result = d.decodeUInt256(r.bytes())
+		case valueFieldWord8:
+			result = cadence.NewMeteredWord8(d.gauge, uint8(decodeUnsignedInteger(r, "Word8", math.MaxUint8)))
+		case valueFieldWord16:
+			result = cadence.NewMeteredWord16(d.gauge, uint16(decodeUnsignedInteger(r, "Word16", math.MaxUint16)))
+		case valueFieldWord32:
+			result = cadence.NewMeteredWord32(d.gauge, uint32(decodeUnsignedInteger(r, "Word32", math.MaxUint32)))
+		case valueFieldWord64:
+			result = cadence.NewMeteredWord64(d.gauge, r.uint64())
+		case valueFieldWord128:
+			result = d.decodeWord128(r.bytes())
+		case valueFieldWord256:
+			result = d.decodeWord256(r.bytes())
+		case valueFieldFix64:
+			value, err := cadence.NewMeteredFix64FromRawFixedPointNumber(d.gauge, r.sint64())
+			if err != nil {
+				panic(errors.NewDefaultUserError("invalid Fix64: %w", err))
+			}
+			result = value
+		case valueFieldUFix64:
+			value, err := cadence.NewMeteredUFix64FromRawFixedPointNumber(d.gauge, r.uint64())
+			if err != nil {
+				panic(errors.NewDefaultUserError("invalid UFix64: %w", err))
+			}
+			result = value
+		case valueFieldArray:
+			result = d.decodeArray(r.bytes())
+		case valueFieldDictionary:
+			result = d.decodeDictionary(r.bytes())
+		case valueFieldStruct:
+			result = d.decodeStruct(r.bytes())
+		case valueFieldResource:
+			result = d.decodeResource(r.bytes())
+		case valueFieldEvent:
+			result = d.decodeEvent(r.bytes())
+		case valueFieldContract:
+			result = d.decodeContract(r.bytes())
+		case valueFieldEnum:
+			result = d.decodeEnum(r.bytes())
+		case valueFieldAttachment:
+			result = d.decodeAttachment(r.bytes())
+		case valueFieldInclusiveRange:
+			result = d.decodeInclusiveRange(r.bytes())
+		case valueFieldPath:
+			result = d.decodePath(r.bytes())
+		case valueFieldTypeValue:
+			result = d.decodeTypeValue(r.bytes())
+		case valueFieldCapability:
+			result = d.decodeCapability(r.bytes())
+		case valueFieldFunction:
+			result = d.decodeFunction(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	if result == nil {
+		panic(errors.NewDefaultUserError("missing value"))
+	}
+
+	return result
+}
+
+func decodeSignedInteger(r *messageReader, typeName string, min, max int64) int64 {
+	value := r.sint64()
+	if value < min || value > max {
+		panic(errors.NewDefaultUserError("invalid %s: %d is out of range", typeName, value))
+	}
+	return value
+}
+
+func decodeUnsignedInteger(r *messageReader, typeName string, max uint64) uint64 {
+	value := r.uint64()
+	if value > max {
+		panic(errors.NewDefaultUserError("invalid %s: %d is out of range", typeName, value))
+	}
+	return value
+}
+
+func (d *decoder) decodeOptional(b []byte) cadence.Optional {
+	var value cadence.Value
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case optionalFieldValue:
+			value = d.decodeValue(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredOptional(d.gauge, value)
+}
+
+func (d *decoder) decodeString(s string) cadence.String {
+	str, err := cadence.NewMeteredString(
+		d.gauge,
+		common.NewCadenceStringMemoryUsage(len(s)),
+		func() string {
+			return s
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	return str
+}
+
+func (d *decoder) decodeCharacter(s string) cadence.Character {
+	char, err := cadence.NewMeteredCharacter(
+		d.gauge,
+		common.NewCadenceCharacterMemoryUsage(len(s)),
+		func() string {
+			return s
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+	return char
+}
+
+func (d *decoder) decodeAddress(b []byte) cadence.Address {
+	if len(b) != cadence.AddressLength {
+		panic(errors.NewDefaultUserError(
+			"invalid address: expected %d bytes, got %d",
+			cadence.AddressLength,
+			len(b),
+		))
+	}
+
+	return cadence.BytesToMeteredAddress(d.gauge, b)
+}
+
+func (d *decoder) decodeBigInt(b []byte) *big.Int {
+	var negative bool
+	var magnitude []byte
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case bigIntFieldNegative:
+			negative = r.bool()
+		case bigIntFieldMagnitude:
+			magnitude = r.bytes()
+		default:
+			r.skip()
+		}
+	}
+
+	value := new(big.Int)
+	if len(magnitude) > 0 {
+		value.SetBytes(magnitude)
+		if negative {
+			value.Neg(value)
+		}
+	}
+
+	return value
+}
+
+func (d *decoder) decodeInt(b []byte) cadence.Int {
+	bigInt := d.decodeBigInt(b)
+
+	return cadence.NewMeteredIntFromBig(
+		d.gauge,
+		common.NewCadenceIntMemoryUsage(
+			common.BigIntByteLength(bigInt),
+		),
+		func() *big.Int {
+			return bigInt
+		},
+	)
+}
+
+func (d *decoder) decodeInt128(b []byte) cadence.Int128 {
+	value, err := cadence.NewMeteredInt128FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid Int128: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeInt256(b []byte) cadence.Int256 {
+	value, err := cadence.NewMeteredInt256FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid Int256: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeUInt(b []byte) cadence.UInt {
+	bigInt := d.decodeBigInt(b)
+
+	value, err := cadence.NewMeteredUIntFromBig(
+		d.gauge,
+		common.NewCadenceIntMemoryUsage(
+			common.BigIntByteLength(bigInt),
+		),
+		func() *big.Int {
+			return bigInt
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid UInt: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeUInt128(b []byte) cadence.UInt128 {
+	value, err := cadence.NewMeteredUInt128FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid UInt128: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeUInt256(b []byte) cadence.UInt256 {
+	value, err := cadence.NewMeteredUInt256FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid UInt256: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeWord128(b []byte) cadence.Word128 {
+	value, err := cadence.NewMeteredWord128FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid Word128: %w", err))
+	}
+	return value
+}
+
+func (d *decoder) decodeWord256(b []byte) cadence.Word256 {
+	value, err := cadence.NewMeteredWord256FromBig(
+		d.gauge,
+		func() *big.Int {
+			return d.decodeBigInt(b)
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid Word256: %w", err))
+	}
+	return value
+}
+
+// container is the content of an Array, Dictionary, or Composite message
+type container struct {
+	typ         cadence.Type
+	elements    [][]byte
+	attachments [][]byte
+}
+
+func (d *decoder) decodeContainer(b []byte) container {
+	var result container
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case containerFieldType:
+			result.typ = d.decodeType(r.bytes())
+		case containerFieldElements:
+			result.elements = append(result.elements, r.bytes())
+		case compositeFieldAttachments:
+			result.attachments = append(result.attachments, r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return result
+}
+
+func (d *decoder) decodeArray(b []byte) cadence.Array {
+	content := d.decodeContainer(b)
+
+	var arrayType cadence.ArrayType
+	if content.typ != nil {
+		var ok bool
+		arrayType, ok = content.typ.(cadence.ArrayType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid array type: %s", content.typ.ID()))
+		}
+	}
+
+	value, err := cadence.NewMeteredArray(
+		d.gauge,
+		len(content.elements),
+		func() ([]cadence.Value, error) {
+			values := make([]cadence.Value, len(content.elements))
+			for i, element := range content.elements {
+				values[i] = d.decodeValue(element)
+			}
+			return values, nil
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid array: %w", err))
+	}
+
+	return value.WithType(arrayType)
+}
+
+func (d *decoder) decodeDictionary(b []byte) cadence.Dictionary {
+	content := d.decodeContainer(b)
+
+	var dictionaryType *cadence.DictionaryType
+	if content.typ != nil {
+		var ok bool
+		dictionaryType, ok = content.typ.(*cadence.DictionaryType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid dictionary type: %s", content.typ.ID()))
+		}
+	}
+
+	value, err := cadence.NewMeteredDictionary(
+		d.gauge,
+		len(content.elements),
+		func() ([]cadence.KeyValuePair, error) {
+			pairs := make([]cadence.KeyValuePair, len(content.elements))
+			for i, element := range content.elements {
+				pairs[i] = d.decodeKeyValuePair(element)
+			}
+			return pairs, nil
+		},
+	)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid dictionary: %w", err))
+	}
+
+	return value.WithType(dictionaryType)
+}
+
+func (d *decoder) decodeKeyValuePair(b []byte) cadence.KeyValuePair {
+	var key, value cadence.Value
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case keyValuePairFieldKey:
+			key = d.decodeValue(r.bytes())
+		case keyValuePairFieldValue:
+			value = d.decodeValue(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	if key == nil || value == nil {
+		panic(errors.NewDefaultUserError("invalid dictionary entry: missing key or value"))
+	}
+
+	return cadence.NewMeteredKeyValuePair(d.gauge, key, value)
+}
+
+// composite is the decoded content of a Composite message
+type composite struct {
+	typ         cadence.CompositeType
+	fieldValues []cadence.Value
+	attachments []cadence.Attachment
+}
+
+func (d *decoder) decodeComposite(b []byte, kind string) composite {
+	content := d.decodeContainer(b)
+
+	var result composite
+
+	if content.typ != nil {
+		compositeType, ok := content.typ.(cadence.CompositeType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid %s type: %s", kind, content.typ.ID()))
+		}
+
+		fieldCount := len(getCompositeTypeFields(compositeType))
+		if len(content.elements) != fieldCount {
+			panic(errors.NewDefaultUserError(
+				"invalid %s `%s`: expected %d fields, got %d",
+				kind,
+				compositeType.ID(),
+				fieldCount,
+				len(content.elements),
+			))
+		}
+
+		result.typ = compositeType
+	}
+
+	result.fieldValues = make([]cadence.Value, len(content.elements))
+	for i, element := range content.elements {
+		result.fieldValues[i] = d.decodeValue(element)
+	}
+
+	if len(content.attachments) > 0 {
+		result.attachments = make([]cadence.Attachment, len(content.attachments))
+		for i, attachment := range content.attachments {
+			attachmentValue, ok := d.decodeValue(attachment).(cadence.Attachment)
+			if !ok {
+				panic(errors.NewDefaultUserError("invalid attachment: expected attachment value"))
+			}
+			result.attachments[i] = attachmentValue
+		}
+	}
+
+	return result
+}
+
+func (d *decoder) compositeConstructor(comp composite) func() ([]cadence.Value, error) {
+	return func() ([]cadence.Value, error) {
+		return comp.fieldValues, nil
+	}
+}
+
+func requireNoAttachments(comp composite, kind string) {
+	if len(comp.attachments) > 0 {
+		panic(errors.NewDefaultUserError("invalid %s: only structs and resources may have attachments", kind))
+	}
+}
+
+func (d *decoder) decodeStruct(b []byte) cadence.Struct {
+	comp := d.decodeComposite(b, "struct")
+
+	var structType *cadence.StructType
+	if comp.typ != nil {
+		var ok bool
+		structType, ok = comp.typ.(*cadence.StructType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid struct type: %s", comp.typ.ID()))
+		}
+	}
+
+	structure, err := cadence.NewMeteredStruct(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid struct: %w", err))
+	}
+
+	return structure.
+		WithType(structType).
+		WithAttachments(comp.attachments)
+}
+
+func (d *decoder) decodeResource(b []byte) cadence.Resource {
+	comp := d.decodeComposite(b, "resource")
+
+	var resourceType *cadence.ResourceType
+	if comp.typ != nil {
+		var ok bool
+		resourceType, ok = comp.typ.(*cadence.ResourceType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid resource type: %s", comp.typ.ID()))
+		}
+	}
+
+	resource, err := cadence.NewMeteredResource(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid resource: %w", err))
+	}
+
+	return resource.
+		WithType(resourceType).
+		WithAttachments(comp.attachments)
+}
+
+func (d *decoder) decodeEvent(b []byte) cadence.Event {
+	comp := d.decodeComposite(b, "event")
+	requireNoAttachments(comp, "event")
+
+	var eventType *cadence.EventType
+	if comp.typ != nil {
+		var ok bool
+		eventType, ok = comp.typ.(*cadence.EventType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid event type: %s", comp.typ.ID()))
+		}
+	}
+
+	event, err := cadence.NewMeteredEvent(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid event: %w", err))
+	}
+
+	return event.WithType(eventType)
+}
+
+func (d *decoder) decodeContract(b []byte) cadence.Contract {
+	comp := d.decodeComposite(b, "contract")
+	requireNoAttachments(comp, "contract")
+
+	var contractType *cadence.ContractType
+	if comp.typ != nil {
+		var ok bool
+		contractType, ok = comp.typ.(*cadence.ContractType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid contract type: %s", comp.typ.ID()))
+		}
+	}
+
+	contract, err := cadence.NewMeteredContract(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid contract: %w", err))
+	}
+
+	return contract.WithType(contractType)
+}
+
+func (d *decoder) decodeEnum(b []byte) cadence.Enum {
+	comp := d.decodeComposite(b, "enum")
+	requireNoAttachments(comp, "enum")
+
+	var enumType *cadence.EnumType
+	if comp.typ != nil {
+		var ok bool
+		enumType, ok = comp.typ.(*cadence.EnumType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid enum type: %s", comp.typ.ID()))
+		}
+	}
+
+	enum, err := cadence.NewMeteredEnum(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid enum: %w", err))
+	}
+
+	return enum.WithType(enumType)
+}
+
+func (d *decoder) decodeAttachment(b []byte) cadence.Attachment {
+	comp := d.decodeComposite(b, "attachment")
+	requireNoAttachments(comp, "attachment")
+
+	var attachmentType *cadence.AttachmentType
+	if comp.typ != nil {
+		var ok bool
+		attachmentType, ok = comp.typ.(*cadence.AttachmentType)
+		if !ok {
+			panic(errors.NewDefaultUserError("invalid attachment type: %s", comp.typ.ID()))
+		}
+	}
+
+	attachment, err := cadence.NewMeteredAttachment(d.gauge, len(comp.fieldValues), d.compositeConstructor(comp))
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid attachment: %w", err))
+	}
+
+	return attachment.WithType(attachmentType)
+}
+
+func (d *decoder) decodeInclusiveRange(b []byte) *cadence.InclusiveRange {
+	var inclusiveRangeType *cadence.InclusiveRangeType
+	var start, end, step cadence.Value
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case inclusiveRangeFieldType:
+			typ := d.decodeType(r.bytes())
+			var ok bool
+			inclusiveRangeType, ok = typ.(*cadence.InclusiveRangeType)
+			if !ok {
+				panic(errors.NewDefaultUserError("invalid inclusive range type: %s", typ.ID()))
+			}
+		case inclusiveRangeFieldStart:
+			start = d.decodeValue(r.bytes())
+		case inclusiveRangeFieldEnd:
+			end = d.decodeValue(r.bytes())
+		case inclusiveRangeFieldStep:
+			step = d.decodeValue(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	if start == nil || end == nil || step == nil {
+		panic(errors.NewDefaultUserError("invalid inclusive range: missing start, end, or step"))
+	}
+
+	return cadence.NewMeteredInclusiveRange(d.gauge, start, end, step).
+		WithType(inclusiveRangeType)
+}
+
+func (d *decoder) decodePath(b []byte) cadence.Path {
+	var domain common.PathDomain
+	var identifier string
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case pathFieldDomain:
+			domain = common.PathDomain(r.uint64())
+		case pathFieldIdentifier:
+			identifier = r.string()
+		default:
+			r.skip()
+		}
+	}
+
+	common.UseMemory(d.gauge, common.NewRawStringMemoryUsage(len(identifier)))
+
+	path, err := cadence.NewMeteredPath(d.gauge, domain, identifier)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid path: %w", err))
+	}
+	return path
+}
+
+func (d *decoder) decodeTypeValue(b []byte) cadence.TypeValue {
+	var staticType cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case typeValueFieldType:
+			staticType = d.decodeType(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredTypeValue(d.gauge, staticType)
+}
+
+func (d *decoder) decodeCapability(b []byte) cadence.Capability {
+	var id uint64
+	var address []byte
+	var borrowType cadence.Type
+	var deprecatedPath *cadence.Path
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case capabilityFieldID:
+			id = r.uint64()
+		case capabilityFieldAddress:
+			address = r.bytes()
+		case capabilityFieldBorrowType:
+			borrowType = d.decodeType(r.bytes())
+		case capabilityFieldDeprecatedPath:
+			path := d.decodePath(r.bytes())
+			deprecatedPath = &path
+		default:
+			r.skip()
+		}
+	}
+
+	if deprecatedPath != nil {
+		return cadence.NewDeprecatedMeteredPathCapability(
+			d.gauge,
+			d.decodeAddress(address),
+			*deprecatedPath,
+			borrowType,
+		)
+	}
+
+	return cadence.NewMeteredCapability(
+		d.gauge,
+		cadence.NewMeteredUInt64(d.gauge, id),
+		d.decodeAddress(address),
+		borrowType,
+	)
+}
+
+func (d *decoder) decodeFunction(b []byte) cadence.Function {
+	var functionType *cadence.FunctionType
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case functionFieldType:
+			typ := d.decodeType(r.bytes())
+			var ok bool
+			functionType, ok = typ.(*cadence.FunctionType)
+			if !ok {
+				panic(errors.NewDefaultUserError("invalid function type: %s", typ.ID()))
+			}
+		default:
+			r.skip()
+		}
+	}
+
+	if functionType == nil {
+		panic(errors.NewDefaultUserError("invalid function: missing function type"))
+	}
+
+	return cadence.NewMeteredFunction(d.gauge, functionType)
+}
+
+// Types
+
+var primitiveTypes = func() map[string]cadence.Type {
+	typeMap := make(map[string]cadence.Type, interpreter.PrimitiveStaticType_Count)
+
+	// Bytes is not a primitive static type
+	typeMap["Bytes"] = cadence.TheBytesType
+
+	for ty := interpreter.PrimitiveStaticType(1); ty < interpreter.PrimitiveStaticType_Count; ty++ {
+		if !ty.IsDefined() || ty.IsDeprecated() { //nolint:staticcheck
+			continue
+		}
+
+		typeMap[string(ty.SemaType().ID())] = cadence.PrimitiveType(ty)
+	}
+
+	return typeMap
+}()
+
+// decodeOptionalType decodes the embedded Type message in the given field, if any
+func (d *decoder) decodeOptionalType(b []byte, field uint64) cadence.Type {
+	var result cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case field:
+			result = d.decodeType(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return result
+}
+
+func (d *decoder) decodeType(b []byte) cadence.Type {
+	var result cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case typeFieldPrimitive:
+			id := r.string()
+			primitiveType, ok := primitiveTypes[id]
+			if !ok {
+				panic(errors.NewDefaultUserError("invalid primitive type: %s", id))
+			}
+			result = primitiveType
+
+		case typeFieldOptional:
+			result = cadence.NewMeteredOptionalType(
+				d.gauge,
+				d.decodeOptionalType(r.bytes(), unaryTypeFieldType),
+			)
+
+		case typeFieldVariableSizedArray:
+			result = cadence.NewMeteredVariableSizedArrayType(
+				d.gauge,
+				d.decodeOptionalType(r.bytes(), unaryTypeFieldType),
+			)
+
+		case typeFieldConstantSizedArray:
+			result = d.decodeConstantSizedArrayType(r.bytes())
+
+		case typeFieldDictionary:
+			content := r.bytes()
+			result = cadence.NewMeteredDictionaryType(
+				d.gauge,
+				d.decodeOptionalType(content, dictionaryTypeFieldKeyType),
+				d.decodeOptionalType(content, dictionaryTypeFieldElementType),
+			)
+
+		case typeFieldInclusiveRange:
+			result = cadence.NewMeteredInclusiveRangeType(
+				d.gauge,
+				d.decodeOptionalType(r.bytes(), unaryTypeFieldType),
+			)
+
+		case typeFieldCapability:
+			result = cadence.NewMeteredCapabilityType(
+				d.gauge,
+				d.decodeOptionalType(r.bytes(), unaryTypeFieldType),
+			)
+
+		case typeFieldReference:
+			result = d.decodeReferenceType(r.bytes())
+
+		case typeFieldIntersection:
+			result = d.decodeIntersectionType(r.bytes())
+
+		case typeFieldFunction:
+			result = d.decodeFunctionType(r.bytes())
+
+		case typeFieldDefinition:
+			index := r.uint64()
+			if index >= uint64(len(d.definitions)) {
+				panic(errors.NewDefaultUserError(
+					"invalid type definition index %d: only %d type definitions",
+					index,
+					len(d.definitions),
+				))
+			}
+			result = d.definitions[index]
+
+		case typeFieldTypeID:
+			result = cadence.TypeID(r.string())
+
+		default:
+			r.skip()
+		}
+	}
+
+	if result == nil {
+		panic(errors.NewDefaultUserError("missing type"))
+	}
+
+	return result
+}
+
+func (d *decoder) decodeConstantSizedArrayType(b []byte) cadence.Type {
+	var elementType cadence.Type
+	var size uint64
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case unaryTypeFieldType:
+			elementType = d.decodeType(r.bytes())
+		case constantSizedArrayTypeSize:
+			size = r.uint64()
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredConstantSizedArrayType(d.gauge, uint(size), elementType)
+}
+
+func (d *decoder) decodeReferenceType(b []byte) cadence.Type {
+	var authorization cadence.Authorization
+	var referencedType cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case referenceTypeFieldAuthorization:
+			authorization = d.decodeAuthorization(r.bytes())
+		case referenceTypeFieldType:
+			referencedType = d.decodeType(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredReferenceType(d.gauge, authorization, referencedType)
+}
+
+func (d *decoder) decodeAuthorization(b []byte) cadence.Authorization {
+	var result cadence.Authorization
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case authorizationFieldUnauthorized:
+			r.bytes()
+			result = cadence.UnauthorizedAccess
+		case authorizationFieldEntitlementConjunctionSet:
+			result = d.decodeEntitlementSet(r.bytes(), cadence.Conjunction)
+		case authorizationFieldEntitlementDisjunctionSet:
+			result = d.decodeEntitlementSet(r.bytes(), cadence.Disjunction)
+		case authorizationFieldEntitlementMap:
+			result = cadence.NewEntitlementMapAuthorization(d.gauge, common.TypeID(r.string()))
+		default:
+			r.skip()
+		}
+	}
+
+	if result == nil {
+		panic(errors.NewDefaultUserError("missing authorization"))
+	}
+
+	return result
+}
+
+func (d *decoder) decodeEntitlementSet(b []byte, kind cadence.EntitlementSetKind) cadence.Authorization {
+	var entitlements []common.TypeID
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case entitlementSetFieldEntitlements:
+			entitlements = append(entitlements, common.TypeID(r.string()))
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewEntitlementSetAuthorization(d.gauge, entitlements, kind)
+}
+
+func (d *decoder) decodeIntersectionType(b []byte) cadence.Type {
+	var types []cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case intersectionTypeFieldTypes:
+			types = append(types, d.decodeType(r.bytes()))
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredIntersectionType(d.gauge, types)
+}
+
+func (d *decoder) decodeFunctionType(b []byte) cadence.Type {
+	purity := cadence.FunctionPurityUnspecified
+	var typeParameterMessages, parameterMessages [][]byte
+	var returnType cadence.Type
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case functionTypeFieldPurity:
+			switch value := r.uint64(); value {
+			case functionPurityUnspecified:
+				purity = cadence.FunctionPurityUnspecified
+			case functionPurityView:
+				purity = cadence.FunctionPurityView
+			default:
+				panic(errors.NewDefaultUserError("invalid function purity: %d", value))
+			}
+		case functionTypeFieldTypeParameters:
+			typeParameterMessages = append(typeParameterMessages, r.bytes())
+		case functionTypeFieldParameters:
+			parameterMessages = append(parameterMessages, r.bytes())
+		case functionTypeFieldReturnType:
+			returnType = d.decodeType(r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return cadence.NewMeteredFunctionType(
+		d.gauge,
+		purity,
+		d.decodeTypeParameters(typeParameterMessages),
+		d.decodeParameters(parameterMessages),
+		returnType,
+	)
+}
+
+func (d *decoder) decodeTypeParameters(messages [][]byte) []cadence.TypeParameter {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	common.UseMemory(d.gauge, common.MemoryUsage{
+		Kind:   common.MemoryKindCadenceTypeParameter,
+		Amount: uint64(len(messages)),
+	})
+
+	typeParameters := make([]cadence.TypeParameter, len(messages))
+	for i, message := range messages {
+		var name string
+		var typeBound cadence.Type
+
+		r := newMessageReader(message)
+		for r.next() {
+			switch r.field {
+			case typeParameterFieldName:
+				name = r.string()
+			case typeParameterFieldTypeBound:
+				typeBound = d.decodeType(r.bytes())
+			default:
+				r.skip()
+			}
+		}
+
+		// Unmetered because decodeTypeParameters is metered
+		typeParameters[i] = cadence.NewTypeParameter(name, typeBound)
+	}
+
+	return typeParameters
+}
+
+func (d *decoder) decodeParameters(messages [][]byte) []cadence.Parameter {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	common.UseMemory(d.gauge, common.MemoryUsage{
+		Kind:   common.MemoryKindCadenceParameter,
+		Amount: uint64(len(messages)),
+	})
+
+	parameters := make([]cadence.Parameter, len(messages))
+	for i, message := range messages {
+		var label, identifier string
+		var typ cadence.Type
+
+		r := newMessageReader(message)
+		for r.next() {
+			switch r.field {
+			case parameterFieldLabel:
+				label = r.string()
+			case parameterFieldIdentifier:
+				identifier = r.string()
+			case parameterFieldType:
+				typ = d.decodeType(r.bytes())
+			default:
+				r.skip()
+			}
+		}
+
+		// Unmetered because decodeParameters is metered
+		parameters[i] = cadence.NewParameter(label, identifier, typ)
+	}
+
+	return parameters
+}
+
+// Type definitions
+
+// typeDefinition is a TypeDefinition message, of which only the kind and type ID are decoded
+type typeDefinition struct {
+	kind         uint64
+	typeID       string
+	fields       [][]byte
+	initializers [][]byte
+	rawType      []byte
+	baseType     []byte
+}
+
+// decodeTypeDefinitions decodes the type definitions of a top-level message.
+//
+// As definitions may refer to each other, all types are first created without members,
+// and are completed once all definitions are known.
+func (d *decoder) decodeTypeDefinitions(messages [][]byte) {
+	definitions := make([]typeDefinition, len(messages))
+	d.definitions = make([]cadence.Type, len(messages))
+
+	for i, message := range messages {
+		definition := &definitions[i]
+
+		r := newMessageReader(message)
+		for r.next() {
+			switch r.field {
+			case typeDefinitionFieldKind:
+				definition.kind = r.uint64()
+			case typeDefinitionFieldTypeID:
+				definition.typeID = r.string()
+			case typeDefinitionFieldFields:
+				definition.fields = append(definition.fields, r.bytes())
+			case typeDefinitionFieldInitializers:
+				definition.initializers = append(definition.initializers, r.bytes())
+			case typeDefinitionFieldRawType:
+				definition.rawType = r.bytes()
+			case typeDefinitionFieldBaseType:
+				definition.baseType = r.bytes()
+			default:
+				r.skip()
+			}
+		}
+
+		d.definitions[i] = d.newDefinedType(definition.kind, definition.typeID)
+	}
+
+	for i, definition := range definitions {
+		d.completeDefinedType(d.definitions[i], definition)
+	}
+}
+
+func (d *decoder) newDefinedType(kind uint64, typeID string) cadence.Type {
+	location, qualifiedIdentifier, err := common.DecodeTypeID(d.gauge, typeID)
+	if err != nil {
+		panic(errors.NewDefaultUserError("invalid type ID `%s`: %w", typeID, err))
+	} else if location == nil && sema.NativeCompositeTypes[typeID] == nil {
+		// If the location is nil, and there is no native composite type with this ID, then it's an invalid type.
+		panic(errors.NewDefaultUserError("invalid type ID for built-in: `%s`", typeID))
+	}
+
+	switch kind {
+	case typeDefinitionKindStruct:
+		return cadence.NewMeteredStructType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindResource:
+		return cadence.NewMeteredResourceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindEvent:
+		return cadence.NewMeteredEventType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindContract:
+		return cadence.NewMeteredContractType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindEnum:
+		return cadence.NewMeteredEnumType(d.gauge, location, qualifiedIdentifier, nil, nil, nil)
+	case typeDefinitionKindAttachment:
+		return cadence.NewMeteredAttachmentType(d.gauge, location, qualifiedIdentifier, nil, nil, nil)
+	case typeDefinitionKindStructInterface:
+		return cadence.NewMeteredStructInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindResourceInterface:
+		return cadence.NewMeteredResourceInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefinitionKindContractInterface:
+		return cadence.NewMeteredContractInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	}
+
+	panic(errors.NewDefaultUserError("invalid type definition kind: %d", kind))
+}
+
+func (d *decoder) completeDefinedType(typ cadence.Type, definition typeDefinition) {
+	fields := d.decodeFields(definition.fields)
+
+	var initializers [][]cadence.Parameter
+	if len(definition.initializers) > 0 {
+		// Unmetered because this is created as an array of nil arrays, not Parameter structs
+		initializers = make([][]cadence.Parameter, len(definition.initializers))
+		for i, message := range definition.initializers {
+			initializers[i] = d.decodeInitializer(message)
+		}
+	}
+
+	switch t := typ.(type) {
+	case *cadence.StructType:
+		t.Initializers = initializers
+	case *cadence.ResourceType:
+		t.Initializers = initializers
+	case *cadence.EventType:
+		switch len(initializers) {
+		case 0:
+			break
+		case 1:
+			t.Initializer = initializers[0]
+		default:
+			panic(errors.NewDefaultUserError("invalid event type %s: events have at most one initializer", t.ID()))
+		}
+	case *cadence.ContractType:
+		t.Initializers = initializers
+	case *cadence.EnumType:
+		t.Initializers = initializers
+		if definition.rawType != nil {
+			t.RawType = d.decodeType(definition.rawType)
+		}
+	case *cadence.AttachmentType:
+		t.Initializers = initializers
+		if definition.baseType != nil {
+			t.BaseType = d.decodeType(definition.baseType)
+		}
+	case *cadence.StructInterfaceType:
+		t.Initializers = initializers
+	case *cadence.ResourceInterfaceType:
+		t.Initializers = initializers
+	case *cadence.ContractInterfaceType:
+		t.Initializers = initializers
+	}
+
+	switch t := typ.(type) {
+	case cadence.CompositeType:
+		setCompositeTypeFields(t, fields)
+	case cadence.InterfaceType:
+		setInterfaceTypeFields(t, fields)
+	}
+}
+
+func (d *decoder) decodeFields(messages [][]byte) []cadence.Field {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	common.UseMemory(d.gauge, common.MemoryUsage{
+		Kind:   common.MemoryKindCadenceField,
+		Amount: uint64(len(messages)),
+	})
+
+	fields := make([]cadence.Field, len(messages))
+	for i, message := range messages {
+		var identifier string
+		var typ cadence.Type
+
+		r := newMessageReader(message)
+		for r.next() {
+			switch r.field {
+			case fieldFieldIdentifier:
+				identifier = r.string()
+			case fieldFieldType:
+				typ = d.decodeType(r.bytes())
+			default:
+				r.skip()
+			}
+		}
+
+		// Unmetered because decodeFields is metered
+		fields[i] = cadence.NewField(identifier, typ)
+	}
+
+	return fields
+}
+
+func (d *decoder) decodeInitializer(b []byte) []cadence.Parameter {
+	var parameterMessages [][]byte
+
+	r := newMessageReader(b)
+	for r.next() {
+		switch r.field {
+		case initializerFieldParameters:
+			parameterMessages = append(parameterMessages, r.bytes())
+		default:
+			r.skip()
+		}
+	}
+
+	return d.decodeParameters(parameterMessages)
+}
@@ -0,0 +1,585 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"fmt"
+	"math/big"
+	goRuntime "runtime"
+
+	"github.com/onflow/cadence"
+)
+
+// encoder encodes Cadence values and types into Protocol Buffers messages.
+//
+// Composite and interface types are collected into the type definitions of the top-level message.
+type encoder struct {
+	definitions       []cadence.Type
+	definitionIndices map[cadence.Type]int
+}
+
+func newEncoder() *encoder {
+	return &encoder{
+		definitionIndices: map[cadence.Type]int{},
+	}
+}
+
+// Encode returns the Protocol Buffers encoding of the given value, a ValueMessage.
+//
+// This function returns an error if the Cadence value cannot be encoded.
+func Encode(value cadence.Value) (b []byte, err error) {
+	defer recoverEncodingError(&err, "value")
+
+	if value == nil {
+		return nil, fmt.Errorf("failed to encode value: unsupported value: nil")
+	}
+
+	e := newEncoder()
+
+	var valueBuilder messageBuilder
+	e.encodeValue(&valueBuilder, value)
+
+	return e.encodeMessage(messageFieldValue, valueBuilder.buf), nil
+}
+
+// MustEncode returns the Protocol Buffers encoding of the given value, or panics
+// if the value cannot be encoded.
+func MustEncode(value cadence.Value) []byte {
+	b, err := Encode(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// EncodeType returns the Protocol Buffers encoding of the given type, a TypeMessage.
+//
+// This function returns an error if the Cadence type cannot be encoded.
+func EncodeType(typ cadence.Type) (b []byte, err error) {
+	defer recoverEncodingError(&err, "type")
+
+	if typ == nil {
+		return nil, fmt.Errorf("failed to encode type: unsupported type: nil")
+	}
+
+	e := newEncoder()
+
+	var typeBuilder messageBuilder
+	e.encodeType(&typeBuilder, typ)
+
+	return e.encodeMessage(messageFieldType, typeBuilder.buf), nil
+}
+
+// recoverEncodingError captures panics that occur during encoding
+func recoverEncodingError(err *error, subject string) {
+	if r := recover(); r != nil {
+		// don't recover Go errors
+		goErr, ok := r.(goRuntime.Error)
+		if ok {
+			panic(goErr)
+		}
+
+		panicErr, isError := r.(error)
+		if !isError {
+			panic(r)
+		}
+
+		*err = fmt.Errorf("failed to encode %s: %w", subject, panicErr)
+	}
+}
+
+// encodeMessage writes a top-level message with the collected type definitions,
+// and the given already encoded content.
+func (e *encoder) encodeMessage(field uint64, content []byte) []byte {
+	var b messageBuilder
+
+	// Encoding a definition may add further definitions
+	for i := 0; i < len(e.definitions); i++ {
+		definition := e.definitions[i]
+		b.messageField(messageFieldTypeDefinitions, func(b *messageBuilder) {
+			e.encodeTypeDefinition(b, definition)
+		})
+	}
+
+	b.bytesField(field, content)
+
+	return b.buf
+}
+
+func (e *encoder) encodeValueField(b *messageBuilder, field uint64, value cadence.Value) {
+	b.messageField(field, func(b *messageBuilder) {
+		e.encodeValue(b, value)
+	})
+}
+
+func (e *encoder) encodeValue(b *messageBuilder, value cadence.Value) {
+	switch v := value.(type) {
+	case cadence.Void:
+		b.messageField(valueFieldVoid, func(*messageBuilder) {})
+	case cadence.Optional:
+		b.messageField(valueFieldOptional, func(b *messageBuilder) {
+			if v.Value != nil {
+				e.encodeValueField(b, optionalFieldValue, v.Value)
+			}
+		})
+	case cadence.Bool:
+		b.boolField(valueFieldBool, bool(v))
+	case cadence.String:
+		b.stringField(valueFieldString, string(v))
+	case cadence.Character:
+		b.stringField(valueFieldCharacter, string(v))
+	case cadence.Address:
+		b.bytesField(valueFieldAddress, v.Bytes())
+	case cadence.Int:
+		encodeBigIntField(b, valueFieldInt, v.Big())
+	case cadence.Int8:
+		b.sint64Field(valueFieldInt8, int64(v))
+	case cadence.Int16:
+		b.sint64Field(valueFieldInt16, int64(v))
+	case cadence.Int32:
+		b.sint64Field(valueFieldInt32, int64(v))
+	case cadence.Int64:
+		b.sint64Field(valueFieldInt64, int64(v))
+	case cadence.Int128:
+		encodeBigIntField(b, valueFieldInt128, v.Big())
+	case cadence.Int256:
+		encodeBigIntField(b, valueFieldInt256, v.Big())
+	case cadence.UInt:
+		encodeBigIntField(b, valueFieldUInt, v.Big())
+	case cadence.UInt8:
+		b.uint64Field(valueFieldUInt8, uint64(v))
+	case cadence.UInt16:
+		b.uint64Field(valueFieldUInt16, uint64(v))
+	case cadence.UInt32:
+		b.uint64Field(valueFieldUInt32, uint64(v))
+	case cadence.UInt64:
+		b.uint64Field(valueFieldUInt64, uint64(v))
+	case cadence.UInt128:
+		encodeBigIntField(b, valueFieldUInt128, v.Big())
+	case cadence.UInt256:
+		encodeBigIntField(b, valueFieldUInt256, v.Big())
+	case cadence.Word8:
+		b.uint64Field(valueFieldWord8, uint64(v))
+	case cadence.Word16:
+		b.uint64Field(valueFieldWord16, uint64(v))
+	case cadence.Word32:
+		b.uint64Field(valueFieldWord32, uint64(v))
+	case cadence.Word64:
+		b.uint64Field(valueFieldWord64, uint64(v))
+	case cadence.Word128:
+		encodeBigIntField(b, valueFieldWord128, v.Big())
+	case cadence.Word256:
+		encodeBigIntField(b, valueFieldWord256, v.Big())
+	case cadence.Fix64:
+		b.sint64Field(valueFieldFix64, int64(v))
+	case cadence.UFix64:
+		b.uint64Field(valueFieldUFix64, uint64(v))
+	case cadence.Array:
+		e.encodeArray(b, v)
+	case cadence.Dictionary:
+		e.encodeDictionary(b, v)
+	case *cadence.InclusiveRange:
+		e.encodeInclusiveRange(b, v)
+	case cadence.Struct:
+		var compositeType cadence.CompositeType
+		if v.StructType != nil {
+			compositeType = v.StructType
+		}
+		e.encodeComposite(b, valueFieldStruct, v, compositeType, v.Attachments())
+	case cadence.Resource:
+		var compositeType cadence.CompositeType
+		if v.ResourceType != nil {
+			compositeType = v.ResourceType
+		}
+		e.encodeComposite(b, valueFieldResource, v, compositeType, v.Attachments())
+	case cadence.Event:
+		var compositeType cadence.CompositeType
+		if v.EventType != nil {
+			compositeType = v.EventType
+		}
+		e.encodeComposite(b, valueFieldEvent, v, compositeType, nil)
+	case cadence.Contract:
+		var compositeType cadence.CompositeType
+		if v.ContractType != nil {
+			compositeType = v.ContractType
+		}
+		e.encodeComposite(b, valueFieldContract, v, compositeType, nil)
+	case cadence.Enum:
+		var compositeType cadence.CompositeType
+		if v.EnumType != nil {
+			compositeType = v.EnumType
+		}
+		e.encodeComposite(b, valueFieldEnum, v, compositeType, nil)
+	case cadence.Attachment:
+		var compositeType cadence.CompositeType
+		if v.AttachmentType != nil {
+			compositeType = v.AttachmentType
+		}
+		e.encodeComposite(b, valueFieldAttachment, v, compositeType, nil)
+	case cadence.Path:
+		b.messageField(valueFieldPath, func(b *messageBuilder) {
+			encodePath(b, v)
+		})
+	case cadence.TypeValue:
+		b.messageField(valueFieldTypeValue, func(b *messageBuilder) {
+			e.encodeTypeField(b, typeValueFieldType, v.StaticType)
+		})
+	case cadence.Capability:
+		e.encodeCapability(b, v)
+	case cadence.Function:
+		b.messageField(valueFieldFunction, func(b *messageBuilder) {
+			if v.FunctionType != nil {
+				e.encodeTypeField(b, functionFieldType, v.FunctionType)
+			}
+		})
+	default:
+		panic(fmt.Errorf("unsupported value: %T, %v", value, value))
+	}
+}
+
+func encodeBigIntField(b *messageBuilder, field uint64, value *big.Int) {
+	b.messageField(field, func(b *messageBuilder) {
+		if value.Sign() < 0 {
+			b.boolField(bigIntFieldNegative, true)
+		}
+		if value.Sign() != 0 {
+			b.bytesField(bigIntFieldMagnitude, value.Bytes())
+		}
+	})
+}
+
+func encodePath(b *messageBuilder, path cadence.Path) {
+	if path.Domain != 0 {
+		b.uint64Field(pathFieldDomain, uint64(path.Domain))
+	}
+	if path.Identifier != "" {
+		b.stringField(pathFieldIdentifier, path.Identifier)
+	}
+}
+
+func (e *encoder) encodeArray(b *messageBuilder, array cadence.Array) {
+	b.messageField(valueFieldArray, func(b *messageBuilder) {
+		if array.ArrayType != nil {
+			e.encodeTypeField(b, containerFieldType, array.ArrayType)
+		}
+		for _, element := range array.Values {
+			e.encodeValueField(b, containerFieldElements, element)
+		}
+	})
+}
+
+func (e *encoder) encodeDictionary(b *messageBuilder, dictionary cadence.Dictionary) {
+	b.messageField(valueFieldDictionary, func(b *messageBuilder) {
+		if dictionary.DictionaryType != nil {
+			e.encodeTypeField(b, containerFieldType, dictionary.DictionaryType)
+		}
+		for _, pair := range dictionary.Pairs {
+			b.messageField(containerFieldElements, func(b *messageBuilder) {
+				e.encodeValueField(b, keyValuePairFieldKey, pair.Key)
+				e.encodeValueField(b, keyValuePairFieldValue, pair.Value)
+			})
+		}
+	})
+}
+
+func (e *encoder) encodeInclusiveRange(b *messageBuilder, inclusiveRange *cadence.InclusiveRange) {
+	b.messageField(valueFieldInclusiveRange, func(b *messageBuilder) {
+		if inclusiveRange.InclusiveRangeType != nil {
+			e.encodeTypeField(b, inclusiveRangeFieldType, inclusiveRange.InclusiveRangeType)
+		}
+		e.encodeValueField(b, inclusiveRangeFieldStart, inclusiveRange.Start)
+		e.encodeValueField(b, inclusiveRangeFieldEnd, inclusiveRange.End)
+		e.encodeValueField(b, inclusiveRangeFieldStep, inclusiveRange.Step)
+	})
+}
+
+func (e *encoder) encodeComposite(
+	b *messageBuilder,
+	field uint64,
+	value cadence.Composite,
+	compositeType cadence.CompositeType,
+	attachments []cadence.Attachment,
+) {
+	fieldValues := getCompositeFieldValues(value)
+
+	b.messageField(field, func(b *messageBuilder) {
+		if compositeType != nil {
+			fieldTypes := getCompositeTypeFields(compositeType)
+			if len(fieldValues) != len(fieldTypes) {
+				panic(fmt.Errorf(
+					"%s field count (%d) does not match declared type (%d)",
+					compositeType.ID(),
+					len(fieldValues),
+					len(fieldTypes),
+				))
+			}
+
+			e.encodeTypeField(b, containerFieldType, compositeType)
+		}
+
+		for _, fieldValue := range fieldValues {
+			e.encodeValueField(b, containerFieldElements, fieldValue)
+		}
+
+		for _, attachment := range attachments {
+			e.encodeValueField(b, compositeFieldAttachments, attachment)
+		}
+	})
+}
+
+func (e *encoder) encodeCapability(b *messageBuilder, capability cadence.Capability) {
+	b.messageField(valueFieldCapability, func(b *messageBuilder) {
+		if capability.ID != 0 {
+			b.uint64Field(capabilityFieldID, uint64(capability.ID))
+		}
+		b.bytesField(capabilityFieldAddress, capability.Address.Bytes())
+		e.encodeTypeField(b, capabilityFieldBorrowType, capability.BorrowType)
+		if capability.DeprecatedPath != nil {
+			b.messageField(capabilityFieldDeprecatedPath, func(b *messageBuilder) {
+				encodePath(b, *capability.DeprecatedPath)
+			})
+		}
+	})
+}
+
+// encodeTypeField writes the given type as an embedded Type message, if it is not nil
+func (e *encoder) encodeTypeField(b *messageBuilder, field uint64, typ cadence.Type) {
+	if typ == nil {
+		return
+	}
+
+	b.messageField(field, func(b *messageBuilder) {
+		e.encodeType(b, typ)
+	})
+}
+
+func (e *encoder) encodeType(b *messageBuilder, typ cadence.Type) {
+	switch t := typ.(type) {
+	case cadence.PrimitiveType:
+		b.stringField(typeFieldPrimitive, t.ID())
+	case cadence.BytesType:
+		b.stringField(typeFieldPrimitive, t.ID())
+	case *cadence.OptionalType:
+		b.messageField(typeFieldOptional, func(b *messageBuilder) {
+			e.encodeTypeField(b, unaryTypeFieldType, t.Type)
+		})
+	case *cadence.VariableSizedArrayType:
+		b.messageField(typeFieldVariableSizedArray, func(b *messageBuilder) {
+			e.encodeTypeField(b, unaryTypeFieldType, t.ElementType)
+		})
+	case *cadence.ConstantSizedArrayType:
+		b.messageField(typeFieldConstantSizedArray, func(b *messageBuilder) {
+			e.encodeTypeField(b, unaryTypeFieldType, t.ElementType)
+			if t.Size != 0 {
+				b.uint64Field(constantSizedArrayTypeSize, uint64(t.Size))
+			}
+		})
+	case *cadence.DictionaryType:
+		b.messageField(typeFieldDictionary, func(b *messageBuilder) {
+			e.encodeTypeField(b, dictionaryTypeFieldKeyType, t.KeyType)
+			e.encodeTypeField(b, dictionaryTypeFieldElementType, t.ElementType)
+		})
+	case *cadence.InclusiveRangeType:
+		b.messageField(typeFieldInclusiveRange, func(b *messageBuilder) {
+			e.encodeTypeField(b, unaryTypeFieldType, t.ElementType)
+		})
+	case *cadence.CapabilityType:
+		b.messageField(typeFieldCapability, func(b *messageBuilder) {
+			e.encodeTypeField(b, unaryTypeFieldType, t.BorrowType)
+		})
+	case *cadence.ReferenceType:
+		b.messageField(typeFieldReference, func(b *messageBuilder) {
+			if t.Authorization != nil {
+				b.messageField(referenceTypeFieldAuthorization, func(b *messageBuilder) {
+					encodeAuthorization(b, t.Authorization)
+				})
+			}
+			e.encodeTypeField(b, referenceTypeFieldType, t.Type)
+		})
+	case *cadence.IntersectionType:
+		b.messageField(typeFieldIntersection, func(b *messageBuilder) {
+			for _, intersectedType := range t.Types {
+				e.encodeTypeField(b, intersectionTypeFieldTypes, intersectedType)
+			}
+		})
+	case *cadence.FunctionType:
+		b.messageField(typeFieldFunction, func(b *messageBuilder) {
+			e.encodeFunctionType(b, t)
+		})
+	case cadence.CompositeType, cadence.InterfaceType:
+		b.uint64Field(typeFieldDefinition, uint64(e.definitionIndex(t)))
+	case cadence.TypeID:
+		b.stringField(typeFieldTypeID, string(t))
+	default:
+		panic(fmt.Errorf("unsupported type: %T, %s", typ, typ))
+	}
+}
+
+// definitionIndex returns the index of the type definition for the given composite or interface type,
+// adding a new definition if the type was not encoded before
+func (e *encoder) definitionIndex(typ cadence.Type) int {
+	index, ok := e.definitionIndices[typ]
+	if !ok {
+		index = len(e.definitions)
+		e.definitions = append(e.definitions, typ)
+		e.definitionIndices[typ] = index
+	}
+	return index
+}
+
+func encodeAuthorization(b *messageBuilder, authorization cadence.Authorization) {
+	switch authorization := authorization.(type) {
+	case cadence.Unauthorized:
+		b.messageField(authorizationFieldUnauthorized, func(*messageBuilder) {})
+
+	case *cadence.EntitlementSetAuthorization:
+		var field uint64
+		switch authorization.Kind {
+		case cadence.Conjunction:
+			field = authorizationFieldEntitlementConjunctionSet
+		case cadence.Disjunction:
+			field = authorizationFieldEntitlementDisjunctionSet
+		default:
+			panic(fmt.Errorf("unsupported entitlement set kind: %d", authorization.Kind))
+		}
+
+		b.messageField(field, func(b *messageBuilder) {
+			for _, entitlement := range authorization.Entitlements {
+				b.stringField(entitlementSetFieldEntitlements, string(entitlement))
+			}
+		})
+
+	case cadence.EntitlementMapAuthorization:
+		b.stringField(authorizationFieldEntitlementMap, string(authorization.TypeID))
+
+	default:
+		panic(fmt.Errorf("unsupported authorization: %T", authorization))
+	}
+}
+
+func (e *encoder) encodeFunctionType(b *messageBuilder, functionType *cadence.FunctionType) {
+	switch functionType.Purity {
+	case cadence.FunctionPurityUnspecified:
+		break
+	case cadence.FunctionPurityView:
+		b.uint64Field(functionTypeFieldPurity, functionPurityView)
+	default:
+		panic(fmt.Errorf("unsupported function purity: %d", functionType.Purity))
+	}
+
+	for _, typeParameter := range functionType.TypeParameters {
+		b.messageField(functionTypeFieldTypeParameters, func(b *messageBuilder) {
+			if typeParameter.Name != "" {
+				b.stringField(typeParameterFieldName, typeParameter.Name)
+			}
+			e.encodeTypeField(b, typeParameterFieldTypeBound, typeParameter.TypeBound)
+		})
+	}
+
+	e.encodeParameters(b, functionTypeFieldParameters, functionType.Parameters)
+
+	e.encodeTypeField(b, functionTypeFieldReturnType, functionType.ReturnType)
+}
+
+func (e *encoder) encodeParameters(b *messageBuilder, field uint64, parameters []cadence.Parameter) {
+	for _, parameter := range parameters {
+		b.messageField(field, func(b *messageBuilder) {
+			if parameter.Label != "" {
+				b.stringField(parameterFieldLabel, parameter.Label)
+			}
+			if parameter.Identifier != "" {
+				b.stringField(parameterFieldIdentifier, parameter.Identifier)
+			}
+			e.encodeTypeField(b, parameterFieldType, parameter.Type)
+		})
+	}
+}
+
+func (e *encoder) encodeTypeDefinition(b *messageBuilder, typ cadence.Type) {
+	var kind uint64
+	var fields []cadence.Field
+	var initializers [][]cadence.Parameter
+	var rawType, baseType cadence.Type
+
+	switch t := typ.(type) {
+	case *cadence.StructType:
+		kind = typeDefinitionKindStruct
+		fields = getCompositeTypeFields(t)
+		initializers = t.Initializers
+	case *cadence.ResourceType:
+		kind = typeDefinitionKindResource
+		fields = getCompositeTypeFields(t)
+		initializers = t.Initializers
+	case *cadence.EventType:
+		kind = typeDefinitionKindEvent
+		fields = getCompositeTypeFields(t)
+		if t.Initializer != nil {
+			initializers = [][]cadence.Parameter{t.Initializer}
+		}
+	case *cadence.ContractType:
+		kind = typeDefinitionKindContract
+		fields = getCompositeTypeFields(t)
+		initializers = t.Initializers
+	case *cadence.EnumType:
+		kind = typeDefinitionKindEnum
+		fields = getCompositeTypeFields(t)
+		initializers = t.Initializers
+		rawType = t.RawType
+	case *cadence.AttachmentType:
+		kind = typeDefinitionKindAttachment
+		fields = getCompositeTypeFields(t)
+		initializers = t.Initializers
+		baseType = t.BaseType
+	case *cadence.StructInterfaceType:
+		kind = typeDefinitionKindStructInterface
+		fields = getInterfaceTypeFields(t)
+		initializers = t.Initializers
+	case *cadence.ResourceInterfaceType:
+		kind = typeDefinitionKindResourceInterface
+		fields = getInterfaceTypeFields(t)
+		initializers = t.Initializers
+	case *cadence.ContractInterfaceType:
+		kind = typeDefinitionKindContractInterface
+		fields = getInterfaceTypeFields(t)
+		initializers = t.Initializers
+	default:
+		panic(fmt.Errorf("unsupported type definition: %T, %s", typ, typ))
+	}
+
+	b.uint64Field(typeDefinitionFieldKind, kind)
+	b.stringField(typeDefinitionFieldTypeID, typ.ID())
+
+	for _, field := range fields {
+		b.messageField(typeDefinitionFieldFields, func(b *messageBuilder) {
+			if field.Identifier != "" {
+				b.stringField(fieldFieldIdentifier, field.Identifier)
+			}
+			e.encodeTypeField(b, fieldFieldType, field.Type)
+		})
+	}
+
+	for _, parameters := range initializers {
+		b.messageField(typeDefinitionFieldInitializers, func(b *messageBuilder) {
+			e.encodeParameters(b, initializerFieldParameters, parameters)
+		})
+	}
+
+	e.encodeTypeField(b, typeDefinitionFieldRawType, rawType)
+	e.encodeTypeField(b, typeDefinitionFieldBaseType, baseType)
+}
@@ -0,0 +1,261 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package proto implements a Protocol Buffers encoding of Cadence values and types,
+// as specified by the messages in cadence.proto.
+//
+// Unlike JSON-Cadence, the encoding preserves the static types of values,
+// e.g. the types of arrays, dictionaries and composite fields.
+package proto
+
+import (
+	_ "unsafe"
+
+	"github.com/onflow/cadence"
+)
+
+//go:linkname getCompositeFieldValues github.com/onflow/cadence.getCompositeFieldValues
+func getCompositeFieldValues(cadence.Composite) []cadence.Value
+
+//go:linkname getCompositeTypeFields github.com/onflow/cadence.getCompositeTypeFields
+func getCompositeTypeFields(cadence.CompositeType) []cadence.Field
+
+//go:linkname getInterfaceTypeFields github.com/onflow/cadence.getInterfaceTypeFields
+func getInterfaceTypeFields(cadence.InterfaceType) []cadence.Field
+
+//go:linkname setCompositeTypeFields github.com/onflow/cadence.setCompositeTypeFields
+func setCompositeTypeFields(cadence.CompositeType, []cadence.Field)
+
+//go:linkname setInterfaceTypeFields github.com/onflow/cadence.setInterfaceTypeFields
+func setInterfaceTypeFields(cadence.InterfaceType, []cadence.Field)
+
+// Field numbers of the messages in cadence.proto
+
+// ValueMessage, TypeMessage
+const (
+	messageFieldTypeDefinitions uint64 = 1
+	messageFieldValue           uint64 = 2
+	messageFieldType            uint64 = 2
+)
+
+// Value
+const (
+	valueFieldVoid           uint64 = 1
+	valueFieldOptional       uint64 = 2
+	valueFieldBool           uint64 = 3
+	valueFieldString         uint64 = 4
+	valueFieldCharacter      uint64 = 5
+	valueFieldAddress        uint64 = 6
+	valueFieldInt            uint64 = 7
+	valueFieldInt8           uint64 = 8
+	valueFieldInt16          uint64 = 9
+	valueFieldInt32          uint64 = 10
+	valueFieldInt64          uint64 = 11
+	valueFieldInt128         uint64 = 12
+	valueFieldInt256         uint64 = 13
+	valueFieldUInt           uint64 = 14
+	valueFieldUInt8          uint64 = 15
+	valueFieldUInt16         uint64 = 16
+	valueFieldUInt32         uint64 = 17
+	valueFieldUInt64         uint64 = 18
+	valueFieldUInt128        uint64 = 19
+	valueFieldUInt256        uint64 = 20
+	valueFieldWord8          uint64 = 21
+	valueFieldWord16         uint64 = 22
+	valueFieldWord32         uint64 = 23
+	valueFieldWord64         uint64 = 24
+	valueFieldWord128        uint64 = 25
+	valueFieldWord256        uint64 = 26
+	valueFieldFix64          uint64 = 27
+	valueFieldUFix64         uint64 = 28
+	valueFieldArray          uint64 = 29
+	valueFieldDictionary     uint64 = 30
+	valueFieldStruct         uint64 = 31
+	valueFieldResource       uint64 = 32
+	valueFieldEvent          uint64 = 33
+	valueFieldContract       uint64 = 34
+	valueFieldEnum           uint64 = 35
+	valueFieldAttachment     uint64 = 36
+	valueFieldInclusiveRange uint64 = 37
+	valueFieldPath           uint64 = 38
+	valueFieldTypeValue      uint64 = 39
+	valueFieldCapability     uint64 = 40
+	valueFieldFunction       uint64 = 41
+)
+
+// Optional
+const (
+	optionalFieldValue uint64 = 1
+)
+
+// BigInt
+const (
+	bigIntFieldNegative  uint64 = 1
+	bigIntFieldMagnitude uint64 = 2
+)
+
+// Array, Dictionary, Composite
+const (
+	containerFieldType        uint64 = 1
+	containerFieldElements    uint64 = 2
+	compositeFieldAttachments uint64 = 3
+)
+
+// KeyValuePair
+const (
+	keyValuePairFieldKey   uint64 = 1
+	keyValuePairFieldValue uint64 = 2
+)
+
+// InclusiveRange
+const (
+	inclusiveRangeFieldType  uint64 = 1
+	inclusiveRangeFieldStart uint64 = 2
+	inclusiveRangeFieldEnd   uint64 = 3
+	inclusiveRangeFieldStep  uint64 = 4
+)
+
+// Path
+const (
+	pathFieldDomain     uint64 = 1
+	pathFieldIdentifier uint64 = 2
+)
+
+// TypeValue, Function
+const (
+	typeValueFieldType uint64 = 1
+	functionFieldType  uint64 = 1
+)
+
+// Capability
+const (
+	capabilityFieldID             uint64 = 1
+	capabilityFieldAddress        uint64 = 2
+	capabilityFieldBorrowType     uint64 = 3
+	capabilityFieldDeprecatedPath uint64 = 4
+)
+
+// Type
+const (
+	typeFieldPrimitive          uint64 = 1
+	typeFieldOptional           uint64 = 2
+	typeFieldVariableSizedArray uint64 = 3
+	typeFieldConstantSizedArray uint64 = 4
+	typeFieldDictionary         uint64 = 5
+	typeFieldInclusiveRange     uint64 = 6
+	typeFieldReference          uint64 = 7
+	typeFieldIntersection       uint64 = 8
+	typeFieldCapability         uint64 = 9
+	typeFieldFunction           uint64 = 10
+	typeFieldDefinition         uint64 = 11
+	typeFieldTypeID             uint64 = 12
+)
+
+// OptionalType, VariableSizedArrayType, ConstantSizedArrayType, InclusiveRangeType, CapabilityType
+const (
+	unaryTypeFieldType         uint64 = 1
+	constantSizedArrayTypeSize uint64 = 2
+)
+
+// DictionaryType
+const (
+	dictionaryTypeFieldKeyType     uint64 = 1
+	dictionaryTypeFieldElementType uint64 = 2
+)
+
+// ReferenceType
+const (
+	referenceTypeFieldAuthorization uint64 = 1
+	referenceTypeFieldType          uint64 = 2
+)
+
+// Authorization
+const (
+	authorizationFieldUnauthorized              uint64 = 1
+	authorizationFieldEntitlementConjunctionSet uint64 = 2
+	authorizationFieldEntitlementDisjunctionSet uint64 = 3
+	authorizationFieldEntitlementMap            uint64 = 4
+)
+
+// EntitlementSet, IntersectionType
+const (
+	entitlementSetFieldEntitlements uint64 = 1
+	intersectionTypeFieldTypes      uint64 = 1
+)
+
+// FunctionType
+const (
+	functionTypeFieldPurity         uint64 = 1
+	functionTypeFieldTypeParameters uint64 = 2
+	functionTypeFieldParameters     uint64 = 3
+	functionTypeFieldReturnType     uint64 = 4
+)
+
+// FunctionPurity
+const (
+	functionPurityUnspecified uint64 = 0
+	functionPurityView        uint64 = 1
+)
+
+// TypeParameter
+const (
+	typeParameterFieldName      uint64 = 1
+	typeParameterFieldTypeBound uint64 = 2
+)
+
+// Parameter
+const (
+	parameterFieldLabel      uint64 = 1
+	parameterFieldIdentifier uint64 = 2
+	parameterFieldType       uint64 = 3
+)
+
+// TypeDefinition
+const (
+	typeDefinitionFieldKind         uint64 = 1
+	typeDefinitionFieldTypeID       uint64 = 2
+	typeDefinitionFieldFields       uint64 = 3
+	typeDefinitionFieldInitializers uint64 = 4
+	typeDefinitionFieldRawType      uint64 = 5
+	typeDefinitionFieldBaseType     uint64 = 6
+)
+
+// TypeDefinitionKind
+const (
+	typeDefinitionKindUnspecified       uint64 = 0
+	typeDefinitionKindStruct            uint64 = 1
+	typeDefinitionKindResource          uint64 = 2
+	typeDefinitionKindEvent             uint64 = 3
+	typeDefinitionKindContract          uint64 = 4
+	typeDefinitionKindEnum              uint64 = 5
+	typeDefinitionKindAttachment        uint64 = 6
+	typeDefinitionKindStructInterface   uint64 = 7
+	typeDefinitionKindResourceInterface uint64 = 8
+	typeDefinitionKindContractInterface uint64 = 9
+)
+
+// Field
+const (
+	fieldFieldIdentifier uint64 = 1
+	fieldFieldType       uint64 = 2
+)
+
+// Initializer
+const (
+	initializerFieldParameters uint64 = 1
+)
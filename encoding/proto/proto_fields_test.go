@@ -0,0 +1,223 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/common"
+)
+
+// parseProtoNumbers returns the numbers of the fields of the messages
+// and of the values of the enums in the given Protocol Buffers schema,
+// keyed by "<message or enum>.<field or value>"
+func parseProtoNumbers(t *testing.T, schema string) map[string]uint64 {
+	declarationRegexp := regexp.MustCompile(`^(?:message|enum) (\w+) \{`)
+	numberRegexp := regexp.MustCompile(`^\s*(?:repeated )?(?:\w+ )?(\w+) = (\d+);`)
+
+	numbers := map[string]uint64{}
+
+	var declaration string
+	for _, line := range regexp.MustCompile(`\r?\n`).Split(schema, -1) {
+		if match := declarationRegexp.FindStringSubmatch(line); match != nil {
+			declaration = match[1]
+			continue
+		}
+
+		match := numberRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		require.NotEmpty(t, declaration, line)
+
+		number, err := strconv.ParseUint(match[2], 10, 64)
+		require.NoError(t, err)
+
+		key := declaration + "." + match[1]
+		require.NotContains(t, numbers, key)
+		numbers[key] = number
+	}
+
+	return numbers
+}
+
+// TestFieldNumbers checks that the field numbers used by the encoder and decoder
+// match the numbers in the schema
+func TestFieldNumbers(t *testing.T) {
+
+	t.Parallel()
+
+	schema, err := os.ReadFile("cadence.proto")
+	require.NoError(t, err)
+
+	expected := map[string]uint64{
+		"ValueMessage.type_definitions": messageFieldTypeDefinitions,
+		"ValueMessage.value":            messageFieldValue,
+		"TypeMessage.type_definitions":  messageFieldTypeDefinitions,
+		"TypeMessage.type":              messageFieldType,
+
+		"Value.void":            valueFieldVoid,
+		"Value.optional":        valueFieldOptional,
+		"Value.bool":            valueFieldBool,
+		"Value.string":          valueFieldString,
+		"Value.character":       valueFieldCharacter,
+		"Value.address":         valueFieldAddress,
+		"Value.int":             valueFieldInt,
+		"Value.int8":            valueFieldInt8,
+		"Value.int16":           valueFieldInt16,
+		"Value.int32":           valueFieldInt32,
+		"Value.int64":           valueFieldInt64,
+		"Value.int128":          valueFieldInt128,
+		"Value.int256":          valueFieldInt256,
+		"Value.uint":            valueFieldUInt,
+		"Value.uint8":           valueFieldUInt8,
+		"Value.uint16":          valueFieldUInt16,
+		"Value.uint32":          valueFieldUInt32,
+		"Value.uint64":          valueFieldUInt64,
+		"Value.uint128":         valueFieldUInt128,
+		"Value.uint256":         valueFieldUInt256,
+		"Value.word8":           valueFieldWord8,
+		"Value.word16":          valueFieldWord16,
+		"Value.word32":          valueFieldWord32,
+		"Value.word64":          valueFieldWord64,
+		"Value.word128":         valueFieldWord128,
+		"Value.word256":         valueFieldWord256,
+		"Value.fix64":           valueFieldFix64,
+		"Value.ufix64":          valueFieldUFix64,
+		"Value.array":           valueFieldArray,
+		"Value.dictionary":      valueFieldDictionary,
+		"Value.struct":          valueFieldStruct,
+		"Value.resource":        valueFieldResource,
+		"Value.event":           valueFieldEvent,
+		"Value.contract":        valueFieldContract,
+		"Value.enum":            valueFieldEnum,
+		"Value.attachment":      valueFieldAttachment,
+		"Value.inclusive_range": valueFieldInclusiveRange,
+		"Value.path":            valueFieldPath,
+		"Value.type_value":      valueFieldTypeValue,
+		"Value.capability":      valueFieldCapability,
+		"Value.function":        valueFieldFunction,
+
+		"Optional.value": optionalFieldValue,
+
+		"BigInt.negative":  bigIntFieldNegative,
+		"BigInt.magnitude": bigIntFieldMagnitude,
+
+		"Array.type":                 containerFieldType,
+		"Array.values":               containerFieldElements,
+		"Dictionary.type":            containerFieldType,
+		"Dictionary.pairs":           containerFieldElements,
+		"Composite.type":             containerFieldType,
+		"Composite.fields":           containerFieldElements,
+		"Composite.attachments":      compositeFieldAttachments,
+		"KeyValuePair.key":           keyValuePairFieldKey,
+		"KeyValuePair.value":         keyValuePairFieldValue,
+		"InclusiveRange.type":        inclusiveRangeFieldType,
+		"InclusiveRange.start":       inclusiveRangeFieldStart,
+		"InclusiveRange.end":         inclusiveRangeFieldEnd,
+		"InclusiveRange.step":        inclusiveRangeFieldStep,
+		"Path.domain":                pathFieldDomain,
+		"Path.identifier":            pathFieldIdentifier,
+		"TypeValue.type":             typeValueFieldType,
+		"Function.type":              functionFieldType,
+		"Capability.id":              capabilityFieldID,
+		"Capability.address":         capabilityFieldAddress,
+		"Capability.borrow_type":     capabilityFieldBorrowType,
+		"Capability.deprecated_path": capabilityFieldDeprecatedPath,
+
+		"PathDomain.PATH_DOMAIN_UNKNOWN": uint64(common.PathDomainUnknown),
+		"PathDomain.PATH_DOMAIN_STORAGE": uint64(common.PathDomainStorage),
+		"PathDomain.PATH_DOMAIN_PRIVATE": uint64(common.PathDomainPrivate),
+		"PathDomain.PATH_DOMAIN_PUBLIC":  uint64(common.PathDomainPublic),
+
+		"Type.primitive":            typeFieldPrimitive,
+		"Type.optional":             typeFieldOptional,
+		"Type.variable_sized_array": typeFieldVariableSizedArray,
+		"Type.constant_sized_array": typeFieldConstantSizedArray,
+		"Type.dictionary":           typeFieldDictionary,
+		"Type.inclusive_range":      typeFieldInclusiveRange,
+		"Type.reference":            typeFieldReference,
+		"Type.intersection":         typeFieldIntersection,
+		"Type.capability":           typeFieldCapability,
+		"Type.function":             typeFieldFunction,
+		"Type.definition":           typeFieldDefinition,
+		"Type.type_id":              typeFieldTypeID,
+
+		"OptionalType.type":                   unaryTypeFieldType,
+		"VariableSizedArrayType.element_type": unaryTypeFieldType,
+		"ConstantSizedArrayType.element_type": unaryTypeFieldType,
+		"ConstantSizedArrayType.size":         constantSizedArrayTypeSize,
+		"InclusiveRangeType.element_type":     unaryTypeFieldType,
+		"CapabilityType.borrow_type":          unaryTypeFieldType,
+		"DictionaryType.key_type":             dictionaryTypeFieldKeyType,
+		"DictionaryType.element_type":         dictionaryTypeFieldElementType,
+		"ReferenceType.authorization":         referenceTypeFieldAuthorization,
+		"ReferenceType.type":                  referenceTypeFieldType,
+
+		"Authorization.unauthorized":                authorizationFieldUnauthorized,
+		"Authorization.entitlement_conjunction_set": authorizationFieldEntitlementConjunctionSet,
+		"Authorization.entitlement_disjunction_set": authorizationFieldEntitlementDisjunctionSet,
+		"Authorization.entitlement_map":             authorizationFieldEntitlementMap,
+		"EntitlementSet.entitlements":               entitlementSetFieldEntitlements,
+		"IntersectionType.types":                    intersectionTypeFieldTypes,
+
+		"FunctionPurity.FUNCTION_PURITY_UNSPECIFIED": functionPurityUnspecified,
+		"FunctionPurity.FUNCTION_PURITY_VIEW":        functionPurityView,
+
+		"FunctionType.purity":          functionTypeFieldPurity,
+		"FunctionType.type_parameters": functionTypeFieldTypeParameters,
+		"FunctionType.parameters":      functionTypeFieldParameters,
+		"FunctionType.return_type":     functionTypeFieldReturnType,
+		"TypeParameter.name":           typeParameterFieldName,
+		"TypeParameter.type_bound":     typeParameterFieldTypeBound,
+		"Parameter.label":              parameterFieldLabel,
+		"Parameter.identifier":         parameterFieldIdentifier,
+		"Parameter.type":               parameterFieldType,
+
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_UNSPECIFIED":        typeDefinitionKindUnspecified,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_STRUCT":             typeDefinitionKindStruct,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_RESOURCE":           typeDefinitionKindResource,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_EVENT":              typeDefinitionKindEvent,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_CONTRACT":           typeDefinitionKindContract,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_ENUM":               typeDefinitionKindEnum,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_ATTACHMENT":         typeDefinitionKindAttachment,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_STRUCT_INTERFACE":   typeDefinitionKindStructInterface,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_RESOURCE_INTERFACE": typeDefinitionKindResourceInterface,
+		"TypeDefinitionKind.TYPE_DEFINITION_KIND_CONTRACT_INTERFACE": typeDefinitionKindContractInterface,
+
+		"TypeDefinition.kind":         typeDefinitionFieldKind,
+		"TypeDefinition.type_id":      typeDefinitionFieldTypeID,
+		"TypeDefinition.fields":       typeDefinitionFieldFields,
+		"TypeDefinition.initializers": typeDefinitionFieldInitializers,
+		"TypeDefinition.raw_type":     typeDefinitionFieldRawType,
+		"TypeDefinition.base_type":    typeDefinitionFieldBaseType,
+		"Field.identifier":            fieldFieldIdentifier,
+		"Field.type":                  fieldFieldType,
+		"Initializer.parameters":      initializerFieldParameters,
+	}
+
+	assert.Equal(t, expected, parseProtoNumbers(t, string(schema)))
+}
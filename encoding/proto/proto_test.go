@@ -0,0 +1,589 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/encoding/proto"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+type testCase struct {
+	name  string
+	value cadence.Value
+}
+
+func newFooStructType() *cadence.StructType {
+	return cadence.NewStructType(
+		TestLocation,
+		"Foo",
+		[]cadence.Field{
+			{
+				Identifier: "a",
+				Type:       cadence.IntType,
+			},
+			{
+				Identifier: "b",
+				Type:       cadence.StringType,
+			},
+		},
+		nil,
+	)
+}
+
+func newFooStruct() cadence.Struct {
+	return cadence.NewStruct([]cadence.Value{
+		cadence.NewInt(1),
+		cadence.String("foo"),
+	}).WithType(newFooStructType())
+}
+
+func newBarResourceType() *cadence.ResourceType {
+	return cadence.NewResourceType(
+		TestLocation,
+		"Bar",
+		[]cadence.Field{
+			{
+				Identifier: "uuid",
+				Type:       cadence.UInt64Type,
+			},
+		},
+		[][]cadence.Parameter{
+			{
+				{
+					Label:      "_",
+					Identifier: "uuid",
+					Type:       cadence.UInt64Type,
+				},
+			},
+		},
+	)
+}
+
+func newRecursiveStructType() *cadence.StructType {
+	fields := []cadence.Field{
+		{
+			Identifier: "next",
+		},
+	}
+	recursiveType := cadence.NewStructType(
+		TestLocation,
+		"Node",
+		fields,
+		nil,
+	)
+	fields[0].Type = cadence.NewOptionalType(recursiveType)
+	return recursiveType
+}
+
+func newEnumType() *cadence.EnumType {
+	return cadence.NewEnumType(
+		TestLocation,
+		"Color",
+		cadence.UInt8Type,
+		[]cadence.Field{
+			{
+				Identifier: "rawValue",
+				Type:       cadence.UInt8Type,
+			},
+		},
+		nil,
+	)
+}
+
+func newEventType() *cadence.EventType {
+	return cadence.NewEventType(
+		TestLocation,
+		"Deposited",
+		[]cadence.Field{
+			{
+				Identifier: "amount",
+				Type:       cadence.UFix64Type,
+			},
+			{
+				Identifier: "to",
+				Type:       cadence.NewOptionalType(cadence.AddressType),
+			},
+		},
+		nil,
+	)
+}
+
+func mustUFix64(t *testing.T, s string) cadence.UFix64 {
+	value, err := cadence.NewUFix64(s)
+	require.NoError(t, err)
+	return value
+}
+
+func mustFix64(t *testing.T, s string) cadence.Fix64 {
+	value, err := cadence.NewFix64(s)
+	require.NoError(t, err)
+	return value
+}
+
+func mustPath(t *testing.T, domain common.PathDomain, identifier string) cadence.Path {
+	path, err := cadence.NewPath(domain, identifier)
+	require.NoError(t, err)
+	return path
+}
+
+func bigInt(s string) *big.Int {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big integer")
+	}
+	return value
+}
+
+// exchangeableValues returns values which are supported by all of JSON-CDC, CCF and Protocol Buffers
+func exchangeableValues(t *testing.T) []testCase {
+
+	address := cadence.BytesToAddress([]byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8})
+
+	return []testCase{
+		{"Void", cadence.NewVoid()},
+		{"Optional nil", cadence.NewOptional(nil)},
+		{"Optional", cadence.NewOptional(cadence.NewInt(42))},
+		{"Bool", cadence.NewBool(true)},
+		{"String", cadence.String("foo ✓")},
+		{"empty String", cadence.String("")},
+		{"Character", cadence.Character("a")},
+		{"Address", address},
+		{"Int", cadence.NewInt(-42)},
+		{"Int big", cadence.NewIntFromBig(bigInt("-1234567890123456789012345678901234567890"))},
+		{"Int zero", cadence.NewInt(0)},
+		{"Int8", cadence.NewInt8(-128)},
+		{"Int16", cadence.NewInt16(-32768)},
+		{"Int32", cadence.NewInt32(-2147483648)},
+		{"Int64", cadence.NewInt64(-9223372036854775808)},
+		{"Int128", cadence.Int128{Value: bigInt("-170141183460469231731687303715884105728")}},
+		{"Int256", cadence.Int256{Value: bigInt("57896044618658097711785492504343953926634992332820282019728792003956564819967")}},
+		{"UInt", cadence.UInt{Value: bigInt("1234567890123456789012345678901234567890")}},
+		{"UInt8", cadence.NewUInt8(255)},
+		{"UInt16", cadence.NewUInt16(65535)},
+		{"UInt32", cadence.NewUInt32(4294967295)},
+		{"UInt64", cadence.NewUInt64(18446744073709551615)},
+		{"UInt128", cadence.UInt128{Value: bigInt("340282366920938463463374607431768211455")}},
+		{"UInt256", cadence.UInt256{Value: bigInt("115792089237316195423570985008687907853269984665640564039457584007913129639935")}},
+		{"Word8", cadence.NewWord8(255)},
+		{"Word16", cadence.NewWord16(65535)},
+		{"Word32", cadence.NewWord32(4294967295)},
+		{"Word64", cadence.NewWord64(18446744073709551615)},
+		{"Word128", cadence.Word128{Value: bigInt("340282366920938463463374607431768211455")}},
+		{"Word256", cadence.Word256{Value: bigInt("115792089237316195423570985008687907853269984665640564039457584007913129639935")}},
+		{"Fix64", mustFix64(t, "-12.34")},
+		{"UFix64", mustUFix64(t, "12.34")},
+		{
+			"Array",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+			}).WithType(cadence.NewVariableSizedArrayType(cadence.IntType)),
+		},
+		{
+			"constant-sized Array",
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.NewInt(2),
+			}).WithType(cadence.NewConstantSizedArrayType(2, cadence.AnyStructType)),
+		},
+		{
+			"Dictionary",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("a"),
+					Value: cadence.NewUInt8(1),
+				},
+				{
+					Key:   cadence.String("b"),
+					Value: cadence.NewUInt8(2),
+				},
+			}).WithType(cadence.NewDictionaryType(cadence.StringType, cadence.UInt8Type)),
+		},
+		{"Struct", newFooStruct()},
+		{
+			"Resource",
+			cadence.NewResource([]cadence.Value{
+				cadence.NewUInt64(1),
+			}).WithType(newBarResourceType()),
+		},
+		{
+			"recursive Struct",
+			func() cadence.Value {
+				nodeType := newRecursiveStructType()
+				return cadence.NewStruct([]cadence.Value{
+					cadence.NewOptional(
+						cadence.NewStruct([]cadence.Value{
+							cadence.NewOptional(nil),
+						}).WithType(nodeType),
+					),
+				}).WithType(nodeType)
+			}(),
+		},
+		{
+			"Event",
+			cadence.NewEvent([]cadence.Value{
+				mustUFix64(t, "1.5"),
+				cadence.NewOptional(address),
+			}).WithType(newEventType()),
+		},
+		{
+			"Enum",
+			cadence.NewEnum([]cadence.Value{
+				cadence.NewUInt8(1),
+			}).WithType(newEnumType()),
+		},
+		{
+			"InclusiveRange",
+			cadence.NewInclusiveRange(
+				cadence.NewInt(1),
+				cadence.NewInt(10),
+				cadence.NewInt(2),
+			).WithType(cadence.NewInclusiveRangeType(cadence.IntType)),
+		},
+		{"Path", mustPath(t, common.PathDomainStorage, "foo")},
+		{
+			"TypeValue",
+			cadence.NewTypeValue(
+				cadence.NewReferenceType(
+					cadence.NewEntitlementSetAuthorization(
+						nil,
+						[]common.TypeID{"S.test.E", "S.test.F"},
+						cadence.Conjunction,
+					),
+					newFooStructType(),
+				),
+			),
+		},
+		{
+			"Capability",
+			cadence.NewCapability(
+				cadence.NewUInt64(3),
+				address,
+				cadence.NewReferenceType(cadence.UnauthorizedAccess, cadence.IntType),
+			),
+		},
+	}
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+
+	t.Parallel()
+
+	for _, test := range exchangeableValues(t) {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := proto.Encode(test.value)
+			require.NoError(t, err)
+
+			decoded, err := proto.Decode(nil, encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.value, decoded)
+		})
+	}
+}
+
+// TestConformance checks that values exchanged in other formats are preserved
+// when they are converted to Protocol Buffers and back
+func TestConformance(t *testing.T) {
+
+	t.Parallel()
+
+	viaProto := func(t *testing.T, value cadence.Value) cadence.Value {
+		encoded, err := proto.Encode(value)
+		require.NoError(t, err)
+
+		decoded, err := proto.Decode(nil, encoded)
+		require.NoError(t, err)
+
+		return decoded
+	}
+
+	for _, test := range exchangeableValues(t) {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("JSON-CDC", func(t *testing.T) {
+				t.Parallel()
+
+				expected, err := json.Encode(test.value)
+				require.NoError(t, err)
+
+				decoded, err := json.Decode(nil, expected)
+				require.NoError(t, err)
+
+				actual, err := json.Encode(viaProto(t, decoded))
+				require.NoError(t, err)
+
+				assert.JSONEq(t, string(expected), string(actual))
+			})
+
+			t.Run("CCF", func(t *testing.T) {
+				t.Parallel()
+
+				expected, err := ccf.Encode(test.value)
+				require.NoError(t, err)
+
+				decoded, err := ccf.Decode(nil, expected)
+				require.NoError(t, err)
+
+				actual, err := ccf.Encode(viaProto(t, decoded))
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, actual)
+			})
+		})
+	}
+}
+
+func TestEncodeAndDecodeAttachments(t *testing.T) {
+
+	t.Parallel()
+
+	attachmentType := cadence.NewAttachmentType(
+		TestLocation,
+		"A",
+		newFooStructType(),
+		[]cadence.Field{
+			{
+				Identifier: "x",
+				Type:       cadence.BoolType,
+			},
+		},
+		nil,
+	)
+
+	value := newFooStruct().WithAttachments([]cadence.Attachment{
+		cadence.NewAttachment([]cadence.Value{
+			cadence.NewBool(true),
+		}).WithType(attachmentType),
+	})
+
+	encoded, err := proto.Encode(value)
+	require.NoError(t, err)
+
+	decoded, err := proto.Decode(nil, encoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, value, decoded)
+}
+
+func TestEncodeAndDecodeType(t *testing.T) {
+
+	t.Parallel()
+
+	types := []cadence.Type{
+		cadence.IntType,
+		cadence.TheBytesType,
+		cadence.NewOptionalType(cadence.StringType),
+		cadence.NewVariableSizedArrayType(cadence.AnyResourceType),
+		cadence.NewConstantSizedArrayType(3, cadence.UInt8Type),
+		cadence.NewDictionaryType(cadence.StringType, cadence.NewOptionalType(cadence.IntType)),
+		cadence.NewInclusiveRangeType(cadence.UInt64Type),
+		cadence.NewCapabilityType(nil),
+		cadence.NewCapabilityType(
+			cadence.NewReferenceType(
+				cadence.NewEntitlementSetAuthorization(
+					nil,
+					[]common.TypeID{"S.test.E"},
+					cadence.Disjunction,
+				),
+				cadence.AnyStructType,
+			),
+		),
+		cadence.NewReferenceType(
+			cadence.EntitlementMapAuthorization{
+				TypeID: "S.test.M",
+			},
+			cadence.AnyStructType,
+		),
+		cadence.NewIntersectionType([]cadence.Type{
+			cadence.NewStructInterfaceType(TestLocation, "I", nil, nil),
+		}),
+		cadence.NewFunctionType(
+			cadence.FunctionPurityView,
+			[]cadence.TypeParameter{
+				{
+					Name:      "T",
+					TypeBound: cadence.AnyStructType,
+				},
+			},
+			[]cadence.Parameter{
+				{
+					Label:      "_",
+					Identifier: "x",
+					Type:       cadence.IntType,
+				},
+			},
+			cadence.StringType,
+		),
+		newFooStructType(),
+		newRecursiveStructType(),
+		newEnumType(),
+		newEventType(),
+		cadence.NewContractInterfaceType(TestLocation, "C", nil, nil),
+		cadence.TypeID("S.test.Unknown"),
+	}
+
+	for _, typ := range types {
+		typ := typ
+
+		t.Run(typ.ID(), func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := proto.EncodeType(typ)
+			require.NoError(t, err)
+
+			decoded, err := proto.DecodeType(nil, encoded)
+			require.NoError(t, err)
+
+			// Some types cache their type ID
+			assert.Equal(t, typ.ID(), decoded.ID())
+
+			assert.Equal(t, typ, decoded)
+		})
+	}
+}
+
+func TestEncodeFunction(t *testing.T) {
+
+	t.Parallel()
+
+	value := cadence.NewFunction(
+		cadence.NewFunctionType(
+			cadence.FunctionPurityUnspecified,
+			nil,
+			nil,
+			cadence.VoidType,
+		),
+	)
+
+	encoded, err := proto.Encode(value)
+	require.NoError(t, err)
+
+	decoded, err := proto.Decode(nil, encoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, value, decoded)
+}
+
+func TestEncodeUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := proto.Encode(nil)
+	require.Error(t, err)
+	assert.Equal(t, "failed to encode value: unsupported value: nil", err.Error())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, encoded []byte, expectedError string) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := proto.Decode(nil, encoded)
+			require.Error(t, err)
+			assert.Equal(t, expectedError, err.Error())
+		})
+	}
+
+	test(
+		"missing value",
+		nil,
+		"failed to decode Cadence value from protobuf: missing value",
+	)
+
+	test(
+		"truncated",
+		// ValueMessage.value with length 5, but only 1 byte
+		[]byte{0x12, 0x05, 0x18},
+		"failed to decode Cadence value from protobuf: invalid length for field 2: 5 exceeds remaining 1 bytes",
+	)
+
+	test(
+		"out of range",
+		// ValueMessage.value { uint8: 256 }
+		[]byte{0x12, 0x03, 0x78, 0x80, 0x02},
+		"failed to decode Cadence value from protobuf: invalid UInt8: 256 is out of range",
+	)
+
+	test(
+		"invalid address",
+		// ValueMessage.value { address: 0x01 }
+		[]byte{0x12, 0x03, 0x32, 0x01, 0x01},
+		"failed to decode Cadence value from protobuf: invalid address: expected 8 bytes, got 1",
+	)
+
+	test(
+		"invalid type definition index",
+		// ValueMessage.value { type_value: { type: { definition: 0 } } }
+		[]byte{0x12, 0x07, 0xba, 0x02, 0x04, 0x0a, 0x02, 0x58, 0x00},
+		"failed to decode Cadence value from protobuf: invalid type definition index 0: only 0 type definitions",
+	)
+}
+
+type testMemoryGauge struct {
+	meter map[common.MemoryKind]uint64
+}
+
+var _ common.MemoryGauge = &testMemoryGauge{}
+
+func (g *testMemoryGauge) MeterMemory(usage common.MemoryUsage) error {
+	g.meter[usage.Kind] += usage.Amount
+	return nil
+}
+
+func TestDecodeMetered(t *testing.T) {
+
+	t.Parallel()
+
+	encoded, err := proto.Encode(newFooStruct())
+	require.NoError(t, err)
+
+	gauge := &testMemoryGauge{
+		meter: map[common.MemoryKind]uint64{},
+	}
+
+	_, err = proto.Decode(gauge, encoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), gauge.meter[common.MemoryKindCadenceStructValueBase])
+	assert.Equal(t, uint64(2), gauge.meter[common.MemoryKindCadenceField])
+	assert.Equal(t, uint64(1), gauge.meter[common.MemoryKindCadenceStructType])
+	assert.NotZero(t, gauge.meter[common.MemoryKindCadenceIntValue])
+	assert.NotZero(t, gauge.meter[common.MemoryKindCadenceStringValue])
+}
@@ -0,0 +1,198 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"encoding/binary"
+
+	"github.com/onflow/cadence/errors"
+)
+
+// Protocol Buffers wire types,
+// see https://protobuf.dev/programming-guides/encoding/#structure
+type wireType uint64
+
+const (
+	wireTypeVarint  wireType = 0
+	wireTypeFixed64 wireType = 1
+	wireTypeBytes   wireType = 2
+	wireTypeFixed32 wireType = 5
+)
+
+// messageBuilder appends the fields of a Protocol Buffers message.
+//
+// Fields are always written, callers omit fields which have the default value,
+// except for members of a oneof, which must always be written.
+type messageBuilder struct {
+	buf []byte
+}
+
+func (b *messageBuilder) appendTag(field uint64, wireType wireType) {
+	b.buf = binary.AppendUvarint(b.buf, field<<3|uint64(wireType))
+}
+
+func (b *messageBuilder) uint64Field(field uint64, value uint64) {
+	b.appendTag(field, wireTypeVarint)
+	b.buf = binary.AppendUvarint(b.buf, value)
+}
+
+// sint64Field writes a ZigZag-encoded signed integer
+func (b *messageBuilder) sint64Field(field uint64, value int64) {
+	b.uint64Field(field, uint64(value<<1)^uint64(value>>63))
+}
+
+func (b *messageBuilder) boolField(field uint64, value bool) {
+	var v uint64
+	if value {
+		v = 1
+	}
+	b.uint64Field(field, v)
+}
+
+func (b *messageBuilder) bytesField(field uint64, value []byte) {
+	b.appendTag(field, wireTypeBytes)
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(value)))
+	b.buf = append(b.buf, value...)
+}
+
+func (b *messageBuilder) stringField(field uint64, value string) {
+	b.appendTag(field, wireTypeBytes)
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(value)))
+	b.buf = append(b.buf, value...)
+}
+
+// messageField writes an embedded message, built by the given function
+func (b *messageBuilder) messageField(field uint64, build func(b *messageBuilder)) {
+	var nested messageBuilder
+	build(&nested)
+	b.bytesField(field, nested.buf)
+}
+
+// messageReader iterates over the fields of a Protocol Buffers message.
+//
+// Malformed input results in a panic with a user error,
+// which is recovered by the decoding functions.
+type messageReader struct {
+	buf      []byte
+	field    uint64
+	wireType wireType
+}
+
+func newMessageReader(buf []byte) *messageReader {
+	return &messageReader{buf: buf}
+}
+
+// next advances to the next field, and returns false if the end of the message is reached
+func (r *messageReader) next() bool {
+	if len(r.buf) == 0 {
+		return false
+	}
+
+	tag := r.readUvarint()
+	r.field = tag >> 3
+	r.wireType = wireType(tag & 0x7)
+
+	if r.field == 0 {
+		panic(errors.NewDefaultUserError("invalid field number 0"))
+	}
+
+	return true
+}
+
+func (r *messageReader) readUvarint() uint64 {
+	value, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		panic(errors.NewDefaultUserError("invalid varint"))
+	}
+	r.buf = r.buf[n:]
+	return value
+}
+
+func (r *messageReader) expectWireType(expected wireType) {
+	if r.wireType != expected {
+		panic(errors.NewDefaultUserError(
+			"invalid wire type for field %d: expected %d, got %d",
+			r.field,
+			expected,
+			r.wireType,
+		))
+	}
+}
+
+func (r *messageReader) uint64() uint64 {
+	r.expectWireType(wireTypeVarint)
+	return r.readUvarint()
+}
+
+func (r *messageReader) sint64() int64 {
+	value := r.uint64()
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func (r *messageReader) bool() bool {
+	return r.uint64() != 0
+}
+
+func (r *messageReader) bytes() []byte {
+	r.expectWireType(wireTypeBytes)
+	length := r.readUvarint()
+	if length > uint64(len(r.buf)) {
+		panic(errors.NewDefaultUserError(
+			"invalid length for field %d: %d exceeds remaining %d bytes",
+			r.field,
+			length,
+			len(r.buf),
+		))
+	}
+	value := r.buf[:length]
+	r.buf = r.buf[length:]
+	return value
+}
+
+func (r *messageReader) string() string {
+	return string(r.bytes())
+}
+
+// skip skips the value of an unknown field
+func (r *messageReader) skip() {
+	var length int
+	switch r.wireType {
+	case wireTypeVarint:
+		r.readUvarint()
+		return
+	case wireTypeBytes:
+		r.bytes()
+		return
+	case wireTypeFixed64:
+		length = 8
+	case wireTypeFixed32:
+		length = 4
+	default:
+		panic(errors.NewDefaultUserError(
+			"unsupported wire type for field %d: %d",
+			r.field,
+			r.wireType,
+		))
+	}
+
+	if length > len(r.buf) {
+		panic(errors.NewDefaultUserError("unexpected end of message"))
+	}
+	r.buf = r.buf[length:]
+}
@@ -0,0 +1,261 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/interpreter"
+	cadenceParser "github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/values"
+)
+
+var catalogs = map[string][]errors.ErrorCodeInfo{
+	"parser":      cadenceParser.ErrorCodes,
+	"sema":        sema.ErrorCodes,
+	"interpreter": interpreter.ErrorCodes,
+}
+
+var catalogPrefixes = map[string]string{
+	"parser":      "E1",
+	"sema":        "E2",
+	"interpreter": "E3",
+}
+
+const goldenPath = "testdata/error_codes.golden"
+
+var errorCodePattern = regexp.MustCompile(`^E[0-9]{4}$`)
+
+func catalogLines() []string {
+	var lines []string
+	for _, infos := range catalogs { //nolint:maprange
+		for _, info := range infos {
+			lines = append(lines, fmt.Sprintf("%s %s", info.Code, info.Name))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestErrorCodesUnique(t *testing.T) {
+
+	t.Parallel()
+
+	seen := map[errors.ErrorCode]string{}
+
+	for name, infos := range catalogs { //nolint:maprange
+		prefix := catalogPrefixes[name]
+
+		for _, info := range infos {
+			code := info.Code
+
+			assert.Regexp(t, errorCodePattern, code)
+			assert.True(t,
+				strings.HasPrefix(string(code), prefix),
+				"error code %s of %s must have prefix %s",
+				code,
+				info.Name,
+				prefix,
+			)
+			assert.NotEmpty(t, info.Explanation, info.Name)
+
+			if other, ok := seen[code]; ok {
+				assert.Fail(t,
+					"duplicate error code",
+					"%s is used for both %s and %s",
+					code,
+					other,
+					info.Name,
+				)
+			}
+			seen[code] = info.Name
+		}
+	}
+}
+
+// TestErrorCodesStable ensures that error codes never change.
+//
+// When a new error is added, append its code to the golden file.
+// Existing lines must never be changed.
+func TestErrorCodesStable(t *testing.T) {
+
+	t.Parallel()
+
+	golden, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+
+	expected := strings.Split(strings.TrimSpace(string(golden)), "\n")
+	sort.Strings(expected)
+
+	require.Equal(t, expected, catalogLines())
+}
+
+// userErrorTypes returns the names of the user error types declared in the package in the given directory,
+// and the names of the types which have an error code.
+// Parent errors, which only group other errors, are not included
+func userErrorTypes(t *testing.T, dir string) (userErrors []string, hasErrorCode map[string]bool) {
+
+	fileSet := token.NewFileSet()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	isUserError := map[string]bool{}
+	isParentError := map[string]bool{}
+	hasErrorCode = map[string]bool{}
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fileSet, path, nil, parser.SkipObjectResolution)
+		require.NoError(t, err)
+
+		for _, declaration := range file.Decls {
+			functionDeclaration, ok := declaration.(*ast.FuncDecl)
+			if !ok || functionDeclaration.Recv == nil {
+				continue
+			}
+
+			receiverType := functionDeclaration.Recv.List[0].Type
+			if starExpr, ok := receiverType.(*ast.StarExpr); ok {
+				receiverType = starExpr.X
+			}
+			receiverIdentifier, ok := receiverType.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			typeName := receiverIdentifier.Name
+
+			switch functionDeclaration.Name.Name {
+			case "IsUserError":
+				isUserError[typeName] = true
+			case "ChildErrors":
+				isParentError[typeName] = true
+			case "ErrorCode":
+				hasErrorCode[typeName] = true
+			}
+		}
+	}
+
+	for typeName := range isUserError { //nolint:maprange
+		if isParentError[typeName] {
+			continue
+		}
+		userErrors = append(userErrors, typeName)
+	}
+	sort.Strings(userErrors)
+
+	return userErrors, hasErrorCode
+}
+
+func TestErrorCodesComplete(t *testing.T) {
+
+	t.Parallel()
+
+	// wrapperErrors are errors which only wrap another error,
+	// and provide the error code of the wrapped error
+	wrapperErrors := map[string]bool{
+		"PositionedError": true,
+	}
+
+	directories := map[string][]string{
+		"parser":      {"../parser", "../parser/lexer"},
+		"sema":        {"../sema"},
+		"interpreter": {"../interpreter"},
+	}
+
+	for name, dirs := range directories { //nolint:maprange
+
+		var allUserErrors []string
+
+		for _, dir := range dirs {
+			userErrors, hasErrorCode := userErrorTypes(t, dir)
+			for _, userError := range userErrors {
+				if wrapperErrors[userError] {
+					continue
+				}
+				assert.True(t,
+					hasErrorCode[userError],
+					"user error %s in %s has no error code",
+					userError,
+					dir,
+				)
+				allUserErrors = append(allUserErrors, userError)
+			}
+		}
+
+		var catalogNames []string
+		for _, info := range catalogs[name] {
+			catalogNames = append(catalogNames, info.Name)
+		}
+
+		assert.ElementsMatch(t, allUserErrors, catalogNames, name)
+	}
+}
+
+func TestValueErrorCodes(t *testing.T) {
+
+	t.Parallel()
+
+	// The value errors have the same error codes as the corresponding interpreter errors
+
+	interpreterCodes := map[string]errors.ErrorCode{}
+	for _, info := range interpreter.ErrorCodes {
+		interpreterCodes[info.Name] = info.Code
+	}
+
+	valueErrors := []error{
+		values.InvalidOperandsError{},
+		values.UnderflowError{},
+		values.OverflowError{},
+		values.NegativeShiftError{},
+		values.DivisionByZeroError{},
+	}
+
+	for _, err := range valueErrors {
+		name := fmt.Sprintf("%T", err)
+		name = name[strings.LastIndex(name, ".")+1:]
+
+		code, ok := errors.GetErrorCode(err)
+		require.True(t, ok, name)
+		assert.Equal(t, interpreterCodes[name], code, name)
+	}
+
+	userErrors, hasErrorCode := userErrorTypes(t, "../values")
+	assert.Len(t, userErrors, len(valueErrors))
+	for _, userError := range userErrors {
+		assert.True(t, hasErrorCode[userError], userError)
+	}
+}
@@ -121,6 +121,46 @@ type SuggestedFix[T any] struct {
 	TextEdits []T
 }
 
+// ErrorCode
+
+// ErrorCode is a stable identifier of a kind of user error, e.g. `E2001`.
+//
+// Error codes never change once they have been assigned, and are never reused.
+// Parser errors have codes in the range E1xxx, checker errors E2xxx, and interpreter errors E3xxx.
+type ErrorCode string
+
+// HasErrorCode is an interface for errors that provide a stable error code
+type HasErrorCode interface {
+	ErrorCode() ErrorCode
+}
+
+// ErrorCodeInfo describes an error code in the error code catalog
+type ErrorCodeInfo struct {
+	Code        ErrorCode
+	Name        string
+	Explanation string
+	// Example is an optional program which produces the error
+	Example string
+}
+
+// GetErrorCode returns the error code of the given error, if any.
+// Wrapped errors are unwrapped until an error which provides an error code is found.
+func GetErrorCode(err error) (ErrorCode, bool) {
+	for err != nil {
+		if hasErrorCode, ok := err.(HasErrorCode); ok {
+			return hasErrorCode.ErrorCode(), true
+		}
+
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = wrapper.Unwrap()
+	}
+
+	return "", false
+}
+
 // MemoryMeteringError indicates a memory limit has reached and should end
 // the Cadence parsing, checking, or interpretation.
 type MemoryMeteringError struct {
@@ -31,8 +31,10 @@ import (
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
+	"github.com/onflow/cadence/test_utils"
 	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
 )
@@ -382,6 +384,10 @@ func (t *testAccountHandler) RemoveAccountContractCode(location common.AddressLo
 	return t.removeAccountContractCode(location)
 }
 
+func (*testAccountHandler) ParserConfig() parser.Config {
+	return parser.Config{}
+}
+
 func (t *testAccountHandler) RecordContractRemoval(location common.AddressLocation) {
 	if t.recordContractRemoval == nil {
 		panic(errors.NewUnexpectedError("unexpected call to RecordContractRemoval"))
@@ -600,72 +606,7 @@ func testAccountWithErrorHandlerWithCompiler(
 	var invokable Invokable
 	var storage interpreter.Storage
 
-	if compilerEnabled && *compile {
-
-		// TODO: Uncomment once the compiler branch is merged to master.
-		//vmConfig := &vm.Config{
-		//	BuiltinGlobalsProvider: func() map[string]*vm.Variable {
-		//		funcs := vm.NativeFunctions()
-		//		variable := &interpreter.SimpleVariable{}
-		//		variable.InitializeWithValue(accountValueDeclaration.Value)
-		//		funcs[accountValueDeclaration.Name] = variable
-		//		return funcs
-		//	},
-		//}
-		//
-		//programs := map[common.Location]*CompiledProgram{}
-		//parseAndCheckOptions := &ParseAndCheckOptions{
-		//	Config: &sema.Config{
-		//		LocationHandler: NewSingleIdentifierLocationResolver(t),
-		//		BaseValueActivationHandler: func(_ common.Location) *sema.VariableActivation {
-		//			return baseValueActivation
-		//		},
-		//	},
-		//}
-		//
-		//vmInstance := compilerUtils.CompileAndPrepareToInvoke(
-		//	t,
-		//	code,
-		//	compilerUtils.CompilerAndVMOptions{
-		//		ParseCheckAndCompileOptions: ParseCheckAndCompileOptions{
-		//			ParseAndCheckOptions: parseAndCheckOptions,
-		//			CompilerConfig: &compiler.Config{
-		//				BuiltinGlobalsProvider: func() map[string]*compiler.Global {
-		//					builtins := compiler.NativeFunctions()
-		//					for _, valueDeclaration := range valueDeclarations {
-		//						name := valueDeclaration.Name
-		//						builtins[name] = &compiler.Global{
-		//							Name: name,
-		//						}
-		//					}
-		//					return builtins
-		//				},
-		//			},
-		//		},
-		//		VMConfig: vmConfig,
-		//		Programs: programs,
-		//	},
-		//)
-		//
-		//var uuid uint64
-		//uuidHandler := func() (uint64, error) {
-		//	uuid++
-		//	return uuid, nil
-		//}
-		//
-		//vmConfig.WithInterpreterConfig(&interpreter.Config{
-		//	UUIDHandler: uuidHandler,
-		//})
-		//
-		//elaboration := programs[parseAndCheckOptions.Location].DesugaredElaboration
-		//
-		//invokable = test_utils.NewVMInvokable(vmInstance, elaboration)
-		//storage = vmConfig.Storage()
-
-		// Not supported for now
-		panic(errors.NewUnreachableError())
-	} else {
-
+	{
 		inter, err := parseCheckAndInterpretWithOptions(t,
 			code,
 			ParseCheckAndInterpretOptions{
@@ -686,6 +627,10 @@ func testAccountWithErrorHandlerWithCompiler(
 
 		invokable = inter
 		storage = inter.Storage()
+
+		if compilerEnabled && *compile {
+			invokable = test_utils.NewVMInvokable(t, inter)
+		}
 	}
 
 	getAccountValues := func() map[storageKey]interpreter.Value {
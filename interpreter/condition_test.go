@@ -31,6 +31,7 @@ import (
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
+	"github.com/onflow/cadence/test_utils"
 	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
 	. "github.com/onflow/cadence/test_utils/sema_utils"
@@ -49,6 +50,8 @@ func TestInterpretFunctionPreTestCondition(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke(
 		"test",
 		interpreter.NewUnmeteredIntValueFromInt64(42),
@@ -117,6 +120,8 @@ func TestInterpretFunctionPostTestCondition(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke(
 		"test",
 		interpreter.NewUnmeteredIntValueFromInt64(42),
@@ -185,6 +190,8 @@ func TestInterpretFunctionWithResultAndPostTestConditionWithResult(t *testing.T)
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke(
 		"test",
 		interpreter.NewUnmeteredIntValueFromInt64(42),
@@ -304,6 +311,8 @@ func TestInterpretFunctionWithoutResultAndPostTestConditionWithResult(t *testing
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	value, err := inter.Invoke("test")
 	require.NoError(t, err)
 
@@ -365,6 +374,8 @@ func TestInterpretFunctionPostTestConditionWithBefore(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	value, err := inter.Invoke("test")
 	require.NoError(t, err)
 
@@ -417,6 +428,8 @@ func TestInterpretFunctionPostConditionWithBeforeFailingPreTestCondition(t *test
 
 	t.Parallel()
 
+	skipIfCompiled(t, "emit conditions are not compiled yet")
+
 	inter, getEvents, err := parseCheckAndPrepareWithEvents(t, `
       event Foo(x: Int)
 
@@ -451,6 +464,8 @@ func TestInterpretFunctionPostConditionWithBeforeFailingPostTestCondition(t *tes
 
 	t.Parallel()
 
+	skipIfCompiled(t, "emit conditions are not compiled yet")
+
 	inter, getEvents, err := parseCheckAndPrepareWithEvents(t, `
       event Foo(x: Int)
 
@@ -495,6 +510,8 @@ func TestInterpretFunctionPostConditionWithMessageUsingStringLiteral(t *testing.
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke(
 		"test",
 		interpreter.NewUnmeteredIntValueFromInt64(42),
@@ -533,6 +550,8 @@ func TestInterpretFunctionPostConditionWithMessageUsingResult(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke(
 		"test",
 		interpreter.NewUnmeteredIntValueFromInt64(42),
@@ -570,6 +589,8 @@ func TestInterpretFunctionPostConditionWithMessageUsingBefore(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue("parameter value"))
 
 	assertConditionErrorWithMessage(
@@ -593,6 +614,8 @@ func TestInterpretFunctionPostConditionWithMessageUsingParameter(t *testing.T) {
       }
     `)
 
+	test_utils.RequireCompiled(t, inter, "test")
+
 	_, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue("parameter value"))
 
 	assertConditionErrorWithMessage(
@@ -607,6 +630,8 @@ func TestInterpretInterfaceFunctionUseWithPreCondition(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	for _, compositeKind := range common.CompositeKindsWithFieldsAndFunctions {
 
 		if !compositeKind.SupportsInterfaces() {
@@ -972,6 +997,8 @@ func TestInterpretResourceInterfaceInitializerPreConditions(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	newInterpreter := func(t *testing.T) (invokable Invokable, getEvents func() []testEvent) {
 		var err error
 		invokable, getEvents, err = parseCheckAndPrepareWithEvents(t, `
@@ -1040,6 +1067,8 @@ func TestInterpretFunctionPostConditionInInterface(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	newInterpreter := func(t *testing.T) (inter Invokable, getEvents func() []testEvent) {
 		var err error
 		inter, getEvents, err = parseCheckAndPrepareWithEvents(t, `
@@ -1125,6 +1154,8 @@ func TestInterpretFunctionPostConditionWithBeforeInInterface(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	newInterpreter := func(t *testing.T) (inter Invokable, getEvents func() []testEvent) {
 		var err error
 		inter, getEvents, err = parseCheckAndPrepareWithEvents(t, `
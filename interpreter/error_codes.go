@@ -0,0 +1,434 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/errors"
+)
+
+// Error codes of the interpreter errors.
+//
+// Error codes are stable: Once assigned, an error code must never change,
+// and must not be reused, even if the error is removed.
+// New errors must be assigned the next unassigned error code.
+const (
+	ErrorCodeNotDeclared                      errors.ErrorCode = "E3001"
+	ErrorCodeNotInvokable                     errors.ErrorCode = "E3002"
+	ErrorCodeArgumentCount                    errors.ErrorCode = "E3003"
+	ErrorCodeTransactionNotDeclared           errors.ErrorCode = "E3004"
+	ErrorCodeCondition                        errors.ErrorCode = "E3005"
+	ErrorCodeRedeclaration                    errors.ErrorCode = "E3006"
+	ErrorCodeDereference                      errors.ErrorCode = "E3007"
+	ErrorCodeOverflow                         errors.ErrorCode = "E3008"
+	ErrorCodeUnderflow                        errors.ErrorCode = "E3009"
+	ErrorCodeNegativeShift                    errors.ErrorCode = "E3010"
+	ErrorCodeDivisionByZero                   errors.ErrorCode = "E3011"
+	ErrorCodeDestroyedResource                errors.ErrorCode = "E3012"
+	ErrorCodeForceNil                         errors.ErrorCode = "E3013"
+	ErrorCodeForceCastTypeMismatch            errors.ErrorCode = "E3014"
+	ErrorCodeTypeMismatch                     errors.ErrorCode = "E3015"
+	ErrorCodeInvalidMemberReference           errors.ErrorCode = "E3016"
+	ErrorCodeInvalidPathDomain                errors.ErrorCode = "E3017"
+	ErrorCodeOverwrite                        errors.ErrorCode = "E3018"
+	ErrorCodeArrayIndexOutOfBounds            errors.ErrorCode = "E3019"
+	ErrorCodeArraySliceIndices                errors.ErrorCode = "E3020"
+	ErrorCodeInvalidSliceIndex                errors.ErrorCode = "E3021"
+	ErrorCodeStringIndexOutOfBounds           errors.ErrorCode = "E3022"
+	ErrorCodeStringSliceIndices               errors.ErrorCode = "E3023"
+	ErrorCodeEventEmissionUnavailable         errors.ErrorCode = "E3024"
+	ErrorCodeUUIDUnavailable                  errors.ErrorCode = "E3025"
+	ErrorCodeTypeLoading                      errors.ErrorCode = "E3026"
+	ErrorCodeUseBeforeInitialization          errors.ErrorCode = "E3027"
+	ErrorCodeContainerMutation                errors.ErrorCode = "E3028"
+	ErrorCodeNonStorableValue                 errors.ErrorCode = "E3029"
+	ErrorCodeNonStorableStaticType            errors.ErrorCode = "E3030"
+	ErrorCodeInterfaceMissingLocation         errors.ErrorCode = "E3031"
+	ErrorCodeInvalidOperands                  errors.ErrorCode = "E3032"
+	ErrorCodeInvalidPublicKey                 errors.ErrorCode = "E3033"
+	ErrorCodeNonTransferableValue             errors.ErrorCode = "E3034"
+	ErrorCodeDuplicateKeyInResourceDictionary errors.ErrorCode = "E3035"
+	ErrorCodeStorageMutatedDuringIteration    errors.ErrorCode = "E3036"
+	ErrorCodeContainerMutatedDuringIteration  errors.ErrorCode = "E3037"
+	ErrorCodeInvalidHexByte                   errors.ErrorCode = "E3038"
+	ErrorCodeInvalidHexLength                 errors.ErrorCode = "E3039"
+	ErrorCodeInvalidatedResourceReference     errors.ErrorCode = "E3040"
+	ErrorCodeDuplicateAttachment              errors.ErrorCode = "E3041"
+	ErrorCodeAttachmentIterationMutation      errors.ErrorCode = "E3042"
+	ErrorCodeRecursiveTransfer                errors.ErrorCode = "E3043"
+	ErrorCodeCapabilityAddressPublishing      errors.ErrorCode = "E3044"
+	ErrorCodeEntitledCapabilityPublishing     errors.ErrorCode = "E3045"
+	ErrorCodeNestedReference                  errors.ErrorCode = "E3046"
+	ErrorCodeNonOptionalReferenceToNil        errors.ErrorCode = "E3047"
+	ErrorCodeInclusiveRangeConstruction       errors.ErrorCode = "E3048"
+	ErrorCodeInvalidCapabilityIssueType       errors.ErrorCode = "E3049"
+	ErrorCodeResourceLoss                     errors.ErrorCode = "E3050"
+	ErrorCodeReferencedValueChanged           errors.ErrorCode = "E3051"
+	ErrorCodeGetCapability                    errors.ErrorCode = "E3052"
+)
+
+// ErrorCodes is the catalog of the error codes of the interpreter errors
+var ErrorCodes = []errors.ErrorCodeInfo{
+	{
+		Code:        ErrorCodeNotDeclared,
+		Name:        "NotDeclaredError",
+		Explanation: "A name is used which is not declared in the current scope, for example the entry point of a program.",
+	},
+	{
+		Code:        ErrorCodeNotInvokable,
+		Name:        "NotInvokableError",
+		Explanation: "A value is invoked, but it is not a function.",
+	},
+	{
+		Code:        ErrorCodeArgumentCount,
+		Name:        "ArgumentCountError",
+		Explanation: "A function is invoked with a different number of arguments than it has parameters, for example the entry point of a program.",
+	},
+	{
+		Code:        ErrorCodeTransactionNotDeclared,
+		Name:        "TransactionNotDeclaredError",
+		Explanation: "A transaction is executed, but the program does not declare a transaction at the given index.",
+	},
+	{
+		Code:        ErrorCodeCondition,
+		Name:        "ConditionError",
+		Explanation: "A pre-condition or post-condition of a function failed, or an assertion failed.",
+		Example: `
+access(all) fun test(_ x: Int) {
+    pre {
+        x > 0: "x must be positive"
+    }
+}
+
+access(all) fun main() {
+    test(0)
+}
+`,
+	},
+	{
+		Code:        ErrorCodeRedeclaration,
+		Name:        "RedeclarationError",
+		Explanation: "A value is declared with the same name as a value which is already declared.",
+	},
+	{
+		Code:        ErrorCodeDereference,
+		Name:        "DereferenceError",
+		Explanation: "A reference is dereferenced, but the referenced value is no longer available.",
+	},
+	{
+		Code:        ErrorCodeOverflow,
+		Name:        "OverflowError",
+		Explanation: "The result of an arithmetic operation is larger than the maximum value of its type.",
+		Example: `
+access(all) fun main() {
+    let x: UInt8 = 255
+    let y = x + 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeUnderflow,
+		Name:        "UnderflowError",
+		Explanation: "The result of an arithmetic operation is smaller than the minimum value of its type.",
+		Example: `
+access(all) fun main() {
+    let x: UInt8 = 0
+    let y = x - 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeNegativeShift,
+		Name:        "NegativeShiftError",
+		Explanation: "A value is shifted by a negative number of bits.",
+		Example: `
+access(all) fun main() {
+    let x = 1
+    let y = x << -1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeDivisionByZero,
+		Name:        "DivisionByZeroError",
+		Explanation: "A value is divided by zero, or the remainder of a division by zero is computed.",
+		Example: `
+access(all) fun main() {
+    let x = 0
+    let y = 1 / x
+}
+`,
+	},
+	{
+		Code:        ErrorCodeDestroyedResource,
+		Name:        "DestroyedResourceError",
+		Explanation: "A resource is used after it has been destroyed.",
+	},
+	{
+		Code:        ErrorCodeForceNil,
+		Name:        "ForceNilError",
+		Explanation: "An optional value is force-unwrapped with `!`, but it is `nil`.",
+		Example: `
+access(all) fun main() {
+    let x: Int? = nil
+    let y = x!
+}
+`,
+	},
+	{
+		Code:        ErrorCodeForceCastTypeMismatch,
+		Name:        "ForceCastTypeMismatchError",
+		Explanation: "A value is force-cast with `as!` to a type which is not a supertype of the value's type.",
+		Example: `
+access(all) fun main() {
+    let x: AnyStruct = 1
+    let y = x as! String
+}
+`,
+	},
+	{
+		Code:        ErrorCodeTypeMismatch,
+		Name:        "TypeMismatchError",
+		Explanation: "A value does not have the type expected at run-time.",
+	},
+	{
+		Code:        ErrorCodeInvalidMemberReference,
+		Name:        "InvalidMemberReferenceError",
+		Explanation: "A reference to a member is created, but the member's value does not have the expected type.",
+	},
+	{
+		Code:        ErrorCodeInvalidPathDomain,
+		Name:        "InvalidPathDomainError",
+		Explanation: "A path has a domain which is not valid for the operation, for example a public path is used to access storage.",
+	},
+	{
+		Code:        ErrorCodeOverwrite,
+		Name:        "OverwriteError",
+		Explanation: "A value is saved to a storage path which already stores a value. Load or remove the stored value first.",
+	},
+	{
+		Code:        ErrorCodeArrayIndexOutOfBounds,
+		Name:        "ArrayIndexOutOfBoundsError",
+		Explanation: "An array is indexed with an index which is negative, or not smaller than the size of the array.",
+		Example: `
+access(all) fun main() {
+    let xs = [1, 2, 3]
+    let x = xs[3]
+}
+`,
+	},
+	{
+		Code:        ErrorCodeArraySliceIndices,
+		Name:        "ArraySliceIndicesError",
+		Explanation: "An array is sliced with indices which are out of the bounds of the array.",
+		Example: `
+access(all) fun main() {
+    let xs = [1, 2, 3]
+    let ys = xs.slice(from: 1, upTo: 4)
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidSliceIndex,
+		Name:        "InvalidSliceIndexError",
+		Explanation: "An array or string is sliced with a start index which is greater than the end index.",
+		Example: `
+access(all) fun main() {
+    let xs = [1, 2, 3]
+    let ys = xs.slice(from: 2, upTo: 1)
+}
+`,
+	},
+	{
+		Code:        ErrorCodeStringIndexOutOfBounds,
+		Name:        "StringIndexOutOfBoundsError",
+		Explanation: "A string is indexed with an index which is negative, or not smaller than the length of the string.",
+		Example: `
+access(all) fun main() {
+    let s = "abc"
+    let c = s[3]
+}
+`,
+	},
+	{
+		Code:        ErrorCodeStringSliceIndices,
+		Name:        "StringSliceIndicesError",
+		Explanation: "A string is sliced with indices which are out of the bounds of the string.",
+		Example: `
+access(all) fun main() {
+    let s = "abc"
+    let t = s.slice(from: 1, upTo: 4)
+}
+`,
+	},
+	{
+		Code:        ErrorCodeEventEmissionUnavailable,
+		Name:        "EventEmissionUnavailableError",
+		Explanation: "An event is emitted, but event emission is not supported by the environment.",
+	},
+	{
+		Code:        ErrorCodeUUIDUnavailable,
+		Name:        "UUIDUnavailableError",
+		Explanation: "The UUID of a resource is accessed, but UUIDs are not supported by the environment.",
+	},
+	{
+		Code:        ErrorCodeTypeLoading,
+		Name:        "TypeLoadingError",
+		Explanation: "A type could not be loaded, for example because the contract which declares the type was removed or updated.",
+	},
+	{
+		Code:        ErrorCodeUseBeforeInitialization,
+		Name:        "UseBeforeInitializationError",
+		Explanation: "A field is used before it has been initialized.",
+	},
+	{
+		Code:        ErrorCodeContainerMutation,
+		Name:        "ContainerMutationError",
+		Explanation: "An element is inserted into a container, like an array or dictionary, but its type is not a subtype of the element type of the container.",
+	},
+	{
+		Code:        ErrorCodeNonStorableValue,
+		Name:        "NonStorableValueError",
+		Explanation: "A value is stored which is not storable, for example a function.",
+	},
+	{
+		Code:        ErrorCodeNonStorableStaticType,
+		Name:        "NonStorableStaticTypeError",
+		Explanation: "A value is stored which has a type that is not storable.",
+	},
+	{
+		Code:        ErrorCodeInterfaceMissingLocation,
+		Name:        "InterfaceMissingLocationError",
+		Explanation: "An interface is looked up, but its location is unknown.",
+	},
+	{
+		Code:        ErrorCodeInvalidOperands,
+		Name:        "InvalidOperandsError",
+		Explanation: "An operation is applied to values which have types that are not supported by the operation.",
+	},
+	{
+		Code:        ErrorCodeInvalidPublicKey,
+		Name:        "InvalidPublicKeyError",
+		Explanation: "A public key is not valid for its signature algorithm.",
+	},
+	{
+		Code:        ErrorCodeNonTransferableValue,
+		Name:        "NonTransferableValueError",
+		Explanation: "A value is transferred, for example moved or copied, but it is not transferable.",
+	},
+	{
+		Code:        ErrorCodeDuplicateKeyInResourceDictionary,
+		Name:        "DuplicateKeyInResourceDictionaryError",
+		Explanation: "A resource dictionary literal contains the same key more than once, which would lose a resource.",
+	},
+	{
+		Code:        ErrorCodeStorageMutatedDuringIteration,
+		Name:        "StorageMutatedDuringIterationError",
+		Explanation: "Storage is iterated, and the iteration is continued after the storage was mutated.",
+	},
+	{
+		Code:        ErrorCodeContainerMutatedDuringIteration,
+		Name:        "ContainerMutatedDuringIterationError",
+		Explanation: "A resource container, like an array of resources, is mutated while it is iterated.",
+	},
+	{
+		Code:        ErrorCodeInvalidHexByte,
+		Name:        "InvalidHexByteError",
+		Explanation: "A hexadecimal string contains a character which is not a hexadecimal digit.",
+		Example: `
+access(all) fun main() {
+    let bytes = "zz".decodeHex()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidHexLength,
+		Name:        "InvalidHexLengthError",
+		Explanation: "A hexadecimal string has an odd length.",
+		Example: `
+access(all) fun main() {
+    let bytes = "abc".decodeHex()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidatedResourceReference,
+		Name:        "InvalidatedResourceReferenceError",
+		Explanation: "A reference to a resource is used after the resource has been moved or destroyed.",
+	},
+	{
+		Code:        ErrorCodeDuplicateAttachment,
+		Name:        "DuplicateAttachmentError",
+		Explanation: "An attachment is attached to a value which already has an attachment of the same type.",
+	},
+	{
+		Code:        ErrorCodeAttachmentIterationMutation,
+		Name:        "AttachmentIterationMutationError",
+		Explanation: "The attachments of a value are mutated while they are iterated.",
+	},
+	{
+		Code:        ErrorCodeRecursiveTransfer,
+		Name:        "RecursiveTransferError",
+		Explanation: "A value is transferred into itself, for example a resource is moved into one of its own fields.",
+	},
+	{
+		Code:        ErrorCodeCapabilityAddressPublishing,
+		Name:        "CapabilityAddressPublishingError",
+		Explanation: "A capability is published in an account, but the capability was issued by a different account.",
+	},
+	{
+		Code:        ErrorCodeEntitledCapabilityPublishing,
+		Name:        "EntitledCapabilityPublishingError",
+		Explanation: "A capability with an authorized reference borrow type is published, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeNestedReference,
+		Name:        "NestedReferenceError",
+		Explanation: "A reference is created to a value which is already a reference.",
+	},
+	{
+		Code:        ErrorCodeNonOptionalReferenceToNil,
+		Name:        "NonOptionalReferenceToNilError",
+		Explanation: "A non-optional reference is created to the value `nil`.",
+	},
+	{
+		Code:        ErrorCodeInclusiveRangeConstruction,
+		Name:        "InclusiveRangeConstructionError",
+		Explanation: "An inclusive range is constructed with an invalid step, for example a step of zero, or a step which does not lead from the start to the end.",
+	},
+	{
+		Code:        ErrorCodeInvalidCapabilityIssueType,
+		Name:        "InvalidCapabilityIssueTypeError",
+		Explanation: "A capability is issued with a type which is not a reference type.",
+	},
+	{
+		Code:        ErrorCodeResourceLoss,
+		Name:        "ResourceLossError",
+		Explanation: "A resource is assigned to a value which already stores a non-nil resource, which would lose the resource.",
+	},
+	{
+		Code:        ErrorCodeReferencedValueChanged,
+		Name:        "ReferencedValueChangedError",
+		Explanation: "A reference is used after the referenced value has been changed, for example after a storage value was replaced.",
+	},
+	{
+		Code:        ErrorCodeGetCapability,
+		Name:        "GetCapabilityError",
+		Explanation: "A capability could not be retrieved, for example because the capability controller was removed.",
+	},
+}
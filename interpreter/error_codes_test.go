@@ -0,0 +1,61 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/errors"
+	. "github.com/onflow/cadence/interpreter"
+)
+
+func TestInterpretErrorCodeExamples(t *testing.T) {
+
+	t.Parallel()
+
+	for _, info := range ErrorCodes {
+		if info.Example == "" {
+			continue
+		}
+
+		info := info
+
+		t.Run(string(info.Code), func(t *testing.T) {
+
+			t.Parallel()
+
+			if info.Code == ErrorCodeCondition {
+				// Compiled conditions fail like an invocation of the panic function,
+				// see assertConditionError
+				skipIfCompiled(t, "compiled conditions have no error code")
+			}
+
+			inter := parseCheckAndPrepare(t, info.Example)
+
+			_, err := inter.Invoke("main")
+			require.Error(t, err)
+
+			code, ok := errors.GetErrorCode(err)
+			require.True(t, ok, "missing error code: %T", err)
+			require.Equal(t, info.Code, code, info.Name)
+		})
+	}
+}
@@ -151,6 +151,10 @@ var _ errors.SecondaryError = NotDeclaredError{}
 
 func (NotDeclaredError) IsUserError() {}
 
+func (NotDeclaredError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotDeclared
+}
+
 func (e NotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find %s in this scope: `%s`",
@@ -173,6 +177,10 @@ var _ errors.UserError = NotInvokableError{}
 
 func (NotInvokableError) IsUserError() {}
 
+func (NotInvokableError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotInvokable
+}
+
 func (e NotInvokableError) Error() string {
 	return fmt.Sprintf("cannot call value: %#+v", e.Value)
 }
@@ -188,6 +196,10 @@ var _ errors.UserError = ArgumentCountError{}
 
 func (ArgumentCountError) IsUserError() {}
 
+func (ArgumentCountError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeArgumentCount
+}
+
 func (e ArgumentCountError) Error() string {
 	return fmt.Sprintf(
 		"incorrect number of arguments: expected %d, got %d",
@@ -206,6 +218,10 @@ var _ errors.UserError = TransactionNotDeclaredError{}
 
 func (TransactionNotDeclaredError) IsUserError() {}
 
+func (TransactionNotDeclaredError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTransactionNotDeclared
+}
+
 func (e TransactionNotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find transaction with index %d in this scope",
@@ -226,6 +242,10 @@ var _ HasLocationRange = &ConditionError{}
 
 func (*ConditionError) IsUserError() {}
 
+func (*ConditionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCondition
+}
+
 func (e *ConditionError) Error() string {
 	if e.Message == "" {
 		return fmt.Sprintf("%s failed", e.ConditionKind.Name())
@@ -247,6 +267,10 @@ var _ errors.UserError = RedeclarationError{}
 
 func (RedeclarationError) IsUserError() {}
 
+func (RedeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeRedeclaration
+}
+
 func (e RedeclarationError) Error() string {
 	return fmt.Sprintf("cannot redeclare: `%s` is already declared", e.Name)
 }
@@ -266,6 +290,10 @@ var _ HasLocationRange = &DereferenceError{}
 
 func (*DereferenceError) IsUserError() {}
 
+func (*DereferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDereference
+}
+
 func (e *DereferenceError) Error() string {
 	return "dereference failed"
 }
@@ -301,6 +329,10 @@ var _ HasLocationRange = &OverflowError{}
 
 func (*OverflowError) IsUserError() {}
 
+func (*OverflowError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeOverflow
+}
+
 func (e *OverflowError) Error() string {
 	return "overflow"
 }
@@ -320,6 +352,10 @@ var _ HasLocationRange = &UnderflowError{}
 
 func (*UnderflowError) IsUserError() {}
 
+func (*UnderflowError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnderflow
+}
+
 func (e *UnderflowError) Error() string {
 	return "underflow"
 }
@@ -339,6 +375,10 @@ var _ HasLocationRange = &NegativeShiftError{}
 
 func (*NegativeShiftError) IsUserError() {}
 
+func (*NegativeShiftError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNegativeShift
+}
+
 func (e *NegativeShiftError) Error() string {
 	return "negative shift"
 }
@@ -358,6 +398,10 @@ var _ HasLocationRange = &DivisionByZeroError{}
 
 func (*DivisionByZeroError) IsUserError() {}
 
+func (*DivisionByZeroError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDivisionByZero
+}
+
 func (e *DivisionByZeroError) Error() string {
 	return "division by zero"
 }
@@ -398,6 +442,10 @@ var _ HasLocationRange = &DestroyedResourceError{}
 
 func (*DestroyedResourceError) IsUserError() {}
 
+func (*DestroyedResourceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDestroyedResource
+}
+
 func (e *DestroyedResourceError) Error() string {
 	return "resource was destroyed and cannot be used anymore"
 }
@@ -416,6 +464,10 @@ var _ HasLocationRange = &ForceNilError{}
 
 func (*ForceNilError) IsUserError() {}
 
+func (*ForceNilError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeForceNil
+}
+
 func (e *ForceNilError) Error() string {
 	return "unexpectedly found nil while forcing an Optional value"
 }
@@ -436,6 +488,10 @@ var _ HasLocationRange = &ForceCastTypeMismatchError{}
 
 func (*ForceCastTypeMismatchError) IsUserError() {}
 
+func (*ForceCastTypeMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeForceCastTypeMismatch
+}
+
 func (e *ForceCastTypeMismatchError) Error() string {
 	expected, actual := sema.ErrorMessageExpectedActualTypes(
 		e.ExpectedType,
@@ -465,6 +521,10 @@ var _ HasLocationRange = &TypeMismatchError{}
 
 func (*TypeMismatchError) IsUserError() {}
 
+func (*TypeMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeMismatch
+}
+
 func (e *TypeMismatchError) Error() string {
 	expected, actual := sema.ErrorMessageExpectedActualTypes(
 		e.ExpectedType,
@@ -494,6 +554,10 @@ var _ HasLocationRange = &InvalidMemberReferenceError{}
 
 func (*InvalidMemberReferenceError) IsUserError() {}
 
+func (*InvalidMemberReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMemberReference
+}
+
 func (e *InvalidMemberReferenceError) Error() string {
 	expected, actual := sema.ErrorMessageExpectedActualTypes(
 		e.ExpectedType,
@@ -524,6 +588,10 @@ var _ HasLocationRange = &InvalidPathDomainError{}
 
 func (*InvalidPathDomainError) IsUserError() {}
 
+func (*InvalidPathDomainError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidPathDomain
+}
+
 func (e *InvalidPathDomainError) Error() string {
 	return "invalid path domain"
 }
@@ -559,6 +627,10 @@ var _ HasLocationRange = &OverwriteError{}
 
 func (*OverwriteError) IsUserError() {}
 
+func (*OverwriteError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeOverwrite
+}
+
 func (e *OverwriteError) Error() string {
 	return fmt.Sprintf(
 		"failed to save object: path %s in account %s already stores an object",
@@ -583,6 +655,10 @@ var _ HasLocationRange = &ArrayIndexOutOfBoundsError{}
 
 func (*ArrayIndexOutOfBoundsError) IsUserError() {}
 
+func (*ArrayIndexOutOfBoundsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeArrayIndexOutOfBounds
+}
+
 func (e *ArrayIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"array index out of bounds: %d, but size is %d",
@@ -608,6 +684,10 @@ var _ HasLocationRange = &ArraySliceIndicesError{}
 
 func (*ArraySliceIndicesError) IsUserError() {}
 
+func (*ArraySliceIndicesError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeArraySliceIndices
+}
+
 func (e *ArraySliceIndicesError) Error() string {
 	return fmt.Sprintf(
 		"slice indices [%d:%d] are out of bounds (size %d)",
@@ -632,6 +712,10 @@ var _ HasLocationRange = &InvalidSliceIndexError{}
 
 func (*InvalidSliceIndexError) IsUserError() {}
 
+func (*InvalidSliceIndexError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidSliceIndex
+}
+
 func (e *InvalidSliceIndexError) Error() string {
 	return fmt.Sprintf("invalid slice index: %d > %d", e.FromIndex, e.UpToIndex)
 }
@@ -652,6 +736,10 @@ var _ HasLocationRange = &StringIndexOutOfBoundsError{}
 
 func (*StringIndexOutOfBoundsError) IsUserError() {}
 
+func (*StringIndexOutOfBoundsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeStringIndexOutOfBounds
+}
+
 func (e *StringIndexOutOfBoundsError) Error() string {
 	return fmt.Sprintf(
 		"string index out of bounds: %d, but length is %d",
@@ -677,6 +765,10 @@ var _ HasLocationRange = &StringSliceIndicesError{}
 
 func (*StringSliceIndicesError) IsUserError() {}
 
+func (*StringSliceIndicesError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeStringSliceIndices
+}
+
 func (e *StringSliceIndicesError) Error() string {
 	return fmt.Sprintf(
 		"string slice indices [%d:%d] are out of bounds (length %d)",
@@ -698,6 +790,10 @@ var _ HasLocationRange = &EventEmissionUnavailableError{}
 
 func (*EventEmissionUnavailableError) IsUserError() {}
 
+func (*EventEmissionUnavailableError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeEventEmissionUnavailable
+}
+
 func (e *EventEmissionUnavailableError) Error() string {
 	return "cannot emit event: event emission is unavailable in this configuration of Cadence"
 }
@@ -716,6 +812,10 @@ var _ HasLocationRange = &UUIDUnavailableError{}
 
 func (*UUIDUnavailableError) IsUserError() {}
 
+func (*UUIDUnavailableError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUUIDUnavailable
+}
+
 func (e *UUIDUnavailableError) Error() string {
 	return "cannot get UUID: UUID access is unavailable in this configuration of Cadence"
 }
@@ -733,6 +833,10 @@ var _ errors.UserError = TypeLoadingError{}
 
 func (TypeLoadingError) IsUserError() {}
 
+func (TypeLoadingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeLoading
+}
+
 func (e TypeLoadingError) Error() string {
 	return fmt.Sprintf("failed to load type: %s", e.TypeID)
 }
@@ -748,6 +852,10 @@ var _ HasLocationRange = &UseBeforeInitializationError{}
 
 func (*UseBeforeInitializationError) IsUserError() {}
 
+func (*UseBeforeInitializationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUseBeforeInitialization
+}
+
 func (e *UseBeforeInitializationError) Error() string {
 	return fmt.Sprintf("member `%s` is used before it has been initialized", e.Name)
 }
@@ -870,6 +978,10 @@ var _ HasLocationRange = &ContainerMutationError{}
 
 func (*ContainerMutationError) IsUserError() {}
 
+func (*ContainerMutationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeContainerMutation
+}
+
 func (e *ContainerMutationError) Error() string {
 	return fmt.Sprintf(
 		"invalid container update: expected a subtype of `%s`, found `%s`",
@@ -891,6 +1003,10 @@ var _ errors.UserError = NonStorableValueError{}
 
 func (NonStorableValueError) IsUserError() {}
 
+func (NonStorableValueError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonStorableValue
+}
+
 func (e NonStorableValueError) Error() string {
 	return "cannot store non-storable value"
 }
@@ -904,6 +1020,10 @@ var _ errors.UserError = NonStorableStaticTypeError{}
 
 func (NonStorableStaticTypeError) IsUserError() {}
 
+func (NonStorableStaticTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonStorableStaticType
+}
+
 func (e NonStorableStaticTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot store non-storable type: `%s`",
@@ -921,6 +1041,10 @@ var _ errors.UserError = InterfaceMissingLocationError{}
 
 func (InterfaceMissingLocationError) IsUserError() {}
 
+func (InterfaceMissingLocationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInterfaceMissingLocation
+}
+
 func (e InterfaceMissingLocationError) Error() string {
 	return fmt.Sprintf(
 		"tried to look up interface %s without a location",
@@ -942,6 +1066,10 @@ var _ HasLocationRange = &InvalidOperandsError{}
 
 func (*InvalidOperandsError) IsUserError() {}
 
+func (*InvalidOperandsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidOperands
+}
+
 func (e *InvalidOperandsError) Error() string {
 	var op string
 	if e.Operation == ast.OperationUnknown {
@@ -974,6 +1102,10 @@ var _ HasLocationRange = &InvalidPublicKeyError{}
 
 func (*InvalidPublicKeyError) IsUserError() {}
 
+func (*InvalidPublicKeyError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidPublicKey
+}
+
 func (e *InvalidPublicKeyError) Error() string {
 	return fmt.Sprintf("invalid public key: %s, err: %s", e.PublicKey, e.Err)
 }
@@ -995,6 +1127,10 @@ var _ errors.UserError = &NonTransferableValueError{}
 
 func (*NonTransferableValueError) IsUserError() {}
 
+func (*NonTransferableValueError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonTransferableValue
+}
+
 func (e *NonTransferableValueError) Error() string {
 	return "cannot transfer non-transferable value"
 }
@@ -1009,6 +1145,10 @@ var _ HasLocationRange = &DuplicateKeyInResourceDictionaryError{}
 
 func (*DuplicateKeyInResourceDictionaryError) IsUserError() {}
 
+func (*DuplicateKeyInResourceDictionaryError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDuplicateKeyInResourceDictionary
+}
+
 func (e *DuplicateKeyInResourceDictionaryError) Error() string {
 	return "duplicate key in resource dictionary"
 }
@@ -1027,6 +1167,10 @@ var _ HasLocationRange = &StorageMutatedDuringIterationError{}
 
 func (StorageMutatedDuringIterationError) IsUserError() {}
 
+func (StorageMutatedDuringIterationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeStorageMutatedDuringIteration
+}
+
 func (StorageMutatedDuringIterationError) Error() string {
 	return "storage iteration continued after modifying storage"
 }
@@ -1045,6 +1189,10 @@ var _ HasLocationRange = &ContainerMutatedDuringIterationError{}
 
 func (*ContainerMutatedDuringIterationError) IsUserError() {}
 
+func (*ContainerMutatedDuringIterationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeContainerMutatedDuringIteration
+}
+
 func (*ContainerMutatedDuringIterationError) Error() string {
 	return "resource container modified during iteration"
 }
@@ -1064,6 +1212,10 @@ var _ HasLocationRange = &InvalidHexByteError{}
 
 func (*InvalidHexByteError) IsUserError() {}
 
+func (*InvalidHexByteError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidHexByte
+}
+
 func (e *InvalidHexByteError) Error() string {
 	return fmt.Sprintf("invalid byte in hex string: %x", e.Byte)
 }
@@ -1082,6 +1234,10 @@ var _ HasLocationRange = &InvalidHexLengthError{}
 
 func (*InvalidHexLengthError) IsUserError() {}
 
+func (*InvalidHexLengthError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidHexLength
+}
+
 func (*InvalidHexLengthError) Error() string {
 	return "hex string has non-even length"
 }
@@ -1101,6 +1257,10 @@ var _ HasLocationRange = &InvalidatedResourceReferenceError{}
 
 func (*InvalidatedResourceReferenceError) IsUserError() {}
 
+func (*InvalidatedResourceReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidatedResourceReference
+}
+
 func (e *InvalidatedResourceReferenceError) Error() string {
 	return "referenced resource has been moved or destroyed after taking the reference"
 }
@@ -1121,6 +1281,10 @@ var _ HasLocationRange = &DuplicateAttachmentError{}
 
 func (*DuplicateAttachmentError) IsUserError() {}
 
+func (*DuplicateAttachmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDuplicateAttachment
+}
+
 func (e *DuplicateAttachmentError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach %s to %s, as it already exists on that value",
@@ -1144,6 +1308,10 @@ var _ HasLocationRange = &AttachmentIterationMutationError{}
 
 func (*AttachmentIterationMutationError) IsUserError() {}
 
+func (*AttachmentIterationMutationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAttachmentIterationMutation
+}
+
 func (e *AttachmentIterationMutationError) Error() string {
 	return fmt.Sprintf(
 		"cannot modify %s's attachments while iterating over them",
@@ -1188,6 +1356,10 @@ var _ HasLocationRange = &RecursiveTransferError{}
 
 func (*RecursiveTransferError) IsUserError() {}
 
+func (*RecursiveTransferError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeRecursiveTransfer
+}
+
 func (*RecursiveTransferError) Error() string {
 	return "recursive transfer of value"
 }
@@ -1227,6 +1399,10 @@ var _ HasLocationRange = &CapabilityAddressPublishingError{}
 
 func (*CapabilityAddressPublishingError) IsUserError() {}
 
+func (*CapabilityAddressPublishingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCapabilityAddressPublishing
+}
+
 func (e *CapabilityAddressPublishingError) Error() string {
 	return fmt.Sprintf(
 		"cannot publish capability of account %s in account %s",
@@ -1251,6 +1427,10 @@ var _ HasLocationRange = &EntitledCapabilityPublishingError{}
 
 func (*EntitledCapabilityPublishingError) IsUserError() {}
 
+func (*EntitledCapabilityPublishingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeEntitledCapabilityPublishing
+}
+
 func (e *EntitledCapabilityPublishingError) Error() string {
 	return fmt.Sprintf(
 		"cannot publish capability of type `%s` to the path %s",
@@ -1274,6 +1454,10 @@ var _ HasLocationRange = &NestedReferenceError{}
 
 func (*NestedReferenceError) IsUserError() {}
 
+func (*NestedReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNestedReference
+}
+
 func (e *NestedReferenceError) Error() string {
 	return fmt.Sprintf(
 		"cannot create a nested reference to %s",
@@ -1296,6 +1480,10 @@ var _ HasLocationRange = &NonOptionalReferenceToNilError{}
 
 func (*NonOptionalReferenceToNilError) IsUserError() {}
 
+func (*NonOptionalReferenceToNilError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonOptionalReferenceToNil
+}
+
 func (e *NonOptionalReferenceToNilError) Error() string {
 	return fmt.Sprintf(
 		"cannot create a reference to nil: expected `%s`, but found `nil`",
@@ -1319,6 +1507,10 @@ var _ HasLocationRange = &InclusiveRangeConstructionError{}
 
 func (*InclusiveRangeConstructionError) IsUserError() {}
 
+func (*InclusiveRangeConstructionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInclusiveRangeConstruction
+}
+
 func (e *InclusiveRangeConstructionError) Error() string {
 	const message = "InclusiveRange construction failed"
 	if e.Message == "" {
@@ -1343,6 +1535,10 @@ var _ HasLocationRange = &InvalidCapabilityIssueTypeError{}
 
 func (*InvalidCapabilityIssueTypeError) IsUserError() {}
 
+func (*InvalidCapabilityIssueTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidCapabilityIssueType
+}
+
 func (e *InvalidCapabilityIssueTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid type: expected %s, got `%s`",
@@ -1386,6 +1582,10 @@ var _ HasLocationRange = &ResourceLossError{}
 
 func (*ResourceLossError) IsUserError() {}
 
+func (*ResourceLossError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceLoss
+}
+
 func (e *ResourceLossError) Error() string {
 	return "resource loss: attempting to assign to non-nil resource-typed value"
 }
@@ -1419,6 +1619,10 @@ var _ HasLocationRange = &ReferencedValueChangedError{}
 
 func (*ReferencedValueChangedError) IsUserError() {}
 
+func (*ReferencedValueChangedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeReferencedValueChanged
+}
+
 func (e *ReferencedValueChangedError) Error() string {
 	return "referenced value has been changed after taking the reference"
 }
@@ -1437,6 +1641,10 @@ var _ HasLocationRange = &GetCapabilityError{}
 
 func (*GetCapabilityError) IsUserError() {}
 
+func (*GetCapabilityError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeGetCapability
+}
+
 func (e *GetCapabilityError) Error() string {
 	return "cannot get capability"
 }
@@ -0,0 +1,242 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/sema"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+)
+
+func TestInterpretNonNativeGenericFunction(t *testing.T) {
+
+	t.Parallel()
+
+	parseCheckAndPrepareGeneric := func(t *testing.T, code string) Invokable {
+		inter, err := parseCheckAndPrepareWithOptions(t,
+			code,
+			ParseCheckAndInterpretOptions{
+				ParseOptions: parser.Config{
+					TypeParametersEnabled: true,
+				},
+				CheckerConfig: &sema.Config{
+					TypeParametersEnabled: true,
+				},
+			},
+		)
+		require.NoError(t, err)
+		return inter
+	}
+
+	t.Run("inferred type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          fun firstOr<T: AnyStruct>(_ xs: [T], _ d: T): T {
+              if xs.length == 0 {
+                  return d
+              }
+              return xs[0]
+          }
+
+          fun testInt(): Int {
+              return firstOr([1, 2, 3], 4)
+          }
+
+          fun testString(): String {
+              return firstOr<String>([], "default")
+          }
+        `)
+
+		result, err := inter.Invoke("testInt")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.NewUnmeteredIntValueFromInt64(1), result)
+
+		result, err = inter.Invoke("testString")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.NewUnmeteredStringValue("default"), result)
+	})
+
+	t.Run("instantiated static types", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          fun pair<T>(_ a: T, _ b: T): [T] {
+              let result: [T] = [a]
+              result.append(b)
+              return result
+          }
+
+          fun twice<U>(_ x: U): [U] {
+              return pair(x, x)
+          }
+
+          fun test(): [Int8] {
+              return twice<Int8>(1)
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.IsType(t, &interpreter.ArrayValue{}, result)
+		assert.Equal(t,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt8,
+			},
+			result.StaticType(inter),
+		)
+		assert.Equal(t, 2, result.(*interpreter.ArrayValue).Count())
+	})
+
+	t.Run("dynamic cast to type parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          fun cast<T>(_ x: AnyStruct): T? {
+              return x as? T
+          }
+
+          fun testInt(): Int? {
+              return cast<Int>(1)
+          }
+
+          fun testString(): String? {
+              return cast<String>(1)
+          }
+        `)
+
+		result, err := inter.Invoke("testInt")
+		require.NoError(t, err)
+		AssertValuesEqual(t,
+			inter,
+			interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewUnmeteredIntValueFromInt64(1),
+			),
+			result,
+		)
+
+		result, err = inter.Invoke("testString")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.Nil, result)
+	})
+
+	t.Run("closure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          fun makeGetter<T>(_ x: T): fun(): [T] {
+              return fun(): [T] {
+                  return [x]
+              }
+          }
+
+          fun test(): [String] {
+              let get = makeGetter("hello")
+              return get()
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeString,
+			},
+			result.StaticType(inter),
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          resource R {
+              let id: Int
+
+              init(id: Int) {
+                  self.id = id
+              }
+          }
+
+          fun id<T: @AnyResource>(_ x: @T): @T {
+              return <-x
+          }
+
+          fun test(): @R {
+              return <-id(<-create R(id: 42))
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.IsType(t, &interpreter.CompositeValue{}, result)
+		resource := result.(*interpreter.CompositeValue)
+		assert.Equal(t, common.CompositeKindResource, resource.Kind)
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+			resource.GetField(inter, "id"),
+		)
+	})
+
+	t.Run("composite function", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndPrepareGeneric(t, `
+          struct Box {
+              fun wrap<T>(_ x: T): T? {
+                  let result: T? = x
+                  return result
+              }
+          }
+
+          fun test(): UInt8? {
+              return Box().wrap<UInt8>(3)
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+		AssertValuesEqual(t,
+			inter,
+			interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewUnmeteredUInt8Value(3),
+			),
+			result,
+		)
+	})
+}
@@ -488,6 +488,8 @@ func TestInterpretInterfaceFunctionConditionsInheritance(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	t.Run("condition in super", func(t *testing.T) {
 
 		t.Parallel()
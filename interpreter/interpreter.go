@@ -791,7 +791,7 @@ func (interpreter *Interpreter) functionDeclarationValue(
 		beforeStatements = postConditionsRewrite.BeforeStatements
 	}
 
-	return NewInterpretedFunctionValue(
+	function := NewInterpretedFunctionValue(
 		interpreter,
 		declaration.ParameterList,
 		functionType,
@@ -801,6 +801,9 @@ func (interpreter *Interpreter) functionDeclarationValue(
 		declaration.FunctionBlock.Block.Statements,
 		rewrittenPostConditions,
 	)
+	interpreter.captureTypeArguments(function)
+
+	return function
 }
 
 func (interpreter *Interpreter) visitBlock(block *ast.Block) StatementResult {
@@ -5100,7 +5103,8 @@ func (interpreter *Interpreter) GetInterfaceType(
 	return ty, nil
 }
 
-func (interpreter *Interpreter) reportLoopIteration(pos ast.HasPosition) {
+// ReportLoopIteration meters and reports a loop iteration.
+func (interpreter *Interpreter) ReportLoopIteration(pos ast.HasPosition) {
 
 	common.UseComputation(interpreter, common.LoopComputationUsage)
 
@@ -5111,7 +5115,8 @@ func (interpreter *Interpreter) reportLoopIteration(pos ast.HasPosition) {
 	}
 }
 
-func (interpreter *Interpreter) reportFunctionInvocation() {
+// ReportFunctionInvocation meters and reports a function invocation.
+func (interpreter *Interpreter) ReportFunctionInvocation() {
 
 	common.UseComputation(interpreter, common.FunctionInvocationComputationUsage)
 
@@ -5121,7 +5126,8 @@ func (interpreter *Interpreter) reportFunctionInvocation() {
 	}
 }
 
-func (interpreter *Interpreter) reportInvokedFunctionReturn() {
+// ReportInvokedFunctionReturn reports the return from an invoked function.
+func (interpreter *Interpreter) ReportInvokedFunctionReturn() {
 	onInvokedFunctionReturn := interpreter.SharedState.Config.OnInvokedFunctionReturn
 	if onInvokedFunctionReturn == nil {
 		return
@@ -5146,9 +5152,9 @@ func getAccessOfMember(context ValueStaticTypeContext, self Value, identifier st
 	return member.Resolve(context, identifier, ast.EmptyRange, func(err error) {}).Access
 }
 
-// getMember gets the member value by the given identifier from the given Value depending on its type.
+// GetMember gets the member value by the given identifier from the given Value depending on its type.
 // May return nil if the member does not exist.
-func getMember(context MemberAccessibleContext, self Value, locationRange LocationRange, identifier string) Value {
+func GetMember(context MemberAccessibleContext, self Value, locationRange LocationRange, identifier string) Value {
 	var result Value
 	// When the accessed value has a type that supports the declaration of members
 	// or is a built-in type that has members (`MemberAccessibleValue`),
@@ -150,8 +150,8 @@ func (interpreter *Interpreter) valueIndexExpressionGetterSetter(
 		panic(errors.NewUnreachableError())
 	}
 
-	indexedType := indexExpressionTypes.IndexedType
-	indexingType := indexExpressionTypes.IndexingType
+	indexedType := interpreter.substituteTypeArguments(indexExpressionTypes.IndexedType).(sema.ValueIndexableType)
+	indexingType := interpreter.substituteTypeArguments(indexExpressionTypes.IndexingType)
 
 	transferredIndexingValue := TransferAndConvert(
 		interpreter,
@@ -265,7 +265,7 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(
 			if isNestedResourceMove {
 				resultValue = target.(MemberAccessibleValue).RemoveMember(interpreter, locationRange, identifier)
 			} else {
-				resultValue = getMember(interpreter, target, locationRange, identifier)
+				resultValue = GetMember(interpreter, target, locationRange, identifier)
 			}
 
 			if resultValue == nil && !allowMissing {
@@ -291,7 +291,7 @@ func (interpreter *Interpreter) memberExpressionGetterSetter(
 				resultValue = getReferenceValue(
 					interpreter,
 					resultValue,
-					memberAccessInfo.ResultingType,
+					interpreter.substituteTypeArguments(memberAccessInfo.ResultingType),
 					locationRange,
 				)
 			}
@@ -328,8 +328,18 @@ func (interpreter *Interpreter) checkMemberAccess(
 	CheckInvalidatedResourceOrResourceReference(target, locationRange, interpreter)
 
 	memberInfo, _ := interpreter.Program.Elaboration.MemberExpressionMemberAccessInfo(memberExpression)
-	expectedType := memberInfo.AccessedType
+	expectedType := interpreter.substituteTypeArguments(memberInfo.AccessedType)
 
+	interpreter.CheckMemberAccessTarget(target, expectedType, locationRange)
+}
+
+// CheckMemberAccessTarget checks that the target of a member access
+// has the accessed type determined by the checker.
+func (interpreter *Interpreter) CheckMemberAccessTarget(
+	target Value,
+	expectedType sema.Type,
+	locationRange LocationRange,
+) {
 	switch expectedType := expectedType.(type) {
 	case *sema.TransactionType:
 		// TODO: maybe also check transactions.
@@ -632,8 +642,8 @@ func (interpreter *Interpreter) VisitBinaryExpression(expression *ast.BinaryExpr
 		value := rightValue()
 
 		binaryExpressionTypes := interpreter.Program.Elaboration.BinaryExpressionTypes(expression)
-		rightType := binaryExpressionTypes.RightType
-		resultType := binaryExpressionTypes.ResultType
+		rightType := interpreter.substituteTypeArguments(binaryExpressionTypes.RightType)
+		resultType := interpreter.substituteTypeArguments(binaryExpressionTypes.ResultType)
 
 		// NOTE: important to convert both any and optional
 		return ConvertAndBox(interpreter, locationRange, value, rightType, resultType)
@@ -949,8 +959,8 @@ func (interpreter *Interpreter) VisitArrayExpression(expression *ast.ArrayExpres
 	values := interpreter.visitExpressionsNonCopying(expression.Values)
 
 	arrayExpressionTypes := interpreter.Program.Elaboration.ArrayExpressionTypes(expression)
-	argumentTypes := arrayExpressionTypes.ArgumentTypes
-	arrayType := arrayExpressionTypes.ArrayType
+	argumentTypes := interpreter.substituteTypeArgumentsInTypes(arrayExpressionTypes.ArgumentTypes)
+	arrayType := interpreter.substituteTypeArguments(arrayExpressionTypes.ArrayType).(sema.ArrayType)
 	elementType := arrayType.ElementType(false)
 
 	var copies []Value
@@ -991,7 +1001,7 @@ func (interpreter *Interpreter) VisitDictionaryExpression(expression *ast.Dictio
 
 	dictionaryExpressionTypes := interpreter.Program.Elaboration.DictionaryExpressionTypes(expression)
 	entryTypes := dictionaryExpressionTypes.EntryTypes
-	dictionaryType := dictionaryExpressionTypes.DictionaryType
+	dictionaryType := interpreter.substituteTypeArguments(dictionaryExpressionTypes.DictionaryType).(*sema.DictionaryType)
 
 	var keyValuePairs []Value
 
@@ -1002,7 +1012,7 @@ func (interpreter *Interpreter) VisitDictionaryExpression(expression *ast.Dictio
 		key := TransferAndConvert(
 			interpreter,
 			dictionaryEntryValues.Key,
-			entryType.KeyType,
+			interpreter.substituteTypeArguments(entryType.KeyType),
 			dictionaryType.KeyType,
 			LocationRange{
 				Location:    interpreter.Location,
@@ -1013,7 +1023,7 @@ func (interpreter *Interpreter) VisitDictionaryExpression(expression *ast.Dictio
 		value := TransferAndConvert(
 			interpreter,
 			dictionaryEntryValues.Value,
-			entryType.ValueType,
+			interpreter.substituteTypeArguments(entryType.ValueType),
 			dictionaryType.ValueType,
 			LocationRange{
 				Location:    interpreter.Location,
@@ -1093,7 +1103,7 @@ func (interpreter *Interpreter) maybeGetReference(
 	indexExpressionTypes, _ := interpreter.Program.Elaboration.IndexExpressionTypes(expression)
 
 	if indexExpressionTypes.ReturnReference {
-		expectedType := indexExpressionTypes.ResultType
+		expectedType := interpreter.substituteTypeArguments(indexExpressionTypes.ResultType)
 
 		locationRange := LocationRange{
 			Location:    interpreter.Location,
@@ -1193,10 +1203,10 @@ func (interpreter *Interpreter) visitInvocationExpressionWithImplicitArgument(in
 
 	invocationExpressionTypes := elaboration.InvocationExpressionTypes(invocationExpression)
 
-	typeParameterTypes := invocationExpressionTypes.TypeArguments
-	argumentTypes := invocationExpressionTypes.ArgumentTypes
-	parameterTypes := invocationExpressionTypes.ParameterTypes
-	returnType := invocationExpressionTypes.ReturnType
+	typeParameterTypes := interpreter.substituteTypeArgumentsInTypeArguments(invocationExpressionTypes.TypeArguments)
+	argumentTypes := interpreter.substituteTypeArgumentsInTypes(invocationExpressionTypes.ArgumentTypes)
+	parameterTypes := interpreter.substituteTypeArgumentsInTypes(invocationExpressionTypes.ParameterTypes)
+	returnType := interpreter.substituteTypeArguments(invocationExpressionTypes.ReturnType)
 
 	// add the implicit argument to the end of the argument list, if it exists
 	if implicitArg != nil {
@@ -1205,7 +1215,7 @@ func (interpreter *Interpreter) visitInvocationExpressionWithImplicitArgument(in
 		argumentTypes = append(argumentTypes, argumentType)
 	}
 
-	interpreter.reportFunctionInvocation()
+	interpreter.ReportFunctionInvocation()
 
 	resultValue := invokeFunctionValue(
 		interpreter,
@@ -1219,7 +1229,7 @@ func (interpreter *Interpreter) visitInvocationExpressionWithImplicitArgument(in
 		invocationExpression,
 	)
 
-	interpreter.reportInvokedFunctionReturn()
+	interpreter.ReportInvokedFunctionReturn()
 
 	// If this is invocation is optional chaining, wrap the result
 	// as an optional, as the result is expected to be an optional
@@ -1315,7 +1325,7 @@ func (interpreter *Interpreter) VisitFunctionExpression(expression *ast.Function
 
 	statements := expression.FunctionBlock.Block.Statements
 
-	return NewInterpretedFunctionValue(
+	function := NewInterpretedFunctionValue(
 		interpreter,
 		expression.ParameterList,
 		functionType,
@@ -1325,6 +1335,9 @@ func (interpreter *Interpreter) VisitFunctionExpression(expression *ast.Function
 		statements,
 		rewrittenPostConditions,
 	)
+	interpreter.captureTypeArguments(function)
+
+	return function
 }
 
 func (interpreter *Interpreter) VisitCastingExpression(expression *ast.CastingExpression) Value {
@@ -1336,7 +1349,7 @@ func (interpreter *Interpreter) VisitCastingExpression(expression *ast.CastingEx
 	}
 
 	castingExpressionTypes := interpreter.Program.Elaboration.CastingExpressionTypes(expression)
-	expectedType := castingExpressionTypes.TargetType
+	expectedType := interpreter.substituteTypeArguments(castingExpressionTypes.TargetType)
 
 	switch expression.Operation {
 	case ast.OperationFailableCast, ast.OperationForceCast:
@@ -1397,7 +1410,7 @@ func (interpreter *Interpreter) VisitCastingExpression(expression *ast.CastingEx
 		return value
 
 	case ast.OperationCast:
-		staticValueType := castingExpressionTypes.StaticValueType
+		staticValueType := interpreter.substituteTypeArguments(castingExpressionTypes.StaticValueType)
 		// The cast may upcast to an optional type, e.g. `1 as Int?`, so box
 		return ConvertAndBox(interpreter, locationRange, value, staticValueType, expectedType)
 
@@ -1427,7 +1440,9 @@ func (interpreter *Interpreter) VisitDestroyExpression(expression *ast.DestroyEx
 
 func (interpreter *Interpreter) VisitReferenceExpression(referenceExpression *ast.ReferenceExpression) Value {
 
-	borrowType := interpreter.Program.Elaboration.ReferenceExpressionBorrowType(referenceExpression)
+	borrowType := interpreter.substituteTypeArguments(
+		interpreter.Program.Elaboration.ReferenceExpressionBorrowType(referenceExpression),
+	)
 
 	result := interpreter.evalExpression(referenceExpression.Expression)
 
@@ -1679,3 +1694,8 @@ func (interpreter *Interpreter) VisitAttachExpression(attachExpression *ast.Atta
 
 	return base
 }
+
+func (interpreter *Interpreter) VisitInvalidExpression(_ *ast.InvalidExpression) Value {
+	// Programs with syntax errors are never interpreted
+	panic(errors.NewUnreachableError())
+}
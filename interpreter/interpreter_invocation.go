@@ -37,6 +37,33 @@ func InvokeFunctionValue(
 	value Value,
 	err error,
 ) {
+	return InvokeFunctionValueWithTypeArguments(
+		context,
+		function,
+		arguments,
+		argumentTypes,
+		parameterTypes,
+		returnType,
+		nil,
+		invocationPosition,
+	)
+}
+
+// InvokeFunctionValueWithTypeArguments invokes the given function value like InvokeFunctionValue,
+// and additionally passes the given type arguments, e.g. to a generic host function.
+func InvokeFunctionValueWithTypeArguments(
+	context InvocationContext,
+	function FunctionValue,
+	arguments []Value,
+	argumentTypes []sema.Type,
+	parameterTypes []sema.Type,
+	returnType sema.Type,
+	typeArguments *sema.TypeParameterTypeOrderedMap,
+	invocationPosition ast.HasPosition,
+) (
+	value Value,
+	err error,
+) {
 
 	// recover internal panics and return them as an error
 	defer context.RecoverErrors(func(internalErr error) {
@@ -51,7 +78,7 @@ func InvokeFunctionValue(
 		argumentTypes,
 		parameterTypes,
 		returnType,
-		nil,
+		typeArguments,
 		invocationPosition,
 	), nil
 }
@@ -133,7 +160,16 @@ func invokeFunctionValue(
 
 	resultValue := function.Invoke(invocation)
 
-	functionReturnType := function.FunctionType(context).ReturnTypeAnnotation.Type
+	functionType := function.FunctionType(context)
+	functionReturnType := functionType.ReturnTypeAnnotation.Type
+
+	// The return type of a generic function is instantiated with the type arguments
+	if len(functionType.TypeParameters) > 0 && typeParameterTypes != nil {
+		resolvedReturnType := functionReturnType.Resolve(typeParameterTypes)
+		if resolvedReturnType != nil {
+			functionReturnType = resolvedReturnType
+		}
+	}
 
 	// Only convert and box.
 	// No need to transfer, since transfer would happen later, when the return value gets assigned.
@@ -174,6 +210,8 @@ func (interpreter *Interpreter) invokeInterpretedFunction(
 	current := interpreter.activations.PushNewWithParent(function.Activation)
 	current.IsFunction = true
 
+	invocation.TypeParameterTypes = function.typeArguments(invocation.TypeParameterTypes)
+
 	interpreter.SharedState.callStack.Push(invocation)
 
 	// Make `self` available, if any
@@ -213,7 +251,7 @@ func (interpreter *Interpreter) invokeInterpretedFunctionActivated(
 			return interpreter.visitStatements(function.Statements)
 		},
 		function.PostConditions,
-		function.Type.ReturnTypeAnnotation.Type,
+		interpreter.substituteTypeArguments(function.Type.ReturnTypeAnnotation.Type),
 		declarationLocationRange,
 	)
 }
@@ -228,3 +266,110 @@ func (interpreter *Interpreter) bindParameterArguments(
 		interpreter.declareVariable(parameter.Identifier.Identifier, argument)
 	}
 }
+
+// typeArguments returns the type arguments for an invocation of the function:
+// The given type arguments of the invocation itself,
+// and the type arguments of the generic functions the function was declared in, if any.
+func (f *InterpretedFunctionValue) typeArguments(
+	invocationTypeArguments *sema.TypeParameterTypeOrderedMap,
+) *sema.TypeParameterTypeOrderedMap {
+
+	if f.TypeArguments == nil || f.TypeArguments.Len() == 0 {
+		return invocationTypeArguments
+	}
+
+	if invocationTypeArguments == nil || invocationTypeArguments.Len() == 0 {
+		return f.TypeArguments
+	}
+
+	typeArguments := &sema.TypeParameterTypeOrderedMap{}
+	typeArguments.SetAll(f.TypeArguments)
+	typeArguments.SetAll(invocationTypeArguments)
+	return typeArguments
+}
+
+// captureTypeArguments records the type arguments of the current invocation in the given function,
+// as the function may be declared in a generic function and refer to its type parameters.
+func (interpreter *Interpreter) captureTypeArguments(function *InterpretedFunctionValue) {
+	typeArguments := interpreter.currentTypeArguments()
+	if typeArguments == nil || typeArguments.Len() == 0 {
+		return
+	}
+
+	function.TypeArguments = typeArguments
+
+	// Instantiate the function's type, unless it is generic itself
+	if len(function.Type.TypeParameters) == 0 {
+		if resolvedType, ok := function.Type.Resolve(typeArguments).(*sema.FunctionType); ok {
+			function.Type = resolvedType
+		}
+	}
+}
+
+// currentTypeArguments returns the type arguments of the current invocation,
+// i.e. the types with which the type parameters of the currently executing generic function,
+// and the generic functions it was declared in, were instantiated.
+func (interpreter *Interpreter) currentTypeArguments() *sema.TypeParameterTypeOrderedMap {
+	invocations := interpreter.SharedState.callStack.Invocations
+	if len(invocations) == 0 {
+		return nil
+	}
+	return invocations[len(invocations)-1].TypeParameterTypes
+}
+
+// substituteTypeArguments instantiates the type parameters in the given static type
+// with the type arguments of the current invocation.
+//
+// The types recorded in the elaboration for expressions and statements
+// in the body of a generic function refer to the function's type parameters,
+// so they must be instantiated before the types are used at run-time,
+// e.g. for conversions, dynamic type checks, or to construct static types of values.
+func (interpreter *Interpreter) substituteTypeArguments(ty sema.Type) sema.Type {
+	if ty == nil {
+		return nil
+	}
+
+	typeArguments := interpreter.currentTypeArguments()
+	if typeArguments == nil || typeArguments.Len() == 0 {
+		return ty
+	}
+
+	resolvedType := ty.Resolve(typeArguments)
+	if resolvedType == nil {
+		return ty
+	}
+	return resolvedType
+}
+
+func (interpreter *Interpreter) substituteTypeArgumentsInTypes(types []sema.Type) []sema.Type {
+	typeArguments := interpreter.currentTypeArguments()
+	if typeArguments == nil || typeArguments.Len() == 0 {
+		return types
+	}
+
+	resolvedTypes := make([]sema.Type, len(types))
+	for i, ty := range types {
+		resolvedTypes[i] = interpreter.substituteTypeArguments(ty)
+	}
+	return resolvedTypes
+}
+
+func (interpreter *Interpreter) substituteTypeArgumentsInTypeArguments(
+	typeArguments *sema.TypeParameterTypeOrderedMap,
+) *sema.TypeParameterTypeOrderedMap {
+
+	if typeArguments == nil || typeArguments.Len() == 0 {
+		return typeArguments
+	}
+
+	currentTypeArguments := interpreter.currentTypeArguments()
+	if currentTypeArguments == nil || currentTypeArguments.Len() == 0 {
+		return typeArguments
+	}
+
+	resolvedTypeArguments := &sema.TypeParameterTypeOrderedMap{}
+	typeArguments.Foreach(func(typeParameter *sema.TypeParameter, ty sema.Type) {
+		resolvedTypeArguments.Set(typeParameter, interpreter.substituteTypeArguments(ty))
+	})
+	return resolvedTypeArguments
+}
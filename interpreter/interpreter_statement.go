@@ -75,8 +75,8 @@ func (interpreter *Interpreter) VisitReturnStatement(statement *ast.ReturnStatem
 		value = interpreter.evalExpression(statement.Expression)
 
 		returnStatementTypes := interpreter.Program.Elaboration.ReturnStatementTypes(statement)
-		valueType := returnStatementTypes.ValueType
-		returnType := returnStatementTypes.ReturnType
+		valueType := interpreter.substituteTypeArguments(returnStatementTypes.ValueType)
+		returnType := interpreter.substituteTypeArguments(returnStatementTypes.ReturnType)
 
 		locationRange := LocationRange{
 			Location:    interpreter.Location,
@@ -112,6 +112,16 @@ func (interpreter *Interpreter) VisitEntitlementMappingDeclaration(_ *ast.Entitl
 	panic(errors.NewUnreachableError())
 }
 
+func (interpreter *Interpreter) VisitInvalidDeclaration(_ *ast.InvalidDeclaration) StatementResult {
+	// Programs with syntax errors are never interpreted
+	panic(errors.NewUnreachableError())
+}
+
+func (interpreter *Interpreter) VisitInvalidStatement(_ *ast.InvalidStatement) StatementResult {
+	// Programs with syntax errors are never interpreted
+	panic(errors.NewUnreachableError())
+}
+
 func (interpreter *Interpreter) VisitIfStatement(statement *ast.IfStatement) StatementResult {
 	switch test := statement.Test.(type) {
 	case ast.Expression:
@@ -242,7 +252,7 @@ func (interpreter *Interpreter) VisitWhileStatement(statement *ast.WhileStatemen
 			return nil
 		}
 
-		interpreter.reportLoopIteration(statement)
+		interpreter.ReportLoopIteration(statement)
 
 		result := interpreter.visitBlock(statement.Block)
 
@@ -309,7 +319,7 @@ func (interpreter *Interpreter) VisitForStatement(statement *ast.ForStatement) (
 
 	iterable.ForEach(
 		interpreter,
-		forStmtTypes.ValueVariableType,
+		interpreter.substituteTypeArguments(forStmtTypes.ValueVariableType),
 		executeBody,
 		transferElements,
 		locationRange,
@@ -326,7 +336,7 @@ func (interpreter *Interpreter) visitForStatementBody(
 	result StatementResult,
 	done bool,
 ) {
-	interpreter.reportLoopIteration(statement)
+	interpreter.ReportLoopIteration(statement)
 
 	interpreter.activations.PushNewWithCurrent()
 	defer interpreter.activations.Pop()
@@ -506,9 +516,9 @@ func (interpreter *Interpreter) visitVariableDeclaration(
 ) Value {
 
 	variableDeclarationTypes := interpreter.Program.Elaboration.VariableDeclarationTypes(declaration)
-	targetType := variableDeclarationTypes.TargetType
-	valueType := variableDeclarationTypes.ValueType
-	secondValueType := variableDeclarationTypes.SecondValueType
+	targetType := interpreter.substituteTypeArguments(variableDeclarationTypes.TargetType)
+	valueType := interpreter.substituteTypeArguments(variableDeclarationTypes.ValueType)
+	secondValueType := interpreter.substituteTypeArguments(variableDeclarationTypes.SecondValueType)
 
 	// NOTE: It is *REQUIRED* that the getter for the value is used
 	// instead of just evaluating value expression,
@@ -568,8 +578,8 @@ func (interpreter *Interpreter) visitVariableDeclaration(
 
 func (interpreter *Interpreter) VisitAssignmentStatement(assignment *ast.AssignmentStatement) StatementResult {
 	assignmentStatementTypes := interpreter.Program.Elaboration.AssignmentStatementTypes(assignment)
-	targetType := assignmentStatementTypes.TargetType
-	valueType := assignmentStatementTypes.ValueType
+	targetType := interpreter.substituteTypeArguments(assignmentStatementTypes.TargetType)
+	valueType := interpreter.substituteTypeArguments(assignmentStatementTypes.ValueType)
 
 	target := assignment.Target
 	value := assignment.Value
@@ -596,8 +606,8 @@ func (interpreter *Interpreter) VisitSwapStatement(swap *ast.SwapStatement) Stat
 	// Get type information
 
 	swapStatementTypes := interpreter.Program.Elaboration.SwapStatementTypes(swap)
-	leftType := swapStatementTypes.LeftType
-	rightType := swapStatementTypes.RightType
+	leftType := interpreter.substituteTypeArguments(swapStatementTypes.LeftType)
+	rightType := interpreter.substituteTypeArguments(swapStatementTypes.RightType)
 
 	// Evaluate the left side (target and key)
 
@@ -60,6 +60,16 @@ func parseCheckAndPrepareWithOptions(
 	return test_utils.ParseCheckAndPrepareWithOptions(tb, code, options, *compile)
 }
 
+// skipIfCompiled skips the test if the tests are run using the compiler,
+// because the tested code is not compiled yet, and is executed by the interpreter
+func skipIfCompiled(t *testing.T, reason string) {
+	t.Helper()
+
+	if *compile {
+		t.Skip(reason)
+	}
+}
+
 func TestInterpreterOptionalBoxing(t *testing.T) {
 
 	t.Parallel()
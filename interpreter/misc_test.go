@@ -7327,6 +7327,8 @@ func TestInterpretInterfaceInitializer(t *testing.T) {
 
 	t.Parallel()
 
+	skipIfCompiled(t, "composite functions are not compiled yet")
+
 	inter := parseCheckAndPrepare(t, `
       struct interface I {
           init(a a1: Bool) {
@@ -48,6 +48,9 @@ type InterpretedFunctionValue struct {
 	PreConditions    []ast.Condition
 	Statements       []ast.Statement
 	PostConditions   []ast.Condition
+	// TypeArguments are the type arguments of the invocations of the generic functions
+	// in which the function was declared, if any
+	TypeArguments *sema.TypeParameterTypeOrderedMap
 }
 
 func NewInterpretedFunctionValue(
@@ -213,7 +213,7 @@ func (v *StorageReferenceValue) GetMember(context MemberAccessibleContext, locat
 	// By default, bound functions create and hold an ephemeral reference (`SelfReference`).
 	// For storage references, replace this default one with the actual storage reference.
 	// It is not possible (or a lot of work), to create the bound function with the storage reference
-	// when it was created originally, because `getMember(referencedValue, ...)` doesn't know
+	// when it was created originally, because `GetMember(referencedValue, ...)` doesn't know
 	// whether the member was accessed directly, or via a reference.
 	if boundFunction, isBoundFunction := member.(BoundFunctionValue); isBoundFunction {
 		boundFunction.SelfReference = v
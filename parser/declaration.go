@@ -47,14 +47,21 @@ func parseDeclarations(p *parser, endTokenType lexer.TokenType) (declarations []
 			return
 
 		default:
+			startPos := p.current.StartPos
+
 			var declaration ast.Declaration
 			declaration, err = parseDeclaration(p, docString)
-			if err != nil {
-				return
+			if err == nil && declaration == nil {
+				if !p.config.ErrorRecoveryEnabled {
+					return
+				}
+				err = p.syntaxError("unexpected token: %s", p.current.Type)
 			}
-
-			if declaration == nil {
-				return
+			if err != nil {
+				declaration, err = p.recoverDeclaration(startPos, err, endTokenType)
+				if err != nil {
+					return
+				}
 			}
 
 			declarations = append(declarations, declaration)
@@ -1377,7 +1384,7 @@ func parseCompositeOrInterfaceDeclaration(
 
 	p.skipSpaceAndComments()
 
-	endToken, err := p.mustOne(lexer.TokenBraceClose)
+	endToken, err := p.mustOneOrInsert(lexer.TokenBraceClose)
 	if err != nil {
 		return nil, err
 	}
@@ -1485,7 +1492,7 @@ func parseAttachmentDeclaration(
 
 	p.skipSpaceAndComments()
 
-	endToken, err := p.mustOne(lexer.TokenBraceClose)
+	endToken, err := p.mustOneOrInsert(lexer.TokenBraceClose)
 	if err != nil {
 		return nil, err
 	}
@@ -1532,13 +1539,20 @@ func parseMembersAndNestedDeclarations(p *parser, endTokenType lexer.TokenType)
 			return ast.NewMembers(p.memoryGauge, declarations), nil
 
 		default:
+			startPos := p.current.StartPos
+
 			memberOrNestedDeclaration, err := parseMemberOrNestedDeclaration(p, docString)
-			if err != nil {
-				return nil, err
+			if err == nil && memberOrNestedDeclaration == nil {
+				if !p.config.ErrorRecoveryEnabled {
+					return ast.NewMembers(p.memoryGauge, declarations), nil
+				}
+				err = p.syntaxError("unexpected token: %s", p.current.Type)
 			}
-
-			if memberOrNestedDeclaration == nil {
-				return ast.NewMembers(p.memoryGauge, declarations), nil
+			if err != nil {
+				memberOrNestedDeclaration, err = p.recoverDeclaration(startPos, err, endTokenType)
+				if err != nil {
+					return nil, err
+				}
 			}
 
 			declarations = append(declarations, memberOrNestedDeclaration)
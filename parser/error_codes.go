@@ -0,0 +1,115 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/parser/lexer"
+)
+
+// Error codes of the parser errors.
+//
+// Error codes are stable: Once assigned, an error code must never change,
+// and must not be reused, even if the error is removed.
+// New errors must be assigned the next unassigned error code.
+const (
+	ErrorCodeSyntax                              errors.ErrorCode = "E1001"
+	ErrorCodeSyntaxErrorWithSuggestedReplacement errors.ErrorCode = "E1002"
+	ErrorCodeJuxtaposedUnaryOperators            errors.ErrorCode = "E1003"
+	ErrorCodeInvalidIntegerLiteral               errors.ErrorCode = "E1004"
+	ErrorCodeExpressionDepthLimitReached         errors.ErrorCode = "E1005"
+	ErrorCodeTypeDepthLimitReached               errors.ErrorCode = "E1006"
+	ErrorCodeMissingCommaInParameterList         errors.ErrorCode = "E1007"
+	ErrorCodeCustomDestructor                    errors.ErrorCode = "E1008"
+	ErrorCodeRestrictedType                      errors.ErrorCode = "E1009"
+)
+
+// ErrorCodes is the catalog of the error codes of the parser errors
+var ErrorCodes = []errors.ErrorCodeInfo{
+	{
+		Code:        ErrorCodeSyntax,
+		Name:        "SyntaxError",
+		Explanation: "The program is not syntactically valid Cadence, for example because a token is missing or unexpected.",
+		Example: `
+let x =
+`,
+	},
+	{
+		Code:        ErrorCodeSyntaxErrorWithSuggestedReplacement,
+		Name:        "SyntaxErrorWithSuggestedReplacement",
+		Explanation: "The program is not syntactically valid Cadence, but the parser can suggest a replacement, for example for a removed access modifier.",
+		Example: `
+pub fun test() {}
+`,
+	},
+	{
+		Code:        ErrorCodeJuxtaposedUnaryOperators,
+		Name:        "JuxtaposedUnaryOperatorsError",
+		Explanation: "Two unary operators are written directly next to each other, which is ambiguous. Parenthesize the inner expression.",
+	},
+	{
+		Code:        ErrorCodeInvalidIntegerLiteral,
+		Name:        "InvalidIntegerLiteralError",
+		Explanation: "An integer literal is malformed, for example it has an unknown prefix, a leading or trailing underscore, or no digits after the prefix.",
+		Example: `
+let x = 0x
+`,
+	},
+	{
+		Code:        ErrorCodeExpressionDepthLimitReached,
+		Name:        "ExpressionDepthLimitReachedError",
+		Explanation: "An expression is nested too deeply. Simplify the expression, for example by splitting it into multiple declarations.",
+	},
+	{
+		Code:        ErrorCodeTypeDepthLimitReached,
+		Name:        "TypeDepthLimitReachedError",
+		Explanation: "A type is nested too deeply. Simplify the type, for example by introducing intermediate composite types.",
+	},
+	{
+		Code:        ErrorCodeMissingCommaInParameterList,
+		Name:        "MissingCommaInParameterListError",
+		Explanation: "The parameters of a function are not separated by commas.",
+		Example: `
+fun test(a: Int b: Int) {}
+`,
+	},
+	{
+		Code:        ErrorCodeCustomDestructor,
+		Name:        "CustomDestructorError",
+		Explanation: "Resources can no longer declare custom destructors. Remove the `destroy` function and use a default destruction event if needed.",
+		Example: `
+resource R {
+    destroy() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeRestrictedType,
+		Name:        "RestrictedTypeError",
+		Explanation: "Restricted types, like `T{I}`, have been removed. Replace them with the concrete type, or with an intersection type, like `{I}`.",
+		Example: `
+let x: AnyStruct{Equatable}? = nil
+`,
+	},
+	{
+		Code:        lexer.ErrorCodeTokenLimitReached,
+		Name:        "TokenLimitReachedError",
+		Explanation: "The program consists of too many tokens. Split the program into multiple smaller programs.",
+	},
+}
@@ -0,0 +1,60 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/errors"
+)
+
+func TestParseErrorCodeExamples(t *testing.T) {
+
+	t.Parallel()
+
+	for _, info := range ErrorCodes {
+		if info.Example == "" {
+			continue
+		}
+
+		info := info
+
+		t.Run(string(info.Code), func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseProgram(nil, []byte(info.Example), Config{})
+			require.Error(t, err)
+
+			var parentErr errors.ParentError
+			require.ErrorAs(t, err, &parentErr)
+
+			var codes []errors.ErrorCode
+			for _, err := range parentErr.ChildErrors() {
+				code, ok := errors.GetErrorCode(err)
+				require.True(t, ok, "missing error code: %T", err)
+				codes = append(codes, code)
+			}
+
+			require.Contains(t, codes, info.Code, info.Name)
+		})
+	}
+}
@@ -83,6 +83,10 @@ func (*SyntaxError) isParseError() {}
 
 func (*SyntaxError) IsUserError() {}
 
+func (*SyntaxError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeSyntax
+}
+
 func (e *SyntaxError) StartPosition() ast.Position {
 	return e.Pos
 }
@@ -119,6 +123,11 @@ var _ errors.UserError = &SyntaxErrorWithSuggestedReplacement{}
 func (*SyntaxErrorWithSuggestedReplacement) isParseError() {}
 
 func (*SyntaxErrorWithSuggestedReplacement) IsUserError() {}
+
+func (*SyntaxErrorWithSuggestedReplacement) ErrorCode() errors.ErrorCode {
+	return ErrorCodeSyntaxErrorWithSuggestedReplacement
+}
+
 func (e *SyntaxErrorWithSuggestedReplacement) Error() string {
 	return e.Message
 }
@@ -150,6 +159,10 @@ func (*JuxtaposedUnaryOperatorsError) isParseError() {}
 
 func (*JuxtaposedUnaryOperatorsError) IsUserError() {}
 
+func (*JuxtaposedUnaryOperatorsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeJuxtaposedUnaryOperators
+}
+
 func (e *JuxtaposedUnaryOperatorsError) StartPosition() ast.Position {
 	return e.Pos
 }
@@ -179,6 +192,10 @@ func (*InvalidIntegerLiteralError) isParseError() {}
 
 func (*InvalidIntegerLiteralError) IsUserError() {}
 
+func (*InvalidIntegerLiteralError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidIntegerLiteral
+}
+
 func (e *InvalidIntegerLiteralError) Error() string {
 	if e.IntegerLiteralKind == common.IntegerLiteralKindUnknown {
 		return fmt.Sprintf(
@@ -225,6 +242,10 @@ func (ExpressionDepthLimitReachedError) isParseError() {}
 
 func (ExpressionDepthLimitReachedError) IsUserError() {}
 
+func (ExpressionDepthLimitReachedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeExpressionDepthLimitReached
+}
+
 func (e ExpressionDepthLimitReachedError) Error() string {
 	return fmt.Sprintf(
 		"program too complex, reached max expression depth limit %d",
@@ -254,6 +275,10 @@ func (TypeDepthLimitReachedError) isParseError() {}
 
 func (TypeDepthLimitReachedError) IsUserError() {}
 
+func (TypeDepthLimitReachedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeDepthLimitReached
+}
+
 func (e TypeDepthLimitReachedError) Error() string {
 	return fmt.Sprintf(
 		"program too complex, reached max type depth limit %d",
@@ -282,6 +307,10 @@ func (*MissingCommaInParameterListError) isParseError() {}
 
 func (*MissingCommaInParameterListError) IsUserError() {}
 
+func (*MissingCommaInParameterListError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingCommaInParameterList
+}
+
 func (e *MissingCommaInParameterListError) StartPosition() ast.Position {
 	return e.Pos
 }
@@ -307,6 +336,10 @@ func (*CustomDestructorError) isParseError() {}
 
 func (*CustomDestructorError) IsUserError() {}
 
+func (*CustomDestructorError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCustomDestructor
+}
+
 func (e *CustomDestructorError) StartPosition() ast.Position {
 	return e.Pos
 }
@@ -336,6 +369,10 @@ func (*RestrictedTypeError) isParseError() {}
 
 func (*RestrictedTypeError) IsUserError() {}
 
+func (*RestrictedTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeRestrictedType
+}
+
 func (e *RestrictedTypeError) Error() string {
 	return "restricted types have been removed; replace with the concrete type or an equivalent intersection type"
 }
@@ -40,6 +40,14 @@ var _ errors.UserError = TokenLimitReachedError{}
 
 func (TokenLimitReachedError) IsUserError() {}
 
+// ErrorCodeTokenLimitReached is the error code of TokenLimitReachedError.
+// It is part of the parser error codes, see parser.ErrorCodes
+const ErrorCodeTokenLimitReached errors.ErrorCode = "E1010"
+
+func (TokenLimitReachedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTokenLimitReached
+}
+
 func (TokenLimitReachedError) Error() string {
 	return fmt.Sprintf("limit of %d tokens exceeded", tokenLimit)
 }
@@ -57,6 +57,12 @@ type Config struct {
 	IgnoreLeadingIdentifierEnabled bool
 	// TypeParametersEnabled determines if type parameters are enabled
 	TypeParametersEnabled bool
+	// ErrorRecoveryEnabled determines if the parser recovers from syntax errors
+	// and produces a partial AST, instead of stopping at the first error.
+	//
+	// This option is intended for tooling, e.g. the checker command and the language server,
+	// which benefit from reporting all errors and analysing the valid parts of a program.
+	ErrorRecoveryEnabled bool
 }
 
 type parser struct {
@@ -0,0 +1,274 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/parser/lexer"
+)
+
+// Error recovery
+//
+// When a declaration or statement cannot be parsed, the error is reported,
+// and the parser skips ahead to a synchronization point:
+// a keyword which starts a declaration (or statement),
+// or a closing brace which ends the enclosing block.
+// The skipped source is represented by an invalid placeholder node,
+// so the result is a best-effort AST which contains all valid parts of the input.
+//
+// Recovery is only performed if it is enabled in the configuration.
+// It is not possible while buffering for an ambiguity,
+// as the error is needed to select an alternative.
+
+// declarationSyncKeywords are the keywords which may start a declaration
+var declarationSyncKeywords = map[string]struct{}{
+	KeywordAccess:      {},
+	KeywordPub:         {},
+	KeywordPriv:        {},
+	KeywordStatic:      {},
+	KeywordNative:      {},
+	KeywordView:        {},
+	KeywordLet:         {},
+	KeywordVar:         {},
+	KeywordFun:         {},
+	KeywordInit:        {},
+	KeywordImport:      {},
+	KeywordEvent:       {},
+	KeywordStruct:      {},
+	KeywordResource:    {},
+	KeywordContract:    {},
+	KeywordEnum:        {},
+	KeywordCase:        {},
+	KeywordAttachment:  {},
+	KeywordEntitlement: {},
+	KeywordTransaction: {},
+}
+
+// statementSyncKeywords are the keywords which may start a statement,
+// in addition to the declaration keywords
+var statementSyncKeywords = map[string]struct{}{
+	KeywordReturn:   {},
+	KeywordBreak:    {},
+	KeywordContinue: {},
+	KeywordIf:       {},
+	KeywordSwitch:   {},
+	KeywordWhile:    {},
+	KeywordFor:      {},
+	KeywordEmit:     {},
+	KeywordRemove:   {},
+}
+
+// canRecover returns true if the parser may recover from the given error.
+// Only syntax errors can be recovered from,
+// and only if recovery is enabled and the parser is not buffering.
+func (p *parser) canRecover(err error) bool {
+	if !p.config.ErrorRecoveryEnabled {
+		return false
+	}
+	if _, ok := err.(ParseError); !ok {
+		return false
+	}
+	return len(p.backtrackingCursorStack) == 0
+}
+
+func (p *parser) isDeclarationSyncToken() bool {
+	switch p.current.Type {
+	case lexer.TokenPragma:
+		return true
+
+	case lexer.TokenIdentifier:
+		keyword := string(p.currentTokenSource())
+		switch keyword {
+		case KeywordStatic:
+			return p.config.StaticModifierEnabled
+		case KeywordNative:
+			return p.config.NativeModifierEnabled
+		}
+		_, ok := declarationSyncKeywords[keyword]
+		return ok
+	}
+
+	return false
+}
+
+func (p *parser) isStatementSyncToken() bool {
+	if p.isDeclarationSyncToken() {
+		return true
+	}
+
+	if !p.current.Is(lexer.TokenIdentifier) {
+		return false
+	}
+
+	_, ok := statementSyncKeywords[string(p.currentTokenSource())]
+	return ok
+}
+
+// synchronize skips tokens until the given function reports a synchronization point,
+// a closing brace ends the enclosing block, or the end of the input is reached.
+// Nested parentheses, brackets, and braces are skipped as a whole.
+//
+// The current token is always skipped if it caused the error,
+// or if it is still at the given start position, to ensure that the parser makes progress.
+//
+// The returned range spans from the start position to the end of the last skipped token,
+// or the position of the error if no token was skipped.
+func (p *parser) synchronize(
+	startPos ast.Position,
+	err ParseError,
+	isEndToken func(token lexer.Token) bool,
+	isSyncToken func() bool,
+) ast.Range {
+
+	endPos := err.EndPosition(p.memoryGauge)
+	if endPos.Compare(startPos) < 0 {
+		endPos = startPos
+	}
+
+	depth := 0
+
+	skip := func() {
+		endPos = p.current.EndPos
+		p.next()
+	}
+
+	// Skip the current token if it caused the error,
+	// or if no progress was made since the start position
+
+	errPos := err.StartPosition()
+	if (p.current.StartPos == startPos || p.current.StartPos == errPos) &&
+		!p.current.Is(lexer.TokenEOF) {
+
+		switch p.current.Type {
+		case lexer.TokenParenOpen, lexer.TokenBracketOpen, lexer.TokenBraceOpen:
+			depth++
+		}
+		skip()
+	}
+
+	for {
+		switch p.current.Type {
+		case lexer.TokenEOF:
+			return ast.NewRange(p.memoryGauge, startPos, endPos)
+
+		case lexer.TokenSpace, lexer.TokenLineComment, lexer.TokenBlockCommentStart:
+			p.skipSpaceAndComments()
+			continue
+
+		case lexer.TokenParenOpen, lexer.TokenBracketOpen, lexer.TokenBraceOpen:
+			depth++
+			skip()
+			continue
+
+		case lexer.TokenParenClose, lexer.TokenBracketClose, lexer.TokenBraceClose:
+			if depth > 0 {
+				depth--
+				skip()
+				continue
+			}
+		}
+
+		if depth == 0 {
+			if isEndToken != nil && isEndToken(p.current) {
+				return ast.NewRange(p.memoryGauge, startPos, endPos)
+			}
+
+			if p.current.Is(lexer.TokenSemicolon) || isSyncToken() {
+				return ast.NewRange(p.memoryGauge, startPos, endPos)
+			}
+		}
+
+		skip()
+	}
+}
+
+// recoverDeclaration reports the given error and skips to the next declaration.
+// It returns an invalid declaration covering the skipped source,
+// or the error, if recovery is not possible.
+func (p *parser) recoverDeclaration(
+	startPos ast.Position,
+	err error,
+	endTokenType lexer.TokenType,
+) (*ast.InvalidDeclaration, error) {
+	if !p.canRecover(err) {
+		return nil, err
+	}
+
+	p.report(err)
+
+	declarationRange := p.synchronize(
+		startPos,
+		err.(ParseError),
+		func(token lexer.Token) bool {
+			return token.Is(endTokenType)
+		},
+		p.isDeclarationSyncToken,
+	)
+
+	return ast.NewInvalidDeclaration(p.memoryGauge, declarationRange), nil
+}
+
+// recoverStatement reports the given error and skips to the next statement.
+// It returns an invalid statement covering the skipped source,
+// or the error, if recovery is not possible.
+func (p *parser) recoverStatement(
+	startPos ast.Position,
+	err error,
+	isEndToken func(token lexer.Token) bool,
+) (*ast.InvalidStatement, error) {
+	if !p.canRecover(err) {
+		return nil, err
+	}
+
+	p.report(err)
+
+	statementRange := p.synchronize(
+		startPos,
+		err.(ParseError),
+		func(token lexer.Token) bool {
+			return token.Is(lexer.TokenBraceClose) ||
+				(isEndToken != nil && isEndToken(token))
+		},
+		p.isStatementSyncToken,
+	)
+
+	return ast.NewInvalidStatement(p.memoryGauge, statementRange), nil
+}
+
+// mustOneOrInsert is like mustOne, but if the input ended prematurely,
+// and the parser may recover, the error is reported and the missing token is inserted.
+// The inserted token has an empty range at the end of the input.
+func (p *parser) mustOneOrInsert(tokenType lexer.TokenType) (lexer.Token, error) {
+	if !p.current.Is(lexer.TokenEOF) {
+		return p.mustOne(tokenType)
+	}
+
+	err := p.syntaxError("expected token %s", tokenType)
+	if !p.canRecover(err) {
+		return lexer.Token{}, err
+	}
+
+	p.report(err)
+
+	pos := p.current.StartPos
+	return lexer.Token{
+		Type:  tokenType,
+		Range: ast.NewRange(p.memoryGauge, pos, pos),
+	}, nil
+}
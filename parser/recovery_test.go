@@ -0,0 +1,218 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/ast"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+)
+
+var recoveryConfig = Config{
+	ErrorRecoveryEnabled: true,
+}
+
+func testParseDeclarationsWithRecovery(s string) ([]ast.Declaration, []error) {
+	return ParseDeclarations(nil, []byte(s), recoveryConfig)
+}
+
+func testParseStatementsWithRecovery(s string) ([]ast.Statement, []error) {
+	return ParseStatements(nil, []byte(s), recoveryConfig)
+}
+
+func TestParseRecovery(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("declaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseDeclarationsWithRecovery(`
+fun a() {}
+fun b( {}
+fun c() {}`,
+		)
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected parameter or end of parameter list, got '{'",
+					Pos:     ast.Position{Offset: 19, Line: 3, Column: 7},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 3)
+
+		require.IsType(t, &ast.FunctionDeclaration{}, result[0])
+		require.Equal(t, "a", result[0].DeclarationIdentifier().Identifier)
+
+		AssertEqualWithDiff(t,
+			&ast.InvalidDeclaration{
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 12, Line: 3, Column: 0},
+					EndPos:   ast.Position{Offset: 20, Line: 3, Column: 8},
+				},
+			},
+			result[1],
+		)
+
+		require.IsType(t, &ast.FunctionDeclaration{}, result[2])
+		require.Equal(t, "c", result[2].DeclarationIdentifier().Identifier)
+	})
+
+	t.Run("member", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseDeclarationsWithRecovery(`
+struct S {
+    let x: Int =
+    fun f() {}
+}`,
+		)
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "unexpected token: '='",
+					Pos:     ast.Position{Offset: 27, Line: 3, Column: 15},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 1)
+		require.IsType(t, &ast.CompositeDeclaration{}, result[0])
+
+		members := result[0].DeclarationMembers()
+		require.Len(t, members.Declarations(), 3)
+		require.Len(t, members.Fields(), 1)
+		require.IsType(t, &ast.InvalidDeclaration{}, members.Declarations()[1])
+		require.Len(t, members.Functions(), 1)
+	})
+
+	t.Run("statement", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseStatementsWithRecovery(`
+let x = (1 + )
+return x
+`,
+		)
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "unexpected token in expression: ')'",
+					Pos:     ast.Position{Offset: 15, Line: 2, Column: 14},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 2)
+		require.IsType(t, &ast.InvalidStatement{}, result[0])
+		require.IsType(t, &ast.ReturnStatement{}, result[1])
+	})
+
+	t.Run("stray closing brace", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseDeclarationsWithRecovery("} let x = 1")
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "unexpected token: '}'",
+					Pos:     ast.Position{Offset: 0, Line: 1, Column: 0},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 2)
+
+		AssertEqualWithDiff(t,
+			&ast.InvalidDeclaration{
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+					EndPos:   ast.Position{Offset: 0, Line: 1, Column: 0},
+				},
+			},
+			result[0],
+		)
+
+		require.IsType(t, &ast.VariableDeclaration{}, result[1])
+	})
+
+	t.Run("missing closing brace", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseDeclarationsWithRecovery("fun a() {\n    let x = 1")
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected token '}'",
+					Pos:     ast.Position{Offset: 23, Line: 2, Column: 13},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 1)
+		require.IsType(t, &ast.FunctionDeclaration{}, result[0])
+
+		functionDeclaration := result[0].(*ast.FunctionDeclaration)
+		require.Len(t, functionDeclaration.FunctionBlock.Block.Statements, 1)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := testParseDeclarations(`
+fun a() {}
+fun b( {}
+fun c() {}`,
+		)
+
+		AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected parameter or end of parameter list, got '{'",
+					Pos:     ast.Position{Offset: 19, Line: 3, Column: 7},
+				},
+			},
+			errs,
+		)
+
+		require.Len(t, result, 1)
+		require.IsType(t, &ast.FunctionDeclaration{}, result[0])
+	})
+}
@@ -40,8 +40,13 @@ func parseStatements(p *parser, isEndToken func(token lexer.Token) bool) (statem
 				return
 			}
 
+			startPos := p.current.StartPos
+
 			var statement ast.Statement
 			statement, err = parseStatement(p)
+			if err != nil {
+				statement, err = p.recoverStatement(startPos, err, isEndToken)
+			}
 			if err != nil || statement == nil {
 				return
 			}
@@ -492,7 +497,7 @@ func parseBlock(p *parser) (*ast.Block, error) {
 		return nil, err
 	}
 
-	endToken, err := p.mustOne(lexer.TokenBraceClose)
+	endToken, err := p.mustOneOrInsert(lexer.TokenBraceClose)
 	if err != nil {
 		return nil, err
 	}
@@ -549,7 +554,7 @@ func parseFunctionBlock(p *parser) (*ast.FunctionBlock, error) {
 		return nil, err
 	}
 
-	endToken, err := p.mustOne(lexer.TokenBraceClose)
+	endToken, err := p.mustOneOrInsert(lexer.TokenBraceClose)
 	if err != nil {
 		return nil, err
 	}
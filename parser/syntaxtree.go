@@ -0,0 +1,448 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser/lexer"
+)
+
+//go:generate stringer -type=TriviaKind
+
+// TriviaKind is the kind of trivia, i.e. source which is not significant for the AST.
+type TriviaKind uint8
+
+const (
+	// TriviaKindUnknown is source which the lexer could not turn into tokens,
+	// e.g. unrecognized characters
+	TriviaKindUnknown TriviaKind = iota
+	TriviaKindSpace
+	TriviaKindLineComment
+	TriviaKindBlockComment
+)
+
+// Trivia is whitespace, a comment, or unrecognized source
+type Trivia struct {
+	ast.Range
+	Kind TriviaKind
+}
+
+func (t Trivia) IsComment() bool {
+	switch t.Kind {
+	case TriviaKindLineComment, TriviaKindBlockComment:
+		return true
+	}
+	return false
+}
+
+// SyntaxToken is a significant token, together with the trivia surrounding it.
+//
+// The trailing trivia of a token is the trivia following it on the same line.
+// All other trivia preceding a token is its leading trivia.
+type SyntaxToken struct {
+	LeadingTrivia  []Trivia
+	TrailingTrivia []Trivia
+	ast.Range
+	Type lexer.TokenType
+}
+
+// SyntaxTree is a lossless representation of a program:
+// In addition to the AST, it contains all tokens of the source code,
+// including whitespace and comments.
+//
+// Printing the syntax tree reproduces the source code exactly,
+// which allows tools like formatters and refactoring tools
+// to edit the source code without losing comments or formatting.
+type SyntaxTree struct {
+	// Program is the AST of the source code.
+	// It may be partial, if parsing failed
+	Program *ast.Program
+	// Code is the source code
+	Code []byte
+	// Tokens are the significant tokens of the source code, in order
+	Tokens []SyntaxToken
+	// EndTrivia is the trivia after the last token
+	EndTrivia []Trivia
+}
+
+// ParseSyntaxTree parses the given code into a lossless syntax tree.
+//
+// The syntax tree is also returned if parsing fails,
+// so the valid parts of the program can still be inspected.
+func ParseSyntaxTree(
+	memoryGauge common.MemoryGauge,
+	code []byte,
+	config Config,
+) (
+	*SyntaxTree,
+	error,
+) {
+	tokens, err := lexer.Lex(code, memoryGauge)
+	if err != nil {
+		return nil, err
+	}
+	defer tokens.Reclaim()
+
+	syntaxTokens, endTrivia := newSyntaxTokens(tokens)
+
+	// Rewind the token stream, so it can be parsed
+	tokens.Revert(0)
+
+	program, err := ParseProgramFromTokenStream(memoryGauge, tokens, config)
+
+	return &SyntaxTree{
+		Program:   program,
+		Code:      code,
+		Tokens:    syntaxTokens,
+		EndTrivia: endTrivia,
+	}, err
+}
+
+// syntaxItem is either a significant token or trivia, used while building the syntax tokens
+type syntaxItem struct {
+	// tokenRange is the range of the item, if it was produced by the lexer
+	tokenRange *ast.Range
+	tokenType  lexer.TokenType
+	start      int
+	end        int
+	trivia     bool
+	kind       TriviaKind
+}
+
+// newSyntaxTokens reads the whole token stream
+// and groups the tokens into significant tokens and trivia.
+//
+// Source which is not covered by any token, e.g. after an unrecognized character,
+// is recorded as unknown trivia, so that the syntax tree always covers the whole source.
+func newSyntaxTokens(tokens lexer.TokenStream) ([]SyntaxToken, []Trivia) {
+	code := tokens.Input()
+
+	var items []syntaxItem
+	covered := 0
+
+	add := func(item syntaxItem) {
+		if item.start > covered {
+			items = append(items, syntaxItem{
+				start:  covered,
+				end:    item.start,
+				trivia: true,
+				kind:   TriviaKindUnknown,
+			})
+		}
+		items = append(items, item)
+		covered = item.end
+	}
+
+	blockCommentDepth := 0
+	var blockCommentStart ast.Position
+
+	for {
+		token := tokens.Next()
+		if token.Is(lexer.TokenEOF) {
+			break
+		}
+
+		start := token.StartPos.Offset
+		end := token.EndPos.Offset + 1
+
+		// Skip error tokens, the erroneous source is recorded as unknown trivia.
+		// Also skip tokens which do not cover any source,
+		// e.g. empty block comment content
+
+		if token.Is(lexer.TokenError) || end <= start || start < covered {
+			continue
+		}
+
+		if blockCommentDepth > 0 {
+			switch token.Type {
+			case lexer.TokenBlockCommentStart:
+				blockCommentDepth++
+			case lexer.TokenBlockCommentEnd:
+				blockCommentDepth--
+			}
+
+			if blockCommentDepth == 0 {
+				add(syntaxItem{
+					tokenRange: &ast.Range{
+						StartPos: blockCommentStart,
+						EndPos:   token.EndPos,
+					},
+					start:  blockCommentStart.Offset,
+					end:    end,
+					trivia: true,
+					kind:   TriviaKindBlockComment,
+				})
+			}
+			continue
+		}
+
+		switch token.Type {
+		case lexer.TokenSpace:
+			add(syntaxItem{
+				tokenRange: &token.Range,
+				start:      start,
+				end:        end,
+				trivia:     true,
+				kind:       TriviaKindSpace,
+			})
+
+		case lexer.TokenLineComment:
+			add(syntaxItem{
+				tokenRange: &token.Range,
+				start:      start,
+				end:        end,
+				trivia:     true,
+				kind:       TriviaKindLineComment,
+			})
+
+		case lexer.TokenBlockCommentStart:
+			blockCommentDepth = 1
+			blockCommentStart = token.StartPos
+
+		default:
+			add(syntaxItem{
+				tokenRange: &token.Range,
+				tokenType:  token.Type,
+				start:      start,
+				end:        end,
+			})
+		}
+	}
+
+	// An unterminated block comment extends to the end of the source
+
+	if blockCommentDepth > 0 {
+		add(syntaxItem{
+			start:  blockCommentStart.Offset,
+			end:    len(code),
+			trivia: true,
+			kind:   TriviaKindBlockComment,
+		})
+	}
+
+	if covered < len(code) {
+		add(syntaxItem{
+			start:  covered,
+			end:    len(code),
+			trivia: true,
+			kind:   TriviaKindUnknown,
+		})
+	}
+
+	positions := newPositionTable(code)
+
+	itemRange := func(item syntaxItem) ast.Range {
+		if item.tokenRange != nil {
+			return *item.tokenRange
+		}
+		return ast.Range{
+			StartPos: positions.position(item.start),
+			EndPos:   positions.position(item.end - 1),
+		}
+	}
+
+	var syntaxTokens []SyntaxToken
+	var pendingTrivia []Trivia
+	trailing := false
+
+	for _, item := range items {
+		if !item.trivia {
+			syntaxTokens = append(syntaxTokens, SyntaxToken{
+				Type:          item.tokenType,
+				Range:         itemRange(item),
+				LeadingTrivia: pendingTrivia,
+			})
+			pendingTrivia = nil
+			trailing = true
+			continue
+		}
+
+		trivia := Trivia{
+			Kind:  item.kind,
+			Range: itemRange(item),
+		}
+
+		// Trivia on the same line as the previous token is trailing trivia,
+		// all trivia starting with the first line break is leading trivia of the next token
+
+		if trailing && !bytes.ContainsRune(code[item.start:item.end], '\n') {
+			lastToken := &syntaxTokens[len(syntaxTokens)-1]
+			lastToken.TrailingTrivia = append(lastToken.TrailingTrivia, trivia)
+			continue
+		}
+
+		trailing = false
+		pendingTrivia = append(pendingTrivia, trivia)
+	}
+
+	return syntaxTokens, pendingTrivia
+}
+
+// positionTable maps offsets to positions.
+// Like the lexer, columns are counted in runes
+type positionTable struct {
+	code       []byte
+	lineStarts []int
+}
+
+func newPositionTable(code []byte) positionTable {
+	lineStarts := []int{0}
+	for offset, b := range code {
+		if b == '\n' {
+			lineStarts = append(lineStarts, offset+1)
+		}
+	}
+	return positionTable{
+		code:       code,
+		lineStarts: lineStarts,
+	}
+}
+
+func (t positionTable) position(offset int) ast.Position {
+	line := sort.Search(len(t.lineStarts), func(i int) bool {
+		return t.lineStarts[i] > offset
+	})
+	lineStart := t.lineStarts[line-1]
+	return ast.Position{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCount(t.code[lineStart:offset]),
+	}
+}
+
+// Text returns the source code of the given range
+func (t *SyntaxTree) Text(r ast.HasPosition) []byte {
+	start := r.StartPosition().Offset
+	end := r.EndPosition(nil).Offset + 1
+	if end <= start {
+		return nil
+	}
+	return t.Code[start:end]
+}
+
+// WriteTo prints the syntax tree, i.e. all tokens and trivia, to the given writer.
+// The output is the original source code.
+func (t *SyntaxTree) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	write := func(r ast.HasPosition) error {
+		n, err := w.Write(t.Text(r))
+		total += int64(n)
+		return err
+	}
+
+	writeTrivia := func(trivia []Trivia) error {
+		for _, t := range trivia {
+			err := write(t)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, token := range t.Tokens {
+		err := writeTrivia(token.LeadingTrivia)
+		if err != nil {
+			return total, err
+		}
+
+		err = write(token)
+		if err != nil {
+			return total, err
+		}
+
+		err = writeTrivia(token.TrailingTrivia)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	err := writeTrivia(t.EndTrivia)
+	return total, err
+}
+
+func (t *SyntaxTree) String() string {
+	var buffer bytes.Buffer
+	_, _ = t.WriteTo(&buffer)
+	return buffer.String()
+}
+
+// tokenIndex returns the index of the first token which starts at or after the given offset
+func (t *SyntaxTree) tokenIndex(offset int) int {
+	return sort.Search(len(t.Tokens), func(i int) bool {
+		return t.Tokens[i].StartPos.Offset >= offset
+	})
+}
+
+// ElementTokens returns the tokens of the given element
+func (t *SyntaxTree) ElementTokens(element ast.HasPosition) []SyntaxToken {
+	startOffset := element.StartPosition().Offset
+	endOffset := element.EndPosition(nil).Offset
+
+	startIndex := t.tokenIndex(startOffset)
+	endIndex := startIndex
+	for endIndex < len(t.Tokens) && t.Tokens[endIndex].EndPos.Offset <= endOffset {
+		endIndex++
+	}
+
+	return t.Tokens[startIndex:endIndex]
+}
+
+// LeadingTrivia returns the trivia preceding the given element,
+// i.e. the leading trivia of its first token
+func (t *SyntaxTree) LeadingTrivia(element ast.HasPosition) []Trivia {
+	tokens := t.ElementTokens(element)
+	if len(tokens) == 0 {
+		return nil
+	}
+	return tokens[0].LeadingTrivia
+}
+
+// TrailingTrivia returns the trivia following the given element on the same line,
+// i.e. the trailing trivia of its last token
+func (t *SyntaxTree) TrailingTrivia(element ast.HasPosition) []Trivia {
+	tokens := t.ElementTokens(element)
+	if len(tokens) == 0 {
+		return nil
+	}
+	return tokens[len(tokens)-1].TrailingTrivia
+}
+
+// Comments returns the comments attached to the given element,
+// i.e. the comments in its leading and trailing trivia
+func (t *SyntaxTree) Comments(element ast.HasPosition) (comments []Trivia) {
+	for _, trivia := range t.LeadingTrivia(element) {
+		if trivia.IsComment() {
+			comments = append(comments, trivia)
+		}
+	}
+	for _, trivia := range t.TrailingTrivia(element) {
+		if trivia.IsComment() {
+			comments = append(comments, trivia)
+		}
+	}
+	return
+}
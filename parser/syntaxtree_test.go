@@ -0,0 +1,175 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/parser/lexer"
+)
+
+func TestParseSyntaxTreeRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	tests := map[string]string{
+		"empty":       ``,
+		"spaces only": "  \n\t\r\n ",
+		"declarations": `
+          access(all) contract C {
+              access(all) let x: Int
+
+              init() {
+                  self.x = 1 // trailing
+              }
+          }
+        `,
+		"comments": `
+          /// doc
+          fun test() {
+              /* block */ let x = 1 /* nested /* block */ comment */
+              // line
+          }
+          // end`,
+		"unterminated block comment": "let x = 1 /* unterminated",
+		"string template":            `let x = "a\(1 + 2)b"`,
+		"unicode":                    "let x = \"✓\" // ☺\n/* ö */ let y = 1",
+		"invalid identifier":         "let ö = 1\nlet y = 2",
+		"pragma":                     "#allowAccountLinking\n",
+		"syntax error":               "fun test( {\n  let x = }\n",
+		"unrecognized character":     "let x = 1 $ 2",
+		"no trailing newline":        "let x = 1",
+	}
+
+	for name, code := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			tree, _ := ParseSyntaxTree(nil, []byte(code), Config{})
+			require.NotNil(t, tree)
+
+			assert.Equal(t, code, tree.String())
+		})
+	}
+}
+
+func TestParseSyntaxTreeRoundTripFiles(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("..", "sema", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		code, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		tree, err := ParseSyntaxTree(
+			nil,
+			code,
+			Config{
+				TypeParametersEnabled: true,
+				NativeModifierEnabled: true,
+				StaticModifierEnabled: true,
+			},
+		)
+		require.NoError(t, err, path)
+
+		assert.Equal(t, string(code), tree.String(), path)
+	}
+}
+
+func TestParseSyntaxTreeTrivia(t *testing.T) {
+
+	t.Parallel()
+
+	code := strings.TrimLeft(`
+// first
+let x = 1 // one
+
+/* second */
+let y = 2
+`, "\n")
+
+	tree, err := ParseSyntaxTree(nil, []byte(code), Config{})
+	require.NoError(t, err)
+
+	declarations := tree.Program.Declarations()
+	require.Len(t, declarations, 2)
+
+	text := func(trivia []Trivia) (texts []string) {
+		for _, t := range trivia {
+			texts = append(texts, string(tree.Text(t)))
+		}
+		return
+	}
+
+	x := declarations[0]
+
+	assert.Equal(t,
+		[]string{"// first", "\n"},
+		text(tree.LeadingTrivia(x)),
+	)
+	assert.Equal(t,
+		[]string{" ", "// one"},
+		text(tree.TrailingTrivia(x)),
+	)
+	assert.Equal(t,
+		[]string{"// first", "// one"},
+		text(tree.Comments(x)),
+	)
+
+	y := declarations[1]
+
+	assert.Equal(t,
+		[]string{"\n\n", "/* second */", "\n"},
+		text(tree.LeadingTrivia(y)),
+	)
+	assert.Equal(t,
+		[]string{"/* second */"},
+		text(tree.Comments(y)),
+	)
+
+	tokens := tree.ElementTokens(y)
+	require.Len(t, tokens, 4)
+	assert.Equal(t, lexer.TokenIdentifier, tokens[0].Type)
+	assert.Equal(t, lexer.TokenDecimalIntegerLiteral, tokens[3].Type)
+
+	assert.Equal(t,
+		[]Trivia{
+			{
+				Kind: TriviaKindSpace,
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 49, Line: 5, Column: 9},
+					EndPos:   ast.Position{Offset: 49, Line: 5, Column: 9},
+				},
+			},
+		},
+		tree.EndTrivia,
+	)
+}
@@ -125,8 +125,9 @@ func sortExcerpts(excerpts []excerpt) {
 }
 
 type ErrorPrettyPrinter struct {
-	writer   Writer
-	useColor bool
+	writer     Writer
+	useColor   bool
+	errorCodes bool
 }
 
 func NewErrorPrettyPrinter(writer Writer, useColor bool) ErrorPrettyPrinter {
@@ -136,6 +137,13 @@ func NewErrorPrettyPrinter(writer Writer, useColor bool) ErrorPrettyPrinter {
 	}
 }
 
+// WithErrorCodes returns a copy of the printer which also prints the error codes
+// of errors which provide one, e.g. `error[E2001]: invalid pragma`
+func (p ErrorPrettyPrinter) WithErrorCodes() ErrorPrettyPrinter {
+	p.errorCodes = true
+	return p
+}
+
 func (p ErrorPrettyPrinter) writeString(str string) {
 	_, err := p.writer.WriteString(str)
 	if err != nil {
@@ -218,6 +226,12 @@ func (p ErrorPrettyPrinter) prettyPrintError(err error, location common.Location
 		prefix = secondaryError.Prefix()
 	}
 
+	if p.errorCodes {
+		if code, ok := errors.GetErrorCode(err); ok {
+			prefix = fmt.Sprintf("%s[%s]", prefix, code)
+		}
+	}
+
 	p.writeString(FormatErrorMessage(prefix, err.Error(), p.useColor))
 
 	message := ""
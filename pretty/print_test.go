@@ -26,6 +26,7 @@ import (
 
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
 )
 
 type testError struct {
@@ -36,6 +37,14 @@ func (testError) Error() string {
 	return "test error"
 }
 
+type testErrorWithCode struct {
+	testError
+}
+
+func (testErrorWithCode) ErrorCode() errors.ErrorCode {
+	return "E0001"
+}
+
 func TestPrintBrokenCode(t *testing.T) {
 
 	t.Parallel()
@@ -112,3 +121,85 @@ func TestPrintTabs(t *testing.T) {
 		sb.String(),
 	)
 }
+
+func TestPrintErrorCodes(t *testing.T) {
+
+	t.Parallel()
+
+	const code = "let x = 1"
+
+	location := common.StringLocation("test")
+
+	err := testErrorWithCode{
+		testError: testError{
+			Range: ast.Range{
+				StartPos: ast.Position{
+					Line:   1,
+					Column: 4,
+				},
+				EndPos: ast.Position{
+					Line:   1,
+					Column: 4,
+				},
+			},
+		},
+	}
+
+	codes := map[common.Location][]byte{
+		location: []byte(code),
+	}
+
+	t.Run("without error codes", func(t *testing.T) {
+
+		t.Parallel()
+
+		var sb strings.Builder
+		printer := NewErrorPrettyPrinter(&sb, false)
+		printErr := printer.PrettyPrintError(err, location, codes)
+		require.NoError(t, printErr)
+		require.Equal(t,
+			"error: test error\n"+
+				" --> test:1:4\n"+
+				"  |\n"+
+				"1 | let x = 1\n"+
+				"  |     ^\n",
+			sb.String(),
+		)
+	})
+
+	t.Run("with error codes", func(t *testing.T) {
+
+		t.Parallel()
+
+		var sb strings.Builder
+		printer := NewErrorPrettyPrinter(&sb, false).WithErrorCodes()
+		printErr := printer.PrettyPrintError(err, location, codes)
+		require.NoError(t, printErr)
+		require.Equal(t,
+			"error[E0001]: test error\n"+
+				" --> test:1:4\n"+
+				"  |\n"+
+				"1 | let x = 1\n"+
+				"  |     ^\n",
+			sb.String(),
+		)
+	})
+
+	t.Run("with error codes, error without code", func(t *testing.T) {
+
+		t.Parallel()
+
+		var sb strings.Builder
+		printer := NewErrorPrettyPrinter(&sb, false).WithErrorCodes()
+		printErr := printer.PrettyPrintError(err.testError, location, codes)
+		require.NoError(t, printErr)
+		require.Equal(t,
+			"error: test error\n"+
+				" --> test:1:4\n"+
+				"  |\n"+
+				"1 | let x = 1\n"+
+				"  |     ^\n",
+			sb.String(),
+		)
+	})
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/interpreter"
 	. "github.com/onflow/cadence/runtime"
 	. "github.com/onflow/cadence/test_utils/runtime_utils"
@@ -405,6 +406,10 @@ func TestRuntimeAccountAttachedExport(t *testing.T) {
 		"A.0000000000000001.Test.A()",
 		attachment.String(),
 	)
+
+	attachments := v.(cadence.Resource).Attachments()
+	require.Len(t, attachments, 1)
+	require.Equal(t, attachment, attachments[0])
 }
 
 func TestRuntimeAccountAttachmentSaveAndBorrow(t *testing.T) {
@@ -875,3 +880,110 @@ func TestRuntimeAttachmentStorage(t *testing.T) {
 		assert.Equal(t, cadence.NewInt(3), result)
 	})
 }
+
+func TestRuntimeAttachmentArgumentImport(t *testing.T) {
+
+	t.Parallel()
+
+	const script = `
+        access(all) struct S {
+            access(all) let x: Int
+
+            init(x: Int) {
+                self.x = x
+            }
+        }
+
+        access(all) struct T {}
+
+        access(all) attachment A for S {
+            access(all) let y: Int
+
+            init(y: Int) {
+                self.y = y
+            }
+
+            access(all) fun sum(): Int {
+                return base.x + self.y
+            }
+        }
+
+        access(all) attachment B for T {}
+
+        access(all) fun main(s: S): Int {
+            return s[A]!.sum()
+        }
+    `
+
+	newArgument := func(attachmentIdentifier string) string {
+		return `
+          {
+            "type": "Struct",
+            "value": {
+              "id": "s.0000000000000000000000000000000000000000000000000000000000000000.S",
+              "fields": [
+                {
+                  "name": "x",
+                  "value": {"type": "Int", "value": "1"}
+                }
+              ],
+              "attachments": [
+                {
+                  "type": "Attachment",
+                  "value": {
+                    "id": "s.0000000000000000000000000000000000000000000000000000000000000000.` + attachmentIdentifier + `",
+                    "fields": [
+                      {
+                        "name": "y",
+                        "value": {"type": "Int", "value": "2"}
+                      }
+                    ]
+                  }
+                }
+              ]
+            }
+          }
+        `
+	}
+
+	executeScript := func(argument string) (cadence.Value, error) {
+		rt := NewTestInterpreterRuntime()
+
+		runtimeInterface := &TestRuntimeInterface{
+			Storage: NewTestLedger(nil, nil),
+			OnDecodeArgument: func(b []byte, t cadence.Type) (value cadence.Value, err error) {
+				return json.Decode(nil, b)
+			},
+		}
+
+		return rt.ExecuteScript(
+			Script{
+				Source: []byte(script),
+				Arguments: [][]byte{
+					[]byte(argument),
+				},
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := executeScript(newArgument("A"))
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(3), value)
+	})
+
+	t.Run("invalid base", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := executeScript(newArgument("B"))
+		require.ErrorContains(t, err, "not attachable to value of type S")
+	})
+}
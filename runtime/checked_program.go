@@ -0,0 +1,299 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+)
+
+// CheckedProgramEncodingVersion is the version of the checked program encoding.
+// It must be incremented when the encoding changes.
+// Changes to the encoding of the elaboration are covered by sema.ElaborationEncodingVersion
+const CheckedProgramEncodingVersion uint64 = 1
+
+// CodeHash is the hash of the code of a program
+type CodeHash [32]byte
+
+// NewCodeHash returns the hash of the given code.
+// Like the code hash of account contracts, it is the SHA3-256 hash of the code
+func NewCodeHash(code []byte) CodeHash {
+	return sha3.Sum256(code)
+}
+
+func (h CodeHash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// CheckedProgramStore is a persistent store of checked programs.
+//
+// It allows reusing the result of checking a program across executions,
+// without parsing and checking the program and all its imports again.
+//
+// Stored programs are keyed by the location and the hash of the code.
+// Stores may choose to only persist programs at certain locations, e.g. contracts.
+// Errors returned by the store abort the execution.
+type CheckedProgramStore interface {
+	// GetCheckedProgram returns the encoded checked program
+	// for the given location and code hash, or nil if there is none
+	GetCheckedProgram(location common.Location, codeHash CodeHash) ([]byte, error)
+	// SetCheckedProgram stores the encoded checked program
+	// for the given location and code hash
+	SetCheckedProgram(location common.Location, codeHash CodeHash, encoded []byte) error
+}
+
+// CheckedProgramDependency is a program which a checked program depends on,
+// i.e. a program which it imports directly or indirectly
+type CheckedProgramDependency struct {
+	Location common.Location
+	CodeHash CodeHash
+}
+
+// CheckedProgram is the persisted form of a checked program.
+//
+// It contains the encoded elaboration of the program (see sema.EncodeElaboration),
+// and the hashes of the code of the program and of all its dependencies.
+// A checked program is only valid while the code of the program and all its dependencies are unchanged
+type CheckedProgram struct {
+	Location     common.Location
+	CodeHash     CodeHash
+	Dependencies []CheckedProgramDependency
+	Elaboration  []byte
+}
+
+type encodedCheckedProgramDependency struct {
+	_        struct{} `cbor:",toarray"`
+	Location string
+	CodeHash []byte
+}
+
+type encodedCheckedProgram struct {
+	_              struct{} `cbor:",toarray"`
+	Version        uint64
+	CadenceVersion string
+	Location       string
+	CodeHash       []byte
+	Dependencies   []encodedCheckedProgramDependency
+	Elaboration    []byte
+}
+
+// EncodeCheckedProgram encodes the given checked program.
+//
+// The encoding contains the version of the encoding and the version of Cadence,
+// so checked programs are invalidated when Cadence is updated
+func EncodeCheckedProgram(program *CheckedProgram) ([]byte, error) {
+	dependencies := make([]encodedCheckedProgramDependency, 0, len(program.Dependencies))
+	for _, dependency := range program.Dependencies {
+		dependencies = append(
+			dependencies,
+			encodedCheckedProgramDependency{
+				Location: encodeCheckedProgramLocation(dependency.Location),
+				CodeHash: dependency.CodeHash[:],
+			},
+		)
+	}
+
+	return cbor.Marshal(encodedCheckedProgram{
+		Version:        CheckedProgramEncodingVersion,
+		CadenceVersion: cadence.Version,
+		Location:       encodeCheckedProgramLocation(program.Location),
+		CodeHash:       program.CodeHash[:],
+		Dependencies:   dependencies,
+		Elaboration:    program.Elaboration,
+	})
+}
+
+// DecodeCheckedProgram decodes a checked program encoded with EncodeCheckedProgram.
+// Checked programs encoded with a different version of the encoding or of Cadence are rejected
+func DecodeCheckedProgram(memoryGauge common.MemoryGauge, data []byte) (*CheckedProgram, error) {
+	var encoded encodedCheckedProgram
+	err := cbor.Unmarshal(data, &encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checked program: %w", err)
+	}
+
+	if encoded.Version != CheckedProgramEncodingVersion {
+		return nil, fmt.Errorf(
+			"invalid checked program: unsupported version: got %d, expected %d",
+			encoded.Version,
+			CheckedProgramEncodingVersion,
+		)
+	}
+
+	if encoded.CadenceVersion != cadence.Version {
+		return nil, fmt.Errorf(
+			"invalid checked program: unsupported Cadence version: got %s, expected %s",
+			encoded.CadenceVersion,
+			cadence.Version,
+		)
+	}
+
+	location, err := decodeCheckedProgramLocation(memoryGauge, encoded.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	codeHash, err := decodeCheckedProgramCodeHash(encoded.CodeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]CheckedProgramDependency, 0, len(encoded.Dependencies))
+	for _, encodedDependency := range encoded.Dependencies {
+		dependencyLocation, err := decodeCheckedProgramLocation(memoryGauge, encodedDependency.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencyCodeHash, err := decodeCheckedProgramCodeHash(encodedDependency.CodeHash)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies = append(
+			dependencies,
+			CheckedProgramDependency{
+				Location: dependencyLocation,
+				CodeHash: dependencyCodeHash,
+			},
+		)
+	}
+
+	return &CheckedProgram{
+		Location:     location,
+		CodeHash:     codeHash,
+		Dependencies: dependencies,
+		Elaboration:  encoded.Elaboration,
+	}, nil
+}
+
+// encodeCheckedProgramLocation encodes the given location as a type ID,
+// which can be decoded using common.DecodeTypeID.
+// The name of an address location is encoded as the qualified identifier,
+// all other locations use a placeholder qualified identifier
+func encodeCheckedProgramLocation(location common.Location) string {
+	qualifiedIdentifier := "_"
+	if addressLocation, ok := location.(common.AddressLocation); ok {
+		qualifiedIdentifier = addressLocation.Name
+	}
+	return string(location.TypeID(nil, qualifiedIdentifier))
+}
+
+func decodeCheckedProgramLocation(memoryGauge common.MemoryGauge, encoded string) (common.Location, error) {
+	location, _, err := common.DecodeTypeID(memoryGauge, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checked program location: %w", err)
+	}
+	return location, nil
+}
+
+func decodeCheckedProgramCodeHash(encoded []byte) (codeHash CodeHash, err error) {
+	if len(encoded) != len(codeHash) {
+		return codeHash, fmt.Errorf("invalid checked program code hash: %x", encoded)
+	}
+	copy(codeHash[:], encoded)
+	return codeHash, nil
+}
+
+func sortCheckedProgramDependencies(dependencies []CheckedProgramDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].Location.ID() < dependencies[j].Location.ID()
+	})
+}
+
+// DirectoryCheckedProgramStore is a CheckedProgramStore
+// which stores checked programs as files in a directory
+type DirectoryCheckedProgramStore struct {
+	path string
+}
+
+var _ CheckedProgramStore = &DirectoryCheckedProgramStore{}
+
+// NewDirectoryCheckedProgramStore returns a new store of checked programs
+// in the directory with the given path. The directory is created if it does not exist
+func NewDirectoryCheckedProgramStore(path string) (*DirectoryCheckedProgramStore, error) {
+	err := os.MkdirAll(path, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DirectoryCheckedProgramStore{
+		path: path,
+	}, nil
+}
+
+func (s *DirectoryCheckedProgramStore) filePath(location common.Location, codeHash CodeHash) string {
+	locationHash := sha3.Sum256([]byte(location.ID()))
+	name := fmt.Sprintf(
+		"%s-%s",
+		hex.EncodeToString(locationHash[:]),
+		codeHash,
+	)
+	return filepath.Join(s.path, name)
+}
+
+func (s *DirectoryCheckedProgramStore) GetCheckedProgram(
+	location common.Location,
+	codeHash CodeHash,
+) (
+	[]byte,
+	error,
+) {
+	encoded, err := os.ReadFile(s.filePath(location, codeHash))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return encoded, err
+}
+
+func (s *DirectoryCheckedProgramStore) SetCheckedProgram(
+	location common.Location,
+	codeHash CodeHash,
+	encoded []byte,
+) error {
+	// Write to a temporary file first, and then rename it,
+	// so concurrent readers never observe a partially written file
+
+	file, err := os.CreateTemp(s.path, "tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+
+	_, err = file.Write(encoded)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, s.filePath(location, codeHash))
+}
@@ -0,0 +1,330 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	. "github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/sema"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+type testCheckedProgramStoreKey struct {
+	locationID string
+	codeHash   CodeHash
+}
+
+type testCheckedProgramStore struct {
+	programs map[testCheckedProgramStoreKey][]byte
+	hits     int
+}
+
+var _ CheckedProgramStore = &testCheckedProgramStore{}
+
+func newTestCheckedProgramStore() *testCheckedProgramStore {
+	return &testCheckedProgramStore{
+		programs: map[testCheckedProgramStoreKey][]byte{},
+	}
+}
+
+func (s *testCheckedProgramStore) GetCheckedProgram(location common.Location, codeHash CodeHash) ([]byte, error) {
+	encoded := s.programs[testCheckedProgramStoreKey{
+		locationID: location.ID(),
+		codeHash:   codeHash,
+	}]
+	if encoded != nil {
+		s.hits++
+	}
+	return encoded, nil
+}
+
+func (s *testCheckedProgramStore) SetCheckedProgram(location common.Location, codeHash CodeHash, encoded []byte) error {
+	s.programs[testCheckedProgramStoreKey{
+		locationID: location.ID(),
+		codeHash:   codeHash,
+	}] = encoded
+	return nil
+}
+
+func TestRuntimeCheckedProgramStore(t *testing.T) {
+
+	t.Parallel()
+
+	store := newTestCheckedProgramStore()
+
+	config := DefaultTestInterpreterConfig
+	config.CheckedProgramStore = store
+	runtime := NewTestInterpreterRuntimeWithConfig(config)
+
+	baseLocation := common.StringLocation("base")
+	importedLocation := common.StringLocation("imported")
+
+	codes := map[common.Location][]byte{
+		baseLocation: []byte(`
+          access(all) struct Answer {
+              access(all) let value: Int
+
+              init(value: Int) {
+                  pre { value > 0 }
+                  self.value = value
+              }
+          }
+        `),
+		importedLocation: []byte(`
+          import "base"
+
+          access(all) fun answer(base: Int): Answer {
+              post { result.value > before(base) }
+              let values: [Int] = [base]
+              values.append(2)
+              var sum = 0
+              for value in values {
+                  sum = sum + value
+              }
+              return Answer(value: sum)
+          }
+        `),
+	}
+
+	script := []byte(`
+      import "imported"
+
+      access(all) fun main(): Int {
+          return answer(base: 40).value
+      }
+    `)
+
+	var checked []common.Location
+
+	nextScriptLocation := NewScriptLocationGenerator()
+
+	execute := func() (cadence.Value, error) {
+		checked = nil
+		store.hits = 0
+
+		// Use a new runtime interface for each execution,
+		// so programs are not cached in memory across executions
+		runtimeInterface := &TestRuntimeInterface{
+			OnGetCode: func(location Location) ([]byte, error) {
+				code, ok := codes[location]
+				if !ok {
+					return nil, fmt.Errorf("unknown location: %s", location)
+				}
+				return code, nil
+			},
+			OnProgramChecked: func(location Location, _ time.Duration) {
+				checked = append(checked, location)
+			},
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextScriptLocation(),
+			},
+		)
+	}
+
+	t.Run("first execution checks all programs", func(t *testing.T) {
+
+		value, err := execute()
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(42), value)
+
+		assert.ElementsMatch(t,
+			[]common.Location{
+				common.ScriptLocation{0x1},
+				importedLocation,
+				baseLocation,
+			},
+			checked,
+		)
+		assert.Equal(t, 0, store.hits)
+	})
+
+	t.Run("imports are loaded from the store", func(t *testing.T) {
+
+		value, err := execute()
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(42), value)
+
+		assert.Equal(t,
+			[]common.Location{
+				common.ScriptLocation{0x2},
+			},
+			checked,
+		)
+		assert.Equal(t, 2, store.hits)
+	})
+
+	t.Run("dependency update invalidates stored programs", func(t *testing.T) {
+
+		codes[baseLocation] = []byte(`
+          access(all) struct Answer {
+              access(all) let value: Int
+
+              init(value: Int) {
+                  self.value = value * 2
+              }
+          }
+        `)
+
+		value, err := execute()
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(84), value)
+
+		assert.ElementsMatch(t,
+			[]common.Location{
+				common.ScriptLocation{0x3},
+				importedLocation,
+				baseLocation,
+			},
+			checked,
+		)
+	})
+
+	t.Run("incompatible dependency update is reported", func(t *testing.T) {
+
+		codes[baseLocation] = []byte(`
+          access(all) struct Answer {
+              access(all) let value: String
+
+              init(value: Int) {
+                  self.value = value.toString()
+              }
+          }
+        `)
+
+		_, err := execute()
+		require.Error(t, err)
+
+		var importedProgramErr *sema.ImportedProgramError
+		require.ErrorAs(t, err, &importedProgramErr)
+		assert.Equal(t, importedLocation, importedProgramErr.Location)
+	})
+}
+
+func TestRuntimeCheckedProgramEncoding(t *testing.T) {
+
+	t.Parallel()
+
+	program := &CheckedProgram{
+		Location: common.AddressLocation{
+			Address: common.MustBytesToAddress([]byte{0x1}),
+			Name:    "Test",
+		},
+		CodeHash: NewCodeHash([]byte("code")),
+		Dependencies: []CheckedProgramDependency{
+			{
+				Location: common.IdentifierLocation("Crypto"),
+				CodeHash: NewCodeHash([]byte("crypto")),
+			},
+			{
+				Location: common.StringLocation("imported"),
+				CodeHash: NewCodeHash([]byte("imported")),
+			},
+		},
+		Elaboration: []byte{0x1, 0x2, 0x3},
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := EncodeCheckedProgram(program)
+		require.NoError(t, err)
+
+		decoded, err := DecodeCheckedProgram(nil, encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, program, decoded)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := EncodeCheckedProgram(program)
+		require.NoError(t, err)
+
+		var items []cbor.RawMessage
+		err = cbor.Unmarshal(encoded, &items)
+		require.NoError(t, err)
+
+		items[0], err = cbor.Marshal(CheckedProgramEncodingVersion + 1)
+		require.NoError(t, err)
+
+		encoded, err = cbor.Marshal(items)
+		require.NoError(t, err)
+
+		_, err = DecodeCheckedProgram(nil, encoded)
+		require.ErrorContains(t, err, "unsupported version")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeCheckedProgram(nil, []byte{0x1})
+		require.Error(t, err)
+	})
+}
+
+func TestRuntimeDirectoryCheckedProgramStore(t *testing.T) {
+
+	t.Parallel()
+
+	store, err := NewDirectoryCheckedProgramStore(t.TempDir())
+	require.NoError(t, err)
+
+	location := common.StringLocation("test")
+	codeHash := NewCodeHash([]byte("code"))
+
+	encoded, err := store.GetCheckedProgram(location, codeHash)
+	require.NoError(t, err)
+	assert.Nil(t, encoded)
+
+	err = store.SetCheckedProgram(location, codeHash, []byte{0x1, 0x2})
+	require.NoError(t, err)
+
+	encoded, err = store.GetCheckedProgram(location, codeHash)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x1, 0x2}, encoded)
+
+	// Different code
+
+	encoded, err = store.GetCheckedProgram(location, NewCodeHash([]byte("other")))
+	require.NoError(t, err)
+	assert.Nil(t, encoded)
+
+	// Different location
+
+	encoded, err = store.GetCheckedProgram(common.StringLocation("other"), codeHash)
+	require.NoError(t, err)
+	assert.Nil(t, encoded)
+}
@@ -43,6 +43,12 @@ type checkingEnvironment struct {
 
 	config *sema.Config
 
+	// parserConfig is the configuration used to parse programs
+	parserConfig parser.Config
+
+	// checkedProgramStore, if set, persists checked programs across executions
+	checkedProgramStore CheckedProgramStore
+
 	checkedImports importResolutionResults
 
 	// defaultBaseTypeActivation is the base type activation that applies to all locations by default.
@@ -66,19 +72,24 @@ type checkingEnvironment struct {
 	baseValueActivationsByLocation map[common.Location]*sema.VariableActivation
 }
 
-func newCheckingEnvironment() *checkingEnvironment {
+func newCheckingEnvironment(config Config) *checkingEnvironment {
 	defaultBaseValueActivation := sema.NewVariableActivation(sema.BaseValueActivation)
 	defaultBaseTypeActivation := sema.NewVariableActivation(sema.BaseTypeActivation)
 	env := &checkingEnvironment{
+		parserConfig: parser.Config{
+			TypeParametersEnabled: config.TypeParametersEnabled,
+		},
+		checkedProgramStore:        config.CheckedProgramStore,
 		defaultBaseValueActivation: defaultBaseValueActivation,
 		defaultBaseTypeActivation:  defaultBaseTypeActivation,
 	}
-	env.config = env.newConfig()
+	env.config = env.newConfig(config)
 	return env
 }
 
-func (e *checkingEnvironment) newConfig() *sema.Config {
+func (e *checkingEnvironment) newConfig(config Config) *sema.Config {
 	return &sema.Config{
+		TypeParametersEnabled:            config.TypeParametersEnabled,
 		AccessCheckMode:                  sema.AccessCheckModeStrict,
 		BaseValueActivationHandler:       e.getBaseValueActivation,
 		BaseTypeActivationHandler:        e.getBaseTypeActivation,
@@ -222,6 +233,21 @@ func (e *checkingEnvironment) resolveImport(
 	}, nil
 }
 
+// importedElaboration returns the elaboration of the imported program at the given location
+func (e *checkingEnvironment) importedElaboration(importedLocation common.Location) (*sema.Elaboration, error) {
+	imported, err := e.resolveImport(nil, importedLocation, ast.EmptyRange)
+	if err != nil {
+		return nil, err
+	}
+
+	elaborationImport, ok := imported.(sema.ElaborationImport)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return elaborationImport.Elaboration, nil
+}
+
 func (e *checkingEnvironment) check(
 	location common.Location,
 	program *ast.Program,
@@ -303,15 +329,7 @@ func (e *checkingEnvironment) parseAndCheckProgram(
 
 	// Parse
 
-	reportMetric(
-		func() {
-			program, err = parser.ParseProgram(e, code, parser.Config{})
-		},
-		e.runtimeInterface,
-		func(metrics Metrics, duration time.Duration) {
-			metrics.ProgramParsed(location, duration)
-		},
-	)
+	program, err = e.parseProgram(code, location)
 	if err != nil {
 		return nil, nil, wrapParsingCheckingError(err)
 	}
@@ -326,6 +344,26 @@ func (e *checkingEnvironment) parseAndCheckProgram(
 	return program, elaboration, nil
 }
 
+// parseProgram parses the given program.
+func (e *checkingEnvironment) parseProgram(
+	code []byte,
+	location common.Location,
+) (
+	program *ast.Program,
+	err error,
+) {
+	reportMetric(
+		func() {
+			program, err = parser.ParseProgram(e, code, e.parserConfig)
+		},
+		e.runtimeInterface,
+		func(metrics Metrics, duration time.Duration) {
+			metrics.ProgramParsed(location, duration)
+		},
+	)
+	return
+}
+
 func (e *checkingEnvironment) GetProgram(
 	location Location,
 	storeProgram bool,
@@ -346,6 +384,10 @@ func (e *checkingEnvironment) GetProgram(
 
 // getProgram returns the existing program at the given location, if available.
 // If it is not available, it loads the code, and then parses and checks it.
+//
+// Imports are loaded sequentially, unlike in tools/analysis:
+// The runtime interface is not required to be safe for concurrent use,
+// and memory metering and computation metering must be deterministic.
 func (e *checkingEnvironment) getProgram(
 	location Location,
 	getCode func() ([]byte, error),
@@ -363,7 +405,7 @@ func (e *checkingEnvironment) getProgram(
 
 		e.codesAndPrograms.setCode(location, code)
 
-		parsedProgram, elaboration, err := e.parseAndCheckProgramWithRecovery(
+		parsedProgram, elaboration, err := e.parseAndCheckProgramWithStore(
 			code,
 			location,
 			checkedImports,
@@ -408,6 +450,241 @@ func (e *checkingEnvironment) getProgram(
 	return
 }
 
+// parseAndCheckProgramWithStore parses and checks the given program.
+// If a checked program store is configured,
+// a previously stored checked program is used, if it is still valid,
+// and a newly checked program is stored.
+func (e *checkingEnvironment) parseAndCheckProgramWithStore(
+	code []byte,
+	location common.Location,
+	checkedImports importResolutionResults,
+) (
+	program *ast.Program,
+	elaboration *sema.Elaboration,
+	err error,
+) {
+	store := e.checkedProgramStore
+	if store == nil {
+		return e.parseAndCheckProgramWithRecovery(
+			code,
+			location,
+			checkedImports,
+		)
+	}
+
+	codeHash := NewCodeHash(code)
+
+	program, elaboration, err = e.loadCheckedProgram(
+		store,
+		code,
+		codeHash,
+		location,
+		checkedImports,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if elaboration != nil {
+		return program, elaboration, nil
+	}
+
+	program, elaboration, err = e.parseAndCheckProgramWithRecovery(
+		code,
+		location,
+		checkedImports,
+	)
+	if err != nil {
+		return program, elaboration, err
+	}
+
+	// Recovered programs are not stored,
+	// they are recovered again in each execution
+	if !elaboration.IsRecovered {
+		err = e.storeCheckedProgram(
+			store,
+			program,
+			elaboration,
+			codeHash,
+			location,
+			checkedImports,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return program, elaboration, nil
+}
+
+// loadCheckedProgram loads the checked program for the given code from the given store.
+// If no checked program is stored, or the stored checked program is invalid,
+// e.g. because a dependency changed, then no program and elaboration is returned.
+func (e *checkingEnvironment) loadCheckedProgram(
+	store CheckedProgramStore,
+	code []byte,
+	codeHash CodeHash,
+	location common.Location,
+	checkedImports importResolutionResults,
+) (
+	program *ast.Program,
+	elaboration *sema.Elaboration,
+	err error,
+) {
+	var encoded []byte
+	errors.WrapPanic(func() {
+		encoded, err = store.GetCheckedProgram(location, codeHash)
+	})
+	if err != nil || encoded == nil {
+		return nil, nil, err
+	}
+
+	// Invalid and outdated checked programs are ignored,
+	// the program is checked again and stored again
+
+	checkedProgram, err := DecodeCheckedProgram(e, encoded)
+	if err != nil ||
+		checkedProgram.CodeHash != codeHash ||
+		checkedProgram.Location.ID() != location.ID() {
+
+		//nolint:nilerr
+		return nil, nil, nil
+	}
+
+	for _, dependency := range checkedProgram.Dependencies {
+		dependencyCode, err := getLocationCodeFromInterface(e.runtimeInterface, dependency.Location)
+		if err != nil {
+			return nil, nil, err
+		}
+		if NewCodeHash(dependencyCode) != dependency.CodeHash {
+			return nil, nil, nil
+		}
+	}
+
+	program, err = e.parseProgram(code, location)
+	if err != nil {
+		//nolint:nilerr
+		return nil, nil, nil
+	}
+
+	e.checkedImports = checkedImports
+
+	elaboration, err = sema.DecodeElaboration(
+		checkedProgram.Elaboration,
+		program,
+		e.elaborationEncodingConfig(location),
+	)
+	if err != nil {
+		//nolint:nilerr
+		return nil, nil, nil
+	}
+
+	return program, elaboration, nil
+}
+
+// storeCheckedProgram stores the given checked program in the given store.
+// Programs which elaboration cannot be encoded are not stored.
+func (e *checkingEnvironment) storeCheckedProgram(
+	store CheckedProgramStore,
+	program *ast.Program,
+	elaboration *sema.Elaboration,
+	codeHash CodeHash,
+	location common.Location,
+	checkedImports importResolutionResults,
+) error {
+	e.checkedImports = checkedImports
+
+	encodedElaboration, err := sema.EncodeElaboration(
+		program,
+		elaboration,
+		e.elaborationEncodingConfig(location),
+	)
+	if err != nil {
+		//nolint:nilerr
+		return nil
+	}
+
+	dependencies, err := e.checkedProgramDependencies(elaboration)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := EncodeCheckedProgram(&CheckedProgram{
+		Location:     location,
+		CodeHash:     codeHash,
+		Dependencies: dependencies,
+		Elaboration:  encodedElaboration,
+	})
+	if err != nil {
+		return err
+	}
+
+	errors.WrapPanic(func() {
+		err = store.SetCheckedProgram(location, codeHash, encoded)
+	})
+	return err
+}
+
+// checkedProgramDependencies returns the programs which the program with the given elaboration
+// imports directly or indirectly, and the hashes of their code.
+func (e *checkingEnvironment) checkedProgramDependencies(
+	elaboration *sema.Elaboration,
+) (
+	[]CheckedProgramDependency,
+	error,
+) {
+	var dependencies []CheckedProgramDependency
+	seen := map[common.Location]struct{}{}
+
+	elaborations := []*sema.Elaboration{elaboration}
+	for len(elaborations) > 0 {
+		current := elaborations[len(elaborations)-1]
+		elaborations = elaborations[:len(elaborations)-1]
+
+		for _, resolvedLocations := range current.AllImportDeclarationsResolvedLocations() { //nolint:maprange
+			for _, resolvedLocation := range resolvedLocations {
+				importedLocation := resolvedLocation.Location
+				if _, ok := seen[importedLocation]; ok {
+					continue
+				}
+				seen[importedLocation] = struct{}{}
+
+				code, err := getLocationCodeFromInterface(e.runtimeInterface, importedLocation)
+				if err != nil {
+					return nil, err
+				}
+
+				dependencies = append(
+					dependencies,
+					CheckedProgramDependency{
+						Location: importedLocation,
+						CodeHash: NewCodeHash(code),
+					},
+				)
+
+				importedElaboration, err := e.importedElaboration(importedLocation)
+				if err != nil {
+					return nil, err
+				}
+				elaborations = append(elaborations, importedElaboration)
+			}
+		}
+	}
+
+	sortCheckedProgramDependencies(dependencies)
+
+	return dependencies, nil
+}
+
+func (e *checkingEnvironment) elaborationEncodingConfig(location common.Location) *sema.ElaborationEncodingConfig {
+	return &sema.ElaborationEncodingConfig{
+		Location:            location,
+		MemoryGauge:         e,
+		BaseValueActivation: e.getBaseValueActivation(location),
+		BaseTypeActivation:  e.getBaseTypeActivation(location),
+		ImportedElaboration: e.importedElaboration,
+	}
+}
+
 // parseAndCheckProgramWithRecovery parses and checks the given program.
 // It first attempts to parse and checks the program as usual.
 // If parsing or checking fails, recovery is attempted.
@@ -491,7 +768,7 @@ func (e *checkingEnvironment) recoverProgram(
 
 	// Parse and check the recovered program
 
-	program, err = parser.ParseProgram(e, newCode, parser.Config{})
+	program, err = parser.ParseProgram(e, newCode, e.parserConfig)
 	if err != nil {
 		return nil, nil
 	}
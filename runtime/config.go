@@ -19,6 +19,8 @@
 package runtime
 
 import (
+	"github.com/onflow/cadence/bbq/compiler"
+	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/interpreter"
 )
 
@@ -35,4 +37,21 @@ type Config struct {
 	ResourceOwnerChangeHandlerEnabled bool
 	// CoverageReport enables and collects coverage reporting metrics
 	CoverageReport *CoverageReport
+	// EventBus, if set, receives all emitted events, in addition to Interface.EmitEvent
+	EventBus *EventBus
+	// VMEnabled configures if scripts are executed using the VM instead of the interpreter.
+	// Functions which cannot be compiled, and executions which require debugging, coverage reporting,
+	// or tracing, are still executed by the interpreter.
+	// Only applies to scripts, transactions are always executed by the interpreter,
+	// as if the VM was not enabled
+	VMEnabled bool
+	// VMFallbackHandler, if set, is called for each function which could not be compiled,
+	// and which is therefore executed by the interpreter when the VM is used
+	VMFallbackHandler func(location common.Location, err *compiler.UnsupportedError)
+	// CheckedProgramStore, if set, persists checked programs across executions,
+	// so imported programs do not need to be checked again.
+	// Note that loading a stored program meters less memory than checking it
+	CheckedProgramStore CheckedProgramStore
+	// TypeParametersEnabled configures if non-native functions may have type parameters
+	TypeParametersEnabled bool
 }
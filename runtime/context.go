@@ -28,7 +28,15 @@ type Context struct {
 	Environment    Environment
 	CoverageReport *CoverageReport
 
-	// UseVM configures if the VM should be used
+	// DryRunJournal, if set, executes a transaction as a dry run:
+	// The transaction is fully executed, but register writes, contract code changes, and events
+	// are not passed to the Interface, slab indices and UUIDs are not allocated from the Interface,
+	// and the effects of the execution are recorded in the journal.
+	// Only applies to transactions
+	DryRunJournal *StateChangeJournal
+
+	// UseVM configures if the VM should be used.
+	// Only applies to scripts, transactions cannot be executed with the VM
 	UseVM bool
 }
 
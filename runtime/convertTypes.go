@@ -527,8 +527,33 @@ func exportFunctionType(
 		}
 	}
 
+	// The types of non-native type parameters have no exported representation,
+	// so the parameter types and the return type are exported
+	// with the non-native type parameters instantiated with their type bounds.
+	// Non-native type parameters only exist if type parameters are enabled (see Config.TypeParametersEnabled)
+
+	parameters := t.Parameters
+	returnType := t.ReturnTypeAnnotation.Type
+
+	if typeParameterCount > 0 {
+		typeArguments := &sema.TypeParameterTypeOrderedMap{}
+		for _, typeParameter := range t.TypeParameters {
+			if !typeParameter.NonNative {
+				continue
+			}
+			typeArguments.Set(typeParameter, typeParameter.TypeBound)
+		}
+
+		if typeArguments.Len() > 0 {
+			if resolvedType, ok := t.Resolve(typeArguments).(*sema.FunctionType); ok {
+				parameters = resolvedType.Parameters
+				returnType = resolvedType.ReturnTypeAnnotation.Type
+			}
+		}
+	}
+
 	// Parameters
-	parameterCount := len(t.Parameters)
+	parameterCount := len(parameters)
 	common.UseMemory(gauge, common.MemoryUsage{
 		Kind:   common.MemoryKindCadenceParameter,
 		Amount: uint64(parameterCount),
@@ -537,7 +562,7 @@ func exportFunctionType(
 	if parameterCount > 0 {
 		convertedParameters = make([]cadence.Parameter, parameterCount)
 
-		for i, parameter := range t.Parameters {
+		for i, parameter := range parameters {
 			convertedParameterType := ExportMeteredType(gauge, parameter.TypeAnnotation.Type, results)
 
 			// Metered above
@@ -549,7 +574,7 @@ func exportFunctionType(
 		}
 	}
 
-	convertedReturnType := ExportMeteredType(gauge, t.ReturnTypeAnnotation.Type, results)
+	convertedReturnType := ExportMeteredType(gauge, returnType, results)
 
 	return cadence.NewMeteredFunctionType(
 		gauge,
@@ -138,3 +138,80 @@ func BenchmarkExportType(b *testing.B) {
 		}
 	})
 }
+
+func TestRuntimeExportGenericFunctionType(t *testing.T) {
+
+	t.Parallel()
+
+	typeParameter := &sema.TypeParameter{
+		Name:      "T",
+		TypeBound: sema.AnyStructType,
+		NonNative: true,
+	}
+
+	resourceTypeParameter := &sema.TypeParameter{
+		Name:      "R",
+		TypeBound: sema.AnyResourceType,
+		NonNative: true,
+	}
+
+	ty := &sema.FunctionType{
+		TypeParameters: []*sema.TypeParameter{
+			typeParameter,
+			resourceTypeParameter,
+		},
+		Parameters: []sema.Parameter{
+			{
+				Label:      sema.ArgumentLabelNotRequired,
+				Identifier: "xs",
+				TypeAnnotation: sema.NewTypeAnnotation(
+					&sema.VariableSizedType{
+						Type: &sema.GenericType{
+							TypeParameter: typeParameter,
+						},
+					},
+				),
+			},
+			{
+				Label:      sema.ArgumentLabelNotRequired,
+				Identifier: "r",
+				TypeAnnotation: sema.NewTypeAnnotation(
+					&sema.GenericType{
+						TypeParameter: resourceTypeParameter,
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			&sema.OptionalType{
+				Type: &sema.GenericType{
+					TypeParameter: typeParameter,
+				},
+			},
+		),
+	}
+
+	assert.Equal(t,
+		cadence.NewFunctionType(
+			cadence.FunctionPurityUnspecified,
+			[]cadence.TypeParameter{
+				cadence.NewTypeParameter("T", cadence.AnyStructType),
+				cadence.NewTypeParameter("R", cadence.AnyResourceType),
+			},
+			[]cadence.Parameter{
+				cadence.NewParameter(
+					sema.ArgumentLabelNotRequired,
+					"xs",
+					cadence.NewVariableSizedArrayType(cadence.AnyStructType),
+				),
+				cadence.NewParameter(
+					sema.ArgumentLabelNotRequired,
+					"r",
+					cadence.AnyResourceType,
+				),
+			},
+			cadence.NewOptionalType(cadence.AnyStructType),
+		),
+		ExportType(ty, map[sema.TypeID]cadence.Type{}),
+	)
+}
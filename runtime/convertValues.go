@@ -46,6 +46,20 @@ func ExportValue(
 	)
 }
 
+// TestFrameworkValueExporter implements stdlib.TestFrameworkValueExporter using ExportValue.
+// Test providers can embed it to implement stdlib.TestFramework.
+type TestFrameworkValueExporter struct{}
+
+var _ stdlib.TestFrameworkValueExporter = TestFrameworkValueExporter{}
+
+func (TestFrameworkValueExporter) ExportValue(
+	value interpreter.Value,
+	context interpreter.ValueExportContext,
+	locationRange interpreter.LocationRange,
+) (cadence.Value, error) {
+	return ExportValue(value, context, locationRange)
+}
+
 // NOTE: Do not generalize to map[interpreter.Value],
 // as not all values are Go hashable, i.e. this might lead to run-time panics
 type seenReferences map[interpreter.ReferenceValue]struct{}
@@ -431,22 +445,42 @@ func exportCompositeValue(
 			fieldValues[i] = exportedFieldValue
 		}
 
-		if composite, ok := v.(*interpreter.CompositeValue); ok {
-			for _, attachment := range composite.GetAttachments(context, locationRange) {
-				exportedAttachmentValue, err := exportValue(
-					attachment,
-					context,
-					locationRange,
-					seenReferences,
-				)
-				if err != nil {
-					return nil, err
-				}
-				fieldValues = append(fieldValues, exportedAttachmentValue)
+		return fieldValues, nil
+	}
+
+	exportAttachments := func() ([]cadence.Attachment, error) {
+		composite, ok := v.(*interpreter.CompositeValue)
+		if !ok {
+			return nil, nil
+		}
+
+		attachments := composite.GetAttachments(context, locationRange)
+		if len(attachments) == 0 {
+			return nil, nil
+		}
+
+		exportedAttachments := make([]cadence.Attachment, 0, len(attachments))
+
+		for _, attachment := range attachments {
+			exportedAttachmentValue, err := exportValue(
+				attachment,
+				context,
+				locationRange,
+				seenReferences,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			exportedAttachment, ok := exportedAttachmentValue.(cadence.Attachment)
+			if !ok {
+				panic(errors.NewUnreachableError())
 			}
+
+			exportedAttachments = append(exportedAttachments, exportedAttachment)
 		}
 
-		return fieldValues, nil
+		return exportedAttachments, nil
 	}
 
 	compositeKind := compositeType.Kind
@@ -464,7 +498,13 @@ func exportCompositeValue(
 		if err != nil {
 			return nil, err
 		}
-		return structure.WithType(t.(*cadence.StructType)), nil
+		attachments, err := exportAttachments()
+		if err != nil {
+			return nil, err
+		}
+		return structure.
+			WithType(t.(*cadence.StructType)).
+			WithAttachments(attachments), nil
 
 	case common.CompositeKindResource:
 		resource, err := cadence.NewMeteredResource(
@@ -475,7 +515,13 @@ func exportCompositeValue(
 		if err != nil {
 			return nil, err
 		}
-		return resource.WithType(t.(*cadence.ResourceType)), nil
+		attachments, err := exportAttachments()
+		if err != nil {
+			return nil, err
+		}
+		return resource.
+			WithType(t.(*cadence.ResourceType)).
+			WithAttachments(attachments), nil
 
 	case common.CompositeKindAttachment:
 		attachment, err := cadence.NewMeteredAttachment(
@@ -893,6 +939,7 @@ func (i valueImporter) importValue(value cadence.Value, expectedType sema.Type)
 			v.StructType.QualifiedIdentifier,
 			getCompositeTypeFields(v.StructType),
 			getCompositeFieldValues(v),
+			v.Attachments(),
 		)
 	case cadence.Resource:
 		return i.importCompositeValue(
@@ -901,6 +948,7 @@ func (i valueImporter) importValue(value cadence.Value, expectedType sema.Type)
 			v.ResourceType.QualifiedIdentifier,
 			getCompositeTypeFields(v.ResourceType),
 			getCompositeFieldValues(v),
+			v.Attachments(),
 		)
 	case cadence.Event:
 		return i.importCompositeValue(
@@ -909,6 +957,7 @@ func (i valueImporter) importValue(value cadence.Value, expectedType sema.Type)
 			v.EventType.QualifiedIdentifier,
 			getCompositeTypeFields(v.EventType),
 			getCompositeFieldValues(v),
+			nil,
 		)
 	case cadence.Enum:
 		return i.importCompositeValue(
@@ -917,6 +966,7 @@ func (i valueImporter) importValue(value cadence.Value, expectedType sema.Type)
 			v.EnumType.QualifiedIdentifier,
 			getCompositeTypeFields(v.EnumType),
 			getCompositeFieldValues(v),
+			nil,
 		)
 	case *cadence.InclusiveRange:
 		return i.importInclusiveRangeValue(v, expectedType)
@@ -1505,6 +1555,7 @@ func (i valueImporter) importCompositeValue(
 	qualifiedIdentifier string,
 	fieldTypes []cadence.Field,
 	fieldValues []cadence.Value,
+	attachments []cadence.Attachment,
 ) (
 	interpreter.Value,
 	error,
@@ -1573,6 +1624,13 @@ func (i valueImporter) importCompositeValue(
 	}
 
 	if location == nil {
+		if len(attachments) > 0 {
+			return nil, errors.NewDefaultUserError(
+				"cannot import value of type %s: attachments are not supported",
+				qualifiedIdentifier,
+			)
+		}
+
 		switch sema.NativeCompositeTypes[qualifiedIdentifier] {
 		case sema.PublicKeyType:
 			// PublicKey has a dedicated constructor
@@ -1597,7 +1655,7 @@ func (i valueImporter) importCompositeValue(
 		}
 	}
 
-	return interpreter.NewCompositeValue(
+	compositeValue := interpreter.NewCompositeValue(
 		inter,
 		locationRange,
 		location,
@@ -1605,7 +1663,89 @@ func (i valueImporter) importCompositeValue(
 		kind,
 		fields,
 		common.ZeroAddress,
-	), nil
+	)
+
+	for _, attachment := range attachments {
+		err := i.importAttachment(compositeValue, compositeType, attachment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return compositeValue, nil
+}
+
+func (i valueImporter) importAttachment(
+	base *interpreter.CompositeValue,
+	baseType *sema.CompositeType,
+	attachment cadence.Attachment,
+) error {
+
+	// Only struct attachments on structs can be imported
+
+	if baseType.Kind != common.CompositeKindStructure {
+		return errors.NewDefaultUserError(
+			"cannot import value of type %s: attachments are only supported on structs",
+			baseType.QualifiedIdentifier(),
+		)
+	}
+
+	if attachment.AttachmentType == nil {
+		return errors.NewDefaultUserError(
+			"cannot import attachment of value of type %s: missing attachment type",
+			baseType.QualifiedIdentifier(),
+		)
+	}
+
+	importedAttachment, err := i.importCompositeValue(
+		common.CompositeKindAttachment,
+		attachment.AttachmentType.Location,
+		attachment.AttachmentType.QualifiedIdentifier,
+		getCompositeTypeFields(attachment.AttachmentType),
+		getCompositeFieldValues(attachment),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	attachmentValue, ok := importedAttachment.(*interpreter.CompositeValue)
+	if !ok {
+		return errors.NewDefaultUserError(
+			"cannot import attachment of type %s",
+			attachment.AttachmentType.QualifiedIdentifier,
+		)
+	}
+
+	inter := i.context
+
+	attachmentType, ok := interpreter.MustSemaTypeOfValue(attachmentValue, inter).(*sema.CompositeType)
+	if !ok ||
+		attachmentType.Kind != common.CompositeKindAttachment ||
+		attachmentType.IsResourceType() {
+
+		return errors.NewDefaultUserError(
+			"cannot import attachment of type %s: not a struct attachment",
+			attachment.AttachmentType.QualifiedIdentifier,
+		)
+	}
+
+	if !sema.IsSubType(baseType, attachmentType.GetBaseType()) {
+		return errors.NewDefaultUserError(
+			"cannot import attachment of type %s: not attachable to value of type %s",
+			attachmentType.QualifiedIdentifier(),
+			baseType.QualifiedIdentifier(),
+		)
+	}
+
+	base.SetTypeKey(
+		inter,
+		i.locationRange,
+		attachmentType,
+		attachmentValue,
+	)
+
+	return nil
 }
 
 func (i valueImporter) importPublicKey(
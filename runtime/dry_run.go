@@ -0,0 +1,591 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/stdlib"
+)
+
+// StateChangeJournal records the effects of a transaction executed as a dry run
+// (see Context.DryRunJournal).
+type StateChangeJournal struct {
+	// RegisterWrites are the registers that would have been written, sorted by owner and key.
+	// A nil value is a removal.
+	RegisterWrites []RegisterWrite
+	// PathChanges are the storage map entries that would have been added, updated, or removed,
+	// sorted by address, domain, and key
+	PathChanges []StoragePathChange
+	// ResourceMoves are the resources that moved between accounts, in order of execution
+	ResourceMoves []ResourceMove
+	// CapabilityChanges are the capability controllers issued, retargeted, and revoked,
+	// and the capabilities published and unpublished, in order of execution
+	CapabilityChanges []CapabilityChange
+	// ContractCodeChanges are the contract code updates and removals, in order of execution
+	ContractCodeChanges []ContractCodeChange
+	// Events are all emitted events, in order of emission
+	Events []cadence.Event
+	// ComputationIntensities is the metered computation intensity per computation kind
+	ComputationIntensities map[common.ComputationKind]uint64
+	// ComputationUsed is the computation used, as reported by the runtime interface
+	ComputationUsed uint64
+}
+
+type RegisterWrite struct {
+	Owner []byte
+	Key   []byte
+	Value []byte
+}
+
+type StoragePathChangeKind uint8
+
+const (
+	StoragePathChangeKindUnknown StoragePathChangeKind = iota
+	StoragePathChangeKindAdded
+	StoragePathChangeKindUpdated
+	StoragePathChangeKindRemoved
+)
+
+func (k StoragePathChangeKind) String() string {
+	switch k {
+	case StoragePathChangeKindAdded:
+		return "added"
+	case StoragePathChangeKindUpdated:
+		return "updated"
+	case StoragePathChangeKindRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+type StoragePathChange struct {
+	Kind    StoragePathChangeKind
+	Address common.Address
+	Domain  common.StorageDomain
+	Key     string
+}
+
+type ResourceMove struct {
+	TypeID common.TypeID
+	// UUID is the UUID of the resource, or 0 if it is unavailable
+	UUID uint64
+	From common.Address
+	To   common.Address
+}
+
+type CapabilityChangeKind uint8
+
+const (
+	CapabilityChangeKindUnknown CapabilityChangeKind = iota
+	CapabilityChangeKindIssued
+	CapabilityChangeKindRetargeted
+	CapabilityChangeKindRevoked
+	CapabilityChangeKindPublished
+	CapabilityChangeKindUnpublished
+)
+
+func (k CapabilityChangeKind) String() string {
+	switch k {
+	case CapabilityChangeKindIssued:
+		return "issued"
+	case CapabilityChangeKindRetargeted:
+		return "retargeted"
+	case CapabilityChangeKindRevoked:
+		return "revoked"
+	case CapabilityChangeKindPublished:
+		return "published"
+	case CapabilityChangeKindUnpublished:
+		return "unpublished"
+	}
+	return "unknown"
+}
+
+type CapabilityChange struct {
+	Kind    CapabilityChangeKind
+	Address common.Address
+	// ID is the capability controller ID, if any
+	ID uint64
+	// Path is the target storage path of issued and retargeted storage capability controllers,
+	// and the public path of published and unpublished capabilities
+	Path *cadence.Path
+}
+
+type ContractCodeChange struct {
+	Location common.AddressLocation
+	// Code is the new code of the contract, or nil if the contract is removed
+	Code []byte
+}
+
+var capabilityChangeKinds = map[common.TypeID]CapabilityChangeKind{
+	stdlib.StorageCapabilityControllerIssuedEventType.ID():        CapabilityChangeKindIssued,
+	stdlib.AccountCapabilityControllerIssuedEventType.ID():        CapabilityChangeKindIssued,
+	stdlib.StorageCapabilityControllerTargetChangedEventType.ID(): CapabilityChangeKindRetargeted,
+	stdlib.StorageCapabilityControllerDeletedEventType.ID():       CapabilityChangeKindRevoked,
+	stdlib.AccountCapabilityControllerDeletedEventType.ID():       CapabilityChangeKindRevoked,
+	stdlib.CapabilityPublishedEventType.ID():                      CapabilityChangeKindPublished,
+	stdlib.CapabilityUnpublishedEventType.ID():                    CapabilityChangeKindUnpublished,
+}
+
+func newCapabilityChange(event cadence.Event) (CapabilityChange, bool) {
+	if event.EventType == nil {
+		return CapabilityChange{}, false
+	}
+
+	kind, ok := capabilityChangeKinds[common.TypeID(event.EventType.ID())]
+	if !ok {
+		return CapabilityChange{}, false
+	}
+
+	change := CapabilityChange{
+		Kind: kind,
+	}
+
+	if address, ok := event.SearchFieldByName("address").(cadence.Address); ok {
+		change.Address = common.Address(address)
+	}
+
+	if id, ok := event.SearchFieldByName("id").(cadence.UInt64); ok {
+		change.ID = uint64(id)
+	}
+
+	if path, ok := event.SearchFieldByName("path").(cadence.Path); ok {
+		change.Path = &path
+	}
+
+	return change, true
+}
+
+type registerKey struct {
+	owner string
+	key   string
+}
+
+// dryRunSlabIndexBase and dryRunUUIDBase are the first slab index and UUID
+// allocated during a dry run. They are in the upper half of the range,
+// so they do not collide with the slab indices and UUIDs allocated by the wrapped interface.
+const dryRunSlabIndexBase = 1 << 63
+const dryRunUUIDBase = 1 << 63
+
+// dryRunInterface is a runtime interface which buffers all register writes, contract code changes,
+// and events, and records the effects of the execution in a journal.
+//
+// Slab indices and UUIDs are allocated locally,
+// so the state of the wrapped interface is not changed.
+type dryRunInterface struct {
+	Interface
+	journal *StateChangeJournal
+	// registers are the written registers. A nil value is a removal
+	registers map[registerKey][]byte
+	// slabIndices are the last slab indices allocated for each owner
+	slabIndices map[string]uint64
+	// lastUUID is the last allocated UUID
+	lastUUID uint64
+	// contractCodes are the updated contract codes. A nil code is a removal
+	contractCodes map[common.AddressLocation][]byte
+	// forwardResourceOwnerChanges configures if resource owner changes
+	// are also reported to the wrapped interface
+	forwardResourceOwnerChanges bool
+}
+
+var _ Interface = &dryRunInterface{}
+
+func newDryRunInterface(
+	runtimeInterface Interface,
+	journal *StateChangeJournal,
+) *dryRunInterface {
+	journal.ComputationIntensities = map[common.ComputationKind]uint64{}
+
+	return &dryRunInterface{
+		Interface:     runtimeInterface,
+		journal:       journal,
+		registers:     map[registerKey][]byte{},
+		slabIndices:   map[string]uint64{},
+		contractCodes: map[common.AddressLocation][]byte{},
+		lastUUID:      dryRunUUIDBase,
+	}
+}
+
+func (i *dryRunInterface) GetValue(owner, key []byte) ([]byte, error) {
+	value, ok := i.registers[registerKey{owner: string(owner), key: string(key)}]
+	if ok {
+		return value, nil
+	}
+	return i.Interface.GetValue(owner, key)
+}
+
+func (i *dryRunInterface) SetValue(owner, key, value []byte) error {
+	if len(value) == 0 {
+		value = nil
+	} else {
+		value = bytes.Clone(value)
+	}
+	i.registers[registerKey{owner: string(owner), key: string(key)}] = value
+	return nil
+}
+
+func (i *dryRunInterface) ValueExists(owner, key []byte) (bool, error) {
+	value, ok := i.registers[registerKey{owner: string(owner), key: string(key)}]
+	if ok {
+		return len(value) > 0, nil
+	}
+	return i.Interface.ValueExists(owner, key)
+}
+
+func (i *dryRunInterface) AllocateSlabIndex(owner []byte) (atree.SlabIndex, error) {
+	index, ok := i.slabIndices[string(owner)]
+	if !ok {
+		index = dryRunSlabIndexBase
+	}
+	index++
+	i.slabIndices[string(owner)] = index
+
+	var result atree.SlabIndex
+	binary.BigEndian.PutUint64(result[:], index)
+	return result, nil
+}
+
+func (i *dryRunInterface) GenerateUUID() (uint64, error) {
+	i.lastUUID++
+	return i.lastUUID, nil
+}
+
+func (i *dryRunInterface) UpdateAccountContractCode(location common.AddressLocation, code []byte) error {
+	code = bytes.Clone(code)
+	i.contractCodes[location] = code
+	i.journal.ContractCodeChanges = append(
+		i.journal.ContractCodeChanges,
+		ContractCodeChange{
+			Location: location,
+			Code:     code,
+		},
+	)
+	return nil
+}
+
+func (i *dryRunInterface) RemoveAccountContractCode(location common.AddressLocation) error {
+	i.contractCodes[location] = nil
+	i.journal.ContractCodeChanges = append(
+		i.journal.ContractCodeChanges,
+		ContractCodeChange{
+			Location: location,
+		},
+	)
+	return nil
+}
+
+func (i *dryRunInterface) GetAccountContractCode(location common.AddressLocation) ([]byte, error) {
+	code, ok := i.contractCodes[location]
+	if ok {
+		return code, nil
+	}
+	return i.Interface.GetAccountContractCode(location)
+}
+
+func (i *dryRunInterface) GetAccountContractNames(address Address) ([]string, error) {
+	names, err := i.Interface.GetAccountContractNames(address)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := map[string]bool{}
+	for _, name := range names {
+		exists[name] = true
+	}
+
+	for location, code := range i.contractCodes { //nolint:maprange
+		if location.Address != address {
+			continue
+		}
+		exists[location.Name] = code != nil
+	}
+
+	names = make([]string, 0, len(exists))
+	for name, exists := range exists { //nolint:maprange
+		if exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (i *dryRunInterface) EmitEvent(event cadence.Event) error {
+	i.journal.Events = append(i.journal.Events, event)
+
+	if change, ok := newCapabilityChange(event); ok {
+		i.journal.CapabilityChanges = append(i.journal.CapabilityChanges, change)
+	}
+
+	return nil
+}
+
+func (i *dryRunInterface) MeterComputation(usage common.ComputationUsage) error {
+	i.journal.ComputationIntensities[usage.Kind] += usage.Intensity
+	return i.Interface.MeterComputation(usage)
+}
+
+func (i *dryRunInterface) ResourceOwnerChanged(
+	inter *interpreter.Interpreter,
+	resource *interpreter.CompositeValue,
+	oldOwner common.Address,
+	newOwner common.Address,
+) {
+	move := ResourceMove{
+		TypeID: resource.TypeID(),
+		From:   oldOwner,
+		To:     newOwner,
+	}
+
+	uuid := resource.ResourceUUID(inter)
+	if uuid != nil {
+		move.UUID = uint64(*uuid)
+	}
+
+	i.journal.ResourceMoves = append(i.journal.ResourceMoves, move)
+
+	if i.forwardResourceOwnerChanges {
+		i.Interface.ResourceOwnerChanged(inter, resource, oldOwner, newOwner)
+	}
+}
+
+// finish completes the journal after the execution, once storage was committed.
+func (i *dryRunInterface) finish() error {
+	journal := i.journal
+
+	writtenAddresses := map[common.Address]struct{}{}
+
+	journal.RegisterWrites = make([]RegisterWrite, 0, len(i.registers))
+	for key, value := range i.registers { //nolint:maprange
+		journal.RegisterWrites = append(
+			journal.RegisterWrites,
+			RegisterWrite{
+				Owner: []byte(key.owner),
+				Key:   []byte(key.key),
+				Value: value,
+			},
+		)
+
+		address, err := common.BytesToAddress([]byte(key.owner))
+		if err == nil {
+			writtenAddresses[address] = struct{}{}
+		}
+	}
+
+	sort.Slice(journal.RegisterWrites, func(a, b int) bool {
+		writeA := journal.RegisterWrites[a]
+		writeB := journal.RegisterWrites[b]
+		result := bytes.Compare(writeA.Owner, writeB.Owner)
+		if result != 0 {
+			return result < 0
+		}
+		return bytes.Compare(writeA.Key, writeB.Key) < 0
+	})
+
+	addresses := make([]common.Address, 0, len(writtenAddresses))
+	for address := range writtenAddresses { //nolint:maprange
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(a, b int) bool {
+		return bytes.Compare(addresses[a][:], addresses[b][:]) < 0
+	})
+
+	for _, address := range addresses {
+		changes, err := i.storagePathChanges(address)
+		if err != nil {
+			return err
+		}
+		journal.PathChanges = append(journal.PathChanges, changes...)
+	}
+
+	computationUsed, err := i.Interface.ComputationUsed()
+	if err != nil {
+		return err
+	}
+	journal.ComputationUsed = computationUsed
+
+	return nil
+}
+
+type storagePathEntry struct {
+	// encoded is the encoding of the storable in the domain storage map:
+	// the whole value if it is inlined, or the slab ID otherwise
+	encoded []byte
+	slabIDs []atree.SlabID
+}
+
+type storagePathKey struct {
+	domain common.StorageDomain
+	key    string
+}
+
+// storagePathChanges compares the storage maps of the given account before and after the execution.
+// A storage map entry is updated if its encoding changed,
+// or if any of the slabs of its value were written.
+func (i *dryRunInterface) storagePathChanges(address common.Address) ([]StoragePathChange, error) {
+	before, err := readStoragePathEntries(i.Interface, address)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := readStoragePathEntries(i, address)
+	if err != nil {
+		return nil, err
+	}
+
+	written := func(entry storagePathEntry) bool {
+		for _, slabID := range entry.slabIDs {
+			slabAddress := slabID.Address()
+			slabIndex := slabID.Index()
+			key := registerKey{
+				owner: string(slabAddress[:]),
+				key:   string(atree.SlabIndexToLedgerKey(slabIndex)),
+			}
+			if _, ok := i.registers[key]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var changes []StoragePathChange
+
+	addChange := func(kind StoragePathChangeKind, key storagePathKey) {
+		changes = append(changes, StoragePathChange{
+			Kind:    kind,
+			Address: address,
+			Domain:  key.domain,
+			Key:     key.key,
+		})
+	}
+
+	for key, afterEntry := range after { //nolint:maprange
+		beforeEntry, ok := before[key]
+		switch {
+		case !ok:
+			addChange(StoragePathChangeKindAdded, key)
+
+		case !bytes.Equal(beforeEntry.encoded, afterEntry.encoded),
+			written(beforeEntry),
+			written(afterEntry):
+
+			addChange(StoragePathChangeKindUpdated, key)
+		}
+	}
+
+	for key := range before { //nolint:maprange
+		if _, ok := after[key]; !ok {
+			addChange(StoragePathChangeKindRemoved, key)
+		}
+	}
+
+	sort.Slice(changes, func(a, b int) bool {
+		changeA := changes[a]
+		changeB := changes[b]
+		if changeA.Domain != changeB.Domain {
+			return changeA.Domain < changeB.Domain
+		}
+		return changeA.Key < changeB.Key
+	})
+
+	return changes, nil
+}
+
+func readStoragePathEntries(ledger atree.Ledger, address common.Address) (map[storagePathKey]storagePathEntry, error) {
+	entries := map[storagePathKey]storagePathEntry{}
+
+	slabIndex, exists, err := readAccountStorageSlabIndexFromRegister(ledger, address)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return entries, nil
+	}
+
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	walker := &storageUsageWalker{
+		storage: storage.PersistentSlabStorage,
+		types:   map[common.TypeID]*TypeStorageUsage{},
+	}
+
+	err = walker.walkStoragePaths(
+		atree.NewSlabID(atree.Address(address), slabIndex),
+		func(domain common.StorageDomain, key string, _ atree.Storable, value atree.Storable) error {
+			var buffer bytes.Buffer
+			encoder := atree.NewEncoder(&buffer, interpreter.CBOREncMode)
+			err := value.Encode(encoder)
+			if err != nil {
+				return err
+			}
+			err = encoder.CBOR.Flush()
+			if err != nil {
+				return err
+			}
+
+			var slabIDs []atree.SlabID
+			err = collectSlabIDs(walker, value, &slabIDs)
+			if err != nil {
+				return err
+			}
+
+			entries[storagePathKey{domain: domain, key: key}] = storagePathEntry{
+				encoded: buffer.Bytes(),
+				slabIDs: slabIDs,
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// collectSlabIDs collects the IDs of all (non-inlined) slabs of the given storable.
+func collectSlabIDs(walker *storageUsageWalker, storable atree.Storable, slabIDs *[]atree.SlabID) error {
+	if slabIDStorable, ok := storable.(atree.SlabIDStorable); ok {
+		slabID := atree.SlabID(slabIDStorable)
+		slab, err := walker.retrieve(slabID)
+		if err != nil {
+			return err
+		}
+		*slabIDs = append(*slabIDs, slabID)
+		storable = slab
+	}
+
+	for _, child := range storable.ChildStorables() {
+		err := collectSlabIDs(walker, child, slabIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	. "github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+func TestRuntimeTransactionDryRun(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewTestInterpreterRuntime()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+
+	deployTx := DeploymentTransaction("Test", []byte(`
+      access(all) contract Test {
+
+          access(all) event Minted(id: UInt64)
+
+          access(all) resource NFT {}
+
+          access(all) fun mint(): @NFT {
+              let nft <- create NFT()
+              emit Minted(id: nft.uuid)
+              return <-nft
+          }
+      }
+    `))
+
+	const setupTx = `
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: auth(Storage) &Account) {
+              signer.storage.save(<-Test.mint(), to: /storage/nft)
+              signer.storage.save("Hello", to: /storage/greeting)
+          }
+      }
+    `
+
+	const dryRunTx = `
+      import Test from 0x1
+
+      transaction {
+          prepare(
+              signer1: auth(Storage, Contracts) &Account,
+              signer2: auth(Storage, Capabilities) &Account
+          ) {
+              let nft <- signer1.storage.load<@Test.NFT>(from: /storage/nft)!
+              signer2.storage.save(<-nft, to: /storage/nft)
+
+              signer1.storage.load<String>(from: /storage/greeting)
+              signer1.storage.save("Bye", to: /storage/greeting)
+
+              let cap = signer2.capabilities.storage.issue<&Test.NFT>(/storage/nft)
+              signer2.capabilities.publish(cap, at: /public/nft)
+
+              signer1.contracts.add(
+                  name: "Other",
+                  code: "access(all) contract Other {}".utf8
+              )
+
+              destroy Test.mint()
+          }
+      }
+    `
+
+	accountCodes := map[common.Location][]byte{}
+	var events []cadence.Event
+	var signers []Address
+	var uuid uint64
+
+	ledger := NewTestLedger(nil, nil)
+
+	runtimeInterface := &TestRuntimeInterface{
+		Storage: ledger,
+		OnGetSigningAccounts: func() ([]Address, error) {
+			return signers, nil
+		},
+		OnResolveLocation: NewSingleIdentifierLocationResolver(t),
+		OnUpdateAccountContractCode: func(location common.AddressLocation, code []byte) error {
+			accountCodes[location] = code
+			return nil
+		},
+		OnGetAccountContractCode: func(location common.AddressLocation) (code []byte, err error) {
+			return accountCodes[location], nil
+		},
+		OnEmitEvent: func(event cadence.Event) error {
+			events = append(events, event)
+			return nil
+		},
+		OnGenerateUUID: func() (uint64, error) {
+			uuid++
+			return uuid, nil
+		},
+	}
+
+	nextTransactionLocation := NewTransactionLocationGenerator()
+
+	signers = []Address{address1}
+
+	for _, source := range []string{
+		string(deployTx),
+		setupTx,
+	} {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(source),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	storedValues := maps.Clone(ledger.StoredValues)
+	storageIndices := maps.Clone(ledger.StorageIndices)
+	lastUUID := uuid
+	events = nil
+	signers = []Address{address1, address2}
+
+	journal := &StateChangeJournal{}
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(dryRunTx),
+		},
+		Context{
+			Interface:     runtimeInterface,
+			Location:      nextTransactionLocation(),
+			DryRunJournal: journal,
+		},
+	)
+	require.NoError(t, err)
+
+	// No registers or contract codes were written,
+	// no slab indices or UUIDs were allocated, and no events were emitted
+
+	assert.Equal(t, storedValues, ledger.StoredValues)
+	assert.Equal(t, storageIndices, ledger.StorageIndices)
+	assert.Equal(t, lastUUID, uuid)
+	assert.Empty(t, events)
+	assert.NotContains(t,
+		accountCodes,
+		common.AddressLocation{
+			Address: address1,
+			Name:    "Other",
+		},
+	)
+
+	assert.NotEmpty(t, journal.RegisterWrites)
+
+	type pathChange struct {
+		kind    string
+		address common.Address
+		path    string
+	}
+
+	var pathChanges []pathChange
+	for _, change := range journal.PathChanges {
+		pathChanges = append(pathChanges, pathChange{
+			kind:    change.Kind.String(),
+			address: change.Address,
+			path:    change.Domain.Identifier() + "/" + change.Key,
+		})
+	}
+
+	assert.Equal(t,
+		[]pathChange{
+			{"updated", address1, "storage/greeting"},
+			{"removed", address1, "storage/nft"},
+			{"added", address1, "contract/Other"},
+			{"added", address2, "storage/nft"},
+			{"added", address2, "public/nft"},
+			{"added", address2, "cap_con/1"},
+			{"added", address2, "path_cap/nft"},
+		},
+		pathChanges,
+	)
+
+	nftTypeID := common.TypeID("A.0000000000000001.Test.NFT")
+
+	require.NotEmpty(t, journal.ResourceMoves)
+	lastMove := journal.ResourceMoves[len(journal.ResourceMoves)-1]
+	assert.Equal(t, nftTypeID, lastMove.TypeID)
+	assert.NotZero(t, lastMove.UUID)
+	assert.Equal(t, address2, lastMove.To)
+
+	require.Len(t, journal.CapabilityChanges, 2)
+
+	issued := journal.CapabilityChanges[0]
+	assert.Equal(t, CapabilityChangeKindIssued, issued.Kind)
+	assert.Equal(t, address2, issued.Address)
+	assert.Equal(t, uint64(1), issued.ID)
+	assert.Equal(t,
+		&cadence.Path{
+			Domain:     common.PathDomainStorage,
+			Identifier: "nft",
+		},
+		issued.Path,
+	)
+
+	published := journal.CapabilityChanges[1]
+	assert.Equal(t, CapabilityChangeKindPublished, published.Kind)
+	assert.Equal(t, address2, published.Address)
+	assert.Equal(t,
+		&cadence.Path{
+			Domain:     common.PathDomainPublic,
+			Identifier: "nft",
+		},
+		published.Path,
+	)
+
+	assert.Equal(t,
+		[]ContractCodeChange{
+			{
+				Location: common.AddressLocation{
+					Address: address1,
+					Name:    "Other",
+				},
+				Code: []byte("access(all) contract Other {}"),
+			},
+		},
+		journal.ContractCodeChanges,
+	)
+
+	assert.Len(t, journal.Events, 4)
+
+	assert.NotZero(t, journal.ComputationIntensities[common.ComputationKindStatement])
+}
@@ -21,12 +21,15 @@ package runtime
 import (
 	"time"
 
+	"github.com/onflow/cadence"
+
 	"github.com/onflow/cadence/activations"
 
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
 )
@@ -72,6 +75,9 @@ type interpreterEnvironmentReconfigured struct {
 	Interface
 	storage        *Storage
 	coverageReport *CoverageReport
+	// executionLocation is the location of the first program interpreted
+	// after the environment was configured, i.e. the executed transaction or script
+	executionLocation common.Location
 }
 
 type interpreterEnvironment struct {
@@ -121,7 +127,7 @@ func NewInterpreterEnvironment(config Config) *interpreterEnvironment {
 
 	env := &interpreterEnvironment{
 		config:                        config,
-		checkingEnvironment:           newCheckingEnvironment(),
+		checkingEnvironment:           newCheckingEnvironment(config),
 		defaultBaseActivation:         defaultBaseActivation,
 		stackDepthLimiter:             newStackDepthLimiter(config.StackDepthLimit),
 		SimpleContractAdditionTracker: stdlib.NewSimpleContractAdditionTracker(),
@@ -137,7 +143,7 @@ func (e *interpreterEnvironment) NewInterpreterConfig() *interpreter.Config {
 		MemoryGauge:                    e,
 		ComputationGauge:               e,
 		BaseActivationHandler:          e.getBaseActivation,
-		OnEventEmitted:                 newOnEventEmittedHandler(&e.Interface),
+		OnEventEmitted:                 e.newOnEventEmittedHandler(),
 		InjectedCompositeFieldsHandler: newInjectedCompositeFieldsHandler(e),
 		UUIDHandler:                    newUUIDHandler(&e.Interface),
 		ContractValueHandler:           e.newContractValueHandler(),
@@ -191,6 +197,7 @@ func (e *interpreterEnvironment) Configure(
 	e.storage = storage
 	e.InterpreterConfig.Storage = storage
 	e.coverageReport = coverageReport
+	e.executionLocation = nil
 	e.stackDepthLimiter.depth = 0
 
 	e.checkingEnvironment.configure(
@@ -254,10 +261,45 @@ func (e *interpreterEnvironment) EmitEvent(
 		locationRange,
 		eventType,
 		values,
-		e.Interface.EmitEvent,
+		func(event cadence.Event) error {
+			err := e.Interface.EmitEvent(event)
+			if err != nil {
+				return err
+			}
+
+			eventBus := e.config.EventBus
+			if eventBus == nil {
+				return nil
+			}
+
+			return eventBus.Publish(&EmittedEvent{
+				Event:             event,
+				Location:          locationRange.Location,
+				Range:             ast.NewUnmeteredRangeFromPositioned(locationRange),
+				ExecutionLocation: e.executionLocation,
+			})
+		},
 	)
 }
 
+func (e *interpreterEnvironment) newOnEventEmittedHandler() interpreter.OnEventEmittedFunc {
+	return func(
+		context interpreter.ValueExportContext,
+		locationRange interpreter.LocationRange,
+		eventType *sema.CompositeType,
+		eventFields []interpreter.Value,
+	) error {
+		e.EmitEvent(
+			context,
+			locationRange,
+			eventType,
+			eventFields,
+		)
+
+		return nil
+	}
+}
+
 func (e *interpreterEnvironment) RecordContractRemoval(location common.AddressLocation) {
 	e.storage.recordContractUpdate(location, nil)
 }
@@ -277,6 +319,10 @@ func (e *interpreterEnvironment) TemporarilyRecordCode(location common.AddressLo
 	e.checkingEnvironment.temporarilyRecordCode(location, code)
 }
 
+func (e *interpreterEnvironment) ParserConfig() parser.Config {
+	return e.checkingEnvironment.parserConfig
+}
+
 func (e *interpreterEnvironment) ParseAndCheckProgram(
 	code []byte,
 	location common.Location,
@@ -517,6 +563,10 @@ func (e *interpreterEnvironment) interpret(
 	*interpreter.Interpreter,
 	error,
 ) {
+	if e.executionLocation == nil {
+		e.executionLocation = location
+	}
+
 	inter, err := e.newInterpreter(location, program)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,189 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sync"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+)
+
+// EmittedEvent is an event that was emitted during the execution of a transaction or script.
+type EmittedEvent struct {
+	// Event is the exported event
+	Event cadence.Event
+	// Location is the location of the program that emitted the event
+	Location common.Location
+	// Range is the source range of the emit statement,
+	// or of the invocation of the built-in function that emitted the event
+	Range ast.Range
+	// ExecutionLocation is the location of the transaction or script
+	// during which execution the event was emitted
+	ExecutionLocation common.Location
+}
+
+// TypeID returns the type ID of the event.
+func (e *EmittedEvent) TypeID() common.TypeID {
+	if e.Event.EventType == nil {
+		return ""
+	}
+	return common.TypeID(e.Event.EventType.ID())
+}
+
+// EventFilter reports whether an event should be delivered to a subscriber.
+type EventFilter func(event *EmittedEvent) bool
+
+// EventHandler receives the events of a subscription.
+// An error aborts the execution which emitted the event.
+type EventHandler func(event *EmittedEvent) error
+
+// EventTypeFilter returns a filter which only accepts events of the given types.
+func EventTypeFilter(typeIDs ...common.TypeID) EventFilter {
+	accepted := make(map[common.TypeID]struct{}, len(typeIDs))
+	for _, typeID := range typeIDs {
+		accepted[typeID] = struct{}{}
+	}
+
+	return func(event *EmittedEvent) bool {
+		_, ok := accepted[event.TypeID()]
+		return ok
+	}
+}
+
+// EventFieldFilter returns a filter which only accepts events
+// which have a field with the given name, and for which value the given predicate holds.
+func EventFieldFilter(fieldName string, predicate func(value cadence.Value) bool) EventFilter {
+	return func(event *EmittedEvent) bool {
+		if event.Event.EventType == nil {
+			return false
+		}
+		value := event.Event.SearchFieldByName(fieldName)
+		if value == nil {
+			return false
+		}
+		return predicate(value)
+	}
+}
+
+// EventLocationFilter returns a filter which only accepts events
+// emitted by the program with the given location.
+func EventLocationFilter(location common.Location) EventFilter {
+	return func(event *EmittedEvent) bool {
+		return event.Location == location
+	}
+}
+
+// AllEventFilters returns a filter which only accepts events accepted by all given filters.
+func AllEventFilters(filters ...EventFilter) EventFilter {
+	return func(event *EmittedEvent) bool {
+		for _, filter := range filters {
+			if !filter(event) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyEventFilter returns a filter which accepts events accepted by any of the given filters.
+func AnyEventFilter(filters ...EventFilter) EventFilter {
+	return func(event *EmittedEvent) bool {
+		for _, filter := range filters {
+			if filter(event) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type eventSubscription struct {
+	filter  EventFilter
+	handler EventHandler
+}
+
+// EventBus delivers the events emitted during executions to subscribers.
+//
+// An event bus can be set in the runtime configuration (see Config.EventBus),
+// and may be shared by multiple runtimes and concurrent executions.
+// Subscribers are called synchronously, after the event was emitted to the runtime interface,
+// and in the order in which they subscribed.
+type EventBus struct {
+	mutex         sync.RWMutex
+	subscriptions []*eventSubscription
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers the given handler for all events accepted by the given filter.
+// A nil filter accepts all events.
+//
+// The returned function cancels the subscription.
+func (b *EventBus) Subscribe(filter EventFilter, handler EventHandler) (unsubscribe func()) {
+	subscription := &eventSubscription{
+		filter:  filter,
+		handler: handler,
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscriptions = append(b.subscriptions, subscription)
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		for i, other := range b.subscriptions {
+			if other == subscription {
+				// Copy, as concurrent publications may still iterate over the old slice
+				subscriptions := make([]*eventSubscription, 0, len(b.subscriptions)-1)
+				subscriptions = append(subscriptions, b.subscriptions[:i]...)
+				subscriptions = append(subscriptions, b.subscriptions[i+1:]...)
+				b.subscriptions = subscriptions
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers the given event to all subscribers whose filter accepts it.
+// Delivery stops at the first handler that returns an error.
+func (b *EventBus) Publish(event *EmittedEvent) error {
+	b.mutex.RLock()
+	subscriptions := b.subscriptions
+	b.mutex.RUnlock()
+
+	for _, subscription := range subscriptions {
+		if subscription.filter != nil && !subscription.filter(event) {
+			continue
+		}
+
+		err := subscription.handler(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	. "github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+func TestRuntimeEventBus(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	contractLocation := common.AddressLocation{
+		Address: address,
+		Name:    "Test",
+	}
+
+	const (
+		depositedTypeID = common.TypeID("A.0000000000000001.Test.Deposited")
+		withdrawnTypeID = common.TypeID("A.0000000000000001.Test.Withdrawn")
+	)
+
+	deployTx := DeploymentTransaction("Test", []byte(`
+      access(all) contract Test {
+
+          access(all) event Deposited(id: UInt64, amount: UFix64)
+
+          access(all) event Withdrawn(id: UInt64)
+
+          access(all) fun deposit(id: UInt64, amount: UFix64) {
+              emit Deposited(id: id, amount: amount)
+          }
+
+          access(all) fun withdraw(id: UInt64) {
+              emit Withdrawn(id: id)
+          }
+      }
+    `))
+
+	const tx = `
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: &Account) {
+              Test.deposit(id: 1, amount: 1.0)
+              Test.deposit(id: 2, amount: 2.0)
+              Test.withdraw(id: 1)
+          }
+      }
+    `
+
+	setup := func(t *testing.T, eventBus *EventBus) (execute func(source string) error, emitted *[]cadence.Event) {
+		runtime := NewTestInterpreterRuntimeWithConfig(Config{
+			AtreeValidationEnabled: true,
+			EventBus:               eventBus,
+		})
+
+		accountCodes := map[common.Location][]byte{}
+		var events []cadence.Event
+
+		runtimeInterface := &TestRuntimeInterface{
+			Storage: NewTestLedger(nil, nil),
+			OnGetSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			OnResolveLocation: NewSingleIdentifierLocationResolver(t),
+			OnUpdateAccountContractCode: func(location common.AddressLocation, code []byte) error {
+				accountCodes[location] = code
+				return nil
+			},
+			OnGetAccountContractCode: func(location common.AddressLocation) (code []byte, err error) {
+				return accountCodes[location], nil
+			},
+			OnEmitEvent: func(event cadence.Event) error {
+				events = append(events, event)
+				return nil
+			},
+		}
+
+		nextTransactionLocation := NewTransactionLocationGenerator()
+
+		execute = func(source string) error {
+			return runtime.ExecuteTransaction(
+				Script{
+					Source: []byte(source),
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  nextTransactionLocation(),
+				},
+			)
+		}
+
+		return execute, &events
+	}
+
+	t.Run("type filter", func(t *testing.T) {
+		t.Parallel()
+
+		eventBus := NewEventBus()
+
+		var received []*EmittedEvent
+		eventBus.Subscribe(
+			EventTypeFilter(depositedTypeID),
+			func(event *EmittedEvent) error {
+				received = append(received, event)
+				return nil
+			},
+		)
+
+		execute, emitted := setup(t, eventBus)
+
+		err := execute(string(deployTx))
+		require.NoError(t, err)
+
+		err = execute(tx)
+		require.NoError(t, err)
+
+		require.Len(t, received, 2)
+
+		// The runtime interface still receives all events,
+		// including the account contract added event
+		assert.Len(t, *emitted, 4)
+
+		event := received[0]
+		assert.Equal(t, depositedTypeID, event.TypeID())
+		assert.Equal(t, contractLocation, event.Location)
+		assert.Equal(t, common.TransactionLocation{0x2}, event.ExecutionLocation)
+		assert.Equal(t, 9, event.Range.StartPos.Line)
+		assert.Equal(t,
+			cadence.UInt64(1),
+			event.Event.SearchFieldByName("id"),
+		)
+	})
+
+	t.Run("field filter", func(t *testing.T) {
+		t.Parallel()
+
+		eventBus := NewEventBus()
+
+		var received []*EmittedEvent
+		eventBus.Subscribe(
+			AllEventFilters(
+				EventLocationFilter(contractLocation),
+				EventFieldFilter("id", func(value cadence.Value) bool {
+					return value == cadence.UInt64(1)
+				}),
+			),
+			func(event *EmittedEvent) error {
+				received = append(received, event)
+				return nil
+			},
+		)
+
+		execute, _ := setup(t, eventBus)
+
+		err := execute(string(deployTx))
+		require.NoError(t, err)
+
+		err = execute(tx)
+		require.NoError(t, err)
+
+		require.Len(t, received, 2)
+		assert.Equal(t, depositedTypeID, received[0].TypeID())
+		assert.Equal(t, withdrawnTypeID, received[1].TypeID())
+	})
+
+	t.Run("built-in event", func(t *testing.T) {
+		t.Parallel()
+
+		eventBus := NewEventBus()
+
+		var received []*EmittedEvent
+		eventBus.Subscribe(
+			EventTypeFilter("flow.AccountContractAdded"),
+			func(event *EmittedEvent) error {
+				received = append(received, event)
+				return nil
+			},
+		)
+
+		execute, _ := setup(t, eventBus)
+
+		err := execute(string(deployTx))
+		require.NoError(t, err)
+
+		require.Len(t, received, 1)
+		assert.Equal(t, common.TransactionLocation{0x1}, received[0].Location)
+		assert.Equal(t, common.TransactionLocation{0x1}, received[0].ExecutionLocation)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		eventBus := NewEventBus()
+
+		var count int
+		unsubscribe := eventBus.Subscribe(
+			nil,
+			func(_ *EmittedEvent) error {
+				count++
+				return nil
+			},
+		)
+
+		execute, _ := setup(t, eventBus)
+
+		err := execute(string(deployTx))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		unsubscribe()
+
+		err = execute(tx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		t.Parallel()
+
+		eventBus := NewEventBus()
+
+		handlerErr := errors.New("handler failed")
+
+		eventBus.Subscribe(
+			EventTypeFilter(withdrawnTypeID),
+			func(_ *EmittedEvent) error {
+				return handlerErr
+			},
+		)
+
+		execute, _ := setup(t, eventBus)
+
+		err := execute(string(deployTx))
+		require.NoError(t, err)
+
+		err = execute(tx)
+		require.ErrorIs(t, err, handlerErr)
+	})
+}
@@ -212,22 +212,3 @@ func newResourceOwnerChangedHandler(i *Interface) interpreter.OnResourceOwnerCha
 		)
 	}
 }
-
-func newOnEventEmittedHandler(i *Interface) interpreter.OnEventEmittedFunc {
-	return func(
-		context interpreter.ValueExportContext,
-		locationRange interpreter.LocationRange,
-		eventType *sema.CompositeType,
-		eventFields []interpreter.Value,
-	) error {
-		EmitEventFields(
-			context,
-			locationRange,
-			eventType,
-			eventFields,
-			(*i).EmitEvent,
-		)
-
-		return nil
-	}
-}
@@ -28,6 +28,7 @@ import (
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
 )
 
 var InvalidLiteralError = parser.NewSyntaxError(
@@ -314,6 +315,254 @@ func fixedPointLiteralValue(memoryGauge common.MemoryGauge, expression ast.Expre
 	return nil, UnsupportedLiteralError
 }
 
+// literalQualifiedIdentifier returns the qualified identifier denoted by the given expression,
+// i.e. an identifier, or a chain of non-optional member accesses on an identifier.
+func literalQualifiedIdentifier(expression ast.Expression) (string, bool) {
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		return expression.Identifier.Identifier, true
+
+	case *ast.MemberExpression:
+		if expression.Optional {
+			return "", false
+		}
+
+		parent, ok := literalQualifiedIdentifier(expression.Expression)
+		if !ok {
+			return "", false
+		}
+
+		return parent + "." + expression.Identifier.Identifier, true
+	}
+
+	return "", false
+}
+
+// literalInvocation returns the invocation expression for a constructor-like literal,
+// e.g. `S(a: 1)` or `InclusiveRange(1, 10)`, if the invoked expression is the given qualified identifier.
+func literalInvocation(
+	expression ast.Expression,
+	qualifiedIdentifier string,
+) (
+	*ast.InvocationExpression,
+	error,
+) {
+	invocationExpression, ok := expression.(*ast.InvocationExpression)
+	if !ok || len(invocationExpression.TypeArguments) > 0 {
+		return nil, LiteralExpressionTypeError
+	}
+
+	invokedIdentifier, ok := literalQualifiedIdentifier(invocationExpression.InvokedExpression)
+	if !ok {
+		return nil, LiteralExpressionTypeError
+	}
+
+	if invokedIdentifier != qualifiedIdentifier {
+		return nil, parser.NewSyntaxError(
+			ast.Position{Line: 1},
+			"literal type %s does not match requested type %s",
+			invokedIdentifier, qualifiedIdentifier,
+		)
+	}
+
+	return invocationExpression, nil
+}
+
+// compositeLiteralValue converts a struct or enum literal, e.g. `S(a: 1, b: "2")` or `E(rawValue: 1)`.
+// Each field of the composite must be given exactly once, as an argument labeled with the field name.
+//
+// NOTE: The value is constructed from the field values directly, the initializer is not called.
+func compositeLiteralValue(
+	context interpreter.ValueExportContext,
+	expression ast.Expression,
+	ty *sema.CompositeType,
+) (
+	cadence.Value,
+	error,
+) {
+	switch ty.Kind {
+	case common.CompositeKindStructure, common.CompositeKindEnum:
+		break
+	default:
+		return nil, UnsupportedLiteralError
+	}
+
+	invocationExpression, err := literalInvocation(expression, ty.QualifiedIdentifier())
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make(map[string]ast.Expression, len(invocationExpression.Arguments))
+	for _, argument := range invocationExpression.Arguments {
+		label := argument.Label
+		if label == "" {
+			return nil, parser.NewSyntaxError(
+				argument.StartPosition(),
+				"missing field name for argument of %s literal",
+				ty.QualifiedIdentifier(),
+			)
+		}
+		if _, ok := arguments[label]; ok {
+			return nil, parser.NewSyntaxError(
+				argument.StartPosition(),
+				"duplicate field %s in %s literal",
+				label, ty.QualifiedIdentifier(),
+			)
+		}
+		arguments[label] = argument.Expression
+	}
+
+	compositeType := ExportType(ty, map[sema.TypeID]cadence.Type{}).(cadence.CompositeType)
+	fields := getCompositeTypeFields(compositeType)
+
+	if len(arguments) != len(fields) {
+		return nil, parser.NewSyntaxError(
+			ast.Position{Line: 1},
+			"invalid number of fields for %s literal: got %d, expected %d",
+			ty.QualifiedIdentifier(),
+			len(arguments),
+			len(fields),
+		)
+	}
+
+	constructor := func() ([]cadence.Value, error) {
+		values := make([]cadence.Value, len(fields))
+
+		for i, field := range fields {
+			argument, ok := arguments[field.Identifier]
+			if !ok {
+				return nil, parser.NewSyntaxError(
+					ast.Position{Line: 1},
+					"missing field %s in %s literal",
+					field.Identifier, ty.QualifiedIdentifier(),
+				)
+			}
+
+			member, ok := ty.Members.Get(field.Identifier)
+			if !ok {
+				return nil, UnsupportedLiteralError
+			}
+
+			value, err := LiteralValue(context, argument, member.TypeAnnotation.Type)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+
+		return values, nil
+	}
+
+	switch compositeType := compositeType.(type) {
+	case *cadence.StructType:
+		structValue, err := cadence.NewMeteredStruct(context, len(fields), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return structValue.WithType(compositeType), nil
+
+	case *cadence.EnumType:
+		enumValue, err := cadence.NewMeteredEnum(context, len(fields), constructor)
+		if err != nil {
+			return nil, err
+		}
+		return enumValue.WithType(compositeType), nil
+	}
+
+	return nil, UnsupportedLiteralError
+}
+
+// inclusiveRangeLiteralValue converts an inclusive range literal, e.g. `InclusiveRange(1, 10, step: 2)`.
+// If the step is omitted, it defaults to 1, or -1 if the start is greater than the end.
+func inclusiveRangeLiteralValue(
+	context interpreter.ValueExportContext,
+	expression ast.Expression,
+	ty *sema.InclusiveRangeType,
+) (
+	cadence.Value,
+	error,
+) {
+	invocationExpression, err := literalInvocation(expression, stdlib.InclusiveRangeConstructorFunction.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := invocationExpression.Arguments
+	argumentCount := len(arguments)
+	if argumentCount < 2 || argumentCount > 3 ||
+		arguments[0].Label != "" ||
+		arguments[1].Label != "" ||
+		(argumentCount == 3 && arguments[2].Label != sema.InclusiveRangeTypeStepFieldName) {
+
+		return nil, InvalidLiteralError
+	}
+
+	bounds := make([]*big.Int, 2)
+	for i, argument := range arguments[:2] {
+		integerExpression, ok := argument.Expression.(*ast.IntegerExpression)
+		if !ok {
+			return nil, LiteralExpressionTypeError
+		}
+		bounds[i] = integerExpression.Value
+	}
+	start, end := bounds[0], bounds[1]
+
+	var stepExpression ast.Expression
+	if argumentCount == 3 {
+		stepExpression = arguments[2].Expression
+	} else {
+		step := big.NewInt(1)
+		if start.Cmp(end) > 0 {
+			step.SetInt64(-1)
+		}
+		stepExpression = ast.NewIntegerExpression(
+			context,
+			[]byte(step.String()),
+			step,
+			10,
+			ast.EmptyRange,
+		)
+	}
+
+	stepIntegerExpression, ok := stepExpression.(*ast.IntegerExpression)
+	if !ok {
+		return nil, LiteralExpressionTypeError
+	}
+	step := stepIntegerExpression.Value
+
+	// The step must be non-zero and move from the start towards the end
+	if step.Sign() == 0 ||
+		(start.Cmp(end) != 0 && step.Sign() != end.Cmp(start)) {
+
+		return nil, parser.NewSyntaxError(
+			ast.Position{Line: 1},
+			"invalid step %s for inclusive range from %s to %s",
+			step, start, end,
+		)
+	}
+
+	values := make([]cadence.Value, 3)
+	for i, expression := range []ast.Expression{
+		arguments[0].Expression,
+		arguments[1].Expression,
+		stepExpression,
+	} {
+		values[i], err = integerLiteralValue(context, expression, ty.MemberType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inclusiveRangeType := ExportType(ty, map[sema.TypeID]cadence.Type{}).(*cadence.InclusiveRangeType)
+
+	return cadence.NewMeteredInclusiveRange(
+		context,
+		values[0],
+		values[1],
+		values[2],
+	).WithType(inclusiveRangeType), nil
+}
+
 func LiteralValue(
 	context interpreter.ValueExportContext,
 	expression ast.Expression,
@@ -355,15 +604,10 @@ func LiteralValue(
 		return array.WithType(arrayCadenceType), err
 
 	case *sema.OptionalType:
+		// `nil` is nil, also for nested optional types, e.g. `Int??`,
+		// like in programs
 		if _, ok := expression.(*ast.NilExpression); ok {
-			value := cadence.NewMeteredOptional(context, nil)
-			for {
-				ty, ok = ty.Type.(*sema.OptionalType)
-				if !ok {
-					return value, nil
-				}
-				value = cadence.NewMeteredOptional(context, value)
-			}
+			return cadence.NewMeteredOptional(context, nil), nil
 		}
 
 		converted, err := LiteralValue(context, expression, ty.Type)
@@ -430,6 +674,12 @@ func LiteralValue(
 		}
 
 		return cadence.BytesToAddress(expression.Value.Bytes()), nil
+
+	case *sema.CompositeType:
+		return compositeLiteralValue(context, expression, ty)
+
+	case *sema.InclusiveRangeType:
+		return inclusiveRangeLiteralValue(context, expression, ty)
 	}
 
 	switch ty {
@@ -454,6 +704,24 @@ func LiteralValue(
 				return expression.Value
 			},
 		)
+
+	case sema.CharacterType:
+		expression, ok := expression.(*ast.StringExpression)
+		if !ok {
+			return nil, LiteralExpressionTypeError
+		}
+
+		if !sema.IsValidCharacter(expression.Value) {
+			return nil, InvalidLiteralError
+		}
+
+		return cadence.NewMeteredCharacter(
+			context,
+			common.NewCadenceCharacterMemoryUsage(len(expression.Value)),
+			func() string {
+				return expression.Value
+			},
+		)
 	}
 
 	switch {
@@ -0,0 +1,197 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"strings"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
+)
+
+// EncodeLiteral returns the canonical Cadence literal for the given value.
+//
+// The result can be parsed back into an equal value using ParseLiteral,
+// given the static type of the value.
+// Values which have no literal syntax, e.g. resources, capabilities and type values,
+// cannot be encoded and result in an error.
+func EncodeLiteral(value cadence.Value) (string, error) {
+	var builder strings.Builder
+	err := encodeLiteral(&builder, value)
+	if err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func encodeLiteral(builder *strings.Builder, value cadence.Value) error {
+	switch value := value.(type) {
+	case cadence.Optional:
+		// Nested optionals, e.g. `Int??`, cannot be distinguished in literals,
+		// and are written as the innermost value, or nil.
+		// A non-nil optional which contains nil, e.g. `Some(nil)` of type `Int??`,
+		// has no literal, as `nil` is parsed as nil
+		if value.Value == nil {
+			builder.WriteString("nil")
+			return nil
+		}
+		if innerOptional, ok := value.Value.(cadence.Optional); ok && innerOptional.Value == nil {
+			return errors.NewDefaultUserError("cannot encode non-nil optional of nil as literal")
+		}
+		return encodeLiteral(builder, value.Value)
+
+	case cadence.Bool:
+		builder.WriteString(value.String())
+
+	case cadence.String:
+		builder.WriteString(ast.QuoteString(string(value)))
+
+	case cadence.Character:
+		builder.WriteString(ast.QuoteString(string(value)))
+
+	case cadence.Address:
+		builder.WriteString(value.String())
+
+	case cadence.Path:
+		builder.WriteString(value.String())
+
+	case cadence.Int, cadence.Int8, cadence.Int16, cadence.Int32, cadence.Int64, cadence.Int128, cadence.Int256,
+		cadence.UInt, cadence.UInt8, cadence.UInt16, cadence.UInt32, cadence.UInt64, cadence.UInt128, cadence.UInt256,
+		cadence.Word8, cadence.Word16, cadence.Word32, cadence.Word64, cadence.Word128, cadence.Word256,
+		cadence.Fix64, cadence.UFix64:
+
+		builder.WriteString(value.String())
+
+	case cadence.Array:
+		builder.WriteByte('[')
+		for i, element := range value.Values {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			err := encodeLiteral(builder, element)
+			if err != nil {
+				return err
+			}
+		}
+		builder.WriteByte(']')
+
+	case cadence.Dictionary:
+		builder.WriteByte('{')
+		for i, pair := range value.Pairs {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			err := encodeLiteral(builder, pair.Key)
+			if err != nil {
+				return err
+			}
+			builder.WriteString(": ")
+			err = encodeLiteral(builder, pair.Value)
+			if err != nil {
+				return err
+			}
+		}
+		builder.WriteByte('}')
+
+	case cadence.Struct:
+		if value.StructType == nil {
+			return errors.NewDefaultUserError("cannot encode struct without type as literal")
+		}
+		if len(value.Attachments()) > 0 {
+			return errors.NewDefaultUserError(
+				"cannot encode struct with attachments as literal: %s",
+				value.StructType.ID(),
+			)
+		}
+		return encodeCompositeLiteral(builder, value, value.StructType)
+
+	case cadence.Enum:
+		if value.EnumType == nil {
+			return errors.NewDefaultUserError("cannot encode enum without type as literal")
+		}
+		return encodeCompositeLiteral(builder, value, value.EnumType)
+
+	case *cadence.InclusiveRange:
+		builder.WriteString(stdlib.InclusiveRangeConstructorFunction.Name)
+		builder.WriteByte('(')
+		err := encodeLiteral(builder, value.Start)
+		if err != nil {
+			return err
+		}
+		builder.WriteString(", ")
+		err = encodeLiteral(builder, value.End)
+		if err != nil {
+			return err
+		}
+		builder.WriteString(", ")
+		builder.WriteString(sema.InclusiveRangeTypeStepFieldName)
+		builder.WriteString(": ")
+		err = encodeLiteral(builder, value.Step)
+		if err != nil {
+			return err
+		}
+		builder.WriteByte(')')
+
+	default:
+		return errors.NewDefaultUserError(
+			"cannot encode value as literal: unsupported value %T",
+			value,
+		)
+	}
+
+	return nil
+}
+
+func encodeCompositeLiteral(
+	builder *strings.Builder,
+	value cadence.Composite,
+	compositeType cadence.CompositeType,
+) error {
+	fields := getCompositeTypeFields(compositeType)
+	fieldValues := getCompositeFieldValues(value)
+
+	if len(fields) != len(fieldValues) {
+		return errors.NewDefaultUserError(
+			"cannot encode composite as literal: %s has %d fields, got %d values",
+			compositeType.ID(),
+			len(fields),
+			len(fieldValues),
+		)
+	}
+
+	builder.WriteString(compositeType.CompositeTypeQualifiedIdentifier())
+	builder.WriteByte('(')
+	for i, field := range fields {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(field.Identifier)
+		builder.WriteString(": ")
+		err := encodeLiteral(builder, fieldValues[i])
+		if err != nil {
+			return err
+		}
+	}
+	builder.WriteByte(')')
+
+	return nil
+}
@@ -21,6 +21,8 @@ package runtime_test
 import (
 	"fmt"
 	"math/big"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -31,6 +33,7 @@ import (
 	"github.com/onflow/cadence/sema"
 	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+	. "github.com/onflow/cadence/test_utils/sema_utils"
 )
 
 func TestRuntimeParseLiteral(t *testing.T) {
@@ -52,6 +55,22 @@ func TestRuntimeParseLiteral(t *testing.T) {
 		require.Nil(t, value)
 	})
 
+	t.Run("Character, valid literal", func(t *testing.T) {
+		value, err := ParseLiteral(`"\u{1F1E8}\u{1F1ED}"`, sema.CharacterType, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			cadence.Character("\U0001F1E8\U0001F1ED"),
+			value,
+		)
+	})
+
+	t.Run("Character, invalid literal", func(t *testing.T) {
+		value, err := ParseLiteral(`"ab"`, sema.CharacterType, NewTestInterpreter(t))
+		RequireError(t, err)
+
+		require.Nil(t, value)
+	})
+
 	t.Run("Bool, valid literal", func(t *testing.T) {
 		value, err := ParseLiteral(`true`, sema.BoolType, NewTestInterpreter(t))
 		require.NoError(t, err)
@@ -93,9 +112,7 @@ func TestRuntimeParseLiteral(t *testing.T) {
 		)
 		require.NoError(t, err)
 		require.Equal(t,
-			cadence.NewOptional(
-				cadence.NewOptional(nil),
-			),
+			cadence.NewOptional(nil),
 			value,
 		)
 	})
@@ -811,3 +828,627 @@ func TestRuntimeParseLiteralArgumentList(t *testing.T) {
 		RequireError(t, err)
 	})
 }
+
+const literalTestCode = `
+  access(all) struct S {
+      access(all) let a: Int
+      access(all) let b: String?
+
+      init(a: Int, b: String?) {
+          self.a = a
+          self.b = b
+      }
+  }
+
+  access(all) enum E: UInt8 {
+      access(all) case x
+      access(all) case y
+  }
+
+  access(all) contract C {
+      access(all) struct T {
+          access(all) let s: S
+
+          init(s: S) {
+              self.s = s
+          }
+      }
+  }
+`
+
+type literalTestTypes struct {
+	s, e, t *sema.CompositeType
+}
+
+func parseLiteralTestTypes(t *testing.T) literalTestTypes {
+	checker, err := ParseAndCheck(t, literalTestCode)
+	require.NoError(t, err)
+
+	contractType := RequireGlobalType(t, checker.Elaboration, "C").(*sema.CompositeType)
+	nestedType, ok := contractType.NestedTypes.Get("T")
+	require.True(t, ok)
+
+	return literalTestTypes{
+		s: RequireGlobalType(t, checker.Elaboration, "S").(*sema.CompositeType),
+		e: RequireGlobalType(t, checker.Elaboration, "E").(*sema.CompositeType),
+		t: nestedType.(*sema.CompositeType),
+	}
+}
+
+func exportLiteralTestType(ty sema.Type) cadence.Type {
+	return ExportType(ty, map[sema.TypeID]cadence.Type{})
+}
+
+func TestRuntimeParseLiteralComposite(t *testing.T) {
+	t.Parallel()
+
+	types := parseLiteralTestTypes(t)
+
+	structType := exportLiteralTestType(types.s).(*cadence.StructType)
+
+	newStruct := func(a int, b cadence.Value) cadence.Struct {
+		return cadence.NewStruct([]cadence.Value{
+			cadence.NewInt(a),
+			cadence.NewOptional(b),
+		}).WithType(structType)
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`S(a: 1, b: "x")`, types.s, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t, newStruct(1, cadence.String("x")), value)
+	})
+
+	t.Run("struct, fields in different order", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`S(b: nil, a: -2)`, types.s, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t, newStruct(-2, nil), value)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`C.T(s: S(a: 1, b: nil))`, types.t, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			cadence.NewStruct([]cadence.Value{
+				newStruct(1, nil),
+			}).WithType(exportLiteralTestType(types.t).(*cadence.StructType)),
+			value,
+		)
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`E(rawValue: 1)`, types.e, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			cadence.NewEnum([]cadence.Value{
+				cadence.UInt8(1),
+			}).WithType(exportLiteralTestType(types.e).(*cadence.EnumType)),
+			value,
+		)
+	})
+
+	for name, literal := range map[string]string{
+		"missing field":       `S(a: 1)`,
+		"unknown field":       `S(a: 1, b: nil, c: 2)`,
+		"duplicate field":     `S(a: 1, a: 2)`,
+		"unlabeled argument":  `S(1, nil)`,
+		"invalid field value": `S(a: "1", b: nil)`,
+		"other type":          `T(s: S(a: 1, b: nil))`,
+		"type arguments":      `S<Int>(a: 1, b: nil)`,
+		"not an invocation":   `S`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := ParseLiteral(literal, types.s, NewTestInterpreter(t))
+			RequireError(t, err)
+
+			require.Nil(t, value)
+		})
+	}
+
+	t.Run("invalid enum raw value", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`E(rawValue: 256)`, types.e, NewTestInterpreter(t))
+		RequireError(t, err)
+
+		require.Nil(t, value)
+	})
+}
+
+func TestRuntimeParseLiteralInclusiveRange(t *testing.T) {
+	t.Parallel()
+
+	newRange := func(memberType sema.Type, start, end, step cadence.Value) *cadence.InclusiveRange {
+		rangeType := exportLiteralTestType(&sema.InclusiveRangeType{
+			MemberType: memberType,
+		}).(*cadence.InclusiveRangeType)
+
+		return cadence.NewInclusiveRange(start, end, step).WithType(rangeType)
+	}
+
+	intRangeType := &sema.InclusiveRangeType{
+		MemberType: sema.IntType,
+	}
+
+	uint8RangeType := &sema.InclusiveRangeType{
+		MemberType: sema.UInt8Type,
+	}
+
+	t.Run("default step", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`InclusiveRange(1, 10)`, uint8RangeType, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			newRange(sema.UInt8Type, cadence.UInt8(1), cadence.UInt8(10), cadence.UInt8(1)),
+			value,
+		)
+	})
+
+	t.Run("default negative step", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`InclusiveRange(10, -1)`, intRangeType, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			newRange(sema.IntType, cadence.NewInt(10), cadence.NewInt(-1), cadence.NewInt(-1)),
+			value,
+		)
+	})
+
+	t.Run("step", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := ParseLiteral(`InclusiveRange(1, 10, step: 3)`, intRangeType, NewTestInterpreter(t))
+		require.NoError(t, err)
+		require.Equal(t,
+			newRange(sema.IntType, cadence.NewInt(1), cadence.NewInt(10), cadence.NewInt(3)),
+			value,
+		)
+	})
+
+	for name, test := range map[string]struct {
+		literal string
+		ty      sema.Type
+	}{
+		"default negative step, unsigned": {`InclusiveRange(10, 1)`, uint8RangeType},
+		"zero step":                       {`InclusiveRange(1, 10, step: 0)`, intRangeType},
+		"step in wrong direction":         {`InclusiveRange(1, 10, step: -1)`, intRangeType},
+		"unlabeled step":                  {`InclusiveRange(1, 10, 1)`, intRangeType},
+		"missing end":                     {`InclusiveRange(1)`, intRangeType},
+		"out of range":                    {`InclusiveRange(1, 256)`, uint8RangeType},
+		"other constructor":               {`Range(1, 10)`, intRangeType},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := ParseLiteral(test.literal, test.ty, NewTestInterpreter(t))
+			RequireError(t, err)
+
+			require.Nil(t, value)
+		})
+	}
+}
+
+func TestRuntimeEncodeLiteral(t *testing.T) {
+	t.Parallel()
+
+	types := parseLiteralTestTypes(t)
+
+	structType := exportLiteralTestType(types.s).(*cadence.StructType)
+
+	for _, test := range []struct {
+		value    cadence.Value
+		expected string
+	}{
+		{cadence.NewBool(true), `true`},
+		{cadence.String("a\"b\né"), `"a\"b\n\u{e9}"`},
+		{cadence.Character("\\"), `"\\"`},
+		{cadence.BytesToAddress([]byte{0x1}), `0x0000000000000001`},
+		{cadence.NewInt(-42), `-42`},
+		{cadence.UInt8(255), `255`},
+		{cadence.Fix64(-1_50000000), `-1.50000000`},
+		{cadence.UFix64(1), `0.00000001`},
+		{cadence.MustNewPath(common.PathDomainStorage, "foo"), `/storage/foo`},
+		{cadence.NewOptional(nil), `nil`},
+		{cadence.NewOptional(cadence.NewOptional(cadence.NewInt(1))), `1`},
+		{
+			cadence.NewArray([]cadence.Value{cadence.NewInt(1), cadence.NewInt(2)}),
+			`[1, 2]`,
+		},
+		{
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{Key: cadence.String("a"), Value: cadence.NewInt(1)},
+				{Key: cadence.String("b"), Value: cadence.NewInt(2)},
+			}),
+			`{"a": 1, "b": 2}`,
+		},
+		{
+			cadence.NewStruct([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewOptional(nil),
+			}).WithType(structType),
+			`S(a: 1, b: nil)`,
+		},
+		{
+			cadence.NewStruct([]cadence.Value{
+				cadence.NewStruct([]cadence.Value{
+					cadence.NewInt(1),
+					cadence.NewOptional(cadence.String("x")),
+				}).WithType(structType),
+			}).WithType(exportLiteralTestType(types.t).(*cadence.StructType)),
+			`C.T(s: S(a: 1, b: "x"))`,
+		},
+		{
+			cadence.NewEnum([]cadence.Value{
+				cadence.UInt8(1),
+			}).WithType(exportLiteralTestType(types.e).(*cadence.EnumType)),
+			`E(rawValue: 1)`,
+		},
+		{
+			cadence.NewInclusiveRange(cadence.NewInt(1), cadence.NewInt(10), cadence.NewInt(2)),
+			`InclusiveRange(1, 10, step: 2)`,
+		},
+	} {
+		literal, err := EncodeLiteral(test.value)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, literal)
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []cadence.Value{
+			cadence.Void{},
+			cadence.NewTypeValue(cadence.IntType),
+			cadence.NewCapability(1, cadence.BytesToAddress([]byte{0x1}), cadence.IntType),
+			cadence.NewResource(nil),
+			cadence.NewArray([]cadence.Value{cadence.Void{}}),
+			cadence.NewOptional(cadence.NewOptional(nil)),
+			cadence.NewArray([]cadence.Value{
+				cadence.NewOptional(cadence.NewOptional(cadence.NewOptional(nil))),
+			}),
+			cadence.NewStruct(nil),
+			cadence.NewStruct(nil).
+				WithType(cadence.NewStructType(TestLocation, "S", []cadence.Field{}, nil)).
+				WithAttachments([]cadence.Attachment{
+					cadence.NewAttachment(nil).
+						WithType(cadence.NewAttachmentType(TestLocation, "A", nil, []cadence.Field{}, nil)),
+				}),
+		} {
+			_, err := EncodeLiteral(value)
+			RequireError(t, err)
+		}
+	})
+}
+
+// TestRuntimeLiteralRoundTrip checks that values of randomly generated types
+// round-trip through EncodeLiteral and ParseLiteral
+func TestRuntimeLiteralRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	types := parseLiteralTestTypes(t)
+
+	const iterations = 1000
+
+	random := rand.New(rand.NewSource(42))
+
+	generator := literalGenerator{
+		random: random,
+		types:  types,
+	}
+
+	inter := NewTestInterpreter(t)
+
+	for i := 0; i < iterations; i++ {
+		ty := generator.generateType(3)
+		value := generator.generateValue(ty)
+
+		literal, err := EncodeLiteral(value)
+		if containsOptionalOfNil(value) {
+			RequireError(t, err)
+			continue
+		}
+		require.NoError(t, err, ty.QualifiedString())
+
+		parsed, err := ParseLiteral(literal, ty, inter)
+		require.NoError(t, err, "%s: %s", ty.QualifiedString(), literal)
+
+		require.Equal(t, value, parsed, "%s: %s", ty.QualifiedString(), literal)
+	}
+}
+
+// containsOptionalOfNil returns true if the given value is or contains a non-nil optional of nil,
+// e.g. `Some(nil)` of type `Int??`, which has no literal
+func containsOptionalOfNil(value cadence.Value) bool {
+	switch value := value.(type) {
+	case cadence.Optional:
+		if innerOptional, ok := value.Value.(cadence.Optional); ok && innerOptional.Value == nil {
+			return true
+		}
+		return value.Value != nil && containsOptionalOfNil(value.Value)
+
+	case cadence.Array:
+		return slices.ContainsFunc(value.Values, containsOptionalOfNil)
+
+	case cadence.Dictionary:
+		return slices.ContainsFunc(value.Pairs, func(pair cadence.KeyValuePair) bool {
+			return containsOptionalOfNil(pair.Key) ||
+				containsOptionalOfNil(pair.Value)
+		})
+
+	case cadence.Struct:
+		for _, field := range cadence.FieldsMappedByName(value) { //nolint:maprange
+			if containsOptionalOfNil(field) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type literalGenerator struct {
+	random *rand.Rand
+	types  literalTestTypes
+}
+
+var literalTestSimpleTypes = []sema.Type{
+	sema.BoolType,
+	sema.StringType,
+	sema.CharacterType,
+	sema.TheAddressType,
+	sema.IntType,
+	sema.Int8Type,
+	sema.Int64Type,
+	sema.Int256Type,
+	sema.UIntType,
+	sema.UInt64Type,
+	sema.UInt128Type,
+	sema.Word8Type,
+	sema.Word256Type,
+	sema.Fix64Type,
+	sema.UFix64Type,
+	sema.StoragePathType,
+	sema.PublicPathType,
+}
+
+var literalTestKeyTypes = []sema.Type{
+	sema.StringType,
+	sema.IntType,
+	sema.UInt8Type,
+}
+
+var literalTestCharacters = []string{"a", "\"", "\\", "\n", "é", "\U0001F600", "\U0001F1E8\U0001F1ED"}
+
+func (g literalGenerator) generateType(depth int) sema.Type {
+	const compositeTypeCount = 4
+
+	choiceCount := len(literalTestSimpleTypes) + compositeTypeCount
+	if depth > 0 {
+		// optional, variable-sized array, constant-sized array, dictionary
+		choiceCount += 4
+	}
+
+	choice := g.random.Intn(choiceCount)
+	if choice < len(literalTestSimpleTypes) {
+		return literalTestSimpleTypes[choice]
+	}
+	choice -= len(literalTestSimpleTypes)
+
+	switch choice {
+	case 0:
+		return g.types.s
+	case 1:
+		return g.types.e
+	case 2:
+		return g.types.t
+	case 3:
+		return &sema.InclusiveRangeType{
+			MemberType: sema.Int16Type,
+		}
+	case 4:
+		return &sema.OptionalType{
+			Type: g.generateType(depth - 1),
+		}
+	case 5:
+		return &sema.VariableSizedType{
+			Type: g.generateType(depth - 1),
+		}
+	case 6:
+		return &sema.ConstantSizedType{
+			Type: g.generateType(depth - 1),
+			Size: int64(g.random.Intn(3)),
+		}
+	default:
+		return &sema.DictionaryType{
+			KeyType:   literalTestKeyTypes[g.random.Intn(len(literalTestKeyTypes))],
+			ValueType: g.generateType(depth - 1),
+		}
+	}
+}
+
+func (g literalGenerator) generateBigInt(bits int, signed bool) *big.Int {
+	value := new(big.Int).Rand(g.random, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	if signed {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(bits-1)))
+	}
+	return value
+}
+
+func (g literalGenerator) generateString() string {
+	length := g.random.Intn(5)
+	var result string
+	for i := 0; i < length; i++ {
+		result += literalTestCharacters[g.random.Intn(len(literalTestCharacters))]
+	}
+	return result
+}
+
+func (g literalGenerator) generateValue(ty sema.Type) cadence.Value {
+	switch ty := ty.(type) {
+	case *sema.OptionalType:
+		if g.random.Intn(3) == 0 {
+			// Generate every shape of nil of a nested optional type,
+			// e.g. nil and `Some(nil)` for `Int??`
+			value := cadence.NewOptional(nil)
+			for {
+				var ok bool
+				ty, ok = ty.Type.(*sema.OptionalType)
+				if !ok || g.random.Intn(2) == 0 {
+					return value
+				}
+				value = cadence.NewOptional(value)
+			}
+		}
+		return cadence.NewOptional(g.generateValue(ty.Type))
+
+	case *sema.VariableSizedType:
+		values := make([]cadence.Value, g.random.Intn(3))
+		for i := range values {
+			values[i] = g.generateValue(ty.Type)
+		}
+		return cadence.NewArray(values).
+			WithType(exportLiteralTestType(ty).(cadence.ArrayType))
+
+	case *sema.ConstantSizedType:
+		values := make([]cadence.Value, ty.Size)
+		for i := range values {
+			values[i] = g.generateValue(ty.Type)
+		}
+		return cadence.NewArray(values).
+			WithType(exportLiteralTestType(ty).(cadence.ArrayType))
+
+	case *sema.DictionaryType:
+		count := g.random.Intn(3)
+		pairs := make([]cadence.KeyValuePair, 0, count)
+		keys := map[string]struct{}{}
+		for i := 0; i < count; i++ {
+			key := g.generateValue(ty.KeyType)
+			if _, ok := keys[key.String()]; ok {
+				continue
+			}
+			keys[key.String()] = struct{}{}
+
+			pairs = append(pairs, cadence.KeyValuePair{
+				Key:   key,
+				Value: g.generateValue(ty.ValueType),
+			})
+		}
+		return cadence.NewDictionary(pairs).
+			WithType(exportLiteralTestType(ty).(*cadence.DictionaryType))
+
+	case *sema.InclusiveRangeType:
+		start := int16(g.random.Intn(200) - 100)
+		end := int16(g.random.Intn(200) - 100)
+		step := int16(g.random.Intn(10) + 1)
+		if start > end {
+			step = -step
+		}
+		return cadence.NewInclusiveRange(
+			cadence.Int16(start),
+			cadence.Int16(end),
+			cadence.Int16(step),
+		).WithType(exportLiteralTestType(ty).(*cadence.InclusiveRangeType))
+
+	case *sema.CompositeType:
+		switch ty {
+		case g.types.s:
+			return cadence.NewStruct([]cadence.Value{
+				g.generateValue(sema.IntType),
+				g.generateValue(&sema.OptionalType{Type: sema.StringType}),
+			}).WithType(exportLiteralTestType(ty).(*cadence.StructType))
+
+		case g.types.e:
+			return cadence.NewEnum([]cadence.Value{
+				cadence.UInt8(g.random.Intn(2)),
+			}).WithType(exportLiteralTestType(ty).(*cadence.EnumType))
+
+		case g.types.t:
+			return cadence.NewStruct([]cadence.Value{
+				g.generateValue(g.types.s),
+			}).WithType(exportLiteralTestType(ty).(*cadence.StructType))
+		}
+
+	case *sema.AddressType:
+		var address cadence.Address
+		g.random.Read(address[:])
+		return address
+	}
+
+	switch ty {
+	case sema.BoolType:
+		return cadence.NewBool(g.random.Intn(2) == 0)
+	case sema.StringType:
+		return cadence.String(g.generateString())
+	case sema.CharacterType:
+		return cadence.Character(literalTestCharacters[g.random.Intn(len(literalTestCharacters))])
+	case sema.IntType:
+		return cadence.NewIntFromBig(g.generateBigInt(100, true))
+	case sema.Int8Type:
+		return cadence.Int8(g.random.Intn(256) - 128)
+	case sema.Int64Type:
+		return cadence.Int64(g.generateBigInt(64, true).Int64())
+	case sema.Int256Type:
+		value, err := cadence.NewInt256FromBig(g.generateBigInt(256, true))
+		if err != nil {
+			panic(err)
+		}
+		return value
+	case sema.UIntType:
+		value, err := cadence.NewUIntFromBig(g.generateBigInt(100, false))
+		if err != nil {
+			panic(err)
+		}
+		return value
+	case sema.UInt64Type:
+		return cadence.UInt64(g.random.Uint64())
+	case sema.UInt128Type:
+		value, err := cadence.NewUInt128FromBig(g.generateBigInt(128, false))
+		if err != nil {
+			panic(err)
+		}
+		return value
+	case sema.UInt8Type:
+		return cadence.UInt8(g.random.Intn(256))
+	case sema.Word8Type:
+		return cadence.Word8(g.random.Intn(256))
+	case sema.Word256Type:
+		value, err := cadence.NewWord256FromBig(g.generateBigInt(256, false))
+		if err != nil {
+			panic(err)
+		}
+		return value
+	case sema.Fix64Type:
+		// Exclude the minimum value, its literal overflows when negated
+		return cadence.Fix64(g.random.Int63() * int64(1-2*g.random.Intn(2)))
+	case sema.UFix64Type:
+		return cadence.UFix64(g.random.Uint64())
+	case sema.StoragePathType:
+		return cadence.MustNewPath(common.PathDomainStorage, "p"+g.generateIdentifier())
+	case sema.PublicPathType:
+		return cadence.MustNewPath(common.PathDomainPublic, "p"+g.generateIdentifier())
+	}
+
+	panic(fmt.Errorf("unsupported type: %s", ty))
+}
+
+func (g literalGenerator) generateIdentifier() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz_0123456789"
+	length := g.random.Intn(8)
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = letters[g.random.Intn(len(letters))]
+	}
+	return string(result)
+}
@@ -12822,3 +12822,125 @@ func TestRuntimeInvokeContractFunctionImported(t *testing.T) {
 		events[0].FieldsMappedByName()["x"],
 	)
 }
+
+func TestRuntimeGenericFunction(t *testing.T) {
+
+	t.Parallel()
+
+	importedScript := []byte(`
+      access(all) fun firstOr<T: AnyStruct>(_ xs: [T], _ d: T): T {
+          if xs.length == 0 {
+              return d
+          }
+          return xs[0]
+      }
+    `)
+
+	script := []byte(`
+      import "imported"
+
+      access(all) fun main(): [String] {
+          return [
+              firstOr(["a", "b"], "c"),
+              firstOr<String>([], "d")
+          ]
+      }
+    `)
+
+	execute := func(t *testing.T, typeParametersEnabled bool) (cadence.Value, error) {
+		config := DefaultTestInterpreterConfig
+		config.TypeParametersEnabled = typeParametersEnabled
+		runtime := NewTestInterpreterRuntimeWithConfig(config)
+
+		runtimeInterface := &TestRuntimeInterface{
+			OnGetCode: func(location Location) (bytes []byte, err error) {
+				switch location {
+				case common.StringLocation("imported"):
+					return importedScript, nil
+				default:
+					return nil, fmt.Errorf("unknown import location: %s", location)
+				}
+			},
+		}
+
+		return runtime.ExecuteScript(
+			Script{
+				Source: script,
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+	}
+
+	t.Run("type parameters enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := execute(t, true)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.String("d"),
+			}).WithType(cadence.NewVariableSizedArrayType(cadence.StringType)),
+			value,
+		)
+	})
+
+	t.Run("type parameters disabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := execute(t, false)
+		RequireError(t, err)
+
+		var parserError parser.Error
+		require.ErrorAs(t, err, &parserError)
+	})
+}
+
+func TestRuntimeNativeGenericFunction(t *testing.T) {
+
+	t.Parallel()
+
+	script := []byte(`
+      access(all) fun main(): Int {
+          let account = getAuthAccount<auth(Storage) &Account>(0x1)
+          account.storage.save([1, 2, 3], to: /storage/xs)
+          let xs = account.storage.borrow<&[Int]>(from: /storage/xs)!
+          return xs.length + Int(revertibleRandom<UInt8>(modulo: 1))
+      }
+    `)
+
+	for _, typeParametersEnabled := range []bool{true, false} {
+
+		t.Run(fmt.Sprintf("type parameters enabled: %t", typeParametersEnabled), func(t *testing.T) {
+
+			t.Parallel()
+
+			config := DefaultTestInterpreterConfig
+			config.TypeParametersEnabled = typeParametersEnabled
+			runtime := NewTestInterpreterRuntimeWithConfig(config)
+
+			runtimeInterface := &TestRuntimeInterface{
+				Storage: NewTestLedger(nil, nil),
+			}
+
+			value, err := runtime.ExecuteScript(
+				Script{
+					Source: script,
+				},
+				Context{
+					Interface: runtimeInterface,
+					Location:  common.ScriptLocation{},
+				},
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, cadence.NewInt(3), value)
+		})
+	}
+}
@@ -22,6 +22,9 @@ import (
 	"sync"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/bbq"
+	"github.com/onflow/cadence/bbq/compiler"
+	"github.com/onflow/cadence/bbq/vm"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/sema"
@@ -33,6 +36,7 @@ type scriptExecutorPreparation struct {
 	codesAndPrograms       CodesAndPrograms
 	functionEntryPointType *sema.FunctionType
 	program                *interpreter.Program
+	compiledProgram        *bbq.Program
 	storage                *Storage
 	interpret              interpretFunc
 	preprocessOnce         sync.Once
@@ -113,12 +117,11 @@ func (executor *scriptExecutor) preprocess() (err error) {
 	)
 	executor.storage = storage
 
+	config := executor.runtime.Config()
+
 	environment := context.Environment
 	if environment == nil {
-		if context.UseVM {
-			return errors.NewUnexpectedError("cannot execute script with the VM")
-		}
-		environment = NewScriptInterpreterEnvironment(executor.runtime.Config())
+		environment = NewScriptInterpreterEnvironment(config)
 	}
 
 	switch environment.(type) {
@@ -174,11 +177,40 @@ func (executor *scriptExecutor) preprocess() (err error) {
 		return newError(err, location, codesAndPrograms)
 	}
 
+	if executor.useVM(config) {
+		// Functions which cannot be compiled are executed by the interpreter
+		var unsupportedErrors []*compiler.UnsupportedError
+		executor.compiledProgram, unsupportedErrors = compiler.Compile(program)
+
+		fallbackHandler := config.VMFallbackHandler
+		if fallbackHandler != nil {
+			for _, unsupportedError := range unsupportedErrors {
+				fallbackHandler(location, unsupportedError)
+			}
+		}
+	}
+
 	executor.interpret = executor.scriptExecutionFunction()
 
 	return nil
 }
 
+// useVM returns true if the script should be executed using the VM.
+// The VM does not support debugging, coverage reporting, and tracing,
+// so the interpreter is used if any of them is enabled.
+func (executor *scriptExecutor) useVM(config Config) bool {
+	context := executor.context
+
+	if !context.UseVM && !config.VMEnabled {
+		return false
+	}
+
+	return config.Debugger == nil &&
+		config.CoverageReport == nil &&
+		context.CoverageReport == nil &&
+		!config.TracingEnabled
+}
+
 func (executor *scriptExecutor) execute() (val cadence.Value, err error) {
 	err = executor.Preprocess()
 	if err != nil {
@@ -270,6 +302,11 @@ func (executor *scriptExecutor) scriptExecutionFunction() interpretFunc {
 			return nil, err
 		}
 
+		if executor.compiledProgram != nil {
+			return vm.NewVM(executor.compiledProgram, inter).
+				Invoke(sema.FunctionEntryPointName, values...)
+		}
+
 		return inter.Invoke(sema.FunctionEntryPointName, values...)
 	}
 }
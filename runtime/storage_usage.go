@@ -0,0 +1,394 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/interpreter"
+)
+
+// StorageUsage is the encoded byte size of the storage of an account,
+// attributed to storage paths (storage map keys) and composite types.
+type StorageUsage struct {
+	Address common.Address
+	// Size is the encoded byte size of all slabs of the account storage,
+	// including the account storage map and the domain storage maps
+	Size uint64
+	// Paths is the usage of each storage map key, sorted by descending size
+	Paths []PathStorageUsage
+	// Types is the usage of each composite type, sorted by descending size
+	Types []TypeStorageUsage
+}
+
+// PathStorageUsage is the encoded byte size of the value stored under a storage map key,
+// including the size of the key and of all child slabs.
+type PathStorageUsage struct {
+	Domain common.StorageDomain
+	Key    string
+	Size   uint64
+	// SlabCount is the number of (non-inlined) slabs of the value
+	SlabCount int
+}
+
+// TypeStorageUsage is the total encoded byte size of all stored values of a composite type,
+// including the size of all their child slabs.
+//
+// The size is inclusive: the size of a nested composite value
+// is attributed both to its own type and to the types of its containers.
+type TypeStorageUsage struct {
+	TypeID common.TypeID
+	Size   uint64
+	Count  int
+}
+
+// AccountStorageUsage walks the account storage map, the domain storage maps,
+// and the slabs of all stored values of the given account,
+// and attributes their encoded byte sizes to storage paths and composite types.
+//
+// Accounts in storage format v1, which have no account storage map,
+// are walked starting at the domain storage maps referenced by the domain registers.
+//
+// Returns nil if the account has no storage.
+func (s *Storage) AccountStorageUsage(address common.Address) (*StorageUsage, error) {
+	walker := &storageUsageWalker{
+		storage: s.PersistentSlabStorage,
+		types:   map[common.TypeID]*TypeStorageUsage{},
+	}
+
+	usage := &StorageUsage{
+		Address: address,
+	}
+
+	recordPath := func(domain common.StorageDomain, key string, keyStorable atree.Storable, value atree.Storable) error {
+		slabCount := walker.slabCount
+		size, err := walker.storableSize(value)
+		if err != nil {
+			return err
+		}
+
+		usage.Paths = append(usage.Paths, PathStorageUsage{
+			Domain:    domain,
+			Key:       key,
+			Size:      uint64(keyStorable.ByteSize()) + size,
+			SlabCount: walker.slabCount - slabCount,
+		})
+
+		return nil
+	}
+
+	slabIndex, exists, err := readAccountStorageSlabIndexFromRegister(s.Ledger, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		accountStorageMapSlabID := atree.NewSlabID(atree.Address(address), slabIndex)
+
+		err = walker.walkStoragePaths(accountStorageMapSlabID, recordPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Storage format v1: each domain storage map is referenced by its own register
+
+		for _, domain := range common.AllStorageDomains {
+			slabIndex, exists, err := readSlabIndexFromRegister(
+				s.Ledger,
+				address,
+				[]byte(domain.Identifier()),
+			)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+
+			domainStorageMapSlabID := atree.NewSlabID(atree.Address(address), slabIndex)
+
+			err = walker.walkDomainStoragePaths(
+				domain,
+				atree.SlabIDStorable(domainStorageMapSlabID),
+				recordPath,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if walker.mapSize == 0 {
+			return nil, nil
+		}
+	}
+
+	usage.Size = walker.mapSize + walker.valueSize
+
+	sort.SliceStable(usage.Paths, func(i, j int) bool {
+		a := usage.Paths[i]
+		b := usage.Paths[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		return a.Key < b.Key
+	})
+
+	usage.Types = make([]TypeStorageUsage, 0, len(walker.types))
+	for _, typeUsage := range walker.types { //nolint:maprange
+		usage.Types = append(usage.Types, *typeUsage)
+	}
+
+	sort.Slice(usage.Types, func(i, j int) bool {
+		a := usage.Types[i]
+		b := usage.Types[j]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.TypeID < b.TypeID
+	})
+
+	return usage, nil
+}
+
+type storageUsageWalker struct {
+	storage atree.SlabStorage
+	types   map[common.TypeID]*TypeStorageUsage
+	// mapSize is the size of the slabs of the account storage map and the domain storage maps
+	mapSize uint64
+	// valueSize is the size of the non-inlined slabs of stored values
+	valueSize uint64
+	slabCount int
+}
+
+func (w *storageUsageWalker) retrieve(slabID atree.SlabID) (atree.Slab, error) {
+	slab, found, err := w.storage.Retrieve(slabID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.NewUnexpectedError("slab %s not found", slabID)
+	}
+	return slab, nil
+}
+
+// walkStoragePaths calls the given function for the key and value storable of each entry
+// of the domain storage maps of the account storage map with the given slab ID.
+func (w *storageUsageWalker) walkStoragePaths(
+	accountStorageMapSlabID atree.SlabID,
+	f func(domain common.StorageDomain, key string, keyStorable atree.Storable, value atree.Storable) error,
+) error {
+	return w.walkMapEntries(
+		atree.SlabIDStorable(accountStorageMapSlabID),
+		func(key atree.Storable, value atree.Storable) error {
+			domainKey, ok := key.(interpreter.Uint64AtreeValue)
+			if !ok {
+				return errors.NewUnexpectedError("invalid account storage map key: %T", key)
+			}
+
+			domain, err := common.StorageDomainFromUint64(uint64(domainKey))
+			if err != nil {
+				return err
+			}
+
+			return w.walkDomainStoragePaths(domain, value, f)
+		},
+	)
+}
+
+// walkDomainStoragePaths calls the given function for the key and value storable of each entry
+// of the given domain storage map.
+func (w *storageUsageWalker) walkDomainStoragePaths(
+	domain common.StorageDomain,
+	domainStorageMap atree.Storable,
+	f func(domain common.StorageDomain, key string, keyStorable atree.Storable, value atree.Storable) error,
+) error {
+	return w.walkMapEntries(
+		domainStorageMap,
+		func(key atree.Storable, value atree.Storable) error {
+			var keyString string
+			switch key := key.(type) {
+			case interpreter.StringAtreeValue:
+				keyString = string(key)
+			case interpreter.Uint64AtreeValue:
+				keyString = strconv.FormatUint(uint64(key), 10)
+			default:
+				return errors.NewUnexpectedError("invalid domain storage map key: %T", key)
+			}
+
+			return f(domain, keyString, key, value)
+		},
+	)
+}
+
+// walkMapEntries calls the given function for each key and value storable
+// of the map with the given (inlined or non-inlined) storable.
+func (w *storageUsageWalker) walkMapEntries(
+	storable atree.Storable,
+	f func(key atree.Storable, value atree.Storable) error,
+) error {
+	if slabIDStorable, ok := storable.(atree.SlabIDStorable); ok {
+		slab, err := w.retrieve(atree.SlabID(slabIDStorable))
+		if err != nil {
+			return err
+		}
+		w.mapSize += uint64(slab.ByteSize())
+		storable = slab
+	}
+
+	children := storable.ChildStorables()
+
+	for i := 0; i < len(children); i++ {
+		child := children[i]
+
+		// The children of metadata slabs are data slabs,
+		// and external collision groups are slabs containing further entries
+		if _, ok := child.(atree.SlabIDStorable); ok {
+			err := w.walkMapEntries(child, f)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if i+1 >= len(children) {
+			return errors.NewUnexpectedError("missing value for map key")
+		}
+
+		err := f(child, children[i+1])
+		if err != nil {
+			return err
+		}
+		i++
+	}
+
+	return nil
+}
+
+// storableSize returns the encoded byte size of the given storable,
+// including the sizes of all child slabs,
+// and attributes the sizes of composite values to their types.
+func (w *storageUsageWalker) storableSize(storable atree.Storable) (uint64, error) {
+	var size uint64
+
+	if slabIDStorable, ok := storable.(atree.SlabIDStorable); ok {
+		slab, err := w.retrieve(atree.SlabID(slabIDStorable))
+		if err != nil {
+			return 0, err
+		}
+
+		w.slabCount++
+		slabSize := uint64(slab.ByteSize())
+		w.valueSize += slabSize
+
+		// The reference itself is part of the parent's size
+		size = slabSize
+		storable = slab
+	} else {
+		size = uint64(storable.ByteSize())
+	}
+
+	// The sizes of inlined children are already included,
+	// but the sizes of referenced slabs are not
+
+	childSizes, err := w.referencedSlabsSize(storable.ChildStorables())
+	if err != nil {
+		return 0, err
+	}
+	size += childSizes
+
+	w.recordType(storable, size)
+
+	return size, nil
+}
+
+func (w *storageUsageWalker) referencedSlabsSize(children []atree.Storable) (uint64, error) {
+	var size uint64
+
+	for _, child := range children {
+		if _, ok := child.(atree.SlabIDStorable); ok {
+			childSize, err := w.storableSize(child)
+			if err != nil {
+				return 0, err
+			}
+			size += childSize
+			continue
+		}
+
+		// Inlined containers are recorded,
+		// and may reference further slabs
+		switch child.(type) {
+		case *atree.MapDataSlab, *atree.ArrayDataSlab:
+			childSize, err := w.storableSize(child)
+			if err != nil {
+				return 0, err
+			}
+			// Only add the sizes of the referenced slabs,
+			// the inlined size is already included
+			size += childSize - uint64(child.ByteSize())
+
+		default:
+			childSize, err := w.referencedSlabsSize(child.ChildStorables())
+			if err != nil {
+				return 0, err
+			}
+			size += childSize
+		}
+	}
+
+	return size, nil
+}
+
+// recordType attributes the given size to the composite type of the given map slab, if any.
+func (w *storageUsageWalker) recordType(storable atree.Storable, size uint64) {
+	var extraData *atree.MapExtraData
+	switch storable := storable.(type) {
+	case *atree.MapDataSlab:
+		extraData = storable.ExtraData()
+	case *atree.MapMetaDataSlab:
+		extraData = storable.ExtraData()
+	}
+	if extraData == nil {
+		return
+	}
+
+	typeInfo, ok := extraData.TypeInfo.(interpreter.CompositeTypeInfo)
+	if !ok {
+		return
+	}
+
+	typeID := common.NewTypeIDFromQualifiedName(nil, typeInfo.Location, typeInfo.QualifiedIdentifier)
+
+	typeUsage, ok := w.types[typeID]
+	if !ok {
+		typeUsage = &TypeStorageUsage{
+			TypeID: typeID,
+		}
+		w.types[typeID] = typeUsage
+	}
+	typeUsage.Size += size
+	typeUsage.Count++
+}
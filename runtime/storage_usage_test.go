@@ -0,0 +1,255 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	. "github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+func TestRuntimeAccountStorageUsage(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := NewTestInterpreterRuntime()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	deployTx := DeploymentTransaction("Test", []byte(`
+      access(all) contract Test {
+
+          access(all) resource NFT {
+              access(all) let name: String
+
+              init(name: String) {
+                  self.name = name
+              }
+          }
+
+          access(all) resource Collection {
+              access(all) let ownedNFTs: @{UInt64: NFT}
+
+              init() {
+                  self.ownedNFTs <- {}
+              }
+
+              access(all) fun deposit(_ nft: @NFT) {
+                  self.ownedNFTs[nft.uuid] <-! nft
+              }
+          }
+
+          access(all) fun createCollection(): @Collection {
+              return <-create Collection()
+          }
+
+          access(all) fun mint(name: String): @NFT {
+              return <-create NFT(name: name)
+          }
+      }
+    `))
+
+	accountCodes := map[common.Location][]byte{}
+
+	ledger := NewTestLedger(nil, nil)
+
+	newRuntimeInterface := func() Interface {
+		return &TestRuntimeInterface{
+			Storage: ledger,
+			OnGetSigningAccounts: func() ([]Address, error) {
+				return []Address{address}, nil
+			},
+			OnResolveLocation: NewSingleIdentifierLocationResolver(t),
+			OnUpdateAccountContractCode: func(location common.AddressLocation, code []byte) error {
+				accountCodes[location] = code
+				return nil
+			},
+			OnGetAccountContractCode: func(location common.AddressLocation) (code []byte, err error) {
+				code = accountCodes[location]
+				return code, nil
+			},
+			OnEmitEvent: func(_ cadence.Event) error {
+				return nil
+			},
+		}
+	}
+
+	nextTransactionLocation := NewTransactionLocationGenerator()
+
+	for _, source := range []string{
+		string(deployTx),
+		`
+          import Test from 0x1
+
+          transaction {
+              prepare(signer: auth(Storage) &Account) {
+                  let collection <- Test.createCollection()
+                  var i = 0
+                  while i < 50 {
+                      collection.deposit(<-Test.mint(name: "NFT ".concat(i.toString())))
+                      i = i + 1
+                  }
+                  signer.storage.save(<-collection, to: /storage/collection)
+                  signer.storage.save("Hello, World!", to: /storage/greeting)
+                  signer.storage.save(<-Test.mint(name: "single"), to: /storage/nft)
+              }
+          }
+        `,
+	} {
+		err := runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(source),
+			},
+			Context{
+				Interface: newRuntimeInterface(),
+				Location:  nextTransactionLocation(),
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	usage, err := storage.AccountStorageUsage(address)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	assert.Equal(t, address, usage.Address)
+
+	// Paths are sorted by descending size
+
+	var pathKeys []string
+	var pathsSize uint64
+	for _, pathUsage := range usage.Paths {
+		pathKeys = append(pathKeys, pathUsage.Domain.Identifier()+"/"+pathUsage.Key)
+		pathsSize += pathUsage.Size
+	}
+
+	assert.Equal(t,
+		[]string{
+			"storage/collection",
+			"storage/nft",
+			"contract/Test",
+			"storage/greeting",
+		},
+		pathKeys,
+	)
+
+	collectionUsage := usage.Paths[0]
+	assert.Greater(t, collectionUsage.SlabCount, 1)
+
+	// The total size includes the storage maps
+	assert.Greater(t, usage.Size, pathsSize)
+
+	// Types are sorted by descending size
+
+	var typeIDs []common.TypeID
+	typeUsages := map[common.TypeID]TypeStorageUsage{}
+	for _, typeUsage := range usage.Types {
+		typeIDs = append(typeIDs, typeUsage.TypeID)
+		typeUsages[typeUsage.TypeID] = typeUsage
+	}
+
+	const (
+		collectionTypeID = common.TypeID("A.0000000000000001.Test.Collection")
+		nftTypeID        = common.TypeID("A.0000000000000001.Test.NFT")
+		contractTypeID   = common.TypeID("A.0000000000000001.Test")
+	)
+
+	assert.Equal(t,
+		[]common.TypeID{
+			collectionTypeID,
+			nftTypeID,
+			contractTypeID,
+		},
+		typeIDs,
+	)
+
+	assert.Equal(t, 1, typeUsages[collectionTypeID].Count)
+	assert.Equal(t, collectionUsage.Size-uint64(len("collection"))-1, typeUsages[collectionTypeID].Size)
+
+	// Nested NFTs are attributed to both the collection and the NFT type
+	assert.Equal(t, 51, typeUsages[nftTypeID].Count)
+	assert.Less(t, typeUsages[nftTypeID].Size, typeUsages[collectionTypeID].Size+usage.Paths[1].Size)
+
+	// Accounts without storage have no usage
+
+	usage, err = storage.AccountStorageUsage(common.MustBytesToAddress([]byte{0x2}))
+	require.NoError(t, err)
+	assert.Nil(t, usage)
+}
+
+func TestRuntimeAccountStorageUsageV1(t *testing.T) {
+
+	t.Parallel()
+
+	// Accounts in storage format v1 have no account storage map,
+	// but reference their domain storage maps from domain registers
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		TestLocation,
+		&interpreter.Config{
+			Storage: storage,
+		},
+	)
+	require.NoError(t, err)
+
+	domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+	domainStorageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("greeting"),
+		interpreter.NewUnmeteredStringValue("Hello, World!"),
+	)
+
+	err = storage.PersistentSlabStorage.FastCommit(1)
+	require.NoError(t, err)
+
+	slabIndex := domainStorageMap.SlabID().Index()
+	err = ledger.SetValue(
+		address[:],
+		[]byte(common.StorageDomainPathStorage.Identifier()),
+		slabIndex[:],
+	)
+	require.NoError(t, err)
+
+	usage, err := NewStorage(ledger, nil, StorageConfig{}).AccountStorageUsage(address)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	require.Len(t, usage.Paths, 1)
+	assert.Equal(t, common.StorageDomainPathStorage, usage.Paths[0].Domain)
+	assert.Equal(t, "greeting", usage.Paths[0].Key)
+	assert.Greater(t, usage.Size, usage.Paths[0].Size)
+}
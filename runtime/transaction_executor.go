@@ -34,6 +34,7 @@ type transactionExecutorPreparation struct {
 	transactionType  *sema.TransactionType
 	storage          *Storage
 	program          *interpreter.Program
+	dryRunInterface  *dryRunInterface
 	preprocessOnce   sync.Once
 }
 
@@ -104,6 +105,14 @@ func (executor *transactionExecutor) preprocess() (err error) {
 
 	runtimeInterface := context.Interface
 
+	if context.DryRunJournal != nil {
+		executor.dryRunInterface = newDryRunInterface(
+			runtimeInterface,
+			context.DryRunJournal,
+		)
+		runtimeInterface = executor.dryRunInterface
+	}
+
 	storage := NewStorage(
 		runtimeInterface,
 		runtimeInterface,
@@ -237,6 +246,21 @@ func (executor *transactionExecutor) execute() (err error) {
 func (executor *transactionExecutor) executeWithInterpreter(
 	environment *interpreterEnvironment,
 ) error {
+	dryRunInterface := executor.dryRunInterface
+
+	// Resource moves are recorded in dry runs,
+	// even if the resource owner change handler is not enabled
+	if dryRunInterface != nil {
+		if environment.InterpreterConfig.OnResourceOwnerChange == nil {
+			environment.InterpreterConfig.OnResourceOwnerChange = newResourceOwnerChangedHandler(&environment.Interface)
+			defer func() {
+				environment.InterpreterConfig.OnResourceOwnerChange = nil
+			}()
+		} else {
+			dryRunInterface.forwardResourceOwnerChanges = true
+		}
+	}
+
 	_, inter, err := environment.interpret(
 		executor.context.Location,
 		executor.program,
@@ -246,12 +270,17 @@ func (executor *transactionExecutor) executeWithInterpreter(
 		return err
 	}
 
-	// Write back all stored values, which were actually just cached, back into storage
+	// Write back all stored values, which were actually just cached, back into storage.
+	// In a dry run, the writes are buffered by the dry-run interface
 	err = environment.commitStorage(inter)
 	if err != nil {
 		return err
 	}
 
+	if dryRunInterface != nil {
+		return dryRunInterface.finish()
+	}
+
 	return nil
 }
 
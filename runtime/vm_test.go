@@ -0,0 +1,242 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/bbq/compiler"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/encoding/json"
+	. "github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+func TestRuntimeScriptVM(t *testing.T) {
+
+	t.Parallel()
+
+	const script = `
+      access(all) fun fib(_ n: Int): Int {
+          var a = 0
+          var b = 1
+          var i = 0
+          while i < n {
+              let next = a + b
+              a = b
+              b = next
+              i = i + 1
+          }
+          return a
+      }
+
+      access(all) fun sum(_ xs: [Int]): Int {
+          var result = 0
+          for x in xs {
+              result = result + x
+          }
+          return result
+      }
+
+      access(all) fun main(n: Int): [Int] {
+          log(n)
+          return [fib(n), sum([1, 2, 3])]
+      }
+    `
+
+	type execution struct {
+		result      cadence.Value
+		logs        []string
+		computation map[common.ComputationKind]uint64
+	}
+
+	execute := func(t *testing.T, config Config, useVM bool) execution {
+		runtime := NewTestInterpreterRuntimeWithConfig(config)
+
+		var logs []string
+		computation := map[common.ComputationKind]uint64{}
+
+		runtimeInterface := &TestRuntimeInterface{
+			Storage: NewTestLedger(nil, nil),
+			OnProgramLog: func(message string) {
+				logs = append(logs, message)
+			},
+			OnMeterComputation: func(usage common.ComputationUsage) error {
+				computation[usage.Kind] += usage.Intensity
+				return nil
+			},
+			OnDecodeArgument: func(b []byte, t cadence.Type) (cadence.Value, error) {
+				return json.Decode(nil, b)
+			},
+		}
+
+		result, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(script),
+				Arguments: encodeArgs([]cadence.Value{
+					cadence.NewInt(10),
+				}),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+				UseVM:     useVM,
+			},
+		)
+		require.NoError(t, err)
+
+		return execution{
+			result:      result,
+			logs:        logs,
+			computation: computation,
+		}
+	}
+
+	expected := execute(t, Config{}, false)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(55),
+			cadence.NewInt(6),
+		}).WithType(cadence.NewVariableSizedArrayType(cadence.IntType)),
+		expected.result,
+	)
+	assert.Equal(t, []string{"10"}, expected.logs)
+
+	// The function `sum` cannot be compiled yet, so it is executed by the interpreter
+
+	assertFallbacks := func(t *testing.T, fallbacks []string) {
+		assert.Equal(t,
+			[]string{
+				"s.0000000000000000000000000000000000000000000000000000000000000000: " +
+					"cannot compile function `sum`: for statement is not supported yet",
+			},
+			fallbacks,
+		)
+	}
+
+	newFallbackHandler := func(fallbacks *[]string) func(common.Location, *compiler.UnsupportedError) {
+		return func(location common.Location, err *compiler.UnsupportedError) {
+			*fallbacks = append(*fallbacks, fmt.Sprintf("%s: %s", location.ID(), err))
+		}
+	}
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		var fallbacks []string
+		config := Config{
+			VMFallbackHandler: newFallbackHandler(&fallbacks),
+		}
+
+		assert.Equal(t, expected, execute(t, config, true))
+		assertFallbacks(t, fallbacks)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+
+		var fallbacks []string
+		config := Config{
+			VMEnabled:         true,
+			VMFallbackHandler: newFallbackHandler(&fallbacks),
+		}
+
+		assert.Equal(t, expected, execute(t, config, false))
+		assertFallbacks(t, fallbacks)
+	})
+
+	t.Run("interpreter", func(t *testing.T) {
+		t.Parallel()
+
+		var fallbacks []string
+		config := Config{
+			VMFallbackHandler: newFallbackHandler(&fallbacks),
+		}
+
+		assert.Equal(t, expected, execute(t, config, false))
+		assert.Empty(t, fallbacks)
+	})
+}
+
+func TestRuntimeTransactionVM(t *testing.T) {
+
+	t.Parallel()
+
+	// The VM only applies to scripts.
+	// Even if it is enabled in the configuration,
+	// transactions are executed by the interpreter,
+	// and no function is reported as not compilable
+
+	const transaction = `
+      access(all) fun sum(_ xs: [Int]): Int {
+          var result = 0
+          for x in xs {
+              result = result + x
+          }
+          return result
+      }
+
+      transaction {
+          prepare(signer: &Account) {
+              log(sum([1, 2, 3]))
+          }
+      }
+    `
+
+	var fallbacks []common.Location
+
+	runtime := NewTestInterpreterRuntimeWithConfig(Config{
+		VMEnabled: true,
+		VMFallbackHandler: func(location common.Location, _ *compiler.UnsupportedError) {
+			fallbacks = append(fallbacks, location)
+		},
+	})
+
+	var logs []string
+
+	runtimeInterface := &TestRuntimeInterface{
+		Storage: NewTestLedger(nil, nil),
+		OnGetSigningAccounts: func() ([]Address, error) {
+			return []Address{common.MustBytesToAddress([]byte{0x1})}, nil
+		},
+		OnProgramLog: func(message string) {
+			logs = append(logs, message)
+		},
+	}
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(transaction),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  common.TransactionLocation{},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"6"}, logs)
+	assert.Empty(t, fallbacks)
+}
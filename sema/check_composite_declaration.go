@@ -1982,6 +1982,12 @@ func (checker *Checker) enumMembersAndOrigins(
 ) {
 	for _, declaration := range allMembers.Declarations() {
 
+		// Invalid declarations were already reported by the parser
+
+		if _, ok := declaration.(*ast.InvalidDeclaration); ok {
+			continue
+		}
+
 		// Enum declarations may only contain enum cases
 
 		enumCase, ok := declaration.(*ast.EnumCaseDeclaration)
@@ -20,6 +20,7 @@ package sema
 
 import (
 	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
 )
 
@@ -66,7 +67,27 @@ func (checker *Checker) checkCondition(condition ast.Condition) {
 	}
 }
 
+// postConditionsRewriter rewrites post-conditions,
+// extracting the expressions of `before` invocations into variable declarations.
+//
+// The rewrite is deterministic, so it can be repeated for an already checked program,
+// e.g. when decoding an elaboration (see DecodeElaboration)
+type postConditionsRewriter struct {
+	memoryGauge     common.MemoryGauge
+	beforeExtractor *BeforeExtractor
+	report          func(error)
+}
+
 func (checker *Checker) rewritePostConditions(postConditions []ast.Condition) PostConditionsRewrite {
+	rewriter := postConditionsRewriter{
+		memoryGauge:     checker.memoryGauge,
+		beforeExtractor: checker.beforeExtractor(),
+		report:          checker.report,
+	}
+	return rewriter.rewritePostConditions(postConditions)
+}
+
+func (rewriter postConditionsRewriter) rewritePostConditions(postConditions []ast.Condition) PostConditionsRewrite {
 
 	var beforeStatements []ast.Statement
 
@@ -79,7 +100,7 @@ func (checker *Checker) rewritePostConditions(postConditions []ast.Condition) Po
 
 		for i, postCondition := range postConditions {
 
-			newPostCondition, extractedExpressions := checker.rewritePostCondition(postCondition)
+			newPostCondition, extractedExpressions := rewriter.rewritePostCondition(postCondition)
 			rewrittenPostConditions[i] = newPostCondition
 			allExtractedExpressions = append(
 				allExtractedExpressions,
@@ -94,11 +115,11 @@ func (checker *Checker) rewritePostConditions(postConditions []ast.Condition) Po
 
 		// NOTE: no need to check the before statements or update elaboration here:
 		// The before statements are visited/checked later
-		variableDeclaration := ast.NewEmptyVariableDeclaration(checker.memoryGauge)
+		variableDeclaration := ast.NewEmptyVariableDeclaration(rewriter.memoryGauge)
 		variableDeclaration.StartPos = startPos
 		variableDeclaration.Identifier = extractedExpression.Identifier
 		variableDeclaration.Transfer = ast.NewTransfer(
-			checker.memoryGauge,
+			rewriter.memoryGauge,
 			ast.TransferOperationCopy,
 			startPos,
 		)
@@ -116,7 +137,7 @@ func (checker *Checker) rewritePostConditions(postConditions []ast.Condition) Po
 	}
 }
 
-func (checker *Checker) rewritePostCondition(
+func (rewriter postConditionsRewriter) rewritePostCondition(
 	postCondition ast.Condition,
 ) (
 	newPostCondition ast.Condition,
@@ -124,17 +145,17 @@ func (checker *Checker) rewritePostCondition(
 ) {
 	switch postCondition := postCondition.(type) {
 	case *ast.TestCondition:
-		return checker.rewriteTestPostCondition(postCondition)
+		return rewriter.rewriteTestPostCondition(postCondition)
 
 	case *ast.EmitCondition:
-		return checker.rewriteEmitPostCondition(postCondition)
+		return rewriter.rewriteEmitPostCondition(postCondition)
 
 	default:
 		panic(errors.NewUnreachableError())
 	}
 }
 
-func (checker *Checker) rewriteTestPostCondition(
+func (rewriter postConditionsRewriter) rewriteTestPostCondition(
 	postTestCondition *ast.TestCondition,
 ) (
 	newPostCondition ast.Condition,
@@ -143,7 +164,7 @@ func (checker *Checker) rewriteTestPostCondition(
 	// copy condition and set expression to rewritten one
 	newPostTestCondition := *postTestCondition
 
-	beforeExtractor := checker.beforeExtractor()
+	beforeExtractor := rewriter.beforeExtractor
 
 	testExtraction := beforeExtractor.ExtractBefore(postTestCondition.Test)
 
@@ -167,7 +188,7 @@ func (checker *Checker) rewriteTestPostCondition(
 	return
 }
 
-func (checker *Checker) rewriteEmitPostCondition(
+func (rewriter postConditionsRewriter) rewriteEmitPostCondition(
 	postEmitCondition *ast.EmitCondition,
 ) (
 	newPostCondition ast.Condition,
@@ -176,7 +197,7 @@ func (checker *Checker) rewriteEmitPostCondition(
 	// copy condition and set argument expressions to rewritten ones
 	newPostEmitCondition := *postEmitCondition
 
-	beforeExtractor := checker.beforeExtractor()
+	beforeExtractor := rewriter.beforeExtractor
 
 	invocationExtraction := beforeExtractor.ExtractBefore(postEmitCondition.InvocationExpression)
 
@@ -185,9 +206,9 @@ func (checker *Checker) rewriteEmitPostCondition(
 	if rewrittenInvocationExpression, ok := invocationExtraction.RewrittenExpression.(*ast.InvocationExpression); ok {
 		newPostEmitCondition.InvocationExpression = rewrittenInvocationExpression
 	} else {
-		checker.report(&InvalidEmitConditionError{
+		rewriter.report(&InvalidEmitConditionError{
 			Range: ast.NewRangeFromPositioned(
-				checker.memoryGauge,
+				rewriter.memoryGauge,
 				postEmitCondition.InvocationExpression,
 			),
 		})
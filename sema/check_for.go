@@ -88,6 +88,7 @@ func (checker *Checker) VisitForStatement(statement *ast.ForStatement) (_ struct
 	}
 
 	checker.Elaboration.SetForStatementType(statement, ForStatementTypes{
+		IterableType:      valueType,
 		IndexVariableType: indexType,
 		ValueVariableType: loopVariableType,
 	})
@@ -125,6 +125,18 @@ func (checker *Checker) visitFunctionDeclaration(
 
 	checker.Elaboration.SetFunctionDeclarationFunctionType(declaration, functionType)
 
+	// The type parameters of a generic function may be referred to in its body
+
+	if functionBlock != nil && len(functionType.TypeParameters) > 0 {
+		checker.typeActivations.Enter()
+		defer checker.typeActivations.Leave(declaration.EndPosition)
+
+		checker.declareFunctionBodyTypeParameters(
+			declaration.TypeParameterList,
+			functionType.TypeParameters,
+		)
+	}
+
 	checker.checkFunction(
 		declaration.ParameterList,
 		declaration.ReturnTypeAnnotation,
@@ -137,6 +149,27 @@ func (checker *Checker) visitFunctionDeclaration(
 	)
 }
 
+// declareFunctionBodyTypeParameters declares the types of the given type parameters
+// in the current type activation.
+//
+// The type parameters were already declared when the function type was converted,
+// so potential redeclaration errors were already reported.
+func (checker *Checker) declareFunctionBodyTypeParameters(
+	typeParameterList *ast.TypeParameterList,
+	typeParameters []*TypeParameter,
+) {
+	for i, typeParameter := range typeParameterList.TypeParameters {
+		_, _ = checker.typeActivations.declareType(typeDeclaration{
+			identifier: typeParameter.Identifier,
+			ty: &GenericType{
+				TypeParameter: typeParameters[i],
+			},
+			declarationKind:          common.DeclarationKindTypeParameter,
+			allowOuterScopeShadowing: true,
+		})
+	}
+}
+
 func (checker *Checker) declareFunctionDeclaration(
 	declaration *ast.FunctionDeclaration,
 	functionType *FunctionType,
@@ -0,0 +1,40 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/ast"
+)
+
+// Invalid declarations, statements, and expressions are placeholders
+// produced by the parser when it recovers from a syntax error.
+// The syntax error is already reported by the parser,
+// so no further errors are reported for them.
+
+func (checker *Checker) VisitInvalidDeclaration(_ *ast.InvalidDeclaration) (_ struct{}) {
+	return
+}
+
+func (checker *Checker) VisitInvalidStatement(_ *ast.InvalidStatement) (_ struct{}) {
+	return
+}
+
+func (checker *Checker) VisitInvalidExpression(_ *ast.InvalidExpression) Type {
+	return InvalidType
+}
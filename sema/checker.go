@@ -480,6 +480,12 @@ func (checker *Checker) checkTopLevelDeclarationValidity(
 	declaration ast.Declaration,
 	validTopLevelDeclarations common.DeclarationKindSet,
 ) {
+	// Invalid declarations were already reported by the parser
+
+	if _, ok := declaration.(*ast.InvalidDeclaration); ok {
+		return
+	}
+
 	declarationKind := declaration.DeclarationKind()
 
 	if validTopLevelDeclarations.Has(declarationKind) {
@@ -1291,16 +1297,6 @@ func (checker *Checker) functionType(
 	var convertedTypeParameters []*TypeParameter
 	if typeParameterList != nil {
 
-		// Only native functions may have type parameters at the moment
-		if !isNative && !typeParameterList.IsEmpty() {
-			checker.report(&InvalidTypeParameterizedNonNativeFunctionError{
-				Range: ast.NewRangeFromPositioned(
-					checker.memoryGauge,
-					typeParameterList,
-				),
-			})
-		}
-
 		checker.typeActivations.Enter()
 		defer checker.typeActivations.Leave(func(gauge common.MemoryGauge) ast.Position {
 			if returnTypeAnnotation != nil {
@@ -1315,6 +1311,19 @@ func (checker *Checker) functionType(
 
 		convertedTypeParameters = checker.typeParameters(typeParameterList)
 
+		if !isNative && !typeParameterList.IsEmpty() {
+			if checker.Config.TypeParametersEnabled {
+				setNonNativeTypeParameters(convertedTypeParameters)
+			} else {
+				checker.report(&InvalidTypeParameterizedNonNativeFunctionError{
+					Range: ast.NewRangeFromPositioned(
+						checker.memoryGauge,
+						typeParameterList,
+					),
+				})
+			}
+		}
+
 		for typeParameterIndex, typeParameter := range typeParameterList.TypeParameters {
 			convertedTypeParameter := convertedTypeParameters[typeParameterIndex]
 
@@ -1381,6 +1390,21 @@ func (checker *Checker) typeParameters(typeParameterList *ast.TypeParameterList)
 	return typeParameters
 }
 
+// setNonNativeTypeParameters marks the type parameters of a non-native function
+// as non-native, and sets their type bounds.
+//
+// Values of a type parameter's type must be handled uniformly in the function body,
+// so the type bound must determine whether values are resources or not:
+// Type parameters without a type bound are implicitly bound by `AnyStruct`.
+func setNonNativeTypeParameters(typeParameters []*TypeParameter) {
+	for _, typeParameter := range typeParameters {
+		typeParameter.NonNative = true
+		if typeParameter.TypeBound == nil {
+			typeParameter.TypeBound = AnyStructType
+		}
+	}
+}
+
 func (checker *Checker) parameters(parameterList *ast.ParameterList) []Parameter {
 
 	// TODO: required for initializer conformance checking at the moment, optimize/refactor
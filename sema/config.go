@@ -53,4 +53,6 @@ type Config struct {
 	AllowNativeDeclarations bool
 	// AllowStaticDeclarations determines if declarations may be static
 	AllowStaticDeclarations bool
+	// TypeParametersEnabled determines if non-native functions may have type parameters
+	TypeParametersEnabled bool
 }
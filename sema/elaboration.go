@@ -112,6 +112,7 @@ type ExpressionTypes struct {
 }
 
 type ForStatementTypes struct {
+	IterableType      Type
 	IndexVariableType Type
 	ValueVariableType Type
 }
@@ -0,0 +1,1136 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/common/orderedmap"
+)
+
+type elaborationDecoderMemberKey struct {
+	containerType Type
+	identifier    string
+}
+
+type elaborationDecoder struct {
+	config         *ElaborationEncodingConfig
+	decoder        *cbor.StreamDecoder
+	nodes          *elaborationNodes
+	elaboration    *Elaboration
+	types          []Type
+	typeParameters []*TypeParameter
+	// members are the resolved members of types.
+	// Some members are created when they are resolved,
+	// so they are cached to resolve each member only once
+	members map[elaborationDecoderMemberKey]*Member
+}
+
+// DecodeElaboration decodes an elaboration of the given program,
+// which was encoded using EncodeElaboration.
+//
+// The program must be the result of parsing the same code as the encoded program,
+// and the configuration must be the same as the configuration used for encoding.
+func DecodeElaboration(
+	data []byte,
+	program *ast.Program,
+	config *ElaborationEncodingConfig,
+) (
+	elaboration *Elaboration,
+	err error,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			decodingErr, ok := r.(ElaborationDecodingError)
+			if !ok {
+				panic(r)
+			}
+			elaboration = nil
+			err = decodingErr
+		}
+	}()
+
+	decoder := &elaborationDecoder{
+		config:      config,
+		decoder:     elaborationCBORDecMode.NewByteStreamDecoder(data),
+		nodes:       newElaborationNodes(program),
+		elaboration: NewElaboration(config.MemoryGauge),
+		members:     map[elaborationDecoderMemberKey]*Member{},
+	}
+
+	decoder.decode()
+
+	return decoder.elaboration, nil
+}
+
+func (d *elaborationDecoder) errorf(format string, args ...any) {
+	panic(ElaborationDecodingError{
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *elaborationDecoder) check(err error) {
+	if err != nil {
+		panic(ElaborationDecodingError{
+			Message: err.Error(),
+		})
+	}
+}
+
+func (d *elaborationDecoder) readUint() uint64 {
+	value, err := d.decoder.DecodeUint64()
+	d.check(err)
+	return value
+}
+
+func (d *elaborationDecoder) readInt() int64 {
+	value, err := d.decoder.DecodeInt64()
+	d.check(err)
+	return value
+}
+
+func (d *elaborationDecoder) readBool() bool {
+	value, err := d.decoder.DecodeBool()
+	d.check(err)
+	return value
+}
+
+func (d *elaborationDecoder) readString() string {
+	value, err := d.decoder.DecodeString()
+	d.check(err)
+	return value
+}
+
+func (d *elaborationDecoder) readLength() int {
+	length := d.readUint()
+	if length > math.MaxInt32 {
+		d.errorf("invalid length: %d", length)
+	}
+	return int(length)
+}
+
+func (d *elaborationDecoder) readStrings() []string {
+	isNonNil := d.readBool()
+	length := d.readLength()
+	if !isNonNil {
+		return nil
+	}
+	values := make([]string, 0, length)
+	for i := 0; i < length; i++ {
+		values = append(values, d.readString())
+	}
+	return values
+}
+
+func (d *elaborationDecoder) readNode() any {
+	index := d.readUint()
+	if index >= uint64(len(d.nodes.nodes)) {
+		d.errorf("invalid element index: %d", index)
+	}
+	return d.nodes.nodes[index]
+}
+
+// readNodeOf reads an element, which must be of the given type
+func readNodeOf[T any](d *elaborationDecoder) T {
+	node := d.readNode()
+	result, ok := node.(T)
+	if !ok {
+		d.errorf("unexpected element: %T", node)
+	}
+	return result
+}
+
+// decodeEntries decodes entries which are keyed by elements of the given type.
+// The given function decodes the value of an entry
+func decodeEntries[T any](d *elaborationDecoder, decode func(node T)) {
+	count := d.readLength()
+	for i := 0; i < count; i++ {
+		decode(readNodeOf[T](d))
+	}
+}
+
+func (d *elaborationDecoder) readLocation() common.Location {
+	location, _, err := common.DecodeTypeID(d.config.MemoryGauge, d.readString())
+	d.check(err)
+	return location
+}
+
+func (d *elaborationDecoder) readPosition() ast.Position {
+	return ast.Position{
+		Offset: int(d.readInt()),
+		Line:   int(d.readInt()),
+		Column: int(d.readInt()),
+	}
+}
+
+func (d *elaborationDecoder) readIdentifier() ast.Identifier {
+	return ast.Identifier{
+		Identifier: d.readString(),
+		Pos:        d.readPosition(),
+	}
+}
+
+func (d *elaborationDecoder) decode() {
+	elaboration := d.elaboration
+
+	// Header
+
+	version := d.readUint()
+	if version != ElaborationEncodingVersion {
+		d.errorf("unsupported version: %d", version)
+	}
+
+	nodeCount := d.readLength()
+	checksum := d.readUint()
+	if nodeCount != len(d.nodes.nodes) || checksum != d.nodes.checksum() {
+		d.errorf("program does not match")
+	}
+
+	// Post-conditions rewrites
+
+	// The program was already checked, so errors can be ignored
+	ignoreError := func(error) {}
+
+	// The checker uses a single before-extractor for the whole program,
+	// so the extracted variables have unique names
+	rewriter := postConditionsRewriter{
+		memoryGauge:     d.config.MemoryGauge,
+		beforeExtractor: NewBeforeExtractor(d.config.MemoryGauge, ignoreError),
+		report:          ignoreError,
+	}
+
+	rewriteCount := d.readLength()
+	for i := 0; i < rewriteCount; i++ {
+		conditions := readNodeOf[*ast.Conditions](d)
+		rewrite := rewriter.rewritePostConditions(conditions.Conditions)
+		elaboration.SetPostConditionsRewrite(conditions, rewrite)
+		d.nodes.indexRewrite(rewrite)
+	}
+
+	// Types declared in the program
+
+	ownTypeCount := d.readLength()
+	ownTypes := make([]Type, 0, ownTypeCount)
+	for i := 0; i < ownTypeCount; i++ {
+		ty := d.decodeType()
+		switch ty := ty.(type) {
+		case *CompositeType:
+			elaboration.SetCompositeType(ty.ID(), ty)
+		case *InterfaceType:
+			elaboration.SetInterfaceType(ty.ID(), ty)
+		case *EntitlementType:
+			elaboration.SetEntitlementType(ty.ID(), ty)
+		case *EntitlementMapType:
+			// The inclusions of the entitlement map were already resolved
+			ty.resolveInclusions.Do(func() {})
+			elaboration.SetEntitlementMapType(ty.ID(), ty)
+		default:
+			d.errorf("unexpected declared type: %T", ty)
+		}
+		ownTypes = append(ownTypes, ty)
+	}
+
+	// Globals
+
+	d.decodeVariables(elaboration.SetGlobalValue)
+	d.decodeVariables(elaboration.SetGlobalType)
+
+	transactionTypeCount := d.readLength()
+	for i := 0; i < transactionTypeCount; i++ {
+		elaboration.TransactionTypes = append(
+			elaboration.TransactionTypes,
+			d.decodeTransactionType(),
+		)
+	}
+
+	// Function types
+
+	decodeEntries(d, func(declaration *ast.FunctionDeclaration) {
+		elaboration.SetFunctionDeclarationFunctionType(declaration, d.decodeFunctionType())
+	})
+
+	decodeEntries(d, func(expression *ast.FunctionExpression) {
+		elaboration.SetFunctionExpressionFunctionType(expression, d.decodeFunctionType())
+	})
+
+	decodeEntries(d, func(declaration *ast.SpecialFunctionDeclaration) {
+		elaboration.SetConstructorFunctionType(declaration, d.decodeFunctionType())
+	})
+
+	// Elements
+
+	decodeEntries(d, func(expression *ast.ArrayExpression) {
+		arrayType, ok := d.decodeType().(ArrayType)
+		if !ok {
+			d.errorf("expected array type")
+		}
+		elaboration.SetArrayExpressionTypes(
+			expression,
+			ArrayExpressionTypes{
+				ArrayType:     arrayType,
+				ArgumentTypes: d.decodeTypes(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(statement *ast.AssignmentStatement) {
+		elaboration.SetAssignmentStatementTypes(
+			statement,
+			AssignmentStatementTypes{
+				ValueType:  d.decodeType(),
+				TargetType: d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.AttachExpression) {
+		elaboration.SetAttachTypes(expression, d.decodeCompositeType())
+	})
+
+	decodeEntries(d, func(expression *ast.IndexExpression) {
+		elaboration.SetAttachmentAccessTypes(expression, d.decodeType())
+	})
+
+	decodeEntries(d, func(statement *ast.RemoveStatement) {
+		elaboration.SetAttachmentRemoveTypes(statement, d.decodeType())
+	})
+
+	decodeEntries(d, func(expression *ast.BinaryExpression) {
+		elaboration.SetBinaryExpressionTypes(
+			expression,
+			BinaryExpressionTypes{
+				ResultType: d.decodeType(),
+				LeftType:   d.decodeType(),
+				RightType:  d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.CastingExpression) {
+		elaboration.SetCastingExpressionTypes(
+			expression,
+			CastingExpressionTypes{
+				StaticValueType: d.decodeType(),
+				TargetType:      d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(declaration ast.CompositeLikeDeclaration) {
+		elaboration.SetCompositeDeclarationType(declaration, d.decodeCompositeType())
+	})
+
+	decodeEntries(d, func(declaration ast.CompositeLikeDeclaration) {
+		elaboration.SetCompositeTypeDeclaration(d.decodeCompositeType(), declaration)
+	})
+
+	decodeEntries(d, func(declaration ast.CompositeLikeDeclaration) {
+		elaboration.SetCompositeNestedDeclarations(declaration, d.decodeNestedDeclarations())
+	})
+
+	decodeEntries(d, func(declaration ast.Declaration) {
+		elaboration.SetDefaultDestroyDeclaration(
+			declaration,
+			readNodeOf[ast.CompositeLikeDeclaration](d),
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.DictionaryExpression) {
+		var dictionaryType *DictionaryType
+		ty := d.decodeType()
+		if ty != nil {
+			var ok bool
+			dictionaryType, ok = ty.(*DictionaryType)
+			if !ok {
+				d.errorf("expected dictionary type")
+			}
+		}
+
+		entryTypeCount := d.readLength()
+		entryTypes := make([]DictionaryEntryType, 0, entryTypeCount)
+		for i := 0; i < entryTypeCount; i++ {
+			entryTypes = append(
+				entryTypes,
+				DictionaryEntryType{
+					KeyType:   d.decodeType(),
+					ValueType: d.decodeType(),
+				},
+			)
+		}
+
+		elaboration.SetDictionaryExpressionTypes(
+			expression,
+			DictionaryExpressionTypes{
+				DictionaryType: dictionaryType,
+				EntryTypes:     entryTypes,
+			},
+		)
+	})
+
+	decodeEntries(d, func(statement *ast.EmitStatement) {
+		elaboration.SetEmitStatementEventType(statement, d.decodeCompositeType())
+	})
+
+	// The entitlement, entitlement map, and interface declarations
+
+	for _, ty := range ownTypes {
+		switch ty := ty.(type) {
+		case *EntitlementType:
+			if d.readBool() {
+				elaboration.SetEntitlementDeclarationWithType(
+					readNodeOf[*ast.EntitlementDeclaration](d),
+					ty,
+				)
+			}
+		case *EntitlementMapType:
+			if d.readBool() {
+				elaboration.SetEntitlementMapDeclarationWithType(
+					readNodeOf[*ast.EntitlementMappingDeclaration](d),
+					ty,
+				)
+			}
+		case *InterfaceType:
+			if d.readBool() {
+				elaboration.SetInterfaceDeclarationWithType(
+					readNodeOf[*ast.InterfaceDeclaration](d),
+					ty,
+				)
+			}
+		}
+	}
+
+	decodeEntries(d, func(expression *ast.FixedPointExpression) {
+		elaboration.SetFixedPointExpression(expression, d.decodeType())
+	})
+
+	decodeEntries(d, func(statement *ast.ForStatement) {
+		elaboration.SetForStatementType(
+			statement,
+			ForStatementTypes{
+				IterableType:      d.decodeType(),
+				IndexVariableType: d.decodeType(),
+				ValueVariableType: d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(declaration *ast.ImportDeclaration) {
+		resolvedLocationCount := d.readLength()
+		resolvedLocations := make([]ResolvedLocation, 0, resolvedLocationCount)
+		for i := 0; i < resolvedLocationCount; i++ {
+			location := d.readLocation()
+
+			identifierCount := d.readLength()
+			identifiers := make([]ast.Identifier, 0, identifierCount)
+			for j := 0; j < identifierCount; j++ {
+				identifiers = append(identifiers, d.readIdentifier())
+			}
+
+			resolvedLocations = append(
+				resolvedLocations,
+				ResolvedLocation{
+					Location:    location,
+					Identifiers: identifiers,
+				},
+			)
+		}
+		elaboration.SetImportDeclarationsResolvedLocations(declaration, resolvedLocations)
+	})
+
+	decodeEntries(d, func(expression *ast.IndexExpression) {
+		var indexedType ValueIndexableType
+		ty := d.decodeType()
+		if ty != nil {
+			var ok bool
+			indexedType, ok = ty.(ValueIndexableType)
+			if !ok {
+				d.errorf("expected indexable type")
+			}
+		}
+
+		elaboration.SetIndexExpressionTypes(
+			expression,
+			IndexExpressionTypes{
+				IndexedType:     indexedType,
+				IndexingType:    d.decodeType(),
+				ResultType:      d.decodeType(),
+				ReturnReference: d.readBool(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.IntegerExpression) {
+		elaboration.SetIntegerExpressionType(expression, d.decodeType())
+	})
+
+	decodeEntries(d, func(declaration *ast.InterfaceDeclaration) {
+		elaboration.SetInterfaceNestedDeclarations(declaration, d.decodeNestedDeclarations())
+	})
+
+	decodeEntries(d, func(expression *ast.InvocationExpression) {
+		returnType := d.decodeType()
+
+		var typeArguments *TypeParameterTypeOrderedMap
+		if d.readBool() {
+			typeArgumentCount := d.readLength()
+			typeArguments = orderedmap.New[TypeParameterTypeOrderedMap](typeArgumentCount)
+			for i := 0; i < typeArgumentCount; i++ {
+				typeParameter := d.decodeTypeParameter()
+				typeArguments.Set(typeParameter, d.decodeType())
+			}
+		}
+
+		elaboration.SetInvocationExpressionTypes(
+			expression,
+			InvocationExpressionTypes{
+				ReturnType:     returnType,
+				TypeArguments:  typeArguments,
+				ArgumentTypes:  d.decodeTypes(),
+				ParameterTypes: d.decodeTypes(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.MemberExpression) {
+		elaboration.SetMemberExpressionMemberAccessInfo(
+			expression,
+			MemberAccessInfo{
+				AccessedType:    d.decodeType(),
+				ResultingType:   d.decodeType(),
+				Member:          d.decodeMemberReference(),
+				IsOptional:      d.readBool(),
+				ReturnReference: d.readBool(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression ast.Expression) {
+		elaboration.SetIsNestedResourceMoveExpression(expression)
+	})
+
+	decodeEntries(d, func(expression *ast.ReferenceExpression) {
+		elaboration.SetReferenceExpressionBorrowType(expression, d.decodeType())
+	})
+
+	decodeEntries(d, func(statement *ast.ReturnStatement) {
+		elaboration.SetReturnStatementTypes(
+			statement,
+			ReturnStatementTypes{
+				ValueType:  d.decodeType(),
+				ReturnType: d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(expression *ast.StringExpression) {
+		elaboration.SetStringExpressionType(expression, d.decodeType())
+	})
+
+	decodeEntries(d, func(statement *ast.SwapStatement) {
+		elaboration.SetSwapStatementTypes(
+			statement,
+			SwapStatementTypes{
+				LeftType:  d.decodeType(),
+				RightType: d.decodeType(),
+			},
+		)
+	})
+
+	decodeEntries(d, func(declaration *ast.TransactionDeclaration) {
+		elaboration.SetTransactionDeclarationType(declaration, d.decodeTransactionType())
+	})
+
+	decodeEntries(d, func(declaration *ast.VariableDeclaration) {
+		elaboration.SetVariableDeclarationTypes(
+			declaration,
+			VariableDeclarationTypes{
+				ValueType:       d.decodeType(),
+				SecondValueType: d.decodeType(),
+				TargetType:      d.decodeType(),
+			},
+		)
+	})
+
+	elaboration.IsRecovered = d.readBool()
+}
+
+func (d *elaborationDecoder) registerType(ty Type) {
+	d.types = append(d.types, ty)
+}
+
+func (d *elaborationDecoder) decodeTypes() []Type {
+	isNonNil := d.readBool()
+	length := d.readLength()
+	if !isNonNil {
+		return nil
+	}
+	types := make([]Type, 0, length)
+	for i := 0; i < length; i++ {
+		types = append(types, d.decodeType())
+	}
+	return types
+}
+
+func (d *elaborationDecoder) decodeCompositeType() *CompositeType {
+	ty := d.decodeType()
+	if ty == nil {
+		return nil
+	}
+	compositeType, ok := ty.(*CompositeType)
+	if !ok {
+		d.errorf("expected composite type, got %T", ty)
+	}
+	return compositeType
+}
+
+func (d *elaborationDecoder) decodeInterfaceType() *InterfaceType {
+	ty := d.decodeType()
+	interfaceType, ok := ty.(*InterfaceType)
+	if !ok {
+		d.errorf("expected interface type, got %T", ty)
+	}
+	return interfaceType
+}
+
+func (d *elaborationDecoder) decodeEntitlementType() *EntitlementType {
+	ty := d.decodeType()
+	entitlementType, ok := ty.(*EntitlementType)
+	if !ok {
+		d.errorf("expected entitlement type, got %T", ty)
+	}
+	return entitlementType
+}
+
+func (d *elaborationDecoder) decodeEntitlementMapType() *EntitlementMapType {
+	ty := d.decodeType()
+	entitlementMapType, ok := ty.(*EntitlementMapType)
+	if !ok {
+		d.errorf("expected entitlement map type, got %T", ty)
+	}
+	return entitlementMapType
+}
+
+func (d *elaborationDecoder) decodeFunctionType() *FunctionType {
+	ty := d.decodeType()
+	if ty == nil {
+		return nil
+	}
+	functionType, ok := ty.(*FunctionType)
+	if !ok {
+		d.errorf("expected function type, got %T", ty)
+	}
+	return functionType
+}
+
+func (d *elaborationDecoder) decodeTransactionType() *TransactionType {
+	ty := d.decodeType()
+	transactionType, ok := ty.(*TransactionType)
+	if !ok {
+		d.errorf("expected transaction type, got %T", ty)
+	}
+	return transactionType
+}
+
+func (d *elaborationDecoder) decodeInterfaceTypes() []*InterfaceType {
+	isNonNil := d.readBool()
+	length := d.readLength()
+	if !isNonNil {
+		return nil
+	}
+	interfaceTypes := make([]*InterfaceType, 0, length)
+	for i := 0; i < length; i++ {
+		interfaceTypes = append(interfaceTypes, d.decodeInterfaceType())
+	}
+	return interfaceTypes
+}
+
+func (d *elaborationDecoder) decodeType() Type {
+	memoryGauge := d.config.MemoryGauge
+	location := d.config.Location
+
+	tag := encodedTypeTag(d.readUint())
+	switch tag {
+	case encodedTypeTagNil:
+		return nil
+
+	case encodedTypeTagReference:
+		index := d.readUint()
+		if index >= uint64(len(d.types)) {
+			d.errorf("invalid type index: %d", index)
+		}
+		return d.types[index]
+
+	case encodedTypeTagBuiltin:
+		typeID := d.readString()
+		ty := d.config.builtinType(typeID)
+		if ty == nil {
+			d.errorf("unknown built-in type: %s", typeID)
+		}
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagOptional:
+		ty := NewOptionalType(memoryGauge, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagVariableSized:
+		ty := NewVariableSizedType(memoryGauge, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagConstantSized:
+		elementType := d.decodeType()
+		ty := NewConstantSizedType(memoryGauge, elementType, d.readInt())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagDictionary:
+		keyType := d.decodeType()
+		ty := NewDictionaryType(memoryGauge, keyType, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagReferenceType:
+		authorization := d.decodeAccess()
+		ty := NewReferenceType(memoryGauge, authorization, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagIntersection:
+		types := d.decodeInterfaceTypes()
+		legacyType := d.decodeType()
+		if len(types) == 0 && legacyType == nil {
+			d.errorf("invalid intersection type")
+		}
+		ty := NewIntersectionType(memoryGauge, legacyType, types)
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagCapability:
+		ty := NewCapabilityType(memoryGauge, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagInclusiveRange:
+		ty := NewInclusiveRangeType(memoryGauge, d.decodeType())
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagGeneric:
+		ty := &GenericType{
+			TypeParameter: d.decodeTypeParameter(),
+		}
+		d.registerType(ty)
+		return ty
+
+	case encodedTypeTagFunction:
+		return d.decodeFunctionTypeDefinition()
+
+	case encodedTypeTagBaseValueFunction:
+		name := d.readString()
+		functionType := d.config.baseValueFunctionType(name)
+		if functionType == nil {
+			d.errorf("unknown base value function: %s", name)
+		}
+		return functionType
+
+	case encodedTypeTagImportedFunction:
+		importedLocation := d.readLocation()
+		name := d.readString()
+		importedElaboration := d.importedElaboration(importedLocation)
+		variable, ok := importedElaboration.GetGlobalValue(name)
+		if !ok {
+			d.errorf("unknown imported function: %s", name)
+		}
+		functionType, ok := variable.Type.(*FunctionType)
+		if !ok {
+			d.errorf("unknown imported function: %s", name)
+		}
+		return functionType
+
+	case encodedTypeTagMemberFunction:
+		containerType := d.decodeType()
+		identifier := d.readString()
+		member := d.resolveMember(containerType, identifier)
+		if member == nil {
+			d.errorf("unknown member function: %s", identifier)
+		}
+		functionType, ok := member.TypeAnnotation.Type.(*FunctionType)
+		if !ok {
+			d.errorf("unknown member function: %s", identifier)
+		}
+		return functionType
+
+	case encodedTypeTagTransaction:
+		ty := &TransactionType{}
+		d.registerType(ty)
+		ty.Fields = d.readStrings()
+		ty.PrepareParameters = d.decodeParameters()
+		ty.Parameters = d.decodeParameters()
+		ty.Members = d.decodeMembers()
+		return ty
+
+	case encodedTypeTagComposite:
+		ty := &CompositeType{
+			Location: location,
+		}
+		d.registerType(ty)
+		ty.Identifier = d.readString()
+		ty.Kind = common.CompositeKind(d.readUint())
+		ty.containerType = d.decodeType()
+		ty.EnumRawType = d.decodeType()
+		ty.NestedTypes = d.decodeNestedTypes()
+		ty.baseType = d.decodeType()
+		ty.baseTypeDocString = d.readString()
+		ty.DefaultDestroyEvent = d.decodeCompositeType()
+		ty.Members = d.decodeMembers()
+		ty.Fields = d.readStrings()
+		ty.ConstructorParameters = d.decodeParameters()
+		ty.ExplicitInterfaceConformances = d.decodeInterfaceTypes()
+		ty.ConstructorPurity = FunctionPurity(d.readUint())
+		ty.HasComputedMembers = d.readBool()
+		ty.ImportableBuiltin = d.readBool()
+		return ty
+
+	case encodedTypeTagInterface:
+		ty := &InterfaceType{
+			Location: location,
+		}
+		d.registerType(ty)
+		ty.Identifier = d.readString()
+		ty.CompositeKind = common.CompositeKind(d.readUint())
+		ty.containerType = d.decodeType()
+		ty.Members = d.decodeMembers()
+		ty.NestedTypes = d.decodeNestedTypes()
+		ty.Fields = d.readStrings()
+		ty.InitializerParameters = d.decodeParameters()
+		ty.InitializerPurity = FunctionPurity(d.readUint())
+		ty.ExplicitInterfaceConformances = d.decodeInterfaceTypes()
+		ty.DefaultDestroyEvent = d.decodeCompositeType()
+		return ty
+
+	case encodedTypeTagEntitlement:
+		ty := NewEntitlementType(memoryGauge, location, "")
+		d.registerType(ty)
+		ty.Identifier = d.readString()
+		ty.containerType = d.decodeType()
+		return ty
+
+	case encodedTypeTagEntitlementMap:
+		ty := NewEntitlementMapType(memoryGauge, location, "")
+		d.registerType(ty)
+		ty.Identifier = d.readString()
+		ty.containerType = d.decodeType()
+		relationCount := d.readLength()
+		ty.Relations = make([]EntitlementRelation, 0, relationCount)
+		for i := 0; i < relationCount; i++ {
+			common.UseMemory(memoryGauge, common.EntitlementRelationSemaTypeMemoryUsage)
+			ty.Relations = append(
+				ty.Relations,
+				EntitlementRelation{
+					Input:  d.decodeEntitlementType(),
+					Output: d.decodeEntitlementType(),
+				},
+			)
+		}
+		ty.IncludesIdentity = d.readBool()
+		return ty
+
+	case encodedTypeTagImportedComposite,
+		encodedTypeTagImportedInterface,
+		encodedTypeTagImportedEntitlement,
+		encodedTypeTagImportedEntitlementMap:
+
+		typeID := d.readString()
+		importedLocation, _, err := common.DecodeTypeID(memoryGauge, typeID)
+		d.check(err)
+		ty := importedType(d.importedElaboration(importedLocation), tag, TypeID(typeID))
+		if ty == nil {
+			d.errorf("unknown imported type: %s", typeID)
+		}
+		d.registerType(ty)
+		return ty
+
+	default:
+		d.errorf("invalid type tag: %d", tag)
+		return nil
+	}
+}
+
+func (d *elaborationDecoder) importedElaboration(location common.Location) *Elaboration {
+	if d.config.ImportedElaboration == nil {
+		d.errorf("cannot import %s", location)
+	}
+	elaboration, err := d.config.ImportedElaboration(location)
+	d.check(err)
+	if elaboration == nil {
+		d.errorf("cannot import %s", location)
+	}
+	return elaboration
+}
+
+func (d *elaborationDecoder) decodeFunctionTypeDefinition() *FunctionType {
+	functionType := &FunctionType{}
+	d.registerType(functionType)
+
+	functionType.Purity = FunctionPurity(d.readUint())
+
+	isNonNil := d.readBool()
+	typeParameterCount := d.readLength()
+	if isNonNil {
+		functionType.TypeParameters = make([]*TypeParameter, 0, typeParameterCount)
+	}
+	for i := 0; i < typeParameterCount; i++ {
+		functionType.TypeParameters = append(
+			functionType.TypeParameters,
+			d.decodeTypeParameter(),
+		)
+	}
+
+	functionType.Parameters = d.decodeParameters()
+	functionType.ReturnTypeAnnotation = d.decodeTypeAnnotation()
+
+	if d.readBool() {
+		functionType.Arity = &Arity{
+			Min: int(d.readInt()),
+			Max: int(d.readInt()),
+		}
+	}
+
+	functionType.IsConstructor = d.readBool()
+	functionType.Members = d.decodeMembers()
+
+	return functionType
+}
+
+func (d *elaborationDecoder) decodeTypeParameter() *TypeParameter {
+	tag := encodedTypeParameterTag(d.readUint())
+	switch tag {
+	case encodedTypeParameterTagReference:
+		index := d.readUint()
+		if index >= uint64(len(d.typeParameters)) {
+			d.errorf("invalid type parameter index: %d", index)
+		}
+		return d.typeParameters[index]
+
+	case encodedTypeParameterTagDefinition:
+		typeParameter := &TypeParameter{}
+		d.typeParameters = append(d.typeParameters, typeParameter)
+		typeParameter.Name = d.readString()
+		typeParameter.Optional = d.readBool()
+		typeParameter.NonNative = d.readBool()
+		typeParameter.TypeBound = d.decodeType()
+		return typeParameter
+
+	case encodedTypeParameterTagFunction:
+		functionType := d.decodeFunctionType()
+		index := d.readLength()
+		if functionType == nil || index >= len(functionType.TypeParameters) {
+			d.errorf("invalid type parameter index: %d", index)
+		}
+		return functionType.TypeParameters[index]
+
+	default:
+		d.errorf("invalid type parameter tag: %d", tag)
+		return nil
+	}
+}
+
+func (d *elaborationDecoder) decodeTypeAnnotation() TypeAnnotation {
+	isResource := d.readBool()
+	return TypeAnnotation{
+		IsResource: isResource,
+		Type:       d.decodeType(),
+	}
+}
+
+func (d *elaborationDecoder) decodeParameters() []Parameter {
+	isNonNil := d.readBool()
+	length := d.readLength()
+	if !isNonNil {
+		return nil
+	}
+	parameters := make([]Parameter, 0, length)
+	for i := 0; i < length; i++ {
+		parameters = append(
+			parameters,
+			Parameter{
+				Label:           d.readString(),
+				Identifier:      d.readString(),
+				TypeAnnotation:  d.decodeTypeAnnotation(),
+				DefaultArgument: d.decodeType(),
+			},
+		)
+	}
+	return parameters
+}
+
+func (d *elaborationDecoder) decodeNestedTypes() *StringTypeOrderedMap {
+	if !d.readBool() {
+		return nil
+	}
+	length := d.readLength()
+	nestedTypes := orderedmap.New[StringTypeOrderedMap](length)
+	for i := 0; i < length; i++ {
+		name := d.readString()
+		nestedTypes.Set(name, d.decodeType())
+	}
+	return nestedTypes
+}
+
+func (d *elaborationDecoder) decodeNestedDeclarations() map[string]ast.Declaration {
+	isNonNil := d.readBool()
+	length := d.readLength()
+	if !isNonNil {
+		return nil
+	}
+	declarations := make(map[string]ast.Declaration, length)
+	for i := 0; i < length; i++ {
+		name := d.readString()
+		declarations[name] = readNodeOf[ast.Declaration](d)
+	}
+	return declarations
+}
+
+func (d *elaborationDecoder) decodeAccess() Access {
+	tag := encodedAccessTag(d.readUint())
+	switch tag {
+	case encodedAccessTagNil:
+		return nil
+
+	case encodedAccessTagPrimitive:
+		return PrimitiveAccess(d.readUint())
+
+	case encodedAccessTagEntitlementSet:
+		setKind := EntitlementSetKind(d.readUint())
+		length := d.readLength()
+		entitlements := make([]*EntitlementType, 0, length)
+		for i := 0; i < length; i++ {
+			entitlements = append(entitlements, d.decodeEntitlementType())
+		}
+		return NewEntitlementSetAccess(entitlements, setKind)
+
+	case encodedAccessTagEntitlementMap:
+		return NewEntitlementMapAccess(d.decodeEntitlementMapType())
+
+	default:
+		d.errorf("invalid access tag: %d", tag)
+		return nil
+	}
+}
+
+func (d *elaborationDecoder) decodeMember() *Member {
+	return &Member{
+		Identifier:            d.readIdentifier(),
+		TypeAnnotation:        d.decodeTypeAnnotation(),
+		ContainerType:         d.decodeType(),
+		DocString:             d.readString(),
+		ArgumentLabels:        d.readStrings(),
+		Access:                d.decodeAccess(),
+		DeclarationKind:       common.DeclarationKind(d.readUint()),
+		VariableKind:          ast.VariableKind(d.readUint()),
+		Predeclared:           d.readBool(),
+		HasImplementation:     d.readBool(),
+		HasConditions:         d.readBool(),
+		IgnoreInSerialization: d.readBool(),
+	}
+}
+
+func (d *elaborationDecoder) decodeMembers() *StringMemberOrderedMap {
+	if !d.readBool() {
+		return nil
+	}
+	length := d.readLength()
+	members := orderedmap.New[StringMemberOrderedMap](length)
+	for i := 0; i < length; i++ {
+		name := d.readString()
+		members.Set(name, d.decodeMember())
+	}
+	return members
+}
+
+func (d *elaborationDecoder) decodeMemberReference() *Member {
+	tag := encodedMemberTag(d.readUint())
+	switch tag {
+	case encodedMemberTagNil:
+		return nil
+
+	case encodedMemberTagReference:
+		containerType := d.decodeType()
+		identifier := d.readString()
+		if containerType == nil {
+			d.errorf("unknown member: %s", identifier)
+		}
+		member := d.resolveMember(containerType, identifier)
+		if member == nil {
+			d.errorf("unknown member: %s", identifier)
+		}
+		return member
+
+	case encodedMemberTagDefinition:
+		return d.decodeMember()
+
+	default:
+		d.errorf("invalid member tag: %d", tag)
+		return nil
+	}
+}
+
+func (d *elaborationDecoder) resolveMember(containerType Type, identifier string) *Member {
+	key := elaborationDecoderMemberKey{
+		containerType: containerType,
+		identifier:    identifier,
+	}
+	member, ok := d.members[key]
+	if !ok {
+		member = resolveMember(d.config.MemoryGauge, containerType, identifier)
+		d.members[key] = member
+	}
+	return member
+}
+
+func (d *elaborationDecoder) decodeVariables(set func(name string, variable *Variable)) {
+	length := d.readLength()
+	for i := 0; i < length; i++ {
+		name := d.readString()
+
+		variable := &Variable{
+			Identifier: d.readString(),
+			Type:       d.decodeType(),
+		}
+		if d.readBool() {
+			position := d.readPosition()
+			variable.Pos = &position
+		}
+		variable.DocString = d.readString()
+		variable.ArgumentLabels = d.readStrings()
+		variable.DeclarationKind = common.DeclarationKind(d.readUint())
+		variable.Access = d.decodeAccess()
+		variable.ActivationDepth = int(d.readInt())
+		variable.IsConstant = d.readBool()
+
+		set(name, variable)
+	}
+}
@@ -0,0 +1,1099 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+)
+
+// functionTypeReference is a reference to a function type which is not declared in the encoded program,
+// e.g. the function type of a built-in function, or of a member of a built-in type
+type functionTypeReference struct {
+	tag encodedTypeTag
+	// containerType is the container type of the member, for member function references
+	containerType Type
+	// location is the location of the imported program, for imported function references
+	location common.Location
+	// name is the name of the base value or global value, or the identifier of the member
+	name string
+}
+
+// typeParameterOwner is a reference to a type parameter of a function type
+type typeParameterOwner struct {
+	functionType *FunctionType
+	index        int
+}
+
+type elaborationEncoder struct {
+	config      *ElaborationEncodingConfig
+	elaboration *Elaboration
+	nodes       *elaborationNodes
+	buffer      bytes.Buffer
+	encoder     *cbor.StreamEncoder
+	// ownTypes are the nominal types declared in the encoded program
+	ownTypes               map[Type]struct{}
+	types                  map[Type]uint64
+	typeCount              uint64
+	typeParameters         map[*TypeParameter]uint64
+	functionTypeReferences map[*FunctionType]functionTypeReference
+	typeParameterOwners    map[*TypeParameter]typeParameterOwner
+}
+
+// EncodeElaboration encodes the given elaboration of the given checked program.
+//
+// The result can be decoded using DecodeElaboration,
+// given the same program and the same configuration.
+func EncodeElaboration(
+	program *ast.Program,
+	elaboration *Elaboration,
+	config *ElaborationEncodingConfig,
+) (
+	result []byte,
+	err error,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			encodingErr, ok := r.(ElaborationEncodingError)
+			if !ok {
+				panic(r)
+			}
+			err = encodingErr
+		}
+	}()
+
+	encoder := &elaborationEncoder{
+		config:                 config,
+		elaboration:            elaboration,
+		nodes:                  newElaborationNodes(program),
+		ownTypes:               map[Type]struct{}{},
+		types:                  map[Type]uint64{},
+		typeParameters:         map[*TypeParameter]uint64{},
+		functionTypeReferences: map[*FunctionType]functionTypeReference{},
+		typeParameterOwners:    map[*TypeParameter]typeParameterOwner{},
+	}
+	encoder.encoder = elaborationCBOREncMode.NewStreamEncoder(&encoder.buffer)
+
+	encoder.encode()
+
+	err = encoder.encoder.Flush()
+	if err != nil {
+		return nil, ElaborationEncodingError{
+			Message: err.Error(),
+		}
+	}
+
+	return encoder.buffer.Bytes(), nil
+}
+
+func (e *elaborationEncoder) errorf(format string, args ...any) {
+	panic(ElaborationEncodingError{
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *elaborationEncoder) check(err error) {
+	if err != nil {
+		panic(ElaborationEncodingError{
+			Message: err.Error(),
+		})
+	}
+}
+
+func (e *elaborationEncoder) writeUint(value uint64) {
+	e.check(e.encoder.EncodeUint64(value))
+}
+
+func (e *elaborationEncoder) writeInt(value int64) {
+	e.check(e.encoder.EncodeInt64(value))
+}
+
+func (e *elaborationEncoder) writeBool(value bool) {
+	e.check(e.encoder.EncodeBool(value))
+}
+
+func (e *elaborationEncoder) writeString(value string) {
+	e.check(e.encoder.EncodeString(value))
+}
+
+func (e *elaborationEncoder) writeLength(length int) {
+	e.writeUint(uint64(length))
+}
+
+func (e *elaborationEncoder) writeStrings(values []string) {
+	e.writeBool(values != nil)
+	e.writeLength(len(values))
+	for _, value := range values {
+		e.writeString(value)
+	}
+}
+
+func (e *elaborationEncoder) writeNode(node any) {
+	if emitCondition, ok := node.(*ast.EmitCondition); ok {
+		node = (*ast.EmitStatement)(emitCondition)
+	}
+
+	index, ok := e.nodes.indices[node]
+	if !ok {
+		e.errorf("unknown element: %T", node)
+	}
+	e.writeUint(index)
+}
+
+func (e *elaborationEncoder) writeOptionalNode(node any, isNil bool) {
+	e.writeBool(!isNil)
+	if !isNil {
+		e.writeNode(node)
+	}
+}
+
+func (e *elaborationEncoder) writeLocation(location common.Location) {
+	e.writeString(encodedLocationTypeID(location))
+}
+
+func (e *elaborationEncoder) writePosition(position ast.Position) {
+	e.writeInt(int64(position.Offset))
+	e.writeInt(int64(position.Line))
+	e.writeInt(int64(position.Column))
+}
+
+func (e *elaborationEncoder) writeIdentifier(identifier ast.Identifier) {
+	e.writeString(identifier.Identifier)
+	e.writePosition(identifier.Pos)
+}
+
+// encodeEntries encodes the given entries, which are keyed by elements,
+// in the order of the elements.
+// The given function encodes the value of an entry
+func encodeEntries[K comparable, V any](e *elaborationEncoder, entries map[K]V, encode func(key K, value V)) {
+	keys := sortedNodes(e, entries)
+	e.writeLength(len(keys))
+	for _, key := range keys {
+		e.writeNode(key)
+		if encode != nil {
+			encode(key, entries[key])
+		}
+	}
+}
+
+// sortedNodes returns the keys of the given entries, which are elements, in the order of the elements
+func sortedNodes[K comparable, V any](e *elaborationEncoder, entries map[K]V) []K {
+	keys := make([]K, 0, len(entries))
+	for key := range entries { //nolint:maprange
+		keys = append(keys, key)
+	}
+
+	indices := make(map[any]uint64, len(keys))
+	for _, key := range keys {
+		var node any = key
+		if emitCondition, ok := node.(*ast.EmitCondition); ok {
+			node = (*ast.EmitStatement)(emitCondition)
+		}
+		index, ok := e.nodes.indices[node]
+		if !ok {
+			e.errorf("unknown element: %T", node)
+		}
+		indices[key] = index
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return indices[keys[i]] < indices[keys[j]]
+	})
+
+	return keys
+}
+
+func (e *elaborationEncoder) encode() {
+	elaboration := e.elaboration
+
+	// Header
+
+	e.writeUint(ElaborationEncodingVersion)
+	e.writeLength(len(e.nodes.nodes))
+	e.writeUint(e.nodes.checksum())
+
+	// Post-conditions rewrites.
+	// The rewrites are not encoded, they are performed again when decoding
+
+	var rewrittenConditions []uint64
+	for _, conditions := range e.nodes.postConditions {
+		rewrite, ok := elaboration.postConditionsRewrites[conditions]
+		if !ok {
+			continue
+		}
+		rewrittenConditions = append(rewrittenConditions, e.nodes.indices[conditions])
+		e.nodes.indexRewrite(rewrite)
+	}
+	e.writeLength(len(rewrittenConditions))
+	for _, index := range rewrittenConditions {
+		e.writeUint(index)
+	}
+
+	ownTypes := e.collectOwnTypes()
+
+	e.prepareFunctionTypeReferences()
+
+	// Types declared in the program
+
+	e.writeLength(len(ownTypes))
+	for _, ty := range ownTypes {
+		e.encodeType(ty)
+	}
+
+	// Globals
+
+	e.encodeVariables(elaboration.globalValues)
+	e.encodeVariables(elaboration.globalTypes)
+
+	e.writeLength(len(elaboration.TransactionTypes))
+	for _, transactionType := range elaboration.TransactionTypes {
+		e.encodeType(transactionType)
+	}
+
+	// Function types.
+	// Encoded before all other types, so type parameters are defined by their functions
+
+	encodeEntries(e, elaboration.functionDeclarationFunctionTypes, func(_ *ast.FunctionDeclaration, value *FunctionType) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.functionExpressionFunctionTypes, func(_ *ast.FunctionExpression, value *FunctionType) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.constructorFunctionTypes, func(_ *ast.SpecialFunctionDeclaration, value *FunctionType) {
+		e.encodeType(value)
+	})
+
+	// Elements
+
+	encodeEntries(e, elaboration.arrayExpressionTypes, func(_ *ast.ArrayExpression, value ArrayExpressionTypes) {
+		e.encodeType(value.ArrayType)
+		e.encodeTypes(value.ArgumentTypes)
+	})
+
+	encodeEntries(e, elaboration.assignmentStatementTypes, func(_ *ast.AssignmentStatement, value AssignmentStatementTypes) {
+		e.encodeType(value.ValueType)
+		e.encodeType(value.TargetType)
+	})
+
+	encodeEntries(e, elaboration.attachTypes, func(_ *ast.AttachExpression, value *CompositeType) {
+		e.encodeCompositeType(value)
+	})
+
+	encodeEntries(e, elaboration.attachmentAccessTypes, func(_ *ast.IndexExpression, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.attachmentRemoveTypes, func(_ *ast.RemoveStatement, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.binaryExpressionTypes, func(_ *ast.BinaryExpression, value BinaryExpressionTypes) {
+		e.encodeType(value.ResultType)
+		e.encodeType(value.LeftType)
+		e.encodeType(value.RightType)
+	})
+
+	encodeEntries(e, elaboration.castingExpressionTypes, func(_ *ast.CastingExpression, value CastingExpressionTypes) {
+		e.encodeType(value.StaticValueType)
+		e.encodeType(value.TargetType)
+	})
+
+	encodeEntries(e, elaboration.compositeDeclarationTypes, func(_ ast.CompositeLikeDeclaration, value *CompositeType) {
+		e.encodeCompositeType(value)
+	})
+
+	compositeTypeDeclarations := make(map[ast.CompositeLikeDeclaration]*CompositeType, len(elaboration.compositeTypeDeclarations))
+	for compositeType, declaration := range elaboration.compositeTypeDeclarations { //nolint:maprange
+		compositeTypeDeclarations[declaration] = compositeType
+	}
+	encodeEntries(e, compositeTypeDeclarations, func(_ ast.CompositeLikeDeclaration, value *CompositeType) {
+		e.encodeCompositeType(value)
+	})
+
+	encodeEntries(e, elaboration.compositeNestedDeclarations, func(_ ast.CompositeLikeDeclaration, value map[string]ast.Declaration) {
+		e.encodeNestedDeclarations(value)
+	})
+
+	encodeEntries(e, elaboration.defaultDestroyDeclarations, func(_ ast.Declaration, value ast.CompositeLikeDeclaration) {
+		e.writeNode(value)
+	})
+
+	encodeEntries(e, elaboration.dictionaryExpressionTypes, func(_ *ast.DictionaryExpression, value DictionaryExpressionTypes) {
+		if value.DictionaryType == nil {
+			e.encodeType(nil)
+		} else {
+			e.encodeType(value.DictionaryType)
+		}
+		e.writeLength(len(value.EntryTypes))
+		for _, entryType := range value.EntryTypes {
+			e.encodeType(entryType.KeyType)
+			e.encodeType(entryType.ValueType)
+		}
+	})
+
+	encodeEntries(e, elaboration.emitStatementEventTypes, func(_ *ast.EmitStatement, value *CompositeType) {
+		e.encodeCompositeType(value)
+	})
+
+	// The entitlement, entitlement map, and interface declarations
+	// are derived from the types declared in the program
+
+	for _, ty := range ownTypes {
+		switch ty := ty.(type) {
+		case *EntitlementType:
+			declaration := elaboration.EntitlementTypeDeclaration(ty)
+			e.writeOptionalNode(declaration, declaration == nil)
+		case *EntitlementMapType:
+			declaration := elaboration.EntitlementMapTypeDeclaration(ty)
+			e.writeOptionalNode(declaration, declaration == nil)
+		case *InterfaceType:
+			declaration := elaboration.InterfaceTypeDeclaration(ty)
+			e.writeOptionalNode(declaration, declaration == nil)
+		}
+	}
+
+	encodeEntries(e, elaboration.fixedPointExpressionTypes, func(_ *ast.FixedPointExpression, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.forStatementTypes, func(_ *ast.ForStatement, value ForStatementTypes) {
+		e.encodeType(value.IterableType)
+		e.encodeType(value.IndexVariableType)
+		e.encodeType(value.ValueVariableType)
+	})
+
+	encodeEntries(e, elaboration.importDeclarationsResolvedLocations, func(_ *ast.ImportDeclaration, value []ResolvedLocation) {
+		e.writeLength(len(value))
+		for _, resolvedLocation := range value {
+			e.writeLocation(resolvedLocation.Location)
+			e.writeLength(len(resolvedLocation.Identifiers))
+			for _, identifier := range resolvedLocation.Identifiers {
+				e.writeIdentifier(identifier)
+			}
+		}
+	})
+
+	encodeEntries(e, elaboration.indexExpressionTypes, func(_ *ast.IndexExpression, value IndexExpressionTypes) {
+		e.encodeType(value.IndexedType)
+		e.encodeType(value.IndexingType)
+		e.encodeType(value.ResultType)
+		e.writeBool(value.ReturnReference)
+	})
+
+	encodeEntries(e, elaboration.integerExpressionTypes, func(_ *ast.IntegerExpression, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.interfaceNestedDeclarations, func(_ *ast.InterfaceDeclaration, value map[string]ast.Declaration) {
+		e.encodeNestedDeclarations(value)
+	})
+
+	encodeEntries(e, elaboration.invocationExpressionTypes, func(_ *ast.InvocationExpression, value InvocationExpressionTypes) {
+		e.encodeType(value.ReturnType)
+		e.writeBool(value.TypeArguments != nil)
+		if value.TypeArguments != nil {
+			e.writeLength(value.TypeArguments.Len())
+			value.TypeArguments.Foreach(func(typeParameter *TypeParameter, ty Type) {
+				e.encodeTypeParameter(typeParameter)
+				e.encodeType(ty)
+			})
+		}
+		e.encodeTypes(value.ArgumentTypes)
+		e.encodeTypes(value.ParameterTypes)
+	})
+
+	encodeEntries(e, elaboration.memberExpressionMemberAccessInfos, func(_ *ast.MemberExpression, value MemberAccessInfo) {
+		e.encodeType(value.AccessedType)
+		e.encodeType(value.ResultingType)
+		e.encodeMemberReference(value.Member)
+		e.writeBool(value.IsOptional)
+		e.writeBool(value.ReturnReference)
+	})
+
+	encodeEntries(e, elaboration.nestedResourceMoveExpressions, nil)
+
+	encodeEntries(e, elaboration.referenceExpressionBorrowTypes, func(_ *ast.ReferenceExpression, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.returnStatementTypes, func(_ *ast.ReturnStatement, value ReturnStatementTypes) {
+		e.encodeType(value.ValueType)
+		e.encodeType(value.ReturnType)
+	})
+
+	encodeEntries(e, elaboration.stringExpressionTypes, func(_ *ast.StringExpression, value Type) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.swapStatementTypes, func(_ *ast.SwapStatement, value SwapStatementTypes) {
+		e.encodeType(value.LeftType)
+		e.encodeType(value.RightType)
+	})
+
+	encodeEntries(e, elaboration.transactionDeclarationTypes, func(_ *ast.TransactionDeclaration, value *TransactionType) {
+		e.encodeType(value)
+	})
+
+	encodeEntries(e, elaboration.variableDeclarationTypes, func(_ *ast.VariableDeclaration, value VariableDeclarationTypes) {
+		e.encodeType(value.ValueType)
+		e.encodeType(value.SecondValueType)
+		e.encodeType(value.TargetType)
+	})
+
+	e.writeBool(elaboration.IsRecovered)
+}
+
+// collectOwnTypes returns the nominal types declared in the program, sorted by type ID
+func (e *elaborationEncoder) collectOwnTypes() []Type {
+	elaboration := e.elaboration
+
+	typeIDs := make([]TypeID, 0, len(e.ownTypes))
+	typesByID := make(map[TypeID]Type, len(e.ownTypes))
+
+	add := func(typeID TypeID, ty Type) {
+		e.ownTypes[ty] = struct{}{}
+		typeIDs = append(typeIDs, typeID)
+		typesByID[typeID] = ty
+	}
+
+	for typeID, ty := range elaboration.compositeTypes { //nolint:maprange
+		add(typeID, ty)
+	}
+	for typeID, ty := range elaboration.interfaceTypes { //nolint:maprange
+		add(typeID, ty)
+	}
+	for typeID, ty := range elaboration.entitlementTypes { //nolint:maprange
+		add(typeID, ty)
+	}
+	for typeID, ty := range elaboration.entitlementMapTypes { //nolint:maprange
+		add(typeID, ty)
+	}
+
+	sort.Slice(typeIDs, func(i, j int) bool {
+		return typeIDs[i] < typeIDs[j]
+	})
+
+	types := make([]Type, 0, len(typeIDs))
+	for _, typeID := range typeIDs {
+		types = append(types, typesByID[typeID])
+	}
+	return types
+}
+
+// prepareFunctionTypeReferences determines which function types are not declared in the program,
+// and can be encoded as references: functions of the base value activation,
+// global functions of imported programs, and functions of members of types not declared in the program
+func (e *elaborationEncoder) prepareFunctionTypeReferences() {
+
+	addReference := func(functionType *FunctionType, reference functionTypeReference) {
+		if _, ok := e.functionTypeReferences[functionType]; ok {
+			return
+		}
+		e.functionTypeReferences[functionType] = reference
+
+		for index, typeParameter := range functionType.TypeParameters {
+			if _, ok := e.typeParameterOwners[typeParameter]; ok {
+				continue
+			}
+			e.typeParameterOwners[typeParameter] = typeParameterOwner{
+				functionType: functionType,
+				index:        index,
+			}
+		}
+	}
+
+	// Base values
+
+	baseValueActivation := e.config.baseValueActivation()
+	_ = baseValueActivation.ForEach(func(name string, variable *Variable) error {
+		// Ignore shadowed variables
+		if baseValueActivation.Find(name) != variable {
+			return nil
+		}
+		functionType, ok := variable.Type.(*FunctionType)
+		if !ok {
+			return nil
+		}
+		addReference(functionType, functionTypeReference{
+			tag:  encodedTypeTagBaseValueFunction,
+			name: name,
+		})
+		return nil
+	})
+
+	// Globals of imported programs
+
+	if e.config.ImportedElaboration != nil {
+		for _, declaration := range sortedNodes(e, e.elaboration.importDeclarationsResolvedLocations) {
+			resolvedLocations := e.elaboration.importDeclarationsResolvedLocations[declaration]
+			for _, resolvedLocation := range resolvedLocations {
+				location := resolvedLocation.Location
+				importedElaboration, err := e.config.ImportedElaboration(location)
+				if err != nil || importedElaboration == nil {
+					continue
+				}
+				importedElaboration.ForEachGlobalValue(func(name string, variable *Variable) {
+					functionType, ok := variable.Type.(*FunctionType)
+					if !ok {
+						return
+					}
+					addReference(functionType, functionTypeReference{
+						tag:      encodedTypeTagImportedFunction,
+						location: location,
+						name:     name,
+					})
+				})
+			}
+		}
+	}
+
+	// Members of types not declared in the program
+
+	for _, expression := range sortedNodes(e, e.elaboration.memberExpressionMemberAccessInfos) {
+		member := e.elaboration.memberExpressionMemberAccessInfos[expression].Member
+		if member == nil || e.isOwnType(member.ContainerType) {
+			continue
+		}
+		functionType, ok := member.TypeAnnotation.Type.(*FunctionType)
+		if !ok {
+			continue
+		}
+
+		identifier := member.Identifier.Identifier
+		resolvedMember := resolveMember(e.config.MemoryGauge, member.ContainerType, identifier)
+		// Some members are created when they are resolved,
+		// so the resolved function type might not be identical
+		if resolvedMember == nil || !equivalentResolvedMemberType(resolvedMember, functionType) {
+			continue
+		}
+
+		addReference(functionType, functionTypeReference{
+			tag:           encodedTypeTagMemberFunction,
+			containerType: member.ContainerType,
+			name:          identifier,
+		})
+	}
+}
+
+// isOwnType returns true if the given type is a nominal type declared in the program
+func (e *elaborationEncoder) isOwnType(ty Type) bool {
+	_, ok := e.ownTypes[ty]
+	return ok
+}
+
+func (e *elaborationEncoder) registerType(ty Type) {
+	e.types[ty] = e.typeCount
+	e.typeCount++
+}
+
+func (e *elaborationEncoder) encodeTypes(types []Type) {
+	e.writeBool(types != nil)
+	e.writeLength(len(types))
+	for _, ty := range types {
+		e.encodeType(ty)
+	}
+}
+
+func (e *elaborationEncoder) encodeCompositeType(compositeType *CompositeType) {
+	if compositeType == nil {
+		e.encodeType(nil)
+		return
+	}
+	e.encodeType(compositeType)
+}
+
+func (e *elaborationEncoder) encodeInterfaceTypes(interfaceTypes []*InterfaceType) {
+	e.writeBool(interfaceTypes != nil)
+	e.writeLength(len(interfaceTypes))
+	for _, interfaceType := range interfaceTypes {
+		e.encodeType(interfaceType)
+	}
+}
+
+func (e *elaborationEncoder) encodeType(ty Type) {
+	if ty == nil {
+		e.writeUint(uint64(encodedTypeTagNil))
+		return
+	}
+
+	if index, ok := e.types[ty]; ok {
+		e.writeUint(uint64(encodedTypeTagReference))
+		e.writeUint(index)
+		return
+	}
+
+	// Nominal types and function types are registered before their components are encoded,
+	// as they may refer to themselves. All other types are registered after their components
+
+	switch ty := ty.(type) {
+	case *SimpleType, *NumericType, *FixedPointNumericType, *AddressType:
+		e.encodeBuiltinType(ty)
+
+	case *OptionalType:
+		e.writeUint(uint64(encodedTypeTagOptional))
+		e.encodeType(ty.Type)
+		e.registerType(ty)
+
+	case *VariableSizedType:
+		e.writeUint(uint64(encodedTypeTagVariableSized))
+		e.encodeType(ty.Type)
+		e.registerType(ty)
+
+	case *ConstantSizedType:
+		e.writeUint(uint64(encodedTypeTagConstantSized))
+		e.encodeType(ty.Type)
+		e.writeInt(ty.Size)
+		e.registerType(ty)
+
+	case *DictionaryType:
+		e.writeUint(uint64(encodedTypeTagDictionary))
+		e.encodeType(ty.KeyType)
+		e.encodeType(ty.ValueType)
+		e.registerType(ty)
+
+	case *ReferenceType:
+		e.writeUint(uint64(encodedTypeTagReferenceType))
+		e.encodeAccess(ty.Authorization)
+		e.encodeType(ty.Type)
+		e.registerType(ty)
+
+	case *IntersectionType:
+		e.writeUint(uint64(encodedTypeTagIntersection))
+		e.encodeInterfaceTypes(ty.Types)
+		e.encodeType(ty.LegacyType)
+		e.registerType(ty)
+
+	case *CapabilityType:
+		e.writeUint(uint64(encodedTypeTagCapability))
+		e.encodeType(ty.BorrowType)
+		e.registerType(ty)
+
+	case *InclusiveRangeType:
+		e.writeUint(uint64(encodedTypeTagInclusiveRange))
+		e.encodeType(ty.MemberType)
+		e.registerType(ty)
+
+	case *GenericType:
+		e.writeUint(uint64(encodedTypeTagGeneric))
+		e.encodeTypeParameter(ty.TypeParameter)
+		e.registerType(ty)
+
+	case *FunctionType:
+		if reference, ok := e.functionTypeReferences[ty]; ok {
+			e.encodeFunctionTypeReference(ty, reference)
+		} else {
+			e.encodeFunctionType(ty)
+		}
+
+	case *TransactionType:
+		e.writeUint(uint64(encodedTypeTagTransaction))
+		e.registerType(ty)
+		e.writeStrings(ty.Fields)
+		e.encodeParameters(ty.PrepareParameters)
+		e.encodeParameters(ty.Parameters)
+		e.encodeMembers(ty.Members)
+
+	case *CompositeType:
+		switch {
+		case e.isOwnType(ty):
+			e.writeUint(uint64(encodedTypeTagComposite))
+			e.registerType(ty)
+			e.checkOwnLocation(ty.Location)
+			e.writeString(ty.Identifier)
+			e.writeUint(uint64(ty.Kind))
+			e.encodeType(ty.containerType)
+			e.encodeType(ty.EnumRawType)
+			e.encodeNestedTypes(ty.NestedTypes)
+			e.encodeType(ty.baseType)
+			e.writeString(ty.baseTypeDocString)
+			e.encodeCompositeType(ty.DefaultDestroyEvent)
+			e.encodeMembers(ty.Members)
+			e.writeStrings(ty.Fields)
+			e.encodeParameters(ty.ConstructorParameters)
+			e.encodeInterfaceTypes(ty.ExplicitInterfaceConformances)
+			e.writeUint(uint64(ty.ConstructorPurity))
+			e.writeBool(ty.HasComputedMembers)
+			e.writeBool(ty.ImportableBuiltin)
+
+		case ty.Location == nil:
+			e.encodeBuiltinType(ty)
+
+		default:
+			e.encodeImportedType(encodedTypeTagImportedComposite, ty, ty.Location)
+		}
+
+	case *InterfaceType:
+		switch {
+		case e.isOwnType(ty):
+			e.writeUint(uint64(encodedTypeTagInterface))
+			e.registerType(ty)
+			e.checkOwnLocation(ty.Location)
+			e.writeString(ty.Identifier)
+			e.writeUint(uint64(ty.CompositeKind))
+			e.encodeType(ty.containerType)
+			e.encodeMembers(ty.Members)
+			e.encodeNestedTypes(ty.NestedTypes)
+			e.writeStrings(ty.Fields)
+			e.encodeParameters(ty.InitializerParameters)
+			e.writeUint(uint64(ty.InitializerPurity))
+			e.encodeInterfaceTypes(ty.ExplicitInterfaceConformances)
+			e.encodeCompositeType(ty.DefaultDestroyEvent)
+
+		case ty.Location == nil:
+			e.encodeBuiltinType(ty)
+
+		default:
+			e.encodeImportedType(encodedTypeTagImportedInterface, ty, ty.Location)
+		}
+
+	case *EntitlementType:
+		switch {
+		case e.isOwnType(ty):
+			e.writeUint(uint64(encodedTypeTagEntitlement))
+			e.registerType(ty)
+			e.checkOwnLocation(ty.Location)
+			e.writeString(ty.Identifier)
+			e.encodeType(ty.containerType)
+
+		case ty.Location == nil:
+			e.encodeBuiltinType(ty)
+
+		default:
+			e.encodeImportedType(encodedTypeTagImportedEntitlement, ty, ty.Location)
+		}
+
+	case *EntitlementMapType:
+		switch {
+		case e.isOwnType(ty):
+			e.writeUint(uint64(encodedTypeTagEntitlementMap))
+			e.registerType(ty)
+			e.checkOwnLocation(ty.Location)
+			e.writeString(ty.Identifier)
+			e.encodeType(ty.containerType)
+			e.writeLength(len(ty.Relations))
+			for _, relation := range ty.Relations {
+				e.encodeType(relation.Input)
+				e.encodeType(relation.Output)
+			}
+			e.writeBool(ty.IncludesIdentity)
+
+		case ty.Location == nil:
+			e.encodeBuiltinType(ty)
+
+		default:
+			e.encodeImportedType(encodedTypeTagImportedEntitlementMap, ty, ty.Location)
+		}
+
+	default:
+		e.errorf("unsupported type: %T", ty)
+	}
+}
+
+func (e *elaborationEncoder) checkOwnLocation(location common.Location) {
+	if location != e.config.Location {
+		e.errorf("unexpected location: %s", location)
+	}
+}
+
+func (e *elaborationEncoder) encodeBuiltinType(ty Type) {
+	typeID := string(ty.ID())
+	if e.config.builtinType(typeID) != ty {
+		e.errorf("unknown built-in type: %s", typeID)
+	}
+
+	e.writeUint(uint64(encodedTypeTagBuiltin))
+	e.writeString(typeID)
+	e.registerType(ty)
+}
+
+func (e *elaborationEncoder) encodeImportedType(tag encodedTypeTag, ty Type, location common.Location) {
+	typeID := ty.ID()
+
+	if e.config.ImportedElaboration == nil {
+		e.errorf("cannot resolve imported type: %s", typeID)
+	}
+	// The imported program might have been loaded again since the program was checked,
+	// so the imported type is only required to be resolvable, not to be identical
+	importedElaboration, err := e.config.ImportedElaboration(location)
+	if err != nil || importedElaboration == nil || importedType(importedElaboration, tag, typeID) == nil {
+		e.errorf("cannot resolve imported type: %s", typeID)
+	}
+
+	e.writeUint(uint64(tag))
+	e.writeString(string(typeID))
+	e.registerType(ty)
+}
+
+func (e *elaborationEncoder) encodeFunctionTypeReference(functionType *FunctionType, reference functionTypeReference) {
+	e.writeUint(uint64(reference.tag))
+
+	switch reference.tag {
+	case encodedTypeTagBaseValueFunction:
+		e.writeString(reference.name)
+
+	case encodedTypeTagImportedFunction:
+		e.writeLocation(reference.location)
+		e.writeString(reference.name)
+
+	case encodedTypeTagMemberFunction:
+		e.encodeType(reference.containerType)
+		e.writeString(reference.name)
+
+	default:
+		e.errorf("unsupported function type reference: %d", reference.tag)
+	}
+
+	// References are not registered:
+	// Some referenced function types are created when they are resolved,
+	// so the same reference may result in different function types
+}
+
+func (e *elaborationEncoder) encodeFunctionType(functionType *FunctionType) {
+	if functionType.ArgumentExpressionsCheck != nil ||
+		functionType.TypeArgumentsCheck != nil {
+
+		e.errorf("unsupported function type: %s", functionType)
+	}
+
+	e.writeUint(uint64(encodedTypeTagFunction))
+	e.registerType(functionType)
+
+	e.writeUint(uint64(functionType.Purity))
+
+	// Type parameters are always defined by their function
+	e.writeBool(functionType.TypeParameters != nil)
+	e.writeLength(len(functionType.TypeParameters))
+	for _, typeParameter := range functionType.TypeParameters {
+		e.encodeTypeParameter(typeParameter)
+	}
+
+	e.encodeParameters(functionType.Parameters)
+	e.encodeTypeAnnotation(functionType.ReturnTypeAnnotation)
+
+	arity := functionType.Arity
+	e.writeBool(arity != nil)
+	if arity != nil {
+		e.writeInt(int64(arity.Min))
+		e.writeInt(int64(arity.Max))
+	}
+
+	e.writeBool(functionType.IsConstructor)
+	e.encodeMembers(functionType.Members)
+}
+
+func (e *elaborationEncoder) registerTypeParameter(typeParameter *TypeParameter) {
+	e.typeParameters[typeParameter] = uint64(len(e.typeParameters))
+}
+
+func (e *elaborationEncoder) encodeTypeParameterDefinition(typeParameter *TypeParameter) {
+	e.writeUint(uint64(encodedTypeParameterTagDefinition))
+	e.registerTypeParameter(typeParameter)
+	e.writeString(typeParameter.Name)
+	e.writeBool(typeParameter.Optional)
+	e.writeBool(typeParameter.NonNative)
+	e.encodeType(typeParameter.TypeBound)
+}
+
+func (e *elaborationEncoder) encodeTypeParameter(typeParameter *TypeParameter) {
+	// Type parameters of referenced function types are not registered,
+	// just like the referenced function types themselves
+	if owner, ok := e.typeParameterOwners[typeParameter]; ok {
+		e.writeUint(uint64(encodedTypeParameterTagFunction))
+		e.encodeType(owner.functionType)
+		e.writeLength(owner.index)
+		return
+	}
+
+	if index, ok := e.typeParameters[typeParameter]; ok {
+		e.writeUint(uint64(encodedTypeParameterTagReference))
+		e.writeUint(index)
+		return
+	}
+
+	e.encodeTypeParameterDefinition(typeParameter)
+}
+
+func (e *elaborationEncoder) encodeTypeAnnotation(typeAnnotation TypeAnnotation) {
+	e.writeBool(typeAnnotation.IsResource)
+	e.encodeType(typeAnnotation.Type)
+}
+
+func (e *elaborationEncoder) encodeParameters(parameters []Parameter) {
+	e.writeBool(parameters != nil)
+	e.writeLength(len(parameters))
+	for _, parameter := range parameters {
+		e.writeString(parameter.Label)
+		e.writeString(parameter.Identifier)
+		e.encodeTypeAnnotation(parameter.TypeAnnotation)
+		e.encodeType(parameter.DefaultArgument)
+	}
+}
+
+func (e *elaborationEncoder) encodeNestedTypes(nestedTypes *StringTypeOrderedMap) {
+	e.writeBool(nestedTypes != nil)
+	if nestedTypes == nil {
+		return
+	}
+	e.writeLength(nestedTypes.Len())
+	nestedTypes.Foreach(func(name string, ty Type) {
+		e.writeString(name)
+		e.encodeType(ty)
+	})
+}
+
+func (e *elaborationEncoder) encodeNestedDeclarations(declarations map[string]ast.Declaration) {
+	names := make([]string, 0, len(declarations))
+	for name := range declarations { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	e.writeBool(declarations != nil)
+	e.writeLength(len(names))
+	for _, name := range names {
+		e.writeString(name)
+		e.writeNode(declarations[name])
+	}
+}
+
+func (e *elaborationEncoder) encodeAccess(access Access) {
+	switch access := access.(type) {
+	case nil:
+		e.writeUint(uint64(encodedAccessTagNil))
+
+	case PrimitiveAccess:
+		e.writeUint(uint64(encodedAccessTagPrimitive))
+		e.writeUint(uint64(access))
+
+	case EntitlementSetAccess:
+		e.writeUint(uint64(encodedAccessTagEntitlementSet))
+		e.writeUint(uint64(access.SetKind))
+		e.writeLength(access.Entitlements.Len())
+		access.Entitlements.Foreach(func(entitlementType *EntitlementType, _ struct{}) {
+			e.encodeType(entitlementType)
+		})
+
+	case *EntitlementMapAccess:
+		e.writeUint(uint64(encodedAccessTagEntitlementMap))
+		e.encodeType(access.Type)
+
+	default:
+		e.errorf("unsupported access: %T", access)
+	}
+}
+
+func (e *elaborationEncoder) encodeMember(member *Member) {
+	e.writeIdentifier(member.Identifier)
+	e.encodeTypeAnnotation(member.TypeAnnotation)
+	e.encodeType(member.ContainerType)
+	e.writeString(member.DocString)
+	e.writeStrings(member.ArgumentLabels)
+	e.encodeAccess(member.Access)
+	e.writeUint(uint64(member.DeclarationKind))
+	e.writeUint(uint64(member.VariableKind))
+	e.writeBool(member.Predeclared)
+	e.writeBool(member.HasImplementation)
+	e.writeBool(member.HasConditions)
+	e.writeBool(member.IgnoreInSerialization)
+}
+
+func (e *elaborationEncoder) encodeMembers(members *StringMemberOrderedMap) {
+	e.writeBool(members != nil)
+	if members == nil {
+		return
+	}
+	e.writeLength(members.Len())
+	members.Foreach(func(name string, member *Member) {
+		e.writeString(name)
+		e.encodeMember(member)
+	})
+}
+
+// encodeMemberReference encodes the given member,
+// as a reference to the member of its container type, if possible
+// equivalentResolvedMemberType returns true if the type of the given resolved member
+// is equivalent to the given type.
+// Some members are created when they are resolved, including their type parameters,
+// so the types are compared by ID if they are not equal
+func equivalentResolvedMemberType(resolvedMember *Member, ty Type) bool {
+	resolvedType := resolvedMember.TypeAnnotation.Type
+	return resolvedType.Equal(ty) ||
+		resolvedType.ID() == ty.ID()
+}
+
+func (e *elaborationEncoder) encodeMemberReference(member *Member) {
+	if member == nil {
+		e.writeUint(uint64(encodedMemberTagNil))
+		return
+	}
+
+	identifier := member.Identifier.Identifier
+	containerType := member.ContainerType
+	if containerType != nil {
+		resolvedMember := resolveMember(e.config.MemoryGauge, containerType, identifier)
+		if resolvedMember == member ||
+			(!e.isOwnType(containerType) &&
+				resolvedMember != nil &&
+				equivalentResolvedMemberType(resolvedMember, member.TypeAnnotation.Type)) {
+
+			e.writeUint(uint64(encodedMemberTagReference))
+			e.encodeType(containerType)
+			e.writeString(identifier)
+			return
+		}
+	}
+
+	e.writeUint(uint64(encodedMemberTagDefinition))
+	e.encodeMember(member)
+}
+
+func (e *elaborationEncoder) encodeVariable(variable *Variable) {
+	e.writeString(variable.Identifier)
+	e.encodeType(variable.Type)
+	e.writeBool(variable.Pos != nil)
+	if variable.Pos != nil {
+		e.writePosition(*variable.Pos)
+	}
+	e.writeString(variable.DocString)
+	e.writeStrings(variable.ArgumentLabels)
+	e.writeUint(uint64(variable.DeclarationKind))
+	e.encodeAccess(variable.Access)
+	e.writeInt(int64(variable.ActivationDepth))
+	e.writeBool(variable.IsConstant)
+}
+
+func (e *elaborationEncoder) encodeVariables(variables *StringVariableOrderedMap) {
+	if variables == nil {
+		e.writeLength(0)
+		return
+	}
+	e.writeLength(variables.Len())
+	variables.Foreach(func(name string, variable *Variable) {
+		e.writeString(name)
+		e.encodeVariable(variable)
+	})
+}
@@ -0,0 +1,432 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+)
+
+// Elaboration encoding
+//
+// An elaboration can be encoded into a compact binary form (see EncodeElaboration),
+// so that a checked program can be persisted, e.g. to disk, and reloaded later
+// without checking it again (see DecodeElaboration).
+//
+// The encoding does not contain the program itself. Instead, the program is parsed again,
+// and the elements of the program are identified by their index in a deterministic traversal
+// of the program. The encoding contains the number of elements and a checksum of their kinds,
+// so a program which does not match the encoded elaboration is rejected.
+//
+// The encoding contains the information needed to interpret the program,
+// and to check programs importing it. Information which is only used
+// while checking the program itself, e.g. the expected types of expressions, is not included.
+//
+// Types declared in the program are encoded completely.
+// Built-in types, and types declared in imported programs, are encoded by reference,
+// and are resolved using the base type activation and the imported elaborations.
+//
+// The encoding is a sequence of CBOR data items.
+// It starts with the version of the encoding, ElaborationEncodingVersion,
+// which must be incremented when the encoding changes.
+
+// ElaborationEncodingVersion is the version of the elaboration encoding.
+const ElaborationEncodingVersion uint64 = 1
+
+// ElaborationEncodingConfig is the configuration for encoding and decoding elaborations.
+//
+// Elaborations must be decoded with the same configuration they were encoded with.
+type ElaborationEncodingConfig struct {
+	// Location is the location of the program
+	Location common.Location
+	// MemoryGauge is used to meter the memory used by a decoded elaboration
+	MemoryGauge common.MemoryGauge
+	// BaseValueActivation is the base value activation the program was checked with.
+	// If nil, BaseValueActivation is used
+	BaseValueActivation *VariableActivation
+	// BaseTypeActivation is the base type activation the program was checked with.
+	// If nil, BaseTypeActivation is used
+	BaseTypeActivation *VariableActivation
+	// ImportedElaboration returns the elaboration of the imported program with the given location.
+	// It is used to resolve the types and functions declared in imported programs
+	ImportedElaboration func(location common.Location) (*Elaboration, error)
+}
+
+// ElaborationEncodingError is reported when an elaboration cannot be encoded.
+type ElaborationEncodingError struct {
+	Message string
+}
+
+var _ error = ElaborationEncodingError{}
+
+func (e ElaborationEncodingError) Error() string {
+	return fmt.Sprintf("cannot encode elaboration: %s", e.Message)
+}
+
+// ElaborationDecodingError is reported when an elaboration cannot be decoded.
+type ElaborationDecodingError struct {
+	Message string
+}
+
+var _ error = ElaborationDecodingError{}
+
+func (e ElaborationDecodingError) Error() string {
+	return fmt.Sprintf("cannot decode elaboration: %s", e.Message)
+}
+
+// encodedTypeTag is the tag of an encoded type
+type encodedTypeTag uint64
+
+const (
+	encodedTypeTagNil encodedTypeTag = iota
+	// encodedTypeTagReference refers to a previously encoded type
+	encodedTypeTagReference
+	// encodedTypeTagBuiltin refers to a built-in type by its qualified identifier
+	encodedTypeTagBuiltin
+	encodedTypeTagOptional
+	encodedTypeTagVariableSized
+	encodedTypeTagConstantSized
+	encodedTypeTagDictionary
+	encodedTypeTagReferenceType
+	encodedTypeTagIntersection
+	encodedTypeTagCapability
+	encodedTypeTagInclusiveRange
+	encodedTypeTagGeneric
+	encodedTypeTagFunction
+	// encodedTypeTagMemberFunction refers to the function type of a member
+	encodedTypeTagMemberFunction
+	// encodedTypeTagBaseValueFunction refers to the function type of a base value
+	encodedTypeTagBaseValueFunction
+	// encodedTypeTagImportedFunction refers to the function type of a global value of an imported program
+	encodedTypeTagImportedFunction
+	encodedTypeTagTransaction
+	encodedTypeTagComposite
+	encodedTypeTagInterface
+	encodedTypeTagEntitlement
+	encodedTypeTagEntitlementMap
+	encodedTypeTagImportedComposite
+	encodedTypeTagImportedInterface
+	encodedTypeTagImportedEntitlement
+	encodedTypeTagImportedEntitlementMap
+)
+
+// encodedTypeParameterTag is the tag of an encoded type parameter
+type encodedTypeParameterTag uint64
+
+const (
+	// encodedTypeParameterTagReference refers to a previously encoded type parameter
+	encodedTypeParameterTagReference encodedTypeParameterTag = iota
+	encodedTypeParameterTagDefinition
+	// encodedTypeParameterTagFunction refers to the type parameter of a function type
+	encodedTypeParameterTagFunction
+)
+
+// encodedAccessTag is the tag of an encoded access
+type encodedAccessTag uint64
+
+const (
+	encodedAccessTagNil encodedAccessTag = iota
+	encodedAccessTagPrimitive
+	encodedAccessTagEntitlementSet
+	encodedAccessTagEntitlementMap
+)
+
+// encodedMemberTag is the tag of an encoded member
+type encodedMemberTag uint64
+
+const (
+	encodedMemberTagNil encodedMemberTag = iota
+	// encodedMemberTagReference refers to the member of a type
+	encodedMemberTagReference
+	encodedMemberTagDefinition
+)
+
+var elaborationCBOREncMode = func() cbor.EncMode {
+	encMode, err := cbor.EncOptions{}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return encMode
+}()
+
+var elaborationCBORDecMode = func() cbor.DecMode {
+	decMode, err := cbor.DecOptions{
+		IntDec:           cbor.IntDecConvertNone,
+		MaxArrayElements: math.MaxInt64,
+		MaxMapPairs:      math.MaxInt64,
+		MaxNestedLevels:  math.MaxInt16,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return decMode
+}()
+
+// elaborationNodes assigns indices to the elements of a program,
+// in a deterministic traversal of the program.
+//
+// Besides the elements, the conditions of functions and transactions are indexed,
+// and the elements of rewritten post-conditions, which are created by the checker.
+type elaborationNodes struct {
+	indices        map[any]uint64
+	nodes          []any
+	postConditions []*ast.Conditions
+}
+
+func newElaborationNodes(program *ast.Program) *elaborationNodes {
+	nodes := &elaborationNodes{
+		indices: map[any]uint64{},
+	}
+	nodes.indexElement(program)
+	return nodes
+}
+
+func (n *elaborationNodes) add(node any) bool {
+	if _, ok := n.indices[node]; ok {
+		return false
+	}
+	n.indices[node] = uint64(len(n.nodes))
+	n.nodes = append(n.nodes, node)
+	return true
+}
+
+func (n *elaborationNodes) indexConditions(conditions *ast.Conditions, isPostConditions bool) {
+	if conditions == nil {
+		return
+	}
+	n.add(conditions)
+	if isPostConditions {
+		n.postConditions = append(n.postConditions, conditions)
+	}
+}
+
+func (n *elaborationNodes) indexElement(element ast.Element) {
+
+	// Emit conditions are checked as emit statements
+	if emitCondition, ok := element.(*ast.EmitCondition); ok {
+		element = (*ast.EmitStatement)(emitCondition)
+	}
+
+	if !n.add(element) {
+		return
+	}
+
+	// Some elements do not walk all of their children, so walk them explicitly
+
+	switch element := element.(type) {
+	case *ast.FunctionBlock:
+		n.indexConditions(element.PreConditions, false)
+		n.indexConditions(element.PostConditions, true)
+
+	case *ast.TransactionDeclaration:
+		n.indexConditions(element.PreConditions, false)
+		n.indexConditions(element.PostConditions, true)
+		element.PreConditions.Walk(n.indexElement)
+		element.PostConditions.Walk(n.indexElement)
+
+	case *ast.SpecialFunctionDeclaration:
+		n.indexElement(element.FunctionDeclaration)
+
+	case *ast.FunctionDeclaration:
+		n.indexParameterList(element.ParameterList)
+
+	case *ast.FunctionExpression:
+		n.indexParameterList(element.ParameterList)
+	}
+
+	element.Walk(n.indexElement)
+}
+
+func (n *elaborationNodes) indexParameterList(parameterList *ast.ParameterList) {
+	if parameterList == nil {
+		return
+	}
+	for _, parameter := range parameterList.Parameters {
+		if parameter.DefaultArgument != nil {
+			n.indexElement(parameter.DefaultArgument)
+		}
+	}
+}
+
+// indexRewrite indexes the elements of the given post-conditions rewrite
+// which are not part of the program
+func (n *elaborationNodes) indexRewrite(rewrite PostConditionsRewrite) {
+	for _, statement := range rewrite.BeforeStatements {
+		n.indexElement(statement)
+	}
+	for _, condition := range rewrite.RewrittenPostConditions {
+		n.indexElement(condition)
+	}
+}
+
+// checksum returns a checksum of the kinds of the indexed nodes
+func (n *elaborationNodes) checksum() uint64 {
+	hash := fnv.New64a()
+	var buffer [binary.MaxVarintLen64]byte
+
+	for _, node := range n.nodes {
+		var elementType ast.ElementType
+		if element, ok := node.(ast.Element); ok {
+			elementType = element.ElementType()
+		}
+		length := binary.PutUvarint(buffer[:], uint64(elementType))
+		_, _ = hash.Write(buffer[:length])
+	}
+
+	return hash.Sum64()
+}
+
+// encodedLocationTypeID returns the type ID which is used to encode the given location.
+//
+// Locations can be decoded from type IDs (see common.DecodeTypeID),
+// so a placeholder qualified identifier is used.
+// The name of an address location is encoded as the qualified identifier.
+func encodedLocationTypeID(location common.Location) string {
+	qualifiedIdentifier := "_"
+	if addressLocation, ok := location.(common.AddressLocation); ok {
+		qualifiedIdentifier = addressLocation.Name
+	}
+	return string(location.TypeID(nil, qualifiedIdentifier))
+}
+
+func (config *ElaborationEncodingConfig) baseValueActivation() *VariableActivation {
+	if config.BaseValueActivation != nil {
+		return config.BaseValueActivation
+	}
+	return BaseValueActivation
+}
+
+func (config *ElaborationEncodingConfig) baseTypeActivation() *VariableActivation {
+	if config.BaseTypeActivation != nil {
+		return config.BaseTypeActivation
+	}
+	return BaseTypeActivation
+}
+
+// builtinType returns the built-in type with the given qualified identifier, if any
+func (config *ElaborationEncodingConfig) builtinType(qualifiedIdentifier string) Type {
+	identifiers := strings.Split(qualifiedIdentifier, ".")
+
+	var ty Type
+	variable := config.baseTypeActivation().Find(identifiers[0])
+	if variable != nil {
+		ty = variable.Type
+	} else if entitlementType, ok := BuiltinEntitlements[identifiers[0]]; ok {
+		ty = entitlementType
+	} else if entitlementMapType, ok := BuiltinEntitlementMappings[identifiers[0]]; ok {
+		ty = entitlementMapType
+	} else if compositeType, ok := NativeCompositeTypes[identifiers[0]]; ok {
+		ty = compositeType
+	} else {
+		return nil
+	}
+
+	for _, identifier := range identifiers[1:] {
+		containerType, ok := ty.(ContainerType)
+		if !ok || !containerType.IsContainerType() {
+			return nil
+		}
+
+		nestedTypes := containerType.GetNestedTypes()
+		if nestedTypes == nil {
+			return nil
+		}
+
+		ty, ok = nestedTypes.Get(identifier)
+		if !ok {
+			return nil
+		}
+	}
+
+	return ty
+}
+
+// baseValueFunctionType returns the function type of the base value with the given name, if any
+func (config *ElaborationEncodingConfig) baseValueFunctionType(name string) *FunctionType {
+	variable := config.baseValueActivation().Find(name)
+	if variable == nil {
+		return nil
+	}
+	functionType, _ := variable.Type.(*FunctionType)
+	return functionType
+}
+
+// importedType returns the type with the given type ID and tag,
+// declared in the given imported elaboration, if any
+func importedType(elaboration *Elaboration, tag encodedTypeTag, typeID TypeID) Type {
+	switch tag {
+	case encodedTypeTagImportedComposite:
+		if ty := elaboration.CompositeType(typeID); ty != nil {
+			return ty
+		}
+	case encodedTypeTagImportedInterface:
+		if ty := elaboration.InterfaceType(typeID); ty != nil {
+			return ty
+		}
+	case encodedTypeTagImportedEntitlement:
+		if ty := elaboration.EntitlementType(typeID); ty != nil {
+			return ty
+		}
+	case encodedTypeTagImportedEntitlementMap:
+		if ty := elaboration.EntitlementMapType(typeID); ty != nil {
+			return ty
+		}
+	}
+	return nil
+}
+
+// resolveMember resolves the member with the given identifier of the given type, if any
+func resolveMember(memoryGauge common.MemoryGauge, ty Type, identifier string) *Member {
+	switch ty := ty.(type) {
+	case *CompositeType:
+		if member, ok := ty.Members.Get(identifier); ok {
+			return member
+		}
+	case *InterfaceType:
+		if member, ok := ty.Members.Get(identifier); ok {
+			return member
+		}
+	case *TransactionType:
+		if member, ok := ty.Members.Get(identifier); ok {
+			return member
+		}
+	}
+
+	resolver, ok := ty.GetMembers()[identifier]
+	if !ok {
+		return nil
+	}
+
+	return resolver.Resolve(
+		memoryGauge,
+		identifier,
+		ast.EmptyRange,
+		func(error) {
+			// ignored, the program was already checked
+		},
+	)
+}
@@ -0,0 +1,479 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/sema"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+	. "github.com/onflow/cadence/test_utils/sema_utils"
+)
+
+// testElaborationRoundTrip checks the given program, encodes its elaboration,
+// decodes it for a newly parsed program, and ensures that encoding the decoded elaboration
+// results in the same encoding
+func testElaborationRoundTrip(
+	t *testing.T,
+	code string,
+	options ParseAndCheckOptions,
+	config *sema.ElaborationEncodingConfig,
+) (*sema.Checker, *ast.Program, *sema.Elaboration) {
+
+	checker, err := ParseAndCheckWithOptions(t, code, options)
+	require.NoError(t, err)
+
+	encoded, err := sema.EncodeElaboration(checker.Program, checker.Elaboration, config)
+	require.NoError(t, err)
+
+	program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+	require.NoError(t, err)
+
+	decoded, err := sema.DecodeElaboration(encoded, program, config)
+	require.NoError(t, err)
+
+	reencoded, err := sema.EncodeElaboration(program, decoded, config)
+	require.NoError(t, err)
+
+	assert.Equal(t, encoded, reencoded)
+
+	return checker, program, decoded
+}
+
+func TestElaborationEncodingRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(name string, code string) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testElaborationRoundTrip(
+				t,
+				code,
+				ParseAndCheckOptions{},
+				&sema.ElaborationEncodingConfig{
+					Location: TestLocation,
+				},
+			)
+		})
+	}
+
+	test("composites and interfaces", `
+      access(all) struct interface HasID {
+          access(all) let id: UInt64
+
+          access(all) fun describe(): String {
+              return "ID: ".concat(self.id.toString())
+          }
+      }
+
+      access(all) struct S: HasID {
+          access(all) let id: UInt64
+          access(all) var children: {String: [S]}
+
+          init(id: UInt64) {
+              self.id = id
+              self.children = {}
+          }
+
+          access(all) fun add(_ key: String, _ child: S) {
+              if self.children[key] == nil {
+                  self.children[key] = []
+              }
+              self.children[key]!.append(child)
+          }
+      }
+
+      access(all) resource R {
+          access(all) let s: S
+
+          init() {
+              self.s = S(id: 1)
+          }
+      }
+
+      access(all) fun test(): String {
+          let s = S(id: 2)
+          s.add("a", S(id: 3))
+          let r <- create R()
+          let description = r.s.describe()
+          destroy r
+          return description.concat(s.describe())
+      }
+    `)
+
+	test("enums and switch", `
+      access(all) enum Color: UInt8 {
+          access(all) case red
+          access(all) case green
+      }
+
+      access(all) fun test(_ color: Color): Int {
+          switch color {
+          case Color.red:
+              return 1
+          default:
+              return Color(rawValue: 1)!.rawValue == color.rawValue ? 2 : 3
+          }
+      }
+    `)
+
+	test("events", `
+      access(all) event Created(id: UInt64, tags: [String])
+
+      access(all) resource R {
+          access(all) event ResourceDestroyed(id: UInt64 = self.id)
+
+          access(all) let id: UInt64
+
+          init(id: UInt64) {
+              self.id = id
+              emit Created(id: id, tags: ["a"])
+          }
+      }
+    `)
+
+	test("entitlements and references", `
+      access(all) entitlement E
+      access(all) entitlement F
+      access(all) entitlement X
+      access(all) entitlement Y
+
+      access(all) entitlement mapping M {
+          E -> X
+          F -> Y
+          include Identity
+      }
+
+      access(all) struct Inner {
+          access(X) fun onlyX(): Int {
+              return 1
+          }
+      }
+
+      access(all) struct S {
+          access(mapping M) let inner: Inner
+
+          access(E) fun onlyE() {}
+
+          access(E | F) fun eOrF() {}
+
+          init() {
+              self.inner = Inner()
+          }
+      }
+
+      access(all) fun test(): Int {
+          let s = S()
+          let ref = &s as auth(E, F) &S
+          ref.onlyE()
+          ref.eOrF()
+          let inner: auth(X, Y) &Inner = ref.inner
+          return inner.onlyX()
+      }
+    `)
+
+	test("attachments", `
+      access(all) resource R {}
+
+      access(all) attachment A for R {
+          access(all) let x: Int
+
+          init(x: Int) {
+              self.x = x
+          }
+
+          access(all) fun getX(): Int {
+              return self.x + base.uuid.getType().identifier.length
+          }
+      }
+
+      access(all) fun test(): Int {
+          let r <- attach A(x: 1) to <-create R()
+          let x = r[A]!.getX()
+          remove A from r
+          destroy r
+          return x
+      }
+    `)
+
+	test("conditions", `
+      access(all) struct interface I {
+          access(all) fun test(_ x: Int): Int {
+              pre { x > 0: "positive" }
+              post { result > before(x) }
+          }
+      }
+
+      access(all) struct S: I {
+          access(all) fun test(_ x: Int): Int {
+              post {
+                  result == before(x) + 1
+                  emit Tested(x: x)
+              }
+              return x + 1
+          }
+      }
+
+      access(all) event Tested(x: Int)
+
+      access(all) fun test(_ values: [Int]): [Int] {
+          post { result.length == before(values.length) }
+          return values
+      }
+    `)
+
+	test("statements and expressions", `
+      access(all) fun test(): Int {
+          var sum = 0
+          for i, value in [1, 2, 3] {
+              sum = sum + i * value
+          }
+          var a = 1
+          var b = 2
+          a <-> b
+          let dict: {String: Int} = {"a": 1}
+          let x: Fix64 = -1.5
+          let s = "\(sum)"
+          let y = (dict["a"] ?? 0) as! Int
+          let z = (x as AnyStruct) as? Fix64
+          let c = [1, 2, 3].map(fun (_ v: Int): Int { return v * 2 })
+          let d = [1, 2, 3].toConstantSized<[Int; 3]>()
+          let path = /storage/foo
+          while a > 0 {
+              a = a - 1
+              if a == 1 { break } else { continue }
+          }
+          return sum + y + c.length + s.length + path.toString().length
+      }
+    `)
+
+	test("transaction", `
+      transaction(amount: Int) {
+          let value: Int
+
+          prepare(signer: &Account) {
+              self.value = amount
+          }
+
+          pre { amount > 0 }
+
+          execute {
+              let x = self.value
+          }
+
+          post { self.value == amount }
+      }
+    `)
+}
+
+func TestElaborationEncodingDecoded(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      access(all) struct S {
+          access(all) let x: Int
+
+          init(x: Int) {
+              self.x = x
+          }
+
+          access(all) fun double(): Int {
+              return self.x * 2
+          }
+      }
+
+      access(all) let s = S(x: 1)
+    `
+
+	checker, _, decoded := testElaborationRoundTrip(
+		t,
+		code,
+		ParseAndCheckOptions{},
+		&sema.ElaborationEncodingConfig{
+			Location: TestLocation,
+		},
+	)
+
+	typeID := TestLocation.TypeID(nil, "S")
+
+	compositeType := decoded.CompositeType(typeID)
+	require.NotNil(t, compositeType)
+	assert.NotSame(t, checker.Elaboration.CompositeType(typeID), compositeType)
+	assert.True(t, compositeType.Equal(checker.Elaboration.CompositeType(typeID)))
+
+	member, ok := compositeType.Members.Get("double")
+	require.True(t, ok)
+	assert.Same(t, compositeType, member.ContainerType)
+	assert.Equal(t,
+		"fun(): Int",
+		member.TypeAnnotation.Type.String(),
+	)
+
+	variable, ok := decoded.GetGlobalValue("s")
+	require.True(t, ok)
+	assert.Same(t, compositeType, variable.Type)
+}
+
+func TestElaborationEncodingImport(t *testing.T) {
+
+	t.Parallel()
+
+	importedLocation := common.StringLocation("imported")
+
+	importedChecker, err := ParseAndCheckWithOptions(t,
+		`
+          access(all) struct S {
+              access(all) fun answer(): Int {
+                  return 42
+              }
+          }
+
+          access(all) fun newS(): S {
+              return S()
+          }
+        `,
+		ParseAndCheckOptions{
+			Location: importedLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	_, _, decoded := testElaborationRoundTrip(
+		t,
+		`
+          import S, newS from "imported"
+
+          access(all) let s: S = newS()
+
+          access(all) let f = newS
+
+          access(all) fun test(): Int {
+              let s: S = newS()
+              return s.answer()
+          }
+        `,
+		ParseAndCheckOptions{
+			Config: &sema.Config{
+				ImportHandler: func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+					return sema.ElaborationImport{
+						Elaboration: importedChecker.Elaboration,
+					}, nil
+				},
+			},
+		},
+		&sema.ElaborationEncodingConfig{
+			Location: TestLocation,
+			ImportedElaboration: func(location common.Location) (*sema.Elaboration, error) {
+				require.Equal(t, importedLocation, location)
+				return importedChecker.Elaboration, nil
+			},
+		},
+	)
+
+	// Imported types are not encoded, but refer to the imported elaboration
+
+	importedType := importedChecker.Elaboration.CompositeType(importedLocation.TypeID(nil, "S"))
+	require.NotNil(t, importedType)
+
+	variable, ok := decoded.GetGlobalValue("s")
+	require.True(t, ok)
+	assert.Same(t, importedType, variable.Type)
+
+	importedVariable, ok := importedChecker.Elaboration.GetGlobalValue("newS")
+	require.True(t, ok)
+
+	variable, ok = decoded.GetGlobalValue("f")
+	require.True(t, ok)
+	assert.Same(t, importedVariable.Type, variable.Type)
+}
+
+func TestElaborationEncodingInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	config := &sema.ElaborationEncodingConfig{
+		Location: TestLocation,
+	}
+
+	const code = `
+      access(all) fun test(): Int {
+          return 1
+      }
+    `
+
+	checker, err := ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	encoded, err := sema.EncodeElaboration(checker.Program, checker.Elaboration, config)
+	require.NoError(t, err)
+
+	t.Run("different program", func(t *testing.T) {
+		t.Parallel()
+
+		program, err := parser.ParseProgram(
+			nil,
+			[]byte(`
+              access(all) fun test(): Int {
+                  return 1 + 2
+              }
+            `),
+			parser.Config{},
+		)
+		require.NoError(t, err)
+
+		_, err = sema.DecodeElaboration(encoded, program, config)
+		var decodingErr sema.ElaborationDecodingError
+		require.ErrorAs(t, err, &decodingErr)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+		require.NoError(t, err)
+
+		data := append([]byte{}, encoded...)
+		// The version is the first data item, a small unsigned integer
+		data[0]++
+
+		_, err = sema.DecodeElaboration(data, program, config)
+		var decodingErr sema.ElaborationDecodingError
+		require.ErrorAs(t, err, &decodingErr)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		program, err := parser.ParseProgram(nil, []byte(code), parser.Config{})
+		require.NoError(t, err)
+
+		_, err = sema.DecodeElaboration(encoded[:len(encoded)/2], program, config)
+		var decodingErr sema.ElaborationDecodingError
+		require.ErrorAs(t, err, &decodingErr)
+	})
+}
@@ -0,0 +1,1633 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/errors"
+)
+
+// Error codes of the checker errors.
+//
+// Error codes are stable: Once assigned, an error code must never change,
+// and must not be reused, even if the error is removed.
+// New errors must be assigned the next unassigned error code.
+const (
+	ErrorCodeInvalidPragma                                         errors.ErrorCode = "E2001"
+	ErrorCodeRedeclaration                                         errors.ErrorCode = "E2002"
+	ErrorCodeNotDeclared                                           errors.ErrorCode = "E2003"
+	ErrorCodeAssignmentToConstant                                  errors.ErrorCode = "E2004"
+	ErrorCodeTypeMismatch                                          errors.ErrorCode = "E2005"
+	ErrorCodeTypeMismatchWithDescription                           errors.ErrorCode = "E2006"
+	ErrorCodeNotIndexableType                                      errors.ErrorCode = "E2007"
+	ErrorCodeNotIndexingAssignableType                             errors.ErrorCode = "E2008"
+	ErrorCodeNotEquatableType                                      errors.ErrorCode = "E2009"
+	ErrorCodeNotCallable                                           errors.ErrorCode = "E2010"
+	ErrorCodeInsufficientArguments                                 errors.ErrorCode = "E2011"
+	ErrorCodeExcessiveArguments                                    errors.ErrorCode = "E2012"
+	ErrorCodeMissingArgumentLabel                                  errors.ErrorCode = "E2013"
+	ErrorCodeIncorrectArgumentLabel                                errors.ErrorCode = "E2014"
+	ErrorCodeInvalidUnaryOperand                                   errors.ErrorCode = "E2015"
+	ErrorCodeInvalidBinaryOperand                                  errors.ErrorCode = "E2016"
+	ErrorCodeInvalidBinaryOperands                                 errors.ErrorCode = "E2017"
+	ErrorCodeControlStatement                                      errors.ErrorCode = "E2018"
+	ErrorCodeInvalidAccessModifier                                 errors.ErrorCode = "E2019"
+	ErrorCodeMissingAccessModifier                                 errors.ErrorCode = "E2020"
+	ErrorCodeInvalidStaticModifier                                 errors.ErrorCode = "E2021"
+	ErrorCodeInvalidNativeModifier                                 errors.ErrorCode = "E2022"
+	ErrorCodeNativeFunctionWithImplementation                      errors.ErrorCode = "E2023"
+	ErrorCodeInvalidName                                           errors.ErrorCode = "E2024"
+	ErrorCodeUnknownSpecialFunction                                errors.ErrorCode = "E2025"
+	ErrorCodeInvalidVariableKind                                   errors.ErrorCode = "E2026"
+	ErrorCodeInvalidDeclaration                                    errors.ErrorCode = "E2027"
+	ErrorCodeMissingInitializer                                    errors.ErrorCode = "E2028"
+	ErrorCodeNotDeclaredMember                                     errors.ErrorCode = "E2029"
+	ErrorCodeAssignmentToConstantMember                            errors.ErrorCode = "E2030"
+	ErrorCodeFieldReinitialization                                 errors.ErrorCode = "E2031"
+	ErrorCodeFieldUninitialized                                    errors.ErrorCode = "E2032"
+	ErrorCodeFieldTypeNotStorable                                  errors.ErrorCode = "E2033"
+	ErrorCodeFunctionExpressionInCondition                         errors.ErrorCode = "E2034"
+	ErrorCodeInvalidEmitCondition                                  errors.ErrorCode = "E2035"
+	ErrorCodeMissingReturnValue                                    errors.ErrorCode = "E2036"
+	ErrorCodeInvalidImplementation                                 errors.ErrorCode = "E2037"
+	ErrorCodeInvalidConformance                                    errors.ErrorCode = "E2038"
+	ErrorCodeInvalidEnumRawType                                    errors.ErrorCode = "E2039"
+	ErrorCodeMissingEnumRawType                                    errors.ErrorCode = "E2040"
+	ErrorCodeInvalidEnumConformances                               errors.ErrorCode = "E2041"
+	ErrorCodeInvalidAttachmentConformances                         errors.ErrorCode = "E2042"
+	ErrorCodeConformance                                           errors.ErrorCode = "E2043"
+	ErrorCodeDuplicateConformance                                  errors.ErrorCode = "E2044"
+	ErrorCodeCyclicConformance                                     errors.ErrorCode = "E2045"
+	ErrorCodeMultipleInterfaceDefaultImplementations               errors.ErrorCode = "E2046"
+	ErrorCodeSpecialFunctionDefaultImplementation                  errors.ErrorCode = "E2047"
+	ErrorCodeInterfaceMemberConflict                               errors.ErrorCode = "E2048"
+	ErrorCodeMissingConformance                                    errors.ErrorCode = "E2049"
+	ErrorCodeUnresolvedImport                                      errors.ErrorCode = "E2050"
+	ErrorCodeNotExported                                           errors.ErrorCode = "E2051"
+	ErrorCodeAlwaysFailingNonResourceCastingType                   errors.ErrorCode = "E2052"
+	ErrorCodeAlwaysFailingResourceCastingType                      errors.ErrorCode = "E2053"
+	ErrorCodeUnsupportedOverloading                                errors.ErrorCode = "E2054"
+	ErrorCodeCompositeKindMismatch                                 errors.ErrorCode = "E2055"
+	ErrorCodeInvalidIntegerLiteralRange                            errors.ErrorCode = "E2056"
+	ErrorCodeInvalidAddressLiteral                                 errors.ErrorCode = "E2057"
+	ErrorCodeInvalidFixedPointLiteralRange                         errors.ErrorCode = "E2058"
+	ErrorCodeInvalidFixedPointLiteralScale                         errors.ErrorCode = "E2059"
+	ErrorCodeMissingReturnStatement                                errors.ErrorCode = "E2060"
+	ErrorCodeUnsupportedOptionalChainingAssignment                 errors.ErrorCode = "E2061"
+	ErrorCodeMissingResourceAnnotation                             errors.ErrorCode = "E2062"
+	ErrorCodeInvalidNestedResourceMove                             errors.ErrorCode = "E2063"
+	ErrorCodeInvalidInterfaceConditionResourceInvalidation         errors.ErrorCode = "E2064"
+	ErrorCodeInvalidResourceAnnotation                             errors.ErrorCode = "E2065"
+	ErrorCodeInvalidInterfaceType                                  errors.ErrorCode = "E2066"
+	ErrorCodeInvalidInterfaceDeclaration                           errors.ErrorCode = "E2067"
+	ErrorCodeIncorrectTransferOperation                            errors.ErrorCode = "E2068"
+	ErrorCodeInvalidConstruction                                   errors.ErrorCode = "E2069"
+	ErrorCodeInvalidDestruction                                    errors.ErrorCode = "E2070"
+	ErrorCodeResourceLoss                                          errors.ErrorCode = "E2071"
+	ErrorCodeResourceUseAfterInvalidation                          errors.ErrorCode = "E2072"
+	ErrorCodeMissingCreate                                         errors.ErrorCode = "E2073"
+	ErrorCodeMissingMoveOperation                                  errors.ErrorCode = "E2074"
+	ErrorCodeInvalidMoveOperation                                  errors.ErrorCode = "E2075"
+	ErrorCodeResourceCapturing                                     errors.ErrorCode = "E2076"
+	ErrorCodeInvalidResourceField                                  errors.ErrorCode = "E2077"
+	ErrorCodeInvalidSwapExpression                                 errors.ErrorCode = "E2078"
+	ErrorCodeInvalidEventParameterType                             errors.ErrorCode = "E2079"
+	ErrorCodeInvalidEventUsage                                     errors.ErrorCode = "E2080"
+	ErrorCodeEmitNonEvent                                          errors.ErrorCode = "E2081"
+	ErrorCodeEmitDefaultDestroyEvent                               errors.ErrorCode = "E2082"
+	ErrorCodeEmitImportedEvent                                     errors.ErrorCode = "E2083"
+	ErrorCodeInvalidResourceAssignment                             errors.ErrorCode = "E2084"
+	ErrorCodeResourceFieldNotInvalidated                           errors.ErrorCode = "E2085"
+	ErrorCodeUninitializedFieldAccess                              errors.ErrorCode = "E2086"
+	ErrorCodeUnreachableStatement                                  errors.ErrorCode = "E2087"
+	ErrorCodeUninitializedUse                                      errors.ErrorCode = "E2088"
+	ErrorCodeInvalidResourceArrayMember                            errors.ErrorCode = "E2089"
+	ErrorCodeInvalidResourceDictionaryMember                       errors.ErrorCode = "E2090"
+	ErrorCodeInvalidResourceOptionalMember                         errors.ErrorCode = "E2091"
+	ErrorCodeNonReferenceTypeReference                             errors.ErrorCode = "E2092"
+	ErrorCodeReferenceToAnOptional                                 errors.ErrorCode = "E2093"
+	ErrorCodeInvalidResourceCreation                               errors.ErrorCode = "E2094"
+	ErrorCodeNonResourceType                                       errors.ErrorCode = "E2095"
+	ErrorCodeInvalidAssignmentTarget                               errors.ErrorCode = "E2096"
+	ErrorCodeResourceMethodBinding                                 errors.ErrorCode = "E2097"
+	ErrorCodeInvalidDictionaryKeyType                              errors.ErrorCode = "E2098"
+	ErrorCodeMissingFunctionBody                                   errors.ErrorCode = "E2099"
+	ErrorCodeInvalidOptionalChaining                               errors.ErrorCode = "E2100"
+	ErrorCodeInvalidAccess                                         errors.ErrorCode = "E2101"
+	ErrorCodeInvalidAssignmentAccess                               errors.ErrorCode = "E2102"
+	ErrorCodeUnauthorizedReferenceAssignment                       errors.ErrorCode = "E2103"
+	ErrorCodeInvalidCharacterLiteral                               errors.ErrorCode = "E2104"
+	ErrorCodeInvalidFailableResourceDowncastOutsideOptionalBinding errors.ErrorCode = "E2105"
+	ErrorCodeInvalidNonIdentifierFailableResourceDowncast          errors.ErrorCode = "E2106"
+	ErrorCodeReadOnlyTargetAssignment                              errors.ErrorCode = "E2107"
+	ErrorCodeInvalidTransactionBlock                               errors.ErrorCode = "E2108"
+	ErrorCodeTransactionMissingPrepare                             errors.ErrorCode = "E2109"
+	ErrorCodeInvalidResourceTransactionParameter                   errors.ErrorCode = "E2110"
+	ErrorCodeInvalidNonImportableTransactionParameterType          errors.ErrorCode = "E2111"
+	ErrorCodeInvalidTransactionFieldAccessModifier                 errors.ErrorCode = "E2112"
+	ErrorCodeInvalidTransactionPrepareParameterType                errors.ErrorCode = "E2113"
+	ErrorCodeInvalidNestedDeclaration                              errors.ErrorCode = "E2114"
+	ErrorCodeInvalidNestedType                                     errors.ErrorCode = "E2115"
+	ErrorCodeInvalidEnumCase                                       errors.ErrorCode = "E2116"
+	ErrorCodeInvalidNonEnumCase                                    errors.ErrorCode = "E2117"
+	ErrorCodeDeclarationKindMismatch                               errors.ErrorCode = "E2118"
+	ErrorCodeInvalidTopLevelDeclaration                            errors.ErrorCode = "E2119"
+	ErrorCodeInvalidSelfInvalidation                               errors.ErrorCode = "E2120"
+	ErrorCodeInvalidMove                                           errors.ErrorCode = "E2121"
+	ErrorCodeConstantSizedArrayLiteralSize                         errors.ErrorCode = "E2122"
+	ErrorCodeInvalidIntersectedType                                errors.ErrorCode = "E2123"
+	ErrorCodeIntersectionCompositeKindMismatch                     errors.ErrorCode = "E2124"
+	ErrorCodeInvalidIntersectionTypeDuplicate                      errors.ErrorCode = "E2125"
+	ErrorCodeIntersectionMemberClash                               errors.ErrorCode = "E2126"
+	ErrorCodeAmbiguousIntersectionType                             errors.ErrorCode = "E2127"
+	ErrorCodeInvalidPathDomain                                     errors.ErrorCode = "E2128"
+	ErrorCodeInvalidTypeArgumentCount                              errors.ErrorCode = "E2129"
+	ErrorCodeMissingTypeArgument                                   errors.ErrorCode = "E2130"
+	ErrorCodeInvalidTypeArgument                                   errors.ErrorCode = "E2131"
+	ErrorCodeTypeParameterTypeInference                            errors.ErrorCode = "E2132"
+	ErrorCodeInvalidConstantSizedTypeBase                          errors.ErrorCode = "E2133"
+	ErrorCodeInvalidConstantSizedTypeSize                          errors.ErrorCode = "E2134"
+	ErrorCodeUnsupportedResourceForLoop                            errors.ErrorCode = "E2135"
+	ErrorCodeTypeParameterTypeMismatch                             errors.ErrorCode = "E2136"
+	ErrorCodeUnparameterizedTypeInstantiation                      errors.ErrorCode = "E2137"
+	ErrorCodeTypeAnnotationRequired                                errors.ErrorCode = "E2138"
+	ErrorCodeCyclicImports                                         errors.ErrorCode = "E2139"
+	ErrorCodeSwitchDefaultPosition                                 errors.ErrorCode = "E2140"
+	ErrorCodeMissingSwitchCaseStatements                           errors.ErrorCode = "E2141"
+	ErrorCodeMissingEntryPoint                                     errors.ErrorCode = "E2142"
+	ErrorCodeInvalidEntryPointType                                 errors.ErrorCode = "E2143"
+	ErrorCodePurity                                                errors.ErrorCode = "E2144"
+	ErrorCodeInvalidatedResourceReference                          errors.ErrorCode = "E2145"
+	ErrorCodeInvalidEntitlementAccess                              errors.ErrorCode = "E2146"
+	ErrorCodeInvalidEntitlementMappingType                         errors.ErrorCode = "E2147"
+	ErrorCodeInvalidNonEntitlementTypeInMap                        errors.ErrorCode = "E2148"
+	ErrorCodeInvalidMappingAccess                                  errors.ErrorCode = "E2149"
+	ErrorCodeInvalidMappingAccessMemberType                        errors.ErrorCode = "E2150"
+	ErrorCodeInvalidNonEntitlementAccess                           errors.ErrorCode = "E2151"
+	ErrorCodeMappingAccessMissingKeyword                           errors.ErrorCode = "E2152"
+	ErrorCodeDirectEntitlementAnnotation                           errors.ErrorCode = "E2153"
+	ErrorCodeUnrepresentableEntitlementMapOutput                   errors.ErrorCode = "E2154"
+	ErrorCodeInvalidEntitlementMappingInclusion                    errors.ErrorCode = "E2155"
+	ErrorCodeDuplicateEntitlementMappingInclusion                  errors.ErrorCode = "E2156"
+	ErrorCodeCyclicEntitlementMapping                              errors.ErrorCode = "E2157"
+	ErrorCodeInvalidBaseType                                       errors.ErrorCode = "E2158"
+	ErrorCodeInvalidAttachmentAnnotation                           errors.ErrorCode = "E2159"
+	ErrorCodeInvalidAttachmentUsage                                errors.ErrorCode = "E2160"
+	ErrorCodeAttachNonAttachment                                   errors.ErrorCode = "E2161"
+	ErrorCodeAttachToInvalidType                                   errors.ErrorCode = "E2162"
+	ErrorCodeInvalidAttachmentRemove                               errors.ErrorCode = "E2163"
+	ErrorCodeInvalidTypeIndexing                                   errors.ErrorCode = "E2164"
+	ErrorCodeInvalidAttachmentEntitlement                          errors.ErrorCode = "E2165"
+	ErrorCodeDefaultDestroyEventInNonResource                      errors.ErrorCode = "E2166"
+	ErrorCodeDefaultDestroyInvalidArgument                         errors.ErrorCode = "E2167"
+	ErrorCodeDefaultDestroyInvalidParameter                        errors.ErrorCode = "E2168"
+	ErrorCodeNestedReference                                       errors.ErrorCode = "E2169"
+	ErrorCodeResultVariableConflict                                errors.ErrorCode = "E2170"
+	ErrorCodeInvocationTypeInference                               errors.ErrorCode = "E2171"
+	ErrorCodeUnconvertableType                                     errors.ErrorCode = "E2172"
+	ErrorCodeInvalidMappingAuthorization                           errors.ErrorCode = "E2173"
+	ErrorCodeInvalidTypeParameterizedNonNativeFunction             errors.ErrorCode = "E2174"
+)
+
+// ErrorCodes is the catalog of the error codes of the checker errors
+var ErrorCodes = []errors.ErrorCodeInfo{
+	{
+		Code:        ErrorCodeInvalidPragma,
+		Name:        "InvalidPragmaError",
+		Explanation: "A pragma declaration is malformed, for example because it is not an identifier or an invocation of an identifier with string arguments.",
+		Example: `
+#"pragma"
+`,
+	},
+	{
+		Code:        ErrorCodeRedeclaration,
+		Name:        "RedeclarationError",
+		Explanation: "A declaration has the same name as another declaration in the same scope. Rename one of the declarations.",
+		Example: `
+let x = 1
+let x = 2
+`,
+	},
+	{
+		Code:        ErrorCodeNotDeclared,
+		Name:        "NotDeclaredError",
+		Explanation: "A name is used which is not declared in the current scope. Check the spelling, or declare or import it.",
+		Example: `
+let x = y
+`,
+	},
+	{
+		Code:        ErrorCodeAssignmentToConstant,
+		Name:        "AssignmentToConstantError",
+		Explanation: "A constant, declared with `let`, is assigned to. Declare the variable with `var` if it should be mutable.",
+		Example: `
+fun test() {
+    let x = 1
+    x = 2
+}
+`,
+	},
+	{
+		Code:        ErrorCodeTypeMismatch,
+		Name:        "TypeMismatchError",
+		Explanation: "The type of a value is not a subtype of the type expected in the context, for example the declared type of a variable or parameter.",
+		Example: `
+let x: Int = "hello"
+`,
+	},
+	{
+		Code:        ErrorCodeTypeMismatchWithDescription,
+		Name:        "TypeMismatchWithDescriptionError",
+		Explanation: "The type of a value is not one of the types expected in the context, for example an integer type is expected.",
+		Example: `
+fun test() {
+    for x in 1 {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeNotIndexableType,
+		Name:        "NotIndexableTypeError",
+		Explanation: "A value is indexed, but its type does not support indexing.",
+		Example: `
+let x = 1
+let y = x[0]
+`,
+	},
+	{
+		Code:        ErrorCodeNotIndexingAssignableType,
+		Name:        "NotIndexingAssignableTypeError",
+		Explanation: "A value is assigned to an index, but its type does not support index assignment.",
+		Example: `
+fun test() {
+    let x = "abc"
+    x[0] = "d"
+}
+`,
+	},
+	{
+		Code:        ErrorCodeNotEquatableType,
+		Name:        "NotEquatableTypeError",
+		Explanation: "Values are compared for equality, but their types do not support equality comparison.",
+		Example: `
+fun test(): Int? {
+    let x = [[fun() {}]]
+    return x.firstIndex(of: [fun() {}])
+}
+`,
+	},
+	{
+		Code:        ErrorCodeNotCallable,
+		Name:        "NotCallableError",
+		Explanation: "A value is called, but it is not a function.",
+		Example: `
+let x = 1
+let y = x()
+`,
+	},
+	{
+		Code:        ErrorCodeInsufficientArguments,
+		Name:        "InsufficientArgumentsError",
+		Explanation: "A function is called with fewer arguments than it has parameters.",
+		Example: `
+fun f(_ a: Int) {}
+let x = f()
+`,
+	},
+	{
+		Code:        ErrorCodeExcessiveArguments,
+		Name:        "ExcessiveArgumentsError",
+		Explanation: "A function is called with more arguments than it has parameters.",
+		Example: `
+fun f() {}
+let x = f(1)
+`,
+	},
+	{
+		Code:        ErrorCodeMissingArgumentLabel,
+		Name:        "MissingArgumentLabelError",
+		Explanation: "An argument is passed without the argument label which is required by the function's parameter.",
+		Example: `
+fun f(a: Int) {}
+let x = f(1)
+`,
+	},
+	{
+		Code:        ErrorCodeIncorrectArgumentLabel,
+		Name:        "IncorrectArgumentLabelError",
+		Explanation: "An argument is passed with an argument label which does not match the argument label of the function's parameter.",
+		Example: `
+fun f(a: Int) {}
+let x = f(b: 1)
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidUnaryOperand,
+		Name:        "InvalidUnaryOperandError",
+		Explanation: "A unary operator is applied to a value which has a type that is not supported by the operator.",
+		Example: `
+let x = !1
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidBinaryOperand,
+		Name:        "InvalidBinaryOperandError",
+		Explanation: "A binary operator is applied to an operand which has a type that is not supported by the operator.",
+		Example: `
+let x = 1 && true
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidBinaryOperands,
+		Name:        "InvalidBinaryOperandsError",
+		Explanation: "A binary operator is applied to operands which have types that are not supported by the operator in combination, for example integers of different types.",
+		Example: `
+let x = (1 as UInt8) + (1 as Int8)
+`,
+	},
+	{
+		Code:        ErrorCodeControlStatement,
+		Name:        "ControlStatementError",
+		Explanation: "A control statement, like `break` or `continue`, is used outside of a loop.",
+		Example: `
+fun test() {
+    break
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidAccessModifier,
+		Name:        "InvalidAccessModifierError",
+		Explanation: "An access modifier is used for a declaration which does not support it, or an access modifier is less restrictive than allowed.",
+		Example: `
+access(self) event E()
+`,
+	},
+	{
+		Code:        ErrorCodeMissingAccessModifier,
+		Name:        "MissingAccessModifierError",
+		Explanation: "A declaration is missing an access modifier, which is required in this context, for example for the members of composite types.",
+		Example: `
+struct S {
+    fun test() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidStaticModifier,
+		Name:        "InvalidStaticModifierError",
+		Explanation: "The `static` modifier is used for a declaration which does not support it.",
+	},
+	{
+		Code:        ErrorCodeInvalidNativeModifier,
+		Name:        "InvalidNativeModifierError",
+		Explanation: "The `native` modifier is used for a declaration which does not support it.",
+	},
+	{
+		Code:        ErrorCodeNativeFunctionWithImplementation,
+		Name:        "NativeFunctionWithImplementationError",
+		Explanation: "A function declared with the `native` modifier has an implementation. Native functions are implemented by the host environment.",
+	},
+	{
+		Code:        ErrorCodeInvalidName,
+		Name:        "InvalidNameError",
+		Explanation: "A declaration uses a name which is not allowed, for example a reserved name.",
+		Example: `
+struct interface I {
+    access(all) let init: Int
+}
+`,
+	},
+	{
+		Code:        ErrorCodeUnknownSpecialFunction,
+		Name:        "UnknownSpecialFunctionError",
+		Explanation: "A composite declares an unknown special function. Special functions are `init` and `prepare`, all other functions must be declared with the `fun` keyword.",
+		Example: `
+struct S {
+    foo() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidVariableKind,
+		Name:        "InvalidVariableKindError",
+		Explanation: "A declaration uses a variable kind which is not allowed in this context, or the variable kind is missing, for example in a field declaration.",
+		Example: `
+struct S {
+    access(all) x: Int
+    init() {
+        self.x = 1
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidDeclaration,
+		Name:        "InvalidDeclarationError",
+		Explanation: "A declaration is not allowed in the current context, for example a composite declaration inside a function.",
+		Example: `
+fun test() {
+    struct S {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingInitializer,
+		Name:        "MissingInitializerError",
+		Explanation: "A composite declares fields, but has no initializer which initializes them.",
+		Example: `
+struct S {
+    access(all) let x: Int
+}
+`,
+	},
+	{
+		Code:        ErrorCodeNotDeclaredMember,
+		Name:        "NotDeclaredMemberError",
+		Explanation: "A member is accessed which is not declared by the type of the accessed value.",
+		Example: `
+struct S {}
+let x = S().y
+`,
+	},
+	{
+		Code:        ErrorCodeAssignmentToConstantMember,
+		Name:        "AssignmentToConstantMemberError",
+		Explanation: "A constant field, declared with `let`, is assigned to outside of the initializer. Declare the field with `var` if it should be mutable.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {
+        self.x = 1
+    }
+    access(all) fun test() {
+        self.x = 2
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeFieldReinitialization,
+		Name:        "FieldReinitializationError",
+		Explanation: "A constant field is initialized more than once in the initializer.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {
+        self.x = 1
+        self.x = 2
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeFieldUninitialized,
+		Name:        "FieldUninitializedError",
+		Explanation: "A field is not initialized on all paths in the initializer.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeFieldTypeNotStorable,
+		Name:        "FieldTypeNotStorableError",
+		Explanation: "The type of a field is not storable, for example a function type.",
+		Example: `
+contract C {
+    access(all) let f: fun(): Void
+    init() {
+        self.f = fun() {}
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeFunctionExpressionInCondition,
+		Name:        "FunctionExpressionInConditionError",
+		Explanation: "A function expression is used in a pre-condition or post-condition, which is not allowed.",
+		Example: `
+fun test() {
+    pre {
+        fun(): Bool { return true }()
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEmitCondition,
+		Name:        "InvalidEmitConditionError",
+		Explanation: "An `emit` statement is used in a condition, but the emitted event is not declared in the same interface.",
+	},
+	{
+		Code:        ErrorCodeMissingReturnValue,
+		Name:        "MissingReturnValueError",
+		Explanation: "A `return` statement does not return a value, but the function declares a return type.",
+		Example: `
+fun test(): Int {
+    return
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidImplementation,
+		Name:        "InvalidImplementationError",
+		Explanation: "A declaration is implemented in a context which does not allow an implementation, for example an interface function with an empty body, which is neither a requirement nor a default implementation.",
+		Example: `
+struct interface I {
+    access(all) fun test() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidConformance,
+		Name:        "InvalidConformanceError",
+		Explanation: "A composite declares a conformance to a type which is not an interface.",
+		Example: `
+struct S {}
+struct T: S {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEnumRawType,
+		Name:        "InvalidEnumRawTypeError",
+		Explanation: "An enum declares a raw type which is not an integer type.",
+		Example: `
+enum E: String {}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingEnumRawType,
+		Name:        "MissingEnumRawTypeError",
+		Explanation: "An enum does not declare a raw type. Enums must declare an integer type as their raw type.",
+		Example: `
+enum E {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEnumConformances,
+		Name:        "InvalidEnumConformancesError",
+		Explanation: "An enum declares a conformance to an interface, which enums do not support.",
+	},
+	{
+		Code:        ErrorCodeInvalidAttachmentConformances,
+		Name:        "InvalidAttachmentConformancesError",
+		Explanation: "An attachment declares a conformance to an interface, which attachments do not support.",
+	},
+	{
+		Code:        ErrorCodeConformance,
+		Name:        "ConformanceError",
+		Explanation: "A composite or interface does not satisfy the requirements of an interface it declares a conformance to, for example because a member is missing or has a mismatching type.",
+		Example: `
+struct interface I {
+    access(all) fun test()
+}
+struct S: I {}
+`,
+	},
+	{
+		Code:        ErrorCodeDuplicateConformance,
+		Name:        "DuplicateConformanceError",
+		Explanation: "A composite or interface declares a conformance to the same interface more than once.",
+		Example: `
+struct interface I {}
+struct S: I, I {}
+`,
+	},
+	{
+		Code:        ErrorCodeCyclicConformance,
+		Name:        "CyclicConformanceError",
+		Explanation: "An interface declares a conformance to itself, directly or indirectly.",
+		Example: `
+struct interface I: J {}
+struct interface J: I {}
+`,
+	},
+	{
+		Code:        ErrorCodeMultipleInterfaceDefaultImplementations,
+		Name:        "MultipleInterfaceDefaultImplementationsError",
+		Explanation: "A composite conforms to multiple interfaces which provide a default implementation for the same function, which is ambiguous.",
+		Example: `
+struct interface I {
+    access(all) fun test(): Int {
+        return 1
+    }
+}
+struct interface J {
+    access(all) fun test(): Int {
+        return 2
+    }
+}
+struct S: I, J {}
+`,
+	},
+	{
+		Code:        ErrorCodeSpecialFunctionDefaultImplementation,
+		Name:        "SpecialFunctionDefaultImplementationError",
+		Explanation: "An interface declares a default implementation for a special function, like the initializer, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeInterfaceMemberConflict,
+		Name:        "InterfaceMemberConflictError",
+		Explanation: "Two interfaces which are combined, for example through inheritance, declare members with the same name but different kinds or types.",
+		Example: `
+struct interface I {
+    access(all) fun test()
+}
+struct interface J {
+    access(all) let test: Int
+}
+struct interface K: I, J {}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingConformance,
+		Name:        "MissingConformanceError",
+		Explanation: "A composite implementing an interface which is nested in a containing interface does not conform to it.",
+	},
+	{
+		Code:        ErrorCodeUnresolvedImport,
+		Name:        "UnresolvedImportError",
+		Explanation: "An import could not be resolved, for example because the imported location does not exist.",
+	},
+	{
+		Code:        ErrorCodeNotExported,
+		Name:        "NotExportedError",
+		Explanation: "A declaration is imported from a location which does not declare it.",
+	},
+	{
+		Code:        ErrorCodeAlwaysFailingNonResourceCastingType,
+		Name:        "AlwaysFailingNonResourceCastingTypeError",
+		Explanation: "A resource is cast to a non-resource type, which always fails.",
+	},
+	{
+		Code:        ErrorCodeAlwaysFailingResourceCastingType,
+		Name:        "AlwaysFailingResourceCastingTypeError",
+		Explanation: "A non-resource value is cast to a resource type, which always fails.",
+	},
+	{
+		Code:        ErrorCodeUnsupportedOverloading,
+		Name:        "UnsupportedOverloadingError",
+		Explanation: "A declaration is overloaded, for example a composite declares multiple initializers, which is not supported.",
+	},
+	{
+		Code:        ErrorCodeCompositeKindMismatch,
+		Name:        "CompositeKindMismatchError",
+		Explanation: "The kind of a composite does not match the kind expected in the context, for example a structure is used where a resource is expected.",
+	},
+	{
+		Code:        ErrorCodeInvalidIntegerLiteralRange,
+		Name:        "InvalidIntegerLiteralRangeError",
+		Explanation: "An integer literal is outside of the range of the expected integer type.",
+		Example: `
+let x: UInt8 = 256
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidAddressLiteral,
+		Name:        "InvalidAddressLiteralError",
+		Explanation: "An address literal is not a valid address, for example because it is too long.",
+		Example: `
+let x: Address = 0x10000000000000000
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidFixedPointLiteralRange,
+		Name:        "InvalidFixedPointLiteralRangeError",
+		Explanation: "A fixed-point literal is outside of the range of the expected fixed-point type.",
+		Example: `
+let x: UFix64 = -1.0
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidFixedPointLiteralScale,
+		Name:        "InvalidFixedPointLiteralScaleError",
+		Explanation: "A fixed-point literal has more fractional digits than the expected fixed-point type supports.",
+		Example: `
+let x: UFix64 = 1.000000001
+`,
+	},
+	{
+		Code:        ErrorCodeMissingReturnStatement,
+		Name:        "MissingReturnStatementError",
+		Explanation: "A function declares a return type, but not all paths return a value.",
+		Example: `
+fun test(): Int {}
+`,
+	},
+	{
+		Code:        ErrorCodeUnsupportedOptionalChainingAssignment,
+		Name:        "UnsupportedOptionalChainingAssignmentError",
+		Explanation: "A value is assigned to an optional chaining expression, which is not supported.",
+	},
+	{
+		Code:        ErrorCodeMissingResourceAnnotation,
+		Name:        "MissingResourceAnnotationError",
+		Explanation: "A resource type is used without the resource annotation `@`.",
+		Example: `
+resource R {}
+fun test(r: R) {
+    destroy r
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidNestedResourceMove,
+		Name:        "InvalidNestedResourceMoveError",
+		Explanation: "A resource nested in another value, like a field or an array element, is moved out of the value, which would leave the value invalid.",
+	},
+	{
+		Code:        ErrorCodeInvalidInterfaceConditionResourceInvalidation,
+		Name:        "InvalidInterfaceConditionResourceInvalidationError",
+		Explanation: "A resource is invalidated in a condition of an interface function.",
+	},
+	{
+		Code:        ErrorCodeInvalidResourceAnnotation,
+		Name:        "InvalidResourceAnnotationError",
+		Explanation: "A non-resource type is annotated with the resource annotation `@`.",
+		Example: `
+struct S {}
+fun test(s: @S) {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidInterfaceType,
+		Name:        "InvalidInterfaceTypeError",
+		Explanation: "An interface is used as a type. Use an intersection type, like `{I}`, instead.",
+		Example: `
+struct interface I {}
+fun test(i: I) {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidInterfaceDeclaration,
+		Name:        "InvalidInterfaceDeclarationError",
+		Explanation: "An interface is declared for a composite kind which does not support interfaces, like events or enums.",
+	},
+	{
+		Code:        ErrorCodeIncorrectTransferOperation,
+		Name:        "IncorrectTransferOperationError",
+		Explanation: "A value is transferred with the wrong transfer operation, for example a non-resource is moved using `<-`.",
+		Example: `
+fun test() {
+    let x <- 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidConstruction,
+		Name:        "InvalidConstructionError",
+		Explanation: "A non-resource value is created with `create`. Only resources are created with `create`.",
+		Example: `
+struct S {}
+let s = create S()
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidDestruction,
+		Name:        "InvalidDestructionError",
+		Explanation: "A non-resource value is destroyed with `destroy`. Only resources can be destroyed.",
+		Example: `
+fun test() {
+    destroy 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeResourceLoss,
+		Name:        "ResourceLossError",
+		Explanation: "A resource is lost, for example because it is not moved or destroyed. Resources must be used exactly once.",
+		Example: `
+resource R {}
+fun test() {
+    create R()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeResourceUseAfterInvalidation,
+		Name:        "ResourceUseAfterInvalidationError",
+		Explanation: "A resource is used after it has been moved or destroyed.",
+		Example: `
+resource R {}
+fun test() {
+    let r <- create R()
+    destroy r
+    destroy r
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingCreate,
+		Name:        "MissingCreateError",
+		Explanation: "A resource is constructed without `create`.",
+		Example: `
+resource R {}
+fun test() {
+    let r <- R()
+    destroy r
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingMoveOperation,
+		Name:        "MissingMoveOperationError",
+		Explanation: "A resource is transferred without the move operator `<-`.",
+		Example: `
+resource R {}
+fun consume(_ r: @R) {
+    destroy r
+}
+fun test() {
+    consume(create R())
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidMoveOperation,
+		Name:        "InvalidMoveOperationError",
+		Explanation: "The move operator `<-` is used for a non-resource value.",
+		Example: `
+struct S {}
+fun test(): S {
+    return <-S()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeResourceCapturing,
+		Name:        "ResourceCapturingError",
+		Explanation: "A resource is captured by a closure, which is not allowed.",
+		Example: `
+resource R {}
+fun test() {
+    let r <- create R()
+    let f = fun() {
+        destroy r
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidResourceField,
+		Name:        "InvalidResourceFieldError",
+		Explanation: "A resource field is declared in a composite which is not a resource, and which therefore cannot own resources.",
+		Example: `
+resource R {}
+struct S {
+    access(all) let r: @R
+    init(r: @R) {
+        self.r <- r
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidSwapExpression,
+		Name:        "InvalidSwapExpressionError",
+		Explanation: "A side of a swap statement is not assignable.",
+		Example: `
+fun test() {
+    var x = 1
+    x <-> 2
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEventParameterType,
+		Name:        "InvalidEventParameterTypeError",
+		Explanation: "An event parameter has a type which is not supported for events.",
+		Example: `
+event E(f: fun(): Void)
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEventUsage,
+		Name:        "InvalidEventUsageError",
+		Explanation: "An event is invoked outside of an `emit` statement.",
+		Example: `
+event E()
+fun test() {
+    E()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeEmitNonEvent,
+		Name:        "EmitNonEventError",
+		Explanation: "A value which is not an event is emitted.",
+	},
+	{
+		Code:        ErrorCodeEmitDefaultDestroyEvent,
+		Name:        "EmitDefaultDestroyEventError",
+		Explanation: "A default destruction event is emitted explicitly. Default destruction events are emitted automatically when a resource is destroyed.",
+	},
+	{
+		Code:        ErrorCodeEmitImportedEvent,
+		Name:        "EmitImportedEventError",
+		Explanation: "An event is emitted which is declared in another location. Events can only be emitted by the location which declares them.",
+	},
+	{
+		Code:        ErrorCodeInvalidResourceAssignment,
+		Name:        "InvalidResourceAssignmentError",
+		Explanation: "A resource is assigned with the assignment operator. Resources must be moved using the move operator `<-`, the force-move operator `<-!` or the swap operator `<->`.",
+		Example: `
+resource R {}
+fun test() {
+    var r <- create R()
+    r <- create R()
+    destroy r
+}
+`,
+	},
+	{
+		Code:        ErrorCodeResourceFieldNotInvalidated,
+		Name:        "ResourceFieldNotInvalidatedError",
+		Explanation: "A resource field is not moved or destroyed when the containing resource is destroyed.",
+	},
+	{
+		Code:        ErrorCodeUninitializedFieldAccess,
+		Name:        "UninitializedFieldAccessError",
+		Explanation: "A field is accessed in the initializer before it is initialized.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {
+        let y = self.x
+        self.x = 1
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeUnreachableStatement,
+		Name:        "UnreachableStatementError",
+		Explanation: "A statement is unreachable, for example because it follows a `return` statement.",
+		Example: `
+fun test() {
+    return
+    let x = 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeUninitializedUse,
+		Name:        "UninitializedUseError",
+		Explanation: "The value `self` is used in the initializer before all fields are initialized.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {
+        let s = self
+        self.x = 1
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidResourceArrayMember,
+		Name:        "InvalidResourceArrayMemberError",
+		Explanation: "A member of an array is used which is not available for arrays of resources.",
+	},
+	{
+		Code:        ErrorCodeInvalidResourceDictionaryMember,
+		Name:        "InvalidResourceDictionaryMemberError",
+		Explanation: "A member of a dictionary is used which is not available for dictionaries of resources.",
+	},
+	{
+		Code:        ErrorCodeInvalidResourceOptionalMember,
+		Name:        "InvalidResourceOptionalMemberError",
+		Explanation: "A member of an optional is used which is not available for optional resources.",
+	},
+	{
+		Code:        ErrorCodeNonReferenceTypeReference,
+		Name:        "NonReferenceTypeReferenceError",
+		Explanation: "A reference expression is cast to a type which is not a reference type.",
+		Example: `
+let x = &1 as Int
+`,
+	},
+	{
+		Code:        ErrorCodeReferenceToAnOptional,
+		Name:        "ReferenceToAnOptionalError",
+		Explanation: "A reference is created with an optional type, which is not allowed. Use an optional reference type instead.",
+	},
+	{
+		Code:        ErrorCodeInvalidResourceCreation,
+		Name:        "InvalidResourceCreationError",
+		Explanation: "A resource is created outside of the contract which declares the resource type.",
+	},
+	{
+		Code:        ErrorCodeNonResourceType,
+		Name:        "NonResourceTypeError",
+		Explanation: "A type is used which is not a resource type, but a resource type is expected.",
+	},
+	{
+		Code:        ErrorCodeInvalidAssignmentTarget,
+		Name:        "InvalidAssignmentTargetError",
+		Explanation: "A value is assigned to an expression which is not assignable, like a literal.",
+		Example: `
+fun test() {
+    1 = 2
+}
+`,
+	},
+	{
+		Code:        ErrorCodeResourceMethodBinding,
+		Name:        "ResourceMethodBindingError",
+		Explanation: "A function of a resource is bound to a variable, which is not allowed.",
+		Example: `
+resource R {
+    access(all) fun test() {}
+}
+fun test() {
+    let r <- create R()
+    let f = r.test
+    destroy r
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidDictionaryKeyType,
+		Name:        "InvalidDictionaryKeyTypeError",
+		Explanation: "A dictionary type has a key type which is not hashable.",
+		Example: `
+let x: {[Int]: Int} = {}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingFunctionBody,
+		Name:        "MissingFunctionBodyError",
+		Explanation: "A function is declared without a body in a context which requires an implementation.",
+	},
+	{
+		Code:        ErrorCodeInvalidOptionalChaining,
+		Name:        "InvalidOptionalChainingError",
+		Explanation: "Optional chaining is used on a value which does not have an optional type.",
+		Example: `
+struct S {
+    access(all) let x: Int
+    init() {
+        self.x = 1
+    }
+}
+let x = S()?.x
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidAccess,
+		Name:        "InvalidAccessError",
+		Explanation: "A member is accessed which is not accessible in the current context, due to its access modifier or missing entitlements.",
+		Example: `
+struct S {
+    access(self) let x: Int
+    init() {
+        self.x = 1
+    }
+}
+let x = S().x
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidAssignmentAccess,
+		Name:        "InvalidAssignmentAccessError",
+		Explanation: "A field is assigned to outside of the composite which declares the field.",
+		Example: `
+struct S {
+    access(all) var x: Int
+    init() {
+        self.x = 1
+    }
+}
+fun test() {
+    let s = S()
+    s.x = 2
+}
+`,
+	},
+	{
+		Code:        ErrorCodeUnauthorizedReferenceAssignment,
+		Name:        "UnauthorizedReferenceAssignmentError",
+		Explanation: "A field is assigned to through a reference which does not have the required entitlements.",
+	},
+	{
+		Code:        ErrorCodeInvalidCharacterLiteral,
+		Name:        "InvalidCharacterLiteralError",
+		Explanation: "A character literal does not consist of exactly one grapheme cluster.",
+		Example: `
+let x: Character = "ab"
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidFailableResourceDowncastOutsideOptionalBinding,
+		Name:        "InvalidFailableResourceDowncastOutsideOptionalBindingError",
+		Explanation: "A resource is failably downcast with `as?` outside of an optional binding, which could lose the resource.",
+	},
+	{
+		Code:        ErrorCodeInvalidNonIdentifierFailableResourceDowncast,
+		Name:        "InvalidNonIdentifierFailableResourceDowncast",
+		Explanation: "A resource expression which is not a variable is failably downcast with `as?`, which could lose the resource.",
+	},
+	{
+		Code:        ErrorCodeReadOnlyTargetAssignment,
+		Name:        "ReadOnlyTargetAssignmentError",
+		Explanation: "A value is assigned to a target which is read-only.",
+	},
+	{
+		Code:        ErrorCodeInvalidTransactionBlock,
+		Name:        "InvalidTransactionBlockError",
+		Explanation: "A transaction declares an invalid block. Transactions may only declare `prepare`, `pre`, `execute` and `post` blocks.",
+	},
+	{
+		Code:        ErrorCodeTransactionMissingPrepare,
+		Name:        "TransactionMissingPrepareError",
+		Explanation: "A transaction declares fields, but no `prepare` block which initializes them.",
+		Example: `
+transaction {
+    let x: Int
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidResourceTransactionParameter,
+		Name:        "InvalidResourceTransactionParameterError",
+		Explanation: "A transaction parameter has a resource type, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeInvalidNonImportableTransactionParameterType,
+		Name:        "InvalidNonImportableTransactionParameterTypeError",
+		Explanation: "A transaction parameter has a type which cannot be imported as an argument.",
+		Example: `
+transaction(f: fun(): Void) {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidTransactionFieldAccessModifier,
+		Name:        "InvalidTransactionFieldAccessModifierError",
+		Explanation: "A transaction field declares an access modifier, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeInvalidTransactionPrepareParameterType,
+		Name:        "InvalidTransactionPrepareParameterTypeError",
+		Explanation: "A parameter of the `prepare` block of a transaction does not have an account reference type.",
+		Example: `
+transaction {
+    prepare(x: Int) {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidNestedDeclaration,
+		Name:        "InvalidNestedDeclarationError",
+		Explanation: "A declaration is nested inside a declaration which does not support nesting declarations of this kind.",
+		Example: `
+struct S {
+    contract C {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidNestedType,
+		Name:        "InvalidNestedTypeError",
+		Explanation: "A nested type is accessed on a type which does not support nested types.",
+	},
+	{
+		Code:        ErrorCodeInvalidEnumCase,
+		Name:        "InvalidEnumCaseError",
+		Explanation: "An enum case is declared in a declaration which is not an enum.",
+		Example: `
+struct S {
+    case a
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidNonEnumCase,
+		Name:        "InvalidNonEnumCaseError",
+		Explanation: "An enum declares members other than enum cases, which is not allowed.",
+		Example: `
+enum E: UInt8 {
+    access(all) fun test() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeDeclarationKindMismatch,
+		Name:        "DeclarationKindMismatchError",
+		Explanation: "The kind of a declaration does not match the kind of the declaration it is required to conform to.",
+	},
+	{
+		Code:        ErrorCodeInvalidTopLevelDeclaration,
+		Name:        "InvalidTopLevelDeclarationError",
+		Explanation: "A declaration is not allowed at the top-level of the program, for example because the program is a contract and only contract declarations are allowed.",
+	},
+	{
+		Code:        ErrorCodeInvalidSelfInvalidation,
+		Name:        "InvalidSelfInvalidationError",
+		Explanation: "The value `self` is moved or destroyed in a resource function, which is not allowed.",
+		Example: `
+resource R {
+    access(all) fun test() {
+        destroy self
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidMove,
+		Name:        "InvalidMoveError",
+		Explanation: "A declaration which cannot be moved, like a function or a type, is moved using the move operator.",
+	},
+	{
+		Code:        ErrorCodeConstantSizedArrayLiteralSize,
+		Name:        "ConstantSizedArrayLiteralSizeError",
+		Explanation: "An array literal has a different number of elements than the expected constant-sized array type.",
+		Example: `
+let x: [Int; 2] = [1]
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidIntersectedType,
+		Name:        "InvalidIntersectedTypeError",
+		Explanation: "An intersection type contains a type which is not an interface.",
+		Example: `
+struct S {}
+let x: {S}? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeIntersectionCompositeKindMismatch,
+		Name:        "IntersectionCompositeKindMismatchError",
+		Explanation: "An intersection type contains interfaces of different composite kinds, for example a structure interface and a resource interface.",
+	},
+	{
+		Code:        ErrorCodeInvalidIntersectionTypeDuplicate,
+		Name:        "InvalidIntersectionTypeDuplicateError",
+		Explanation: "An intersection type contains the same interface more than once.",
+		Example: `
+struct interface I {}
+let x: {I, I}? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeIntersectionMemberClash,
+		Name:        "IntersectionMemberClashError",
+		Explanation: "An intersection type contains interfaces which declare members with the same name, but with different types or kinds.",
+	},
+	{
+		Code:        ErrorCodeAmbiguousIntersectionType,
+		Name:        "AmbiguousIntersectionTypeError",
+		Explanation: "The type of an intersection type cannot be determined, for example because the intersection type is empty.",
+		Example: `
+let x: {} = 1
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidPathDomain,
+		Name:        "InvalidPathDomainError",
+		Explanation: "A path literal has a domain which is not valid. Valid domains are `storage`, `public` and `private`.",
+		Example: `
+let x = /invalid/test
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidTypeArgumentCount,
+		Name:        "InvalidTypeArgumentCountError",
+		Explanation: "A parameterized type or function is instantiated with the wrong number of type arguments.",
+		Example: `
+let x: Capability<&Int, &Int>? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeMissingTypeArgument,
+		Name:        "MissingTypeArgumentError",
+		Explanation: "A function which requires a type argument is called without it, and the type argument cannot be inferred.",
+	},
+	{
+		Code:        ErrorCodeInvalidTypeArgument,
+		Name:        "InvalidTypeArgumentError",
+		Explanation: "A type argument does not satisfy the requirements of its type parameter.",
+	},
+	{
+		Code:        ErrorCodeTypeParameterTypeInference,
+		Name:        "TypeParameterTypeInferenceError",
+		Explanation: "The type argument of a type parameter cannot be inferred from the arguments of the call. Provide the type argument explicitly.",
+	},
+	{
+		Code:        ErrorCodeInvalidConstantSizedTypeBase,
+		Name:        "InvalidConstantSizedTypeBaseError",
+		Explanation: "The size of a constant-sized array type is not a decimal integer literal.",
+		Example: `
+let x: [Int; 0x1] = [1]
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidConstantSizedTypeSize,
+		Name:        "InvalidConstantSizedTypeSizeError",
+		Explanation: "The size of a constant-sized array type is not a valid size, for example because it is negative or too large.",
+	},
+	{
+		Code:        ErrorCodeUnsupportedResourceForLoop,
+		Name:        "UnsupportedResourceForLoopError",
+		Explanation: "A `for` loop iterates over an array of resources, which is not supported.",
+	},
+	{
+		Code:        ErrorCodeTypeParameterTypeMismatch,
+		Name:        "TypeParameterTypeMismatchError",
+		Explanation: "A type argument is not a subtype of the type bound of its type parameter.",
+	},
+	{
+		Code:        ErrorCodeUnparameterizedTypeInstantiation,
+		Name:        "UnparameterizedTypeInstantiationError",
+		Explanation: "A type which is not parameterized is instantiated with type arguments.",
+		Example: `
+let x: Int<String>? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeTypeAnnotationRequired,
+		Name:        "TypeAnnotationRequiredError",
+		Explanation: "The type of a declaration cannot be inferred, for example for an empty array literal. Add an explicit type annotation.",
+		Example: `
+let x = []
+`,
+	},
+	{
+		Code:        ErrorCodeCyclicImports,
+		Name:        "CyclicImportsError",
+		Explanation: "Programs import each other cyclically, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeSwitchDefaultPosition,
+		Name:        "SwitchDefaultPositionError",
+		Explanation: "The `default` case of a `switch` statement is not the last case.",
+		Example: `
+fun test(x: Int) {
+    switch x {
+        default:
+            return
+        case 1:
+            return
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingSwitchCaseStatements,
+		Name:        "MissingSwitchCaseStatementsError",
+		Explanation: "A case of a `switch` statement has no statements.",
+		Example: `
+fun test(x: Int) {
+    switch x {
+        case 1:
+    }
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMissingEntryPoint,
+		Name:        "MissingEntryPointError",
+		Explanation: "A program is executed, but it does not declare the expected entry point, like the `main` function of a script.",
+	},
+	{
+		Code:        ErrorCodeInvalidEntryPointType,
+		Name:        "InvalidEntryPointTypeError",
+		Explanation: "The entry point of a program has an invalid type, for example the `main` function of a script has a parameter with a non-importable type.",
+	},
+	{
+		Code:        ErrorCodePurity,
+		Name:        "PurityError",
+		Explanation: "An impure operation, like a mutation, is performed in a view context, like a view function or a condition.",
+		Example: `
+var x = 1
+view fun test() {
+    x = 2
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidatedResourceReference,
+		Name:        "InvalidatedResourceReferenceError",
+		Explanation: "A reference to a resource is used after the resource has been moved or destroyed.",
+		Example: `
+resource R {
+    access(all) let x: Int
+    init() {
+        self.x = 1
+    }
+}
+fun test() {
+    let r <- create R()
+    let ref = &r as &R
+    destroy r
+    let x = ref.x
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEntitlementAccess,
+		Name:        "InvalidEntitlementAccessError",
+		Explanation: "A declaration which is not a member of a structure or resource is declared with entitlement access.",
+		Example: `
+entitlement E
+access(E) fun test() {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidEntitlementMappingType,
+		Name:        "InvalidEntitlementMappingTypeError",
+		Explanation: "A type which is not an entitlement mapping is used in a mapping access modifier or an entitlement mapping inclusion.",
+	},
+	{
+		Code:        ErrorCodeInvalidNonEntitlementTypeInMap,
+		Name:        "InvalidNonEntitlementTypeInMapError",
+		Explanation: "An entitlement mapping maps a type which is not an entitlement.",
+		Example: `
+struct S {}
+entitlement E
+entitlement mapping M {
+    S -> E
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidMappingAccess,
+		Name:        "InvalidMappingAccessError",
+		Explanation: "A mapping access modifier is used for a declaration which is not a member of a structure or resource.",
+	},
+	{
+		Code:        ErrorCodeInvalidMappingAccessMemberType,
+		Name:        "InvalidMappingAccessMemberTypeError",
+		Explanation: "A member with mapping access has a type which does not support entitlement mappings. The type must be a reference, or an optional reference.",
+	},
+	{
+		Code:        ErrorCodeInvalidNonEntitlementAccess,
+		Name:        "InvalidNonEntitlementAccessError",
+		Explanation: "A type which is not an entitlement is used in an access modifier.",
+		Example: `
+struct S {
+    access(S) fun test() {}
+}
+`,
+	},
+	{
+		Code:        ErrorCodeMappingAccessMissingKeyword,
+		Name:        "MappingAccessMissingKeywordError",
+		Explanation: "An entitlement mapping is used in an access modifier without the `mapping` keyword.",
+	},
+	{
+		Code:        ErrorCodeDirectEntitlementAnnotation,
+		Name:        "DirectEntitlementAnnotationError",
+		Explanation: "An entitlement is used as a type, outside of an access modifier or an authorized reference type.",
+		Example: `
+entitlement E
+let x: E? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeUnrepresentableEntitlementMapOutput,
+		Name:        "UnrepresentableEntitlementMapOutputError",
+		Explanation: "The entitlements resulting from mapping a reference through an entitlement mapping cannot be represented.",
+	},
+	{
+		Code:        ErrorCodeInvalidEntitlementMappingInclusion,
+		Name:        "InvalidEntitlementMappingInclusionError",
+		Explanation: "An entitlement mapping includes a type which is not an entitlement mapping.",
+	},
+	{
+		Code:        ErrorCodeDuplicateEntitlementMappingInclusion,
+		Name:        "DuplicateEntitlementMappingInclusionError",
+		Explanation: "An entitlement mapping includes the same entitlement mapping more than once.",
+	},
+	{
+		Code:        ErrorCodeCyclicEntitlementMapping,
+		Name:        "CyclicEntitlementMappingError",
+		Explanation: "Entitlement mappings include each other cyclically, which is not allowed.",
+	},
+	{
+		Code:        ErrorCodeInvalidBaseType,
+		Name:        "InvalidBaseTypeError",
+		Explanation: "An attachment declares a base type which cannot have attachments.",
+		Example: `
+attachment A for Int {}
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidAttachmentAnnotation,
+		Name:        "InvalidAttachmentAnnotationError",
+		Explanation: "An attachment type is used directly, outside of a reference type.",
+	},
+	{
+		Code:        ErrorCodeInvalidAttachmentUsage,
+		Name:        "InvalidAttachmentUsageError",
+		Explanation: "An attachment is constructed outside of an `attach` expression.",
+	},
+	{
+		Code:        ErrorCodeAttachNonAttachment,
+		Name:        "AttachNonAttachmentError",
+		Explanation: "A value which is not an attachment is attached using an `attach` expression.",
+	},
+	{
+		Code:        ErrorCodeAttachToInvalidType,
+		Name:        "AttachToInvalidTypeError",
+		Explanation: "An attachment is attached to a value whose type is not a subtype of the base type of the attachment.",
+	},
+	{
+		Code:        ErrorCodeInvalidAttachmentRemove,
+		Name:        "InvalidAttachmentRemoveError",
+		Explanation: "A type which is not an attachment, or not an attachment for the base value, is removed using a `remove` statement.",
+	},
+	{
+		Code:        ErrorCodeInvalidTypeIndexing,
+		Name:        "InvalidTypeIndexingError",
+		Explanation: "A value is indexed with a type, but the type is not a valid type index for the value, for example an attachment type for a different base type.",
+	},
+	{
+		Code:        ErrorCodeInvalidAttachmentEntitlement,
+		Name:        "InvalidAttachmentEntitlementError",
+		Explanation: "An attachment declares a member with entitlement access, but the entitlement is not supported by the base type of the attachment.",
+	},
+	{
+		Code:        ErrorCodeDefaultDestroyEventInNonResource,
+		Name:        "DefaultDestroyEventInNonResourceError",
+		Explanation: "A default destruction event is declared in a composite which is not a resource.",
+		Example: `
+struct S {
+    event ResourceDestroyed()
+}
+`,
+	},
+	{
+		Code:        ErrorCodeDefaultDestroyInvalidArgument,
+		Name:        "DefaultDestroyInvalidArgumentError",
+		Explanation: "An argument of a default destruction event is not a supported expression. Only literals, and accesses to the fields of the resource, are supported.",
+	},
+	{
+		Code:        ErrorCodeDefaultDestroyInvalidParameter,
+		Name:        "DefaultDestroyInvalidParameterError",
+		Explanation: "A parameter of a default destruction event has a type which is not supported for default destruction events.",
+	},
+	{
+		Code:        ErrorCodeNestedReference,
+		Name:        "NestedReferenceError",
+		Explanation: "A reference is created to a value which is already a reference, which is not allowed.",
+		Example: `
+let x = 1
+let y = &(&x as &Int) as &(&Int)
+`,
+	},
+	{
+		Code:        ErrorCodeResultVariableConflict,
+		Name:        "ResultVariableConflictError",
+		Explanation: "A function with post-conditions declares a parameter or variable named `result`, which conflicts with the variable which holds the function's result in post-conditions.",
+		Example: `
+fun test(result: Int): Int {
+    post {
+        result == 1
+    }
+    return 1
+}
+`,
+	},
+	{
+		Code:        ErrorCodeInvocationTypeInference,
+		Name:        "InvocationTypeInferenceError",
+		Explanation: "The type of an invocation cannot be inferred. Add a type annotation to the invoked expression.",
+	},
+	{
+		Code:        ErrorCodeUnconvertableType,
+		Name:        "UnconvertableTypeError",
+		Explanation: "A type cannot be converted to a static type, for example because it is not storable.",
+	},
+	{
+		Code:        ErrorCodeInvalidMappingAuthorization,
+		Name:        "InvalidMappingAuthorizationError",
+		Explanation: "An authorized reference type uses an entitlement mapping in its authorization, which is not supported.",
+		Example: `
+entitlement mapping M {}
+let x: auth(mapping M) &Int? = nil
+`,
+	},
+	{
+		Code:        ErrorCodeInvalidTypeParameterizedNonNativeFunction,
+		Name:        "InvalidTypeParameterizedNonNativeFunctionError",
+		Explanation: "A non-native function declares type parameters, which is only allowed if type parameters are enabled.",
+	},
+}
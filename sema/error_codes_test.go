@@ -0,0 +1,69 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+	. "github.com/onflow/cadence/test_utils/sema_utils"
+)
+
+func TestCheckErrorCodeExamples(t *testing.T) {
+
+	t.Parallel()
+
+	for _, info := range sema.ErrorCodes {
+		if info.Example == "" {
+			continue
+		}
+
+		info := info
+
+		t.Run(string(info.Code), func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheckWithOptions(t,
+				info.Example,
+				ParseAndCheckOptions{
+					Config: &sema.Config{
+						AccessCheckMode: sema.AccessCheckModeStrict,
+					},
+				},
+			)
+			require.Error(t, err)
+
+			var checkerErr *sema.CheckerError
+			require.ErrorAs(t, err, &checkerErr)
+
+			var codes []errors.ErrorCode
+			for _, err := range checkerErr.Errors {
+				code, ok := errors.GetErrorCode(err)
+				require.True(t, ok, "missing error code: %T", err)
+				codes = append(codes, code)
+			}
+
+			require.Contains(t, codes, info.Code, info.Name)
+		})
+	}
+}
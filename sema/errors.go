@@ -82,6 +82,10 @@ func (*InvalidPragmaError) isSemanticError() {}
 
 func (*InvalidPragmaError) IsUserError() {}
 
+func (*InvalidPragmaError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidPragma
+}
+
 func (e *InvalidPragmaError) Error() string {
 	return "invalid pragma"
 }
@@ -154,6 +158,10 @@ func (*RedeclarationError) isSemanticError() {}
 
 func (*RedeclarationError) IsUserError() {}
 
+func (*RedeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeRedeclaration
+}
+
 func (e *RedeclarationError) Error() string {
 	return fmt.Sprintf(
 		"cannot redeclare %s: `%s` is already declared",
@@ -217,6 +225,10 @@ func (*NotDeclaredError) isSemanticError() {}
 
 func (*NotDeclaredError) IsUserError() {}
 
+func (*NotDeclaredError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotDeclared
+}
+
 func (e *NotDeclaredError) Error() string {
 	return fmt.Sprintf(
 		"cannot find %s in this scope: `%s`",
@@ -253,6 +265,10 @@ func (*AssignmentToConstantError) isSemanticError() {}
 
 func (*AssignmentToConstantError) IsUserError() {}
 
+func (*AssignmentToConstantError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAssignmentToConstant
+}
+
 func (e *AssignmentToConstantError) Error() string {
 	return fmt.Sprintf("cannot assign to constant: `%s`", e.Name)
 }
@@ -278,6 +294,10 @@ func (*TypeMismatchError) isSemanticError() {}
 
 func (*TypeMismatchError) IsUserError() {}
 
+func (*TypeMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeMismatch
+}
+
 func (e *TypeMismatchError) Error() string {
 	return "mismatched types"
 }
@@ -311,6 +331,10 @@ func (*TypeMismatchWithDescriptionError) isSemanticError() {}
 
 func (*TypeMismatchWithDescriptionError) IsUserError() {}
 
+func (*TypeMismatchWithDescriptionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeMismatchWithDescription
+}
+
 func (e *TypeMismatchWithDescriptionError) Error() string {
 	return "mismatched types"
 }
@@ -337,6 +361,10 @@ func (*NotIndexableTypeError) isSemanticError() {}
 
 func (*NotIndexableTypeError) IsUserError() {}
 
+func (*NotIndexableTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotIndexableType
+}
+
 func (e *NotIndexableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot index into value which has type: `%s`",
@@ -358,6 +386,10 @@ func (*NotIndexingAssignableTypeError) isSemanticError() {}
 
 func (*NotIndexingAssignableTypeError) IsUserError() {}
 
+func (*NotIndexingAssignableTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotIndexingAssignableType
+}
+
 func (e *NotIndexingAssignableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot assign into value which has type: `%s`",
@@ -379,6 +411,10 @@ func (*NotEquatableTypeError) isSemanticError() {}
 
 func (*NotEquatableTypeError) IsUserError() {}
 
+func (*NotEquatableTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotEquatableType
+}
+
 func (e *NotEquatableTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot compare value which has type: `%s`",
@@ -400,6 +436,10 @@ func (*NotCallableError) isSemanticError() {}
 
 func (*NotCallableError) IsUserError() {}
 
+func (*NotCallableError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotCallable
+}
+
 func (e *NotCallableError) Error() string {
 	return fmt.Sprintf("cannot call type: `%s`",
 		e.Type.QualifiedString(),
@@ -422,6 +462,10 @@ func (*InsufficientArgumentsError) isSemanticError() {}
 
 func (*InsufficientArgumentsError) IsUserError() {}
 
+func (*InsufficientArgumentsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInsufficientArguments
+}
+
 func (e *InsufficientArgumentsError) Error() string {
 	return "too few arguments"
 }
@@ -450,6 +494,10 @@ func (*ExcessiveArgumentsError) isSemanticError() {}
 
 func (*ExcessiveArgumentsError) IsUserError() {}
 
+func (*ExcessiveArgumentsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeExcessiveArguments
+}
+
 func (e *ExcessiveArgumentsError) Error() string {
 	return "too many arguments"
 }
@@ -479,6 +527,10 @@ func (*MissingArgumentLabelError) isSemanticError() {}
 
 func (*MissingArgumentLabelError) IsUserError() {}
 
+func (*MissingArgumentLabelError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingArgumentLabel
+}
+
 func (e *MissingArgumentLabelError) Error() string {
 	return fmt.Sprintf(
 		"missing argument label: `%s`",
@@ -520,6 +572,10 @@ func (*IncorrectArgumentLabelError) isSemanticError() {}
 
 func (*IncorrectArgumentLabelError) IsUserError() {}
 
+func (*IncorrectArgumentLabelError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeIncorrectArgumentLabel
+}
+
 func (e *IncorrectArgumentLabelError) Error() string {
 	return "incorrect argument label"
 }
@@ -598,6 +654,10 @@ func (*InvalidUnaryOperandError) isSemanticError() {}
 
 func (*InvalidUnaryOperandError) IsUserError() {}
 
+func (*InvalidUnaryOperandError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidUnaryOperand
+}
+
 func (e *InvalidUnaryOperandError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply unary operation %s to type",
@@ -645,6 +705,10 @@ func (*InvalidBinaryOperandError) isSemanticError() {}
 
 func (*InvalidBinaryOperandError) IsUserError() {}
 
+func (*InvalidBinaryOperandError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidBinaryOperand
+}
+
 func (e *InvalidBinaryOperandError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply binary operation %s to %s-hand type",
@@ -682,6 +746,10 @@ func (*InvalidBinaryOperandsError) isSemanticError() {}
 
 func (*InvalidBinaryOperandsError) IsUserError() {}
 
+func (*InvalidBinaryOperandsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidBinaryOperands
+}
+
 func (e *InvalidBinaryOperandsError) Error() string {
 	return fmt.Sprintf(
 		"cannot apply binary operation %s to types: `%s`, `%s`",
@@ -726,6 +794,10 @@ func (*ControlStatementError) isSemanticError() {}
 
 func (*ControlStatementError) IsUserError() {}
 
+func (*ControlStatementError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeControlStatement
+}
+
 func (e *ControlStatementError) Error() string {
 	return fmt.Sprintf(
 		"invalid control statement: `%s`",
@@ -761,6 +833,10 @@ func (*InvalidAccessModifierError) isSemanticError() {}
 
 func (*InvalidAccessModifierError) IsUserError() {}
 
+func (*InvalidAccessModifierError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAccessModifier
+}
+
 func (e *InvalidAccessModifierError) Error() string {
 	var explanation string
 	if e.Explanation != "" {
@@ -811,6 +887,10 @@ func (*MissingAccessModifierError) isSemanticError() {}
 
 func (*MissingAccessModifierError) IsUserError() {}
 
+func (*MissingAccessModifierError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingAccessModifier
+}
+
 func (e *MissingAccessModifierError) Error() string {
 	var explanation string
 	if e.Explanation != "" {
@@ -845,6 +925,10 @@ func (*InvalidStaticModifierError) isSemanticError() {}
 
 func (*InvalidStaticModifierError) IsUserError() {}
 
+func (*InvalidStaticModifierError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidStaticModifier
+}
+
 func (e *InvalidStaticModifierError) Error() string {
 	return "invalid static modifier for declaration"
 }
@@ -862,6 +946,10 @@ func (*InvalidNativeModifierError) isSemanticError() {}
 
 func (*InvalidNativeModifierError) IsUserError() {}
 
+func (*InvalidNativeModifierError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNativeModifier
+}
+
 func (e *InvalidNativeModifierError) Error() string {
 	return "invalid native modifier for declaration"
 }
@@ -879,6 +967,10 @@ func (*NativeFunctionWithImplementationError) isSemanticError() {}
 
 func (*NativeFunctionWithImplementationError) IsUserError() {}
 
+func (*NativeFunctionWithImplementationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNativeFunctionWithImplementation
+}
+
 func (e *NativeFunctionWithImplementationError) Error() string {
 	return "native function must not have an implementation"
 }
@@ -897,6 +989,10 @@ func (*InvalidNameError) isSemanticError() {}
 
 func (*InvalidNameError) IsUserError() {}
 
+func (*InvalidNameError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidName
+}
+
 func (e *InvalidNameError) Error() string {
 	return fmt.Sprintf("invalid name: `%s`", e.Name)
 }
@@ -923,6 +1019,10 @@ func (*UnknownSpecialFunctionError) isSemanticError() {}
 
 func (*UnknownSpecialFunctionError) IsUserError() {}
 
+func (*UnknownSpecialFunctionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnknownSpecialFunction
+}
+
 func (e *UnknownSpecialFunctionError) Error() string {
 	return "unknown special function. did you mean `init` or forget the `fun` keyword?"
 }
@@ -949,6 +1049,10 @@ func (*InvalidVariableKindError) isSemanticError() {}
 
 func (*InvalidVariableKindError) IsUserError() {}
 
+func (*InvalidVariableKindError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidVariableKind
+}
+
 func (e *InvalidVariableKindError) Error() string {
 	if e.Kind == ast.VariableKindNotSpecified {
 		return "missing variable kind"
@@ -971,6 +1075,10 @@ func (*InvalidDeclarationError) isSemanticError() {}
 
 func (*InvalidDeclarationError) IsUserError() {}
 
+func (*InvalidDeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidDeclaration
+}
+
 func (e *InvalidDeclarationError) Error() string {
 	if e.Identifier != "" {
 		return fmt.Sprintf(
@@ -998,6 +1106,10 @@ func (*MissingInitializerError) isSemanticError() {}
 
 func (*MissingInitializerError) IsUserError() {}
 
+func (*MissingInitializerError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingInitializer
+}
+
 func (e *MissingInitializerError) Error() string {
 	return fmt.Sprintf(
 		"missing initializer for field `%s` in type `%s`",
@@ -1034,6 +1146,10 @@ func (*NotDeclaredMemberError) isSemanticError() {}
 
 func (*NotDeclaredMemberError) IsUserError() {}
 
+func (*NotDeclaredMemberError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotDeclaredMember
+}
+
 func (e *NotDeclaredMemberError) Error() string {
 	return fmt.Sprintf(
 		"value of type `%s` has no member `%s`",
@@ -1146,6 +1262,10 @@ func (*AssignmentToConstantMemberError) isSemanticError() {}
 
 func (*AssignmentToConstantMemberError) IsUserError() {}
 
+func (*AssignmentToConstantMemberError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAssignmentToConstantMember
+}
+
 func (e *AssignmentToConstantMemberError) Error() string {
 	return fmt.Sprintf("cannot assign to constant member: `%s`", e.Name)
 }
@@ -1163,6 +1283,10 @@ func (*FieldReinitializationError) isSemanticError() {}
 
 func (*FieldReinitializationError) IsUserError() {}
 
+func (*FieldReinitializationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeFieldReinitialization
+}
+
 func (e *FieldReinitializationError) Error() string {
 	return fmt.Sprintf("invalid reinitialization of field: `%s`", e.Name)
 }
@@ -1182,6 +1306,10 @@ func (*FieldUninitializedError) isSemanticError() {}
 
 func (*FieldUninitializedError) IsUserError() {}
 
+func (*FieldUninitializedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeFieldUninitialized
+}
+
 func (e *FieldUninitializedError) Error() string {
 	return fmt.Sprintf(
 		"missing initialization of field `%s` in type `%s`",
@@ -1227,6 +1355,10 @@ func (*FieldTypeNotStorableError) isSemanticError() {}
 
 func (*FieldTypeNotStorableError) IsUserError() {}
 
+func (*FieldTypeNotStorableError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeFieldTypeNotStorable
+}
+
 func (e *FieldTypeNotStorableError) Error() string {
 	return fmt.Sprintf(
 		"field %s has non-storable type: %s",
@@ -1261,6 +1393,10 @@ func (*FunctionExpressionInConditionError) isSemanticError() {}
 
 func (*FunctionExpressionInConditionError) IsUserError() {}
 
+func (*FunctionExpressionInConditionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeFunctionExpressionInCondition
+}
+
 func (e *FunctionExpressionInConditionError) Error() string {
 	return "condition contains function"
 }
@@ -1278,6 +1414,10 @@ func (*InvalidEmitConditionError) isSemanticError() {}
 
 func (*InvalidEmitConditionError) IsUserError() {}
 
+func (*InvalidEmitConditionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEmitCondition
+}
+
 func (e *InvalidEmitConditionError) Error() string {
 	return "invalid emit condition "
 }
@@ -1296,6 +1436,10 @@ func (*MissingReturnValueError) isSemanticError() {}
 
 func (*MissingReturnValueError) IsUserError() {}
 
+func (*MissingReturnValueError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingReturnValue
+}
+
 func (e *MissingReturnValueError) Error() string {
 	var typeDescription string
 	if e.ExpectedValueType.IsInvalidType() {
@@ -1325,6 +1469,10 @@ func (*InvalidImplementationError) isSemanticError() {}
 
 func (*InvalidImplementationError) IsUserError() {}
 
+func (*InvalidImplementationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidImplementation
+}
+
 func (e *InvalidImplementationError) Error() string {
 	return fmt.Sprintf(
 		"cannot implement %s in %s",
@@ -1355,6 +1503,10 @@ func (*InvalidConformanceError) isSemanticError() {}
 
 func (*InvalidConformanceError) IsUserError() {}
 
+func (*InvalidConformanceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidConformance
+}
+
 func (e *InvalidConformanceError) Error() string {
 	return fmt.Sprintf(
 		"cannot conform to non-interface type: `%s`",
@@ -1377,6 +1529,10 @@ func (*InvalidEnumRawTypeError) isSemanticError() {}
 
 func (*InvalidEnumRawTypeError) IsUserError() {}
 
+func (*InvalidEnumRawTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEnumRawType
+}
+
 func (e *InvalidEnumRawTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid enum raw type: `%s`",
@@ -1401,6 +1557,10 @@ func (*MissingEnumRawTypeError) isSemanticError() {}
 
 func (*MissingEnumRawTypeError) IsUserError() {}
 
+func (*MissingEnumRawTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingEnumRawType
+}
+
 func (e *MissingEnumRawTypeError) Error() string {
 	return "missing enum raw type"
 }
@@ -1426,6 +1586,10 @@ func (*InvalidEnumConformancesError) isSemanticError() {}
 
 func (*InvalidEnumConformancesError) IsUserError() {}
 
+func (*InvalidEnumConformancesError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEnumConformances
+}
+
 func (e *InvalidEnumConformancesError) Error() string {
 	return "enums cannot conform to interfaces"
 }
@@ -1443,6 +1607,10 @@ func (*InvalidAttachmentConformancesError) isSemanticError() {}
 
 func (*InvalidAttachmentConformancesError) IsUserError() {}
 
+func (*InvalidAttachmentConformancesError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAttachmentConformances
+}
+
 func (e *InvalidAttachmentConformancesError) Error() string {
 	return "attachments cannot conform to interfaces"
 }
@@ -1482,6 +1650,10 @@ func (*ConformanceError) isSemanticError() {}
 
 func (*ConformanceError) IsUserError() {}
 
+func (*ConformanceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeConformance
+}
+
 func (e *ConformanceError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` does not conform to %s interface `%s`",
@@ -1582,6 +1754,10 @@ func (*DuplicateConformanceError) isSemanticError() {}
 
 func (*DuplicateConformanceError) IsUserError() {}
 
+func (*DuplicateConformanceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDuplicateConformance
+}
+
 func (e *DuplicateConformanceError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` repeats conformance to %s `%s`",
@@ -1605,6 +1781,10 @@ func (CyclicConformanceError) isSemanticError() {}
 
 func (CyclicConformanceError) IsUserError() {}
 
+func (CyclicConformanceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCyclicConformance
+}
+
 func (e CyclicConformanceError) Error() string {
 	return fmt.Sprintf(
 		"`%s` has a cyclic conformance to itself",
@@ -1626,6 +1806,10 @@ func (*MultipleInterfaceDefaultImplementationsError) isSemanticError() {}
 
 func (*MultipleInterfaceDefaultImplementationsError) IsUserError() {}
 
+func (*MultipleInterfaceDefaultImplementationsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMultipleInterfaceDefaultImplementations
+}
+
 func (e *MultipleInterfaceDefaultImplementationsError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` has multiple interface default implementations for function `%s`",
@@ -1649,6 +1833,10 @@ func (*SpecialFunctionDefaultImplementationError) isSemanticError() {}
 
 func (*SpecialFunctionDefaultImplementationError) IsUserError() {}
 
+func (*SpecialFunctionDefaultImplementationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeSpecialFunctionDefaultImplementation
+}
+
 func (e *SpecialFunctionDefaultImplementationError) Error() string {
 	return fmt.Sprintf(
 		"%s may not be defined as a default function on %s %s",
@@ -1683,6 +1871,10 @@ func (*InterfaceMemberConflictError) isSemanticError() {}
 
 func (*InterfaceMemberConflictError) IsUserError() {}
 
+func (*InterfaceMemberConflictError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInterfaceMemberConflict
+}
+
 func (e *InterfaceMemberConflictError) Error() string {
 	return fmt.Sprintf(
 		"`%s` %s of `%s` conflicts with a %s with the same name in `%s`",
@@ -1708,6 +1900,10 @@ func (*MissingConformanceError) isSemanticError() {}
 
 func (*MissingConformanceError) IsUserError() {}
 
+func (*MissingConformanceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingConformance
+}
+
 func (e *MissingConformanceError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is missing a declaration to required conformance to %s `%s`",
@@ -1732,6 +1928,10 @@ func (*UnresolvedImportError) isSemanticError() {}
 
 func (*UnresolvedImportError) IsUserError() {}
 
+func (*UnresolvedImportError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnresolvedImport
+}
+
 func (e *UnresolvedImportError) Error() string {
 	return fmt.Sprintf("import could not be resolved: %s", e.ImportLocation)
 }
@@ -1753,6 +1953,10 @@ func (*NotExportedError) isSemanticError() {}
 
 func (*NotExportedError) IsUserError() {}
 
+func (*NotExportedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNotExported
+}
+
 func (e *NotExportedError) Error() string {
 	return fmt.Sprintf(
 		"cannot find declaration `%s` in `%s`",
@@ -1831,6 +2035,10 @@ func (*AlwaysFailingNonResourceCastingTypeError) isSemanticError() {}
 
 func (*AlwaysFailingNonResourceCastingTypeError) IsUserError() {}
 
+func (*AlwaysFailingNonResourceCastingTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAlwaysFailingNonResourceCastingType
+}
+
 func (e *AlwaysFailingNonResourceCastingTypeError) Error() string {
 	return fmt.Sprintf(
 		"cast of value of resource-type `%s` to non-resource type `%s` will always fail",
@@ -1854,6 +2062,10 @@ func (*AlwaysFailingResourceCastingTypeError) isSemanticError() {}
 
 func (*AlwaysFailingResourceCastingTypeError) IsUserError() {}
 
+func (*AlwaysFailingResourceCastingTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAlwaysFailingResourceCastingType
+}
+
 func (e *AlwaysFailingResourceCastingTypeError) Error() string {
 	return fmt.Sprintf(
 		"cast of value of non-resource-type `%s` to resource type `%s` will always fail",
@@ -1876,6 +2088,10 @@ func (*UnsupportedOverloadingError) isSemanticError() {}
 
 func (*UnsupportedOverloadingError) IsUserError() {}
 
+func (*UnsupportedOverloadingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnsupportedOverloading
+}
+
 func (e *UnsupportedOverloadingError) Error() string {
 	return fmt.Sprintf(
 		"%s overloading is not supported yet",
@@ -1899,6 +2115,10 @@ func (*CompositeKindMismatchError) isSemanticError() {}
 
 func (*CompositeKindMismatchError) IsUserError() {}
 
+func (*CompositeKindMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCompositeKindMismatch
+}
+
 func (e *CompositeKindMismatchError) Error() string {
 	return "mismatched composite kinds"
 }
@@ -1926,6 +2146,10 @@ var _ errors.SecondaryError = &InvalidIntegerLiteralRangeError{}
 
 func (*InvalidIntegerLiteralRangeError) IsUserError() {}
 
+func (*InvalidIntegerLiteralRangeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidIntegerLiteralRange
+}
+
 func (*InvalidIntegerLiteralRangeError) isSemanticError() {}
 
 func (e *InvalidIntegerLiteralRangeError) Error() string {
@@ -1954,6 +2178,10 @@ func (*InvalidAddressLiteralError) isSemanticError() {}
 
 func (*InvalidAddressLiteralError) IsUserError() {}
 
+func (*InvalidAddressLiteralError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAddressLiteral
+}
+
 func (e *InvalidAddressLiteralError) Error() string {
 	return "invalid address"
 }
@@ -1977,6 +2205,10 @@ func (*InvalidFixedPointLiteralRangeError) isSemanticError() {}
 
 func (*InvalidFixedPointLiteralRangeError) IsUserError() {}
 
+func (*InvalidFixedPointLiteralRangeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidFixedPointLiteralRange
+}
+
 func (e *InvalidFixedPointLiteralRangeError) Error() string {
 	return "fixed-point literal out of range"
 }
@@ -2008,6 +2240,10 @@ func (*InvalidFixedPointLiteralScaleError) isSemanticError() {}
 
 func (*InvalidFixedPointLiteralScaleError) IsUserError() {}
 
+func (*InvalidFixedPointLiteralScaleError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidFixedPointLiteralScale
+}
+
 func (e *InvalidFixedPointLiteralScaleError) Error() string {
 	return "fixed-point literal scale out of range"
 }
@@ -2033,6 +2269,10 @@ func (*MissingReturnStatementError) isSemanticError() {}
 
 func (*MissingReturnStatementError) IsUserError() {}
 
+func (*MissingReturnStatementError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingReturnStatement
+}
+
 func (e *MissingReturnStatementError) Error() string {
 	return "missing return statement"
 }
@@ -2050,6 +2290,10 @@ func (*UnsupportedOptionalChainingAssignmentError) isSemanticError() {}
 
 func (*UnsupportedOptionalChainingAssignmentError) IsUserError() {}
 
+func (*UnsupportedOptionalChainingAssignmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnsupportedOptionalChainingAssignment
+}
+
 func (e *UnsupportedOptionalChainingAssignmentError) Error() string {
 	return "cannot assign to optional chaining expression"
 }
@@ -2067,6 +2311,10 @@ func (*MissingResourceAnnotationError) isSemanticError() {}
 
 func (*MissingResourceAnnotationError) IsUserError() {}
 
+func (*MissingResourceAnnotationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingResourceAnnotation
+}
+
 func (e *MissingResourceAnnotationError) Error() string {
 	return fmt.Sprintf(
 		"missing resource annotation: `%s`",
@@ -2087,6 +2335,10 @@ func (*InvalidNestedResourceMoveError) isSemanticError() {}
 
 func (*InvalidNestedResourceMoveError) IsUserError() {}
 
+func (*InvalidNestedResourceMoveError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNestedResourceMove
+}
+
 func (e *InvalidNestedResourceMoveError) Error() string {
 	return "cannot move nested resource"
 }
@@ -2104,6 +2356,10 @@ func (*InvalidInterfaceConditionResourceInvalidationError) isSemanticError() {}
 
 func (*InvalidInterfaceConditionResourceInvalidationError) IsUserError() {}
 
+func (*InvalidInterfaceConditionResourceInvalidationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidInterfaceConditionResourceInvalidation
+}
+
 func (e *InvalidInterfaceConditionResourceInvalidationError) Error() string {
 	return "cannot invalidate resource in interface condition"
 }
@@ -2121,6 +2377,10 @@ func (*InvalidResourceAnnotationError) isSemanticError() {}
 
 func (*InvalidResourceAnnotationError) IsUserError() {}
 
+func (*InvalidResourceAnnotationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceAnnotation
+}
+
 func (e *InvalidResourceAnnotationError) Error() string {
 	return fmt.Sprintf(
 		"invalid resource annotation: `%s`",
@@ -2144,6 +2404,10 @@ func (*InvalidInterfaceTypeError) isSemanticError() {}
 
 func (*InvalidInterfaceTypeError) IsUserError() {}
 
+func (*InvalidInterfaceTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidInterfaceType
+}
+
 func (e *InvalidInterfaceTypeError) Error() string {
 	return "invalid use of interface as type"
 }
@@ -2170,6 +2434,10 @@ func (*InvalidInterfaceDeclarationError) isSemanticError() {}
 
 func (*InvalidInterfaceDeclarationError) IsUserError() {}
 
+func (*InvalidInterfaceDeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidInterfaceDeclaration
+}
+
 func (e *InvalidInterfaceDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s interfaces are not supported",
@@ -2193,6 +2461,10 @@ func (*IncorrectTransferOperationError) isSemanticError() {}
 
 func (*IncorrectTransferOperationError) IsUserError() {}
 
+func (*IncorrectTransferOperationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeIncorrectTransferOperation
+}
+
 func (e *IncorrectTransferOperationError) Error() string {
 	return "incorrect transfer operation"
 }
@@ -2217,6 +2489,10 @@ func (*InvalidConstructionError) isSemanticError() {}
 
 func (*InvalidConstructionError) IsUserError() {}
 
+func (*InvalidConstructionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidConstruction
+}
+
 func (e *InvalidConstructionError) Error() string {
 	return "cannot create value: not a resource"
 }
@@ -2234,6 +2510,10 @@ func (*InvalidDestructionError) isSemanticError() {}
 
 func (*InvalidDestructionError) IsUserError() {}
 
+func (*InvalidDestructionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidDestruction
+}
+
 func (e *InvalidDestructionError) Error() string {
 	return "cannot destroy value: not a resource"
 }
@@ -2251,6 +2531,10 @@ func (*ResourceLossError) isSemanticError() {}
 
 func (*ResourceLossError) IsUserError() {}
 
+func (*ResourceLossError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceLoss
+}
+
 func (e *ResourceLossError) Error() string {
 	return "loss of resource"
 }
@@ -2270,6 +2554,10 @@ func (*ResourceUseAfterInvalidationError) isSemanticError() {}
 
 func (*ResourceUseAfterInvalidationError) IsUserError() {}
 
+func (*ResourceUseAfterInvalidationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceUseAfterInvalidation
+}
+
 func (e *ResourceUseAfterInvalidationError) Error() string {
 	return fmt.Sprintf(
 		"use of previously %s resource",
@@ -2329,6 +2617,10 @@ func (*MissingCreateError) isSemanticError() {}
 
 func (*MissingCreateError) IsUserError() {}
 
+func (*MissingCreateError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingCreate
+}
+
 func (e *MissingCreateError) Error() string {
 	return "cannot create resource"
 }
@@ -2350,6 +2642,10 @@ func (*MissingMoveOperationError) isSemanticError() {}
 
 func (*MissingMoveOperationError) IsUserError() {}
 
+func (*MissingMoveOperationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingMoveOperation
+}
+
 func (e *MissingMoveOperationError) Error() string {
 	return "missing move operation: `<-`"
 }
@@ -2376,6 +2672,10 @@ func (*InvalidMoveOperationError) isSemanticError() {}
 
 func (*InvalidMoveOperationError) IsUserError() {}
 
+func (*InvalidMoveOperationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMoveOperation
+}
+
 func (e *InvalidMoveOperationError) Error() string {
 	return "invalid move operation for non-resource"
 }
@@ -2398,6 +2698,10 @@ func (*ResourceCapturingError) isSemanticError() {}
 
 func (*ResourceCapturingError) IsUserError() {}
 
+func (*ResourceCapturingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceCapturing
+}
+
 func (e *ResourceCapturingError) Error() string {
 	return fmt.Sprintf("cannot capture resource in closure: `%s`", e.Name)
 }
@@ -2426,6 +2730,10 @@ func (*InvalidResourceFieldError) isSemanticError() {}
 
 func (*InvalidResourceFieldError) IsUserError() {}
 
+func (*InvalidResourceFieldError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceField
+}
+
 func (e *InvalidResourceFieldError) Error() string {
 	return fmt.Sprintf(
 		"invalid resource field in %s: `%s`",
@@ -2458,6 +2766,10 @@ func (*InvalidSwapExpressionError) isSemanticError() {}
 
 func (*InvalidSwapExpressionError) IsUserError() {}
 
+func (*InvalidSwapExpressionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidSwapExpression
+}
+
 func (e *InvalidSwapExpressionError) Error() string {
 	return fmt.Sprintf(
 		"invalid %s-hand side of swap",
@@ -2483,6 +2795,10 @@ func (*InvalidEventParameterTypeError) isSemanticError() {}
 
 func (*InvalidEventParameterTypeError) IsUserError() {}
 
+func (*InvalidEventParameterTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEventParameterType
+}
+
 func (e *InvalidEventParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"unsupported event parameter type: `%s`",
@@ -2503,6 +2819,10 @@ func (*InvalidEventUsageError) isSemanticError() {}
 
 func (*InvalidEventUsageError) IsUserError() {}
 
+func (*InvalidEventUsageError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEventUsage
+}
+
 func (e *InvalidEventUsageError) Error() string {
 	return "events can only be invoked in an `emit` statement"
 }
@@ -2521,6 +2841,10 @@ func (*EmitNonEventError) isSemanticError() {}
 
 func (*EmitNonEventError) IsUserError() {}
 
+func (*EmitNonEventError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeEmitNonEvent
+}
+
 func (e *EmitNonEventError) Error() string {
 	return fmt.Sprintf(
 		"cannot emit non-event type: `%s`",
@@ -2541,6 +2865,10 @@ func (*EmitDefaultDestroyEventError) isSemanticError() {}
 
 func (*EmitDefaultDestroyEventError) IsUserError() {}
 
+func (*EmitDefaultDestroyEventError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeEmitDefaultDestroyEvent
+}
+
 func (e *EmitDefaultDestroyEventError) Error() string {
 	return "default destruction events may not be explicitly emitted"
 }
@@ -2559,6 +2887,10 @@ func (*EmitImportedEventError) isSemanticError() {}
 
 func (*EmitImportedEventError) IsUserError() {}
 
+func (*EmitImportedEventError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeEmitImportedEvent
+}
+
 func (e *EmitImportedEventError) Error() string {
 	return fmt.Sprintf(
 		"cannot emit imported event type: `%s`",
@@ -2580,6 +2912,10 @@ func (*InvalidResourceAssignmentError) isSemanticError() {}
 
 func (*InvalidResourceAssignmentError) IsUserError() {}
 
+func (*InvalidResourceAssignmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceAssignment
+}
+
 func (e *InvalidResourceAssignmentError) Error() string {
 	return "cannot assign to resource-typed target"
 }
@@ -2604,6 +2940,10 @@ func (*ResourceFieldNotInvalidatedError) isSemanticError() {}
 
 func (*ResourceFieldNotInvalidatedError) IsUserError() {}
 
+func (*ResourceFieldNotInvalidatedError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceFieldNotInvalidated
+}
+
 func (e *ResourceFieldNotInvalidatedError) Error() string {
 	return fmt.Sprintf(
 		"field `%s` of type `%s` is not invalidated (moved or destroyed)",
@@ -2639,6 +2979,10 @@ func (*UninitializedFieldAccessError) isSemanticError() {}
 
 func (*UninitializedFieldAccessError) IsUserError() {}
 
+func (*UninitializedFieldAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUninitializedFieldAccess
+}
+
 func (e *UninitializedFieldAccessError) Error() string {
 	return fmt.Sprintf(
 		"cannot access uninitialized field: `%s`",
@@ -2669,6 +3013,10 @@ func (*UnreachableStatementError) isSemanticError() {}
 
 func (*UnreachableStatementError) IsUserError() {}
 
+func (*UnreachableStatementError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnreachableStatement
+}
+
 func (e *UnreachableStatementError) Error() string {
 	return "unreachable statement"
 }
@@ -2691,6 +3039,10 @@ func (*UninitializedUseError) isSemanticError() {}
 
 func (*UninitializedUseError) IsUserError() {}
 
+func (*UninitializedUseError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUninitializedUse
+}
+
 func (e *UninitializedUseError) Error() string {
 	return fmt.Sprintf(
 		"cannot use incompletely initialized value: `%s`",
@@ -2722,6 +3074,10 @@ func (*InvalidResourceArrayMemberError) isSemanticError() {}
 
 func (*InvalidResourceArrayMemberError) IsUserError() {}
 
+func (*InvalidResourceArrayMemberError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceArrayMember
+}
+
 func (e *InvalidResourceArrayMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource arrays",
@@ -2745,6 +3101,10 @@ func (*InvalidResourceDictionaryMemberError) isSemanticError() {}
 
 func (*InvalidResourceDictionaryMemberError) IsUserError() {}
 
+func (*InvalidResourceDictionaryMemberError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceDictionaryMember
+}
+
 func (e *InvalidResourceDictionaryMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource dictionaries",
@@ -2768,6 +3128,10 @@ func (*InvalidResourceOptionalMemberError) isSemanticError() {}
 
 func (*InvalidResourceOptionalMemberError) IsUserError() {}
 
+func (*InvalidResourceOptionalMemberError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceOptionalMember
+}
+
 func (e *InvalidResourceOptionalMemberError) Error() string {
 	return fmt.Sprintf(
 		"%s `%s` is not available for resource optionals",
@@ -2791,6 +3155,10 @@ func (*NonReferenceTypeReferenceError) isSemanticError() {}
 
 func (*NonReferenceTypeReferenceError) IsUserError() {}
 
+func (*NonReferenceTypeReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonReferenceTypeReference
+}
+
 func (e *NonReferenceTypeReferenceError) Error() string {
 	return "cannot create reference"
 }
@@ -2817,6 +3185,10 @@ func (*ReferenceToAnOptionalError) isSemanticError() {}
 
 func (*ReferenceToAnOptionalError) IsUserError() {}
 
+func (*ReferenceToAnOptionalError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeReferenceToAnOptional
+}
+
 func (e *ReferenceToAnOptionalError) Error() string {
 	return "cannot create reference"
 }
@@ -2852,6 +3224,10 @@ func (*InvalidResourceCreationError) isSemanticError() {}
 
 func (*InvalidResourceCreationError) IsUserError() {}
 
+func (*InvalidResourceCreationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceCreation
+}
+
 func (e *InvalidResourceCreationError) Error() string {
 	return fmt.Sprintf(
 		"cannot create resource type outside of containing contract: `%s`",
@@ -2874,6 +3250,10 @@ func (*NonResourceTypeError) isSemanticError() {}
 
 func (*NonResourceTypeError) IsUserError() {}
 
+func (*NonResourceTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNonResourceType
+}
+
 func (e *NonResourceTypeError) Error() string {
 	return "invalid type"
 }
@@ -2898,6 +3278,10 @@ func (*InvalidAssignmentTargetError) isSemanticError() {}
 
 func (*InvalidAssignmentTargetError) IsUserError() {}
 
+func (*InvalidAssignmentTargetError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAssignmentTarget
+}
+
 func (e *InvalidAssignmentTargetError) Error() string {
 	return "cannot assign to unassignable expression"
 }
@@ -2915,6 +3299,10 @@ func (*ResourceMethodBindingError) isSemanticError() {}
 
 func (*ResourceMethodBindingError) IsUserError() {}
 
+func (*ResourceMethodBindingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResourceMethodBinding
+}
+
 func (e *ResourceMethodBindingError) Error() string {
 	return "cannot create bound method for resource"
 }
@@ -2933,6 +3321,10 @@ func (*InvalidDictionaryKeyTypeError) isSemanticError() {}
 
 func (*InvalidDictionaryKeyTypeError) IsUserError() {}
 
+func (*InvalidDictionaryKeyTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidDictionaryKeyType
+}
+
 func (e *InvalidDictionaryKeyTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot use type as dictionary key type: `%s`",
@@ -2953,6 +3345,10 @@ func (*MissingFunctionBodyError) isSemanticError() {}
 
 func (*MissingFunctionBodyError) IsUserError() {}
 
+func (*MissingFunctionBodyError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingFunctionBody
+}
+
 func (e *MissingFunctionBodyError) Error() string {
 	return "missing function implementation"
 }
@@ -2979,6 +3375,10 @@ func (*InvalidOptionalChainingError) isSemanticError() {}
 
 func (*InvalidOptionalChainingError) IsUserError() {}
 
+func (*InvalidOptionalChainingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidOptionalChaining
+}
+
 func (e *InvalidOptionalChainingError) Error() string {
 	return fmt.Sprintf(
 		"cannot use optional chaining: type `%s` is not optional",
@@ -3004,6 +3404,10 @@ func (*InvalidAccessError) isSemanticError() {}
 
 func (*InvalidAccessError) IsUserError() {}
 
+func (*InvalidAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAccess
+}
+
 func (e *InvalidAccessError) Error() string {
 	var possessedDescription string
 	if e.PossessedAccess != nil {
@@ -3113,6 +3517,10 @@ func (*InvalidAssignmentAccessError) isSemanticError() {}
 
 func (*InvalidAssignmentAccessError) IsUserError() {}
 
+func (*InvalidAssignmentAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAssignmentAccess
+}
+
 func (e *InvalidAssignmentAccessError) Error() string {
 	return fmt.Sprintf(
 		"cannot assign to `%s`: %s has `%s` access",
@@ -3145,6 +3553,10 @@ func (*UnauthorizedReferenceAssignmentError) isSemanticError() {}
 
 func (*UnauthorizedReferenceAssignmentError) IsUserError() {}
 
+func (*UnauthorizedReferenceAssignmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnauthorizedReferenceAssignment
+}
+
 func (e *UnauthorizedReferenceAssignmentError) Error() string {
 	var foundAccess string
 	if e.FoundAccess == UnauthorizedAccess {
@@ -3184,6 +3596,10 @@ func (*InvalidCharacterLiteralError) isSemanticError() {}
 
 func (*InvalidCharacterLiteralError) IsUserError() {}
 
+func (*InvalidCharacterLiteralError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidCharacterLiteral
+}
+
 func (e *InvalidCharacterLiteralError) Error() string {
 	return "character literal has invalid length"
 }
@@ -3207,6 +3623,10 @@ func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) isSemanticErr
 
 func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) IsUserError() {}
 
+func (*InvalidFailableResourceDowncastOutsideOptionalBindingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidFailableResourceDowncastOutsideOptionalBinding
+}
+
 func (e *InvalidFailableResourceDowncastOutsideOptionalBindingError) Error() string {
 	return "cannot failably downcast resource type outside of optional binding"
 }
@@ -3225,6 +3645,10 @@ func (*InvalidNonIdentifierFailableResourceDowncast) isSemanticError() {}
 
 func (*InvalidNonIdentifierFailableResourceDowncast) IsUserError() {}
 
+func (*InvalidNonIdentifierFailableResourceDowncast) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNonIdentifierFailableResourceDowncast
+}
+
 func (e *InvalidNonIdentifierFailableResourceDowncast) Error() string {
 	return "cannot failably downcast non-identifier resource"
 }
@@ -3246,6 +3670,10 @@ func (*ReadOnlyTargetAssignmentError) isSemanticError() {}
 
 func (*ReadOnlyTargetAssignmentError) IsUserError() {}
 
+func (*ReadOnlyTargetAssignmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeReadOnlyTargetAssignment
+}
+
 func (e *ReadOnlyTargetAssignmentError) Error() string {
 	return "cannot assign to read-only target"
 }
@@ -3265,6 +3693,10 @@ func (*InvalidTransactionBlockError) isSemanticError() {}
 
 func (*InvalidTransactionBlockError) IsUserError() {}
 
+func (*InvalidTransactionBlockError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTransactionBlock
+}
+
 func (e *InvalidTransactionBlockError) Error() string {
 	return "invalid transaction block"
 }
@@ -3299,6 +3731,10 @@ func (*TransactionMissingPrepareError) isSemanticError() {}
 
 func (*TransactionMissingPrepareError) IsUserError() {}
 
+func (*TransactionMissingPrepareError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTransactionMissingPrepare
+}
+
 func (e *TransactionMissingPrepareError) Error() string {
 	return fmt.Sprintf(
 		"transaction missing prepare function for field `%s`",
@@ -3329,6 +3765,10 @@ func (*InvalidResourceTransactionParameterError) isSemanticError() {}
 
 func (*InvalidResourceTransactionParameterError) IsUserError() {}
 
+func (*InvalidResourceTransactionParameterError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidResourceTransactionParameter
+}
+
 func (e *InvalidResourceTransactionParameterError) Error() string {
 	return fmt.Sprintf(
 		"transaction parameter must not be resource type: `%s`",
@@ -3350,6 +3790,10 @@ func (*InvalidNonImportableTransactionParameterTypeError) isSemanticError() {}
 
 func (*InvalidNonImportableTransactionParameterTypeError) IsUserError() {}
 
+func (*InvalidNonImportableTransactionParameterTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNonImportableTransactionParameterType
+}
+
 func (e *InvalidNonImportableTransactionParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"transaction parameter must be importable: `%s`",
@@ -3372,6 +3816,10 @@ func (*InvalidTransactionFieldAccessModifierError) isSemanticError() {}
 
 func (*InvalidTransactionFieldAccessModifierError) IsUserError() {}
 
+func (*InvalidTransactionFieldAccessModifierError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTransactionFieldAccessModifier
+}
+
 func (e *InvalidTransactionFieldAccessModifierError) Error() string {
 	return fmt.Sprintf(
 		"access modifier not allowed for transaction field `%s`: `%s`",
@@ -3403,6 +3851,10 @@ func (*InvalidTransactionPrepareParameterTypeError) isSemanticError() {}
 
 func (*InvalidTransactionPrepareParameterTypeError) IsUserError() {}
 
+func (*InvalidTransactionPrepareParameterTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTransactionPrepareParameterType
+}
+
 func (e *InvalidTransactionPrepareParameterTypeError) Error() string {
 	return fmt.Sprintf(
 		"prepare parameter must be subtype of `%s`, not `%s`",
@@ -3426,6 +3878,10 @@ func (*InvalidNestedDeclarationError) isSemanticError() {}
 
 func (*InvalidNestedDeclarationError) IsUserError() {}
 
+func (*InvalidNestedDeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNestedDeclaration
+}
+
 func (e *InvalidNestedDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s declarations cannot be nested inside %s declarations",
@@ -3447,6 +3903,10 @@ func (*InvalidNestedTypeError) isSemanticError() {}
 
 func (*InvalidNestedTypeError) IsUserError() {}
 
+func (*InvalidNestedTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNestedType
+}
+
 func (e *InvalidNestedTypeError) Error() string {
 	return fmt.Sprintf(
 		"type does not support nested types: `%s`",
@@ -3476,6 +3936,10 @@ func (*InvalidEnumCaseError) isSemanticError() {}
 
 func (*InvalidEnumCaseError) IsUserError() {}
 
+func (*InvalidEnumCaseError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEnumCase
+}
+
 func (e *InvalidEnumCaseError) Error() string {
 	return fmt.Sprintf(
 		"%s declaration does not allow enum cases",
@@ -3497,6 +3961,10 @@ func (*InvalidNonEnumCaseError) isSemanticError() {}
 
 func (*InvalidNonEnumCaseError) IsUserError() {}
 
+func (*InvalidNonEnumCaseError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNonEnumCase
+}
+
 func (e *InvalidNonEnumCaseError) Error() string {
 	return fmt.Sprintf(
 		"%s declaration only allows enum cases",
@@ -3520,6 +3988,10 @@ func (*DeclarationKindMismatchError) isSemanticError() {}
 
 func (*DeclarationKindMismatchError) IsUserError() {}
 
+func (*DeclarationKindMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDeclarationKindMismatch
+}
+
 func (e *DeclarationKindMismatchError) Error() string {
 	return "mismatched declarations"
 }
@@ -3546,6 +4018,10 @@ func (*InvalidTopLevelDeclarationError) isSemanticError() {}
 
 func (*InvalidTopLevelDeclarationError) IsUserError() {}
 
+func (*InvalidTopLevelDeclarationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTopLevelDeclaration
+}
+
 func (e *InvalidTopLevelDeclarationError) Error() string {
 	return fmt.Sprintf(
 		"%s declarations are not valid at the top-level",
@@ -3567,6 +4043,10 @@ func (*InvalidSelfInvalidationError) isSemanticError() {}
 
 func (*InvalidSelfInvalidationError) IsUserError() {}
 
+func (*InvalidSelfInvalidationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidSelfInvalidation
+}
+
 func (e *InvalidSelfInvalidationError) Error() string {
 	var action string
 	switch e.InvalidationKind {
@@ -3602,6 +4082,10 @@ func (*InvalidMoveError) isSemanticError() {}
 
 func (*InvalidMoveError) IsUserError() {}
 
+func (*InvalidMoveError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMove
+}
+
 func (e *InvalidMoveError) Error() string {
 	return fmt.Sprintf(
 		"cannot move %s: `%s`",
@@ -3635,6 +4119,10 @@ func (*ConstantSizedArrayLiteralSizeError) isSemanticError() {}
 
 func (*ConstantSizedArrayLiteralSizeError) IsUserError() {}
 
+func (*ConstantSizedArrayLiteralSizeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeConstantSizedArrayLiteralSize
+}
+
 func (e *ConstantSizedArrayLiteralSizeError) Error() string {
 	return "incorrect number of array literal elements"
 }
@@ -3661,6 +4149,10 @@ func (*InvalidIntersectedTypeError) isSemanticError() {}
 
 func (*InvalidIntersectedTypeError) IsUserError() {}
 
+func (*InvalidIntersectedTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidIntersectedType
+}
+
 func (e *InvalidIntersectedTypeError) Error() string {
 	return fmt.Sprintf(
 		"intersection type with invalid non-interface type: `%s`",
@@ -3683,6 +4175,10 @@ func (*IntersectionCompositeKindMismatchError) isSemanticError() {}
 
 func (*IntersectionCompositeKindMismatchError) IsUserError() {}
 
+func (*IntersectionCompositeKindMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeIntersectionCompositeKindMismatch
+}
+
 func (e *IntersectionCompositeKindMismatchError) Error() string {
 	return fmt.Sprintf(
 		"interface kind %s does not match previous interface kind %s",
@@ -3705,6 +4201,10 @@ func (*InvalidIntersectionTypeDuplicateError) isSemanticError() {}
 
 func (*InvalidIntersectionTypeDuplicateError) IsUserError() {}
 
+func (*InvalidIntersectionTypeDuplicateError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidIntersectionTypeDuplicate
+}
+
 func (e *InvalidIntersectionTypeDuplicateError) Error() string {
 	return fmt.Sprintf(
 		"duplicate intersected type: `%s`",
@@ -3728,6 +4228,10 @@ func (*IntersectionMemberClashError) isSemanticError() {}
 
 func (*IntersectionMemberClashError) IsUserError() {}
 
+func (*IntersectionMemberClashError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeIntersectionMemberClash
+}
+
 func (e *IntersectionMemberClashError) Error() string {
 	return fmt.Sprintf(
 		"intersected type has member clash with previous intersected type `%s`: %s",
@@ -3749,6 +4253,10 @@ func (*AmbiguousIntersectionTypeError) isSemanticError() {}
 
 func (*AmbiguousIntersectionTypeError) IsUserError() {}
 
+func (*AmbiguousIntersectionTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAmbiguousIntersectionType
+}
+
 func (e *AmbiguousIntersectionTypeError) Error() string {
 	return "ambiguous intersection type"
 }
@@ -3777,6 +4285,10 @@ func (*InvalidPathDomainError) isSemanticError() {}
 
 func (*InvalidPathDomainError) IsUserError() {}
 
+func (*InvalidPathDomainError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidPathDomain
+}
+
 func (e *InvalidPathIdentifierError) Error() string {
 	return fmt.Sprintf("invalid path identifier %s", e.ActualIdentifier)
 }
@@ -3815,6 +4327,10 @@ func (e *InvalidTypeArgumentCountError) isSemanticError() {}
 
 func (*InvalidTypeArgumentCountError) IsUserError() {}
 
+func (*InvalidTypeArgumentCountError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTypeArgumentCount
+}
+
 func (e *InvalidTypeArgumentCountError) Error() string {
 	return "incorrect number of type arguments"
 }
@@ -3841,6 +4357,10 @@ func (e *MissingTypeArgumentError) isSemanticError() {}
 
 func (*MissingTypeArgumentError) IsUserError() {}
 
+func (*MissingTypeArgumentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingTypeArgument
+}
+
 func (e *MissingTypeArgumentError) Error() string {
 	return fmt.Sprintf("non-optional type argument %s missing", e.TypeArgumentName)
 }
@@ -3860,6 +4380,10 @@ func (*InvalidTypeArgumentError) isSemanticError() {}
 
 func (*InvalidTypeArgumentError) IsUserError() {}
 
+func (*InvalidTypeArgumentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTypeArgument
+}
+
 func (e *InvalidTypeArgumentError) Error() string {
 	return fmt.Sprintf("type argument %s invalid", e.TypeArgumentName)
 }
@@ -3882,6 +4406,10 @@ func (e *TypeParameterTypeInferenceError) isSemanticError() {}
 
 func (*TypeParameterTypeInferenceError) IsUserError() {}
 
+func (*TypeParameterTypeInferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeParameterTypeInference
+}
+
 func (e *TypeParameterTypeInferenceError) Error() string {
 	return fmt.Sprintf(
 		"cannot infer type parameter: `%s`",
@@ -3905,6 +4433,10 @@ func (e *InvalidConstantSizedTypeBaseError) isSemanticError() {}
 
 func (*InvalidConstantSizedTypeBaseError) IsUserError() {}
 
+func (*InvalidConstantSizedTypeBaseError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidConstantSizedTypeBase
+}
+
 func (e *InvalidConstantSizedTypeBaseError) Error() string {
 	return "invalid base for constant sized type size"
 }
@@ -3934,6 +4466,10 @@ func (*InvalidConstantSizedTypeSizeError) isSemanticError() {}
 
 func (*InvalidConstantSizedTypeSizeError) IsUserError() {}
 
+func (*InvalidConstantSizedTypeSizeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidConstantSizedTypeSize
+}
+
 func (e *InvalidConstantSizedTypeSizeError) Error() string {
 	return "invalid size for constant sized type"
 }
@@ -3960,6 +4496,10 @@ func (*UnsupportedResourceForLoopError) isSemanticError() {}
 
 func (*UnsupportedResourceForLoopError) IsUserError() {}
 
+func (*UnsupportedResourceForLoopError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnsupportedResourceForLoop
+}
+
 func (e *UnsupportedResourceForLoopError) Error() string {
 	return "cannot loop over resources"
 }
@@ -3981,6 +4521,10 @@ func (*TypeParameterTypeMismatchError) isSemanticError() {}
 
 func (*TypeParameterTypeMismatchError) IsUserError() {}
 
+func (*TypeParameterTypeMismatchError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeParameterTypeMismatch
+}
+
 func (e *TypeParameterTypeMismatchError) Error() string {
 	return "mismatched types for type parameter"
 }
@@ -4014,6 +4558,10 @@ func (*UnparameterizedTypeInstantiationError) isSemanticError() {}
 
 func (*UnparameterizedTypeInstantiationError) IsUserError() {}
 
+func (*UnparameterizedTypeInstantiationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnparameterizedTypeInstantiation
+}
+
 func (e *UnparameterizedTypeInstantiationError) Error() string {
 	return "cannot instantiate non-parameterized type"
 }
@@ -4039,6 +4587,10 @@ func (*TypeAnnotationRequiredError) isSemanticError() {}
 
 func (*TypeAnnotationRequiredError) IsUserError() {}
 
+func (*TypeAnnotationRequiredError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeTypeAnnotationRequired
+}
+
 func (e *TypeAnnotationRequiredError) Error() string {
 	if e.Cause != "" {
 		return fmt.Sprintf(
@@ -4071,6 +4623,10 @@ func (*CyclicImportsError) isSemanticError() {}
 
 func (*CyclicImportsError) IsUserError() {}
 
+func (*CyclicImportsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCyclicImports
+}
+
 func (e *CyclicImportsError) Error() string {
 	return fmt.Sprintf("cyclic import of `%s`", e.Location)
 }
@@ -4088,6 +4644,10 @@ func (*SwitchDefaultPositionError) isSemanticError() {}
 
 func (*SwitchDefaultPositionError) IsUserError() {}
 
+func (*SwitchDefaultPositionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeSwitchDefaultPosition
+}
+
 func (e *SwitchDefaultPositionError) Error() string {
 	return "the 'default' case must appear at the end of a 'switch' statement"
 }
@@ -4105,6 +4665,10 @@ func (*MissingSwitchCaseStatementsError) isSemanticError() {}
 
 func (*MissingSwitchCaseStatementsError) IsUserError() {}
 
+func (*MissingSwitchCaseStatementsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingSwitchCaseStatements
+}
+
 func (e *MissingSwitchCaseStatementsError) Error() string {
 	return "switch cases must have at least one statement"
 }
@@ -4127,6 +4691,10 @@ var _ errors.UserError = &MissingEntryPointError{}
 
 func (*MissingEntryPointError) IsUserError() {}
 
+func (*MissingEntryPointError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMissingEntryPoint
+}
+
 func (e *MissingEntryPointError) Error() string {
 	return fmt.Sprintf("missing entry point: expected '%s'", e.Expected)
 }
@@ -4141,6 +4709,10 @@ var _ errors.UserError = &InvalidEntryPointTypeError{}
 
 func (*InvalidEntryPointTypeError) IsUserError() {}
 
+func (*InvalidEntryPointTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEntryPointType
+}
+
 func (e *InvalidEntryPointTypeError) Error() string {
 	return fmt.Sprintf(
 		"invalid entry point type: `%s`",
@@ -4161,6 +4733,10 @@ var _ errors.UserError = &PurityError{}
 
 func (*PurityError) IsUserError() {}
 
+func (*PurityError) ErrorCode() errors.ErrorCode {
+	return ErrorCodePurity
+}
+
 func (*PurityError) isSemanticError() {}
 
 // InvalidatedResourceReferenceError
@@ -4177,6 +4753,10 @@ func (*InvalidatedResourceReferenceError) isSemanticError() {}
 
 func (*InvalidatedResourceReferenceError) IsUserError() {}
 
+func (*InvalidatedResourceReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidatedResourceReference
+}
+
 func (e *InvalidatedResourceReferenceError) Error() string {
 	return "invalid reference: referenced resource may have been moved or destroyed"
 }
@@ -4200,6 +4780,10 @@ func (*InvalidEntitlementAccessError) isSemanticError() {}
 
 func (*InvalidEntitlementAccessError) IsUserError() {}
 
+func (*InvalidEntitlementAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEntitlementAccess
+}
+
 func (e *InvalidEntitlementAccessError) Error() string {
 	return "only struct or resource members may be declared with entitlement access"
 }
@@ -4225,6 +4809,10 @@ func (*InvalidEntitlementMappingTypeError) isSemanticError() {}
 
 func (*InvalidEntitlementMappingTypeError) IsUserError() {}
 
+func (*InvalidEntitlementMappingTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEntitlementMappingType
+}
+
 func (e *InvalidEntitlementMappingTypeError) Error() string {
 	return fmt.Sprintf("`%s` is not an entitlement map type", e.Type.QualifiedString())
 }
@@ -4253,6 +4841,10 @@ func (*InvalidNonEntitlementTypeInMapError) isSemanticError() {}
 
 func (*InvalidNonEntitlementTypeInMapError) IsUserError() {}
 
+func (*InvalidNonEntitlementTypeInMapError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNonEntitlementTypeInMap
+}
+
 func (e *InvalidNonEntitlementTypeInMapError) Error() string {
 	return "cannot use non-entitlement type in entitlement mapping"
 }
@@ -4277,6 +4869,10 @@ func (*InvalidMappingAccessError) isSemanticError() {}
 
 func (*InvalidMappingAccessError) IsUserError() {}
 
+func (*InvalidMappingAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMappingAccess
+}
+
 func (e *InvalidMappingAccessError) Error() string {
 	return "access(mapping ...) may only be used in structs and resources"
 }
@@ -4301,6 +4897,10 @@ func (*InvalidMappingAccessMemberTypeError) isSemanticError() {}
 
 func (*InvalidMappingAccessMemberTypeError) IsUserError() {}
 
+func (*InvalidMappingAccessMemberTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMappingAccessMemberType
+}
+
 func (e *InvalidMappingAccessMemberTypeError) Error() string {
 	return "invalid type for access(mapping ...) declaration"
 }
@@ -4325,6 +4925,10 @@ func (*InvalidNonEntitlementAccessError) isSemanticError() {}
 
 func (*InvalidNonEntitlementAccessError) IsUserError() {}
 
+func (*InvalidNonEntitlementAccessError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidNonEntitlementAccess
+}
+
 func (e *InvalidNonEntitlementAccessError) Error() string {
 	return "only entitlements may be used in access modifiers"
 }
@@ -4342,6 +4946,10 @@ func (*MappingAccessMissingKeywordError) isSemanticError() {}
 
 func (*MappingAccessMissingKeywordError) IsUserError() {}
 
+func (*MappingAccessMissingKeywordError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeMappingAccessMissingKeyword
+}
+
 func (e *MappingAccessMissingKeywordError) Error() string {
 	return "entitlement mapping access modifiers require the `mapping` keyword preceding the name of the map"
 }
@@ -4362,6 +4970,10 @@ func (*DirectEntitlementAnnotationError) isSemanticError() {}
 
 func (*DirectEntitlementAnnotationError) IsUserError() {}
 
+func (*DirectEntitlementAnnotationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDirectEntitlementAnnotation
+}
+
 func (e *DirectEntitlementAnnotationError) Error() string {
 	return "cannot use an entitlement type outside of an `access` declaration or `auth` modifier"
 }
@@ -4380,6 +4992,10 @@ func (*UnrepresentableEntitlementMapOutputError) isSemanticError() {}
 
 func (*UnrepresentableEntitlementMapOutputError) IsUserError() {}
 
+func (*UnrepresentableEntitlementMapOutputError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnrepresentableEntitlementMapOutput
+}
+
 func (e *UnrepresentableEntitlementMapOutputError) Error() string {
 	return fmt.Sprintf(
 		"cannot map `%s` through `%s` because the output is unrepresentable",
@@ -4417,6 +5033,10 @@ func (*InvalidEntitlementMappingInclusionError) isSemanticError() {}
 
 func (*InvalidEntitlementMappingInclusionError) IsUserError() {}
 
+func (*InvalidEntitlementMappingInclusionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidEntitlementMappingInclusion
+}
+
 func (e *InvalidEntitlementMappingInclusionError) Error() string {
 	return fmt.Sprintf(
 		"cannot include `%s` in the definition of `%s`, as it is not an entitlement map",
@@ -4439,6 +5059,10 @@ func (*DuplicateEntitlementMappingInclusionError) isSemanticError() {}
 
 func (*DuplicateEntitlementMappingInclusionError) IsUserError() {}
 
+func (*DuplicateEntitlementMappingInclusionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDuplicateEntitlementMappingInclusion
+}
+
 func (e *DuplicateEntitlementMappingInclusionError) Error() string {
 	return fmt.Sprintf(
 		"`%s` is already included in the definition of `%s`",
@@ -4461,6 +5085,10 @@ func (*CyclicEntitlementMappingError) isSemanticError() {}
 
 func (*CyclicEntitlementMappingError) IsUserError() {}
 
+func (*CyclicEntitlementMappingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeCyclicEntitlementMapping
+}
+
 func (e *CyclicEntitlementMappingError) Error() string {
 	return fmt.Sprintf(
 		"cannot include `%s` in the definition of `%s`, as it would create a cyclical mapping",
@@ -4484,6 +5112,10 @@ func (*InvalidBaseTypeError) isSemanticError() {}
 
 func (*InvalidBaseTypeError) IsUserError() {}
 
+func (*InvalidBaseTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidBaseType
+}
+
 func (e *InvalidBaseTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot use `%s` as the base type for attachment `%s`",
@@ -4505,6 +5137,10 @@ func (*InvalidAttachmentAnnotationError) isSemanticError() {}
 
 func (*InvalidAttachmentAnnotationError) IsUserError() {}
 
+func (*InvalidAttachmentAnnotationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAttachmentAnnotation
+}
+
 func (e *InvalidAttachmentAnnotationError) Error() string {
 	return "cannot refer directly to attachment type"
 }
@@ -4522,6 +5158,10 @@ func (*InvalidAttachmentUsageError) isSemanticError() {}
 
 func (*InvalidAttachmentUsageError) IsUserError() {}
 
+func (*InvalidAttachmentUsageError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAttachmentUsage
+}
+
 func (*InvalidAttachmentUsageError) Error() string {
 	return "cannot construct attachment outside of an `attach` expression"
 }
@@ -4540,6 +5180,10 @@ func (*AttachNonAttachmentError) isSemanticError() {}
 
 func (*AttachNonAttachmentError) IsUserError() {}
 
+func (*AttachNonAttachmentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAttachNonAttachment
+}
+
 func (e *AttachNonAttachmentError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach non-attachment type: `%s`",
@@ -4560,6 +5204,10 @@ func (*AttachToInvalidTypeError) isSemanticError() {}
 
 func (*AttachToInvalidTypeError) IsUserError() {}
 
+func (*AttachToInvalidTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeAttachToInvalidType
+}
+
 func (e *AttachToInvalidTypeError) Error() string {
 	return fmt.Sprintf(
 		"cannot attach attachment to type `%s`, as it is not valid for this base type",
@@ -4581,6 +5229,10 @@ func (*InvalidAttachmentRemoveError) isSemanticError() {}
 
 func (*InvalidAttachmentRemoveError) IsUserError() {}
 
+func (*InvalidAttachmentRemoveError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAttachmentRemove
+}
+
 func (e *InvalidAttachmentRemoveError) Error() string {
 	if e.BaseType == nil {
 		return fmt.Sprintf(
@@ -4609,6 +5261,10 @@ func (*InvalidTypeIndexingError) isSemanticError() {}
 
 func (*InvalidTypeIndexingError) IsUserError() {}
 
+func (*InvalidTypeIndexingError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTypeIndexing
+}
+
 func (e *InvalidTypeIndexingError) Error() string {
 	return fmt.Sprintf(
 		"cannot index `%s` with `%s`, as it is not an valid type index for this type",
@@ -4632,6 +5288,10 @@ func (*InvalidAttachmentEntitlementError) isSemanticError() {}
 
 func (*InvalidAttachmentEntitlementError) IsUserError() {}
 
+func (*InvalidAttachmentEntitlementError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidAttachmentEntitlement
+}
+
 func (e *InvalidAttachmentEntitlementError) Error() string {
 	entitlementDescription := "entitlements"
 	if e.InvalidEntitlement != nil {
@@ -4672,6 +5332,10 @@ func (*DefaultDestroyEventInNonResourceError) isSemanticError() {}
 
 func (*DefaultDestroyEventInNonResourceError) IsUserError() {}
 
+func (*DefaultDestroyEventInNonResourceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDefaultDestroyEventInNonResource
+}
+
 func (e *DefaultDestroyEventInNonResourceError) Error() string {
 	return fmt.Sprintf(
 		"cannot declare default destruction event in %s",
@@ -4702,6 +5366,10 @@ func (*DefaultDestroyInvalidArgumentError) isSemanticError() {}
 
 func (*DefaultDestroyInvalidArgumentError) IsUserError() {}
 
+func (*DefaultDestroyInvalidArgumentError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDefaultDestroyInvalidArgument
+}
+
 func (e *DefaultDestroyInvalidArgumentError) Error() string {
 	return "Invalid default destroy event argument"
 }
@@ -4734,6 +5402,10 @@ func (*DefaultDestroyInvalidParameterError) isSemanticError() {}
 
 func (*DefaultDestroyInvalidParameterError) IsUserError() {}
 
+func (*DefaultDestroyInvalidParameterError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDefaultDestroyInvalidParameter
+}
+
 func (e *DefaultDestroyInvalidParameterError) Error() string {
 	return fmt.Sprintf("`%s` is not a valid parameter type for a default destroy event", e.ParamType.QualifiedString())
 }
@@ -4751,6 +5423,10 @@ func (*InvalidTypeParameterizedNonNativeFunctionError) isSemanticError() {}
 
 func (*InvalidTypeParameterizedNonNativeFunctionError) IsUserError() {}
 
+func (*InvalidTypeParameterizedNonNativeFunctionError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidTypeParameterizedNonNativeFunction
+}
+
 func (e *InvalidTypeParameterizedNonNativeFunctionError) Error() string {
 	return "invalid type parameters in non-native function"
 }
@@ -4768,6 +5444,10 @@ func (*NestedReferenceError) isSemanticError() {}
 
 func (*NestedReferenceError) IsUserError() {}
 
+func (*NestedReferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNestedReference
+}
+
 func (e *NestedReferenceError) Error() string {
 	return fmt.Sprintf("cannot create a nested reference to value of type %s", e.Type.QualifiedString())
 }
@@ -4789,6 +5469,10 @@ func (*ResultVariableConflictError) isSemanticError() {}
 
 func (*ResultVariableConflictError) IsUserError() {}
 
+func (*ResultVariableConflictError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeResultVariableConflict
+}
+
 func (e *ResultVariableConflictError) Error() string {
 	return fmt.Sprintf(
 		"cannot declare %[1]s `%[2]s`: it conflicts with the `%[2]s` variable for the post-conditions",
@@ -4858,6 +5542,10 @@ func (e *InvocationTypeInferenceError) isSemanticError() {}
 
 func (*InvocationTypeInferenceError) IsUserError() {}
 
+func (*InvocationTypeInferenceError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvocationTypeInference
+}
+
 func (e *InvocationTypeInferenceError) Error() string {
 	return "cannot infer type of invocation"
 }
@@ -4876,6 +5564,10 @@ func (e *UnconvertableTypeError) isSemanticError() {}
 
 func (*UnconvertableTypeError) IsUserError() {}
 
+func (*UnconvertableTypeError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnconvertableType
+}
+
 func (e *UnconvertableTypeError) Error() string {
 	return fmt.Sprintf("cannot convert type `%s`", e.Type)
 }
@@ -4893,6 +5585,10 @@ func (*InvalidMappingAuthorizationError) isSemanticError() {}
 
 func (*InvalidMappingAuthorizationError) IsUserError() {}
 
+func (*InvalidMappingAuthorizationError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidMappingAuthorization
+}
+
 func (e *InvalidMappingAuthorizationError) Error() string {
 	return "auth(mapping ...) is not supported"
 }
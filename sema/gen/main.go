@@ -1316,6 +1316,10 @@ func (*generator) VisitImportDeclaration(_ *ast.ImportDeclaration) struct{} {
 	panic("import declarations are not supported")
 }
 
+func (*generator) VisitInvalidDeclaration(_ *ast.InvalidDeclaration) struct{} {
+	panic("invalid declarations are not supported")
+}
+
 const typeNameSeparator = '_'
 
 func joinTypeName(parentFullTypeName string, typeName string) string {
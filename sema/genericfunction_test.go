@@ -943,6 +943,30 @@ func TestCheckGenericFunctionDeclaration(t *testing.T) {
 		assert.IsType(t, &sema.InvalidTypeParameterizedNonNativeFunctionError{}, errs[0])
 	})
 
+	t.Run("global, non-native, type parameters enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t, `
+              fun head<T>(_ items: [T]): T? { return nil }
+
+              let x: Int? = head([1, 2, 3])
+            `,
+			ParseAndCheckOptions{
+				Config: &sema.Config{
+					AllowNativeDeclarations: false,
+					TypeParametersEnabled:   true,
+				},
+				ParseOptions: parser.Config{
+					NativeModifierEnabled: false,
+					TypeParametersEnabled: true,
+				},
+			},
+		)
+
+		require.NoError(t, err)
+	})
+
 	t.Run("global, native", func(t *testing.T) {
 
 		t.Parallel()
@@ -993,6 +1017,32 @@ func TestCheckGenericFunctionDeclaration(t *testing.T) {
 		assert.IsType(t, &sema.InvalidTypeParameterizedNonNativeFunctionError{}, errs[0])
 	})
 
+	t.Run("composite function, non-native, type parameters enabled", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckWithOptions(t, `
+	          struct S {
+	              fun head<T>(_ items: [T]): T? { return nil }
+	          }
+
+	          let x: Int? = S().head([1, 2, 3])
+	        `,
+			ParseAndCheckOptions{
+				Config: &sema.Config{
+					AllowNativeDeclarations: false,
+					TypeParametersEnabled:   true,
+				},
+				ParseOptions: parser.Config{
+					NativeModifierEnabled: false,
+					TypeParametersEnabled: true,
+				},
+			},
+		)
+
+		require.NoError(t, err)
+	})
+
 	t.Run("composite function, non-native", func(t *testing.T) {
 
 		t.Parallel()
@@ -1120,3 +1170,188 @@ func TestCheckGenericFunctionDeclaration(t *testing.T) {
 		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
 	})
 }
+
+func TestCheckNonNativeGenericFunction(t *testing.T) {
+
+	t.Parallel()
+
+	parseAndCheck := func(t *testing.T, code string) (*sema.Checker, error) {
+		return ParseAndCheckWithOptions(t,
+			code,
+			ParseAndCheckOptions{
+				Config: &sema.Config{
+					TypeParametersEnabled: true,
+				},
+				ParseOptions: parser.Config{
+					TypeParametersEnabled: true,
+				},
+			},
+		)
+	}
+
+	t.Run("inferred type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := parseAndCheck(t, `
+          fun firstOr<T: AnyStruct>(_ xs: [T], _ d: T): T {
+              if xs.length == 0 {
+                  return d
+              }
+              return xs[0]
+          }
+
+          let x = firstOr([1, 2, 3], 4)
+          let y = firstOr<String>([], "default")
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t, sema.IntType, RequireGlobalValue(t, checker.Elaboration, "x"))
+		assert.Equal(t, sema.StringType, RequireGlobalValue(t, checker.Elaboration, "y"))
+	})
+
+	t.Run("type argument mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheck(t, `
+          fun firstOr<T: AnyStruct>(_ xs: [T], _ d: T): T {
+              return d
+          }
+
+          let x = firstOr([1, 2, 3], "4")
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("unbounded type parameter is bound by AnyStruct", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheck(t, `
+          resource R {}
+
+          fun id<T>(_ x: T): T {
+              return x
+          }
+
+          let x = id(1)
+
+          fun test() {
+              let r <- id(<-create R())
+              destroy r
+          }
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("resource type bound", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheck(t, `
+          resource R {}
+
+          fun id<T: @AnyResource>(_ x: @T): @T {
+              return <-x
+          }
+
+          fun test() {
+              let r: @R <- id(<-create R())
+              destroy r
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("resource type parameter, loss", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheck(t, `
+          fun drop<T: @AnyResource>(_ x: @T) {}
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("interface type bound, member access", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := parseAndCheck(t, `
+          struct interface HasName {
+              access(all) let name: String
+          }
+
+          struct S: HasName {
+              access(all) let name: String
+
+              init() {
+                  self.name = "S"
+              }
+          }
+
+          fun nameOf<T: {HasName}>(_ x: T): String {
+              return x.name
+          }
+
+          let name = nameOf(S())
+        `)
+		require.NoError(t, err)
+	})
+}
+
+func TestCheckNativeGenericFunctionTypeParameter(t *testing.T) {
+
+	t.Parallel()
+
+	for _, typeParametersEnabled := range []bool{true, false} {
+
+		t.Run(fmt.Sprintf("type parameters enabled: %t", typeParametersEnabled), func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheckWithOptions(t, `
+                  native fun convert<T: Integer>(_ value: Int): T {}
+
+                  let x = convert<UInt8>(1)
+                `,
+				ParseAndCheckOptions{
+					Config: &sema.Config{
+						AllowNativeDeclarations: true,
+						TypeParametersEnabled:   typeParametersEnabled,
+					},
+					ParseOptions: parser.Config{
+						NativeModifierEnabled: true,
+						TypeParametersEnabled: true,
+					},
+				},
+			)
+			require.NoError(t, err)
+
+			functionType := RequireGlobalValue(t, checker.Elaboration, "convert").(*sema.FunctionType)
+			require.Len(t, functionType.TypeParameters, 1)
+
+			typeParameter := functionType.TypeParameters[0]
+			assert.False(t, typeParameter.NonNative)
+
+			// The type of a native type parameter is not a subtype of its type bound,
+			// and does not have the members of the type bound
+
+			genericType := &sema.GenericType{
+				TypeParameter: typeParameter,
+			}
+			assert.False(t, sema.IsSubType(genericType, sema.IntegerType))
+			assert.NotContains(t, genericType.GetMembers(), sema.ToBigEndianBytesFunctionName)
+		})
+	}
+}
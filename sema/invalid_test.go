@@ -26,6 +26,7 @@ import (
 
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
 	. "github.com/onflow/cadence/test_utils/sema_utils"
@@ -321,3 +322,40 @@ func TestCheckInvalidRemove(t *testing.T) {
 
 	assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
 }
+
+func TestCheckInvalidDeclarationsAndStatements(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheckWithOptions(t,
+		`
+          fun broken( {}
+
+          struct S {
+              let x: Int =
+
+              init() {
+                  self.x = 1
+              }
+          }
+
+          fun test(): Int {
+              let y = (1 + )
+              return true
+          }
+        `,
+		ParseAndCheckOptions{
+			ParseOptions: parser.Config{
+				ErrorRecoveryEnabled: true,
+			},
+			IgnoreParseError: true,
+		},
+	)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+
+	RequireGlobalType(t, checker.Elaboration, "S")
+	RequireGlobalValue(t, checker.Elaboration, "test")
+}
@@ -963,8 +963,11 @@ func (t *GenericType) Equal(other Type) bool {
 	return t.TypeParameter == otherType.TypeParameter
 }
 
-func (*GenericType) IsResourceType() bool {
-	return false
+func (t *GenericType) IsResourceType() bool {
+	typeParameter := t.TypeParameter
+	return typeParameter.NonNative &&
+		typeParameter.TypeBound != nil &&
+		typeParameter.TypeBound.IsResourceType()
 }
 
 func (*GenericType) IsPrimitiveType() bool {
@@ -1062,6 +1065,11 @@ func (t *GenericType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
 }
 
 func (t *GenericType) GetMembers() map[string]MemberResolver {
+	// Values of a non-native type parameter's type have the members of the type bound
+	typeParameter := t.TypeParameter
+	if typeParameter.NonNative && typeParameter.TypeBound != nil {
+		return typeParameter.TypeBound.GetMembers()
+	}
 	return withBuiltinMembers(t, nil)
 }
 
@@ -3412,6 +3420,9 @@ type TypeParameter struct {
 	TypeBound Type
 	Name      string
 	Optional  bool
+	// NonNative determines if the type parameter is declared by a non-native function.
+	// Values of the type of a non-native type parameter have the type bound
+	NonNative bool
 }
 
 func (p TypeParameter) string(typeFormatter func(Type) string) string {
@@ -3954,6 +3965,7 @@ func (t *FunctionType) RewriteWithIntersectionTypes() (Type, bool) {
 						Name:      typeParameter.Name,
 						TypeBound: rewrittenTypeBound,
 						Optional:  typeParameter.Optional,
+						NonNative: typeParameter.NonNative,
 					}
 				} else {
 					rewrittenTypeParameters[i] = typeParameter
@@ -7478,6 +7490,17 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 		return true
 	}
 
+	// A non-native type parameter's type is a subtype of its type bound
+	if genericSubType, ok := subType.(*GenericType); ok {
+		typeParameter := genericSubType.TypeParameter
+		if typeParameter.NonNative &&
+			typeParameter.TypeBound != nil &&
+			IsSubType(typeParameter.TypeBound, superType) {
+
+			return true
+		}
+	}
+
 	switch superType {
 	case AnyType:
 		return true
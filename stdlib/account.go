@@ -1393,10 +1393,16 @@ func (t *SimpleContractAdditionTracker) IsContractBeingAdded(location common.Add
 	return contains
 }
 
+// ParserConfigProvider provides the configuration used to parse programs
+type ParserConfigProvider interface {
+	ParserConfig() parser.Config
+}
+
 type AccountContractAdditionHandler interface {
 	EventEmitter
 	AccountContractProvider
 	ContractAdditionTracker
+	ParserConfigProvider
 
 	ParseAndCheckProgram(
 		code []byte,
@@ -1655,13 +1661,14 @@ func changeAccountContracts(
 		oldCode, err := handler.GetAccountContractCode(location)
 		handleContractUpdateError(err, newCode)
 
+		parserConfig := handler.ParserConfig()
+		parserConfig.IgnoreLeadingIdentifierEnabled = true
+
 		memoryGauge := invocation.InvocationContext
 		oldProgram, err := parser.ParseProgram(
 			memoryGauge,
 			oldCode,
-			parser.Config{
-				IgnoreLeadingIdentifierEnabled: true,
-			},
+			parserConfig,
 		)
 
 		if err != nil && !ignoreUpdatedProgramParserError(err) {
@@ -2031,6 +2038,7 @@ func instantiateContract(
 type AccountContractRemovalHandler interface {
 	EventEmitter
 	AccountContractProvider
+	ParserConfigProvider
 	RemoveAccountContractCode(location common.AddressLocation) error
 	RecordContractRemoval(location common.AddressLocation)
 }
@@ -2075,7 +2083,11 @@ func newAccountContractsRemoveFunction(
 					// NOTE: *DO NOT* call setProgram – the program removal
 					// should not be effective during the execution, only after
 
-					existingProgram, err := parser.ParseProgram(inter, code, parser.Config{})
+					existingProgram, err := parser.ParseProgram(
+						inter,
+						code,
+						handler.ParserConfig(),
+					)
 
 					// If the existing code is not parsable (i.e: `err != nil`),
 					// that shouldn't be a reason to fail the contract removal.
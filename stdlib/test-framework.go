@@ -20,6 +20,7 @@
 package stdlib
 
 import (
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/interpreter"
 )
@@ -35,6 +36,18 @@ type TestFramework interface {
 	ReadFile(string) (string, error)
 }
 
+// TestFrameworkValueExporter can optionally be implemented by test providers,
+// e.g. by embedding `runtime.TestFrameworkValueExporter`.
+// If the test framework is able to export values,
+// failed equality assertions report the differences between the expected and the actual value.
+type TestFrameworkValueExporter interface {
+	ExportValue(
+		value interpreter.Value,
+		context interpreter.ValueExportContext,
+		locationRange interpreter.LocationRange,
+	) (cadence.Value, error)
+}
+
 type Blockchain interface {
 	RunScript(
 		context TestFrameworkScriptExecutionContext,
@@ -24,6 +24,7 @@ import (
 
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/diff"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/parser"
@@ -145,6 +146,7 @@ var testTypeAssertEqualFunctionType = &sema.FunctionType{
 }
 
 func testTypeAssertEqualFunction(
+	testFramework TestFramework,
 	inter *interpreter.Interpreter,
 	testContractValue *interpreter.CompositeValue,
 ) interpreter.BoundFunctionValue {
@@ -191,6 +193,20 @@ func testTypeAssertEqualFunction(
 					expected,
 					actual,
 				)
+
+				// Report the differences if the test framework is able to export values
+				if exporter, ok := testFramework.(TestFrameworkValueExporter); ok {
+					differences := valueDifferences(
+						exporter,
+						inter,
+						invocation.LocationRange,
+						expected,
+						actual,
+					)
+					if differences != "" {
+						message += "\n" + differences
+					}
+				}
 				panic(AssertionError{
 					Message:       message,
 					LocationRange: invocation.LocationRange,
@@ -202,6 +218,29 @@ func testTypeAssertEqualFunction(
 	)
 }
 
+// valueDifferences returns the textual representation of the differences
+// between the expected and the actual value, one difference per line.
+// If a value cannot be exported, the export error is reported instead.
+func valueDifferences(
+	exporter TestFrameworkValueExporter,
+	context interpreter.ValueExportContext,
+	locationRange interpreter.LocationRange,
+	expected interpreter.Value,
+	actual interpreter.Value,
+) string {
+	exportedExpected, err := exporter.ExportValue(expected, context, locationRange)
+	if err != nil {
+		return fmt.Sprintf("cannot report differences: %s", err)
+	}
+
+	exportedActual, err := exporter.ExportValue(actual, context, locationRange)
+	if err != nil {
+		return fmt.Sprintf("cannot report differences: %s", err)
+	}
+
+	return diff.Text(diff.Diff(exportedExpected, exportedActual))
+}
+
 // 'Test.fail' function
 
 const testTypeFailFunctionDocString = `
@@ -1322,7 +1361,7 @@ func (t *TestContractType) NewTestContract(
 
 	// Inject natively implemented function values
 	compositeValue.Functions.Set(testTypeAssertFunctionName, testTypeAssertFunction(inter, compositeValue))
-	compositeValue.Functions.Set(testTypeAssertEqualFunctionName, testTypeAssertEqualFunction(inter, compositeValue))
+	compositeValue.Functions.Set(testTypeAssertEqualFunctionName, testTypeAssertEqualFunction(testFramework, inter, compositeValue))
 	compositeValue.Functions.Set(testTypeFailFunctionName, testTypeFailFunction(inter, compositeValue))
 	compositeValue.Functions.Set(testTypeExpectFunctionName, t.expectFunction(inter, compositeValue))
 	compositeValue.Functions.Set(
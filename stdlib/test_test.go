@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/activations"
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
@@ -1047,6 +1048,109 @@ func TestAssertEqual(t *testing.T) {
 		errs := RequireCheckerErrors(t, err, 1)
 		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
 	})
+
+	t.Run("differences", func(t *testing.T) {
+		t.Parallel()
+
+		script := `
+            import Test
+
+            access(all)
+            fun test() {
+                let expected = [1, [2, 3], 4]
+                let actual = [1, [2, 5]]
+                Test.assertEqual(expected, actual)
+            }
+        `
+
+		testFramework := &mockedValueExportingTestFramework{
+			mockedTestFramework: mockedTestFramework{
+				emulatorBackend: func() Blockchain {
+					return &mockedBlockchain{}
+				},
+			},
+		}
+
+		inter, err := newTestContractInterpreterWithTestFramework(t, script, testFramework)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.Error(t, err)
+		assert.ErrorAs(t, err, &AssertionError{})
+		assert.ErrorContains(
+			t,
+			err,
+			"not equal: expected: [1, [2, 3], 4], actual: [1, [2, 5]]\n"+
+				"~ .[1][1]: 3 => 5\n"+
+				"- .[2]: 4",
+		)
+	})
+
+	t.Run("differences, export error", func(t *testing.T) {
+		t.Parallel()
+
+		script := `
+            import Test
+
+            access(all)
+            fun test() {
+                Test.assertEqual("this string", "that string")
+            }
+        `
+
+		testFramework := &mockedValueExportingTestFramework{
+			mockedTestFramework: mockedTestFramework{
+				emulatorBackend: func() Blockchain {
+					return &mockedBlockchain{}
+				},
+			},
+		}
+
+		inter, err := newTestContractInterpreterWithTestFramework(t, script, testFramework)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.Error(t, err)
+		assert.ErrorAs(t, err, &AssertionError{})
+		assert.ErrorContains(
+			t,
+			err,
+			"not equal: expected: \"this string\", actual: \"that string\"\n"+
+				"cannot report differences: cannot export value: \"this string\"",
+		)
+	})
+
+	t.Run("differences, no value exporter", func(t *testing.T) {
+		t.Parallel()
+
+		script := `
+            import Test
+
+            access(all)
+            fun test() {
+                Test.assertEqual([1, 2], [1, 3])
+            }
+        `
+
+		testFramework := &mockedTestFramework{
+			emulatorBackend: func() Blockchain {
+				return &mockedBlockchain{}
+			},
+		}
+
+		inter, err := newTestContractInterpreterWithTestFramework(t, script, testFramework)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.Error(t, err)
+
+		var assertionErr AssertionError
+		require.ErrorAs(t, err, &assertionErr)
+		assert.Equal(t,
+			"not equal: expected: [1, 2], actual: [1, 3]",
+			assertionErr.Message,
+		)
+	})
 }
 
 func TestTestBeSucceededMatcher(t *testing.T) {
@@ -2894,6 +2998,50 @@ func (m mockedTestFramework) ReadFile(fileName string) (string, error) {
 	return m.readFile(fileName)
 }
 
+// mockedValueExportingTestFramework is a test framework which can export
+// integer and array values, for testing purposes.
+type mockedValueExportingTestFramework struct {
+	mockedTestFramework
+}
+
+var _ TestFrameworkValueExporter = &mockedValueExportingTestFramework{}
+
+func (m mockedValueExportingTestFramework) ExportValue(
+	value interpreter.Value,
+	context interpreter.ValueExportContext,
+	locationRange interpreter.LocationRange,
+) (cadence.Value, error) {
+	switch value := value.(type) {
+	case interpreter.IntValue:
+		return cadence.NewIntFromBig(value.ToBigInt(context)), nil
+
+	case *interpreter.ArrayValue:
+		var values []cadence.Value
+		var err error
+		value.Iterate(
+			context,
+			func(element interpreter.Value) (resume bool) {
+				var exported cadence.Value
+				exported, err = m.ExportValue(element, context, locationRange)
+				if err != nil {
+					return false
+				}
+				values = append(values, exported)
+				return true
+			},
+			false,
+			locationRange,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewArray(values), nil
+
+	default:
+		return nil, fmt.Errorf("cannot export value: %s", value)
+	}
+}
+
 // mockedBlockchain is the implementation of `Blockchain` for testing purposes.
 type mockedBlockchain struct {
 	runScript          func(context TestFrameworkScriptExecutionContext, code string, arguments []interpreter.Value)
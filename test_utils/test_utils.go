@@ -26,9 +26,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence/activations"
+	"github.com/onflow/cadence/bbq"
+	"github.com/onflow/cadence/bbq/compiler"
+	"github.com/onflow/cadence/bbq/vm"
 	"github.com/onflow/cadence/common"
-	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/parser"
 	"github.com/onflow/cadence/pretty"
 	"github.com/onflow/cadence/sema"
 	"github.com/onflow/cadence/stdlib"
@@ -39,52 +42,76 @@ import (
 type ParseCheckAndInterpretOptions struct {
 	Config             *interpreter.Config
 	CheckerConfig      *sema.Config
+	ParseOptions       parser.Config
 	HandleCheckerError func(error)
 }
 
-//type VMInvokable struct {
-//	vmInstance *vm.VM
-//	*vm.Context
-//	elaboration *compiler.DesugaredElaboration
-//}
-//
-//var _ Invokable = &VMInvokable{}
-//
-//func NewVMInvokable(
-//	vmInstance *vm.VM,
-//	elaboration *compiler.DesugaredElaboration,
-//) *VMInvokable {
-//	return &VMInvokable{
-//		vmInstance:  vmInstance,
-//		Context:     vmInstance.Context(),
-//		elaboration: elaboration,
-//	}
-//}
-//
-//func (v *VMInvokable) Invoke(functionName string, arguments ...interpreter.Value) (value interpreter.Value, err error) {
-//	value, err = v.vmInstance.InvokeExternally(functionName, arguments...)
-//
-//	// Reset the VM after a function invocation,
-//	// so the same vm can be re-used for subsequent invocation.
-//	v.vmInstance.Reset()
-//
-//	return
-//}
-//
-//func (v *VMInvokable) GetGlobal(name string) interpreter.Value {
-//	return v.vmInstance.Global(name)
-//}
-//
-//func (v *VMInvokable) GetGlobalType(name string) (*sema.Variable, bool) {
-//	return v.elaboration.GetGlobalType(name)
-//}
-//
-//func (v *VMInvokable) InitializeContract(arguments ...interpreter.Value) (*interpreter.CompositeValue, error) {
-//	return v.vmInstance.InitializeContract(arguments...)
-//}
-//func (v *VMInvokable) InitializeContract(contractName string, arguments ...interpreter.Value) (*interpreter.CompositeValue, error) {
-//	return v.vmInstance.InitializeContract(contractName, arguments...)
-//}
+// VMInvokable invokes functions using the VM.
+// Functions which are not compiled, and all other operations, use the interpreter.
+type VMInvokable struct {
+	*interpreter.Interpreter
+	vmInstance        *vm.VM
+	program           *bbq.Program
+	unsupportedErrors []*compiler.UnsupportedError
+}
+
+var _ Invokable = &VMInvokable{}
+
+// NewVMInvokable compiles the program of the given interpreter.
+// The functions which could not be compiled, and are executed by the interpreter, are logged.
+// Use RequireCompiled to fail the test instead.
+func NewVMInvokable(tb testing.TB, inter *interpreter.Interpreter) *VMInvokable {
+	tb.Helper()
+
+	program, unsupportedErrors := compiler.Compile(inter.Program)
+	for _, unsupportedError := range unsupportedErrors {
+		tb.Logf("%s, executing it using the interpreter", unsupportedError)
+	}
+
+	return &VMInvokable{
+		Interpreter:       inter,
+		vmInstance:        vm.NewVM(program, inter),
+		program:           program,
+		unsupportedErrors: unsupportedErrors,
+	}
+}
+
+func (v *VMInvokable) Invoke(functionName string, arguments ...interpreter.Value) (value interpreter.Value, err error) {
+	return v.vmInstance.Invoke(functionName, arguments...)
+}
+
+// RequireCompiled fails the test if one of the given functions was not compiled,
+// i.e. if it is executed by the interpreter instead of the VM.
+func (v *VMInvokable) RequireCompiled(tb testing.TB, functionNames ...string) {
+	tb.Helper()
+
+	for _, functionName := range functionNames {
+		if v.program.Function(functionName) != nil {
+			continue
+		}
+
+		for _, unsupportedError := range v.unsupportedErrors {
+			if unsupportedError.FunctionName == functionName {
+				require.FailNow(tb, unsupportedError.Error())
+			}
+		}
+
+		require.FailNowf(tb, "function not compiled", "function `%s` was not compiled", functionName)
+	}
+}
+
+// RequireCompiled fails the test if the given invokable uses the VM,
+// and one of the given functions was not compiled.
+func RequireCompiled(tb testing.TB, invokable Invokable, functionNames ...string) {
+	tb.Helper()
+
+	vmInvokable, ok := invokable.(*VMInvokable)
+	if !ok {
+		return
+	}
+
+	vmInvokable.RequireCompiled(tb, functionNames...)
+}
 
 func ParseCheckAndPrepare(tb testing.TB, code string, compile bool) Invokable {
 	tb.Helper()
@@ -159,112 +186,12 @@ func ParseCheckAndPrepareWithOptions(
 		return ParseCheckAndInterpretWithOptions(tb, code, options)
 	}
 
-	//interpreterConfig := options.Config
-	//
-	//var storage interpreter.Storage
-	//if interpreterConfig != nil {
-	//	storage = interpreterConfig.Storage
-	//}
-	//
-	//vmConfig := vm.NewConfig(storage).
-	//	WithInterpreterConfig(interpreterConfig).
-	//	WithDebugEnabled()
-	//
-	//var compilerConfig *compiler.Config
-	//
-	//// If there are builtin functions provided externally (e.g: for tests),
-	//// then convert them to corresponding functions in compiler and in vm.
-	//if interpreterConfig != nil && interpreterConfig.BaseActivationHandler != nil {
-	//	baseActivation := interpreterConfig.BaseActivationHandler(nil)
-	//	baseActivationVariables := baseActivation.ValuesInFunction()
-	//
-	//	vmConfig.BuiltinGlobalsProvider = func() map[string]*vm.Variable {
-	//		builtinGlobals := vm.NativeFunctions()
-	//
-	//		// Add the given built-in values.
-	//		// Convert the externally provided `interpreter.HostFunctionValue`s into `vm.NativeFunctionValue`s.
-	//		for name, variable := range baseActivationVariables { //nolint:maprange
-	//
-	//			if builtinGlobals[name] != nil {
-	//				continue
-	//			}
-	//
-	//			value := variable.GetValue(nil)
-	//
-	//			if functionValue, ok := value.(*interpreter.HostFunctionValue); ok {
-	//				value = vm.NewNativeFunctionValue(
-	//					name,
-	//					functionValue.Type,
-	//					func(context *vm.Context, _ []interpreter.StaticType, arguments ...vm.Value) vm.Value {
-	//						invocation := interpreter.NewInvocation(
-	//							context,
-	//							nil,
-	//							nil,
-	//							arguments,
-	//							nil,
-	//							// TODO: provide these if they are needed for tests.
-	//							nil,
-	//							interpreter.EmptyLocationRange,
-	//						)
-	//						return functionValue.Function(invocation)
-	//					},
-	//				)
-	//
-	//			}
-	//
-	//			vmVariable := &vm.Variable{}
-	//			vmVariable.InitializeWithValue(value)
-	//
-	//			builtinGlobals[name] = vmVariable
-	//		}
-	//
-	//		return builtinGlobals
-	//	}
-	//
-	//	// Register externally provided globals in compiler.
-	//	compilerConfig = &compiler.Config{
-	//		BuiltinGlobalsProvider: func() map[string]*compiler.Global {
-	//			globals := compiler.NativeFunctions()
-	//			for name := range baseActivationVariables { //nolint:maprange
-	//				if globals[name] != nil {
-	//					continue
-	//				}
-	//				globals[name] = &compiler.Global{
-	//					Name: name,
-	//				}
-	//			}
-	//
-	//			return globals
-	//		},
-	//	}
-	//}
-	//
-	//parseAndCheckOptions := &sema_utils.ParseAndCheckOptions{
-	//	Config: options.CheckerConfig,
-	//}
-	//
-	//programs := map[common.Location]*CompiledProgram{}
-	//
-	//vmInstance := compilerUtils.CompileAndPrepareToInvoke(
-	//	tb,
-	//	code,
-	//	compilerUtils.CompilerAndVMOptions{
-	//		VMConfig: vmConfig,
-	//		ParseCheckAndCompileOptions: ParseCheckAndCompileOptions{
-	//			ParseAndCheckOptions: parseAndCheckOptions,
-	//			CompilerConfig:       compilerConfig,
-	//			CheckerErrorHandler:  options.HandleCheckerError,
-	//		},
-	//		Programs: programs,
-	//	},
-	//)
-	//
-	//elaboration := programs[parseAndCheckOptions.Location].DesugaredElaboration
-	//
-	//return NewVMInvokable(vmInstance, elaboration), nil
-
-	// Unsupported for now
-	panic(errors.NewUnreachableError())
+	inter, err := ParseCheckAndInterpretWithOptions(tb, code, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVMInvokable(tb, inter), nil
 }
 
 // Below helper functions were copied as-is from `misc_test.go`.
@@ -434,7 +361,8 @@ func parseCheckAndInterpretWithOptionsAndMemoryMeteringAndAtreeValidations(
 	checker, err := sema_utils.ParseAndCheckWithOptionsAndMemoryMetering(t,
 		code,
 		sema_utils.ParseAndCheckOptions{
-			Config: options.CheckerConfig,
+			Config:       options.CheckerConfig,
+			ParseOptions: options.ParseOptions,
 		},
 		memoryGauge,
 	)
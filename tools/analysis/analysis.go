@@ -27,6 +27,14 @@ func Load(config *Config, locations ...common.Location) (*Programs, error) {
 		Programs:                  make(map[common.Location]*Program, len(locations)),
 		CryptoContractElaboration: config.CryptoContractElaboration,
 	}
+	if config.Concurrency > 1 {
+		err := programs.LoadConcurrently(config, locations...)
+		if err != nil {
+			return nil, err
+		}
+		return programs, nil
+	}
+
 	for _, location := range locations {
 		err := programs.Load(config, location)
 		if err != nil {
@@ -20,7 +20,11 @@ package analysis_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -315,6 +319,132 @@ func TestHandledParserError(t *testing.T) {
 	)
 }
 
+func TestParserErrorRecovery(t *testing.T) {
+
+	t.Parallel()
+
+	contractAddress := common.MustBytesToAddress([]byte{0x1})
+	contractLocation := common.AddressLocation{
+		Address: contractAddress,
+		Name:    "ContractA",
+	}
+	const contractCode = `
+	  access(all) contract ContractA {
+
+	    access(all) fun a() {
+	      let x = true as! Bool
+	    }
+
+	    access(all) fun b() {
+	      ???
+	    }
+
+	    access(all) fun c() {
+	      let y = "c" as! String
+	    }
+	  }
+	`
+
+	var parserErrors []analysis.ParsingCheckingError
+
+	config := &analysis.Config{
+		Mode:                       analysis.NeedTypes,
+		ParserErrorRecoveryEnabled: true,
+		ResolveCode: func(
+			location common.Location,
+			importingLocation common.Location,
+			importRange ast.Range,
+		) ([]byte, error) {
+			switch location {
+			case contractLocation:
+				return []byte(contractCode), nil
+
+			default:
+				require.FailNowf(t,
+					"import of unknown location",
+					"location: %s",
+					location,
+				)
+				return nil, nil
+			}
+		},
+		HandleParserError: func(err analysis.ParsingCheckingError, program *ast.Program) error {
+			require.NotNil(t, program)
+			parserErrors = append(parserErrors, err)
+			return nil
+		},
+		HandleCheckerError: func(_ analysis.ParsingCheckingError, _ *sema.Checker) error {
+			return nil
+		},
+	}
+
+	programs, err := analysis.Load(config, contractLocation)
+	require.NoError(t, err)
+
+	require.Len(t, parserErrors, 1)
+
+	program := programs.Get(contractLocation)
+	require.NotNil(t, program.Program)
+	require.NotNil(t, program.Checker)
+
+	var parserError parser.Error
+	require.ErrorAs(t, program.LoadError, &parserError)
+
+	// Run a simple analysis: Detect unnecessary cast
+
+	analyzer := &analysis.Analyzer{
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			inspector.Preorder(
+				[]ast.Element{
+					(*ast.CastingExpression)(nil),
+				},
+				func(element ast.Element) {
+					castingExpression := element.(*ast.CastingExpression)
+
+					types := pass.Program.Checker.Elaboration.CastingExpressionTypes(castingExpression)
+					if !sema.IsSubType(types.StaticValueType, types.TargetType) {
+						return
+					}
+
+					pass.Report(analysis.Diagnostic{
+						Location: pass.Program.Location,
+						Range:    ast.NewRangeFromPositioned(nil, castingExpression),
+						Message:  "unnecessary cast",
+					})
+				},
+			)
+
+			return nil
+		},
+	}
+
+	var diagnostics []analysis.Diagnostic
+
+	program.Run(
+		[]*analysis.Analyzer{analyzer},
+		func(diagnostic analysis.Diagnostic) {
+			diagnostics = append(diagnostics, diagnostic)
+		},
+	)
+
+	require.Len(t, diagnostics, 2)
+
+	sort.Slice(
+		diagnostics,
+		func(i, j int) bool {
+			return diagnostics[i].StartPos.Offset < diagnostics[j].StartPos.Offset
+		},
+	)
+
+	require.Equal(t, 5, diagnostics[0].StartPos.Line)
+	require.Equal(t, 13, diagnostics[1].StartPos.Line)
+}
+
 func TestHandledCheckerError(t *testing.T) {
 
 	t.Parallel()
@@ -569,3 +699,486 @@ func TestCyclicImports(t *testing.T) {
 	errs = RequireCheckerErrors(t, nestedCheckerErr, 1)
 	require.IsType(t, &sema.CyclicImportsError{}, errs[0])
 }
+
+// newTestContractsConfig returns a configuration for a layered import graph of the given number of contracts.
+// Each contract imports all contracts of the previous layer
+func newTestContractsConfig(count int, layerSize int) (*analysis.Config, []common.Location) {
+	codes := map[common.Location][]byte{}
+	contractNames := map[common.Address][]string{}
+
+	locations := make([]common.Location, 0, count)
+
+	for i := 0; i < count; i++ {
+		address := common.MustBytesToAddress([]byte{byte(i / 256), byte(i % 256)})
+		name := fmt.Sprintf("C%d", i)
+
+		location := common.AddressLocation{
+			Address: address,
+			Name:    name,
+		}
+		locations = append(locations, location)
+		contractNames[address] = []string{name}
+
+		var builder strings.Builder
+
+		layer := i / layerSize
+		var imported []string
+		if layer > 0 {
+			for j := (layer - 1) * layerSize; j < layer*layerSize; j++ {
+				importedLocation := locations[j].(common.AddressLocation)
+				fmt.Fprintf(&builder, "import %s from %s\n", importedLocation.Name, importedLocation.Address.HexWithPrefix())
+				imported = append(imported, importedLocation.Name)
+			}
+		}
+
+		fmt.Fprintf(&builder, "access(all) contract %s {\n", name)
+		fmt.Fprintf(&builder, "    access(all) struct S {\n")
+		fmt.Fprintf(&builder, "        access(all) let value: Int\n")
+		fmt.Fprintf(&builder, "        init(value: Int) { self.value = value }\n")
+		fmt.Fprintf(&builder, "    }\n")
+		fmt.Fprintf(&builder, "    access(all) fun value(): Int {\n")
+		fmt.Fprintf(&builder, "        var sum = %d\n", i)
+		for _, importedName := range imported {
+			fmt.Fprintf(&builder, "        sum = sum + %[1]s.value() + %[1]s.S(value: sum).value\n", importedName)
+		}
+		fmt.Fprintf(&builder, "        return sum\n")
+		fmt.Fprintf(&builder, "    }\n")
+		fmt.Fprintf(&builder, "}\n")
+
+		codes[location] = []byte(builder.String())
+	}
+
+	config := analysis.NewSimpleConfig(
+		analysis.NeedTypes,
+		codes,
+		contractNames,
+		nil,
+	)
+
+	return config, locations
+}
+
+func TestConcurrentLoad(t *testing.T) {
+
+	t.Parallel()
+
+	config, locations := newTestContractsConfig(40, 8)
+
+	sequentialPrograms, err := analysis.Load(config, locations...)
+	require.NoError(t, err)
+
+	concurrentConfig := *config
+	concurrentConfig.Concurrency = 8
+
+	// Only load the last layer, the other contracts are loaded as imports
+	concurrentPrograms, err := analysis.Load(&concurrentConfig, locations[32:]...)
+	require.NoError(t, err)
+
+	require.Len(t, concurrentPrograms.Programs, len(sequentialPrograms.Programs))
+
+	for _, location := range locations {
+		sequentialProgram := sequentialPrograms.Get(location)
+		require.NotNil(t, sequentialProgram)
+
+		concurrentProgram := concurrentPrograms.Get(location)
+		require.NotNil(t, concurrentProgram)
+
+		require.NoError(t, concurrentProgram.LoadError)
+		require.NotNil(t, concurrentProgram.Checker)
+
+		require.Equal(t, sequentialProgram.Code, concurrentProgram.Code)
+	}
+}
+
+func TestConcurrentLoadErrorOrder(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	codes := map[common.Location][]byte{}
+	contractNames := map[common.Address][]string{}
+
+	var locations []common.Location
+
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("C%d", i)
+		location := common.AddressLocation{
+			Address: address,
+			Name:    name,
+		}
+		locations = append(locations, location)
+		contractNames[address] = append(contractNames[address], name)
+
+		code := fmt.Sprintf("access(all) contract %s {}", name)
+		if i%2 == 1 {
+			code = fmt.Sprintf("access(all) contract %s { access(all) let x: Int = X }", name)
+		}
+		codes[location] = []byte(code)
+	}
+
+	config := analysis.NewSimpleConfig(
+		analysis.NeedTypes,
+		codes,
+		contractNames,
+		nil,
+	)
+	config.Concurrency = 4
+
+	for i := 0; i < 10; i++ {
+		_, err := analysis.Load(config, locations...)
+		require.Error(t, err)
+
+		var parsingCheckingErr analysis.ParsingCheckingError
+		require.ErrorAs(t, err, &parsingCheckingErr)
+		require.Equal(t, locations[1], parsingCheckingErr.ImportLocation())
+	}
+}
+
+func TestConcurrentLoadCyclicImports(t *testing.T) {
+
+	t.Parallel()
+
+	fooContractLocation := common.AddressLocation{
+		Address: common.MustBytesToAddress([]byte{0x1}),
+		Name:    "Foo",
+	}
+	barContractLocation := common.AddressLocation{
+		Address: common.MustBytesToAddress([]byte{0x2}),
+		Name:    "Bar",
+	}
+
+	config := analysis.NewSimpleConfig(
+		analysis.NeedTypes,
+		map[common.Location][]byte{
+			fooContractLocation: []byte(`
+              import Bar from 0x2
+              access(all) contract Foo {}
+            `),
+			barContractLocation: []byte(`
+              import Foo from 0x1
+              access(all) contract Bar {}
+            `),
+		},
+		map[common.Address][]string{
+			fooContractLocation.Address: {fooContractLocation.Name},
+			barContractLocation.Address: {barContractLocation.Name},
+		},
+		nil,
+	)
+	config.Concurrency = 4
+
+	_, err := analysis.Load(config, fooContractLocation)
+	require.Error(t, err)
+
+	var checkerError *sema.CheckerError
+	require.ErrorAs(t, err, &checkerError)
+
+	errs := RequireCheckerErrors(t, checkerError, 1)
+
+	var importedProgramErr *sema.ImportedProgramError
+	require.ErrorAs(t, errs[0], &importedProgramErr)
+
+	var nestedCheckerErr *sema.CheckerError
+	require.ErrorAs(t, importedProgramErr.Err, &nestedCheckerErr)
+
+	errs = RequireCheckerErrors(t, nestedCheckerErr, 1)
+	require.IsType(t, &sema.CyclicImportsError{}, errs[0])
+}
+
+// benchmarkConcurrencies returns the concurrencies which loading is benchmarked with:
+// sequential loading, and concurrent loading using all CPUs, if there are several
+func benchmarkConcurrencies() []int {
+	concurrencies := []int{1}
+	if cpus := runtime.NumCPU(); cpus > 1 {
+		concurrencies = append(concurrencies, cpus)
+	}
+	return concurrencies
+}
+
+func BenchmarkLoad(b *testing.B) {
+
+	config, locations := newTestContractsConfig(200, 20)
+
+	for _, concurrency := range benchmarkConcurrencies() {
+		b.Run(fmt.Sprintf("concurrency %d", concurrency), func(b *testing.B) {
+			config := *config
+			config.Concurrency = concurrency
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err := analysis.Load(&config, locations...)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLoadCompatibilitySuite benchmarks loading the contracts of the source compatibility suite.
+//
+// The repositories of the suite are cloned when running the suite, see compat/README.md.
+// The path of the suite can be configured using the environment variable CADENCE_COMPAT_SUITE_PATH
+func BenchmarkLoadCompatibilitySuite(b *testing.B) {
+
+	suitePath := os.Getenv("CADENCE_COMPAT_SUITE_PATH")
+	if suitePath == "" {
+		suitePath = filepath.Join("..", "..", "compat", "suite")
+	}
+
+	// Contracts are identified by name, independent of their address or path,
+	// as the contracts of the suite import each other in different ways
+
+	codes := map[string][]byte{}
+
+	err := filepath.WalkDir(suitePath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".cdc" {
+			return nil
+		}
+
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		program, err := parser.ParseProgram(nil, code, parser.Config{})
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		var name string
+		if declaration := program.SoleContractDeclaration(); declaration != nil {
+			name = declaration.Identifier.Identifier
+		} else if declaration := program.SoleContractInterfaceDeclaration(); declaration != nil {
+			name = declaration.Identifier.Identifier
+		} else {
+			return nil
+		}
+
+		codes[name] = code
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		b.Fatal(err)
+	}
+
+	if len(codes) == 0 {
+		b.Skip("no contracts found in compatibility suite")
+	}
+
+	names := make([]string, 0, len(codes))
+	for name := range codes { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	locations := make([]common.Location, 0, len(names))
+	for _, name := range names {
+		locations = append(locations, common.IdentifierLocation(name))
+	}
+
+	config := &analysis.Config{
+		Mode: analysis.NeedTypes,
+		ResolveAddressContractNames: func(_ common.Address) ([]string, error) {
+			return names, nil
+		},
+		ResolveCode: func(
+			location common.Location,
+			_ common.Location,
+			_ ast.Range,
+		) ([]byte, error) {
+			var name string
+			switch location := location.(type) {
+			case common.AddressLocation:
+				name = location.Name
+			case common.IdentifierLocation:
+				name = string(location)
+			case common.StringLocation:
+				name = strings.TrimSuffix(filepath.Base(string(location)), ".cdc")
+			}
+
+			code, ok := codes[name]
+			if !ok {
+				return nil, fmt.Errorf("import of unknown location: %s", location)
+			}
+			return code, nil
+		},
+		// The contracts of the suite might not be up-to-date
+		HandleParserError: func(err analysis.ParsingCheckingError, program *ast.Program) error {
+			if program == nil {
+				return err
+			}
+			return nil
+		},
+		HandleCheckerError: func(_ analysis.ParsingCheckingError, _ *sema.Checker) error {
+			return nil
+		},
+	}
+
+	for _, concurrency := range benchmarkConcurrencies() {
+		b.Run(fmt.Sprintf("concurrency %d", concurrency), func(b *testing.B) {
+			config := *config
+			config.Concurrency = concurrency
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err := analysis.Load(&config, locations...)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, concurrency int) {
+
+		address := common.MustBytesToAddress([]byte{0x1})
+
+		newLocation := func(name string) common.AddressLocation {
+			return common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+		}
+
+		aLocation := newLocation("A")
+		bLocation := newLocation("B")
+		cLocation := newLocation("C")
+		dLocation := newLocation("D")
+
+		codes := map[common.Location][]byte{
+			aLocation: []byte(`
+              import B from 0x1
+              access(all) contract A {
+                  access(all) fun answer(): Int {
+                      return B.answer()
+                  }
+              }
+            `),
+			bLocation: []byte(`
+              import C from 0x1
+              access(all) contract B {
+                  access(all) fun answer(): Int {
+                      return C.answer()
+                  }
+              }
+            `),
+			cLocation: []byte(`
+              access(all) contract C {
+                  access(all) fun answer(): Int {
+                      return 42
+                  }
+              }
+            `),
+			dLocation: []byte(`
+              access(all) contract D {}
+            `),
+		}
+
+		config := analysis.NewSimpleConfig(
+			analysis.NeedTypes,
+			codes,
+			map[common.Address][]string{
+				address: {"A", "B", "C", "D"},
+			},
+			nil,
+		)
+		config.Concurrency = concurrency
+
+		programs, err := analysis.Load(config, aLocation, dLocation)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]common.Location{aLocation},
+			programs.Importers(bLocation),
+		)
+		require.Equal(t,
+			[]common.Location{bLocation},
+			programs.Importers(cLocation),
+		)
+
+		a := programs.Get(aLocation)
+		d := programs.Get(dLocation)
+
+		// Change C compatibly
+
+		codes[cLocation] = []byte(`
+          access(all) contract C {
+              access(all) fun answer(): Int {
+                  return 43
+              }
+          }
+        `)
+
+		reloaded, err := programs.Reload(config, cLocation)
+		require.NoError(t, err)
+		require.Equal(t,
+			[]common.Location{cLocation, bLocation, aLocation},
+			reloaded,
+		)
+
+		require.NotSame(t, a, programs.Get(aLocation))
+		require.Same(t, d, programs.Get(dLocation))
+		require.Equal(t, codes[cLocation], programs.Get(cLocation).Code)
+
+		// Change C incompatibly
+
+		codes[cLocation] = []byte(`
+          access(all) contract C {
+              access(all) fun answer(): String {
+                  return "42"
+              }
+          }
+        `)
+
+		reloaded, err = programs.Reload(config, cLocation)
+		require.Error(t, err)
+		require.Equal(t,
+			[]common.Location{cLocation, bLocation, aLocation},
+			reloaded,
+		)
+
+		var parsingCheckingErr analysis.ParsingCheckingError
+		require.ErrorAs(t, err, &parsingCheckingErr)
+		require.Equal(t, bLocation, parsingCheckingErr.ImportLocation())
+
+		require.NotNil(t, programs.Get(cLocation))
+		require.Nil(t, programs.Get(bLocation))
+		require.Nil(t, programs.Get(aLocation))
+		require.Same(t, d, programs.Get(dLocation))
+
+		// Change D, which is not imported
+
+		reloaded, err = programs.Reload(config, dLocation)
+		require.NoError(t, err)
+		require.Equal(t,
+			[]common.Location{dLocation},
+			reloaded,
+		)
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, 0)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, 4)
+	})
+}
@@ -43,10 +43,20 @@ type Config struct {
 	Mode LoadMode
 	// HandleParserError is called when a parser error occurs instead of returning it
 	HandleParserError func(err ParsingCheckingError, program *ast.Program) error
+	// ParserErrorRecoveryEnabled determines if the parser recovers from syntax errors.
+	// If enabled, the declarations of a program which could be parsed are still loaded
+	// and passed to HandleParserError, and are checked if the error is handled
+	ParserErrorRecoveryEnabled bool
 	// HandleCheckerError is called when a checker error occurs instead of returning it
 	HandleCheckerError func(err ParsingCheckingError, checker *sema.Checker) error
 	// CryptoContractElaboration is the elaboration of the Crypto contract
 	CryptoContractElaboration *sema.Elaboration
+	// TypeParametersEnabled determines if non-native functions may have type parameters
+	TypeParametersEnabled bool
+	// Concurrency is the maximum number of programs which are parsed and checked concurrently.
+	// If it is less than 2, programs are loaded sequentially.
+	// The functions of the configuration are never called concurrently
+	Concurrency int
 }
 
 func NewSimpleConfig(
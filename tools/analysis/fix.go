@@ -0,0 +1,161 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"bytes"
+	"sort"
+)
+
+// textSpan is the byte span of the source code which is affected by a text edit.
+// The end offset is exclusive, so insertions have an empty span
+type textSpan struct {
+	start int
+	end   int
+}
+
+func textEditSpan(edit TextEdit) textSpan {
+	start := edit.StartPos.Offset
+	if edit.Insertion != "" {
+		return textSpan{
+			start: start,
+			end:   start,
+		}
+	}
+	return textSpan{
+		start: start,
+		end:   edit.EndPos.Offset + 1,
+	}
+}
+
+func (s textSpan) isEmpty() bool {
+	return s.start == s.end
+}
+
+// overlaps returns true if the two spans overlap.
+// Insertions at the same offset overlap, as the order of the insertions would be ambiguous,
+// and an insertion overlaps a replacement if it is at the start or inside of the replaced source,
+// as it would be ambiguous if the insertion is before or after the replacement
+func (s textSpan) overlaps(other textSpan) bool {
+	if s.isEmpty() && other.isEmpty() {
+		return s.start == other.start
+	}
+	if s.isEmpty() {
+		return other.start <= s.start && s.start < other.end
+	}
+	if other.isEmpty() {
+		return s.start <= other.start && other.start < s.end
+	}
+	return s.start < other.end && other.start < s.end
+}
+
+// textEditText returns the text which is inserted by the given edit
+func textEditText(edit TextEdit) string {
+	if edit.Insertion != "" {
+		return edit.Insertion
+	}
+	return edit.Replacement
+}
+
+// ApplySuggestedFixes applies the text edits of the given suggested fixes to the given code.
+//
+// The fixes are applied in the given order. A fix is skipped as a whole
+// if any of its edits is invalid, overlaps another of its edits,
+// or overlaps an edit of a previously applied fix.
+//
+// It returns the resulting code, the applied fixes, and the skipped fixes.
+func ApplySuggestedFixes(
+	code []byte,
+	fixes []SuggestedFix,
+) (
+	result []byte,
+	applied []SuggestedFix,
+	skipped []SuggestedFix,
+) {
+	type spanEdit struct {
+		text string
+		span textSpan
+	}
+
+	var edits []spanEdit
+
+	canApply := func(fix SuggestedFix) bool {
+		fixSpans := make([]textSpan, 0, len(fix.TextEdits))
+
+		for _, edit := range fix.TextEdits {
+			span := textEditSpan(edit)
+
+			if span.start < 0 || span.end < span.start || span.end > len(code) {
+				return false
+			}
+
+			for _, other := range fixSpans {
+				if span.overlaps(other) {
+					return false
+				}
+			}
+
+			for _, other := range edits {
+				if span.overlaps(other.span) {
+					return false
+				}
+			}
+
+			fixSpans = append(fixSpans, span)
+		}
+
+		return true
+	}
+
+	for _, fix := range fixes {
+		if len(fix.TextEdits) == 0 || !canApply(fix) {
+			skipped = append(skipped, fix)
+			continue
+		}
+
+		for _, edit := range fix.TextEdits {
+			edits = append(edits, spanEdit{
+				text: textEditText(edit),
+				span: textEditSpan(edit),
+			})
+		}
+
+		applied = append(applied, fix)
+	}
+
+	// Apply the edits in order of their position.
+	// The edits do not overlap, so the order is unambiguous
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].span.start < edits[j].span.start
+	})
+
+	var buffer bytes.Buffer
+	buffer.Grow(len(code))
+
+	offset := 0
+	for _, edit := range edits {
+		buffer.Write(code[offset:edit.span.start])
+		buffer.WriteString(edit.text)
+		offset = edit.span.end
+	}
+	buffer.Write(code[offset:])
+
+	return buffer.Bytes(), applied, skipped
+}
@@ -0,0 +1,169 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+func TestApplySuggestedFixes(t *testing.T) {
+
+	t.Parallel()
+
+	replace := func(start, end int, replacement string) analysis.SuggestedFix {
+		return analysis.SuggestedFix{
+			TextEdits: []analysis.TextEdit{
+				{
+					Replacement: replacement,
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: start},
+						EndPos:   ast.Position{Offset: end},
+					},
+				},
+			},
+		}
+	}
+
+	insert := func(offset int, insertion string) analysis.SuggestedFix {
+		return analysis.SuggestedFix{
+			TextEdits: []analysis.TextEdit{
+				{
+					Insertion: insertion,
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: offset},
+						EndPos:   ast.Position{Offset: offset},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("non-overlapping", func(t *testing.T) {
+
+		t.Parallel()
+
+		fixes := []analysis.SuggestedFix{
+			replace(4, 6, "xyz"),
+			replace(0, 2, "a"),
+			insert(3, "!"),
+		}
+
+		result, applied, skipped := analysis.ApplySuggestedFixes([]byte("012 456 89"), fixes)
+
+		assert.Equal(t, "a! xyz 89", string(result))
+		assert.Equal(t, fixes, applied)
+		assert.Empty(t, skipped)
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+
+		t.Parallel()
+
+		first := replace(0, 4, "a")
+		overlappingReplacement := replace(3, 6, "b")
+		overlappingInsertion := insert(2, "c")
+		sameInsertion := insert(8, "d")
+		otherInsertion := insert(8, "e")
+		adjacentInsertion := insert(5, "f")
+
+		result, applied, skipped := analysis.ApplySuggestedFixes(
+			[]byte("0123456789"),
+			[]analysis.SuggestedFix{
+				first,
+				overlappingReplacement,
+				overlappingInsertion,
+				sameInsertion,
+				otherInsertion,
+				adjacentInsertion,
+			},
+		)
+
+		assert.Equal(t, "af567d89", string(result))
+		assert.Equal(t,
+			[]analysis.SuggestedFix{first, sameInsertion, adjacentInsertion},
+			applied,
+		)
+		assert.Equal(t,
+			[]analysis.SuggestedFix{overlappingReplacement, overlappingInsertion, otherInsertion},
+			skipped,
+		)
+	})
+
+	t.Run("insertion at start of replacement", func(t *testing.T) {
+
+		t.Parallel()
+
+		replacement := replace(4, 6, "xyz")
+		insertion := insert(4, "!")
+
+		result, applied, skipped := analysis.ApplySuggestedFixes(
+			[]byte("012 456 89"),
+			[]analysis.SuggestedFix{insertion, replacement},
+		)
+
+		assert.Equal(t, "012 !456 89", string(result))
+		assert.Equal(t, []analysis.SuggestedFix{insertion}, applied)
+		assert.Equal(t, []analysis.SuggestedFix{replacement}, skipped)
+
+		result, applied, skipped = analysis.ApplySuggestedFixes(
+			[]byte("012 456 89"),
+			[]analysis.SuggestedFix{replacement, insertion},
+		)
+
+		assert.Equal(t, "012 xyz 89", string(result))
+		assert.Equal(t, []analysis.SuggestedFix{replacement}, applied)
+		assert.Equal(t, []analysis.SuggestedFix{insertion}, skipped)
+
+		// Both edits in the same fix
+
+		fix := analysis.SuggestedFix{
+			TextEdits: append(
+				replacement.TextEdits,
+				insertion.TextEdits...,
+			),
+		}
+
+		result, applied, skipped = analysis.ApplySuggestedFixes(
+			[]byte("012 456 89"),
+			[]analysis.SuggestedFix{fix},
+		)
+
+		assert.Equal(t, "012 456 89", string(result))
+		assert.Empty(t, applied)
+		assert.Equal(t, []analysis.SuggestedFix{fix}, skipped)
+	})
+
+	t.Run("out of bounds", func(t *testing.T) {
+
+		t.Parallel()
+
+		fix := replace(2, 10, "x")
+
+		result, applied, skipped := analysis.ApplySuggestedFixes([]byte("0123"), []analysis.SuggestedFix{fix})
+
+		assert.Equal(t, "0123", string(result))
+		assert.Empty(t, applied)
+		assert.Equal(t, []analysis.SuggestedFix{fix}, skipped)
+	})
+}
@@ -0,0 +1,147 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/onflow/cadence/common"
+)
+
+// addImport records that the program at the importing location imports the program at the imported location
+func (programs *Programs) addImport(importingLocation, importedLocation common.Location) {
+	programs.importsLock.Lock()
+	defer programs.importsLock.Unlock()
+
+	if programs.imports == nil {
+		programs.imports = map[common.Location]map[common.Location]struct{}{}
+		programs.importers = map[common.Location]map[common.Location]struct{}{}
+	}
+
+	imports := programs.imports[importingLocation]
+	if imports == nil {
+		imports = map[common.Location]struct{}{}
+		programs.imports[importingLocation] = imports
+	}
+	imports[importedLocation] = struct{}{}
+
+	importers := programs.importers[importedLocation]
+	if importers == nil {
+		importers = map[common.Location]struct{}{}
+		programs.importers[importedLocation] = importers
+	}
+	importers[importingLocation] = struct{}{}
+}
+
+// Importers returns the locations of the loaded programs
+// which directly import the program at the given location, sorted by location ID
+func (programs *Programs) Importers(location common.Location) []common.Location {
+	programs.importsLock.Lock()
+	defer programs.importsLock.Unlock()
+
+	return sortedLocations(programs.importers[location])
+}
+
+// Invalidate removes the programs at the given locations,
+// and all programs which import them, directly or indirectly.
+// All other programs are kept, and are reused when loading programs again.
+//
+// It returns the invalidated locations: the given locations, in the given order,
+// followed by the locations of the importing programs, in breadth-first order
+func (programs *Programs) Invalidate(locations ...common.Location) []common.Location {
+	programs.importsLock.Lock()
+	defer programs.importsLock.Unlock()
+
+	invalidated := make([]common.Location, 0, len(locations))
+	seen := map[common.Location]struct{}{}
+
+	for _, location := range locations {
+		if _, ok := seen[location]; ok {
+			continue
+		}
+		seen[location] = struct{}{}
+		invalidated = append(invalidated, location)
+	}
+
+	for i := 0; i < len(invalidated); i++ {
+		for _, importer := range sortedLocations(programs.importers[invalidated[i]]) {
+			if _, ok := seen[importer]; ok {
+				continue
+			}
+			seen[importer] = struct{}{}
+			invalidated = append(invalidated, importer)
+		}
+	}
+
+	for _, location := range invalidated {
+		delete(programs.Programs, location)
+
+		// The imports of the invalidated programs are recorded again when they are loaded again
+
+		for imported := range programs.imports[location] { //nolint:maprange
+			delete(programs.importers[imported], location)
+		}
+		delete(programs.imports, location)
+	}
+
+	// If the Crypto contract was invalidated, its memoized elaboration is invalid
+
+	if programs.CryptoContractLocation != nil {
+		if _, ok := seen[programs.CryptoContractLocation()]; ok {
+			programs.CryptoContractElaboration = nil
+		}
+	}
+
+	return invalidated
+}
+
+// Reload invalidates the programs at the given locations, e.g. because their code changed,
+// and loads all invalidated programs again, see Invalidate.
+// Only the invalidated programs are parsed and checked again,
+// the elaborations of all other programs are reused.
+//
+// It returns the invalidated locations, and the first error which occurred while loading them, if any
+func (programs *Programs) Reload(config *Config, locations ...common.Location) ([]common.Location, error) {
+	invalidated := programs.Invalidate(locations...)
+
+	if config.Concurrency > 1 {
+		return invalidated, programs.LoadConcurrently(config, invalidated...)
+	}
+
+	var firstErr error
+	for _, location := range invalidated {
+		err := programs.Load(config, location)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return invalidated, firstErr
+}
+
+func sortedLocations(locations map[common.Location]struct{}) []common.Location {
+	result := make([]common.Location, 0, len(locations))
+	for location := range locations { //nolint:maprange
+		result = append(result, location)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
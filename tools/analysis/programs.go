@@ -20,6 +20,7 @@ package analysis
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
@@ -32,6 +33,13 @@ type Programs struct {
 	Programs                  map[common.Location]*Program
 	CryptoContractElaboration *sema.Elaboration
 	CryptoContractLocation    func() common.Location
+	// importsLock guards imports and importers,
+	// as programs may be loaded concurrently
+	importsLock sync.Mutex
+	// imports are the locations imported by each program
+	imports map[common.Location]map[common.Location]struct{}
+	// importers are the locations of the programs which import each program
+	importers map[common.Location]map[common.Location]struct{}
 }
 
 type importResolutionResults map[common.Location]bool
@@ -60,53 +68,28 @@ func (programs *Programs) load(
 		return nil
 	}
 
-	var loadError error
-	wrapError := func(err error) ParsingCheckingError {
-		return ParsingCheckingError{
-			error:    err,
-			location: location,
-		}
-	}
-
 	code, err := config.ResolveCode(location, importingLocation, importRange)
 	if err != nil {
 		return err
 	}
 
-	program, err := parser.ParseProgram(nil, code, parser.Config{})
+	program, err := parse(config, code)
+	program, loadError, err := handleParserError(config, location, program, err)
 	if err != nil {
-		wrappedErr := wrapError(err)
-		loadError = wrappedErr
-
-		// If a custom error handler is set, use it to potentially handle the error
-		if config.HandleParserError != nil {
-			err = config.HandleParserError(wrappedErr, program)
-			if err != nil {
-				return err
-			}
-		} else {
-			return wrappedErr
-		}
+		return err
 	}
 
 	var checker *sema.Checker
 	if config.Mode&NeedTypes != 0 {
-		checker, err = programs.check(config, program, location, seenImports)
+		checker, loadError, err = check(
+			config,
+			program,
+			location,
+			loadError,
+			programs.importHandler(config, location, seenImports),
+		)
 		if err != nil {
-			wrappedErr := wrapError(err)
-			if loadError == nil {
-				loadError = wrappedErr
-			}
-
-			// If a custom error handler is set, use it to potentially handle the error
-			if config.HandleCheckerError != nil {
-				err = config.HandleCheckerError(wrappedErr, checker)
-				if err != nil {
-					return err
-				}
-			} else {
-				return wrappedErr
-			}
+			return err
 		}
 	}
 
@@ -121,11 +104,104 @@ func (programs *Programs) load(
 	return nil
 }
 
-func (programs *Programs) check(
+func parse(config *Config, code []byte) (*ast.Program, error) {
+	return parser.ParseProgram(
+		nil,
+		code,
+		parser.Config{
+			TypeParametersEnabled: config.TypeParametersEnabled,
+			ErrorRecoveryEnabled:  config.ParserErrorRecoveryEnabled,
+		},
+	)
+}
+
+// handleParserError handles the error which occurred when parsing the program at the given location, if any.
+//
+// If the error is handled by the parser error handler,
+// it is returned as the load error of the program.
+// Otherwise, the error aborts the load
+func handleParserError(
+	config *Config,
+	location common.Location,
+	program *ast.Program,
+	err error,
+) (
+	*ast.Program,
+	error,
+	error,
+) {
+	if err == nil {
+		return program, nil, nil
+	}
+
+	wrappedErr := ParsingCheckingError{
+		error:    err,
+		location: location,
+	}
+
+	// If a custom error handler is set, use it to potentially handle the error
+	if config.HandleParserError == nil {
+		return nil, nil, wrappedErr
+	}
+
+	err = config.HandleParserError(wrappedErr, program)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return program, wrappedErr, nil
+}
+
+// check checks the given program at the given location,
+// using the given import handler to resolve imports.
+//
+// If checking fails and the error is handled by the checker error handler,
+// the error is returned as the load error of the program,
+// unless the program already has a load error.
+// Otherwise, the error aborts the load
+func check(
 	config *Config,
 	program *ast.Program,
 	location common.Location,
-	seenImports importResolutionResults,
+	loadError error,
+	importHandler sema.ImportHandlerFunc,
+) (
+	*sema.Checker,
+	error,
+	error,
+) {
+	checker, err := newChecker(config, program, location, importHandler)
+	if err == nil {
+		err = checker.Check()
+	}
+	if err != nil {
+		wrappedErr := ParsingCheckingError{
+			error:    err,
+			location: location,
+		}
+		if loadError == nil {
+			loadError = wrappedErr
+		}
+
+		// If a custom error handler is set, use it to potentially handle the error
+		if config.HandleCheckerError == nil {
+			return nil, nil, wrappedErr
+		}
+
+		err = config.HandleCheckerError(wrappedErr, checker)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return checker, loadError, nil
+}
+
+func newChecker(
+	config *Config,
+	program *ast.Program,
+	location common.Location,
+	importHandler sema.ImportHandlerFunc,
 ) (
 	*sema.Checker,
 	error,
@@ -135,7 +211,7 @@ func (programs *Programs) check(
 		baseValueActivation.DeclareValue(value)
 	}
 
-	checker, err := sema.NewChecker(
+	return sema.NewChecker(
 		program,
 		location,
 		nil,
@@ -149,86 +225,88 @@ func (programs *Programs) check(
 			),
 			PositionInfoEnabled:        config.Mode&NeedPositionInfo != 0,
 			ExtendedElaborationEnabled: config.Mode&NeedExtendedElaboration != 0,
-			ImportHandler: func(
-				checker *sema.Checker,
-				importedLocation common.Location,
-				importRange ast.Range,
-			) (sema.Import, error) {
-
-				var elaboration *sema.Elaboration
-				var loadError error
-
-				switch importedLocation {
-				case stdlib.CryptoContractLocation:
-					// If the elaboration for the crypto contract is available, take it.
-					elaboration = programs.CryptoContractElaboration
-					if elaboration != nil {
-						break
-					}
-
-					// Otherwise, if the location for the Crypto contract is provided,
-					// then resolve the source code from that location and continue as
-					// any other contract.
-					cryptoLocation := programs.CryptoContractLocation
-					if cryptoLocation == nil {
-						return nil, fmt.Errorf("cannot find crypto contract")
-					}
-
-					importedLocation = cryptoLocation()
-
-					// Memoize the crypto contract's elaboration, for subsequent uses.
-					defer func() {
-						programs.CryptoContractElaboration = elaboration
-					}()
-
-					fallthrough
-				default:
-					if seenImports[importedLocation] {
-						return nil, &sema.CyclicImportsError{
-							Location: importedLocation,
-							Range:    importRange,
-						}
-					}
-					seenImports[importedLocation] = true
-					defer delete(seenImports, importedLocation)
-
-					err := programs.load(config, importedLocation, location, importRange, seenImports)
-					if err != nil {
-						return nil, err
-					}
-
-					program := programs.Programs[importedLocation]
-					checker := program.Checker
-
-					// If the imported program has a checker, use its elaboration for the import
-					if checker != nil {
-						elaboration = checker.Elaboration
-					}
-
-					// If the imported program had an error while loading, record it
-					loadError = program.LoadError
-				}
+			ImportHandler:              importHandler,
+			TypeParametersEnabled:      config.TypeParametersEnabled,
+		},
+	)
+}
 
-				if loadError != nil {
-					return nil, loadError
+// importHandler returns an import handler which loads imports recursively
+func (programs *Programs) importHandler(
+	config *Config,
+	location common.Location,
+	seenImports importResolutionResults,
+) sema.ImportHandlerFunc {
+	return func(
+		_ *sema.Checker,
+		importedLocation common.Location,
+		importRange ast.Range,
+	) (sema.Import, error) {
+
+		var elaboration *sema.Elaboration
+		var loadError error
+
+		switch importedLocation {
+		case stdlib.CryptoContractLocation:
+			// If the elaboration for the crypto contract is available, take it.
+			elaboration = programs.CryptoContractElaboration
+			if elaboration != nil {
+				break
+			}
+
+			// Otherwise, if the location for the Crypto contract is provided,
+			// then resolve the source code from that location and continue as
+			// any other contract.
+			cryptoLocation := programs.CryptoContractLocation
+			if cryptoLocation == nil {
+				return nil, fmt.Errorf("cannot find crypto contract")
+			}
+
+			importedLocation = cryptoLocation()
+
+			// Memoize the crypto contract's elaboration, for subsequent uses.
+			defer func() {
+				programs.CryptoContractElaboration = elaboration
+			}()
+
+			fallthrough
+		default:
+			programs.addImport(location, importedLocation)
+
+			if seenImports[importedLocation] {
+				return nil, &sema.CyclicImportsError{
+					Location: importedLocation,
+					Range:    importRange,
 				}
+			}
+			seenImports[importedLocation] = true
+			defer delete(seenImports, importedLocation)
 
-				return sema.ElaborationImport{
-					Elaboration: elaboration,
-				}, nil
-			},
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
+			err := programs.load(config, importedLocation, location, importRange, seenImports)
+			if err != nil {
+				return nil, err
+			}
 
-	err = checker.Check()
-	if err != nil {
-		return checker, err
-	}
+			program := programs.Programs[importedLocation]
+			checker := program.Checker
+
+			// If the imported program has a checker, use its elaboration for the import
+			if checker != nil {
+				elaboration = checker.Elaboration
+			}
 
-	return checker, nil
+			// If the imported program had an error while loading, record it
+			loadError = program.LoadError
+		}
+
+		if loadError != nil {
+			return nil, loadError
+		}
+
+		return sema.ElaborationImport{
+			Elaboration: elaboration,
+		}, nil
+	}
 }
 
 func (programs *Programs) Get(location common.Location) *Program {
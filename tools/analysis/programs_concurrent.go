@@ -0,0 +1,581 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/stdlib"
+)
+
+// LoadConcurrently loads the programs at the given locations and all their imports, like Load.
+//
+// Programs are parsed and checked concurrently, bounded by the concurrency of the configuration:
+// First, the import graph is discovered, by parsing the programs breadth-first.
+// Then, each program is checked as soon as all of its imports have been checked.
+//
+// All given locations are loaded, even if loading some of them fails.
+// The loaded programs are the same as when loading the locations sequentially,
+// and if loading several locations fails, the error of the first given location is returned.
+// Only the order in which the functions of the configuration are called is not deterministic.
+//
+// If the import graph is cyclic, the locations are loaded sequentially
+func (programs *Programs) LoadConcurrently(config *Config, locations ...common.Location) error {
+	loader := newConcurrentLoader(programs, config)
+
+	roots := loader.discover(locations)
+
+	if loader.hasCycle() {
+		var firstErr error
+		for _, location := range locations {
+			err := programs.Load(config, location)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	loader.load()
+
+	for _, node := range loader.nodes {
+		if node.err != nil {
+			continue
+		}
+		programs.Programs[node.location] = &Program{
+			Location:  node.location,
+			Code:      node.code,
+			Program:   node.program,
+			Checker:   node.checker,
+			LoadError: node.loadError,
+		}
+	}
+
+	for _, root := range roots {
+		if root.err != nil {
+			return root.err
+		}
+	}
+
+	return nil
+}
+
+// concurrentLoadNode is a program in the import graph
+type concurrentLoadNode struct {
+	location          common.Location
+	importingLocation common.Location
+	importRange       ast.Range
+	code              []byte
+	program           *ast.Program
+	parserError       error
+	checker           *sema.Checker
+	loadError         error
+	// err is the error which aborted the load of the program, if any
+	err error
+	// imports are the imported programs which are not loaded yet
+	imports    []*concurrentLoadNode
+	dependents []*concurrentLoadNode
+	// pendingImports is the number of imports which are not loaded yet
+	pendingImports atomic.Int32
+}
+
+type concurrentLoader struct {
+	programs  *Programs
+	config    *Config
+	semaphore chan struct{}
+	// nodes are all programs in the import graph, in the order they were discovered
+	nodes     []*concurrentLoadNode
+	locations map[common.Location]*concurrentLoadNode
+	// panicMutex guards recovered
+	panicMutex sync.Mutex
+	// recovered is the first panic which occurred while parsing or checking a program, if any.
+	// It is re-raised in the goroutine of the caller
+	recovered any
+	// cryptoMutex guards the memoized crypto contract elaboration of the programs
+	cryptoMutex sync.Mutex
+}
+
+func newConcurrentLoader(programs *Programs, config *Config) *concurrentLoader {
+	concurrency := config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &concurrentLoader{
+		programs:  programs,
+		config:    synchronizedConfig(config),
+		semaphore: make(chan struct{}, concurrency),
+		locations: map[common.Location]*concurrentLoadNode{},
+	}
+}
+
+// synchronizedConfig returns a copy of the given configuration,
+// which ensures that the functions of the configuration are not called concurrently
+func synchronizedConfig(config *Config) *Config {
+	var mutex sync.Mutex
+
+	result := *config
+
+	if config.ResolveAddressContractNames != nil {
+		result.ResolveAddressContractNames = func(address common.Address) ([]string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return config.ResolveAddressContractNames(address)
+		}
+	}
+
+	if config.ResolveCode != nil {
+		result.ResolveCode = func(
+			location common.Location,
+			importingLocation common.Location,
+			importRange ast.Range,
+		) ([]byte, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return config.ResolveCode(location, importingLocation, importRange)
+		}
+	}
+
+	if config.HandleParserError != nil {
+		result.HandleParserError = func(err ParsingCheckingError, program *ast.Program) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return config.HandleParserError(err, program)
+		}
+	}
+
+	if config.HandleCheckerError != nil {
+		result.HandleCheckerError = func(err ParsingCheckingError, checker *sema.Checker) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return config.HandleCheckerError(err, checker)
+		}
+	}
+
+	return &result
+}
+
+// discover discovers the import graph of the programs at the given locations,
+// and returns the nodes for the given locations which are not loaded yet.
+//
+// The programs are parsed concurrently, one breadth-first level at a time,
+// so the order of the discovered nodes is deterministic
+func (l *concurrentLoader) discover(locations []common.Location) []*concurrentLoadNode {
+	roots := make([]*concurrentLoadNode, 0, len(locations))
+
+	var level []*concurrentLoadNode
+	for _, location := range locations {
+		if l.programs.Programs[location] != nil {
+			continue
+		}
+		node, added := l.node(location, nil, ast.Range{})
+		if added {
+			level = append(level, node)
+		}
+		roots = append(roots, node)
+	}
+
+	for len(level) > 0 {
+		l.run(level, l.parse)
+
+		// Imports are only loaded if type information is needed
+
+		if l.config.Mode&NeedTypes == 0 {
+			break
+		}
+
+		var nextLevel []*concurrentLoadNode
+		for _, node := range level {
+			for _, imported := range l.importedLocations(node) {
+				if l.programs.Programs[imported.location] != nil {
+					continue
+				}
+
+				importedNode, added := l.node(imported.location, node.location, imported.importRange)
+				if added {
+					nextLevel = append(nextLevel, importedNode)
+				}
+
+				node.addImport(importedNode)
+			}
+		}
+		level = nextLevel
+	}
+
+	return roots
+}
+
+// node returns the node for the given location, and adds it if it does not exist yet
+func (l *concurrentLoader) node(
+	location common.Location,
+	importingLocation common.Location,
+	importRange ast.Range,
+) (
+	node *concurrentLoadNode,
+	added bool,
+) {
+	node, ok := l.locations[location]
+	if ok {
+		return node, false
+	}
+
+	node = &concurrentLoadNode{
+		location:          location,
+		importingLocation: importingLocation,
+		importRange:       importRange,
+	}
+	l.locations[location] = node
+	l.nodes = append(l.nodes, node)
+
+	return node, true
+}
+
+func (n *concurrentLoadNode) addImport(imported *concurrentLoadNode) {
+	for _, existing := range n.imports {
+		if existing == imported {
+			return
+		}
+	}
+
+	n.imports = append(n.imports, imported)
+	imported.dependents = append(imported.dependents, n)
+	n.pendingImports.Add(1)
+}
+
+func (l *concurrentLoader) parse(node *concurrentLoadNode) {
+	code, err := l.config.ResolveCode(node.location, node.importingLocation, node.importRange)
+	if err != nil {
+		node.err = err
+		return
+	}
+
+	node.code = code
+	node.program, node.parserError = parse(l.config, code)
+}
+
+type concurrentLoadImport struct {
+	location    common.Location
+	importRange ast.Range
+}
+
+// importedLocations returns the locations imported by the given program,
+// resolved in the same way as by the checker
+func (l *concurrentLoader) importedLocations(node *concurrentLoadNode) []concurrentLoadImport {
+	program := node.program
+	if node.err != nil || program == nil {
+		return nil
+	}
+
+	locationHandler := sema.AddressLocationHandlerFunc(
+		l.config.ResolveAddressContractNames,
+	)
+
+	var imports []concurrentLoadImport
+
+	for _, declaration := range program.ImportDeclarations() {
+
+		// Imports of the address of the program itself are rejected by the checker
+
+		if len(declaration.Identifiers) == 0 {
+			if nodeLocation, ok := node.location.(common.AddressLocation); ok {
+				if importLocation, ok := declaration.Location.(common.AddressLocation); ok {
+					if nodeLocation.Address == importLocation.Address {
+						continue
+					}
+				}
+			}
+		}
+
+		// Errors are reported by the checker
+
+		resolvedLocations, err := locationHandler(declaration.Identifiers, declaration.Location)
+		if err != nil {
+			continue
+		}
+
+		importRange := ast.NewRange(
+			nil,
+			declaration.LocationPos,
+			declaration.LocationPos,
+		)
+
+		for _, resolvedLocation := range resolvedLocations {
+			location := resolvedLocation.Location
+
+			if location == stdlib.CryptoContractLocation {
+				if l.programs.CryptoContractElaboration != nil ||
+					l.programs.CryptoContractLocation == nil {
+
+					continue
+				}
+				location = l.programs.CryptoContractLocation()
+			}
+
+			imports = append(
+				imports,
+				concurrentLoadImport{
+					location:    location,
+					importRange: importRange,
+				},
+			)
+		}
+	}
+
+	return imports
+}
+
+// hasCycle returns true if the import graph is cyclic
+func (l *concurrentLoader) hasCycle() bool {
+	pendingImports := make(map[*concurrentLoadNode]int, len(l.nodes))
+
+	var ready []*concurrentLoadNode
+	for _, node := range l.nodes {
+		count := len(node.imports)
+		pendingImports[node] = count
+		if count == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	loaded := 0
+	for len(ready) > 0 {
+		node := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		loaded++
+
+		for _, dependent := range node.dependents {
+			pendingImports[dependent]--
+			if pendingImports[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	return loaded < len(l.nodes)
+}
+
+// load loads all programs of the import graph.
+// Each program is loaded as soon as all of its imports are loaded
+func (l *concurrentLoader) load() {
+	var wg sync.WaitGroup
+
+	var start func(node *concurrentLoadNode)
+	start = func(node *concurrentLoadNode) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer l.recoverPanic()
+
+			l.semaphore <- struct{}{}
+			func() {
+				defer func() {
+					<-l.semaphore
+				}()
+
+				l.finish(node)
+			}()
+
+			for _, dependent := range node.dependents {
+				if dependent.pendingImports.Add(-1) == 0 {
+					start(dependent)
+				}
+			}
+		}()
+	}
+
+	for _, node := range l.nodes {
+		if node.pendingImports.Load() == 0 {
+			start(node)
+		}
+	}
+
+	wg.Wait()
+
+	l.repanic()
+}
+
+// run calls the given function for all given nodes concurrently
+func (l *concurrentLoader) run(nodes []*concurrentLoadNode, f func(node *concurrentLoadNode)) {
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+
+	for _, node := range nodes {
+		go func() {
+			defer wg.Done()
+			defer l.recoverPanic()
+
+			l.semaphore <- struct{}{}
+			defer func() {
+				<-l.semaphore
+			}()
+
+			f(node)
+		}()
+	}
+
+	wg.Wait()
+
+	l.repanic()
+}
+
+func (l *concurrentLoader) recoverPanic() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	l.panicMutex.Lock()
+	defer l.panicMutex.Unlock()
+
+	if l.recovered == nil {
+		l.recovered = recovered
+	}
+}
+
+func (l *concurrentLoader) repanic() {
+	if l.recovered != nil {
+		panic(l.recovered)
+	}
+}
+
+// finish handles the parser error of the program, if any, and checks the program, if needed
+func (l *concurrentLoader) finish(node *concurrentLoadNode) {
+	if node.err != nil {
+		return
+	}
+
+	var err error
+	node.program, node.loadError, err = handleParserError(
+		l.config,
+		node.location,
+		node.program,
+		node.parserError,
+	)
+	if err != nil {
+		node.err = err
+		return
+	}
+
+	if l.config.Mode&NeedTypes == 0 {
+		return
+	}
+
+	node.checker, node.loadError, err = check(
+		l.config,
+		node.program,
+		node.location,
+		node.loadError,
+		l.importHandler,
+	)
+	if err != nil {
+		node.err = err
+	}
+}
+
+// importHandler resolves imports to the already loaded imported programs
+func (l *concurrentLoader) importHandler(
+	checker *sema.Checker,
+	importedLocation common.Location,
+	_ ast.Range,
+) (sema.Import, error) {
+
+	var elaboration *sema.Elaboration
+	var loadError error
+
+	if importedLocation == stdlib.CryptoContractLocation {
+		// If the elaboration for the crypto contract is available, take it.
+		elaboration = l.cryptoContractElaboration()
+		if elaboration != nil {
+			return sema.ElaborationImport{
+				Elaboration: elaboration,
+			}, nil
+		}
+
+		// Otherwise, if the location for the Crypto contract is provided,
+		// then continue with the program at that location as any other contract.
+		cryptoLocation := l.programs.CryptoContractLocation
+		if cryptoLocation == nil {
+			return nil, fmt.Errorf("cannot find crypto contract")
+		}
+
+		importedLocation = cryptoLocation()
+
+		// Memoize the crypto contract's elaboration, for subsequent uses.
+		defer func() {
+			l.setCryptoContractElaboration(elaboration)
+		}()
+	}
+
+	l.programs.addImport(checker.Location, importedLocation)
+
+	if program := l.programs.Programs[importedLocation]; program != nil {
+		if program.Checker != nil {
+			elaboration = program.Checker.Elaboration
+		}
+		loadError = program.LoadError
+	} else {
+		node := l.locations[importedLocation]
+		if node == nil {
+			return nil, fmt.Errorf("cannot find imported program: %s", importedLocation)
+		}
+
+		// The imported program was loaded before the importing program,
+		// see load
+
+		if node.err != nil {
+			return nil, node.err
+		}
+
+		if node.checker != nil {
+			elaboration = node.checker.Elaboration
+		}
+		loadError = node.loadError
+	}
+
+	if loadError != nil {
+		return nil, loadError
+	}
+
+	return sema.ElaborationImport{
+		Elaboration: elaboration,
+	}, nil
+}
+
+func (l *concurrentLoader) cryptoContractElaboration() *sema.Elaboration {
+	l.cryptoMutex.Lock()
+	defer l.cryptoMutex.Unlock()
+
+	return l.programs.CryptoContractElaboration
+}
+
+func (l *concurrentLoader) setCryptoContractElaboration(elaboration *sema.Elaboration) {
+	l.cryptoMutex.Lock()
+	defer l.cryptoMutex.Unlock()
+
+	l.programs.CryptoContractElaboration = elaboration
+}
@@ -0,0 +1,50 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codemods
+
+import (
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// AccessModifiersAnalyzer replaces the removed access modifiers
+// `pub` and `priv` with `access(all)` and `access(self)`
+var AccessModifiersAnalyzer = &analysis.Analyzer{
+	Description: "Replaces the removed access modifiers `pub` and `priv` with `access(all)` and `access(self)`",
+	Run: func(pass *analysis.Pass) interface{} {
+		code := string(pass.Program.Code)
+
+		for _, err := range programErrors(pass.Program) {
+			replacementErr, ok := err.(*parser.SyntaxErrorWithSuggestedReplacement)
+			if !ok {
+				continue
+			}
+
+			reportFixes(
+				pass,
+				"access-modifiers",
+				replacementErr,
+				replacementErr.Range,
+				replacementErr.SuggestFixes(code),
+			)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,61 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codemods
+
+import (
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// CheckerFixesAnalyzer reports the fixes suggested by the checker,
+// e.g. for missing or incorrect argument labels
+var CheckerFixesAnalyzer = &analysis.Analyzer{
+	Description: "Applies the fixes suggested by the checker, e.g. for missing or incorrect argument labels",
+	Run: func(pass *analysis.Pass) interface{} {
+		code := string(pass.Program.Code)
+
+		for _, err := range programErrors(pass.Program) {
+			if _, ok := err.(sema.SemanticError); !ok {
+				continue
+			}
+
+			fixer, ok := err.(errors.HasSuggestedFixes[ast.TextEdit])
+			if !ok {
+				continue
+			}
+
+			errRange, ok := errorRange(err)
+			if !ok {
+				continue
+			}
+
+			reportFixes(
+				pass,
+				"checker-fixes",
+				err,
+				errRange,
+				fixer.SuggestFixes(code),
+			)
+		}
+
+		return nil
+	},
+}
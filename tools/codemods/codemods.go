@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package codemods provides analyzers which suggest fixes
+// for automatically upgrading programs, e.g. to replace removed syntax.
+//
+// The suggested fixes can be applied using analysis.ApplySuggestedFixes.
+package codemods
+
+import (
+	"sort"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const Category = "upgrade"
+
+// Analyzers are all codemods, by name
+var Analyzers = map[string]*analysis.Analyzer{
+	"access-modifiers": AccessModifiersAnalyzer,
+	"restricted-types": RestrictedTypesAnalyzer,
+	"checker-fixes":    CheckerFixesAnalyzer,
+}
+
+// AnalyzerNames returns the names of all codemods, sorted
+func AnalyzerNames() []string {
+	names := make([]string, 0, len(Analyzers))
+	for name := range Analyzers { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// programErrors returns the errors of the given program, i.e. the leaves of its load error.
+// Errors of imported programs are not included, as they cannot be fixed in this program
+func programErrors(program *analysis.Program) []error {
+	var result []error
+
+	var walk func(err error)
+	walk = func(err error) {
+		switch err := err.(type) {
+		case *sema.ImportedProgramError:
+			return
+
+		case errors.ParentError:
+			for _, child := range err.ChildErrors() {
+				walk(child)
+			}
+
+		default:
+			result = append(result, err)
+		}
+	}
+
+	if program.LoadError != nil {
+		walk(program.LoadError)
+	}
+
+	return result
+}
+
+// reportFixes reports a diagnostic for the given error, with the given suggested fixes
+func reportFixes(
+	pass *analysis.Pass,
+	code string,
+	err error,
+	errorRange ast.Range,
+	fixes []analysis.SuggestedFix,
+) {
+	if len(fixes) == 0 {
+		return
+	}
+
+	pass.Report(
+		analysis.Diagnostic{
+			Location:       pass.Program.Location,
+			Category:       Category,
+			Code:           code,
+			Message:        err.Error(),
+			SuggestedFixes: fixes,
+			Range:          errorRange,
+		},
+	)
+}
+
+// errorRange returns the range of the given error, if it has a position
+func errorRange(err error) (ast.Range, bool) {
+	positioned, ok := err.(ast.HasPosition)
+	if !ok {
+		return ast.Range{}, false
+	}
+	return ast.NewUnmeteredRangeFromPositioned(positioned), true
+}
@@ -0,0 +1,185 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codemods_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/codemods"
+)
+
+func testFix(t *testing.T, code string, analyzers ...*analysis.Analyzer) (string, []analysis.SuggestedFix) {
+	if len(analyzers) == 0 {
+		for _, name := range codemods.AnalyzerNames() {
+			analyzers = append(analyzers, codemods.Analyzers[name])
+		}
+	}
+
+	result, fixes, err := codemods.Fix(
+		&analysis.Config{},
+		common.StringLocation("test"),
+		[]byte(code),
+		analyzers,
+	)
+	require.NoError(t, err)
+
+	return string(result), fixes
+}
+
+func TestAccessModifiers(t *testing.T) {
+
+	t.Parallel()
+
+	result, fixes := testFix(t,
+		`
+          pub contract C {
+              pub let x: Int
+              priv fun f() {}
+              init() { self.x = 1 }
+          }
+        `,
+		codemods.AccessModifiersAnalyzer,
+	)
+
+	assert.Equal(t,
+		`
+          access(all) contract C {
+              access(all) let x: Int
+              access(self) fun f() {}
+              init() { self.x = 1 }
+          }
+        `,
+		result,
+	)
+	assert.Len(t, fixes, 3)
+}
+
+func TestRestrictedTypes(t *testing.T) {
+
+	t.Parallel()
+
+	result, fixes := testFix(t,
+		`
+          access(all) resource interface I {}
+          access(all) resource interface J {}
+          access(all) resource R: I, J {}
+
+          access(all) fun f(a: &R{I}, b: &AnyResource{I, J}): @AnyResource{I} {
+              return <-create R()
+          }
+        `,
+		codemods.RestrictedTypesAnalyzer,
+	)
+
+	assert.Equal(t,
+		`
+          access(all) resource interface I {}
+          access(all) resource interface J {}
+          access(all) resource R: I, J {}
+
+          access(all) fun f(a: &R, b: &{I, J}): @{I} {
+              return <-create R()
+          }
+        `,
+		result,
+	)
+	assert.Len(t, fixes, 3)
+}
+
+func TestCheckerFixes(t *testing.T) {
+
+	t.Parallel()
+
+	result, fixes := testFix(t,
+		`
+          access(all) fun add(a: Int, b: Int): Int {
+              return a + b
+          }
+
+          access(all) let x = add(1, c: 2)
+        `,
+		codemods.CheckerFixesAnalyzer,
+	)
+
+	assert.Equal(t,
+		`
+          access(all) fun add(a: Int, b: Int): Int {
+              return a + b
+          }
+
+          access(all) let x = add(a: 1, b: 2)
+        `,
+		result,
+	)
+	assert.Len(t, fixes, 2)
+}
+
+func TestFixAll(t *testing.T) {
+
+	t.Parallel()
+
+	// The checker fixes are only suggested once the program parses
+
+	result, _ := testFix(t, `
+      pub resource interface I {}
+      pub resource R: I {}
+
+      pub fun add(a: Int, b: Int): Int {
+          return a + b
+      }
+
+      pub fun test(r: &R{I}): Int {
+          return add(1, 2)
+      }
+    `)
+
+	assert.Equal(t,
+		`
+      access(all) resource interface I {}
+      access(all) resource R: I {}
+
+      access(all) fun add(a: Int, b: Int): Int {
+          return a + b
+      }
+
+      access(all) fun test(r: &R): Int {
+          return add(a: 1, b: 2)
+      }
+    `,
+		result,
+	)
+}
+
+func TestFixNoChanges(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      access(all) fun test() {}
+    `
+
+	result, fixes := testFix(t, code)
+	assert.Equal(t, code, result)
+	assert.Empty(t, fixes)
+}
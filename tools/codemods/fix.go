@@ -0,0 +1,246 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codemods
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// MaxFixRounds is the maximum number of times the analyzers are run on a program.
+//
+// Fixes may only become available after other fixes were applied,
+// e.g. checker fixes are only suggested once the program parses successfully
+const MaxFixRounds = 10
+
+// VerificationError is returned when applying fixes
+// resulted in a program which has more errors than before
+type VerificationError struct {
+	Location common.Location
+	Before   int
+	After    int
+}
+
+func (e VerificationError) Error() string {
+	return fmt.Sprintf(
+		"fixes for %s were not applied: the fixed program has %d errors, the original program has %d errors",
+		e.Location,
+		e.After,
+		e.Before,
+	)
+}
+
+// Fix runs the given analyzers on the program with the given location and code,
+// and applies the first suggested fix of each reported diagnostic.
+// Overlapping fixes are skipped.
+//
+// The analyzers are run repeatedly until no more fixes are suggested,
+// at most MaxFixRounds times.
+// After each round, the fixed program is parsed and checked again,
+// and the fixes are only kept if the program has no more errors than before.
+//
+// The config is used to resolve imports.
+// The given code is used for the program instead of resolving it.
+//
+// It returns the fixed code and the applied fixes.
+// If a round of fixes failed to verify, the fixes of the previous rounds
+// are returned together with a VerificationError.
+func Fix(
+	config *analysis.Config,
+	location common.Location,
+	code []byte,
+	analyzers []*analysis.Analyzer,
+) (
+	[]byte,
+	[]analysis.SuggestedFix,
+	error,
+) {
+	var appliedFixes []analysis.SuggestedFix
+
+	program, err := loadProgram(config, location, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for round := 0; round < MaxFixRounds; round++ {
+
+		fixes := suggestedFixes(program, analyzers)
+		if len(fixes) == 0 {
+			break
+		}
+
+		fixedCode, applied, _ := analysis.ApplySuggestedFixes(code, fixes)
+		if len(applied) == 0 || bytes.Equal(fixedCode, code) {
+			break
+		}
+
+		fixedProgram, err := loadProgram(config, location, fixedCode)
+		if err != nil {
+			return code, appliedFixes, err
+		}
+
+		before := countErrors(program)
+		after := countErrors(fixedProgram)
+		if !after.isImprovementOf(before) {
+			return code, appliedFixes, VerificationError{
+				Location: location,
+				Before:   before.total(),
+				After:    after.total(),
+			}
+		}
+
+		code = fixedCode
+		program = fixedProgram
+		appliedFixes = append(appliedFixes, applied...)
+	}
+
+	return code, appliedFixes, nil
+}
+
+// loadProgram parses and checks the given code for the given location.
+// Programs with errors are loaded, so their errors can be fixed
+func loadProgram(
+	config *analysis.Config,
+	location common.Location,
+	code []byte,
+) (
+	*analysis.Program,
+	error,
+) {
+	fixConfig := *config
+	fixConfig.Mode |= analysis.NeedTypes
+
+	fixConfig.ResolveCode = func(
+		importedLocation common.Location,
+		importingLocation common.Location,
+		importRange ast.Range,
+	) ([]byte, error) {
+		if importedLocation == location {
+			return code, nil
+		}
+		if config.ResolveCode == nil {
+			return nil, fmt.Errorf("cannot import %s", importedLocation)
+		}
+		return config.ResolveCode(importedLocation, importingLocation, importRange)
+	}
+
+	if fixConfig.HandleParserError == nil {
+		fixConfig.HandleParserError = func(err analysis.ParsingCheckingError, program *ast.Program) error {
+			if program == nil {
+				return err
+			}
+			return nil
+		}
+	}
+
+	if fixConfig.HandleCheckerError == nil {
+		fixConfig.HandleCheckerError = func(_ analysis.ParsingCheckingError, _ *sema.Checker) error {
+			return nil
+		}
+	}
+
+	programs := &analysis.Programs{
+		Programs: map[common.Location]*analysis.Program{},
+	}
+
+	err := programs.Load(&fixConfig, location)
+	if err != nil {
+		return nil, err
+	}
+
+	return programs.Get(location), nil
+}
+
+// suggestedFixes runs the given analyzers on the given program
+// and returns the first suggested fix of each reported diagnostic,
+// in order of the position of the diagnostics
+func suggestedFixes(program *analysis.Program, analyzers []*analysis.Analyzer) []analysis.SuggestedFix {
+
+	var diagnostics []analysis.Diagnostic
+	var mutex sync.Mutex
+
+	// Analyzers may be run concurrently
+	program.Run(analyzers, func(diagnostic analysis.Diagnostic) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		diagnostics = append(diagnostics, diagnostic)
+	})
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a := diagnostics[i]
+		b := diagnostics[j]
+		if a.StartPos.Offset != b.StartPos.Offset {
+			return a.StartPos.Offset < b.StartPos.Offset
+		}
+		return a.Code < b.Code
+	})
+
+	fixes := make([]analysis.SuggestedFix, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		if len(diagnostic.SuggestedFixes) == 0 {
+			continue
+		}
+		fixes = append(fixes, diagnostic.SuggestedFixes[0])
+	}
+
+	return fixes
+}
+
+// errorCounts are the number of parser and checker errors of a program
+type errorCounts struct {
+	parsing  int
+	checking int
+}
+
+func countErrors(program *analysis.Program) (counts errorCounts) {
+	for _, err := range programErrors(program) {
+		switch err.(type) {
+		case parser.ParseError:
+			counts.parsing++
+		default:
+			counts.checking++
+		}
+	}
+	return
+}
+
+func (c errorCounts) total() int {
+	return c.parsing + c.checking
+}
+
+// isImprovementOf returns true if the program has no more errors than the given original program.
+//
+// A program is only checked if it can be parsed,
+// so checker errors may first be reported once parser errors are fixed.
+// If the original program had parser errors, only the parser errors are compared
+func (c errorCounts) isImprovementOf(original errorCounts) bool {
+	if original.parsing > 0 {
+		return c.parsing <= original.parsing
+	}
+	return c.parsing == 0 && c.checking <= original.checking
+}
@@ -0,0 +1,165 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codemods
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/parser/lexer"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// RestrictedTypesAnalyzer replaces the removed restricted types:
+// A restricted type `T{I}` is replaced with the concrete type `T`,
+// and `AnyStruct{I}` and `AnyResource{I}` are replaced with the intersection type `{I}`
+var RestrictedTypesAnalyzer = &analysis.Analyzer{
+	Description: "Replaces restricted types with the concrete type or an equivalent intersection type",
+	Run: func(pass *analysis.Pass) interface{} {
+		program := pass.Program
+
+		var restrictedTypeErrors []*parser.RestrictedTypeError
+		for _, err := range programErrors(program) {
+			restrictedTypeErr, ok := err.(*parser.RestrictedTypeError)
+			if !ok {
+				continue
+			}
+			restrictedTypeErrors = append(restrictedTypeErrors, restrictedTypeErr)
+		}
+
+		if len(restrictedTypeErrors) == 0 {
+			return nil
+		}
+
+		// The restricted type error only reports the position of the first restriction,
+		// so use the tokens of the program to find the whole restricted type.
+		// The syntax tree is also produced if parsing fails
+
+		tree, _ := parser.ParseSyntaxTree(
+			nil,
+			program.Code,
+			parser.Config{
+				TypeParametersEnabled: true,
+			},
+		)
+		if tree == nil {
+			return nil
+		}
+
+		for _, restrictedTypeErr := range restrictedTypeErrors {
+			fix, ok := restrictedTypeFix(tree, restrictedTypeErr.StartPos.Offset)
+			if !ok {
+				continue
+			}
+
+			reportFixes(
+				pass,
+				"restricted-types",
+				restrictedTypeErr,
+				restrictedTypeErr.Range,
+				[]analysis.SuggestedFix{fix},
+			)
+		}
+
+		return nil
+	},
+}
+
+// restrictedTypeFix returns the fix for the restricted type
+// which has its first restriction at the given offset.
+//
+// The restricted type must be a nominal type, e.g. `T{I}` or `C.R{I, J}`
+func restrictedTypeFix(tree *parser.SyntaxTree, offset int) (analysis.SuggestedFix, bool) {
+	tokens := tree.Tokens
+
+	index := sort.Search(len(tokens), func(i int) bool {
+		return tokens[i].StartPos.Offset >= offset
+	})
+	if index < 2 ||
+		index >= len(tokens) ||
+		tokens[index].StartPos.Offset != offset ||
+		tokens[index-1].Type != lexer.TokenBraceOpen ||
+		tokens[index-2].Type != lexer.TokenIdentifier {
+
+		return analysis.SuggestedFix{}, false
+	}
+
+	// Find the start of the restricted type, e.g. `C.R`
+
+	braceOpenIndex := index - 1
+	typeEndIndex := index - 2
+	typeStartIndex := typeEndIndex
+	for typeStartIndex >= 2 &&
+		tokens[typeStartIndex-1].Type == lexer.TokenDot &&
+		tokens[typeStartIndex-2].Type == lexer.TokenIdentifier {
+
+		typeStartIndex -= 2
+	}
+
+	// Find the end of the restrictions
+
+	braceCloseIndex := index
+	for braceCloseIndex < len(tokens) {
+		switch tokens[braceCloseIndex].Type {
+		case lexer.TokenIdentifier, lexer.TokenDot, lexer.TokenComma:
+			braceCloseIndex++
+			continue
+		}
+		break
+	}
+	if braceCloseIndex >= len(tokens) ||
+		tokens[braceCloseIndex].Type != lexer.TokenBraceClose {
+
+		return analysis.SuggestedFix{}, false
+	}
+
+	restrictedType := string(tree.Text(ast.Range{
+		StartPos: tokens[typeStartIndex].StartPos,
+		EndPos:   tokens[typeEndIndex].EndPos,
+	}))
+
+	restrictions := string(tree.Text(ast.Range{
+		StartPos: tokens[braceOpenIndex].StartPos,
+		EndPos:   tokens[braceCloseIndex].EndPos,
+	}))
+
+	var replacement string
+	switch restrictedType {
+	case sema.AnyStructType.Name, sema.AnyResourceType.Name:
+		replacement = restrictions
+	default:
+		replacement = restrictedType
+	}
+
+	return analysis.SuggestedFix{
+		Message: fmt.Sprintf("replace with %s", replacement),
+		TextEdits: []analysis.TextEdit{
+			{
+				Replacement: replacement,
+				Range: ast.Range{
+					StartPos: tokens[typeStartIndex].StartPos,
+					EndPos:   tokens[braceCloseIndex].EndPos,
+				},
+			},
+		},
+	}, true
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"log"
 	"reflect"
+	"runtime"
 	"strings"
 
 	"github.com/onflow/cadence/ast"
@@ -65,6 +66,7 @@ func (c *ContractsChecker) CheckCSV(csvReader io.Reader) {
 		contractNames,
 		nil,
 	)
+	analysisConfig.Concurrency = runtime.NumCPU()
 
 	c.analyze(analysisConfig, locations)
 }
@@ -144,6 +146,11 @@ func (c *ContractsChecker) analyze(
 
 	log.Println("Checking contracts ...")
 
+	// Load all contracts concurrently first.
+	// Contracts which fail to load are not cached, and get loaded again below to report their errors
+
+	_ = programs.LoadConcurrently(config, locations...)
+
 	for _, location := range locations {
 		log.Printf("Checking %s", location.Description())
 
@@ -0,0 +1,223 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package diagnostics provides a machine-readable representation of
+// parser errors, checker errors, and analysis diagnostics,
+// and writes them as JSON or SARIF.
+package diagnostics
+
+import (
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Position is a position in the source code.
+// Like in the AST, lines are 1-based, and columns are 0-based and counted in runes
+type Position struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func NewPosition(position ast.Position) Position {
+	return Position{
+		Offset: position.Offset,
+		Line:   position.Line,
+		Column: position.Column,
+	}
+}
+
+// Range is a range in the source code.
+// Like in the AST, the end position is inclusive
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+func NewRange(hasPosition ast.HasPosition) *Range {
+	return &Range{
+		Start: NewPosition(hasPosition.StartPosition()),
+		End:   NewPosition(hasPosition.EndPosition(nil)),
+	}
+}
+
+// Note is additional information about a diagnostic, e.g. the location of a previous declaration
+type Note struct {
+	Message string `json:"message"`
+	Range   *Range `json:"range,omitempty"`
+}
+
+// TextEdit is an edit of the source code.
+// If the insertion is not empty, it is inserted at the start of the range.
+// Otherwise, the range is replaced with the replacement
+type TextEdit struct {
+	Replacement string `json:"replacement,omitempty"`
+	Insertion   string `json:"insertion,omitempty"`
+	Range       Range  `json:"range"`
+}
+
+type SuggestedFix struct {
+	Message   string     `json:"message"`
+	TextEdits []TextEdit `json:"textEdits"`
+}
+
+// Diagnostic is an error or a warning about a program
+type Diagnostic struct {
+	Location         string         `json:"location"`
+	Severity         Severity       `json:"severity"`
+	Category         string         `json:"category,omitempty"`
+	Code             string         `json:"code,omitempty"`
+	Message          string         `json:"message"`
+	SecondaryMessage string         `json:"secondaryMessage,omitempty"`
+	URL              string         `json:"url,omitempty"`
+	Range            *Range         `json:"range,omitempty"`
+	Notes            []Note         `json:"notes,omitempty"`
+	SuggestedFixes   []SuggestedFix `json:"suggestedFixes,omitempty"`
+}
+
+func newSuggestedFixes(fixes []errors.SuggestedFix[ast.TextEdit]) []SuggestedFix {
+	if len(fixes) == 0 {
+		return nil
+	}
+
+	result := make([]SuggestedFix, 0, len(fixes))
+	for _, fix := range fixes {
+		textEdits := make([]TextEdit, 0, len(fix.TextEdits))
+		for _, textEdit := range fix.TextEdits {
+			textEdits = append(textEdits, TextEdit{
+				Replacement: textEdit.Replacement,
+				Insertion:   textEdit.Insertion,
+				Range:       *NewRange(textEdit.Range),
+			})
+		}
+
+		result = append(result, SuggestedFix{
+			Message:   fix.Message,
+			TextEdits: textEdits,
+		})
+	}
+	return result
+}
+
+func locationString(location common.Location) string {
+	if location == nil {
+		return ""
+	}
+	return location.String()
+}
+
+// FromError returns the diagnostics for the given parser or checker error
+// of the program with the given location.
+//
+// Errors which contain other errors, e.g. the errors of imported programs,
+// result in a diagnostic for each contained error.
+// The codes are used to suggest fixes, and are indexed by location
+func FromError(
+	err error,
+	location common.Location,
+	codes map[common.Location][]byte,
+) []Diagnostic {
+
+	var diagnostics []Diagnostic
+
+	var walk func(err error, location common.Location)
+	walk = func(err error, location common.Location) {
+
+		if err, ok := err.(common.HasLocation); ok {
+			importLocation := err.ImportLocation()
+			if importLocation != nil {
+				location = importLocation
+			}
+		}
+
+		if err, ok := err.(errors.ParentError); ok {
+			for _, childErr := range err.ChildErrors() {
+				walk(childErr, location)
+			}
+			return
+		}
+
+		diagnostics = append(diagnostics, newErrorDiagnostic(err, location, codes[location]))
+	}
+
+	walk(err, location)
+
+	return diagnostics
+}
+
+func newErrorDiagnostic(err error, location common.Location, code []byte) Diagnostic {
+	diagnostic := Diagnostic{
+		Location: locationString(location),
+		Severity: SeverityError,
+		Message:  err.Error(),
+	}
+
+	if errorCode, ok := errors.GetErrorCode(err); ok {
+		diagnostic.Code = string(errorCode)
+	}
+
+	if positioned, ok := err.(ast.HasPosition); ok {
+		diagnostic.Range = NewRange(positioned)
+	}
+
+	if secondaryError, ok := err.(errors.SecondaryError); ok {
+		diagnostic.SecondaryMessage = secondaryError.SecondaryError()
+	}
+
+	if errorNotes, ok := err.(errors.ErrorNotes); ok {
+		for _, errorNote := range errorNotes.ErrorNotes() {
+			note := Note{
+				Message: errorNote.Message(),
+			}
+			if positioned, ok := errorNote.(ast.HasPosition); ok {
+				note.Range = NewRange(positioned)
+			}
+			diagnostic.Notes = append(diagnostic.Notes, note)
+		}
+	}
+
+	if fixer, ok := err.(errors.HasSuggestedFixes[ast.TextEdit]); ok {
+		diagnostic.SuggestedFixes = newSuggestedFixes(fixer.SuggestFixes(string(code)))
+	}
+
+	return diagnostic
+}
+
+// FromAnalysis returns the diagnostic for the given analysis diagnostic.
+// Analysis diagnostics are warnings
+func FromAnalysis(diagnostic analysis.Diagnostic) Diagnostic {
+	return Diagnostic{
+		Location:         locationString(diagnostic.Location),
+		Severity:         SeverityWarning,
+		Category:         diagnostic.Category,
+		Code:             diagnostic.Code,
+		Message:          diagnostic.Message,
+		SecondaryMessage: diagnostic.SecondaryMessage,
+		URL:              diagnostic.URL,
+		Range:            NewRange(diagnostic.Range),
+		SuggestedFixes:   newSuggestedFixes(diagnostic.SuggestedFixes),
+	}
+}
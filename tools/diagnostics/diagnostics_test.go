@@ -0,0 +1,284 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/sema"
+	. "github.com/onflow/cadence/test_utils/common_utils"
+	. "github.com/onflow/cadence/test_utils/sema_utils"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/diagnostics"
+)
+
+const testCode = `
+access(all) let x = 1
+access(all) let x = 2
+
+access(all) fun f(a: Int) {}
+
+access(all) let y = f(1)
+`
+
+func testErrorDiagnostics(t *testing.T) []diagnostics.Diagnostic {
+	location := common.StringLocation("test")
+
+	_, err := ParseAndCheckWithOptions(t,
+		testCode,
+		ParseAndCheckOptions{
+			Location: location,
+		},
+	)
+	require.Error(t, err)
+
+	return diagnostics.FromError(
+		err,
+		location,
+		map[common.Location][]byte{
+			location: []byte(testCode),
+		},
+	)
+}
+
+func testRange(offset, line, column int) *diagnostics.Range {
+	position := diagnostics.Position{
+		Offset: offset,
+		Line:   line,
+		Column: column,
+	}
+	return &diagnostics.Range{
+		Start: position,
+		End:   position,
+	}
+}
+
+func TestFromError(t *testing.T) {
+
+	t.Parallel()
+
+	AssertEqualWithDiff(t,
+		[]diagnostics.Diagnostic{
+			{
+				Location: "test",
+				Severity: diagnostics.SeverityError,
+				Code:     string(sema.ErrorCodeRedeclaration),
+				Message:  "cannot redeclare constant: `x` is already declared",
+				Range:    testRange(39, 3, 16),
+				Notes: []diagnostics.Note{
+					{
+						Message: "previously declared here",
+						Range:   testRange(17, 2, 16),
+					},
+				},
+			},
+			{
+				Location: "test",
+				Severity: diagnostics.SeverityError,
+				Code:     string(sema.ErrorCodeMissingArgumentLabel),
+				Message:  "missing argument label: `a`",
+				Range:    testRange(98, 7, 22),
+				SuggestedFixes: []diagnostics.SuggestedFix{
+					{
+						Message: "insert argument label",
+						TextEdits: []diagnostics.TextEdit{
+							{
+								Insertion: "a: ",
+								Range:     *testRange(98, 7, 22),
+							},
+						},
+					},
+				},
+			},
+		},
+		testErrorDiagnostics(t),
+	)
+}
+
+func TestFromAnalysis(t *testing.T) {
+
+	t.Parallel()
+
+	AssertEqualWithDiff(t,
+		diagnostics.Diagnostic{
+			Location:         "test",
+			Severity:         diagnostics.SeverityWarning,
+			Category:         "security",
+			Code:             "test-code",
+			Message:          "message",
+			SecondaryMessage: "secondary message",
+			URL:              "https://example.com",
+			Range: &diagnostics.Range{
+				Start: diagnostics.Position{Offset: 1, Line: 2, Column: 3},
+				End:   diagnostics.Position{Offset: 4, Line: 5, Column: 6},
+			},
+		},
+		diagnostics.FromAnalysis(analysis.Diagnostic{
+			Location:         common.StringLocation("test"),
+			Category:         "security",
+			Code:             "test-code",
+			Message:          "message",
+			SecondaryMessage: "secondary message",
+			URL:              "https://example.com",
+			Range: ast.Range{
+				StartPos: ast.Position{Offset: 1, Line: 2, Column: 3},
+				EndPos:   ast.Position{Offset: 4, Line: 5, Column: 6},
+			},
+		}),
+	)
+}
+
+func TestWriteJSON(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		var buffer bytes.Buffer
+		err := diagnostics.WriteJSON(&buffer, nil)
+		require.NoError(t, err)
+
+		require.JSONEq(t, `[]`, buffer.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+
+		t.Parallel()
+
+		expected := testErrorDiagnostics(t)
+
+		var buffer bytes.Buffer
+		err := diagnostics.WriteJSON(&buffer, expected)
+		require.NoError(t, err)
+
+		var actual []diagnostics.Diagnostic
+		err = json.Unmarshal(buffer.Bytes(), &actual)
+		require.NoError(t, err)
+
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestWriteSARIF(t *testing.T) {
+
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	err := diagnostics.WriteSARIF(
+		&buffer,
+		diagnostics.Tool{
+			Name: "test",
+			Rules: []diagnostics.Rule{
+				{
+					ID:          "test-rule",
+					Description: "test rule",
+				},
+			},
+		},
+		testErrorDiagnostics(t),
+	)
+	require.NoError(t, err)
+
+	require.JSONEq(t,
+		`
+        {
+          "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+          "version": "2.1.0",
+          "runs": [
+            {
+              "tool": {
+                "driver": {
+                  "name": "test",
+                  "rules": [
+                    {
+                      "id": "test-rule",
+                      "shortDescription": {"text": "test rule"}
+                    }
+                  ]
+                }
+              },
+              "columnKind": "unicodeCodePoints",
+              "results": [
+                {
+                  "ruleId": "E2002",
+                  "level": "error",
+                  "message": {"text": "cannot redeclare constant: `+"`x`"+` is already declared"},
+                  "locations": [
+                    {
+                      "physicalLocation": {
+                        "artifactLocation": {"uri": "test"},
+                        "region": {"startLine": 3, "startColumn": 17, "endLine": 3, "endColumn": 18}
+                      }
+                    }
+                  ],
+                  "relatedLocations": [
+                    {
+                      "id": 1,
+                      "physicalLocation": {
+                        "artifactLocation": {"uri": "test"},
+                        "region": {"startLine": 2, "startColumn": 17, "endLine": 2, "endColumn": 18}
+                      },
+                      "message": {"text": "previously declared here"}
+                    }
+                  ]
+                },
+                {
+                  "ruleId": "E2013",
+                  "level": "error",
+                  "message": {"text": "missing argument label: `+"`a`"+`"},
+                  "locations": [
+                    {
+                      "physicalLocation": {
+                        "artifactLocation": {"uri": "test"},
+                        "region": {"startLine": 7, "startColumn": 23, "endLine": 7, "endColumn": 24}
+                      }
+                    }
+                  ],
+                  "fixes": [
+                    {
+                      "description": {"text": "insert argument label"},
+                      "artifactChanges": [
+                        {
+                          "artifactLocation": {"uri": "test"},
+                          "replacements": [
+                            {
+                              "deletedRegion": {"startLine": 7, "startColumn": 23, "endLine": 7, "endColumn": 23},
+                              "insertedContent": {"text": "a: "}
+                            }
+                          ]
+                        }
+                      ]
+                    }
+                  ]
+                }
+              ]
+            }
+          ]
+        }
+        `,
+		buffer.String(),
+	)
+}
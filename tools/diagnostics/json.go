@@ -15,43 +15,20 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
-package main
+package diagnostics
 
 import (
 	"encoding/json"
-	"sort"
-
-	"github.com/onflow/flow-go/cmd/util/ledger/util/registers"
-
-	"github.com/onflow/cadence/runtime/common"
+	"io"
 )
 
-func addressesJSON(registersByAccount *registers.ByAccount) ([]byte, error) {
-
-	var addresses []string
-
-	err := registersByAccount.ForEachAccount(func(accountRegisters *registers.AccountRegisters) error {
-		owner := accountRegisters.Owner()
-		if len(owner) == 0 {
-			return nil
-		}
-
-		address := common.Address([]byte(owner)).Hex()
-		addresses = append(addresses, address)
-
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	sort.Strings(addresses)
-
-	encoded, err := json.Marshal(addresses)
-	if err != nil {
-		return nil, err
+// WriteJSON writes the given diagnostics as an indented JSON array
+func WriteJSON(writer io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
 	}
 
-	return encoded, nil
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diagnostics)
 }
@@ -0,0 +1,275 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// SARIF 2.1.0, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// Tool describes the tool which produced the diagnostics
+type Tool struct {
+	Name           string
+	Version        string
+	InformationURI string
+	Rules          []Rule
+}
+
+// Rule describes a kind of diagnostic, identified by its code
+type Rule struct {
+	ID          string
+	Description string
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId,omitempty"`
+	Level            Severity        `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+	HelpURI          string          `json:"helpUri,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               *int                  `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion is a region of an artifact.
+// Lines and columns are 1-based, and the end column is exclusive
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+func newSARIFRegion(r *Range) *sarifRegion {
+	if r == nil {
+		return nil
+	}
+	return &sarifRegion{
+		StartLine:   r.Start.Line,
+		StartColumn: r.Start.Column + 1,
+		EndLine:     r.End.Line,
+		EndColumn:   r.End.Column + 2,
+	}
+}
+
+func newSARIFLocation(location string, r *Range) sarifLocation {
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI: location,
+			},
+			Region: newSARIFRegion(r),
+		},
+	}
+}
+
+func newSARIFFix(location string, fix SuggestedFix) sarifFix {
+	replacements := make([]sarifReplacement, 0, len(fix.TextEdits))
+
+	for _, textEdit := range fix.TextEdits {
+		var replacement sarifReplacement
+
+		if textEdit.Insertion != "" {
+			// An insertion deletes an empty region at the start of the range
+			start := textEdit.Range.Start
+			replacement = sarifReplacement{
+				DeletedRegion: sarifRegion{
+					StartLine:   start.Line,
+					StartColumn: start.Column + 1,
+					EndLine:     start.Line,
+					EndColumn:   start.Column + 1,
+				},
+				InsertedContent: sarifMessage{
+					Text: textEdit.Insertion,
+				},
+			}
+		} else {
+			replacement = sarifReplacement{
+				DeletedRegion: *newSARIFRegion(&textEdit.Range),
+				InsertedContent: sarifMessage{
+					Text: textEdit.Replacement,
+				},
+			}
+		}
+
+		replacements = append(replacements, replacement)
+	}
+
+	return sarifFix{
+		Description: sarifMessage{
+			Text: fix.Message,
+		},
+		ArtifactChanges: []sarifArtifactChange{
+			{
+				ArtifactLocation: sarifArtifactLocation{
+					URI: location,
+				},
+				Replacements: replacements,
+			},
+		},
+	}
+}
+
+func newSARIFResult(diagnostic Diagnostic) sarifResult {
+	message := diagnostic.Message
+	if diagnostic.SecondaryMessage != "" {
+		message += ": " + diagnostic.SecondaryMessage
+	}
+
+	result := sarifResult{
+		RuleID: diagnostic.Code,
+		Level:  diagnostic.Severity,
+		Message: sarifMessage{
+			Text: message,
+		},
+		HelpURI: diagnostic.URL,
+	}
+
+	if diagnostic.Location != "" {
+		result.Locations = []sarifLocation{
+			newSARIFLocation(diagnostic.Location, diagnostic.Range),
+		}
+
+		for i, note := range diagnostic.Notes {
+			id := i + 1
+			relatedLocation := newSARIFLocation(diagnostic.Location, note.Range)
+			relatedLocation.ID = &id
+			relatedLocation.Message = &sarifMessage{
+				Text: note.Message,
+			}
+			result.RelatedLocations = append(result.RelatedLocations, relatedLocation)
+		}
+
+		for _, fix := range diagnostic.SuggestedFixes {
+			result.Fixes = append(result.Fixes, newSARIFFix(diagnostic.Location, fix))
+		}
+	}
+
+	return result
+}
+
+// WriteSARIF writes the given diagnostics as a SARIF log with a single run of the given tool
+func WriteSARIF(writer io.Writer, tool Tool, diagnostics []Diagnostic) error {
+	rules := make([]sarifRule, 0, len(tool.Rules))
+	for _, rule := range tool.Rules {
+		rules = append(rules, sarifRule{
+			ID: rule.ID,
+			ShortDescription: sarifMessage{
+				Text: rule.Description,
+			},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		results = append(results, newSARIFResult(diagnostic))
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           tool.Name,
+						Version:        tool.Version,
+						InformationURI: tool.InformationURI,
+						Rules:          rules,
+					},
+				},
+				// Columns are counted in runes
+				ColumnKind: "unicodeCodePoints",
+				Results:    results,
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -0,0 +1,89 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// AccountReferenceParametersAnalyzer reports functions which have parameters
+// with authorized account reference types, e.g. `auth(Storage) &Account`.
+//
+// Callers have to pass a reference to their account to such functions,
+// which grants the function access to the account beyond the intended operation
+var AccountReferenceParametersAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.FunctionDeclaration)(nil),
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects function parameters with authorized account reference types",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			location := pass.Program.Location
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					declaration, ok := element.(*ast.FunctionDeclaration)
+					if !ok || declaration.ParameterList == nil {
+						return
+					}
+
+					for _, parameter := range declaration.ParameterList.Parameters {
+						referenceType, ok := parameter.TypeAnnotation.Type.(*ast.ReferenceType)
+						if !ok ||
+							referenceType.Authorization == nil ||
+							!isNominalType(referenceType.Type, sema.AccountType.Identifier) {
+
+							continue
+						}
+
+						report(
+							analysis.Diagnostic{
+								Location: location,
+								Range:    ast.NewUnmeteredRangeFromPositioned(parameter),
+								Category: SecurityCategory,
+								Code:     "account-reference-parameters",
+								Message: fmt.Sprintf(
+									"parameter `%s` of function `%s` is an authorized account reference",
+									parameter.Identifier.Identifier,
+									declaration.Identifier.Identifier,
+								),
+								SecondaryMessage: "callers grant the function access to their account; " +
+									"consider passing only the required values or capabilities instead",
+							},
+						)
+					}
+				},
+			)
+
+			return nil
+		},
+	}
+})()
@@ -0,0 +1,113 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// CapabilityEntitlementsAnalyzer reports capabilities with authorized borrow types
+// which are published, e.g. `account.capabilities.publish(cap, at: /public/x)`,
+// where `cap` has the type `Capability<auth(Withdraw) &Vault>`.
+//
+// Published capabilities can be borrowed by anyone,
+// so the entitlements of the borrow type are granted to anyone
+var CapabilityEntitlementsAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.InvocationExpression)(nil),
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects published capabilities with authorized borrow types",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			program := pass.Program
+			location := program.Location
+			elaboration := program.Checker.Elaboration
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					invocationExpression, ok := element.(*ast.InvocationExpression)
+					if !ok || len(invocationExpression.Arguments) == 0 {
+						return
+					}
+
+					memberExpression, ok := invocationExpression.InvokedExpression.(*ast.MemberExpression)
+					if !ok ||
+						memberExpression.Identifier.Identifier != sema.Account_CapabilitiesTypePublishFunctionName {
+
+						return
+					}
+
+					memberInfo, ok := elaboration.MemberExpressionMemberAccessInfo(memberExpression)
+					if !ok ||
+						memberInfo.Member == nil ||
+						memberInfo.Member.ContainerType != sema.Account_CapabilitiesType {
+
+						return
+					}
+
+					argumentTypes := elaboration.InvocationExpressionTypes(invocationExpression).ArgumentTypes
+					if len(argumentTypes) == 0 {
+						return
+					}
+
+					capabilityType, ok := argumentTypes[0].(*sema.CapabilityType)
+					if !ok {
+						return
+					}
+
+					referenceType, ok := capabilityType.BorrowType.(*sema.ReferenceType)
+					if !ok || referenceType.Authorization == sema.UnauthorizedAccess {
+						return
+					}
+
+					report(
+						analysis.Diagnostic{
+							Location: location,
+							Range: ast.NewUnmeteredRangeFromPositioned(
+								invocationExpression.Arguments[0].Expression,
+							),
+							Category: SecurityCategory,
+							Code:     "capability-entitlements",
+							Message: fmt.Sprintf(
+								"published capability has authorized borrow type `%s`",
+								referenceType.QualifiedString(),
+							),
+							SecondaryMessage: "anyone can borrow a published capability and use its entitlements; " +
+								"consider publishing a capability with an unauthorized borrow type",
+						},
+					)
+				},
+			)
+
+			return nil
+		},
+	}
+})()
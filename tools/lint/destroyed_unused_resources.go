@@ -0,0 +1,127 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// DestroyedUnusedResourcesAnalyzer reports resource parameters
+// which are destroyed without being used otherwise, e.g.
+//
+//	fun deposit(from: @Vault) {
+//	    destroy from
+//	}
+//
+// Destroying a resource loses it, including any nested resources,
+// so destroying a resource which was passed in without inspecting it is likely a mistake
+var DestroyedUnusedResourcesAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.FunctionDeclaration)(nil),
+	}
+
+	// destroyOnlyUse returns the destroy expression of the variable with the given name,
+	// if it is the only use of the variable in the given function block
+	destroyOnlyUse := func(functionBlock *ast.FunctionBlock, name string) *ast.DestroyExpression {
+		uses := 0
+		var destroyExpression *ast.DestroyExpression
+
+		ast.Inspect(functionBlock, func(element ast.Element) bool {
+			switch element := element.(type) {
+			case *ast.IdentifierExpression:
+				if element.Identifier.Identifier == name {
+					uses++
+				}
+
+			case *ast.DestroyExpression:
+				identifierExpression, ok := element.Expression.(*ast.IdentifierExpression)
+				if ok && identifierExpression.Identifier.Identifier == name {
+					destroyExpression = element
+				}
+			}
+
+			return true
+		})
+
+		if uses != 1 {
+			return nil
+		}
+
+		return destroyExpression
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects resource parameters which are destroyed without being used",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			location := pass.Program.Location
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					declaration, ok := element.(*ast.FunctionDeclaration)
+					if !ok ||
+						declaration.ParameterList == nil ||
+						declaration.FunctionBlock == nil {
+
+						return
+					}
+
+					for _, parameter := range declaration.ParameterList.Parameters {
+						if !parameter.TypeAnnotation.IsResource {
+							continue
+						}
+
+						name := parameter.Identifier.Identifier
+
+						destroyExpression := destroyOnlyUse(declaration.FunctionBlock, name)
+						if destroyExpression == nil {
+							continue
+						}
+
+						report(
+							analysis.Diagnostic{
+								Location: location,
+								Range:    ast.NewUnmeteredRangeFromPositioned(destroyExpression),
+								Category: SecurityCategory,
+								Code:     "destroyed-unused-resources",
+								Message: fmt.Sprintf(
+									"resource parameter `%s` is destroyed without being used",
+									name,
+								),
+								SecondaryMessage: "destroying the resource loses it, including any nested resources; " +
+									"consider depositing or returning it instead",
+							},
+						)
+					}
+				},
+			)
+
+			return nil
+		},
+	}
+})()
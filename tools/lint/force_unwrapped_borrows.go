@@ -0,0 +1,110 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/sema"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// ForceUnwrappedBorrowsAnalyzer reports borrows which are force-unwrapped,
+// e.g. `account.storage.borrow<&Vault>(from: /storage/vault)!`.
+//
+// If the borrow fails, the program aborts with an error
+// which does not explain what was expected
+var ForceUnwrappedBorrowsAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.ForceExpression)(nil),
+	}
+
+	isBorrowFunction := func(member *sema.Member) bool {
+		if member == nil {
+			return false
+		}
+
+		switch member.ContainerType.(type) {
+		case *sema.CapabilityType:
+			return member.Identifier.Identifier == sema.CapabilityTypeBorrowFunctionName
+		}
+
+		switch member.ContainerType {
+		case sema.Account_StorageType:
+			return member.Identifier.Identifier == sema.Account_StorageTypeBorrowFunctionName
+		case sema.Account_CapabilitiesType:
+			return member.Identifier.Identifier == sema.Account_CapabilitiesTypeBorrowFunctionName
+		}
+
+		return false
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects force-unwrapped borrows",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			program := pass.Program
+			location := program.Location
+			elaboration := program.Checker.Elaboration
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					forceExpression, ok := element.(*ast.ForceExpression)
+					if !ok {
+						return
+					}
+
+					invocationExpression, ok := forceExpression.Expression.(*ast.InvocationExpression)
+					if !ok {
+						return
+					}
+
+					memberExpression, ok := invocationExpression.InvokedExpression.(*ast.MemberExpression)
+					if !ok {
+						return
+					}
+
+					memberInfo, ok := elaboration.MemberExpressionMemberAccessInfo(memberExpression)
+					if !ok || !isBorrowFunction(memberInfo.Member) {
+						return
+					}
+
+					report(
+						analysis.Diagnostic{
+							Location: location,
+							Range:    ast.NewUnmeteredRangeFromPositioned(forceExpression),
+							Category: BestPracticeCategory,
+							Code:     "force-unwrapped-borrows",
+							Message:  "result of borrow is force-unwrapped",
+							SecondaryMessage: "the program aborts with an unhelpful error if the borrow fails; " +
+								"consider `?? panic(\"...\")` with a descriptive message",
+						},
+					)
+				},
+			)
+
+			return nil
+		},
+	}
+})()
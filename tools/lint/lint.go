@@ -0,0 +1,68 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package lint provides analyzers which detect security anti-patterns in Cadence programs.
+//
+// The analyzers require the programs to be checked, i.e. loaded with analysis.NeedTypes.
+package lint
+
+import (
+	"sort"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+const (
+	SecurityCategory     = "security"
+	BestPracticeCategory = "best-practice"
+)
+
+// Analyzers are all lint analyzers, by name
+var Analyzers = map[string]*analysis.Analyzer{
+	"account-reference-parameters": AccountReferenceParametersAnalyzer,
+	"capability-entitlements":      CapabilityEntitlementsAnalyzer,
+	"destroyed-unused-resources":   DestroyedUnusedResourcesAnalyzer,
+	"force-unwrapped-borrows":      ForceUnwrappedBorrowsAnalyzer,
+	"public-auth-references":       PublicAuthReferencesAnalyzer,
+	"public-mutable-fields":        PublicMutableFieldsAnalyzer,
+}
+
+// AnalyzerNames returns the names of all lint analyzers, sorted
+func AnalyzerNames() []string {
+	names := make([]string, 0, len(Analyzers))
+	for name := range Analyzers { //nolint:maprange
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isAuthReferenceType returns true if the given type is an authorized reference type,
+// e.g. `auth(E) &T`
+func isAuthReferenceType(ty ast.Type) bool {
+	referenceType, ok := ty.(*ast.ReferenceType)
+	return ok && referenceType.Authorization != nil
+}
+
+// isNominalType returns true if the given type is the nominal type with the given identifier
+func isNominalType(ty ast.Type, identifier string) bool {
+	nominalType, ok := ty.(*ast.NominalType)
+	return ok &&
+		len(nominalType.NestedIdentifiers) == 0 &&
+		nominalType.Identifier.Identifier == identifier
+}
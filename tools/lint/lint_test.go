@@ -0,0 +1,300 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/tools/analysis"
+	"github.com/onflow/cadence/tools/lint"
+)
+
+type testDiagnostic struct {
+	Code    string
+	Message string
+	ast.Range
+}
+
+func testLint(t *testing.T, code string, analyzer *analysis.Analyzer) []testDiagnostic {
+	location := common.StringLocation("test")
+
+	config := analysis.NewSimpleConfig(
+		analysis.NeedTypes,
+		map[common.Location][]byte{
+			location: []byte(code),
+		},
+		nil,
+		nil,
+	)
+
+	programs, err := analysis.Load(config, location)
+	require.NoError(t, err)
+
+	var diagnostics []testDiagnostic
+	var mutex sync.Mutex
+
+	programs.Get(location).Run(
+		[]*analysis.Analyzer{analyzer},
+		func(diagnostic analysis.Diagnostic) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			require.Equal(t, location, diagnostic.Location)
+			require.NotEmpty(t, diagnostic.Category)
+			require.NotEmpty(t, diagnostic.SecondaryMessage)
+
+			diagnostics = append(diagnostics, testDiagnostic{
+				Code:    diagnostic.Code,
+				Message: diagnostic.Message,
+				Range:   diagnostic.Range,
+			})
+		},
+	)
+
+	return diagnostics
+}
+
+func TestAnalyzerNames(t *testing.T) {
+
+	t.Parallel()
+
+	names := lint.AnalyzerNames()
+	require.Len(t, names, len(lint.Analyzers))
+	require.IsIncreasing(t, names)
+}
+
+func TestPublicMutableFieldsAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) struct S {
+              access(all) var a: Int
+              access(all) let b: Int
+              access(self) var c: Int
+
+              init() {
+                  self.a = 1
+                  self.b = 2
+                  self.c = 3
+              }
+          }
+        `,
+		lint.PublicMutableFieldsAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "public-mutable-fields",
+				Message: "field `a` is publicly declared as variable",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 64, Line: 3, Column: 30},
+					EndPos:   ast.Position{Offset: 64, Line: 3, Column: 30},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestPublicAuthReferencesAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) entitlement E
+
+          access(all) struct S {
+              access(all) fun a(): auth(E) &S {
+                  return &self
+              }
+
+              access(all) fun b(): &S {
+                  return &self
+              }
+
+              access(E) fun c(): auth(E) &S {
+                  return &self
+              }
+          }
+        `,
+		lint.PublicAuthReferencesAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "public-auth-references",
+				Message: "public function `a` returns an authorized reference",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 106, Line: 5, Column: 35},
+					EndPos:   ast.Position{Offset: 115, Line: 5, Column: 44},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestAccountReferenceParametersAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) fun a(account: auth(Storage) &Account) {}
+
+          access(all) fun b(account: &Account) {}
+        `,
+		lint.AccountReferenceParametersAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "account-reference-parameters",
+				Message: "parameter `account` of function `a` is an authorized account reference",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 29, Line: 2, Column: 28},
+					EndPos:   ast.Position{Offset: 59, Line: 2, Column: 58},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestCapabilityEntitlementsAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) entitlement E
+
+          access(all) resource R {}
+
+          access(all) fun test(account: auth(Capabilities) &Account) {
+              let a = account.capabilities.storage.issue<auth(E) &R>(/storage/r)
+              account.capabilities.publish(a, at: /public/a)
+
+              let b = account.capabilities.storage.issue<&R>(/storage/r)
+              account.capabilities.publish(b, at: /public/b)
+          }
+        `,
+		lint.CapabilityEntitlementsAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "capability-entitlements",
+				Message: "published capability has authorized borrow type `auth(E) &R`",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 270, Line: 8, Column: 43},
+					EndPos:   ast.Position{Offset: 270, Line: 8, Column: 43},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestForceUnwrappedBorrowsAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) resource R {}
+
+          access(all) fun test(account: auth(Storage) &Account) {
+              let a = account.storage.borrow<&R>(from: /storage/r)!
+              let b = account.storage.borrow<&R>(from: /storage/r) ?? panic("no R")
+              let c: Int? = 1
+              let d = c!
+          }
+        `,
+		lint.ForceUnwrappedBorrowsAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "force-unwrapped-borrows",
+				Message: "result of borrow is force-unwrapped",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 126, Line: 5, Column: 22},
+					EndPos:   ast.Position{Offset: 170, Line: 5, Column: 66},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
+
+func TestDestroyedUnusedResourcesAnalyzer(t *testing.T) {
+
+	t.Parallel()
+
+	diagnostics := testLint(t,
+		`
+          access(all) resource R {
+              access(all) let balance: Int
+
+              init() {
+                  self.balance = 0
+              }
+          }
+
+          access(all) fun a(r: @R) {
+              destroy r
+          }
+
+          access(all) fun b(r: @R) {
+              if r.balance > 0 {
+                  panic("not empty")
+              }
+              destroy r
+          }
+        `,
+		lint.DestroyedUnusedResourcesAnalyzer,
+	)
+
+	require.Equal(t,
+		[]testDiagnostic{
+			{
+				Code:    "destroyed-unused-resources",
+				Message: "resource parameter `r` is destroyed without being used",
+				Range: ast.Range{
+					StartPos: ast.Position{Offset: 218, Line: 11, Column: 14},
+					EndPos:   ast.Position{Offset: 226, Line: 11, Column: 22},
+				},
+			},
+		},
+		diagnostics,
+	)
+}
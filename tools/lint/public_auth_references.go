@@ -0,0 +1,87 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// PublicAuthReferencesAnalyzer reports public functions which return authorized references.
+//
+// Anyone can call a public function, so returning an authorized reference
+// grants the entitlements of the reference to anyone
+var PublicAuthReferencesAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.FunctionDeclaration)(nil),
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects public functions which return authorized references",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			location := pass.Program.Location
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					declaration, ok := element.(*ast.FunctionDeclaration)
+					if !ok {
+						return
+					}
+
+					if declaration.Access != ast.AccessAll {
+						return
+					}
+
+					returnTypeAnnotation := declaration.ReturnTypeAnnotation
+					if returnTypeAnnotation == nil ||
+						!isAuthReferenceType(returnTypeAnnotation.Type) {
+
+						return
+					}
+
+					report(
+						analysis.Diagnostic{
+							Location: location,
+							Range:    ast.NewUnmeteredRangeFromPositioned(returnTypeAnnotation),
+							Category: SecurityCategory,
+							Code:     "public-auth-references",
+							Message: fmt.Sprintf(
+								"public function `%s` returns an authorized reference",
+								declaration.Identifier.Identifier,
+							),
+							SecondaryMessage: "anyone can call the function and use the entitlements of the reference; " +
+								"consider returning an unauthorized reference, or restricting access to the function",
+						},
+					)
+				},
+			)
+
+			return nil
+		},
+	}
+})()
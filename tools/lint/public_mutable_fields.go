@@ -0,0 +1,82 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/tools/analysis"
+)
+
+// PublicMutableFieldsAnalyzer reports fields which are declared with `access(all) var`.
+//
+// Public variable fields are an anti-pattern:
+// Their state may be changed in ways the declaring type does not anticipate,
+// and they make it harder to restrict access later
+var PublicMutableFieldsAnalyzer = (func() *analysis.Analyzer {
+
+	elementFilter := []ast.Element{
+		(*ast.FieldDeclaration)(nil),
+	}
+
+	return &analysis.Analyzer{
+		Description: "Detects fields which are declared with `access(all) var`",
+		Requires: []*analysis.Analyzer{
+			analysis.InspectorAnalyzer,
+		},
+		Run: func(pass *analysis.Pass) interface{} {
+			inspector := pass.ResultOf[analysis.InspectorAnalyzer].(*ast.Inspector)
+
+			location := pass.Program.Location
+			report := pass.Report
+
+			inspector.Preorder(
+				elementFilter,
+				func(element ast.Element) {
+					field, ok := element.(*ast.FieldDeclaration)
+					if !ok {
+						return
+					}
+
+					if field.Access != ast.AccessAll ||
+						field.VariableKind != ast.VariableKindVariable {
+
+						return
+					}
+
+					report(
+						analysis.Diagnostic{
+							Location: location,
+							Range:    ast.NewUnmeteredRangeFromPositioned(field.Identifier),
+							Category: BestPracticeCategory,
+							Code:     "public-mutable-fields",
+							Message: fmt.Sprintf(
+								"field `%s` is publicly declared as variable",
+								field.Identifier.Identifier,
+							),
+							SecondaryMessage: "consider declaring the field with `let`, or restricting its access",
+						},
+					)
+				},
+			)
+
+			return nil
+		},
+	}
+})()
@@ -0,0 +1,341 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+)
+
+// Explorer provides read access to the accounts, storage maps, and values
+// of the registers of a payloads file.
+type Explorer struct {
+	// mutex serializes access to the storage and interpreter, which are not thread-safe
+	mutex   sync.Mutex
+	ledger  *payloads.Ledger
+	storage *runtime.Storage
+	inter   *interpreter.Interpreter
+}
+
+func NewExplorer(ledger *payloads.Ledger) (*Explorer, error) {
+	storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		nil,
+		&interpreter.Config{
+			Storage: storage,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Explorer{
+		ledger:  ledger,
+		storage: storage,
+		inter:   inter,
+	}, nil
+}
+
+// Addresses returns the hex-encoded addresses of all accounts, sorted.
+func (e *Explorer) Addresses() []string {
+	addresses := e.ledger.Addresses()
+
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, address.Hex())
+	}
+	return result
+}
+
+// Domains returns the identifiers of the storage maps which exist in the given account.
+func (e *Explorer) Domains(address common.Address) (domains []string, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	defer recoverError(&err)
+
+	domains = make([]string, 0)
+	for _, domain := range common.AllStorageDomains {
+		storageMap := e.storage.GetDomainStorageMap(e.inter, address, domain, false)
+		if storageMap == nil {
+			continue
+		}
+		domains = append(domains, domain.Identifier())
+	}
+	return domains, nil
+}
+
+// Keys returns the keys of the storage map for the given account and domain, sorted.
+func (e *Explorer) Keys(address common.Address, domain string) (keys []string, err error) {
+	knownStorageMap, err := lookupKnownStorageMap(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	defer recoverError(&err)
+
+	storageMap := e.storage.GetDomainStorageMap(e.inter, address, knownStorageMap.Domain, false)
+	if storageMap == nil {
+		return make([]string, 0), nil
+	}
+
+	return storageMapKeys(storageMap, knownStorageMap), nil
+}
+
+// Value returns the prepared value stored in the given account, domain, and key.
+// The optional nested path selects a nested value:
+// strings select composite fields, and JSON-CDC values select dictionary or array elements.
+func (e *Explorer) Value(
+	address common.Address,
+	domain string,
+	identifier string,
+	nested []any,
+) (
+	result Value,
+	err error,
+) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	defer recoverError(&err)
+
+	value, err := e.readValue(address, domain, identifier, nested)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return prepareValue(value, e.inter)
+}
+
+// CadenceValue returns the JSON-CDC encoding of the value stored in the given account, domain, and key.
+// See Value for the nested path.
+func (e *Explorer) CadenceValue(
+	address common.Address,
+	domain string,
+	identifier string,
+	nested []any,
+) (
+	result json.RawMessage,
+	err error,
+) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	defer recoverError(&err)
+
+	value, err := e.readValue(address, domain, identifier, nested)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	exported, err := runtime.ExportValue(value, e.inter, interpreter.EmptyLocationRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsoncdc.Encode(exported)
+}
+
+// StorageUsage is the storage usage of an account, attributed to storage paths and composite types.
+type StorageUsage struct {
+	Size  uint64             `json:"size"`
+	Paths []PathStorageUsage `json:"paths"`
+	Types []TypeStorageUsage `json:"types"`
+}
+
+type PathStorageUsage struct {
+	Domain    string `json:"domain"`
+	Key       string `json:"key"`
+	Size      uint64 `json:"size"`
+	SlabCount int    `json:"slabCount"`
+}
+
+type TypeStorageUsage struct {
+	TypeID string `json:"typeID"`
+	Size   uint64 `json:"size"`
+	Count  int    `json:"count"`
+}
+
+// Usage returns the encoded byte size of the storage of the given account,
+// attributed to storage paths and composite types, sorted by descending size.
+func (e *Explorer) Usage(address common.Address) (usage *StorageUsage, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	defer recoverError(&err)
+
+	accountUsage, err := e.storage.AccountStorageUsage(address)
+	if err != nil {
+		return nil, err
+	}
+
+	usage = &StorageUsage{
+		Paths: make([]PathStorageUsage, 0),
+		Types: make([]TypeStorageUsage, 0),
+	}
+
+	if accountUsage == nil {
+		return usage, nil
+	}
+
+	usage.Size = accountUsage.Size
+
+	for _, pathUsage := range accountUsage.Paths {
+		usage.Paths = append(usage.Paths, PathStorageUsage{
+			Domain:    pathUsage.Domain.Identifier(),
+			Key:       pathUsage.Key,
+			Size:      pathUsage.Size,
+			SlabCount: pathUsage.SlabCount,
+		})
+	}
+
+	for _, typeUsage := range accountUsage.Types {
+		usage.Types = append(usage.Types, TypeStorageUsage{
+			TypeID: string(typeUsage.TypeID),
+			Size:   typeUsage.Size,
+			Count:  typeUsage.Count,
+		})
+	}
+
+	return usage, nil
+}
+
+func (e *Explorer) readValue(
+	address common.Address,
+	domain string,
+	identifier string,
+	nested []any,
+) (
+	interpreter.Value,
+	error,
+) {
+	knownStorageMap, err := lookupKnownStorageMap(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := knownStorageMap.StringAsKey(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	storageMap := e.storage.GetDomainStorageMap(e.inter, address, knownStorageMap.Domain, false)
+	if storageMap == nil {
+		return nil, nil
+	}
+
+	value := storageMap.ReadValue(nil, key)
+	if value == nil {
+		return nil, nil
+	}
+
+	return getNested(e.inter, value, nested)
+}
+
+func lookupKnownStorageMap(domain string) (KnownStorageMap, error) {
+	knownStorageMap, ok := knownStorageMaps[domain]
+	if !ok {
+		return KnownStorageMap{}, fmt.Errorf("unknown storage map domain: %s", domain)
+	}
+	return knownStorageMap, nil
+}
+
+// recoverError recovers from a panic, e.g. caused by a malformed value,
+// and reports it as an error
+func recoverError(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	switch r := r.(type) {
+	case error:
+		*err = r
+	default:
+		*err = fmt.Errorf("%v", r)
+	}
+}
+
+func getNested(inter *interpreter.Interpreter, value interpreter.Value, nested []any) (interpreter.Value, error) {
+	decoder := &jsoncdc.Decoder{}
+
+	for index, n := range nested {
+		switch n := n.(type) {
+		case string:
+			switch v := value.(type) {
+			case *interpreter.CompositeValue:
+				// Only get the stored field, dynamically linking functions
+				// and computed fields requires the contract's program
+				value = v.GetField(inter, n)
+
+			case interpreter.MemberAccessibleValue:
+				value = v.GetMember(inter, interpreter.EmptyLocationRange, n)
+
+			default:
+				return nil, fmt.Errorf("value for index %d is not member accessible", index)
+			}
+
+		case map[string]any:
+			valueIndexableValue, ok := value.(interpreter.ValueIndexableValue)
+			if !ok {
+				return nil, fmt.Errorf("value for index %d is not value indexable", index)
+			}
+
+			decoded := decoder.DecodeJSON(n)
+			imported, err := runtime.ImportValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				nil,
+				nil,
+				decoded,
+				nil,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("value for index %d is not importable: %w", index, err)
+			}
+
+			value = valueIndexableValue.GetKey(inter, interpreter.EmptyLocationRange, imported)
+			if _, ok := valueIndexableValue.(*interpreter.DictionaryValue); ok {
+				if someValue, ok := value.(*interpreter.SomeValue); ok {
+					value = someValue.InnerValue()
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid index %d: %v", index, n)
+		}
+
+		if value == nil {
+			return nil, fmt.Errorf("value for index %d does not exist", index)
+		}
+	}
+	return value, nil
+}
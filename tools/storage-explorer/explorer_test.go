@@ -0,0 +1,183 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+)
+
+func newTestExplorer(t *testing.T, address common.Address) *Explorer {
+	ledger := payloads.NewLedger()
+
+	storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+	// Storage validation is disabled, as the dictionary is created in account storage
+	// before it is referenced by the storage map
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, true, false)
+
+	storageMap := storage.GetDomainStorageMap(
+		inter,
+		address,
+		common.PathDomainStorage.StorageDomain(),
+		true,
+	)
+
+	storageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("string"),
+		interpreter.NewUnmeteredStringValue("hello"),
+	)
+
+	dictionary := interpreter.NewDictionaryValueWithAddress(
+		inter,
+		interpreter.EmptyLocationRange,
+		interpreter.NewDictionaryStaticType(
+			nil,
+			interpreter.PrimitiveStaticTypeString,
+			interpreter.PrimitiveStaticTypeInt,
+		),
+		address,
+		interpreter.NewUnmeteredStringValue("a"),
+		interpreter.NewUnmeteredIntValueFromInt64(1),
+		interpreter.NewUnmeteredStringValue("b"),
+		interpreter.NewUnmeteredIntValueFromInt64(2),
+	)
+
+	storageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("dictionary"),
+		dictionary,
+	)
+
+	const commitContractUpdates = false
+	err := storage.Commit(inter, commitContractUpdates)
+	require.NoError(t, err)
+
+	explorer, err := NewExplorer(ledger)
+	require.NoError(t, err)
+
+	return explorer
+}
+
+func TestExplorer(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	explorer := newTestExplorer(t, address)
+
+	t.Run("addresses", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{address.Hex()},
+			explorer.Addresses(),
+		)
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		domains, err := explorer.Domains(address)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"storage"}, domains)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		keys, err := explorer.Keys(address, "storage")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dictionary", "string"}, keys)
+
+		keys, err = explorer.Keys(address, "public")
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+
+		_, err = explorer.Keys(address, "unknown")
+		require.ErrorContains(t, err, "unknown storage map domain: unknown")
+	})
+
+	t.Run("value", func(t *testing.T) {
+		value, err := explorer.Value(address, "storage", "dictionary", nil)
+		require.NoError(t, err)
+		require.IsType(t, DictionaryValue{}, value)
+		assert.Len(t, value.(DictionaryValue).Keys, 2)
+
+		value, err = explorer.Value(address, "storage", "missing", nil)
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("nested value", func(t *testing.T) {
+		var nested []any
+		err := json.Unmarshal([]byte(`[{"type":"String","value":"b"}]`), &nested)
+		require.NoError(t, err)
+
+		value, err := explorer.CadenceValue(address, "storage", "dictionary", nested)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"Int","value":"2"}`, string(value))
+	})
+
+	t.Run("cadence value", func(t *testing.T) {
+		value, err := explorer.CadenceValue(address, "storage", "string", nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"String","value":"hello"}`, string(value))
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		usage, err := explorer.Usage(address)
+		require.NoError(t, err)
+
+		require.Len(t, usage.Paths, 2)
+		assert.Equal(t, "storage", usage.Paths[0].Domain)
+		assert.Equal(t, "dictionary", usage.Paths[0].Key)
+		assert.Equal(t, "string", usage.Paths[1].Key)
+		assert.Greater(t, usage.Size, usage.Paths[0].Size+usage.Paths[1].Size)
+		assert.Empty(t, usage.Types)
+
+		usage, err = explorer.Usage(common.MustBytesToAddress([]byte{0x2}))
+		require.NoError(t, err)
+		assert.Empty(t, usage.Paths)
+	})
+
+	t.Run("command", func(t *testing.T) {
+		result, err := runCommand(explorer, "keys", []string{address.Hex(), "storage"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dictionary", "string"}, result)
+
+		result, err = runCommand(
+			explorer,
+			"cadence",
+			[]string{address.Hex(), "storage", "dictionary", `[{"type":"String","value":"a"}]`},
+		)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"Int","value":"1"}`, string(result.(json.RawMessage)))
+
+		_, err = runCommand(explorer, "keys", []string{address.Hex()})
+		require.ErrorContains(t, err, "invalid number of arguments for command keys: 1")
+
+		_, err = runCommand(explorer, "unknown", nil)
+		require.ErrorContains(t, err, "unknown command: unknown")
+	})
+}
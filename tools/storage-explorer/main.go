@@ -22,314 +22,333 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"net"
 	"net/http"
 	"os"
-	"sort"
-	"time"
+	"strings"
 
-	"github.com/gorilla/mux"
-	"github.com/onflow/flow-go/cmd/util/ledger/migrations"
-	"github.com/onflow/flow-go/cmd/util/ledger/util"
-	"github.com/onflow/flow-go/cmd/util/ledger/util/registers"
-	"github.com/onflow/flow-go/model/flow"
-	"github.com/rs/zerolog"
+	"github.com/onflow/cadence/cmd/payloads"
+	"github.com/onflow/cadence/common"
+)
 
-	"github.com/onflow/cadence/runtime/interpreter"
+const usage = `usage: storage-explorer -payloads <file> [flags] [command]
 
-	jsoncdc "github.com/onflow/cadence/encoding/json"
-	"github.com/onflow/cadence/runtime"
-	"github.com/onflow/cadence/runtime/common"
-)
+Commands:
+  serve                                     serve the explorer over HTTP (default)
+  accounts                                  list all accounts
+  domains <address>                         list the storage maps of an account
+  keys <address> <domain>                   list the keys of a storage map
+  usage <address>                           print the storage usage of an account, per path and per type
+  value <address> <domain> <key> [nested]   print a stored value
+  cadence <address> <domain> <key> [nested] print a stored value, encoded as JSON-CDC
+
+The optional nested argument is a JSON array which selects a nested value:
+strings select composite fields, and JSON-CDC values select dictionary keys or array indices.
+
+Flags:
+`
 
 func main() {
 
 	portFlag := flag.Int("port", 3000, "port")
-	payloadsFlag := flag.String("payloads", "", "payloads file")
-	chainIDFlag := flag.String("chain-id", "", "chain ID")
+	payloadsFlag := flag.String("payloads", "", "payloads file (JSON Lines, optionally gzipped)")
+	addressesFlag := flag.String("addresses", "", "only load registers of the given comma-separated addresses")
+
+	flag.Usage = func() {
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 
 	flag.Parse()
 
-	consoleWriter := zerolog.ConsoleWriter{
-		Out:        os.Stderr,
-		TimeFormat: time.DateTime,
+	payloadsPath := *payloadsFlag
+	if payloadsPath == "" {
+		flag.Usage()
+		os.Exit(1)
 	}
-	log := zerolog.New(consoleWriter).With().Timestamp().Logger()
 
-	if *chainIDFlag == "" {
-		log.Fatal().Msg("missing chain ID")
+	var addresses []common.Address
+	if *addressesFlag != "" {
+		for _, hexAddress := range strings.Split(*addressesFlag, ",") {
+			address, err := common.HexToAddress(strings.TrimSpace(hexAddress))
+			if err != nil {
+				log.Fatalf("invalid address %s: %s", hexAddress, err)
+			}
+			addresses = append(addresses, address)
+		}
 	}
-	chainID := flow.ChainID(*chainIDFlag)
 
-	payloadsPath := *payloadsFlag
-	if payloadsPath == "" {
-		log.Fatal().Msg("missing payloads")
+	log.Printf("reading payloads from %s ...", payloadsPath)
+
+	ledger, err := payloads.ReadFile(payloadsPath, addresses)
+	if err != nil {
+		log.Fatalf("failed to read payloads: %s", err)
 	}
 
-	_, payloads, err := util.ReadPayloadFile(log, payloadsPath)
+	log.Printf("read %d payloads", ledger.Count())
+
+	explorer, err := NewExplorer(ledger)
 	if err != nil {
-		log.Fatal().Err(err)
+		log.Fatalf("failed to create explorer: %s", err)
+	}
+
+	args := flag.Args()
+
+	command := "serve"
+	if len(args) > 0 {
+		command = args[0]
+		args = args[1:]
 	}
 
-	log.Info().Msgf("read %d payloads", len(payloads))
+	if command == "serve" {
+		serve(explorer, *portFlag)
+		return
+	}
 
-	log.Info().Msg("creating registers from payloads ...")
+	result, err := runCommand(explorer, command, args)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	registersByAccount, err := registers.NewByAccountFromPayloads(payloads)
+	err = writeJSON(os.Stdout, result)
 	if err != nil {
-		log.Fatal().Err(err)
+		log.Fatal(err)
 	}
+}
 
-	log.Info().Msgf("created registers (%d accounts)", registersByAccount.AccountCount())
+func runCommand(explorer *Explorer, command string, args []string) (any, error) {
 
-	mr, err := migrations.NewInterpreterMigrationRuntime(
-		registersByAccount,
-		chainID,
-		migrations.InterpreterMigrationRuntimeConfig{},
-	)
+	argumentCount := map[string]int{
+		"accounts": 0,
+		"domains":  1,
+		"keys":     2,
+		"usage":    1,
+		"value":    3,
+		"cadence":  3,
+	}
+
+	count, ok := argumentCount[command]
+	if !ok {
+		return nil, fmt.Errorf("unknown command: %s", command)
+	}
+
+	// Commands which read a value accept an optional nested path
+	if len(args) < count || (len(args) > count && (count < 3 || len(args) > count+1)) {
+		return nil, fmt.Errorf("invalid number of arguments for command %s: %d", command, len(args))
+	}
+
+	if command == "accounts" {
+		return explorer.Addresses(), nil
+	}
 
+	address, err := common.HexToAddress(args[0])
 	if err != nil {
-		log.Fatal().Err(err)
+		return nil, err
 	}
 
-	r := mux.NewRouter()
+	switch command {
+	case "domains":
+		return explorer.Domains(address)
 
-	r.HandleFunc(
-		"/accounts",
-		NewAccountsHandler(registersByAccount, log),
-	)
+	case "keys":
+		return explorer.Keys(address, args[1])
 
-	r.HandleFunc(
-		"/known_storage_maps",
-		NewKnownStorageMapsHandler(log),
-	)
+	case "usage":
+		return explorer.Usage(address)
+	}
+
+	var nested []any
+	if len(args) > count {
+		err := json.Unmarshal([]byte(args[count]), &nested)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nested path: %w", err)
+		}
+	}
+
+	if command == "cadence" {
+		return explorer.CadenceValue(address, args[1], args[2], nested)
+	}
 
-	const accountDomainPattern = "/accounts/{address:[0-9A-Fa-f]{16}}/{domain:.+}"
+	return explorer.Value(address, args[1], args[2], nested)
+}
 
-	r.PathPrefix(accountDomainPattern + "/{identifier:.+}").
-		HandlerFunc(NewAccountStorageMapValueHandler(mr.Storage, mr.Interpreter, log))
+func serve(explorer *Explorer, port int) {
+	mux := http.NewServeMux()
 
-	r.HandleFunc(
-		accountDomainPattern,
-		NewAccountStorageMapKeysHandler(mr.Storage, log),
-	)
+	mux.HandleFunc("/accounts", NewAccountsHandler(explorer))
 
-	r.PathPrefix("/").Handler(http.FileServer(http.Dir("./dist/")))
+	mux.HandleFunc("/known_storage_maps", NewKnownStorageMapsHandler())
 
-	http.Handle("/", r)
+	mux.HandleFunc("/accounts/{address}/domains", NewAccountDomainsHandler(explorer))
 
-	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", *portFlag))
+	mux.HandleFunc("/accounts/{address}/usage", NewAccountUsageHandler(explorer))
+
+	mux.HandleFunc("/accounts/{address}/{domain}", NewAccountStorageMapKeysHandler(explorer))
+
+	mux.HandleFunc("/accounts/{address}/{domain}/{identifier}", NewAccountStorageMapValueHandler(explorer))
+
+	mux.HandleFunc("/accounts/{address}/{domain}/{identifier}/cadence", NewAccountStorageMapCadenceValueHandler(explorer))
+
+	mux.Handle("/", http.FileServer(http.Dir("./dist/")))
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 	if err != nil {
-		log.Fatal().Err(err)
+		log.Fatal(err)
+	}
+	log.Printf("Listening on http://%s/", ln.Addr().String())
+	srv := http.Server{
+		Handler: mux,
 	}
-	log.Info().Msgf("Listening on http://%s/", ln.Addr().String())
-	var srv http.Server
 	_ = srv.Serve(ln)
 }
 
-func NewKnownStorageMapsHandler(log zerolog.Logger) func(w http.ResponseWriter, r *http.Request) {
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func writeJSONResponse(w http.ResponseWriter, value any) {
+	w.Header().Add("Content-Type", "application/json")
+	err := writeJSON(w, value)
+	if err != nil {
+		log.Printf("failed to write response: %s", err)
+	}
+}
+
+func NewKnownStorageMapsHandler() func(w http.ResponseWriter, r *http.Request) {
 	knownStorageMapsJSON := knownStorageMapsJSON()
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
 		_, err := w.Write(knownStorageMapsJSON)
 		if err != nil {
-			log.Fatal().Err(err)
+			log.Printf("failed to write response: %s", err)
 		}
 	}
 }
 
-func NewAccountsHandler(
-	registersByAccount *registers.ByAccount,
-	log zerolog.Logger,
-) func(w http.ResponseWriter, r *http.Request) {
-	log.Info().Msg("formatting addresses ...")
-
-	addressesJSON, err := addressesJSON(registersByAccount)
-	if err != nil {
-		log.Fatal().Err(err)
-	}
+func NewAccountsHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
+	addresses := explorer.Addresses()
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Add("Content-Type", "application/json")
-		_, err := w.Write(addressesJSON)
-		if err != nil {
-			log.Fatal().Err(err)
-		}
+		writeJSONResponse(w, addresses)
 	}
 }
 
-func NewAccountStorageMapKeysHandler(
-	storage *runtime.Storage,
-	log zerolog.Logger,
-) func(w http.ResponseWriter, r *http.Request) {
+func NewAccountDomainsHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		vars := mux.Vars(r)
-
-		address, err := common.HexToAddress(vars["address"])
+		address, err := common.HexToAddress(r.PathValue("address"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		storageMapDomain := vars["domain"]
-		knownStorageMap, ok := knownStorageMaps[storageMapDomain]
-		if !ok {
-			http.Error(
-				w,
-				fmt.Sprintf("unknown storage map domain: %s", storageMapDomain),
-				http.StatusBadRequest,
-			)
+		domains, err := explorer.Domains(address)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		var keys []string
-		storageMap := storage.GetDomainStorageMap(address, storageMapDomain, false)
-		if storageMap == nil {
-			keys = make([]string, 0)
-		} else {
-			keys = storageMapKeys(storageMap, knownStorageMap)
-		}
-
-		w.Header().Add("Content-Type", "application/json")
-
-		err = json.NewEncoder(w).Encode(keys)
-		if err != nil {
-			log.Fatal().Err(err)
-		}
+		writeJSONResponse(w, domains)
 	}
 }
 
-func NewAccountStorageMapValueHandler(
-	storage *runtime.Storage,
-	inter *interpreter.Interpreter,
-	log zerolog.Logger,
-) func(w http.ResponseWriter, r *http.Request) {
+func NewAccountUsageHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		vars := mux.Vars(r)
-
-		address, err := common.HexToAddress(vars["address"])
+		address, err := common.HexToAddress(r.PathValue("address"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		storageMapDomain := vars["domain"]
-		knownStorageMap, ok := knownStorageMaps[storageMapDomain]
-		if !ok {
-			http.Error(
-				w,
-				fmt.Sprintf("unknown storage map domain: %s", storageMapDomain),
-				http.StatusBadRequest,
-			)
-			return
-		}
-
-		storageMap := storage.GetDomainStorageMap(address, storageMapDomain, false)
-		if storageMap == nil {
-			http.Error(w, "storage map does not exist", http.StatusNotFound)
-			return
-		}
-
-		identifier := vars["identifier"]
-
-		key, err := knownStorageMap.StringAsKey(identifier)
+		usage, err := explorer.Usage(address)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		var preparedValue Value
-
-		value := storageMap.ReadValue(nil, key)
+		writeJSONResponse(w, usage)
+	}
+}
 
-		var nested []any
-		err = json.NewDecoder(r.Body).Decode(&nested)
+func NewAccountStorageMapKeysHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		address, err := common.HexToAddress(r.PathValue("address"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		value, err = getNested(inter, value, nested)
+		keys, err := explorer.Keys(address, r.PathValue("domain"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		if value != nil {
-			preparedValue, err = prepareValue(value, inter)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-		}
-
-		w.Header().Add("Content-Type", "application/json")
-
-		err = json.NewEncoder(w).Encode(preparedValue)
-		if err != nil {
-			log.Fatal().Err(err)
-		}
+		writeJSONResponse(w, keys)
 	}
 }
 
-func getNested(inter *interpreter.Interpreter, value interpreter.Value, nested []any) (interpreter.Value, error) {
-	decoder := &jsoncdc.Decoder{}
-
-	for index, n := range nested {
-		switch n := n.(type) {
-		case string:
-			memberAccessibleValue, ok := value.(interpreter.MemberAccessibleValue)
-			if !ok {
-				return nil, fmt.Errorf("value for index %d is not member accessible", index)
-			}
-			value = memberAccessibleValue.GetMember(inter, interpreter.EmptyLocationRange, n)
-
-		case map[string]any:
-			valueIndexableValue, ok := value.(interpreter.ValueIndexableValue)
-			if !ok {
-				return nil, fmt.Errorf("value for index %d is not value indexable", index)
-			}
+func NewAccountStorageMapValueHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
+	return newValueHandler(func(
+		address common.Address,
+		domain string,
+		identifier string,
+		nested []any,
+	) (
+		any,
+		error,
+	) {
+		return explorer.Value(address, domain, identifier, nested)
+	})
+}
 
-			decoded := decoder.DecodeJSON(n)
-			imported, err := runtime.ImportValue(
-				inter,
-				interpreter.EmptyLocationRange,
-				nil,
-				decoded,
-				nil,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("value for index %d is not importable: %w", index, err)
-			}
+func NewAccountStorageMapCadenceValueHandler(explorer *Explorer) func(w http.ResponseWriter, r *http.Request) {
+	return newValueHandler(func(
+		address common.Address,
+		domain string,
+		identifier string,
+		nested []any,
+	) (
+		any,
+		error,
+	) {
+		return explorer.CadenceValue(address, domain, identifier, nested)
+	})
+}
 
-			value = valueIndexableValue.GetKey(inter, interpreter.EmptyLocationRange, imported)
-			if _, ok := valueIndexableValue.(*interpreter.DictionaryValue); ok {
-				if someValue := value.(*interpreter.SomeValue); ok {
-					value = someValue.InnerValue(inter, interpreter.EmptyLocationRange)
-				}
-			}
+func newValueHandler(
+	read func(address common.Address, domain string, identifier string, nested []any) (any, error),
+) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		address, err := common.HexToAddress(r.PathValue("address"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-	}
-	return value, nil
-}
 
-func storageMapKeys(storageMap *interpreter.StorageMap, knownStorageMap KnownStorageMap) []string {
-	keys := make([]string, 0, storageMap.Count())
-	iterator := storageMap.Iterator(nil)
-	for {
-		key := iterator.NextKey()
-		if key == nil {
-			break
+		// The nested path is optional
+		var nested []any
+		err = json.NewDecoder(r.Body).Decode(&nested)
+		if err != nil && err != io.EOF {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		keys = append(
-			keys,
-			knownStorageMap.KeyAsString(key),
+		value, err := read(
+			address,
+			r.PathValue("domain"),
+			r.PathValue("identifier"),
+			nested,
 		)
-	}
-
-	sort.Strings(keys)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	return keys
+		writeJSONResponse(w, value)
+	}
 }
@@ -20,18 +20,17 @@ package main
 
 import (
 	"encoding/json"
+	"sort"
 	"strconv"
 
 	"github.com/onflow/atree"
 
-	"github.com/onflow/cadence/runtime"
-	"github.com/onflow/cadence/runtime/common"
-	"github.com/onflow/cadence/runtime/interpreter"
-	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
 )
 
 type KnownStorageMap struct {
-	Domain      string
+	Domain      common.StorageDomain
 	KeyAsString func(key atree.Value) string
 	StringAsKey func(identifier string) (interpreter.StorageMapKey, error)
 }
@@ -39,7 +38,7 @@ type KnownStorageMap struct {
 var knownStorageMaps = map[string]KnownStorageMap{}
 
 func addKnownStorageMap(storageMap KnownStorageMap) {
-	knownStorageMaps[storageMap.Domain] = storageMap
+	knownStorageMaps[storageMap.Domain.Identifier()] = storageMap
 }
 
 func StringAtreeValueAsString(key atree.Value) string {
@@ -63,44 +62,61 @@ func StringAsUint64AtreeValue(identifier string) (interpreter.StorageMapKey, err
 }
 
 func init() {
-	for _, domain := range common.AllPathDomains {
-		addKnownStorageMap(KnownStorageMap{
-			Domain:      domain.Identifier(),
-			KeyAsString: StringAtreeValueAsString,
-			StringAsKey: StringAsStringAtreeValue,
-		})
+	for _, domain := range common.AllStorageDomains {
+		switch domain {
+		case common.StorageDomainCapabilityController,
+			common.StorageDomainCapabilityControllerTag,
+			common.StorageDomainAccountCapability:
+
+			// Keyed by capability ID
+			addKnownStorageMap(KnownStorageMap{
+				Domain:      domain,
+				KeyAsString: Uint64AtreeValueAsString,
+				StringAsKey: StringAsUint64AtreeValue,
+			})
+
+		default:
+			addKnownStorageMap(KnownStorageMap{
+				Domain:      domain,
+				KeyAsString: StringAtreeValueAsString,
+				StringAsKey: StringAsStringAtreeValue,
+			})
+		}
 	}
+}
 
-	addKnownStorageMap(KnownStorageMap{
-		Domain:      stdlib.InboxStorageDomain,
-		KeyAsString: StringAtreeValueAsString,
-		StringAsKey: StringAsStringAtreeValue,
-	})
-
-	addKnownStorageMap(KnownStorageMap{
-		Domain:      runtime.StorageDomainContract,
-		KeyAsString: StringAtreeValueAsString,
-		StringAsKey: StringAsStringAtreeValue,
-	})
-
-	addKnownStorageMap(KnownStorageMap{
-		Domain:      stdlib.CapabilityControllerStorageDomain,
-		KeyAsString: Uint64AtreeValueAsString,
-		StringAsKey: StringAsUint64AtreeValue,
-	})
+func knownStorageMapDomains() []string {
+	domains := make([]string, 0, len(knownStorageMaps))
+	for _, domain := range common.AllStorageDomains {
+		domains = append(domains, domain.Identifier())
+	}
+	return domains
 }
 
 func knownStorageMapsJSON() []byte {
-	knownStorageMapDomains := make([]string, 0, len(knownStorageMaps))
-	for _, knownStorageMap := range knownStorageMaps {
-		knownStorageMapDomains = append(
-			knownStorageMapDomains,
-			knownStorageMap.Domain,
-		)
-	}
-	encoded, err := json.Marshal(knownStorageMapDomains)
+	encoded, err := json.Marshal(knownStorageMapDomains())
 	if err != nil {
 		panic(err)
 	}
 	return encoded
 }
+
+func storageMapKeys(storageMap *interpreter.DomainStorageMap, knownStorageMap KnownStorageMap) []string {
+	keys := make([]string, 0, storageMap.Count())
+	iterator := storageMap.Iterator(nil)
+	for {
+		key := iterator.NextKey()
+		if key == nil {
+			break
+		}
+
+		keys = append(
+			keys,
+			knownStorageMap.KeyAsString(key),
+		)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -1526,6 +1526,7 @@ type Composite interface {
 	isComposite()
 	getFields() []Field
 	getFieldValues() []Value
+	getAttachments() []Attachment
 
 	SearchFieldByName(fieldName string) Value
 	FieldsMappedByName() map[string]Value
@@ -1566,17 +1567,20 @@ func FieldsMappedByName(v Composite) map[string]Value {
 		return nil
 	}
 
-	fieldsMap := make(map[string]Value, len(fields))
+	attachments := v.getAttachments()
+
+	fieldsMap := make(map[string]Value, len(fields)+len(attachments))
 	for i, fieldValue := range fieldValues {
-		var fieldName string
-		if i < len(fields) {
-			fieldName = fields[i].Identifier
-		} else if attachment, ok := fieldValue.(Attachment); ok {
-			fieldName = interpreter.AttachmentMemberName(attachment.Type().ID())
-		} else {
+		if i >= len(fields) {
 			panic(errors.NewUnreachableError())
 		}
-		fieldsMap[fieldName] = fieldValue
+		fieldsMap[fields[i].Identifier] = fieldValue
+	}
+
+	// Attachments are accessible by their member name, e.g. `$A.0000000000000001.Test.A`
+	for _, attachment := range attachments {
+		fieldName := interpreter.AttachmentMemberName(attachment.Type().ID())
+		fieldsMap[fieldName] = attachment
 	}
 
 	return fieldsMap
@@ -1585,8 +1589,9 @@ func FieldsMappedByName(v Composite) map[string]Value {
 // Struct
 
 type Struct struct {
-	StructType *StructType
-	fields     []Value
+	StructType  *StructType
+	fields      []Value
+	attachments []Attachment
 }
 
 var _ Value = Struct{}
@@ -1634,6 +1639,17 @@ func (v Struct) WithType(typ *StructType) Struct {
 	return v
 }
 
+// WithAttachments returns the struct with the given attachments
+func (v Struct) WithAttachments(attachments []Attachment) Struct {
+	v.attachments = attachments
+	return v
+}
+
+// Attachments returns the attachments of the struct
+func (v Struct) Attachments() []Attachment {
+	return v.attachments
+}
+
 func (v Struct) String() string {
 	return formatComposite(
 		v.StructType.ID(),
@@ -1654,6 +1670,10 @@ func (v Struct) getFieldValues() []Value {
 	return v.fields
 }
 
+func (v Struct) getAttachments() []Attachment {
+	return v.attachments
+}
+
 // SearchFieldByName searches for the field with the given name in the struct,
 // and returns the value of the field, or nil if the field is not found.
 //
@@ -1693,6 +1713,7 @@ func formatComposite(typeID string, fields []Field, values []Value) string {
 type Resource struct {
 	ResourceType *ResourceType
 	fields       []Value
+	attachments  []Attachment
 }
 
 var _ Value = Resource{}
@@ -1739,6 +1760,17 @@ func (v Resource) WithType(typ *ResourceType) Resource {
 	return v
 }
 
+// WithAttachments returns the resource with the given attachments
+func (v Resource) WithAttachments(attachments []Attachment) Resource {
+	v.attachments = attachments
+	return v
+}
+
+// Attachments returns the attachments of the resource
+func (v Resource) Attachments() []Attachment {
+	return v.attachments
+}
+
 func (v Resource) String() string {
 	return formatComposite(
 		v.ResourceType.ID(),
@@ -1759,6 +1791,10 @@ func (v Resource) getFieldValues() []Value {
 	return v.fields
 }
 
+func (v Resource) getAttachments() []Attachment {
+	return v.attachments
+}
+
 // SearchFieldByName searches for the field with the given name in the resource,
 // and returns the value of the field, or nil if the field is not found.
 //
@@ -1843,6 +1879,10 @@ func (v Attachment) getFieldValues() []Value {
 	return v.fields
 }
 
+func (Attachment) getAttachments() []Attachment {
+	return nil
+}
+
 // SearchFieldByName searches for the field with the given name in the attachment,
 // and returns the value of the field, or nil if the field is not found.
 //
@@ -1927,6 +1967,10 @@ func (v Event) getFieldValues() []Value {
 	return v.fields
 }
 
+func (Event) getAttachments() []Attachment {
+	return nil
+}
+
 // SearchFieldByName searches for the field with the given name in the event,
 // and returns the value of the field, or nil if the field is not found.
 //
@@ -2011,6 +2055,10 @@ func (v Contract) getFieldValues() []Value {
 	return v.fields
 }
 
+func (Contract) getAttachments() []Attachment {
+	return nil
+}
+
 // SearchFieldByName searches for the field with the given name in the contract,
 // and returns the value of the field, or nil if the field is not found.
 //
@@ -2359,6 +2407,10 @@ func (v Enum) getFieldValues() []Value {
 	return v.fields
 }
 
+func (Enum) getAttachments() []Attachment {
+	return nil
+}
+
 // SearchFieldByName searches for the field with the given name in the enum,
 // and returns the value of the field, or nil if the field is not found.
 //
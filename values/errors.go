@@ -20,6 +20,18 @@ package values
 
 import "github.com/onflow/cadence/errors"
 
+// Error codes of the value errors.
+//
+// The errors are the same kinds of errors as the corresponding interpreter errors,
+// so they have the same error codes. See interpreter.ErrorCodes
+const (
+	ErrorCodeInvalidOperands errors.ErrorCode = "E3032"
+	ErrorCodeUnderflow       errors.ErrorCode = "E3009"
+	ErrorCodeOverflow        errors.ErrorCode = "E3008"
+	ErrorCodeNegativeShift   errors.ErrorCode = "E3010"
+	ErrorCodeDivisionByZero  errors.ErrorCode = "E3011"
+)
+
 // InvalidOperandsError
 
 type InvalidOperandsError struct{}
@@ -28,6 +40,10 @@ var _ errors.UserError = InvalidOperandsError{}
 
 func (InvalidOperandsError) IsUserError() {}
 
+func (InvalidOperandsError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeInvalidOperands
+}
+
 func (InvalidOperandsError) Error() string {
 	return "invalid operands"
 }
@@ -40,6 +56,10 @@ var _ errors.UserError = UnderflowError{}
 
 func (UnderflowError) IsUserError() {}
 
+func (UnderflowError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeUnderflow
+}
+
 func (UnderflowError) Error() string {
 	return "underflow"
 }
@@ -52,6 +72,10 @@ var _ errors.UserError = OverflowError{}
 
 func (OverflowError) IsUserError() {}
 
+func (OverflowError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeOverflow
+}
+
 func (OverflowError) Error() string {
 	return "overflow"
 }
@@ -64,6 +88,10 @@ var _ errors.UserError = NegativeShiftError{}
 
 func (NegativeShiftError) IsUserError() {}
 
+func (NegativeShiftError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeNegativeShift
+}
+
 func (NegativeShiftError) Error() string {
 	return "negative shift"
 }
@@ -76,6 +104,10 @@ var _ errors.UserError = DivisionByZeroError{}
 
 func (DivisionByZeroError) IsUserError() {}
 
+func (DivisionByZeroError) ErrorCode() errors.ErrorCode {
+	return ErrorCodeDivisionByZero
+}
+
 func (DivisionByZeroError) Error() string {
 	return "division by zero"
 }
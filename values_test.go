@@ -988,3 +988,55 @@ func TestEvent_GetFieldByName(t *testing.T) {
 		FieldsMappedByName(simpleEventWithType),
 	)
 }
+
+func TestStruct_Attachments(t *testing.T) {
+	t.Parallel()
+
+	attachment := NewAttachment(
+		[]Value{
+			NewInt(2),
+		},
+	).WithType(NewAttachmentType(
+		TestLocation,
+		"A",
+		nil,
+		[]Field{
+			{
+				Identifier: "b",
+				Type:       IntType,
+			},
+		},
+		nil,
+	))
+
+	structWithAttachments := NewStruct(
+		[]Value{
+			NewInt(1),
+		},
+	).
+		WithType(NewStructType(
+			TestLocation,
+			"S",
+			[]Field{
+				{
+					Identifier: "a",
+					Type:       IntType,
+				},
+			},
+			nil,
+		)).
+		WithAttachments([]Attachment{attachment})
+
+	assert.Equal(t, []Attachment{attachment}, structWithAttachments.Attachments())
+
+	// Attachments are not fields
+	assert.Nil(t, SearchFieldByName(structWithAttachments, "b"))
+
+	assert.Equal(t,
+		map[string]Value{
+			"a":         NewInt(1),
+			"$S.test.A": attachment,
+		},
+		FieldsMappedByName(structWithAttachments),
+	)
+}